@@ -4,14 +4,19 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
 	"github.com/spdx/spdx-sbom-generator/pkg/handler"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules"
 )
 
 const jsonLogFormat = "json"
@@ -41,11 +46,47 @@ func main() {
 	}
 }
 func init() {
-	rootCmd.Flags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
-	rootCmd.Flags().BoolP("include-license-text", "i", false, " Include full license text (default: false)")
-	rootCmd.Flags().StringP("schema", "s", "2.2", "<version> Target schema version (default: '2.2')")
-	rootCmd.Flags().StringP("output-dir", "o", ".", "<output> directory to Write SPDX to file (default: current directory)")
-	rootCmd.Flags().StringP("format", "f", "spdx", "output file format (default: spdx)")
+	rootCmd.PersistentFlags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
+	rootCmd.PersistentFlags().BoolP("include-license-text", "i", false, " Include full license text (default: false)")
+	rootCmd.PersistentFlags().StringP("schema", "s", "2.2", "<2.2|2.3> Target schema version (default: '2.2')")
+	rootCmd.PersistentFlags().StringP("output-dir", "o", ".", "<output> directory to Write SPDX to file (default: current directory)")
+	rootCmd.PersistentFlags().StringP("format", "f", "spdx", "output file format (default: spdx)")
+	rootCmd.PersistentFlags().Bool("include-build-tools", false, "include the build tool (maven, npm, go...) and the build plugins, e.g. maven plugins, as packages that are BUILD_TOOL_OF the project (default: false)")
+	rootCmd.PersistentFlags().Bool("include-build-tool", false, "include the build tool (maven, npm, go...) as a package (default: false)")
+	rootCmd.PersistentFlags().MarkDeprecated("include-build-tool", "use --include-build-tools, which also includes the build plugins")
+	rootCmd.PersistentFlags().Bool("warnings-as-errors", false, "exit with an error when any warning was raised during generation (default: false)")
+	rootCmd.PersistentFlags().Bool("no-relationships", false, "packages only, omit all relationships except the document DESCRIBES (default: false)")
+	rootCmd.PersistentFlags().String("document-comment", "", "<comment> free form comment added to the document, e.g. a build ID or pipeline URL")
+	rootCmd.PersistentFlags().Bool("license-policy", false, "report dependencies whose license conflicts with the project license, see --warnings-as-errors (default: false)")
+	rootCmd.PersistentFlags().Bool("allow-network", false, "allow querying remote repositories to enrich the SBOM, e.g. maven checksums, licenses, home pages and scm urls or composer and rubygems licenses and dist urls or hex licenses (default: false)")
+	rootCmd.PersistentFlags().Bool("offline", false, "never access the network: the package managers resolve from their local caches and no registry is queried, overrides --allow-network (default: false)")
+	rootCmd.PersistentFlags().Bool("json-indent", true, "indent the JSON output, --json-indent=false writes it compact (default: true)")
+	rootCmd.PersistentFlags().String("line-ending", "lf", "<lf|crlf> line ending of the output file (default: lf)")
+	rootCmd.PersistentFlags().Bool("swhid", false, "add the Software Heritage identifier of the packages downloaded from a git commit (default: false)")
+	rootCmd.PersistentFlags().Bool("redact-paths", false, "replace the local filesystem paths found in the output with a hash (default: false)")
+	rootCmd.PersistentFlags().Bool("resume", false, "keep what was resolved, e.g. maven checksums, in a cache file of the output directory so an interrupted run resumes (default: false)")
+	rootCmd.PersistentFlags().String("version-lock", "", "<path> versions.properties or gradle.lockfile whose pinned versions override the maven ones")
+	rootCmd.PersistentFlags().StringToString("packaging-extension", nil, "<packaging=extension> artifact file extension of a custom maven packaging, e.g. bundle=jar,nbm=nbm")
+	rootCmd.PersistentFlags().StringSlice("maven-profiles", nil, "<id> activate the maven profile, !id deactivates it, may be repeated (default: the activation rules of the poms)")
+	rootCmd.PersistentFlags().Bool("maven-effective-pom", false, "read the poms computed by mvn help:effective-pom instead of the pom.xml files (default: false)")
+	rootCmd.PersistentFlags().StringSlice("include-scopes", nil, "<scope> only list the maven dependencies of the scope, e.g. compile,runtime, may be repeated (default: every scope)")
+	rootCmd.PersistentFlags().StringSlice("exclude-scopes", nil, "<scope> leave out the maven dependencies of the scope, e.g. test,provided, may be repeated")
+	rootCmd.PersistentFlags().Int("maven-parallelism", 0, "<n> number of maven dependencies whose checksums and licenses are looked up at once (default: the number of CPUs)")
+	rootCmd.PersistentFlags().String("maven-settings", "", "<path> maven settings.xml of the mirrors, proxies and credentials, passed to mvn -s (default: ~/.m2/settings.xml)")
+	rootCmd.PersistentFlags().Bool("go-vendor", false, "list the go modules of vendor/modules.txt, neither the go command nor the module cache and network are needed (default: false)")
+	rootCmd.PersistentFlags().String("root", "", "<path> root filesystem the installed operating system packages are read from, e.g. a mounted image (default: the project path)")
+	rootCmd.PersistentFlags().String("image", "", "<reference|path> container image scanned instead of the project, a docker saved image, an OCI image layout or a reference docker pulls, its layers are merged and read with --lockfile-only")
+	rootCmd.PersistentFlags().Bool("lockfile-only", false, "read the manifests and lockfiles only, without running the package managers, for a best-effort SBOM where they are not installed, implies --offline (default: false)")
+	rootCmd.PersistentFlags().String("baseline", "", "<path> file listing one purl per line, only the packages not listed are emitted")
+	rootCmd.PersistentFlags().Bool("fail-on-new-package", false, "exit with an error listing the packages whose purl is not in the --baseline (default: false)")
+	rootCmd.PersistentFlags().String("components", "", "<path> JSON list of packages the package managers cannot detect (name, version, purl, license), added to the SBOM")
+	rootCmd.PersistentFlags().Bool("git-metadata", false, "record the git remote, commit and tag of the scanned directory on the root package (default: false)")
+	rootCmd.PersistentFlags().Bool("timestamp-packages", false, "stamp every package with a REVIEW annotation of the analysis time and resolution method (default: false)")
+	rootCmd.PersistentFlags().StringSlice("exclude", nil, "<package> drop the package, by name or purl, and the dependencies only it pulls in, may be repeated")
+	rootCmd.PersistentFlags().String("relationship-style", "flat", "<flat|nested> list the relationships after all the packages or in the package sections, JSON is always flat (default: flat)")
+	rootCmd.PersistentFlags().String("workspaces", "aggregate", "<aggregate|per-workspace> describe the npm, yarn and pnpm workspaces, and the modules of maven and sbt builds, in the project document or each in a document of its own (default: aggregate)")
+	rootCmd.Flags().Bool("list-supported", false, "list the supported package managers and the files they are detected by, then exit")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "<duration> abort the whole generation after this time, e.g. 5m (default: no timeout)")
 
 	//rootCmd.MarkFlagRequired("path")
 	cobra.OnInitialize(setupLogger)
@@ -62,6 +103,46 @@ func parseOutputFormat(formatOption string) models.OutputFormat {
 	}
 }
 
+func parseLineEnding(lineEndingOption string) format.LineEnding {
+	switch lineEnding := format.LineEnding(strings.ToLower(lineEndingOption)); lineEnding {
+	case format.LineEndingLF, format.LineEndingCRLF:
+		return lineEnding
+	default:
+		log.Fatalf("Unsupported line ending %q, expected lf or crlf", lineEndingOption)
+	}
+	return format.LineEndingLF
+}
+
+func parseRelationshipStyle(styleOption string) format.RelationshipStyle {
+	switch style := format.RelationshipStyle(strings.ToLower(styleOption)); style {
+	case format.RelationshipStyleFlat, format.RelationshipStyleNested:
+		return style
+	default:
+		log.Fatalf("Unsupported relationship style %q, expected flat or nested", styleOption)
+	}
+	return format.RelationshipStyleFlat
+}
+
+func parseWorkspaceMode(modeOption string) handler.WorkspaceMode {
+	switch mode := handler.WorkspaceMode(strings.ToLower(modeOption)); mode {
+	case handler.WorkspaceModeAggregate, handler.WorkspaceModePerWorkspace:
+		return mode
+	default:
+		log.Fatalf("Unsupported workspace mode %q, expected aggregate or per-workspace", modeOption)
+	}
+	return handler.WorkspaceModeAggregate
+}
+
+func parseSchema(schemaOption string) string {
+	switch schemaOption {
+	case format.SchemaVersion22, format.SchemaVersion23:
+		return schemaOption
+	default:
+		log.Fatalf("Unsupported schema version %q, expected %s or %s", schemaOption, format.SchemaVersion22, format.SchemaVersion23)
+	}
+	return format.SchemaVersion22
+}
+
 func setupLogger() {
 	log.SetFormatter(&log.TextFormatter{
 		ForceColors:   true,
@@ -85,7 +166,39 @@ func setupLogger() {
 }
 
 func generate(cmd *cobra.Command, args []string) {
+	if listSupported, _ := cmd.Flags().GetBool("list-supported"); listSupported {
+		printSupported(os.Stdout)
+		return
+	}
+
 	log.Info("Starting to generate SPDX ...")
+	handler, err := handler.NewSPDX(readSettings(cmd))
+	if err != nil {
+		log.Fatalf("Failed to initialize command: %v", err)
+	}
+
+	if err := handler.Run(); err != nil {
+		handler.Complete()
+		log.Fatalf("Failed to run command: %v", err)
+	}
+
+	if err := handler.Complete(); err != nil {
+		log.Fatalf("Failed to complete command: %v", err)
+	}
+}
+
+// printSupported writes the registered plugins and the manifest files each detects
+func printSupported(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSLUG\tFILES")
+	for _, metadata := range modules.Supported() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", metadata.Name, metadata.Slug, strings.Join(metadata.Manifest, ", "))
+	}
+	w.Flush()
+}
+
+// readSettings builds the generation settings from the command options
+func readSettings(cmd *cobra.Command) handler.SPDXSettings {
 	checkOpt := func(opt string) string {
 		cmdOpt, err := cmd.Flags().GetString(opt)
 		if err != nil {
@@ -94,30 +207,83 @@ func generate(cmd *cobra.Command, args []string) {
 
 		return cmdOpt
 	}
-	path := checkOpt("path")
-	outputDir := checkOpt("output-dir")
-	schema := checkOpt("schema")
-	format := parseOutputFormat(checkOpt("format"))
-	license, err := cmd.Flags().GetBool("include-license-text")
+	checkBoolOpt := func(opt string) bool {
+		cmdOpt, err := cmd.Flags().GetBool(opt)
+		if err != nil {
+			log.Fatalf("Failed to read command option: %v", err)
+		}
+
+		return cmdOpt
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
 	if err != nil {
 		log.Fatalf("Failed to read command option: %v", err)
 	}
-
-	handler, err := handler.NewSPDX(handler.SPDXSettings{
-		Version:   version,
-		Path:      path,
-		License:   license,
-		OutputDir: outputDir,
-		Schema:    schema,
-		Format:    format,
-	})
+	packagingExtensions, err := cmd.Flags().GetStringToString("packaging-extension")
 	if err != nil {
-		log.Fatalf("Failed to initialize command: %v", err)
+		log.Fatalf("Failed to read command option: %v", err)
 	}
-
-	if err := handler.Run(); err != nil {
-		log.Fatalf("Failed to run command: %v", err)
+	mavenProfiles, err := cmd.Flags().GetStringSlice("maven-profiles")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	includeScopes, err := cmd.Flags().GetStringSlice("include-scopes")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	excludeScopes, err := cmd.Flags().GetStringSlice("exclude-scopes")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	mavenParallelism, err := cmd.Flags().GetInt("maven-parallelism")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	exclude, err := cmd.Flags().GetStringSlice("exclude")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
 	}
 
-	handler.Complete()
+	return handler.SPDXSettings{
+		Version:             version,
+		Path:                checkOpt("path"),
+		License:             checkBoolOpt("include-license-text"),
+		OutputDir:           checkOpt("output-dir"),
+		Schema:              parseSchema(checkOpt("schema")),
+		Format:              parseOutputFormat(checkOpt("format")),
+		Timeout:             timeout,
+		IncludeBuildTool:    checkBoolOpt("include-build-tools") || checkBoolOpt("include-build-tool"),
+		IncludeBuildPlugins: checkBoolOpt("include-build-tools"),
+		WarningsAsErrors:    checkBoolOpt("warnings-as-errors"),
+		NoRelationships:     checkBoolOpt("no-relationships"),
+		DocumentComment:     checkOpt("document-comment"),
+		LicensePolicy:       checkBoolOpt("license-policy"),
+		AllowNetwork:        checkBoolOpt("allow-network"),
+		Offline:             checkBoolOpt("offline"),
+		CompactJSON:         !checkBoolOpt("json-indent"),
+		LineEnding:          parseLineEnding(checkOpt("line-ending")),
+		SWHID:               checkBoolOpt("swhid"),
+		RedactPaths:         checkBoolOpt("redact-paths"),
+		Resume:              checkBoolOpt("resume"),
+		VersionLockFile:     checkOpt("version-lock"),
+		PackagingExtensions: packagingExtensions,
+		MavenProfiles:       mavenProfiles,
+		MavenEffectivePom:   checkBoolOpt("maven-effective-pom"),
+		IncludeScopes:       includeScopes,
+		ExcludeScopes:       excludeScopes,
+		MavenSettings:       checkOpt("maven-settings"),
+		MavenParallelism:    mavenParallelism,
+		GoVendor:            checkBoolOpt("go-vendor"),
+		LockfileOnly:        checkBoolOpt("lockfile-only"),
+		RootFS:              checkOpt("root"),
+		Image:               checkOpt("image"),
+		ComponentsFile:      checkOpt("components"),
+		BaselineFile:        checkOpt("baseline"),
+		FailOnNewPackage:    checkBoolOpt("fail-on-new-package"),
+		Exclude:             exclude,
+		TimestampPackages:   checkBoolOpt("timestamp-packages"),
+		GitMetadata:         checkBoolOpt("git-metadata"),
+		RelationshipStyle:   parseRelationshipStyle(checkOpt("relationship-style")),
+		Workspaces:          parseWorkspaceMode(checkOpt("workspaces")),
+	}
 }
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/handler"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/server"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Generate the SPDX documents and serve them over HTTP",
+	Long:  "Generate the SPDX documents and serve them over HTTP until terminated, the format is negotiated with the Accept header (SPDX JSON or tag-value)",
+	Run:   serve,
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "<host:port> address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func serve(cmd *cobra.Command, args []string) {
+	addr, err := cmd.Flags().GetString("addr")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+
+	log.Info("Starting to generate SPDX ...")
+	settings := readSettings(cmd)
+	results, err := handler.Generate(settings)
+	if err != nil && len(results) == 0 {
+		log.Fatalf("Failed to run command: %v", err)
+	}
+	if err != nil {
+		log.Warnf("Generation is incomplete: %v", err)
+	}
+
+	documents := map[string]models.Document{}
+	for _, result := range results {
+		key := result.Key()
+		for _, warning := range result.Warnings {
+			log.Warnf("Plugin %s: %s", key, warning)
+		}
+		if result.Document == nil {
+			log.Infof("Plugin %s return error %v", key, result.Errors[0])
+			continue
+		}
+
+		documents[key] = *result.Document
+		log.Infof("Serving plugin %s document at http://%s/%s", key, addr, key)
+	}
+
+	srv, err := server.New(documents,
+		format.NewRenderer(models.OutputFormatJson, settings.CompactJSON, settings.LineEnding),
+		format.NewRenderer(models.OutputFormatSpdx, settings.CompactJSON, settings.LineEnding))
+	if err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+
+	log.Infof("Listening on %s", addr)
+	if err := http.ListenAndServe(addr, srv); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// flushInterval is the least time between two writes of the cache file by Put
+const flushInterval = 5 * time.Second
+
+// Entry is the information resolved for a package
+type Entry struct {
+	ChecksumAlgorithm string `json:"checksumAlgorithm,omitempty"`
+	Checksum          string `json:"checksum,omitempty"`
+	// License is the SPDX license expression of the package
+	License string `json:"license,omitempty"`
+	// HomePage is the url of the project of the package
+	HomePage string `json:"homePage,omitempty"`
+	// SourceRepository is the url of the scm repository of the package
+	SourceRepository string `json:"sourceRepository,omitempty"`
+	// DownloadLocation is the url of the archive of the package
+	DownloadLocation string `json:"downloadLocation,omitempty"`
+}
+
+// Cache keeps the information resolved for the packages, keyed by purl, in a file so an
+// interrupted generation resumes instead of resolving everything again.
+// A nil Cache is valid and caches nothing
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]Entry
+	// dirty tells the entries changed since the cache file was written at written
+	dirty   bool
+	written time.Time
+}
+
+// Open loads the cache file at path, a missing file gives an empty cache
+func Open(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: map[string]Entry{}, written: time.Now()}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Get returns the entry of the package
+func (c *Cache) Get(purl string) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[purl]
+	return entry, ok
+}
+
+// Put records the entry of the package. The cache file is written at most every flushInterval,
+// so whatever was resolved well before an interruption is kept, Flush writes the rest
+func (c *Cache) Put(purl string, entry Entry) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[purl] = entry
+	c.dirty = true
+	if time.Since(c.written) < flushInterval {
+		return nil
+	}
+	return c.flush()
+}
+
+// Flush writes the entries recorded since the last write to the cache file
+func (c *Cache) Flush() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+	return c.flush()
+}
+
+// Remove deletes the cache file, once the generation completed it is not needed anymore
+func (c *Cache) Remove() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dirty = false
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// flush writes the cache file and records when
+func (c *Cache) flush() error {
+	if err := c.write(); err != nil {
+		return err
+	}
+	c.dirty = false
+	c.written = time.Now()
+	return nil
+}
+
+// write replaces the file through a rename, an interruption never leaves it truncated
+func (c *Cache) write() error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	c, err := Open(path)
+	assert.NoError(t, err)
+	_, ok := c.Get("pkg:maven/com.acme/acme-core@1.0.0")
+	assert.False(t, ok)
+
+	entry := Entry{ChecksumAlgorithm: "SHA1", Checksum: "3a7d0e3f8b2c1a9f0e1d2c3b4a5f6e7d8c9b0a1f"}
+	assert.NoError(t, c.Put("pkg:maven/com.acme/acme-core@1.0.0", entry))
+	assert.NoFileExists(t, path)
+	assert.NoError(t, c.Flush())
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	cached, ok := reopened.Get("pkg:maven/com.acme/acme-core@1.0.0")
+	assert.True(t, ok)
+	assert.Equal(t, entry, cached)
+
+	assert.NoError(t, reopened.Remove())
+	reopened, err = Open(path)
+	assert.NoError(t, err)
+	_, ok = reopened.Get("pkg:maven/com.acme/acme-core@1.0.0")
+	assert.False(t, ok)
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	_, ok := c.Get("pkg:npm/debug@4.3.2")
+	assert.False(t, ok)
+	assert.NoError(t, c.Put("pkg:npm/debug@4.3.2", Entry{}))
+	assert.NoError(t, c.Flush())
+	assert.NoError(t, c.Remove())
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// Baseline is a set of purls already reported, e.g. by a previous SBOM
+type Baseline map[string]bool
+
+// LoadBaseline reads a file listing one purl per line, blank lines and # comments are skipped
+func LoadBaseline(path string) (Baseline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	baseline := Baseline{}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		normalized, err := purl.Normalize(line)
+		if err != nil {
+			return nil, fmt.Errorf("baseline line %d: %w", number, err)
+		}
+		baseline[normalized] = true
+	}
+
+	return baseline, scanner.Err()
+}
+
+// contains reports whether the module purl is in the baseline, a module without purl never is
+func (b Baseline) contains(module models.Module) bool {
+	if len(b) == 0 || !strings.HasPrefix(module.PackageURL, "pkg:") {
+		return false
+	}
+
+	normalized, err := purl.Normalize(module.PackageURL)
+	if err != nil {
+		return false
+	}
+	return b[normalized]
+}
+
+// newPackages returns the normalized purls of the modules which are not in the baseline, sorted.
+// The root is never new and the modules without purl are skipped, a baseline cannot list them
+func (b Baseline) newPackages(modules []models.Module) []string {
+	seen := map[string]bool{}
+	var packages []string
+	for _, module := range modules {
+		if module.Root || !strings.HasPrefix(module.PackageURL, "pkg:") {
+			continue
+		}
+
+		normalized, err := purl.Normalize(module.PackageURL)
+		if err != nil || b[normalized] || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		packages = append(packages, normalized)
+	}
+
+	sort.Strings(packages)
+	return packages
+}
+
+// filter drops the modules of the baseline and the relationships to them, the root is always kept
+func (b Baseline) filter(modules []models.Module) []models.Module {
+	if len(b) == 0 {
+		return modules
+	}
+
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		if !module.Root && b.contains(module) {
+			continue
+		}
+
+		subModules := make(map[string]*models.Module, len(module.Modules))
+		for name, subModule := range module.Modules {
+			if subModule != nil && b.contains(*subModule) {
+				continue
+			}
+			subModules[name] = subModule
+		}
+		module.Modules = subModules
+
+		filtered = append(filtered, module)
+	}
+
+	return filtered
+}
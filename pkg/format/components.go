@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+var errInvalidComponent = errors.New("invalid component")
+
+// Component is a package the package managers cannot detect, e.g. a native library or a vendored
+// binary, listed by the user. The root package depends on it
+type Component struct {
+	Name             string `json:"name"`
+	Version          string `json:"version"`
+	PackageURL       string `json:"purl"`
+	License          string `json:"license"`
+	DownloadLocation string `json:"downloadLocation"`
+}
+
+// LoadComponents reads a JSON array of components, the name and version default to the purl ones
+func LoadComponents(path string) ([]Component, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []Component
+	if err := json.Unmarshal(data, &components); err != nil {
+		return nil, err
+	}
+
+	for i := range components {
+		if err := components[i].complete(); err != nil {
+			return nil, fmt.Errorf("component %d: %w", i+1, err)
+		}
+	}
+
+	return components, nil
+}
+
+// complete validates the purl and fills the name and version from it
+func (c *Component) complete() error {
+	if c.PackageURL != "" {
+		packageURL, err := purl.Parse(c.PackageURL)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errInvalidComponent, err)
+		}
+		c.PackageURL = packageURL.String()
+		if c.Name == "" {
+			c.Name = packageURL.Name
+		}
+		if c.Version == "" {
+			c.Version = packageURL.Version
+		}
+	}
+
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("%w: a name or purl is required", errInvalidComponent)
+	}
+	return nil
+}
+
+func (c Component) module() models.Module {
+	return models.Module{
+		Name:                    c.Name,
+		Version:                 c.Version,
+		PackageURL:              c.PackageURL,
+		PackageDownloadLocation: c.DownloadLocation,
+		LicenseDeclared:         c.License,
+		LicenseConcluded:        c.License,
+	}
+}
+
+// componentModules returns the modules of the components
+func (f *Format) componentModules() []models.Module {
+	modules := make([]models.Module, 0, len(f.Config.Components))
+	for _, component := range f.Config.Components {
+		modules = append(modules, component.module())
+	}
+	return modules
+}
+
+// appendComponents adds the components to the document as dependencies of the root package, those
+// already detected or in the baseline are skipped. Their license is the one the user declared
+func (f *Format) appendComponents(modules []models.Module, document *models.Document) error {
+	if len(f.Config.Components) == 0 || len(document.Packages) == 0 {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, module := range modules {
+		seen[module.Name+"@"+module.Version] = true
+		if normalized, err := purl.Normalize(module.PackageURL); err == nil {
+			seen[normalized] = true
+		}
+	}
+
+	for _, component := range f.Config.Components {
+		module := component.module()
+		if seen[module.Name+"@"+module.Version] || seen[module.PackageURL] || f.Config.Baseline.contains(module) {
+			continue
+		}
+		seen[module.Name+"@"+module.Version] = true
+		if module.PackageURL != "" {
+			seen[module.PackageURL] = true
+		}
+
+		pkg, err := f.convertToPackage(module)
+		if err != nil {
+			return fmt.Errorf("failed to convert component %w", err)
+		}
+		pkg.PackageLicenseConcluded = buildLicense(component.License)
+		pkg.PackageLicenseDeclared = buildLicense(component.License)
+		f.stampPackage(&pkg, document.CreationInfo.Created, "user supplied component list")
+		document.Packages = append(document.Packages, pkg)
+
+		if f.Config.NoRelationships {
+			continue
+		}
+		// the root package is the first one, appending the components may move it
+		root := &document.Packages[0]
+		relationship := buildRelationship(*root, pkg, models.RelationshipDependsOn)
+		if f.Config.RelationshipStyle == RelationshipStyleNested {
+			root.Relationships = append(root.Relationships, relationship)
+			continue
+		}
+		document.Relationships = append(document.Relationships, relationship)
+	}
+
+	return nil
+}
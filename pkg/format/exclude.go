@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Exclude lists the packages pruned from the document with everything they pull in,
+// a package is matched by its name, path or package url
+type Exclude []string
+
+// matches reports whether the module is one of the excluded packages, the root never is
+func (e Exclude) matches(module models.Module) bool {
+	if module.Root {
+		return false
+	}
+
+	for _, excluded := range e {
+		if excluded != "" && (excluded == module.Name || excluded == module.Path || excluded == module.PackageURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter drops the excluded modules and their transitive dependencies, a dependency also
+// reached through a package which is not excluded is kept. Modules the root never reached are left as is
+func (e Exclude) filter(modules []models.Module) []models.Module {
+	if len(e) == 0 {
+		return modules
+	}
+
+	reachable := e.reachable(modules, false)
+	kept := e.reachable(modules, true)
+
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		key := module.Key()
+		if reachable[key] && !kept[key] {
+			continue
+		}
+
+		subModules := make(map[string]*models.Module, len(module.Modules))
+		for name, subModule := range module.Modules {
+			if subModule != nil && reachable[subModule.Key()] && !kept[subModule.Key()] {
+				continue
+			}
+			subModules[name] = subModule
+		}
+		module.Modules = subModules
+
+		filtered = append(filtered, module)
+	}
+
+	return filtered
+}
+
+// reachable returns the keys of the modules depended on from the root modules, without going
+// through the excluded ones when prune is set
+func (e Exclude) reachable(modules []models.Module, prune bool) map[string]bool {
+	index := make(map[string]models.Module, len(modules))
+	var queue []models.Module
+	for _, module := range modules {
+		index[module.Key()] = module
+		if module.Root {
+			queue = append(queue, module)
+		}
+	}
+
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		module := queue[0]
+		queue = queue[1:]
+
+		key := module.Key()
+		if visited[key] || (prune && e.matches(module)) {
+			continue
+		}
+		visited[key] = true
+
+		for _, subModule := range module.Modules {
+			if subModule == nil {
+				continue
+			}
+			// the sub module may be a bare copy, its dependencies are held by the listed module
+			if listed, ok := index[subModule.Key()]; ok {
+				queue = append(queue, listed)
+				continue
+			}
+			queue = append(queue, *subModule)
+		}
+	}
+
+	return visited
+}
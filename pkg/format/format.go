@@ -3,15 +3,19 @@
 package format
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/google/uuid"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
 )
 
 const (
@@ -19,11 +23,44 @@ const (
 	httpPrefix  = "http"
 )
 
+// LineEnding ...
+type LineEnding string
+
+const (
+	LineEndingLF   LineEnding = "lf"
+	LineEndingCRLF LineEnding = "crlf"
+)
+
+// supported SPDX specification versions, 2.2 is the default
+const (
+	SchemaVersion22 = "2.2"
+	SchemaVersion23 = "2.3"
+)
+
+// RelationshipStyle selects where the package relationships are written
+type RelationshipStyle string
+
+const (
+	// RelationshipStyleFlat lists every relationship at the document level, after the packages
+	RelationshipStyleFlat RelationshipStyle = "flat"
+	// RelationshipStyleNested lists the relationships of a package in its section, the JSON
+	// output has no package level relationships and stays flat
+	RelationshipStyleNested RelationshipStyle = "nested"
+)
+
 var replacer *strings.Replacer
 
+// supplierRegex matches a supplier with a non empty name, e.g. `Organization: ACME`
+var supplierRegex = regexp.MustCompile(`^(Person|Organization): *[^\s(]`)
+
+// gitCommitLocation matches a git download location pinned to a full commit id
+var gitCommitLocation = regexp.MustCompile(`^git\+\S+[@#]([0-9a-fA-F]{40})$`)
+
 // Format ...
 type Format struct {
-	Config Config
+	Config      Config
+	warnings    []string
+	newPackages []string
 }
 
 // Config ...
@@ -32,6 +69,36 @@ type Config struct {
 	Filename     string
 	OutputFormat models.OutputFormat
 	GetSource    func() []models.Module
+	// NoRelationships omits every relationship but the document DESCRIBES
+	NoRelationships bool
+	// DocumentComment is a free form comment stamped on the document, e.g. a build ID
+	DocumentComment string
+	// CompactJSON writes the JSON document without indentation
+	CompactJSON bool
+	// LineEnding is the newline written to the document, LF when empty
+	LineEnding LineEnding
+	// SWHID adds the Software Heritage identifier of the packages downloaded from a git commit
+	SWHID bool
+	// RedactPaths replaces the local filesystem paths found in the document with a hash
+	RedactPaths bool
+	// SchemaVersion is the SPDX specification version of the document, 2.2 when empty
+	SchemaVersion string
+	// Baseline lists the purls already reported, their packages are left out of the document
+	Baseline Baseline
+	// Components are the packages listed by the user, added as dependencies of the root package
+	Components []Component
+	// TimestampPackages stamps every package with a REVIEW annotation of the analysis time and ResolutionMethod
+	TimestampPackages bool
+	// ResolutionMethod tells how the packages were resolved, e.g. the package manager plugin
+	ResolutionMethod string
+	// Exclude lists the packages left out of the document with the dependencies only they pull in
+	Exclude Exclude
+	// RelationshipStyle is where the DEPENDS_ON and alike relationships are written, flat when empty
+	RelationshipStyle RelationshipStyle
+	// Git is the provenance of the scanned directory, the root package is downloaded from its commit
+	Git *helper.GitMetadata
+	// Creators are added to the generator in the creation info, e.g. the package manager binary
+	Creators []string
 }
 
 func init() {
@@ -53,46 +120,124 @@ type SPDXRenderer interface {
 
 // Render prepares and generates the final SPDX document in the specified format
 func (f *Format) Render() error {
-	modules := sortModules(f.Config.GetSource())
-	document, err := buildBaseDocument(f.Config.ToolVersion, modules[0])
+	document, err := f.Document()
 	if err != nil {
 		return err
 	}
 
+	return f.Write(*document)
+}
+
+// Document builds the SPDX document of the source modules without writing it
+func (f *Format) Document() (*models.Document, error) {
+	modules := f.Config.Exclude.filter(sortModules(f.Config.GetSource()))
+	f.newPackages = f.Config.Baseline.newPackages(append(append([]models.Module{}, modules...), f.componentModules()...))
+	modules = f.applyGitMetadata(f.Config.Baseline.filter(modules))
+	document, err := buildBaseDocument(f.Config.ToolVersion, f.schemaVersion(), modules[0])
+	if err != nil {
+		return nil, err
+	}
+	document.DocumentComment = f.Config.DocumentComment
+	document.CreationInfo.Creators = append(document.CreationInfo.Creators, f.Config.Creators...)
+
 	err = f.annotateDocumentWithPackages(modules, document)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if f.Config.Git != nil && len(document.Packages) > 0 && document.Packages[0].RootPackage {
+		document.Packages[0].PackageSourceInfo = f.Config.Git.SourceInfo()
+	}
+
+	if err := f.appendComponents(modules, document); err != nil {
+		return nil, err
+	}
+
+	if f.Config.RedactPaths {
+		newPathRedactor(modules).redactDocument(document)
 	}
 
+	return document, nil
+}
+
+// applyGitMetadata pins the download location of the root module to the git commit, the modules are left untouched
+func (f *Format) applyGitMetadata(modules []models.Module) []models.Module {
+	if f.Config.Git == nil || len(modules) == 0 || !modules[0].Root {
+		return modules
+	}
+
+	location := f.Config.Git.DownloadLocation()
+	if location == "" {
+		return modules
+	}
+
+	root := modules[0]
+	root.PackageDownloadLocation = location
+	return append([]models.Module{root}, modules[1:]...)
+}
+
+// Write renders the document in the output format to the configured file
+func (f *Format) Write(document models.Document) error {
 	file, err := os.Create(f.Config.Filename)
 	if err != nil {
 		return err
 	}
 
+	spdxRenderer := NewRenderer(f.Config.OutputFormat, f.Config.CompactJSON, f.Config.LineEnding)
+	outputBytes, err := spdxRenderer.RenderDocument(document)
+	if err != nil {
+		return err
+	}
+
+	// Write to file
+	file.Write(outputBytes)
+	file.Sync()
+
+	return nil
+}
+
+// NewRenderer returns the renderer of the output format, the JSON documents are written without indentation
+// when compact, and every newline is written with the line ending
+func NewRenderer(outputFormat models.OutputFormat, compactJSON bool, lineEnding LineEnding) SPDXRenderer {
 	var spdxRenderer SPDXRenderer
 
-	switch f.Config.OutputFormat {
+	switch outputFormat {
 	case models.OutputFormatSpdx:
 		spdxRenderer = TagValueSPDXRenderer{}
 	case models.OutputFormatJson:
-		spdxRenderer = JsonSPDXRenderer{}
+		spdxRenderer = JsonSPDXRenderer{Compact: compactJSON}
 	}
 
-	outputBytes, err := spdxRenderer.RenderDocument(*document)
+	return lineEndingRenderer{renderer: spdxRenderer, lineEnding: lineEnding}
+}
+
+// lineEndingRenderer rewrites the newlines of the documents its renderer renders
+type lineEndingRenderer struct {
+	renderer   SPDXRenderer
+	lineEnding LineEnding
+}
+
+// RenderDocument renders the document and applies the line ending
+func (r lineEndingRenderer) RenderDocument(document models.Document) ([]byte, error) {
+	output, err := r.renderer.RenderDocument(document)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return applyLineEnding(output, r.lineEnding), nil
+}
 
-	// Write to file
-	file.Write(outputBytes)
-	file.Sync()
+// applyLineEnding rewrites every newline of the output with the line ending
+func applyLineEnding(output []byte, lineEnding LineEnding) []byte {
+	if lineEnding != LineEndingCRLF {
+		return output
+	}
 
-	return nil
+	output = bytes.ReplaceAll(output, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(output, []byte("\n"), []byte("\r\n"))
 }
 
-func buildBaseDocument(toolVersion string, module models.Module) (*models.Document, error) {
+func buildBaseDocument(toolVersion, schemaVersion string, module models.Module) (*models.Document, error) {
 	return &models.Document{
-		SPDXVersion:       "SPDX-2.2",
+		SPDXVersion:       "SPDX-" + schemaVersion,
 		DataLicense:       "CC0-1.0",
 		SPDXID:            "SPDXRef-DOCUMENT",
 		DocumentName:      buildName(module.Name, module.Version),
@@ -110,6 +255,7 @@ func buildBaseDocument(toolVersion string, module models.Module) (*models.Docume
 // WIP
 func (f *Format) annotateDocumentWithPackages(modules []models.Module, document *models.Document) error {
 	for _, module := range modules {
+		f.checkModule(module)
 		pkg, err := f.convertToPackage(module)
 		if pkg.RootPackage {
 			document.Relationships = append(document.Relationships, models.Relationship{
@@ -121,16 +267,20 @@ func (f *Format) annotateDocumentWithPackages(modules []models.Module, document
 		if err != nil {
 			return fmt.Errorf("failed to convert module %w", err)
 		}
+		if f.Config.NoRelationships {
+			module.Modules = nil
+		}
 		for _, subMod := range module.Modules {
 			subPkg, err := f.convertToPackage(*subMod)
 			if err != nil {
 				return fmt.Errorf("failed to convert submodule %w", err)
 			}
-			document.Relationships = append(document.Relationships, models.Relationship{
-				SPDXElementID:      pkg.SPDXID,
-				RelatedSPDXElement: subPkg.SPDXID,
-				RelationshipType:   "DEPENDS_ON",
-			})
+			relationship := buildRelationship(pkg, subPkg, subMod.Relationship)
+			if f.Config.RelationshipStyle == RelationshipStyleNested {
+				pkg.Relationships = append(pkg.Relationships, relationship)
+				continue
+			}
+			document.Relationships = append(document.Relationships, relationship)
 		}
 		for licence := range module.OtherLicense {
 			document.ExtractedLicensingInfos = append(document.ExtractedLicensingInfos, models.ExtractedLicensingInfo{
@@ -140,37 +290,220 @@ func (f *Format) annotateDocumentWithPackages(modules []models.Module, document
 				LicenseComment: module.OtherLicense[licence].Comments,
 			})
 		}
+		f.stampPackage(&pkg, document.CreationInfo.Created, f.Config.ResolutionMethod)
 		document.Packages = append(document.Packages, pkg)
 	}
 	return nil
 }
 
+// Warnings returns the issues found in the modules while rendering
+func (f *Format) Warnings() []string {
+	return f.warnings
+}
+
+// NewPackages returns the purls of the packages of the last document which are not in the baseline
+func (f *Format) NewPackages() []string {
+	return f.newPackages
+}
+
+// checkModule records a warning for every incomplete or inconsistent module information
+func (f *Format) checkModule(module models.Module) {
+	if module.Version == "" && !module.Root {
+		f.warnings = append(f.warnings, fmt.Sprintf("unresolved version for package %s", module.Name))
+	}
+
+	if strings.HasPrefix(module.PackageURL, "pkg:") {
+		if _, err := purl.Parse(module.PackageURL); err != nil {
+			f.warnings = append(f.warnings, fmt.Sprintf("invalid package url %s for package %s: %v", module.PackageURL, module.Name, err))
+		}
+	}
+
+	for _, annotation := range module.Unresolved {
+		f.warnings = append(f.warnings, fmt.Sprintf("package %s: %s", module.Name, annotation))
+	}
+
+	if module.CheckSum == nil {
+		f.warnings = append(f.warnings, fmt.Sprintf("missing checksum for package %s", module.Name))
+	}
+
+	if module.LicenseDeclared != "" && module.LicenseConcluded != "" && module.LicenseDeclared != module.LicenseConcluded {
+		f.warnings = append(f.warnings, fmt.Sprintf("license mismatch for package %s: declared %s, concluded %s",
+			module.Name, module.LicenseDeclared, module.LicenseConcluded))
+	}
+}
+
+func buildChecksums(module models.Module) []models.PackageChecksum {
+	if module.CheckSum == nil {
+		return []models.PackageChecksum{}
+	}
+
+	return []models.PackageChecksum{{
+		Algorithm: module.CheckSum.Algorithm,
+		Value:     module.CheckSum.String(),
+	}}
+}
+
+// buildRelationship links a package to one of its sub packages, reversed relationship types
+// such as BUILD_TOOL_OF are expressed from the sub package to its parent
+func buildRelationship(pkg, subPkg models.Package, relationship models.RelationshipType) models.Relationship {
+	if relationship == "" {
+		relationship = models.RelationshipDependsOn
+	}
+
+	if relationship.IsReversed() {
+		return models.Relationship{
+			SPDXElementID:      subPkg.SPDXID,
+			RelatedSPDXElement: pkg.SPDXID,
+			RelationshipType:   string(relationship),
+		}
+	}
+
+	return models.Relationship{
+		SPDXElementID:      pkg.SPDXID,
+		RelatedSPDXElement: subPkg.SPDXID,
+		RelationshipType:   string(relationship),
+	}
+}
+
 // WIP
 func (f *Format) convertToPackage(module models.Module) (models.Package, error) {
 	return models.Package{
 		PackageName:             module.Name,
 		SPDXID:                  setPkgSPDXID(module.Name, module.Version, module.Root),
 		PackageVersion:          buildVersion(module),
-		PackageSupplier:         setPkgValue(module.Supplier.Get()),
+		PackageSupplier:         buildSupplier(module.Supplier.Get()),
 		PackageDownloadLocation: setPkgValue(module.PackageDownloadLocation),
 		FilesAnalyzed:           false,
-		PackageChecksums: []models.PackageChecksum{{
-			Algorithm: module.CheckSum.Algorithm,
-			Value:     module.CheckSum.String(),
-		}},
-		PackageHomePage:         buildHomepageURL(module.PackageURL),
-		PackageLicenseConcluded: noAssertion, // setPkgValue(module.LicenseConcluded),
-		PackageLicenseDeclared:  noAssertion, // setPkgValue(module.LicenseDeclared),
+		PackageChecksums:        buildChecksums(module),
+		PackageHomePage:         buildHomepageURL(module),
+		PackageLicenseConcluded: buildLicense(module.LicenseConcluded),
+		PackageLicenseDeclared:  buildLicense(module.LicenseDeclared),
 		PackageCopyrightText:    noAssertion, // setPkgValue(module.Copyright),
 		PackageLicenseComments:  setPkgValue(""),
 		PackageComment:          setPkgValue(""),
+		PackageSourceInfo:       module.SourceInfo,
+		PackageAttributionTexts: module.AttributionTexts,
+		PackageExternalRefs:     f.buildExternalRefs(module),
+		PrimaryPackagePurpose:   f.buildPrimaryPackagePurpose(module),
+		Annotations:             f.buildAnnotations(module),
 		RootPackage:             module.Root,
 	}, nil
 }
 
-// todo: complete build package homepage rules
-func buildHomepageURL(url string) string {
+// buildAnnotations returns the notes on the module as annotations of the tool
+func (f *Format) buildAnnotations(module models.Module) []models.Annotation {
+	var annotations []models.Annotation
+	for _, comment := range append(append([]string{}, module.Unresolved...), module.Annotations...) {
+		annotations = append(annotations, models.Annotation{
+			Annotator:      fmt.Sprintf("Tool: spdx-sbom-generator-%s", f.Config.ToolVersion),
+			AnnotationDate: time.Now().UTC().Format(time.RFC3339),
+			AnnotationType: "OTHER",
+			Comment:        comment,
+		})
+	}
+	return annotations
+}
+
+// stampPackage adds the REVIEW annotation of the analysis time and resolution method to the package
+// with Config.TimestampPackages
+func (f *Format) stampPackage(pkg *models.Package, analyzed, method string) {
+	if !f.Config.TimestampPackages {
+		return
+	}
+	if method == "" {
+		method = noAssertion
+	}
+
+	pkg.Annotations = append(pkg.Annotations, models.Annotation{
+		Annotator:      fmt.Sprintf("Tool: spdx-sbom-generator-%s", f.Config.ToolVersion),
+		AnnotationDate: analyzed,
+		AnnotationType: "REVIEW",
+		Comment:        fmt.Sprintf("resolution method: %s", method),
+	})
+}
+
+func (f *Format) schemaVersion() string {
+	if f.Config.SchemaVersion == SchemaVersion23 {
+		return SchemaVersion23
+	}
+	return SchemaVersion22
+}
+
+// buildPrimaryPackagePurpose returns the purpose of the module, the field only exists from SPDX 2.3
+func (f *Format) buildPrimaryPackagePurpose(module models.Module) string {
+	if f.schemaVersion() != SchemaVersion23 {
+		return ""
+	}
+	return string(module.PrimaryPackagePurpose)
+}
+
+// buildExternalRefs returns the purl reference of the module, PackageURL holding a purl.
+// The purl is validated and re-encoded, an invalid one is not emitted. With Config.SWHID,
+// the Software Heritage identifier is added when known
+func (f *Format) buildExternalRefs(module models.Module) []models.ExternalRef {
+	var refs []models.ExternalRef
+	if strings.HasPrefix(module.PackageURL, "pkg:") {
+		if locator, err := purl.Normalize(module.PackageURL); err == nil {
+			refs = append(refs, models.ExternalRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  locator,
+			})
+		}
+	}
+
+	if swhid, ok := buildSWHID(module.PackageDownloadLocation); ok && f.Config.SWHID {
+		refs = append(refs, models.ExternalRef{
+			ReferenceCategory: "PERSISTENT-ID",
+			ReferenceType:     "swh",
+			ReferenceLocator:  swhid,
+		})
+	}
+
+	return refs
+}
+
+// buildSWHID returns the Software Heritage revision identifier of a package downloaded from git at a
+// known commit, e.g. git+https://github.com/org/repo.git@<sha1> or git+ssh://...#<sha1>.
+// A git commit id is the id of the archived revision
+func buildSWHID(downloadLocation string) (string, bool) {
+	match := gitCommitLocation.FindStringSubmatch(downloadLocation)
+	if match == nil {
+		return "", false
+	}
+
+	return "swh:1:rev:" + strings.ToLower(match[1]), true
+}
+
+// buildLicense only reports a license made of known SPDX identifiers so no LicenseRef
+// is left undefined in the document
+func buildLicense(license string) string {
+	if license == "" {
+		return noAssertion
+	}
+
+	for _, token := range strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(license)) {
+		switch token {
+		case "AND", "OR", "WITH":
+			continue
+		}
+		if !helper.LicenseSPDXExists(token) {
+			return noAssertion
+		}
+	}
+
+	return license
+}
+
+// buildHomepageURL returns the homepage of the module, falling back to a PackageURL that is not a purl
+func buildHomepageURL(module models.Module) string {
+	url := module.PackageHomePage
 	if url == "" {
+		url = module.PackageURL
+	}
+
+	// a package url identifies the package, it is not a homepage
+	if url == "" || url == noAssertion || strings.HasPrefix(url, "pkg:") {
 		return noAssertion
 	}
 
@@ -203,6 +536,14 @@ func buildVersion(module models.Module) string {
 	return head.Hash().String()[0:7]
 }
 
+// buildSupplier returns the supplier when made of a known type and a name, NOASSERTION otherwise
+func buildSupplier(supplier string) string {
+	if !supplierRegex.MatchString(supplier) {
+		return noAssertion
+	}
+	return supplier
+}
+
 func setPkgValue(s string) string {
 	if s == "" {
 		return noAssertion
@@ -220,11 +561,14 @@ func setPkgSPDXID(s, v string, root bool) string {
 }
 
 // todo: improve this logic
+// sortModules moves the root module first, the source slice is left untouched
 func sortModules(modules []models.Module) []models.Module {
 	for i, m := range modules {
 		if m.Root {
-			modules = append(modules[:i], modules[i+1:]...)
-			return append([]models.Module{m}, modules...)
+			sorted := make([]models.Module, 0, len(modules))
+			sorted = append(sorted, m)
+			sorted = append(sorted, modules[:i]...)
+			return append(sorted, modules[i+1:]...)
 		}
 	}
 
@@ -0,0 +1,575 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// testModules returns a root module depending on a single dependency
+func testModules() []models.Module {
+	dependency := models.Module{
+		Name:     "dependency",
+		Version:  "2.0.0",
+		CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+	}
+
+	return []models.Module{
+		{
+			Name:     "root",
+			Version:  "1.0.0",
+			Root:     true,
+			CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+			Modules:  map[string]*models.Module{"dependency": &dependency},
+		},
+		dependency,
+	}
+}
+
+// render renders modules with cfg and returns the output file content
+func render(t *testing.T, cfg Config, modules []models.Module) []byte {
+	cfg.Filename = filepath.Join(t.TempDir(), "bom")
+	cfg.GetSource = func() []models.Module {
+		return modules
+	}
+
+	f, err := New(cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	output, err := ioutil.ReadFile(cfg.Filename)
+	assert.NoError(t, err)
+
+	return output
+}
+
+// renderDocument renders modules as JSON and decodes the document
+func renderDocument(t *testing.T, cfg Config, modules []models.Module) models.Document {
+	cfg.OutputFormat = models.OutputFormatJson
+
+	var document models.Document
+	assert.NoError(t, json.Unmarshal(render(t, cfg, modules), &document))
+
+	return document
+}
+
+func TestRenderRelationships(t *testing.T) {
+	document := renderDocument(t, Config{}, testModules())
+
+	assert.Len(t, document.Packages, 2)
+	assert.Equal(t, []models.Relationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-Package-root", RelationshipType: "DESCRIBES"},
+		{SPDXElementID: "SPDXRef-Package-root", RelatedSPDXElement: "SPDXRef-Package-dependency-2.0.0", RelationshipType: "DEPENDS_ON"},
+	}, document.Relationships)
+}
+
+func TestRenderNoRelationships(t *testing.T) {
+	document := renderDocument(t, Config{NoRelationships: true}, testModules())
+
+	assert.Len(t, document.Packages, 2)
+	assert.Equal(t, "dependency", document.Packages[1].PackageName)
+	assert.Equal(t, []models.Relationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-Package-root", RelationshipType: "DESCRIBES"},
+	}, document.Relationships)
+
+	output := string(render(t, Config{NoRelationships: true}, testModules()))
+	assert.Contains(t, output, "Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-root")
+	assert.NotContains(t, output, "DEPENDS_ON")
+	assert.Contains(t, output, "PackageName: dependency")
+}
+
+func TestRenderRelationshipStyle(t *testing.T) {
+	flat, err := (&Format{Config: Config{GetSource: testModules}}).Document()
+	assert.NoError(t, err)
+	nested, err := (&Format{Config: Config{GetSource: testModules, RelationshipStyle: RelationshipStyleNested}}).Document()
+	assert.NoError(t, err)
+
+	assert.Len(t, nested.Relationships, 1)
+	assert.Equal(t, []models.Relationship{
+		{SPDXElementID: "SPDXRef-Package-root", RelatedSPDXElement: "SPDXRef-Package-dependency-2.0.0", RelationshipType: "DEPENDS_ON"},
+	}, nested.Packages[0].Relationships)
+	assert.Equal(t, flat.AllRelationships(), nested.AllRelationships())
+
+	flatOutput := string(render(t, Config{}, testModules()))
+	nestedOutput := string(render(t, Config{RelationshipStyle: RelationshipStyleNested}, testModules()))
+	for _, output := range []string{flatOutput, nestedOutput} {
+		assert.Equal(t, 1, strings.Count(output, "Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-root"))
+		assert.Equal(t, 1, strings.Count(output, "Relationship: SPDXRef-Package-root DEPENDS_ON SPDXRef-Package-dependency-2.0.0"))
+	}
+	dependsOn := strings.Index(nestedOutput, "DEPENDS_ON")
+	assert.True(t, dependsOn < strings.Index(nestedOutput, "##### Package representing the dependency"))
+	assert.True(t, strings.Index(flatOutput, "DEPENDS_ON") > strings.Index(flatOutput, "##### Package representing the dependency"))
+
+	assert.Equal(t, renderDocument(t, Config{}, testModules()).Relationships,
+		renderDocument(t, Config{RelationshipStyle: RelationshipStyleNested}, testModules()).Relationships)
+}
+
+func TestRenderCreatorTool(t *testing.T) {
+	document := renderDocument(t, Config{ToolVersion: "v0.0.15"}, testModules())
+	assert.Equal(t, []string{"Tool: spdx-sbom-generator-v0.0.15"}, document.CreationInfo.Creators)
+
+	output := string(render(t, Config{ToolVersion: "v0.0.15"}, testModules()))
+	assert.Contains(t, output, "\nCreator: Tool: spdx-sbom-generator-v0.0.15\nCreated: ")
+
+	document = renderDocument(t, Config{ToolVersion: "v0.0.15", Creators: []string{"Tool: mvnw-3.8.6"}}, testModules())
+	assert.Equal(t, []string{"Tool: spdx-sbom-generator-v0.0.15", "Tool: mvnw-3.8.6"}, document.CreationInfo.Creators)
+}
+
+func TestRenderDocumentComment(t *testing.T) {
+	cfg := Config{DocumentComment: "build 42 https://ci.example.com/42"}
+
+	document := renderDocument(t, cfg, testModules())
+	assert.Equal(t, "build 42 https://ci.example.com/42", document.DocumentComment)
+
+	output := string(render(t, cfg, testModules()))
+	assert.Contains(t, output, "\nDocumentComment: build 42 https://ci.example.com/42\n")
+
+	output = string(render(t, Config{DocumentComment: "line 1\nline 2"}, testModules()))
+	assert.Contains(t, output, "\nDocumentComment: <text>line 1\nline 2</text>\n")
+
+	output = string(render(t, Config{}, testModules()))
+	assert.NotContains(t, output, "DocumentComment")
+}
+
+func TestRenderLineEnding(t *testing.T) {
+	for _, outputFormat := range []models.OutputFormat{models.OutputFormatSpdx, models.OutputFormatJson} {
+		output := string(render(t, Config{OutputFormat: outputFormat, LineEnding: LineEndingCRLF}, testModules()))
+		lines := strings.Count(output, "\n")
+		assert.Greater(t, lines, 10)
+		assert.Equal(t, lines, strings.Count(output, "\r\n"))
+
+		output = string(render(t, Config{OutputFormat: outputFormat, LineEnding: LineEndingLF}, testModules()))
+		assert.NotContains(t, output, "\r")
+	}
+}
+
+func TestRenderCompactJSON(t *testing.T) {
+	pretty := render(t, Config{OutputFormat: models.OutputFormatJson}, testModules())
+	assert.True(t, strings.HasPrefix(string(pretty), "{\n\t\"spdxVersion\": \"SPDX-2.2\","))
+
+	compact := render(t, Config{OutputFormat: models.OutputFormatJson, CompactJSON: true}, testModules())
+	assert.True(t, strings.HasPrefix(string(compact), `{"spdxVersion":"SPDX-2.2","dataLicense":"CC0-1.0",`))
+	assert.NotContains(t, string(compact), "\n")
+	assert.NotContains(t, string(compact), "\t")
+	assert.NotContains(t, string(compact), "\": ")
+
+	var prettyDocument, compactDocument models.Document
+	assert.NoError(t, json.Unmarshal(pretty, &prettyDocument))
+	assert.NoError(t, json.Unmarshal(compact, &compactDocument))
+	assert.Equal(t, prettyDocument.Packages, compactDocument.Packages)
+	assert.Equal(t, prettyDocument.Relationships, compactDocument.Relationships)
+}
+
+func TestRenderExternalRefs(t *testing.T) {
+	modules := testModules()
+	modules[1].PackageURL = "pkg:maven/org.example/my lib@2.0.0"
+
+	document := renderDocument(t, Config{}, modules)
+	assert.Equal(t, []models.ExternalRef{{
+		ReferenceCategory: "PACKAGE-MANAGER",
+		ReferenceType:     "purl",
+		ReferenceLocator:  "pkg:maven/org.example/my%20lib@2.0.0",
+	}}, document.Packages[1].PackageExternalRefs)
+	assert.Equal(t, "NOASSERTION", document.Packages[1].PackageHomePage)
+	assert.Empty(t, document.Packages[0].PackageExternalRefs)
+
+	output := string(render(t, Config{}, modules))
+	assert.Contains(t, output, "\nExternalRef: PACKAGE-MANAGER purl pkg:maven/org.example/my%20lib@2.0.0\n")
+}
+
+func TestRenderHomePage(t *testing.T) {
+	homePageModules := func(homePage string) []models.Module {
+		modules := testModules()
+		modules[0].PackageURL = "github.com/example/root"
+		modules[1].PackageHomePage = homePage
+		modules[1].PackageURL = "pkg:npm/dependency@2.0.0"
+		return modules
+	}
+
+	document := renderDocument(t, Config{}, homePageModules("http://dependency.example.com"))
+	assert.Equal(t, "https://github.com/example/root", document.Packages[0].PackageHomePage)
+	assert.Equal(t, "http://dependency.example.com", document.Packages[1].PackageHomePage)
+
+	document = renderDocument(t, Config{}, homePageModules("NOASSERTION"))
+	assert.Equal(t, "NOASSERTION", document.Packages[1].PackageHomePage)
+
+	output := string(render(t, Config{}, homePageModules("")))
+	assert.Contains(t, output, "\nPackageHomePage: https://github.com/example/root\n")
+	assert.Contains(t, output, "\nPackageHomePage: NOASSERTION\n")
+}
+
+func TestRenderSWHID(t *testing.T) {
+	commit := "b7f31d0d6c1fb39de7b0d7e5bb64bd1c2e3a0a3f"
+	swhidModules := func() []models.Module {
+		modules := testModules()
+		modules[1].PackageDownloadLocation = "git+ssh://git@github.com/example/dependency.git#" + commit
+		return modules
+	}
+
+	document := renderDocument(t, Config{SWHID: true}, swhidModules())
+	assert.Equal(t, []models.ExternalRef{{
+		ReferenceCategory: "PERSISTENT-ID",
+		ReferenceType:     "swh",
+		ReferenceLocator:  "swh:1:rev:" + commit,
+	}}, document.Packages[1].PackageExternalRefs)
+	assert.Empty(t, document.Packages[0].PackageExternalRefs)
+
+	output := string(render(t, Config{SWHID: true}, swhidModules()))
+	assert.Contains(t, output, "\nExternalRef: PERSISTENT-ID swh swh:1:rev:"+commit+"\n")
+
+	document = renderDocument(t, Config{}, swhidModules())
+	assert.Empty(t, document.Packages[1].PackageExternalRefs)
+}
+
+func TestBuildSWHID(t *testing.T) {
+	commit := "b7f31d0d6c1fb39de7b0d7e5bb64bd1c2e3a0a3f"
+
+	swhid, ok := buildSWHID("git+https://github.com/example/dependency.git@" + commit)
+	assert.True(t, ok)
+	assert.Equal(t, "swh:1:rev:"+commit, swhid)
+
+	_, ok = buildSWHID("git+https://github.com/example/dependency.git@v1.0.0")
+	assert.False(t, ok)
+	_, ok = buildSWHID("https://registry.npmjs.org/debug/-/debug-4.3.2.tgz")
+	assert.False(t, ok)
+}
+
+func TestRenderRedactPaths(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "home", "alice", "project")
+	redactModules := func() []models.Module {
+		modules := testModules()
+		modules[0].LocalPath = projectPath
+		modules[0].PackageDownloadLocation = projectPath
+		modules[1].LocalPath = filepath.Join(projectPath, "vendor", "dependency")
+		modules[1].PackageDownloadLocation = "file://" + modules[1].LocalPath
+		modules[1].OtherLicense = []*models.License{{
+			ID:            "LicenseRef-dependency",
+			ExtractedText: "see " + filepath.Join(modules[1].LocalPath, "LICENSE"),
+			Comments:      "found in " + modules[1].LocalPath,
+		}}
+		return modules
+	}
+
+	for _, outputFormat := range []models.OutputFormat{models.OutputFormatSpdx, models.OutputFormatJson} {
+		output := string(render(t, Config{OutputFormat: outputFormat, RedactPaths: true}, redactModules()))
+		assert.NotContains(t, output, projectPath)
+		assert.NotContains(t, output, "alice")
+		assert.Contains(t, output, "[redacted-")
+
+		output = string(render(t, Config{OutputFormat: outputFormat}, redactModules()))
+		assert.Contains(t, output, projectPath)
+	}
+
+	document := renderDocument(t, Config{RedactPaths: true}, redactModules())
+	assert.Equal(t, "NOASSERTION", document.Packages[0].PackageDownloadLocation)
+	assert.Equal(t, "NOASSERTION", document.Packages[1].PackageDownloadLocation)
+}
+
+func TestRenderPrimaryPackagePurpose(t *testing.T) {
+	purposeModules := func() []models.Module {
+		modules := testModules()
+		modules[0].PrimaryPackagePurpose = models.PurposeApplication
+		return modules
+	}
+
+	document := renderDocument(t, Config{SchemaVersion: SchemaVersion23}, purposeModules())
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Equal(t, "APPLICATION", document.Packages[0].PrimaryPackagePurpose)
+	assert.Empty(t, document.Packages[1].PrimaryPackagePurpose)
+
+	output := string(render(t, Config{SchemaVersion: SchemaVersion23}, purposeModules()))
+	assert.Contains(t, output, "\nPrimaryPackagePurpose: APPLICATION\n")
+
+	document = renderDocument(t, Config{}, purposeModules())
+	assert.Equal(t, "SPDX-2.2", document.SPDXVersion)
+	assert.Empty(t, document.Packages[0].PrimaryPackagePurpose)
+	assert.NotContains(t, string(render(t, Config{}, purposeModules())), "PrimaryPackagePurpose")
+}
+
+func TestRenderBaseline(t *testing.T) {
+	baseline, err := LoadBaseline(filepath.Join("testdata", "baseline.txt"))
+	assert.NoError(t, err)
+	assert.Len(t, baseline, 2)
+
+	debug := models.Module{Name: "debug", Version: "4.3.2", PackageURL: "pkg:npm/debug@4.3.2"}
+	types := models.Module{Name: "@types/node", Version: "16.11.7", PackageURL: "pkg:npm/@types/node@16.11.7"}
+	express := models.Module{Name: "express", Version: "4.17.1", PackageURL: "pkg:npm/express@4.17.1"}
+	modules := []models.Module{
+		{
+			Name:       "root",
+			Version:    "1.0.0",
+			Root:       true,
+			PackageURL: "pkg:npm/root@1.0.0",
+			Modules:    map[string]*models.Module{"debug": &debug, "@types/node": &types, "express": &express},
+		},
+		debug,
+		types,
+		express,
+	}
+
+	document := renderDocument(t, Config{Baseline: baseline}, modules)
+
+	var names []string
+	for _, pkg := range document.Packages {
+		names = append(names, pkg.PackageName)
+	}
+	assert.Equal(t, []string{"root", "express"}, names)
+	assert.Equal(t, []models.Relationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-Package-root", RelationshipType: "DESCRIBES"},
+		{SPDXElementID: "SPDXRef-Package-root", RelatedSPDXElement: "SPDXRef-Package-express-4.17.1", RelationshipType: "DEPENDS_ON"},
+	}, document.Relationships)
+
+	// the source modules are left untouched
+	assert.Len(t, modules[0].Modules, 3)
+}
+
+func TestNewPackages(t *testing.T) {
+	baseline, err := LoadBaseline(filepath.Join("testdata", "baseline.txt"))
+	assert.NoError(t, err)
+
+	modules := testModules()
+	modules[0].PackageURL = "pkg:npm/root@1.0.0"
+	modules[1].PackageURL = "pkg:npm/debug@4.3.2"
+	components := []Component{{Name: "zlib", Version: "1.2.11", PackageURL: "pkg:generic/zlib@1.2.11"}, {Name: "unlisted"}}
+
+	f, err := New(Config{Baseline: baseline, Components: components, GetSource: func() []models.Module { return modules }})
+	assert.NoError(t, err)
+	_, err = f.Document()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pkg:generic/zlib@1.2.11"}, f.NewPackages())
+
+	modules[1].PackageURL = "pkg:npm/dependency@2.0.0"
+	_, err = f.Document()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pkg:generic/zlib@1.2.11", "pkg:npm/dependency@2.0.0"}, f.NewPackages())
+}
+
+func TestRenderExclude(t *testing.T) {
+	c := models.Module{Name: "c", Version: "3.0.0"}
+	b := models.Module{Name: "b", Version: "2.0.0", Modules: map[string]*models.Module{"c": &c}}
+	a := models.Module{Name: "a", Version: "1.0.0", Modules: map[string]*models.Module{"b": &b}}
+	d := models.Module{Name: "d", Version: "4.0.0"}
+	modules := []models.Module{
+		{Name: "root", Version: "1.0.0", Root: true, Modules: map[string]*models.Module{"a": &a, "d": &d}},
+		a,
+		b,
+		c,
+		d,
+	}
+
+	document := renderDocument(t, Config{Exclude: Exclude{"a"}}, modules)
+
+	var names []string
+	for _, pkg := range document.Packages {
+		names = append(names, pkg.PackageName)
+	}
+	assert.Equal(t, []string{"root", "d"}, names)
+	assert.Equal(t, []models.Relationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-Package-root", RelationshipType: "DESCRIBES"},
+		{SPDXElementID: "SPDXRef-Package-root", RelatedSPDXElement: "SPDXRef-Package-d-4.0.0", RelationshipType: "DEPENDS_ON"},
+	}, document.Relationships)
+
+	// c is kept when d depends on it as well
+	d.Modules = map[string]*models.Module{"c": &c}
+	modules[4] = d
+	document = renderDocument(t, Config{Exclude: Exclude{"a"}}, modules)
+
+	names = nil
+	for _, pkg := range document.Packages {
+		names = append(names, pkg.PackageName)
+	}
+	assert.Equal(t, []string{"root", "c", "d"}, names)
+}
+
+func TestRenderComponents(t *testing.T) {
+	components, err := LoadComponents(filepath.Join("testdata", "components.json"))
+	assert.NoError(t, err)
+	assert.Len(t, components, 3)
+
+	document := renderDocument(t, Config{Components: components}, testModules())
+
+	assert.Len(t, document.Packages, 4)
+	zlib := document.Packages[2]
+	assert.Equal(t, "zlib", zlib.PackageName)
+	assert.Equal(t, "1.2.11", zlib.PackageVersion)
+	assert.Equal(t, "Zlib", zlib.PackageLicenseDeclared)
+	assert.Equal(t, "Zlib", zlib.PackageLicenseConcluded)
+	assert.Equal(t, "https://zlib.net/fossils/zlib-1.2.11.tar.gz", zlib.PackageDownloadLocation)
+	assert.Equal(t, []models.ExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:generic/zlib@1.2.11"},
+	}, zlib.PackageExternalRefs)
+
+	native := document.Packages[3]
+	assert.Equal(t, "acme-native", native.PackageName)
+	assert.Equal(t, "3.1.0", native.PackageVersion)
+	// no LicenseRef is emitted without its extracted text
+	assert.Equal(t, "NOASSERTION", native.PackageLicenseDeclared)
+
+	assert.Contains(t, document.Relationships, models.Relationship{
+		SPDXElementID: "SPDXRef-Package-root", RelatedSPDXElement: "SPDXRef-Package-zlib-1.2.11", RelationshipType: "DEPENDS_ON",
+	})
+	assert.Contains(t, document.Relationships, models.Relationship{
+		SPDXElementID: "SPDXRef-Package-root", RelatedSPDXElement: "SPDXRef-Package-acme-native-3.1.0", RelationshipType: "DEPENDS_ON",
+	})
+	// the detected dependency is not duplicated
+	assert.Len(t, document.Relationships, 4)
+}
+
+func TestLoadComponentsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "components.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`[{"license": "MIT"}]`), 0644))
+
+	_, err := LoadComponents(path)
+	assert.True(t, errors.Is(err, errInvalidComponent))
+}
+
+func TestRenderSupplier(t *testing.T) {
+	tests := []struct {
+		name     string
+		supplier models.SupplierContact
+		expected string
+	}{
+		{"person only", models.SupplierContact{Type: models.Person, Name: "Jane Doe", Email: "jane@example.com"}, "Person: Jane Doe (jane@example.com)"},
+		{"organization only", models.SupplierContact{Type: models.Organization, Name: "ACME"}, "Organization: ACME"},
+		{"name without type", models.SupplierContact{Name: "ACME"}, "Organization: ACME"},
+		{"empty", models.SupplierContact{}, "NOASSERTION"},
+		{"blank name", models.SupplierContact{Type: models.Organization, Name: "  "}, "NOASSERTION"},
+		{"malformed plugin supplier", models.SupplierContact{FuncGetSupplier: func() string { return ": ACME" }}, "NOASSERTION"},
+		{"type without name", models.SupplierContact{FuncGetSupplier: func() string { return "Organization: " }}, "NOASSERTION"},
+	}
+
+	for _, test := range tests {
+		modules := testModules()
+		modules[0].Supplier = test.supplier
+
+		document := renderDocument(t, Config{}, modules)
+		assert.Equal(t, test.expected, document.Packages[0].PackageSupplier, test.name)
+
+		output := string(render(t, Config{}, modules))
+		assert.Contains(t, output, "\nPackageSupplier: "+test.expected+"\n", test.name)
+	}
+}
+
+func TestRenderAnnotations(t *testing.T) {
+	modules := testModules()
+	modules[0].Unresolved = []string{"transitive dependencies were not resolved"}
+	modules[0].Annotations = []string{"the package is built from the directory ."}
+
+	cfg := Config{ToolVersion: "v0.0.15", GetSource: func() []models.Module { return modules }}
+	f, err := New(cfg)
+	assert.NoError(t, err)
+	document, err := f.Document()
+	assert.NoError(t, err)
+	assert.Contains(t, f.Warnings(), "package root: transitive dependencies were not resolved")
+	assert.NotContains(t, f.Warnings(), "package root: the package is built from the directory .")
+
+	annotations := document.Packages[0].Annotations
+	assert.Len(t, annotations, 2)
+	assert.Equal(t, "Tool: spdx-sbom-generator-v0.0.15", annotations[0].Annotator)
+	assert.Equal(t, "OTHER", annotations[0].AnnotationType)
+	assert.Equal(t, "transitive dependencies were not resolved", annotations[0].Comment)
+	assert.Equal(t, "the package is built from the directory .", annotations[1].Comment)
+	assert.Empty(t, document.Packages[1].Annotations)
+
+	output := string(render(t, cfg, modules))
+	assert.Contains(t, output, "AnnotationType: OTHER\nSPDXREF: SPDXRef-Package-root\nAnnotationComment: <text>transitive dependencies were not resolved</text>\n")
+
+	decoded := renderDocument(t, cfg, modules)
+	assert.Equal(t, "transitive dependencies were not resolved", decoded.Packages[0].Annotations[0].Comment)
+}
+
+func TestRenderTimestampPackages(t *testing.T) {
+	components := []Component{{Name: "zlib", Version: "1.2.11"}}
+	cfg := Config{TimestampPackages: true, ResolutionMethod: "Java Maven package manager", Components: components}
+	document := renderDocument(t, cfg, testModules())
+
+	assert.Len(t, document.Packages, 3)
+	for _, pkg := range document.Packages {
+		assert.Len(t, pkg.Annotations, 1, pkg.PackageName)
+		annotation := pkg.Annotations[0]
+		assert.Equal(t, "REVIEW", annotation.AnnotationType)
+		assert.Equal(t, document.CreationInfo.Created, annotation.AnnotationDate)
+		_, err := time.Parse(time.RFC3339, annotation.AnnotationDate)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "resolution method: Java Maven package manager", document.Packages[1].Annotations[0].Comment)
+	assert.Equal(t, "resolution method: user supplied component list", document.Packages[2].Annotations[0].Comment)
+
+	document = renderDocument(t, Config{}, testModules())
+	for _, pkg := range document.Packages {
+		assert.Empty(t, pkg.Annotations)
+	}
+}
+
+func TestRenderAttributionTexts(t *testing.T) {
+	modules := testModules()
+	modules[1].AttributionTexts = []string{"Dependency\nCopyright 2021 The Example Foundation"}
+
+	output := string(render(t, Config{}, modules))
+	assert.Contains(t, output, "PackageAttributionText: <text>Dependency\nCopyright 2021 The Example Foundation</text>\n")
+	assert.Equal(t, 1, strings.Count(output, "PackageAttributionText:"))
+
+	document := renderDocument(t, Config{}, modules)
+	assert.Empty(t, document.Packages[0].PackageAttributionTexts)
+	assert.Equal(t, modules[1].AttributionTexts, document.Packages[1].PackageAttributionTexts)
+}
+
+func TestRenderSourceInfo(t *testing.T) {
+	modules := testModules()
+	modules[1].SourceInfo = "scm repository: https://github.com/example/dependency"
+
+	document := renderDocument(t, Config{}, modules)
+	assert.Empty(t, document.Packages[0].PackageSourceInfo)
+	assert.Equal(t, "scm repository: https://github.com/example/dependency", document.Packages[1].PackageSourceInfo)
+
+	output := string(render(t, Config{}, modules))
+	assert.Contains(t, output, "PackageSourceInfo: <text>scm repository: https://github.com/example/dependency</text>\n")
+}
+
+func TestRenderGitMetadata(t *testing.T) {
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	git := &helper.GitMetadata{RemoteURL: "https://github.com/example/app.git", Commit: commit, Tag: "v1.0.0"}
+	modules := testModules()
+	cfg := Config{Git: git, SWHID: true}
+
+	document := renderDocument(t, cfg, modules)
+	root := document.Packages[0]
+	assert.Equal(t, "git+https://github.com/example/app.git@"+commit, root.PackageDownloadLocation)
+	assert.Equal(t, "built from git commit "+commit+" (tag v1.0.0) of https://github.com/example/app.git", root.PackageSourceInfo)
+	assert.Contains(t, root.PackageExternalRefs, models.ExternalRef{ReferenceCategory: "PERSISTENT-ID", ReferenceType: "swh", ReferenceLocator: "swh:1:rev:" + commit})
+	assert.Empty(t, document.Packages[1].PackageSourceInfo)
+	assert.Empty(t, modules[0].PackageDownloadLocation)
+
+	output := string(render(t, cfg, modules))
+	assert.Contains(t, output, "PackageSourceInfo: <text>built from git commit "+commit+" (tag v1.0.0) of https://github.com/example/app.git</text>\n")
+
+	document = renderDocument(t, Config{}, modules)
+	assert.Equal(t, "NOASSERTION", document.Packages[0].PackageDownloadLocation)
+	assert.Empty(t, document.Packages[0].PackageSourceInfo)
+}
+
+func TestDependencyLicenses(t *testing.T) {
+	modules := testModules()
+	modules[1].LicenseDeclared = "Apache-2.0 OR MIT"
+	modules[1].LicenseConcluded = "Apache-2.0 OR MIT"
+
+	document := renderDocument(t, Config{}, modules)
+	assert.Equal(t, "Apache-2.0 OR MIT", document.Packages[1].PackageLicenseDeclared)
+	assert.Equal(t, "Apache-2.0 OR MIT", document.Packages[1].PackageLicenseConcluded)
+
+	// no LicenseRef is emitted without its extracted text
+	modules[1].LicenseDeclared = "LicenseRef-acme"
+	document = renderDocument(t, Config{}, modules)
+	assert.Equal(t, "NOASSERTION", document.Packages[1].PackageLicenseDeclared)
+}
@@ -9,10 +9,19 @@ import (
 )
 
 // JsonSPDXRenderer implements an SPDXRenderer that outputs JSON formatted SPDX documents
-type JsonSPDXRenderer struct{}
+type JsonSPDXRenderer struct {
+	// Compact writes the document without indentation nor newlines
+	Compact bool
+}
 
-// RenderDocument uses golang JSON utilities to generated an indented output
+// RenderDocument uses golang JSON utilities to generated an indented output, or a compact one,
+// the relationships nested in the packages are written at the document level
 func (j JsonSPDXRenderer) RenderDocument(document models.Document) ([]byte, error) {
+	document.Relationships = document.AllRelationships()
+	if j.Compact {
+		return json.Marshal(document)
+	}
+
 	jsonBytes, err := json.MarshalIndent(document, "", "\t")
 	if err != nil {
 		return nil, err
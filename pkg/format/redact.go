@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// pathRedactor replaces local filesystem paths with a short hash of the path, so equal
+// paths stay recognizable without leaking user names or the directory layout
+type pathRedactor struct {
+	replacer *strings.Replacer
+}
+
+// newPathRedactor looks for the paths of the modules, the working directory and the home directory
+func newPathRedactor(modules []models.Module) pathRedactor {
+	found := map[string]bool{}
+	add := func(path string) {
+		if path == "" || !filepath.IsAbs(path) {
+			return
+		}
+		path = filepath.Clean(path)
+		// the root directory alone would redact every absolute path separator
+		if path == string(filepath.Separator) {
+			return
+		}
+		found[path] = true
+	}
+
+	for _, module := range modules {
+		add(module.LocalPath)
+		add(module.Path)
+		for _, subModule := range module.Modules {
+			if subModule != nil {
+				add(subModule.LocalPath)
+				add(subModule.Path)
+			}
+		}
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		add(wd)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		add(home)
+	}
+
+	paths := make([]string, 0, len(found))
+	for path := range found {
+		paths = append(paths, path)
+	}
+	// the longest path wins when several start at the same position
+	sort.Slice(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) > len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+
+	pairs := make([]string, 0, 2*len(paths))
+	for _, path := range paths {
+		pairs = append(pairs, path, redactedPath(path))
+	}
+
+	return pathRedactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+func redactedPath(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return fmt.Sprintf("[redacted-%x]", sum[:6])
+}
+
+func (r pathRedactor) redact(value string) string {
+	return r.replacer.Replace(value)
+}
+
+// redactLocation redacts a location, a local one is no valid download location or homepage anymore
+func (r pathRedactor) redactLocation(value string) string {
+	if redacted := r.redact(value); redacted != value {
+		return noAssertion
+	}
+	return value
+}
+
+// redactDocument redacts the local paths of every free text and location of the document
+func (r pathRedactor) redactDocument(document *models.Document) {
+	document.DocumentComment = r.redact(document.DocumentComment)
+
+	for i := range document.Packages {
+		pkg := &document.Packages[i]
+		pkg.PackageDownloadLocation = r.redactLocation(pkg.PackageDownloadLocation)
+		pkg.PackageHomePage = r.redactLocation(pkg.PackageHomePage)
+		pkg.PackageCopyrightText = r.redact(pkg.PackageCopyrightText)
+		pkg.PackageLicenseComments = r.redact(pkg.PackageLicenseComments)
+		pkg.PackageComment = r.redact(pkg.PackageComment)
+		pkg.PackageSourceInfo = r.redact(pkg.PackageSourceInfo)
+		if len(pkg.PackageAttributionTexts) > 0 {
+			texts := make([]string, len(pkg.PackageAttributionTexts))
+			for j, text := range pkg.PackageAttributionTexts {
+				texts[j] = r.redact(text)
+			}
+			pkg.PackageAttributionTexts = texts
+		}
+		for j := range pkg.Annotations {
+			pkg.Annotations[j].Comment = r.redact(pkg.Annotations[j].Comment)
+		}
+	}
+
+	for i := range document.ExtractedLicensingInfos {
+		info := &document.ExtractedLicensingInfos[i]
+		info.ExtractedText = r.redact(info.ExtractedText)
+		info.LicenseComment = r.redact(info.LicenseComment)
+	}
+}
@@ -4,6 +4,7 @@ package format
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"text/template"
 
@@ -18,8 +19,13 @@ DataLicense: {{ .DataLicense }}
 SPDXID: {{ .SPDXID }}
 DocumentName: {{ .DocumentName }}
 DocumentNamespace: {{ .DocumentNamespace }}
-Creator: {{ range .CreationInfo.Creators }}{{ . -}} {{ end }}
+{{- range .CreationInfo.Creators }}
+Creator: {{ . }}
+{{- end }}
 Created: {{ .CreationInfo.Created }}
+{{- with .DocumentComment }}
+DocumentComment: {{ text . }}
+{{- end }}
 
 {{ range .Packages }}
 ##### Package representing the {{.PackageName}}
@@ -41,6 +47,29 @@ PackageLicenseDeclared: {{ .PackageLicenseDeclared }}
 PackageCopyrightText: {{ .PackageCopyrightText }}
 PackageLicenseComments: {{ .PackageLicenseComments }}
 PackageComment: {{ .PackageComment }}
+{{- with .PackageSourceInfo }}
+PackageSourceInfo: <text>{{ . }}</text>
+{{- end }}
+{{- range .PackageAttributionTexts }}
+PackageAttributionText: <text>{{ . }}</text>
+{{- end }}
+{{- range .PackageExternalRefs }}
+ExternalRef: {{ .ReferenceCategory }} {{ .ReferenceType }} {{ .ReferenceLocator }}
+{{- end }}
+{{- with .PrimaryPackagePurpose }}
+PrimaryPackagePurpose: {{ . }}
+{{- end }}
+{{- range .Relationships }}
+Relationship: {{ .SPDXElementID }} {{ .RelationshipType }} {{ .RelatedSPDXElement }}
+{{- end }}
+{{- $spdxID := .SPDXID }}
+{{- range .Annotations }}
+Annotator: {{ .Annotator }}
+AnnotationDate: {{ .AnnotationDate }}
+AnnotationType: {{ .AnnotationType }}
+SPDXREF: {{ $spdxID }}
+AnnotationComment: <text>{{ .Comment }}</text>
+{{- end }}
 {{ end }}
 {{- range .Relationships }}
 Relationship: {{ .SPDXElementID }} {{ .RelationshipType }} {{ .RelatedSPDXElement }}
@@ -63,6 +92,7 @@ func (t TagValueSPDXRenderer) RenderDocument(document models.Document) ([]byte,
 		"isAsserted": func(s string) bool {
 			return !strings.Contains(s, noAssertion)
 		},
+		"text": textValue,
 	}).Parse(tagValueTemplate)
 
 	if err != nil {
@@ -75,3 +105,12 @@ func (t TagValueSPDXRenderer) RenderDocument(document models.Document) ([]byte,
 	}
 	return templateBuffer.Bytes(), err
 }
+
+// textValue wraps multi-line values in <text> tags as required by the tag value format
+func textValue(s string) string {
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+
+	return fmt.Sprintf("<text>%s</text>", s)
+}
@@ -8,4 +8,5 @@ import ()
 type Handler interface {
 	Run() error
 	Complete() error
+	OutputFiles() map[string]string
 }
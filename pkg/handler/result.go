@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Result is the outcome of the generation for one package manager
+type Result struct {
+	Plugin models.PluginMetadata
+	// Workspace is the name of the workspace package the document describes, empty for the project
+	Workspace string
+	// Document is nil when the generation failed, see Errors
+	Document *models.Document
+	Warnings []string
+	// NewPackages are the purls which are not in the baseline, only set with SPDXSettings.BaselineFile
+	NewPackages []string
+	Stats       Stats
+	Errors      []error
+}
+
+// Stats summarizes a generation
+type Stats struct {
+	Packages      int
+	Relationships int
+	Duration      time.Duration
+}
+
+// Generate runs the generation for every package manager found at settings.Path and returns
+// the documents without writing them, settings.OutputDir and settings.Format are ignored
+func Generate(settings SPDXSettings) ([]Result, error) {
+	sh, err := newSPDXHandler(settings)
+	if err != nil {
+		return nil, err
+	}
+	defer sh.release()
+
+	return sh.results()
+}
+
+func (sh *spdxHandler) results() ([]Result, error) {
+	if len(sh.modulesManager) == 0 {
+		return nil, errNoModuleManagerFound
+	}
+
+	var results []Result
+	for _, mm := range sh.modulesManager {
+		if sh.timedOut() {
+			break
+		}
+
+		generated, _ := sh.generate(mm, "")
+		results = append(results, generated...)
+	}
+
+	if sh.timedOut() {
+		return results, fmt.Errorf("%w after %s", errGenerationTimedOut, sh.config.Timeout)
+	}
+
+	return results, nil
+}
+
+// Key identifies the result in the logs and the output files, the plugin slug followed by the
+// slug of the workspace package, e.g. npm/example-lib
+func (r Result) Key() string {
+	if r.Workspace == "" {
+		return r.Plugin.Slug
+	}
+	return r.Plugin.Slug + "/" + workspaceSlug(r.Workspace)
+}
+
+func buildStats(document *models.Document) Stats {
+	return Stats{
+		Packages:      len(document.Packages),
+		Relationships: len(document.AllRelationships()),
+	}
+}
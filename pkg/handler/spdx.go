@@ -3,20 +3,31 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
 	"github.com/spdx/spdx-sbom-generator/pkg/format"
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/image"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules"
+	"github.com/spdx/spdx-sbom-generator/pkg/policy"
 )
 
 var errNoModuleManagerFound = errors.New("No module manager found")
 var errOutputDirDoesNotExist = errors.New("Output Directory does not exist")
+var errGenerationTimedOut = errors.New("Generation timed out")
+var errWarningsAsErrors = errors.New("Generation completed with warnings")
+var errNewPackages = errors.New("Generation found packages not in the baseline")
+var errNewPackagesWithoutBaseline = errors.New("Failing on new packages requires a baseline file")
 
 // SPDXSettings ...
 type SPDXSettings struct {
@@ -27,14 +38,108 @@ type SPDXSettings struct {
 	OutputDir string
 	Schema    string
 	Format    models.OutputFormat
+	// Timeout bounds the whole generation, zero disables it
+	Timeout time.Duration
+	// IncludeBuildTool adds the build tool (maven, npm, go...) as a package
+	IncludeBuildTool bool
+	// IncludeBuildPlugins adds the build plugins, e.g. maven plugins, as packages
+	IncludeBuildPlugins bool
+	// WarningsAsErrors makes Complete fail when any warning was raised
+	WarningsAsErrors bool
+	// NoRelationships omits every relationship but the document DESCRIBES
+	NoRelationships bool
+	// DocumentComment is a free form comment stamped on every document
+	DocumentComment string
+	// LicensePolicy reports dependencies whose license conflicts with the project license as warnings
+	LicensePolicy bool
+	// AllowNetwork lets plugins query remote repositories, e.g. to fetch checksums
+	AllowNetwork bool
+	// Offline keeps the plugins and their package managers off the network, it overrides AllowNetwork
+	Offline bool
+	// CompactJSON writes the JSON documents without indentation
+	CompactJSON bool
+	// LineEnding is the newline of the documents, LF when empty
+	LineEnding format.LineEnding
+	// SWHID adds Software Heritage identifiers to the packages downloaded from a git commit
+	SWHID bool
+	// RedactPaths hashes the local filesystem paths found in the documents
+	RedactPaths bool
+	// Resume keeps what the plugins resolved in a cache file of the output directory,
+	// an interrupted generation resumes from it. The file is removed once complete
+	Resume bool
+	// VersionLockFile pins the dependency versions, e.g. a versions.properties or gradle.lockfile
+	VersionLockFile string
+	// PackagingExtensions maps custom maven packagings to their artifact file extension, e.g. bundle=jar
+	PackagingExtensions map[string]string
+	// MavenProfiles are the maven profiles to activate, a !id deactivates the profile.
+	// The activation rules of the poms apply when empty
+	MavenProfiles []string
+	// MavenEffectivePom reads the poms computed by mvn help:effective-pom, with the parents, imported
+	// BOMs, profiles and properties maven resolves. pom.xml is read when mvn fails
+	MavenEffectivePom bool
+	// IncludeScopes are the only maven dependency scopes listed when not empty, e.g. compile and runtime
+	IncludeScopes []string
+	// ExcludeScopes are the maven dependency scopes never listed, e.g. test and provided
+	ExcludeScopes []string
+	// MavenSettings is the settings.xml passed to mvn -s, its mirrors, proxies and credentials are
+	// used to reach the remote repositories. ~/.m2/settings.xml when empty
+	MavenSettings string
+	// MavenParallelism is the number of maven dependencies whose checksums and licenses are looked up
+	// at once, as many as CPUs when 0
+	MavenParallelism int
+	// GoVendor lists the go modules of vendor/modules.txt, for hermetic builds without the module cache
+	// nor the network
+	GoVendor bool
+	// LockfileOnly reads the modules from the manifests and lockfiles without running the package managers,
+	// a best-effort SBOM for the environments they are not installed in. It implies Offline
+	LockfileOnly bool
+	// RootFS is the root filesystem the installed operating system packages are read from, e.g. a mounted
+	// container image. The project path when empty
+	RootFS string
+	// Image is a container image, a reference or a docker saved image or OCI image layout, whose layers
+	// are merged and scanned instead of Path and RootFS. The project is read from the image working
+	// directory, from its lockfiles only
+	Image string
+	// LicenseMatcher identifies the license files, e.g. against a proprietary license database.
+	// The SPDX license list when nil
+	LicenseMatcher helper.LicenseMatcher
+	// BaselineFile lists the purls of a previous SBOM, only the other packages are emitted
+	BaselineFile string
+	// FailOnNewPackage makes Complete fail when a package is not in the baseline, BaselineFile is required
+	FailOnNewPackage bool
+	// ComponentsFile lists the packages the package managers cannot detect, they are added to every document
+	ComponentsFile string
+	// TimestampPackages stamps every package with a REVIEW annotation of the analysis time and plugin
+	TimestampPackages bool
+	// Exclude lists the packages dropped from the documents with everything they pull in
+	Exclude []string
+	// RelationshipStyle selects whether the relationships are listed after all the packages or in them
+	RelationshipStyle format.RelationshipStyle
+	// GitMetadata records the remote, commit and tag of the git repository Path belongs to on the root package
+	GitMetadata bool
+	// Workspaces selects whether the workspace packages the project contains get a document each,
+	// they are described with the project when empty
+	Workspaces WorkspaceMode
 }
 
+// resumeCacheFile is the cache file written to the output directory with SPDXSettings.Resume
+const resumeCacheFile = ".spdx-sbom-generator-cache.json"
+
 type spdxHandler struct {
 	config         SPDXSettings
 	modulesManager []*modules.Manager
 	format         format.Format
 	outputFiles    map[string]string
 	errors         map[string]error
+	warnings       map[string][]string
+	newPackages    map[string][]string
+	cache          *cache.Cache
+	baseline       format.Baseline
+	components     []format.Component
+	git            *helper.GitMetadata
+	image          *image.Image
+	ctx            context.Context
+	cancel         context.CancelFunc
 }
 
 // getFiletypeForOutputFormat gets the type suffix for the type of output chosen
@@ -55,10 +160,99 @@ func NewSPDX(settings SPDXSettings) (Handler, error) {
 		return nil, errOutputDirDoesNotExist
 	}
 
+	return newSPDXHandler(settings)
+}
+
+func newSPDXHandler(settings SPDXSettings) (*spdxHandler, error) {
+	if settings.FailOnNewPackage && settings.BaselineFile == "" {
+		return nil, errNewPackagesWithoutBaseline
+	}
+
+	var baseline format.Baseline
+	if settings.BaselineFile != "" {
+		var err error
+		baseline, err = format.LoadBaseline(settings.BaselineFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var components []format.Component
+	if settings.ComponentsFile != "" {
+		var err error
+		components, err = format.LoadComponents(settings.ComponentsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the timeout bounds the image pull too
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if settings.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), settings.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	var img *image.Image
+	if settings.Image != "" {
+		var err error
+		img, err = image.Open(ctx, settings.Image, settings.Offline)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		log.Infof("Scanning the image %s extracted to %s", settings.Image, img.RootFS)
+		// the package managers of the image are not the ones installed here
+		settings.Path, settings.RootFS, settings.LockfileOnly = img.ProjectPath(), img.RootFS, true
+	}
+
+	var git *helper.GitMetadata
+	if settings.GitMetadata {
+		metadata, err := helper.ReadGitMetadata(settings.Path)
+		if err != nil {
+			log.Warnf("Failed to read the git metadata of %s: %v", settings.Path, err)
+		} else {
+			git = &metadata
+		}
+	}
+
+	var resumeCache *cache.Cache
+	if settings.Resume {
+		var err error
+		resumeCache, err = cache.Open(filepath.Join(settings.OutputDir, resumeCacheFile))
+		if err != nil {
+			cancel()
+			img.Close()
+			return nil, err
+		}
+	}
+
 	mm, err := modules.New(modules.Config{
-		Path: settings.Path,
+		Path:                settings.Path,
+		Context:             ctx,
+		IncludeBuildTool:    settings.IncludeBuildTool,
+		IncludeBuildPlugins: settings.IncludeBuildPlugins,
+		AllowNetwork:        settings.AllowNetwork,
+		Offline:             settings.Offline,
+		Cache:               resumeCache,
+		VersionLockFile:     settings.VersionLockFile,
+		PackagingExtensions: settings.PackagingExtensions,
+		MavenProfiles:       settings.MavenProfiles,
+		MavenEffectivePom:   settings.MavenEffectivePom,
+		IncludeScopes:       settings.IncludeScopes,
+		ExcludeScopes:       settings.ExcludeScopes,
+		MavenSettings:       settings.MavenSettings,
+		MavenParallelism:    settings.MavenParallelism,
+		GoVendor:            settings.GoVendor,
+		LockfileOnly:        settings.LockfileOnly,
+		RootFS:              settings.RootFS,
+		LicenseMatcher:      settings.LicenseMatcher,
 	})
 	if err != nil {
+		cancel()
+		img.Close()
 		return nil, err
 	}
 
@@ -67,7 +261,16 @@ func NewSPDX(settings SPDXSettings) (Handler, error) {
 		modulesManager: mm,
 		outputFiles:    map[string]string{},
 		errors:         map[string]error{},
-	}, err
+		warnings:       map[string][]string{},
+		newPackages:    map[string][]string{},
+		cache:          resumeCache,
+		baseline:       baseline,
+		components:     components,
+		git:            git,
+		image:          img,
+		ctx:            ctx,
+		cancel:         cancel,
+	}, nil
 }
 
 // Run ...
@@ -77,40 +280,142 @@ func (sh *spdxHandler) Run() error {
 	}
 
 	for _, mm := range sh.modulesManager {
+		if sh.timedOut() {
+			break
+		}
+
 		plugin := mm.Plugin.GetMetadata()
 		filename := fmt.Sprintf("bom-%s.%s", plugin.Slug, getFiletypeForOutputFormat(sh.config.Format))
 		outputFile := filepath.Join(sh.config.OutputDir, filename)
 
 		log.Infof("Running generator for Module Manager: `%s` with output `%s`", plugin.Slug, outputFile)
-		if err := mm.Run(); err != nil {
-			sh.errors[plugin.Slug] = err
-			continue
+		results, formats := sh.generate(mm, outputFile)
+		for i, result := range results {
+			key := result.Key()
+			if len(result.Warnings) > 0 {
+				sh.warnings[key] = append(sh.warnings[key], result.Warnings...)
+			}
+			if len(result.NewPackages) > 0 {
+				sh.newPackages[key] = result.NewPackages
+			}
+			if len(result.Errors) > 0 {
+				sh.errors[key] = result.Errors[0]
+				continue
+			}
+
+			if err := formats[i].Write(*result.Document); err != nil {
+				sh.errors[key] = err
+				continue
+			}
+			sh.outputFiles[key] = formats[i].Config.Filename
 		}
+	}
 
-		format, err := format.New(format.Config{
-			Filename:     outputFile,
-			ToolVersion:  sh.config.Version,
-			OutputFormat: sh.config.Format,
-			GetSource: func() []models.Module {
-				return mm.GetSource()
-			},
-		})
-		if err != nil {
-			sh.errors[plugin.Slug] = err
-			continue
+	// outputs rendered before the deadline are kept, see Complete
+	if sh.timedOut() {
+		return fmt.Errorf("%w after %s", errGenerationTimedOut, sh.config.Timeout)
+	}
+
+	return nil
+}
+
+// generate runs the package manager and builds its documents, the one of the project written to outputFile
+// and, with WorkspaceModePerWorkspace, one per workspace package the project contains
+func (sh *spdxHandler) generate(mm *modules.Manager, outputFile string) ([]Result, []format.Format) {
+	start := time.Now()
+	result := Result{Plugin: mm.Plugin.GetMetadata()}
+
+	if err := mm.Run(); err != nil {
+		result.Errors = append(result.Errors, err)
+		result.Stats.Duration = time.Since(start)
+		return []Result{result}, []format.Format{{}}
+	}
+
+	source, workspaces := mm.GetSource(), [][]models.Module(nil)
+	if sh.config.Workspaces == WorkspaceModePerWorkspace {
+		source, workspaces = splitWorkspaces(source)
+	}
+
+	projectResult, projectFormat := sh.render(mm, result, source, outputFile, start)
+	results, formats := []Result{projectResult}, []format.Format{projectFormat}
+	for _, workspace := range workspaces {
+		workspaceResult := Result{Plugin: result.Plugin, Workspace: workspace[0].Name}
+		workspaceFile := ""
+		if outputFile != "" {
+			// e.g. bom-npm-example-lib.spdx for the workspace package @example/lib
+			filename := fmt.Sprintf("bom-%s-%s.%s", result.Plugin.Slug, workspaceSlug(workspace[0].Name), getFiletypeForOutputFormat(sh.config.Format))
+			workspaceFile = filepath.Join(filepath.Dir(outputFile), filename)
 		}
-		if err := format.Render(); err != nil {
-			sh.errors[plugin.Slug] = err
-			continue
+		workspaceResult, workspaceFormat := sh.render(mm, workspaceResult, workspace, workspaceFile, time.Now())
+		results = append(results, workspaceResult)
+		formats = append(formats, workspaceFormat)
+	}
+	return results, formats
+}
+
+// render builds the document of the source modules the package manager resolved, outputFile is where
+// the format writes it
+func (sh *spdxHandler) render(mm *modules.Manager, result Result, source []models.Module, outputFile string, start time.Time) (Result, format.Format) {
+	if sh.image != nil {
+		source = sh.image.Annotate(source)
+	}
+
+	f, err := format.New(format.Config{
+		Filename:          outputFile,
+		ToolVersion:       sh.config.Version,
+		OutputFormat:      sh.config.Format,
+		NoRelationships:   sh.config.NoRelationships,
+		DocumentComment:   sh.config.DocumentComment,
+		CompactJSON:       sh.config.CompactJSON,
+		LineEnding:        sh.config.LineEnding,
+		SWHID:             sh.config.SWHID,
+		RedactPaths:       sh.config.RedactPaths,
+		SchemaVersion:     sh.config.Schema,
+		Baseline:          sh.baseline,
+		Components:        sh.components,
+		Exclude:           sh.config.Exclude,
+		TimestampPackages: sh.config.TimestampPackages,
+		ResolutionMethod:  sh.resolutionMethod(result.Plugin),
+		RelationshipStyle: sh.config.RelationshipStyle,
+		Git:               sh.git,
+		Creators:          mm.Creators(),
+		GetSource: func() []models.Module {
+			return source
+		},
+	})
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, f
+	}
+
+	if sh.config.LicensePolicy {
+		for _, violation := range policy.Check(source) {
+			result.Warnings = append(result.Warnings, violation.String())
 		}
-		sh.outputFiles[plugin.Slug] = outputFile
 	}
 
-	return nil
+	document, err := f.Document()
+	result.Warnings = append(result.Warnings, f.Warnings()...)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		result.Stats.Duration = time.Since(start)
+		return result, f
+	}
+
+	if sh.config.BaselineFile != "" {
+		result.NewPackages = f.NewPackages()
+	}
+
+	result.Document = document
+	result.Stats = buildStats(document)
+	result.Stats.Duration = time.Since(start)
+	return result, f
 }
 
 // Complete ...
 func (sh *spdxHandler) Complete() error {
+	sh.release()
+
 	if len(sh.errors) > 0 {
 		log.Info("Command has completed with errors for some package managers, see details below")
 		for plugin, err := range sh.errors {
@@ -118,11 +423,77 @@ func (sh *spdxHandler) Complete() error {
 		}
 	}
 
+	warnings := 0
+	for plugin, pluginWarnings := range sh.warnings {
+		for _, warning := range pluginWarnings {
+			log.Warnf("Plugin %s: %s", plugin, warning)
+		}
+		warnings += len(pluginWarnings)
+	}
+
 	if len(sh.outputFiles) > 0 {
 		log.Info("Command completed successful for below package managers")
 		for plugin, filepath := range sh.outputFiles {
 			log.Infof("Plugin %s generated output at %s", plugin, filepath)
 		}
 	}
+
+	// an incomplete generation keeps the cache for the next run
+	if len(sh.errors) == 0 && !sh.timedOut() {
+		if err := sh.cache.Remove(); err != nil {
+			log.Warnf("Failed to remove the resume cache: %v", err)
+		}
+	} else if err := sh.cache.Flush(); err != nil {
+		log.Warnf("Failed to write the resume cache: %v", err)
+	}
+
+	var newPackages []string
+	seen := map[string]bool{}
+	for plugin, pluginPackages := range sh.newPackages {
+		for _, pkg := range pluginPackages {
+			log.Infof("Plugin %s: package %s is not in the baseline", plugin, pkg)
+			if !seen[pkg] {
+				seen[pkg] = true
+				newPackages = append(newPackages, pkg)
+			}
+		}
+	}
+
+	if sh.config.FailOnNewPackage && len(newPackages) > 0 {
+		sort.Strings(newPackages)
+		return fmt.Errorf("%w: %s", errNewPackages, strings.Join(newPackages, ", "))
+	}
+	if sh.config.WarningsAsErrors && warnings > 0 {
+		return fmt.Errorf("%w: %d warning(s)", errWarningsAsErrors, warnings)
+	}
 	return nil
 }
+
+// release cancels the generation context and removes the extracted image
+func (sh *spdxHandler) release() {
+	if sh.cancel != nil {
+		sh.cancel()
+	}
+	if err := sh.image.Close(); err != nil {
+		log.Warnf("Failed to remove the extracted image: %v", err)
+	}
+}
+
+// OutputFiles returns the generated files keyed by plugin slug, e.g. npm, followed by the slug of the
+// workspace package for the documents of the workspaces, e.g. npm/example-lib
+func (sh *spdxHandler) OutputFiles() map[string]string {
+	return sh.outputFiles
+}
+
+func (sh *spdxHandler) timedOut() bool {
+	return sh.ctx != nil && errors.Is(sh.ctx.Err(), context.DeadlineExceeded)
+}
+
+// resolutionMethod tells how the packages of the plugin were resolved, by its package manager or from its
+// lockfiles only
+func (sh *spdxHandler) resolutionMethod(plugin models.PluginMetadata) string {
+	if sh.config.LockfileOnly {
+		return fmt.Sprintf("%s lockfile", plugin.Name)
+	}
+	return fmt.Sprintf("%s package manager", plugin.Name)
+}
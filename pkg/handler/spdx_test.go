@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules"
+)
+
+// stubPlugin returns a fixed list of modules
+type stubPlugin struct {
+	modules []models.Module
+}
+
+func (s *stubPlugin) SetRootModule(path string) error { return nil }
+func (s *stubPlugin) GetVersion() (string, error)     { return "1.0.0", nil }
+func (s *stubPlugin) GetMetadata() models.PluginMetadata {
+	return models.PluginMetadata{Name: "Stub", Slug: "stub"}
+}
+func (s *stubPlugin) GetRootModule(path string) (*models.Module, error) { return nil, nil }
+func (s *stubPlugin) ListUsedModules(path string) ([]models.Module, error) {
+	return s.modules, nil
+}
+func (s *stubPlugin) ListModulesWithDeps(path string) ([]models.Module, error) {
+	return s.modules, nil
+}
+func (s *stubPlugin) IsValid(path string) bool              { return true }
+func (s *stubPlugin) HasModulesInstalled(path string) error { return nil }
+
+func newTestHandler(t *testing.T, settings SPDXSettings, source []models.Module) *spdxHandler {
+	path := t.TempDir()
+	settings.Path = path
+	settings.OutputDir = path
+
+	return &spdxHandler{
+		config: settings,
+		modulesManager: []*modules.Manager{{
+			Config: modules.Config{Path: path},
+			Plugin: &stubPlugin{modules: source},
+		}},
+		outputFiles: map[string]string{},
+		errors:      map[string]error{},
+		warnings:    map[string][]string{},
+		newPackages: map[string][]string{},
+	}
+}
+
+// modulesWithWarnings has a dependency without version
+func modulesWithWarnings() []models.Module {
+	dependency := models.Module{
+		Name:     "dependency",
+		CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+	}
+	return []models.Module{
+		{
+			Name:     "root",
+			Version:  "1.0.0",
+			Root:     true,
+			CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+			Modules:  map[string]*models.Module{"dependency": &dependency},
+		},
+		dependency,
+	}
+}
+
+func TestWarningsAsErrors(t *testing.T) {
+	handler := newTestHandler(t, SPDXSettings{WarningsAsErrors: true}, modulesWithWarnings())
+
+	assert.NoError(t, handler.Run())
+	assert.Contains(t, handler.outputFiles, "stub")
+
+	err := handler.Complete()
+	assert.True(t, errors.Is(err, errWarningsAsErrors))
+}
+
+func TestWarningsWithoutWarningsAsErrors(t *testing.T) {
+	handler := newTestHandler(t, SPDXSettings{}, modulesWithWarnings())
+
+	assert.NoError(t, handler.Run())
+	assert.Len(t, handler.warnings["stub"], 1)
+	assert.NoError(t, handler.Complete())
+}
+
+func TestResults(t *testing.T) {
+	handler := newTestHandler(t, SPDXSettings{}, modulesWithWarnings())
+
+	results, err := handler.results()
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	result := results[0]
+	assert.Equal(t, "stub", result.Plugin.Slug)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, []string{"unresolved version for package dependency"}, result.Warnings)
+	assert.Equal(t, 2, result.Stats.Packages)
+	assert.Equal(t, 2, result.Stats.Relationships)
+	assert.Equal(t, "SPDXRef-DOCUMENT", result.Document.SPDXID)
+	assert.Empty(t, handler.outputFiles)
+}
+
+func TestCompleteRemovesResumeCache(t *testing.T) {
+	handler := newTestHandler(t, SPDXSettings{}, modulesWithWarnings())
+	path := filepath.Join(handler.config.OutputDir, resumeCacheFile)
+
+	resumeCache, err := cache.Open(path)
+	assert.NoError(t, err)
+	assert.NoError(t, resumeCache.Put("pkg:maven/com.acme/acme-core@1.0.0", cache.Entry{Checksum: "checksum"}))
+	handler.cache = resumeCache
+
+	// a failed plugin keeps the cache for the next run
+	handler.errors["other"] = errors.New("interrupted")
+	assert.NoError(t, handler.Complete())
+	assert.FileExists(t, path)
+
+	delete(handler.errors, "other")
+	assert.NoError(t, handler.Run())
+	assert.NoError(t, handler.Complete())
+	assert.NoFileExists(t, path)
+}
+
+// modulesWithPurls has a root depending on debug and express
+func modulesWithPurls() []models.Module {
+	debug := models.Module{Name: "debug", Version: "4.3.2", PackageURL: "pkg:npm/debug@4.3.2"}
+	express := models.Module{Name: "express", Version: "4.17.1", PackageURL: "pkg:npm/express@4.17.1"}
+	return []models.Module{
+		{
+			Name:       "root",
+			Version:    "1.0.0",
+			Root:       true,
+			PackageURL: "pkg:npm/root@1.0.0",
+			Modules:    map[string]*models.Module{"debug": &debug, "express": &express},
+		},
+		debug,
+		express,
+	}
+}
+
+func TestFailOnNewPackage(t *testing.T) {
+	for _, tc := range []struct {
+		baseline string
+		err      string
+	}{
+		{"pkg:npm/debug@4.3.2\n", "Generation found packages not in the baseline: pkg:npm/express@4.17.1"},
+		{"", "Generation found packages not in the baseline: pkg:npm/debug@4.3.2, pkg:npm/express@4.17.1"},
+		{"pkg:npm/debug@4.3.2\npkg:npm/express@4.17.1\n", ""},
+	} {
+		baselineFile := filepath.Join(t.TempDir(), "baseline.txt")
+		assert.NoError(t, ioutil.WriteFile(baselineFile, []byte(tc.baseline), 0644))
+		baseline, err := format.LoadBaseline(baselineFile)
+		assert.NoError(t, err)
+
+		handler := newTestHandler(t, SPDXSettings{BaselineFile: baselineFile, FailOnNewPackage: true}, modulesWithPurls())
+		handler.baseline = baseline
+
+		assert.NoError(t, handler.Run())
+		assert.Contains(t, handler.outputFiles, "stub")
+
+		err = handler.Complete()
+		if tc.err == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.True(t, errors.Is(err, errNewPackages))
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+func TestFailOnNewPackageRequiresBaseline(t *testing.T) {
+	_, err := newSPDXHandler(SPDXSettings{FailOnNewPackage: true})
+	assert.True(t, errors.Is(err, errNewPackagesWithoutBaseline))
+}
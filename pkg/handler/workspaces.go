@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// WorkspaceMode selects whether the packages a project CONTAINS, e.g. its npm, yarn or pnpm workspaces,
+// are described by the document of the project or each by a document of its own
+type WorkspaceMode string
+
+const (
+	// WorkspaceModeAggregate describes the project and its workspace packages in one document
+	WorkspaceModeAggregate WorkspaceMode = "aggregate"
+	// WorkspaceModePerWorkspace writes a document per workspace package next to the one of the project,
+	// the workspace packages it depends on are DEPENDS_ON packages of its document
+	WorkspaceModePerWorkspace WorkspaceMode = "per-workspace"
+)
+
+var unsafeFileNameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// splitWorkspaces returns the modules of the project without the workspace packages it contains and,
+// for each of these, the modules of the workspace package rooted at it. Every list only keeps the
+// modules its root reaches. workspaces is empty when the root contains no package
+func splitWorkspaces(modules []models.Module) (project []models.Module, workspaces [][]models.Module) {
+	if len(modules) == 0 {
+		return modules, nil
+	}
+
+	rootIndex := 0
+	for i, module := range modules {
+		if module.Root {
+			rootIndex = i
+			break
+		}
+	}
+	root := modules[rootIndex]
+
+	var names []string
+	for name, subModule := range root.Modules {
+		if subModule != nil && subModule.Relationship == models.RelationshipContains {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return modules, nil
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int, len(modules))
+	for i, module := range modules {
+		index[module.Key()] = i
+	}
+
+	projectRoot := root
+	projectRoot.Modules = make(map[string]*models.Module, len(root.Modules))
+	for name, subModule := range root.Modules {
+		if subModule == nil || subModule.Relationship != models.RelationshipContains {
+			projectRoot.Modules[name] = subModule
+		}
+	}
+	project = reachableModules(modules, index, projectRoot)
+
+	for _, name := range names {
+		i, ok := index[root.Modules[name].Key()]
+		if !ok {
+			continue
+		}
+		workspace := modules[i]
+		workspace.Root = true
+		workspace.Relationship = ""
+		workspaces = append(workspaces, reachableModules(modules, index, workspace))
+	}
+	return project, workspaces
+}
+
+// reachableModules returns root followed by the listed modules it depends on, directly or not,
+// in the order of modules. The other modules are not roots of the returned list
+func reachableModules(modules []models.Module, index map[string]int, root models.Module) []models.Module {
+	visited := map[string]bool{root.Key(): true}
+	queue := []models.Module{root}
+	for len(queue) > 0 {
+		module := queue[0]
+		queue = queue[1:]
+
+		for _, subModule := range module.Modules {
+			if subModule == nil {
+				continue
+			}
+			key := subModule.Key()
+			i, ok := index[key]
+			if !ok || visited[key] {
+				continue
+			}
+			visited[key] = true
+			queue = append(queue, modules[i])
+		}
+	}
+
+	reachable := []models.Module{root}
+	for _, module := range modules {
+		key := module.Key()
+		if !visited[key] || key == root.Key() {
+			continue
+		}
+		module.Root = false
+		reachable = append(reachable, module)
+	}
+	return reachable
+}
+
+// workspaceSlug returns the name of a workspace package usable in file names and urls, e.g. example-lib of @example/lib
+func workspaceSlug(workspace string) string {
+	return strings.Trim(unsafeFileNameRegex.ReplaceAllString(workspace, "-"), "-.")
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// modulesWithWorkspaces has a root containing the workspace packages app and @example/lib, app depends on @example/lib
+func modulesWithWorkspaces() []models.Module {
+	checksum := &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "da39a3ee5e6b4b0d3255bfef95601890afd80709"}
+	link := func(name, version string, relationship models.RelationshipType) *models.Module {
+		return &models.Module{Name: name, Version: version, Relationship: relationship, CheckSum: checksum}
+	}
+	return []models.Module{
+		{Name: "monorepo", Version: "1.0.0", Root: true, CheckSum: checksum, Modules: map[string]*models.Module{
+			"app":          link("app", "0.1.0", models.RelationshipContains),
+			"@example/lib": link("@example/lib", "1.0.0", models.RelationshipContains),
+			"ms":           link("ms", "2.1.3", models.RelationshipDevDependencyOf),
+		}},
+		{Name: "app", Version: "0.1.0", CheckSum: checksum, Modules: map[string]*models.Module{
+			"@example/lib": link("@example/lib", "1.0.0", ""),
+			"debug":        link("debug", "4.3.4", ""),
+		}},
+		{Name: "@example/lib", Version: "1.0.0", CheckSum: checksum, Modules: map[string]*models.Module{
+			"ms": link("ms", "2.1.3", models.RelationshipDevDependencyOf),
+		}},
+		{Name: "debug", Version: "4.3.4", CheckSum: checksum, Modules: map[string]*models.Module{
+			"ms": link("ms", "2.1.2", ""),
+		}},
+		{Name: "ms", Version: "2.1.2", CheckSum: checksum},
+		{Name: "ms", Version: "2.1.3", CheckSum: checksum},
+	}
+}
+
+func moduleKeys(modules []models.Module) []string {
+	var keys []string
+	for _, module := range modules {
+		keys = append(keys, module.Key())
+	}
+	return keys
+}
+
+func TestSplitWorkspaces(t *testing.T) {
+	project, workspaces := splitWorkspaces(modulesWithWorkspaces())
+
+	assert.Equal(t, []string{"monorepo@1.0.0", "ms@2.1.3"}, moduleKeys(project))
+	assert.Len(t, project[0].Modules, 1)
+	assert.Len(t, workspaces, 2)
+
+	lib := workspaces[0]
+	assert.Equal(t, []string{"@example/lib@1.0.0", "ms@2.1.3"}, moduleKeys(lib))
+	assert.True(t, lib[0].Root)
+
+	// the workspace packages app depends on are listed as its dependencies
+	app := workspaces[1]
+	assert.Equal(t, []string{"app@0.1.0", "@example/lib@1.0.0", "debug@4.3.4", "ms@2.1.2", "ms@2.1.3"}, moduleKeys(app))
+	assert.True(t, app[0].Root)
+	assert.False(t, app[1].Root)
+
+	// the source is left untouched
+	source := modulesWithWorkspaces()
+	splitWorkspaces(source)
+	assert.Len(t, source[0].Modules, 3)
+}
+
+func TestSplitWithoutWorkspaces(t *testing.T) {
+	project, workspaces := splitWorkspaces(modulesWithPurls())
+	assert.Len(t, project, 3)
+	assert.Empty(t, workspaces)
+}
+
+func TestRunPerWorkspace(t *testing.T) {
+	handler := newTestHandler(t, SPDXSettings{Workspaces: WorkspaceModePerWorkspace}, modulesWithWorkspaces())
+
+	assert.NoError(t, handler.Run())
+	assert.NoError(t, handler.Complete())
+	assert.Equal(t, map[string]string{
+		"stub":             filepath.Join(handler.config.OutputDir, "bom-stub.spdx"),
+		"stub/example-lib": filepath.Join(handler.config.OutputDir, "bom-stub-example-lib.spdx"),
+		"stub/app":         filepath.Join(handler.config.OutputDir, "bom-stub-app.spdx"),
+	}, handler.outputFiles)
+	for _, file := range handler.outputFiles {
+		assert.FileExists(t, file)
+	}
+
+	results, err := handler.results()
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "app", results[2].Workspace)
+	assert.Equal(t, "app-0.1.0", results[2].Document.DocumentName)
+	assert.Contains(t, results[2].Document.AllRelationships(), models.Relationship{
+		SPDXElementID:      "SPDXRef-Package-app",
+		RelatedSPDXElement: "SPDXRef-Package-@example.lib-1.0.0",
+		RelationshipType:   "DEPENDS_ON",
+	})
+}
+
+func TestRunAggregate(t *testing.T) {
+	handler := newTestHandler(t, SPDXSettings{Workspaces: WorkspaceModeAggregate}, modulesWithWorkspaces())
+
+	assert.NoError(t, handler.Run())
+	assert.Len(t, handler.outputFiles, 1)
+
+	results, err := handler.results()
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 6, results[0].Stats.Packages)
+}
@@ -3,8 +3,10 @@
 package helper
 
 import (
+	"context"
 	"errors"
 	"io"
+	"os"
 	"os/exec"
 )
 
@@ -15,6 +17,9 @@ type CmdOptions struct {
 	Name      string
 	Args      []string
 	Directory string
+	Context   context.Context
+	// Env is added to the environment of the command, e.g. GOPROXY=off
+	Env []string
 }
 
 // Cmd ...
@@ -36,12 +41,23 @@ func (c *Cmd) Build() error {
 		return errEmptyArgs
 	}
 
-	c.cmd = exec.Command(c.options.Name, c.options.Args...)
+	c.cmd = CommandContext(c.options.Context, c.options.Name, c.options.Args...)
 	c.cmd.Dir = c.options.Directory
+	if len(c.options.Env) > 0 {
+		c.cmd.Env = append(os.Environ(), c.options.Env...)
+	}
 
 	return nil
 }
 
+// CommandContext returns the command killed once ctx is done, e.g. on timeout. It is never killed when ctx is nil
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return exec.CommandContext(ctx, name, args...)
+}
+
 // Execute ...
 func (c *Cmd) Execute(w io.Writer) error {
 	c.cmd.Stdout = w
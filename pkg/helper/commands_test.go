@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandContext(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep is not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := CommandContext(ctx, "sleep", "10").Run()
+	assert.Error(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+
+	assert.NoError(t, CommandContext(nil, "sleep", "0").Run())
+}
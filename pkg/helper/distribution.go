@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// DistributionModule returns the root package of the operating system packages installed to a root
+// filesystem: the distribution its os-release identifies, named after the root filesystem when it has none
+func DistributionModule(root string) (*models.Module, reader.OSRelease, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, reader.OSRelease{}, err
+	}
+	release, err := reader.ReadOSRelease(absRoot)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, reader.OSRelease{}, err
+	}
+
+	mod := &models.Module{
+		Name:                    filepath.Base(absRoot),
+		Root:                    true,
+		LocalPath:               absRoot,
+		PackageDownloadLocation: "NOASSERTION",
+		PackageHomePage:         release.HomeURL,
+		PrimaryPackagePurpose:   models.PurposeOperatingSystem,
+		Modules:                 map[string]*models.Module{},
+	}
+	if release.ID != "" {
+		mod.Name = release.ID
+		mod.Version = release.VersionID
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	return mod, release, nil
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// GitMetadata is the provenance of a git working tree
+type GitMetadata struct {
+	// RemoteURL is the url of the origin remote, or of the first remote, without credentials
+	RemoteURL string
+	// Commit is the full id of the checked out commit
+	Commit string
+	// Tag is the first tag, in alphabetical order, pointing to the commit if any
+	Tag string
+}
+
+// DownloadLocation returns the SPDX download location of the commit, e.g. git+https://github.com/org/repo.git@<sha1>,
+// empty without remote
+func (g GitMetadata) DownloadLocation() string {
+	if g.RemoteURL == "" || g.Commit == "" {
+		return ""
+	}
+
+	remote := g.RemoteURL
+	if !strings.Contains(remote, "://") {
+		// a local path is no download location
+		if !strings.Contains(remote, ":") {
+			return ""
+		}
+		// scp-like urls, e.g. git@github.com:org/repo.git
+		parts := strings.SplitN(remote, ":", 2)
+		host := parts[0][strings.LastIndex(parts[0], "@")+1:]
+		remote = fmt.Sprintf("https://%s/%s", host, parts[1])
+	}
+
+	return fmt.Sprintf("git+%s@%s", remote, g.Commit)
+}
+
+// SourceInfo describes the commit, its tag and remote
+func (g GitMetadata) SourceInfo() string {
+	info := "built from git commit " + g.Commit
+	if g.Tag != "" {
+		info += fmt.Sprintf(" (tag %s)", g.Tag)
+	}
+	if g.RemoteURL != "" {
+		info += " of " + g.RemoteURL
+	}
+	return info
+}
+
+// ReadGitMetadata returns the metadata of the git repository path belongs to
+func ReadGitMetadata(path string) (GitMetadata, error) {
+	repository, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return GitMetadata{}, err
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return GitMetadata{}, err
+	}
+
+	metadata := GitMetadata{Commit: head.Hash().String()}
+	metadata.RemoteURL, err = remoteURL(repository)
+	if err != nil {
+		return GitMetadata{}, err
+	}
+	metadata.Tag, err = commitTag(repository, head.Hash())
+	if err != nil {
+		return GitMetadata{}, err
+	}
+
+	return metadata, nil
+}
+
+// remoteURL returns the first url of the origin remote, or of the first remote by name
+func remoteURL(repository *git.Repository) (string, error) {
+	remotes, err := repository.Remotes()
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(remotes, func(i, j int) bool {
+		if remotes[i].Config().Name == git.DefaultRemoteName {
+			return true
+		}
+		if remotes[j].Config().Name == git.DefaultRemoteName {
+			return false
+		}
+		return remotes[i].Config().Name < remotes[j].Config().Name
+	})
+
+	for _, remote := range remotes {
+		if urls := remote.Config().URLs; len(urls) > 0 {
+			return stripCredentials(urls[0]), nil
+		}
+	}
+	return "", nil
+}
+
+// stripCredentials removes the user and password of an url, scp-like urls are left as is
+func stripCredentials(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.User == nil || parsed.Scheme == "" {
+		return rawURL
+	}
+
+	parsed.User = nil
+	return parsed.String()
+}
+
+// commitTag returns the first tag, in alphabetical order, pointing to the commit, annotated tags are peeled
+func commitTag(repository *git.Repository, commit plumbing.Hash) (string, error) {
+	tags, err := repository.Tags()
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		target := ref.Hash()
+		if tag, err := repository.TagObject(target); err == nil {
+			target = tag.Target
+		}
+		if target == commit {
+			names = append(names, ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
@@ -65,9 +65,13 @@ func Exists(filepath string) bool {
 	return true
 }
 
-// GetLicenses ...
-func GetLicenses(modulePath string) (*models.License, error) {
+// GetLicenses detects the license of the module directory with the matcher, the SPDX license list when nil
+func GetLicenses(matcher LicenseMatcher, modulePath string) (*models.License, error) {
 	if modulePath != "" {
+		if !isSPDXLicenseMatcher(matcher) {
+			return matchLicenseFiles(matcher, modulePath)
+		}
+
 		licenses := licensedb.Analyse(modulePath)
 		for i := range licenses {
 			for j := range licenses[i].Matches {
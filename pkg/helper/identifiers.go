@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// identifierHeaderLines is how many lines of each file are looked at, the identifier is expected in the header
+const identifierHeaderLines = 20
+
+var (
+	identifierRegex = regexp.MustCompile(`SPDX-License-Identifier:\s*(.+)$`)
+	// expressionTokenRegex splits a license expression into its operators, parentheses and license ids
+	expressionTokenRegex = regexp.MustCompile(`[()]|[^\s()]+`)
+	// comment terminators that may follow the expression on the same line
+	identifierTrailers = []string{"*/", "-->", "*)", "#}", "%>"}
+	// directories holding third party or generated content
+	identifierSkipDirs = map[string]bool{
+		"node_modules": true,
+		"vendor":       true,
+		"target":       true,
+		"build":        true,
+		"dist":         true,
+		"testdata":     true,
+	}
+)
+
+// ScanLicenseIdentifiers walks the source tree at root and returns the sorted, unique
+// license expressions declared through `SPDX-License-Identifier:` comments
+func ScanLicenseIdentifiers(root string) ([]string, error) {
+	found := map[string]bool{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || identifierSkipDirs[info.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if expression := readLicenseIdentifier(path); expression != "" {
+			found[expression] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	identifiers := make([]string, 0, len(found))
+	for expression := range found {
+		identifiers = append(identifiers, expression)
+	}
+	sort.Strings(identifiers)
+
+	return identifiers, nil
+}
+
+// BuildLicenseExpression combines license expressions into one, compound expressions are parenthesized
+func BuildLicenseExpression(expressions []string) string {
+	if len(expressions) == 1 {
+		return expressions[0]
+	}
+
+	parts := make([]string, 0, len(expressions))
+	for _, expression := range expressions {
+		if strings.Contains(expression, " ") {
+			expression = "(" + expression + ")"
+		}
+		parts = append(parts, expression)
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+// LicenseAlternatives returns the expression of the licenses a package is available under, which are
+// alternatives. The ones that are not SPDX expressions become LicenseRefs
+func LicenseAlternatives(licenses []string) string {
+	var parts []string
+	for _, license := range licenses {
+		license = strings.TrimSpace(license)
+		if license == "" {
+			continue
+		}
+		expression := SPDXExpression(license)
+		if expression == "" {
+			expression = BuildLicenseDeclared(license)
+		}
+		if len(licenses) > 1 && strings.Contains(expression, " ") {
+			expression = "(" + expression + ")"
+		}
+		parts = append(parts, expression)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// SPDXExpression returns the license expression when all its ids are SPDX ones, e.g. MIT or
+// Apache-2.0 OR MIT, "" otherwise
+func SPDXExpression(license string) string {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return ""
+	}
+	tokens := expressionTokenRegex.FindAllString(license, -1)
+	for i, token := range tokens {
+		switch token {
+		case "(", ")", "AND", "OR", "WITH":
+			continue
+		}
+		// the exceptions following WITH are not licenses
+		if i > 0 && tokens[i-1] == "WITH" {
+			continue
+		}
+		if !LicenseSPDXExists(strings.TrimSuffix(token, "+")) {
+			return ""
+		}
+	}
+	return license
+}
+
+func readLicenseIdentifier(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 0; line < identifierHeaderLines && scanner.Scan(); line++ {
+		match := identifierRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		expression := strings.TrimSpace(match[1])
+		for _, trailer := range identifierTrailers {
+			expression = strings.TrimSpace(strings.TrimSuffix(expression, trailer))
+		}
+		return expression
+	}
+
+	return ""
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanLicenseIdentifiers(t *testing.T) {
+	identifiers, err := ScanLicenseIdentifiers(filepath.Join("testdata", "identifiers"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apache-2.0", "MIT", "MIT OR Apache-2.0"}, identifiers)
+	assert.Equal(t, "Apache-2.0 AND MIT AND (MIT OR Apache-2.0)", BuildLicenseExpression(identifiers))
+}
+
+func TestBuildLicenseExpressionSingle(t *testing.T) {
+	assert.Equal(t, "MIT OR Apache-2.0", BuildLicenseExpression([]string{"MIT OR Apache-2.0"}))
+}
+
+func TestSPDXExpression(t *testing.T) {
+	assert.Equal(t, "MIT", SPDXExpression("MIT"))
+	assert.Equal(t, "(MIT OR Apache-2.0) AND BSD-3-Clause", SPDXExpression("(MIT OR Apache-2.0) AND BSD-3-Clause"))
+	assert.Equal(t, "GPL-2.0-only WITH Classpath-exception-2.0", SPDXExpression("GPL-2.0-only WITH Classpath-exception-2.0"))
+	assert.Equal(t, "", SPDXExpression("Proprietary"))
+	assert.Equal(t, "", SPDXExpression(""))
+}
+
+func TestLicenseAlternatives(t *testing.T) {
+	assert.Equal(t, "MIT", LicenseAlternatives([]string{"MIT"}))
+	assert.Equal(t, "LGPL-2.1-only OR GPL-3.0-or-later", LicenseAlternatives([]string{"LGPL-2.1-only", "GPL-3.0-or-later"}))
+	assert.Equal(t, "(MIT OR Apache-2.0) OR BSD-3-Clause", LicenseAlternatives([]string{"MIT OR Apache-2.0", "BSD-3-Clause"}))
+	assert.Equal(t, "LicenseRef-proprietary", LicenseAlternatives([]string{"proprietary"}))
+	assert.Equal(t, "", LicenseAlternatives(nil))
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/go-enry/go-license-detector/v4/licensedb"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// licenseFileRegex matches the file names license texts are usually written to, e.g. LICENSE.md or COPYING
+var licenseFileRegex = regexp.MustCompile(`(?i)^(un)?licen[cs]e|^copying`)
+
+// LicenseMatcher identifies the license of a license text, see models.LicenseMatcher
+type LicenseMatcher = models.LicenseMatcher
+
+// SPDXLicenseMatcher is the built-in matcher of the SPDX license list
+type SPDXLicenseMatcher struct{}
+
+// Match returns the SPDX license the text is the closest to
+func (SPDXLicenseMatcher) Match(text string) (string, float64) {
+	var id string
+	var confidence float32
+	for license, score := range licensedb.InvestigateLicenseText([]byte(text)) {
+		if score > confidence || (score == confidence && license < id) {
+			id, confidence = license, score
+		}
+	}
+	return id, float64(confidence)
+}
+
+func isSPDXLicenseMatcher(matcher LicenseMatcher) bool {
+	if matcher == nil {
+		return true
+	}
+	_, ok := matcher.(SPDXLicenseMatcher)
+	return ok
+}
+
+// matchLicenseFiles returns the license the matcher is the most confident of among the license files of the directory
+func matchLicenseFiles(matcher LicenseMatcher, modulePath string) (*models.License, error) {
+	files, err := ioutil.ReadDir(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && licenseFileRegex.MatchString(file.Name()) {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var best *models.License
+	var bestConfidence float64
+	for _, name := range names {
+		content, err := ioutil.ReadFile(filepath.Join(modulePath, name))
+		if err != nil {
+			continue
+		}
+
+		id, confidence := matcher.Match(string(content))
+		if id == "" || confidence <= bestConfidence {
+			continue
+		}
+
+		best = &models.License{ID: id, Name: id, ExtractedText: string(content), File: name}
+		bestConfidence = confidence
+	}
+
+	if best == nil {
+		return nil, errors.New(fmt.Sprintf("could not detect license for %s\n", modulePath))
+	}
+	return best, nil
+}
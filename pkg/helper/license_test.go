@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubLicenseMatcher recognizes the ACME proprietary license only
+type stubLicenseMatcher struct{}
+
+func (stubLicenseMatcher) Match(text string) (string, float64) {
+	if strings.Contains(text, "ACME Corporation Proprietary License") {
+		return "ACME-Proprietary", 0.98
+	}
+	return "", 0
+}
+
+func TestGetLicensesWithLicenseMatcher(t *testing.T) {
+	license, err := GetLicenses(stubLicenseMatcher{}, filepath.Join("testdata", "proprietary"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ACME-Proprietary", license.ID)
+	assert.Equal(t, "LICENSE", license.File)
+	assert.Contains(t, license.ExtractedText, "Redistribution in source or binary form is not permitted.")
+
+	_, err = GetLicenses(stubLicenseMatcher{}, filepath.Join("testdata", "identifiers"))
+	assert.Error(t, err)
+}
+
+func TestSPDXLicenseMatcher(t *testing.T) {
+	text, err := ioutil.ReadFile(filepath.Join("testdata", "mit", "LICENSE"))
+	assert.NoError(t, err)
+
+	id, confidence := SPDXLicenseMatcher{}.Match(string(text))
+	assert.Equal(t, "MIT", id)
+	assert.True(t, confidence > 0.9)
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// LinkModule adds a copy of the module at index to the modules of the one at parentIndex with the relationship
+func LinkModule(modules []models.Module, parentIndex int, index int, relationship models.RelationshipType) {
+	if parentIndex == index {
+		return
+	}
+
+	linked := modules[index]
+	linked.Relationship = relationship
+	addModule(&modules[parentIndex], linked.Name, &linked)
+}
+
+// LinkModuleAs adds a copy of the module at index to the modules of the one at parentIndex, under the key
+func LinkModuleAs(modules []models.Module, parentIndex int, index int, key string) {
+	if parentIndex == index {
+		return
+	}
+
+	linked := modules[index]
+	addModule(&modules[parentIndex], key, &linked)
+}
+
+func addModule(parent *models.Module, key string, mod *models.Module) {
+	if parent.Modules == nil {
+		parent.Modules = map[string]*models.Module{}
+	}
+	parent.Modules[key] = mod
+}
+
+// SetLicense sets the license of the module to the one the matcher detects in the files of a directory,
+// it is left as is when none is detected
+func SetLicense(matcher LicenseMatcher, path string, mod *models.Module) {
+	license, err := GetLicenses(matcher, path)
+	if err != nil {
+		return
+	}
+	mod.LicenseDeclared = BuildLicenseDeclared(license.ID)
+	mod.LicenseConcluded = BuildLicenseConcluded(license.ID)
+	mod.CommentsLicense = license.Comments
+	if !LicenseSPDXExists(license.ID) {
+		mod.OtherLicense = append(mod.OtherLicense, license)
+	}
+}
+
+// SetLicenseExpression sets the declared and concluded license of the module to the expression, unless it is ""
+func SetLicenseExpression(mod *models.Module, expression string) {
+	if expression == "" {
+		return
+	}
+	mod.LicenseDeclared = expression
+	mod.LicenseConcluded = expression
+}
+
+// MaintainerSupplier returns the supplier of a package maintainer, e.g. Jane Doe <jane@example.com>
+func MaintainerSupplier(maintainer string) models.SupplierContact {
+	maintainer = strings.TrimSpace(maintainer)
+	if i := strings.Index(maintainer, "<"); i >= 0 && strings.HasSuffix(maintainer, ">") {
+		return models.SupplierContact{
+			Name:  strings.TrimSpace(maintainer[:i]),
+			Email: maintainer[i+1 : len(maintainer)-1],
+			Type:  models.Person,
+		}
+	}
+	return models.SupplierContact{Name: maintainer, Type: models.Person}
+}
+
+// FileSHA1 returns the hex SHA1 of the file
+func FileSHA1(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AppendUnique appends the value to the list unless it is already in it
+func AppendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestLinkModule(t *testing.T) {
+	modules := []models.Module{{Name: "root"}, {Name: "lib"}}
+
+	LinkModule(modules, 0, 1, models.RelationshipDependsOn)
+	LinkModule(modules, 1, 1, models.RelationshipDependsOn)
+
+	assert.Equal(t, models.RelationshipDependsOn, modules[0].Modules["lib"].Relationship)
+	assert.Empty(t, modules[1].Relationship)
+	assert.Empty(t, modules[1].Modules)
+}
+
+func TestMaintainerSupplier(t *testing.T) {
+	assert.Equal(t, models.SupplierContact{Name: "Jane Doe", Email: "jane@example.com", Type: models.Person},
+		MaintainerSupplier(" Jane Doe <jane@example.com> "))
+	assert.Equal(t, models.SupplierContact{Name: "Jane Doe", Type: models.Person}, MaintainerSupplier("Jane Doe"))
+}
+
+func TestAppendUnique(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, AppendUnique(AppendUnique([]string{"a"}, "b"), "a"))
+}
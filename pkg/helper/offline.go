@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOffline is returned, wrapped, by what needs the network while generating offline
+var ErrOffline = errors.New("network access is disabled by --offline")
+
+// OfflineError explains a package manager command failing offline with the hint to resolve it,
+// e.g. the command populating the local cache. The error is returned as is when online
+func OfflineError(offline bool, err error, hint string) error {
+	if err == nil || !offline {
+		return err
+	}
+	return fmt.Errorf("%w, %s: %v", ErrOffline, hint, err)
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflineError(t *testing.T) {
+	failed := errors.New("exit status 1")
+	assert.Equal(t, failed, OfflineError(false, failed, "run cargo fetch first"))
+	assert.Nil(t, OfflineError(true, nil, "run cargo fetch first"))
+
+	err := OfflineError(true, failed, "run cargo fetch first")
+	assert.True(t, errors.Is(err, ErrOffline))
+	assert.Equal(t, "network access is disabled by --offline, run cargo fetch first: exit status 1", err.Error())
+}
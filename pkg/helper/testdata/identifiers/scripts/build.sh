@@ -0,0 +1,5 @@
+#!/usr/bin/env bash
+
+# SPDX-License-Identifier: MIT
+
+echo build
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WorkspacePatterns returns the glob patterns of the workspaces field of a package.json, a list
+// or, as yarn also accepts, an object listing them in packages
+func WorkspacePatterns(manifest map[string]interface{}) []string {
+	field := manifest["workspaces"]
+	if object, ok := field.(map[string]interface{}); ok {
+		field = object["packages"]
+	}
+
+	list, _ := field.([]interface{})
+	var patterns []string
+	for _, item := range list {
+		if pattern, ok := item.(string); ok && pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// MatchWorkspace tells whether the directory, slash separated and relative to the project, matches
+// the workspace patterns, e.g. packages/* or packages/**. A pattern starting with ! excludes the directories it matches
+func MatchWorkspace(patterns []string, dir string) bool {
+	dir = cleanWorkspacePath(dir)
+	if dir == "." || strings.HasPrefix(dir, "../") {
+		return false
+	}
+
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = cleanWorkspacePath(strings.TrimPrefix(pattern, "!"))
+		if matchSegments(strings.Split(pattern, "/"), strings.Split(dir, "/")) {
+			matched = !negated
+		}
+	}
+	return matched
+}
+
+// FindWorkspaces returns the directories of the project at root matching the workspace patterns and holding
+// a package.json, slash separated and relative to root. node_modules and the hidden directories are not searched
+func FindWorkspaces(root string, patterns []string) ([]string, error) {
+	var workspaces []string
+	if len(patterns) == 0 {
+		return workspaces, nil
+	}
+
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || file == root {
+			return nil
+		}
+		if info.Name() == "node_modules" || strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		dir, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		dir = filepath.ToSlash(dir)
+		if MatchWorkspace(patterns, dir) && Exists(filepath.Join(file, "package.json")) {
+			workspaces = append(workspaces, dir)
+		}
+		return nil
+	})
+	return workspaces, err
+}
+
+// cleanWorkspacePath drops the ./ prefix and the trailing slash of a workspace directory or pattern
+func cleanWorkspacePath(p string) string {
+	return path.Clean(strings.TrimSuffix(p, "/"))
+}
+
+// matchSegments matches the path segments against the pattern ones, ** matches any number of segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
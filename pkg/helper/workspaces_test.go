@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspacePatterns(t *testing.T) {
+	assert.Equal(t, []string{"packages/*"}, WorkspacePatterns(map[string]interface{}{
+		"workspaces": []interface{}{"packages/*"},
+	}))
+	assert.Equal(t, []string{"apps/*", "libs/**"}, WorkspacePatterns(map[string]interface{}{
+		"workspaces": map[string]interface{}{"packages": []interface{}{"apps/*", "libs/**"}, "nohoist": []interface{}{"**/react"}},
+	}))
+	assert.Empty(t, WorkspacePatterns(map[string]interface{}{"name": "project"}))
+}
+
+func TestMatchWorkspace(t *testing.T) {
+	patterns := []string{"packages/*", "./libs/**", "tools/cli/", "!packages/internal"}
+
+	tests := []struct {
+		dir      string
+		expected bool
+	}{
+		{"packages/app", true},
+		{"packages/app/src", false},
+		{"packages/internal", false},
+		{"libs/a", true},
+		{"libs/a/b", true},
+		{"tools/cli", true},
+		{"tools", false},
+		{".", false},
+		{"../shared", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, MatchWorkspace(patterns, test.dir), test.dir)
+	}
+}
+
+func TestFindWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"packages/app", "packages/lib", "packages/docs", "node_modules/dep", "packages/app/node_modules/dep"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755))
+	}
+	for _, dir := range []string{"packages/app", "packages/lib", "node_modules/dep", "packages/app/node_modules/dep"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(root, filepath.FromSlash(dir), "package.json"), []byte("{}"), 0644))
+	}
+
+	workspaces, err := FindWorkspaces(root, []string{"packages/**"})
+	assert.NoError(t, err)
+	// packages/docs has no package.json
+	assert.Equal(t, []string{"packages/app", "packages/lib"}, workspaces)
+}
@@ -0,0 +1,484 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+var (
+	errUnknownImageLayout = errors.New("not a docker saved image nor an OCI image layout")
+	errNoImageManifest    = errors.New("the image has no manifest")
+	errUnsupportedLayer   = errors.New("unsupported image layer compression")
+	errPullOffline        = errors.New("the image is not a file and cannot be pulled offline")
+	errNoDockerCommand    = errors.New("no docker command to save the image")
+	errInvalidDigest      = errors.New("invalid image blob digest")
+	errInvalidBlobPath    = errors.New("image blob path outside of the image")
+)
+
+// digestRegex matches the blob digests of an image, e.g. sha256:<hex>
+var digestRegex = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]+$`)
+
+// Layer is a filesystem layer of the image
+type Layer struct {
+	// Digest is the digest of the layer as the image lists it, e.g. sha256:...
+	Digest string
+	// CreatedBy is the instruction the layer was built by, when the image history tells
+	CreatedBy string
+}
+
+// Image is a container image extracted to a temporary root filesystem
+type Image struct {
+	// Reference is the image as given, a reference or the path of a saved image
+	Reference string
+	// Digest is the digest of the image configuration, the image id
+	Digest string
+	// RootFS is the directory the layers were merged to
+	RootFS string
+	// WorkingDir is the working directory of the image, relative to RootFS
+	WorkingDir string
+	// Layers are the layers of the image, the base layer first
+	Layers []Layer
+
+	dir   string
+	files map[string]int
+}
+
+// saveImage writes the image reference to a docker saved image at file, pulling it when missing locally.
+// docker is killed once ctx is done. Tests replace it
+var saveImage = func(ctx context.Context, reference, file string) error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return errNoDockerCommand
+	}
+
+	if err := exec.CommandContext(ctx, "docker", "save", "-o", file, reference).Run(); err == nil {
+		return nil
+	}
+	log.Infof("Pulling the image %s", reference)
+	if output, err := exec.CommandContext(ctx, "docker", "pull", reference).CombinedOutput(); err != nil {
+		return fmt.Errorf("docker pull %s: %v: %s", reference, err, strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.CommandContext(ctx, "docker", "save", "-o", file, reference).CombinedOutput(); err != nil {
+		return fmt.Errorf("docker save %s: %v: %s", reference, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Open extracts the image to a temporary root filesystem. The image is a docker saved image (docker save),
+// an OCI image layout, as a directory or a tar, or else a reference docker saves, and pulls unless offline.
+// The pull and save are aborted once ctx is done. Close removes the root filesystem
+func Open(ctx context.Context, reference string, offline bool) (*Image, error) {
+	dir, err := ioutil.TempDir("", "spdx-sbom-generator-image-")
+	if err != nil {
+		return nil, err
+	}
+	img := &Image{Reference: reference, RootFS: filepath.Join(dir, "rootfs"), dir: dir, files: map[string]int{}}
+
+	if err := img.open(ctx, reference, offline); err != nil {
+		img.Close()
+		return nil, err
+	}
+	return img, nil
+}
+
+func (img *Image) open(ctx context.Context, reference string, offline bool) error {
+	source := reference
+	if _, err := os.Stat(reference); err != nil {
+		if offline {
+			return fmt.Errorf("%w: %s", errPullOffline, reference)
+		}
+		source = filepath.Join(img.dir, "image.tar")
+		if err := saveImage(ctx, reference, source); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		archive := filepath.Join(img.dir, "archive")
+		if err := unpackArchive(source, archive); err != nil {
+			return err
+		}
+		source = archive
+	}
+
+	config, layers, err := readManifest(source)
+	if err != nil {
+		return err
+	}
+	if err := img.readConfig(config); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(img.RootFS, 0755); err != nil {
+		return err
+	}
+	for i, layer := range layers {
+		if i >= len(img.Layers) {
+			img.Layers = append(img.Layers, Layer{})
+		}
+		if img.Layers[i].Digest == "" {
+			img.Layers[i].Digest = layer.digest
+		}
+		if err := img.applyLayer(i, layer.path); err != nil {
+			return fmt.Errorf("layer %s: %w", img.Layers[i].Digest, err)
+		}
+	}
+	img.Layers = img.Layers[:len(layers)]
+	return nil
+}
+
+// Close removes the root filesystem of the image
+func (img *Image) Close() error {
+	if img == nil || img.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(img.dir)
+}
+
+// ProjectPath is the working directory of the image in the root filesystem, where the project the image
+// was built from usually is, the root filesystem when the image has none
+func (img *Image) ProjectPath() string {
+	if img.WorkingDir != "" {
+		dir := filepath.Join(img.RootFS, filepath.FromSlash(img.WorkingDir))
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return img.RootFS
+}
+
+// LayerOf returns the layer the file or directory at filePath, in the root filesystem, comes from
+func (img *Image) LayerOf(filePath string) (Layer, bool) {
+	rel, err := filepath.Rel(img.RootFS, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return Layer{}, false
+	}
+	index, ok := img.files[filepath.ToSlash(rel)]
+	if !ok {
+		return Layer{}, false
+	}
+	return img.Layers[index], true
+}
+
+// Annotate records the layer each module comes from as its source info, and the image on the root
+// module. The modules in the root filesystem are the ones traced back to a layer
+func (img *Image) Annotate(modules []models.Module) []models.Module {
+	annotated := make([]models.Module, len(modules))
+	for i, module := range modules {
+		if layer, ok := img.moduleLayer(module); ok {
+			info := fmt.Sprintf("installed by the image layer %s", layer.Digest)
+			if module.SourceInfo != "" {
+				info = fmt.Sprintf("%s, %s", module.SourceInfo, info)
+			}
+			module.SourceInfo = info
+		}
+		annotated[i] = module
+	}
+	if len(annotated) > 0 {
+		root := &annotated[0]
+		root.Annotations = append(append([]string{}, root.Annotations...), img.description())
+	}
+	return annotated
+}
+
+func (img *Image) moduleLayer(module models.Module) (Layer, bool) {
+	if module.LocalPath == "" {
+		return Layer{}, false
+	}
+	localPath, err := filepath.Abs(module.LocalPath)
+	if err != nil {
+		return Layer{}, false
+	}
+	return img.LayerOf(localPath)
+}
+
+// description tells the image the packages were read from and its layers
+func (img *Image) description() string {
+	digests := make([]string, 0, len(img.Layers))
+	for _, layer := range img.Layers {
+		digests = append(digests, layer.Digest)
+	}
+	return fmt.Sprintf("read from the image %s (%s), layers: %s", img.Reference, img.Digest, strings.Join(digests, " "))
+}
+
+// imageLayer is a layer archive of the image, digest as the manifest lists it
+type imageLayer struct {
+	path   string
+	digest string
+}
+
+// dockerManifest is an entry of the manifest.json of a docker saved image
+type dockerManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+// ociDescriptor points to a blob of an OCI image layout
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+// ociManifest is an OCI image manifest or index
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// imageConfig is the part of the image configuration read
+type imageConfig struct {
+	Config struct {
+		WorkingDir string `json:"WorkingDir"`
+	} `json:"config"`
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+	History []struct {
+		CreatedBy  string `json:"created_by"`
+		EmptyLayer bool   `json:"empty_layer"`
+	} `json:"history"`
+}
+
+// readManifest returns the configuration file and the layers of the image in dir, docker saved images
+// listing them in manifest.json, OCI image layouts in index.json
+func readManifest(dir string) (string, []imageLayer, error) {
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json")); err == nil {
+		var manifests []dockerManifest
+		if err := json.Unmarshal(data, &manifests); err != nil {
+			return "", nil, fmt.Errorf("manifest.json: %w", err)
+		}
+		if len(manifests) == 0 {
+			return "", nil, errNoImageManifest
+		}
+		var layers []imageLayer
+		for _, layer := range manifests[0].Layers {
+			file, err := archivePath(dir, layer)
+			if err != nil {
+				return "", nil, err
+			}
+			layers = append(layers, imageLayer{path: file, digest: blobDigest(layer)})
+		}
+		config, err := archivePath(dir, manifests[0].Config)
+		if err != nil {
+			return "", nil, err
+		}
+		return config, layers, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return "", nil, errUnknownImageLayout
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", nil, fmt.Errorf("index.json: %w", err)
+	}
+	for len(manifest.Manifests) > 0 {
+		descriptor := selectManifest(manifest.Manifests)
+		file, err := blobPath(dir, descriptor.Digest)
+		if err != nil {
+			return "", nil, err
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", nil, err
+		}
+		manifest = ociManifest{}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return "", nil, fmt.Errorf("manifest %s: %w", descriptor.Digest, err)
+		}
+	}
+	if manifest.Config.Digest == "" {
+		return "", nil, errNoImageManifest
+	}
+
+	var layers []imageLayer
+	for _, layer := range manifest.Layers {
+		file, err := blobPath(dir, layer.Digest)
+		if err != nil {
+			return "", nil, err
+		}
+		layers = append(layers, imageLayer{path: file, digest: layer.Digest})
+	}
+	config, err := blobPath(dir, manifest.Config.Digest)
+	if err != nil {
+		return "", nil, err
+	}
+	return config, layers, nil
+}
+
+// selectManifest picks the manifest of the platform the generator runs on, linux, the first one otherwise
+func selectManifest(manifests []ociDescriptor) ociDescriptor {
+	for _, descriptor := range manifests {
+		if descriptor.Platform != nil && descriptor.Platform.OS == "linux" && descriptor.Platform.Architecture == runtime.GOARCH {
+			return descriptor
+		}
+	}
+	return manifests[0]
+}
+
+// blobPath is the path of the blob of digest in the OCI image layout dir
+func blobPath(dir, digest string) (string, error) {
+	if !digestRegex.MatchString(digest) {
+		return "", fmt.Errorf("%w: %q", errInvalidDigest, digest)
+	}
+	i := strings.Index(digest, ":")
+	return filepath.Join(dir, "blobs", digest[:i], digest[i+1:]), nil
+}
+
+// archivePath is the path of the file name a docker saved image manifest lists, which must be in dir
+func archivePath(dir, name string) (string, error) {
+	clean := path.Clean(strings.Replace(name, "\\", "/", -1))
+	if name == "" || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: %q", errInvalidBlobPath, name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+// blobDigest returns the digest of a docker saved image blob named after it, e.g. blobs/sha256/<hash>,
+// empty for the older <id>/layer.tar layout
+func blobDigest(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) == 3 && parts[0] == "blobs" && digestRegex.MatchString(parts[1]+":"+parts[2]) {
+		return parts[1] + ":" + parts[2]
+	}
+	return ""
+}
+
+// readConfig reads the image configuration, the layers are listed by their uncompressed digest
+func (img *Image) readConfig(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var config imageConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("image configuration: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	img.Digest = "sha256:" + hex.EncodeToString(sum[:])
+	img.WorkingDir = strings.TrimPrefix(path.Clean("/"+config.Config.WorkingDir), "/")
+
+	var history []string
+	for _, entry := range config.History {
+		if !entry.EmptyLayer {
+			history = append(history, entry.CreatedBy)
+		}
+	}
+	for i, digest := range config.RootFS.DiffIDs {
+		layer := Layer{Digest: digest}
+		if i < len(history) {
+			layer.CreatedBy = history[i]
+		}
+		img.Layers = append(img.Layers, layer)
+	}
+	return nil
+}
+
+// layerReader opens the layer archive at file, uncompressing gzip layers
+func layerReader(file string) (io.ReadCloser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, 4)
+	n, _ := io.ReadFull(f, magic)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return readCloser{Reader: gz, closers: []io.Closer{gz, f}}, nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		f.Close()
+		return nil, fmt.Errorf("%w: zstd", errUnsupportedLayer)
+	}
+	return f, nil
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r readCloser) Close() error {
+	var err error
+	for _, closer := range r.closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// unpackArchive extracts the regular files of the saved image archive to dir
+func unpackArchive(file, dir string) error {
+	r, err := layerReader(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		name, ok := cleanName(header.Name)
+		if !ok || header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := writeFile(target, tr, 0644); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// entry is a file of a test archive, a directory when its name ends with /
+type entry struct {
+	name     string
+	content  string
+	linkname string
+	typeflag byte
+}
+
+func archive(t *testing.T, entries ...entry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.content)), Typeflag: e.typeflag, Linkname: e.linkname}
+		if header.Typeflag == 0 {
+			header.Typeflag = tar.TypeReg
+			if e.name[len(e.name)-1] == '/' {
+				header.Typeflag, header.Mode = tar.TypeDir, 0755
+			}
+		}
+		if header.Typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		assert.NoError(t, tw.WriteHeader(header))
+		_, err := tw.Write([]byte(e.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func compress(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func marshal(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return data
+}
+
+// testLayers are a base layer, a layer installing the project and one removing what the base left
+func testLayers(t *testing.T) [][]byte {
+	return [][]byte{
+		archive(t,
+			entry{name: "etc/"},
+			entry{name: "etc/os-release", content: "ID=alpine\nVERSION_ID=3.18.4\n"},
+			entry{name: "tmp/"},
+			entry{name: "tmp/cache/"},
+			entry{name: "tmp/cache/a", content: "a"},
+			entry{name: "var/"},
+			entry{name: "var/run", typeflag: tar.TypeSymlink, linkname: "/"},
+			entry{name: "etc/release", typeflag: tar.TypeSymlink, linkname: "/etc/os-release"},
+			entry{name: "etc/passwd", typeflag: tar.TypeSymlink, linkname: "../../../../etc/passwd"},
+		),
+		archive(t,
+			entry{name: "app/"},
+			entry{name: "app/package.json", content: `{"name":"web"}`},
+			entry{name: "app/index.js", typeflag: tar.TypeLink, linkname: "app/package.json"},
+			entry{name: "../escape", content: "outside"},
+			entry{name: "var/run/escape", content: "through the link"},
+		),
+		archive(t,
+			entry{name: "tmp/cache/.wh..wh..opq"},
+			entry{name: "tmp/cache/b", content: "b"},
+			entry{name: "etc/.wh.os-release"},
+		),
+	}
+}
+
+func testConfig(t *testing.T, layers [][]byte) []byte {
+	var diffIDs []string
+	for _, layer := range layers {
+		diffIDs = append(diffIDs, digest(layer))
+	}
+	return marshal(t, map[string]interface{}{
+		"config": map[string]interface{}{"WorkingDir": "/app"},
+		"rootfs": map[string]interface{}{"type": "layers", "diff_ids": diffIDs},
+		"history": []map[string]interface{}{
+			{"created_by": "ADD rootfs.tar /"},
+			{"created_by": "ENV NODE_ENV=production", "empty_layer": true},
+			{"created_by": "COPY . /app"},
+			{"created_by": "RUN rm -rf /tmp/cache/* /etc/os-release"},
+		},
+	})
+}
+
+// dockerArchive is the archive docker save writes for the layers
+func dockerArchive(t *testing.T, layers [][]byte) []byte {
+	config := testConfig(t, layers)
+	entries := []entry{{name: "config.json", content: string(config)}}
+	var names []string
+	for i, layer := range layers {
+		name := string(rune('a'+i)) + "/layer.tar"
+		names = append(names, name)
+		entries = append(entries, entry{name: name, content: string(layer)})
+	}
+	manifest := marshal(t, []map[string]interface{}{{"Config": "config.json", "RepoTags": []string{"web:1.0"}, "Layers": names}})
+	return archive(t, append(entries, entry{name: "manifest.json", content: string(manifest)})...)
+}
+
+// ociLayout writes an OCI image layout of the gzipped layers to dir, behind an index
+func ociLayout(t *testing.T, dir string, layers [][]byte) {
+	write := func(data []byte) string {
+		d := digest(data)
+		file, err := blobPath(dir, d)
+		assert.NoError(t, err)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, ioutil.WriteFile(file, data, 0644))
+		return d
+	}
+
+	config := testConfig(t, layers)
+	var descriptors []map[string]interface{}
+	for _, layer := range layers {
+		descriptors = append(descriptors, map[string]interface{}{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": write(compress(t, layer))})
+	}
+	manifest := write(marshal(t, map[string]interface{}{
+		"mediaType": "application/vnd.oci.image.manifest.v1+json",
+		"config":    map[string]interface{}{"digest": write(config)},
+		"layers":    descriptors,
+	}))
+	index := write(marshal(t, map[string]interface{}{
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"manifests": []map[string]interface{}{{"digest": manifest, "platform": map[string]string{"os": "linux", "architecture": "amd64"}}},
+	}))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.json"), marshal(t, map[string]interface{}{
+		"manifests": []map[string]interface{}{{"mediaType": "application/vnd.oci.image.index.v1+json", "digest": index}},
+	}), 0644))
+}
+
+func assertMerged(t *testing.T, img *Image, layers [][]byte) {
+	assert.Equal(t, digest(testConfig(t, layers)), img.Digest)
+	assert.Equal(t, "app", img.WorkingDir)
+	assert.Equal(t, filepath.Join(img.RootFS, "app"), img.ProjectPath())
+	assert.Equal(t, []Layer{
+		{Digest: digest(layers[0]), CreatedBy: "ADD rootfs.tar /"},
+		{Digest: digest(layers[1]), CreatedBy: "COPY . /app"},
+		{Digest: digest(layers[2]), CreatedBy: "RUN rm -rf /tmp/cache/* /etc/os-release"},
+	}, img.Layers)
+
+	content, err := ioutil.ReadFile(filepath.Join(img.RootFS, "app", "index.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"web"}`, string(content))
+	assert.FileExists(t, filepath.Join(img.RootFS, "tmp", "cache", "b"))
+	assert.NoFileExists(t, filepath.Join(img.RootFS, "tmp", "cache", "a"))
+	assert.NoFileExists(t, filepath.Join(img.RootFS, "etc", "os-release"))
+	// the entries leaving the root are kept in it, the ones under a symbolic link skipped
+	assert.NoFileExists(t, filepath.Join(img.dir, "escape"))
+	assert.FileExists(t, filepath.Join(img.RootFS, "escape"))
+	_, ok := img.files["var/run/escape"]
+	assert.False(t, ok)
+	// the symbolic links point inside the root filesystem, whatever their target
+	for link, target := range map[string]string{"var/run": "..", "etc/release": "os-release", "etc/passwd": "passwd"} {
+		linkname, err := os.Readlink(filepath.Join(img.RootFS, filepath.FromSlash(link)))
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.FromSlash(target), linkname, link)
+	}
+
+	layer, ok := img.LayerOf(filepath.Join(img.RootFS, "app", "package.json"))
+	assert.True(t, ok)
+	assert.Equal(t, digest(layers[1]), layer.Digest)
+	_, ok = img.LayerOf(filepath.Join(img.RootFS, "tmp", "cache", "a"))
+	assert.False(t, ok)
+	_, ok = img.LayerOf(filepath.Join(img.RootFS, "etc", "os-release"))
+	assert.False(t, ok)
+}
+
+func TestOpenDockerArchive(t *testing.T) {
+	layers := testLayers(t)
+	file := filepath.Join(t.TempDir(), "web.tar")
+	assert.NoError(t, ioutil.WriteFile(file, dockerArchive(t, layers), 0644))
+
+	img, err := Open(context.Background(), file, true)
+	assert.NoError(t, err)
+	assertMerged(t, img, layers)
+
+	assert.NoError(t, img.Close())
+	assert.NoDirExists(t, img.RootFS)
+}
+
+func TestOpenOCILayout(t *testing.T) {
+	layers := testLayers(t)
+	dir := t.TempDir()
+	ociLayout(t, dir, layers)
+
+	img, err := Open(context.Background(), dir, true)
+	assert.NoError(t, err)
+	defer img.Close()
+	assertMerged(t, img, layers)
+}
+
+func TestOpenPullsReference(t *testing.T) {
+	layers := testLayers(t)
+	defer func(save func(context.Context, string, string) error) { saveImage = save }(saveImage)
+	saveImage = func(ctx context.Context, reference, file string) error {
+		assert.Equal(t, "registry.example.com/web:1.0", reference)
+		return ioutil.WriteFile(file, dockerArchive(t, layers), 0644)
+	}
+
+	img, err := Open(context.Background(), "registry.example.com/web:1.0", false)
+	assert.NoError(t, err)
+	defer img.Close()
+	assertMerged(t, img, layers)
+
+	_, err = Open(context.Background(), "registry.example.com/web:1.0", true)
+	assert.True(t, errors.Is(err, errPullOffline))
+}
+
+func TestOpenUnknownLayout(t *testing.T) {
+	_, err := Open(context.Background(), t.TempDir(), true)
+	assert.True(t, errors.Is(err, errUnknownImageLayout))
+}
+
+func TestOpenRejectsPathsOutsideImage(t *testing.T) {
+	layers := testLayers(t)
+	for _, name := range []string{"../layer.tar", "/etc/passwd"} {
+		manifest := marshal(t, []map[string]interface{}{{"Config": "config.json", "Layers": []string{name}}})
+		file := filepath.Join(t.TempDir(), "web.tar")
+		assert.NoError(t, ioutil.WriteFile(file, archive(t,
+			entry{name: "config.json", content: string(testConfig(t, layers))},
+			entry{name: "manifest.json", content: string(manifest)},
+		), 0644))
+
+		_, err := Open(context.Background(), file, true)
+		assert.True(t, errors.Is(err, errInvalidBlobPath), name)
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.json"), marshal(t, map[string]interface{}{
+		"manifests": []map[string]interface{}{{"digest": "sha256:../../../etc/passwd"}},
+	}), 0644))
+	_, err := Open(context.Background(), dir, true)
+	assert.True(t, errors.Is(err, errInvalidDigest))
+}
+
+func TestAnnotate(t *testing.T) {
+	layers := testLayers(t)
+	file := filepath.Join(t.TempDir(), "web.tar")
+	assert.NoError(t, ioutil.WriteFile(file, dockerArchive(t, layers), 0644))
+	img, err := Open(context.Background(), file, true)
+	assert.NoError(t, err)
+	defer img.Close()
+
+	modules := []models.Module{
+		{Name: "web", LocalPath: img.ProjectPath()},
+		{Name: "web-dep", LocalPath: filepath.Join(img.RootFS, "app", "package.json"), SourceInfo: "built from web"},
+		{Name: "musl"},
+	}
+	annotated := img.Annotate(modules)
+
+	assert.Equal(t, []string{"read from the image " + file + " (" + img.Digest + "), layers: " +
+		digest(layers[0]) + " " + digest(layers[1]) + " " + digest(layers[2])}, annotated[0].Annotations)
+	assert.Equal(t, "installed by the image layer "+digest(layers[1]), annotated[0].SourceInfo)
+	assert.Equal(t, "built from web, installed by the image layer "+digest(layers[1]), annotated[1].SourceInfo)
+	assert.Equal(t, "", annotated[2].SourceInfo)
+	assert.Empty(t, modules[0].Annotations)
+	// the image is a note, not a resolution issue raising a warning
+	assert.Empty(t, annotated[0].Unresolved)
+}
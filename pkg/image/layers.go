@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	// whiteoutOpaque hides everything the lower layers put in its directory
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// applyLayer extracts the layer archive over the root filesystem, its whiteouts remove what the lower
+// layers put there. Only directories, regular files and links are extracted, never through a symbolic link
+// of the image, and the symbolic links are rewritten to point inside the root filesystem
+func (img *Image) applyLayer(index int, file string) error {
+	r, err := layerReader(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name, ok := cleanName(header.Name)
+		if !ok {
+			continue
+		}
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+
+		if base == whiteoutOpaque {
+			if err := img.whiteout(index, dir, true); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			if err := img.whiteout(index, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), false); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !img.safeParent(name) {
+			log.Debugf("Skipping %s of the layer %s, a parent is not a directory", name, file)
+			continue
+		}
+		target := img.target(name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+			}
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := removeFile(target); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr, os.FileMode(header.Mode)&0755|0600); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := removeFile(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(rootedLink(name, header.Linkname), target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			// hard links are copied, the file they point to may be replaced by a later layer
+			source, ok := cleanName(header.Linkname)
+			if !ok || !img.safeParent(source) {
+				continue
+			}
+			if err := removeFile(target); err != nil {
+				return err
+			}
+			if err := copyFile(img.target(source), target); err != nil {
+				log.Debugf("Skipping the hard link %s of the layer %s: %v", name, file, err)
+				continue
+			}
+		default:
+			continue
+		}
+		img.files[name] = index
+	}
+}
+
+// whiteout removes name from the root filesystem, or only its content when opaque. What the layer index
+// itself put there stays, the archive may list it before the whiteout
+func (img *Image) whiteout(index int, name string, opaque bool) error {
+	if name != "" && !img.safeParent(name) {
+		return nil
+	}
+	target := img.target(name)
+
+	if opaque {
+		entries, err := ioutil.ReadDir(target)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, entry := range entries {
+			if layer, ok := img.files[path.Join(name, entry.Name())]; ok && layer == index {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(target, entry.Name())); err != nil {
+				return err
+			}
+		}
+	} else if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+
+	for file, layer := range img.files {
+		if layer == index {
+			continue
+		}
+		if (!opaque && file == name) || name == "" || strings.HasPrefix(file, name+"/") {
+			delete(img.files, file)
+		}
+	}
+	return nil
+}
+
+// target is the path of the archive entry name in the root filesystem
+func (img *Image) target(name string) string {
+	return filepath.Join(img.RootFS, filepath.FromSlash(name))
+}
+
+// safeParent tells whether every parent directory of name in the root filesystem is a real directory,
+// creating the missing ones
+func (img *Image) safeParent(name string) bool {
+	parent := img.RootFS
+	parts := strings.Split(name, "/")
+	for _, part := range parts[:len(parts)-1] {
+		parent = filepath.Join(parent, part)
+		info, err := os.Lstat(parent)
+		if os.IsNotExist(err) {
+			if err := os.Mkdir(parent, 0755); err != nil {
+				return false
+			}
+			continue
+		}
+		if err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanName returns the slash separated path of the archive entry relative to the root, false when it
+// is the root or leaves it
+func cleanName(name string) (string, bool) {
+	name = path.Clean("/" + strings.Replace(name, "\\", "/", -1))
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || name == "." {
+		return "", false
+	}
+	return name, true
+}
+
+// rootedLink returns the target of the symbolic link name relative to its directory, resolved as if the
+// root filesystem were the root: absolute targets and the ones climbing above the root stay in it
+func rootedLink(name, linkname string) string {
+	dir := path.Dir("/" + name)
+	linkname = strings.Replace(linkname, "\\", "/", -1)
+	target := path.Join(dir, linkname)
+	if path.IsAbs(linkname) {
+		target = path.Clean(linkname)
+	}
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(target))
+	if err != nil {
+		return "."
+	}
+	return rel
+}
+
+// removeFile removes the file or link at target, a directory is replaced with everything it contains
+func removeFile(target string) error {
+	if err := os.RemoveAll(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", target, err)
+	}
+	return f.Close()
+}
+
+// copyFile copies the regular file source to target, without following a symbolic link
+func copyFile(source, target string) error {
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", source)
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeFile(target, f, info.Mode().Perm())
+}
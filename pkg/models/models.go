@@ -3,6 +3,7 @@
 package models
 
 import (
+	"context"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
@@ -10,6 +11,8 @@ import (
 	"fmt"
 	"hash"
 	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
 )
 
 // IPlugin ...
@@ -24,6 +27,63 @@ type IPlugin interface {
 	HasModulesInstalled(path string) error
 }
 
+// IConfigurablePlugin is implemented by plugins that accept generation wide options
+type IConfigurablePlugin interface {
+	SetOptions(opts PluginOptions)
+}
+
+// IExecutablePlugin is implemented by plugins whose package manager binary depends on the project,
+// e.g. a wrapper script the project pins its build tool version with
+type IExecutablePlugin interface {
+	GetExecutable() string
+}
+
+// PluginOptions ...
+type PluginOptions struct {
+	// Context is cancelled when the generation is aborted, e.g. on timeout
+	Context context.Context
+	// IncludeBuildTools adds the build plugins of the project, e.g. maven plugins, with a BUILD_TOOL_OF relationship
+	IncludeBuildTools bool
+	// AllowNetwork lets plugins query remote repositories to enrich the modules
+	AllowNetwork bool
+	// Offline resolves the modules from the local caches of the package manager only
+	Offline bool
+	// Cache keeps what was resolved for the packages across runs, nil when not resuming
+	Cache *cache.Cache
+	// VersionLockFile pins the dependency versions, e.g. a versions.properties or gradle.lockfile
+	VersionLockFile string
+	// PackagingExtensions maps custom packagings to their artifact file extension, e.g. bundle=jar
+	PackagingExtensions map[string]string
+	// MavenProfiles are the maven profile ids to activate, a !id deactivates the profile
+	MavenProfiles []string
+	// MavenEffectivePom reads the poms computed by mvn help:effective-pom rather than the pom.xml files
+	MavenEffectivePom bool
+	// IncludeScopes are the only dependency scopes listed when not empty, e.g. compile and runtime
+	IncludeScopes []string
+	// ExcludeScopes are the dependency scopes never listed, e.g. test
+	ExcludeScopes []string
+	// MavenSettings is the settings.xml of the maven mirrors, proxies and credentials, the user one when empty
+	MavenSettings string
+	// MavenParallelism is the number of maven dependencies hashed and whose licenses are looked up at once,
+	// as many as CPUs when 0
+	MavenParallelism int
+	// GoVendor lists the go modules of vendor/modules.txt, without the go command nor the module cache
+	GoVendor bool
+	// LockfileOnly lists the modules of the manifests and lockfiles only, the package manager binaries are not run
+	LockfileOnly bool
+	// RootFS is the root filesystem the installed operating system packages are read from, e.g. a mounted
+	// image, the project path when empty
+	RootFS string
+	// LicenseMatcher identifies the license files of the modules, the SPDX license list when nil
+	LicenseMatcher LicenseMatcher
+}
+
+// LicenseMatcher identifies the license of a license text, the confidence ranges from 0 to 1.
+// An empty id means the text matches no license
+type LicenseMatcher interface {
+	Match(text string) (id string, confidence float64)
+}
+
 // PluginMetadata ...
 type PluginMetadata struct {
 	Name       string
@@ -51,6 +111,97 @@ type Module struct {
 	PackageComment          string
 	Root                    bool
 	Modules                 map[string]*Module
+	// Relationship describes how the module relates to the module depending on it,
+	// an empty value means DEPENDS_ON
+	Relationship RelationshipType
+	// PrimaryPackagePurpose is the type of the package, only emitted from SPDX 2.3
+	PrimaryPackagePurpose PackagePurpose
+	// Annotations are notes on how the module was resolved, e.g. it is built from a local directory
+	Annotations []string
+	// Unresolved are notes on what could not be resolved for the module, e.g. its dependencies are
+	// incomplete, they are annotated like Annotations and also raised as warnings
+	Unresolved []string
+	// AttributionTexts are the notices the package asks to be reproduced, e.g. its NOTICE file
+	AttributionTexts []string
+	// Packaging is the artifact type of the package, e.g. jar, war or test-jar
+	Packaging string
+	// Classifier tells the artifact apart from the main one of the package, e.g. tests or linux-x86_64
+	Classifier string
+	// SourceInfo tells where the sources of the package come from, e.g. its scm repository
+	SourceInfo string
+}
+
+// Key identifies the module and the sub modules referencing it
+func (m Module) Key() string {
+	return m.Name + "@" + m.Version
+}
+
+// PackagePurpose is the SPDX 2.3 primary package purpose
+type PackagePurpose string
+
+const (
+	PurposeApplication PackagePurpose = "APPLICATION"
+	PurposeFramework   PackagePurpose = "FRAMEWORK"
+	PurposeLibrary     PackagePurpose = "LIBRARY"
+	PurposeContainer   PackagePurpose = "CONTAINER"
+	PurposeSource      PackagePurpose = "SOURCE"
+	PurposeArchive     PackagePurpose = "ARCHIVE"
+	PurposeOther       PackagePurpose = "OTHER"
+	// PurposeOperatingSystem is the distribution the installed operating system packages are part of
+	PurposeOperatingSystem PackagePurpose = "OPERATING-SYSTEM"
+)
+
+// RelationshipType ...
+type RelationshipType string
+
+const (
+	RelationshipDependsOn       RelationshipType = "DEPENDS_ON"
+	RelationshipDevDependencyOf RelationshipType = "DEV_DEPENDENCY_OF"
+	RelationshipBuildToolOf     RelationshipType = "BUILD_TOOL_OF"
+	RelationshipContains        RelationshipType = "CONTAINS"
+	// RelationshipProvidedDependencyOf is a dependency the runtime environment provides, e.g. the maven provided scope
+	RelationshipProvidedDependencyOf RelationshipType = "PROVIDED_DEPENDENCY_OF"
+	// RelationshipRuntimeDependencyOf is a dependency only needed at runtime, e.g. the maven runtime scope
+	RelationshipRuntimeDependencyOf RelationshipType = "RUNTIME_DEPENDENCY_OF"
+	// RelationshipBuildDependencyOf is a dependency only needed to build, e.g. the cargo build-dependencies
+	RelationshipBuildDependencyOf RelationshipType = "BUILD_DEPENDENCY_OF"
+	// RelationshipOptionalDependencyOf is a dependency enabled by a feature or an extra, e.g. an optional cargo dependency
+	RelationshipOptionalDependencyOf RelationshipType = "OPTIONAL_DEPENDENCY_OF"
+)
+
+// devScopes are the dependency scopes only needed to develop or test the dependent package,
+// e.g. the maven test scope or the npm devDependencies
+var devScopes = map[string]bool{
+	"test":            true,
+	"dev":             true,
+	"development":     true,
+	"devDependencies": true,
+}
+
+// ScopeRelationship returns the relationship of a dependency declared with the scope,
+// DEV_DEPENDENCY_OF for development and test scopes, PROVIDED_DEPENDENCY_OF and RUNTIME_DEPENDENCY_OF
+// for the provided and runtime scopes, DEPENDS_ON otherwise
+func ScopeRelationship(scope string) RelationshipType {
+	scope = strings.TrimSpace(scope)
+	switch {
+	case devScopes[scope]:
+		return RelationshipDevDependencyOf
+	case scope == "provided":
+		return RelationshipProvidedDependencyOf
+	case scope == "runtime":
+		return RelationshipRuntimeDependencyOf
+	}
+	return RelationshipDependsOn
+}
+
+// IsReversed reports whether the relationship is expressed from the dependency to its dependent
+func (r RelationshipType) IsReversed() bool {
+	switch r {
+	case RelationshipDevDependencyOf, RelationshipBuildToolOf, RelationshipProvidedDependencyOf, RelationshipRuntimeDependencyOf,
+		RelationshipBuildDependencyOf, RelationshipOptionalDependencyOf:
+		return true
+	}
+	return false
 }
 
 // SupplierContact ...
@@ -67,7 +218,8 @@ func (s *SupplierContact) Get() string {
 		return s.FuncGetSupplier()
 	}
 
-	if s.Name == "" {
+	name := strings.TrimSpace(s.Name)
+	if name == "" {
 		return ""
 	}
 
@@ -75,7 +227,7 @@ func (s *SupplierContact) Get() string {
 		s.Type = Organization
 	}
 
-	pkgSupplier := fmt.Sprintf("%s: %s", s.Type, s.Name)
+	pkgSupplier := fmt.Sprintf("%s: %s", s.Type, name)
 	if !s.isEmptyEmail() {
 		pkgSupplier += fmt.Sprintf(" (%s)", s.Email)
 	}
@@ -19,7 +19,32 @@ type Package struct {
 	PackageCopyrightText    string            `json:"copyrightText,omitempty"`
 	PackageLicenseComments  string            `json:"licenseComments,omitempty"`
 	PackageComment          string            `json:"comment,omitempty"`
+	PackageSourceInfo       string            `json:"sourceInfo,omitempty"`
+	PackageAttributionTexts []string          `json:"attributionTexts,omitempty"`
+	PackageExternalRefs     []ExternalRef     `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	Annotations             []Annotation      `json:"annotations,omitempty"`
 	RootPackage             bool              `json:"-"`
+	Relationships           []Relationship    `json:"-"`
+}
+
+// Annotation
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type Annotation struct {
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Comment        string `json:"comment"`
+}
+
+// ExternalRef
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
 }
 
 // Document
@@ -31,12 +56,23 @@ type Document struct {
 	SPDXID                  string                   `json:"SPDXID,omitempty"`
 	DocumentName            string                   `json:"name,omitempty"`
 	DocumentNamespace       string                   `json:"documentNamespace,omitempty"`
+	DocumentComment         string                   `json:"comment,omitempty"`
 	CreationInfo            CreationInfo             `json:"creationInfo,omitempty"`
 	Packages                []Package                `json:"packages,omitempty"`
 	Relationships           []Relationship           `json:"relationships,omitempty"`
 	ExtractedLicensingInfos []ExtractedLicensingInfo `json:"hasExtractedLicensingInfos,omitempty"`
 }
 
+// AllRelationships returns the document relationships followed by the ones nested in the packages,
+// the JSON schema has no package relationships so only tag value keeps them nested
+func (d Document) AllRelationships() []Relationship {
+	relationships := append([]Relationship{}, d.Relationships...)
+	for _, pkg := range d.Packages {
+		relationships = append(relationships, pkg.Relationships...)
+	}
+	return relationships
+}
+
 // CreationInfo
 // JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
 // and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
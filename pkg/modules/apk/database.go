@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package apk
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// maxDatabaseLine is the longest line of the installed database, the descriptions can be long
+const maxDatabaseLine = 1024 * 1024
+
+// apkPackage is a package the installed database lists
+type apkPackage struct {
+	Name    string
+	Version string
+	Arch    string
+	License string
+	// Origin is the aports package the package is built from, e.g. openssl for libcrypto3
+	Origin     string
+	Maintainer string
+	URL        string
+	// Checksum is the Q1 prefixed base64 sha1 digest of the control section of the package
+	Checksum string
+	// Commit is the aports commit the package is built from
+	Commit   string
+	Depends  []string
+	Provides []string
+}
+
+// key identifies the package among the installed ones
+func (p *apkPackage) key() string {
+	return fmt.Sprintf("%s:%s", p.Name, p.Arch)
+}
+
+// sha1 returns the hex sha1 digest of the Q1 checksum of the package
+func (p *apkPackage) sha1() (string, bool) {
+	if !strings.HasPrefix(p.Checksum, "Q1") {
+		return "", false
+	}
+	digest, err := base64.StdEncoding.DecodeString(p.Checksum[2:])
+	if err != nil {
+		return "", false
+	}
+	return hex.EncodeToString(digest), true
+}
+
+// readDatabase reads the packages of the installed database, e.g. lib/apk/db/installed. A package is a block
+// of K:value lines, the blocks are separated by empty lines. The files of the packages are left out. The
+// packages are returned sorted by name
+func readDatabase(fileName string) ([]*apkPackage, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var packages []*apkPackage
+	var p *apkPackage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxDatabaseLine)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			if p != nil {
+				packages = append(packages, p)
+			}
+			p = nil
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", line))
+		}
+		if p == nil {
+			p = &apkPackage{}
+		}
+
+		value := line[2:]
+		switch line[0] {
+		case 'P':
+			p.Name = value
+		case 'V':
+			p.Version = value
+		case 'A':
+			p.Arch = value
+		case 'L':
+			p.License = value
+		case 'o':
+			p.Origin = value
+		case 'm':
+			p.Maintainer = value
+		case 'U':
+			p.URL = value
+		case 'C':
+			p.Checksum = value
+		case 'c':
+			p.Commit = value
+		case 'D':
+			p.Depends = parseDependencies(value)
+		case 'p':
+			p.Provides = parseDependencies(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		packages = append(packages, p)
+	}
+
+	for _, p := range packages {
+		if p.Name == "" {
+			return nil, fmt.Errorf("%w %s: package without a P: name", reader.ErrMalformedFile, fileName)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].key() < packages[j].key() })
+	return packages, nil
+}
+
+// parseDependencies parses the names of a D: or p: line, e.g. so:libc.musl-x86_64.so.1 musl>=1.2. The
+// version constraints are left out, and so are the conflicts, e.g. !busybox-suid
+func parseDependencies(value string) []string {
+	var names []string
+	for _, dependency := range strings.Fields(value) {
+		if strings.HasPrefix(dependency, "!") {
+			continue
+		}
+		if i := strings.IndexAny(dependency, "<>=~"); i >= 0 {
+			dependency = dependency[:i]
+		}
+		if dependency != "" {
+			names = append(names, dependency)
+		}
+	}
+	return names
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package apk
+
+import (
+	"errors"
+)
+
+var (
+	errDatabaseNotFound = errors.New("unable to generate SPDX file, no apk installed database found. Please point --root to the root filesystem the Alpine packages are installed to")
+)
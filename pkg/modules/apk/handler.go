@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package apk
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+type apk struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+// databaseFile is the database of the installed packages, relative to the root filesystem
+var databaseFile = filepath.Join("lib", "apk", "db", "installed")
+
+// New creates a new apk instance
+func New() *apk {
+	return &apk{
+		metadata: models.PluginMetadata{
+			Name:       "Alpine Packages",
+			Slug:       "apk",
+			Manifest:   []string{databaseFile},
+			ModulePath: []string{filepath.Dir(databaseFile)},
+		},
+	}
+}
+
+// GetMetadata returns the plugin metadata
+func (m *apk) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetOptions sets the root filesystem the packages are read from
+func (m *apk) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns no version, the installed database is read without apk. The one of the host may not
+// be the one of the root filesystem
+func (m *apk) GetVersion() (string, error) {
+	return "", nil
+}
+
+// SetRootModule sets root package information base on path given
+func (m *apk) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the distribution of the root filesystem
+func (m *apk) GetRootModule(path string) (*models.Module, error) {
+	root, _, err := helper.DistributionModule(m.root(path))
+	return root, err
+}
+
+// ListUsedModules returns the installed packages, without the distribution
+func (m *apk) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the distribution followed by the installed packages, linked to the packages
+// providing what they depend on
+func (m *apk) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, release, err := helper.DistributionModule(m.root(path))
+	if err != nil {
+		return nil, err
+	}
+	databasePath := filepath.Join(root.LocalPath, databaseFile)
+	if !helper.Exists(databasePath) {
+		return nil, errDatabaseNotFound
+	}
+	packages, err := readDatabase(databasePath)
+	if err != nil {
+		return nil, err
+	}
+	return packageModules(root, release, packages), nil
+}
+
+// IsValid checks if the root filesystem has an apk installed database
+func (m *apk) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(m.root(path), databaseFile))
+}
+
+// HasModulesInstalled checks the root filesystem has an apk installed database
+func (m *apk) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errDatabaseNotFound
+}
+
+// root returns the root filesystem, the project path unless one is given
+func (m *apk) root(path string) string {
+	if m.options.RootFS != "" {
+		return m.options.RootFS
+	}
+	return path
+}
+
+// packageModules returns the distribution followed by a package per installed package, the distribution
+// contains all of them. A dependency is linked to the first package providing it
+func packageModules(root *models.Module, release reader.OSRelease, packages []*apkPackage) []models.Module {
+	modules := []models.Module{*root}
+	providers := map[string]int{}
+	for i, p := range packages {
+		modules = append(modules, packageModule(p, release))
+		for _, name := range append([]string{p.Name}, p.Provides...) {
+			if _, ok := providers[name]; !ok {
+				providers[name] = i + 1
+			}
+		}
+	}
+
+	for i, p := range packages {
+		for _, name := range p.Depends {
+			if j, ok := providers[name]; ok {
+				helper.LinkModuleAs(modules, i+1, j, packages[j-1].key())
+			}
+		}
+	}
+	for i, p := range packages {
+		linked := modules[i+1]
+		linked.Relationship = models.RelationshipContains
+		modules[0].Modules[p.key()] = &linked
+	}
+	return modules
+}
+
+// packageModule returns the module of an installed package, its checksum is the sha1 digest of its control
+// section apk identifies it with
+func packageModule(p *apkPackage, release reader.OSRelease) models.Module {
+	namespace := release.ID
+	if namespace == "" {
+		namespace = "alpine"
+	}
+	distro := ""
+	if release.ID != "" && release.VersionID != "" {
+		distro = fmt.Sprintf("%s-%s", release.ID, release.VersionID)
+	}
+
+	mod := models.Module{
+		Name:    p.Name,
+		Version: p.Version,
+		PackageURL: purl.New("apk", namespace, p.Name, p.Version).
+			WithQualifier("arch", p.Arch).
+			WithQualifier("distro", distro).String(),
+		PackageDownloadLocation: "NOASSERTION",
+		PackageHomePage:         p.URL,
+		Supplier:                helper.MaintainerSupplier(p.Maintainer),
+		Modules:                 map[string]*models.Module{},
+	}
+	if sha1, ok := p.sha1(); ok {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: sha1}
+	}
+	if p.Origin != "" {
+		mod.SourceInfo = fmt.Sprintf("built from the aports origin %s", p.Origin)
+		if p.Commit != "" {
+			mod.SourceInfo = fmt.Sprintf("%s at commit %s", mod.SourceInfo, p.Commit)
+		}
+	}
+	if expression := helper.SPDXExpression(licenseExpression(p.License)); expression != "" {
+		mod.LicenseDeclared = expression
+		mod.LicenseConcluded = expression
+	} else if p.License != "" {
+		mod.CommentsLicense = fmt.Sprintf("the package declares the license: %s", p.License)
+	}
+	return mod
+}
+
+// licenseExpression returns the license of a package with the operators in upper case, the older packages
+// write them in lower case, e.g. MIT and BSD-2-Clause
+func licenseExpression(license string) string {
+	tokens := strings.Fields(license)
+	for i, token := range tokens {
+		switch strings.ToLower(token) {
+		case "and", "or", "with":
+			tokens[i] = strings.ToUpper(token)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
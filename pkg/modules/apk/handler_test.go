@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package apk
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func moduleNames(modules map[string]*models.Module) []string {
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	return names
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "rootfs")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 7) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "alpine", root.Name)
+	assert.Equal(t, "3.18.4", root.Version)
+	assert.Len(t, root.Modules, 6)
+
+	names := []string{}
+	for _, mod := range modules[1:] {
+		names = append(names, mod.Name)
+	}
+	assert.Equal(t, []string{"alpine-keys", "busybox", "libcrypto3", "musl", "scanelf", "ssl_client"}, names)
+
+	keys := modules[1]
+	assert.Nil(t, keys.CheckSum)
+	assert.Empty(t, keys.LicenseDeclared)
+	assert.Equal(t, "the package declares the license: Custom-Public-Keys", keys.CommentsLicense)
+
+	libcrypto := modules[3]
+	assert.Equal(t, "3.1.4-r0", libcrypto.Version)
+	assert.Equal(t, "pkg:apk/alpine/libcrypto3@3.1.4-r0?arch=x86_64&distro=alpine-3.18.4", libcrypto.PackageURL)
+	assert.Equal(t, "Apache-2.0", libcrypto.LicenseDeclared)
+	assert.Equal(t, "built from the aports origin openssl at commit b6e3b0fbcd3e4b34e1d2a8b0de2b1c1fa4c7a01a", libcrypto.SourceInfo)
+	assert.Equal(t, models.SupplierContact{Name: "Ariadne Conill", Email: "ariadne@dereferenced.org", Type: models.Person}, libcrypto.Supplier)
+	assert.Equal(t, []string{"musl"}, moduleNames(libcrypto.Modules))
+
+	musl := modules[4]
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "a675e60467e7230c6556edcc1be48c789469f5a3"}, musl.CheckSum)
+	assert.Empty(t, musl.Modules)
+
+	// libfoo is not installed
+	client := modules[6]
+	assert.Equal(t, "GPL-2.0-only AND MIT", client.LicenseDeclared)
+	assert.Equal(t, "built from the aports origin busybox", client.SourceInfo)
+	assert.ElementsMatch(t, []string{"libcrypto3", "musl"}, moduleNames(client.Modules))
+}
+
+func TestListModulesWithDepsRootFS(t *testing.T) {
+	m := New()
+	path := t.TempDir()
+	assert.Equal(t, errDatabaseNotFound, m.HasModulesInstalled(path))
+
+	m.SetOptions(models.PluginOptions{RootFS: filepath.Join("testdata", "rootfs")})
+	assert.NoError(t, m.HasModulesInstalled(path))
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 7)
+}
+
+func TestReadDatabaseMalformed(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "installed")
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("P:musl\nversion 1.2.4\n"), 0644))
+	_, err := readDatabase(fileName)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("V:1.2.4-r2\n"), 0644))
+	_, err = readDatabase(fileName)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
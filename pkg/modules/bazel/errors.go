@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no MODULE.bazel.lock found. Please resolve the modules before running spdx-sbom-generator, e.g.: `bazel mod deps`")
+)
@@ -0,0 +1,371 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type bazel struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ModuleFile   string = "MODULE.bazel"
+	LockFile     string = "MODULE.bazel.lock"
+	Workspace    string = "WORKSPACE"
+	WorkspaceAlt string = "WORKSPACE.bazel"
+)
+
+const (
+	// bcrURL is the Bazel Central Registry, the default registry of the modules
+	bcrURL = "https://bcr.bazel.build"
+	// registryURL is the website of the Bazel Central Registry, the modules have a page on it
+	registryURL = "https://registry.bazel.build"
+)
+
+// integrityAlgorithms are the hash algorithms of the subresource integrity strings
+var integrityAlgorithms = map[string]models.HashAlgorithm{
+	"sha1":   models.HashAlgoSHA1,
+	"sha256": models.HashAlgoSHA256,
+	"sha384": models.HashAlgoSHA384,
+	"sha512": models.HashAlgoSHA512,
+}
+
+// New creates a new bazel instance
+func New() *bazel {
+	return &bazel{
+		metadata: models.PluginMetadata{
+			Name:       "Bazel",
+			Slug:       "bazel",
+			Manifest:   []string{ModuleFile, Workspace, WorkspaceAlt},
+			ModulePath: []string{},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *bazel) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the bazel version
+func (m *bazel) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "bazel", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *bazel) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *bazel) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the module of the MODULE.bazel of the project, named after its directory when it has none
+func (m *bazel) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root, _, err := m.rootModule(absPath)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// ListUsedModules returns the modules of the project, without the project itself
+func (m *bazel) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the modules bazel resolves for its MODULE.bazel, else
+// the repositories its WORKSPACE downloads. The modules are the ones of the MODULE.bazel.lock, linked by the
+// dependency graph of bazel mod graph when the lockfile has none and bazel is installed
+func (m *bazel) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, mf, err := m.rootModule(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if mf == nil {
+		repositories, err := readWorkspace(workspacePath(absPath))
+		if err != nil {
+			return nil, err
+		}
+		return workspaceModules(root, repositories), nil
+	}
+
+	var g *moduleGraph
+	if lockPath := filepath.Join(absPath, LockFile); helper.Exists(lockPath) {
+		if g, err = readLockfile(lockPath); err != nil {
+			return nil, err
+		}
+	}
+	if g == nil || !g.Linked {
+		if graph, err := m.modGraph(absPath); err == nil {
+			if g != nil {
+				graph.merge(g)
+			}
+			g = graph
+		}
+	}
+	if g == nil {
+		return nil, errDependenciesNotFound
+	}
+	return graphModules(root, mf, g), nil
+}
+
+// IsValid checks if a MODULE.bazel or a WORKSPACE exists
+func (m *bazel) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ModuleFile)) || workspacePath(path) != ""
+}
+
+// HasModulesInstalled checks the modules are locked by a MODULE.bazel.lock or resolvable by bazel, the
+// repositories of a WORKSPACE are pinned by it
+func (m *bazel) HasModulesInstalled(path string) error {
+	if !helper.Exists(filepath.Join(path, ModuleFile)) || helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	if _, err := exec.LookPath("bazel"); err == nil && !m.options.Offline {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// workspacePath returns the WORKSPACE of the project, "" when it has none
+func workspacePath(path string) string {
+	for _, name := range []string{WorkspaceAlt, Workspace} {
+		if workspace := filepath.Join(path, name); helper.Exists(workspace) {
+			return workspace
+		}
+	}
+	return ""
+}
+
+// modGraph returns the dependency graph bazel resolves for the MODULE.bazel, it is not run offline as
+// bazel downloads the registry files it does not have
+func (m *bazel) modGraph(path string) (*moduleGraph, error) {
+	if m.options.Offline {
+		return nil, helper.ErrOffline
+	}
+	if _, err := exec.LookPath("bazel"); err != nil {
+		return nil, err
+	}
+
+	command := helper.NewCmd(helper.CmdOptions{
+		Name:      "bazel",
+		Args:      []string{"mod", "graph", "--output=json"},
+		Directory: path,
+		Context:   m.options.Context,
+	})
+	if err := command.Build(); err != nil {
+		return nil, err
+	}
+	output, err := command.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseModGraph([]byte(output))
+}
+
+// rootModule returns the module of the MODULE.bazel of the project, the MODULE.bazel is nil for a WORKSPACE
+func (m *bazel) rootModule(path string) (*models.Module, *moduleFile, error) {
+	var mf *moduleFile
+	if moduleFilePath := filepath.Join(path, ModuleFile); helper.Exists(moduleFilePath) {
+		var err error
+		if mf, err = readModuleFile(moduleFilePath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+	if mf != nil && mf.Name != "" {
+		mod.Name = mf.Name
+		mod.Version = mf.Version
+		mod.PackageURL = purl.New("bazel", "", mod.Name, mod.Version).String()
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicense(m.options.LicenseMatcher, path, mod)
+	return mod, mf, nil
+}
+
+// graphModules returns the root followed by the modules of the graph, linked to the modules they depend
+// on. The dev dependencies of the MODULE.bazel are DEV_DEPENDENCY_OF the root, which depends on all the
+// modules when the graph is not linked
+func graphModules(root *models.Module, mf *moduleFile, g *moduleGraph) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{rootKey: 0}
+	for _, m := range g.sorted() {
+		index[m.Key] = len(modules)
+		modules = append(modules, bazelModuleModule(m, mf.Overrides[m.Name]))
+	}
+
+	relationships := map[string]models.RelationshipType{}
+	for _, dep := range mf.Deps {
+		if dep.Dev {
+			relationships[dep.Name] = models.RelationshipDevDependencyOf
+		}
+	}
+	if !g.Linked {
+		for key, i := range index {
+			if key != rootKey {
+				helper.LinkModule(modules, 0, i, relationships[modules[i].Name])
+			}
+		}
+		return modules
+	}
+	for key, m := range g.Modules {
+		for _, dep := range m.Deps {
+			j, ok := index[dep]
+			if !ok {
+				continue
+			}
+			relationship := models.RelationshipType("")
+			if key == rootKey {
+				relationship = relationships[modules[j].Name]
+			}
+			helper.LinkModule(modules, index[key], j, relationship)
+		}
+	}
+	return modules
+}
+
+// bazelModuleModule returns the package of a module, downloaded from the source of its override else of
+// the registry. A module overridden by a local directory has no download location
+func bazelModuleModule(m *bazelModule, o override) models.Module {
+	mod := models.Module{
+		Name:                    m.Name,
+		Version:                 m.Version,
+		PackageDownloadLocation: "NOASSERTION",
+		Supplier:                models.SupplierContact{Name: m.Name},
+		Modules:                 map[string]*models.Module{},
+	}
+	p := purl.New("bazel", "", m.Name, m.Version)
+	if m.Registry != "" && m.Registry != bcrURL {
+		p = p.WithQualifier("repository_url", m.Registry)
+	}
+	if m.Registry == bcrURL {
+		mod.PackageHomePage = fmt.Sprintf("%s/modules/%s", registryURL, m.Name)
+	}
+
+	urls, integrity, remote, commit := m.URLs, m.Integrity, m.Remote, m.Commit
+	switch o.Kind {
+	case gitOverride:
+		urls, integrity, remote, commit = nil, "", o.Remote, o.Commit
+	case archiveOverride:
+		urls, integrity, remote, commit = o.URLs, o.Integrity, "", ""
+	case localPathOverride:
+		urls, integrity, remote, commit = nil, "", "", ""
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the module is overridden by the directory %s", o.Path))
+	}
+	switch {
+	case len(urls) > 0:
+		mod.PackageDownloadLocation = urls[0]
+		mod.CheckSum = integrityChecksum(integrity)
+	case remote != "":
+		mod.PackageDownloadLocation = fmt.Sprintf("git+%s", remote)
+		if commit != "" {
+			mod.PackageDownloadLocation = fmt.Sprintf("%s@%s", mod.PackageDownloadLocation, commit)
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: commit}
+		}
+		p = p.WithQualifier("vcs_url", mod.PackageDownloadLocation)
+	}
+	mod.PackageURL = p.String()
+	return mod
+}
+
+// workspaceModules returns the root followed by the repositories of its WORKSPACE, the root depends on
+// all of them
+func workspaceModules(root *models.Module, repositories []workspaceRepository) []models.Module {
+	modules := []models.Module{*root}
+	for _, r := range repositories {
+		mod := models.Module{
+			Name:                    r.Name,
+			Version:                 r.Tag,
+			PackageDownloadLocation: "NOASSERTION",
+			Supplier:                models.SupplierContact{Name: r.Name},
+			Modules:                 map[string]*models.Module{},
+		}
+		p := purl.New("generic", "", r.Name, r.Tag)
+		switch {
+		case len(r.URLs) > 0:
+			mod.PackageDownloadLocation = r.URLs[0]
+			if r.SHA256 != "" {
+				mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: strings.ToLower(r.SHA256)}
+			} else {
+				mod.CheckSum = integrityChecksum(r.Integrity)
+			}
+			p = p.WithQualifier("download_url", r.URLs[0])
+		case r.Remote != "":
+			revision := r.Commit
+			if revision == "" {
+				revision = r.Tag
+			}
+			mod.PackageDownloadLocation = fmt.Sprintf("git+%s", r.Remote)
+			if revision != "" {
+				mod.PackageDownloadLocation = fmt.Sprintf("%s@%s", mod.PackageDownloadLocation, revision)
+			}
+			if r.Commit != "" {
+				mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: r.Commit}
+			}
+			p = p.WithQualifier("vcs_url", mod.PackageDownloadLocation)
+		}
+		mod.PackageURL = p.String()
+		modules = append(modules, mod)
+		helper.LinkModule(modules, 0, len(modules)-1, "")
+	}
+	return modules
+}
+
+// integrityChecksum returns the checksum of a subresource integrity string, e.g. sha256-<base64>,
+// nil when there is none
+func integrityChecksum(integrity string) *models.CheckSum {
+	for _, value := range strings.Fields(integrity) {
+		parts := strings.SplitN(value, "-", 2)
+		algorithm, ok := integrityAlgorithms[parts[0]]
+		if !ok || len(parts) != 2 {
+			continue
+		}
+		digest, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			continue
+		}
+		return &models.CheckSum{Algorithm: algorithm, Value: hex.EncodeToString(digest)}
+	}
+	return nil
+}
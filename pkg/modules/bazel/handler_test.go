@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func rootRelationships(root models.Module) map[string]models.RelationshipType {
+	relationships := map[string]models.RelationshipType{}
+	for name, mod := range root.Modules {
+		relationships[name] = mod.Relationship
+	}
+	return relationships
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "app")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "pkg:bazel/shop@1.0.0", root.PackageURL)
+	assert.Equal(t, map[string]models.RelationshipType{
+		"rules_go":      "",
+		"gazelle":       "",
+		"rules_testing": models.RelationshipDevDependencyOf,
+	}, rootRelationships(root))
+
+	skylib := modules[1]
+	assert.Equal(t, "bazel_skylib", skylib.Name)
+	assert.Equal(t, "pkg:bazel/bazel_skylib@1.4.1", skylib.PackageURL)
+	assert.Equal(t, "https://github.com/bazelbuild/bazel-skylib/releases/download/1.4.1/bazel-skylib-1.4.1.tar.gz", skylib.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "b8a1527901774180afc798aeb28c4634bdccf19c4d98e7bdd1ce79d1fe9aaad7"}, skylib.CheckSum)
+
+	gazelle := modules[2]
+	assert.Equal(t, "0.32.0", gazelle.Version)
+	assert.Equal(t, "git+https://github.com/bazelbuild/bazel-gazelle.git@ddc1d0b8a4bbd0d8a0b1e0d93e8e0f1b5c5d7a3e", gazelle.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "ddc1d0b8a4bbd0d8a0b1e0d93e8e0f1b5c5d7a3e"}, gazelle.CheckSum)
+	assert.Len(t, gazelle.Modules, 2)
+	assert.Contains(t, gazelle.Modules, "rules_go")
+}
+
+func TestListModulesWithDepsRegistryFileHashes(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "registry"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 3) {
+		return
+	}
+
+	assert.Equal(t, map[string]models.RelationshipType{
+		"rules_cc":   "",
+		"googletest": models.RelationshipDevDependencyOf,
+	}, rootRelationships(modules[0]))
+
+	rulesCC := modules[2]
+	assert.Equal(t, "rules_cc", rulesCC.Name)
+	assert.Equal(t, "0.0.9", rulesCC.Version)
+	assert.Equal(t, "https://registry.bazel.build/modules/rules_cc", rulesCC.PackageHomePage)
+	assert.Equal(t, "NOASSERTION", rulesCC.PackageDownloadLocation)
+	assert.Nil(t, rulesCC.CheckSum)
+}
+
+func TestListModulesWithDepsWorkspace(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "workspace")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 3) {
+		return
+	}
+	assert.Len(t, modules[0].Modules, 2)
+
+	absl := modules[1]
+	assert.Equal(t, "com_google_absl", absl.Name)
+	assert.Equal(t, "https://github.com/abseil/abseil-cpp/archive/refs/tags/20230802.0.tar.gz", absl.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "59d2976af9d6ecf001a81a35749a6e551a335b949d34918cfade07737b9d93c5"}, absl.CheckSum)
+
+	gflags := modules[2]
+	assert.Equal(t, "v2.2.2", gflags.Version)
+	assert.Equal(t, "git+https://github.com/gflags/gflags.git@v2.2.2", gflags.PackageDownloadLocation)
+	assert.Equal(t, "pkg:generic/com_github_gflags_gflags@v2.2.2?vcs_url=git%2Bhttps:%2F%2Fgithub.com%2Fgflags%2Fgflags.git%40v2.2.2", gflags.PackageURL)
+	assert.Nil(t, gflags.CheckSum)
+}
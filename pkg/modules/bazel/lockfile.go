@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// rootKey is the key of the module of the project in the dependency graph
+const rootKey = "<root>"
+
+// bazelModule is a module of the dependency graph resolved by bazel, keyed by name@version
+type bazelModule struct {
+	Key     string
+	Name    string
+	Version string
+	// Registry is the registry the module is downloaded from, e.g. https://bcr.bazel.build
+	Registry  string
+	URLs      []string
+	Integrity string
+	Remote    string
+	Commit    string
+	// Deps are the keys of the modules the module depends on, nil when they are not known
+	Deps []string
+}
+
+// moduleGraph is the dependency graph of a project, linked tells whether the deps of the modules are known
+type moduleGraph struct {
+	Modules map[string]*bazelModule
+	Linked  bool
+}
+
+// sorted returns the modules of the graph but the root, sorted by key
+func (g *moduleGraph) sorted() []*bazelModule {
+	var modules []*bazelModule
+	for key, m := range g.Modules {
+		if key != rootKey {
+			modules = append(modules, m)
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Key < modules[j].Key })
+	return modules
+}
+
+// lockfile is a MODULE.bazel.lock. Up to version 5 it has the resolved dependency graph, the later versions
+// only have the hashes of the registry files bazel read, the source.json ones are the ones of the
+// selected versions
+type lockfile struct {
+	LockFileVersion    int               `json:"lockFileVersion"`
+	RegistryFileHashes map[string]string `json:"registryFileHashes"`
+	ModuleDepGraph     map[string]struct {
+		Name     string            `json:"name"`
+		Version  string            `json:"version"`
+		Key      string            `json:"key"`
+		Deps     map[string]string `json:"deps"`
+		RepoSpec *struct {
+			Attributes struct {
+				URLs      []string `json:"urls"`
+				Integrity string   `json:"integrity"`
+				Remote    string   `json:"remote"`
+				Commit    string   `json:"commit"`
+			} `json:"attributes"`
+		} `json:"repoSpec"`
+	} `json:"moduleDepGraph"`
+}
+
+// readLockfile reads the modules of a MODULE.bazel.lock
+func readLockfile(path string) (*moduleGraph, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lock := lockfile{}
+	if err := reader.DecodeJSON(path, data, &lock); err != nil {
+		return nil, err
+	}
+
+	g := &moduleGraph{Modules: map[string]*bazelModule{}}
+	if len(lock.ModuleDepGraph) > 0 {
+		g.Linked = true
+		for key, locked := range lock.ModuleDepGraph {
+			m := &bazelModule{Key: key, Name: locked.Name, Version: locked.Version, Deps: []string{}}
+			if locked.RepoSpec != nil {
+				m.URLs = locked.RepoSpec.Attributes.URLs
+				m.Integrity = locked.RepoSpec.Attributes.Integrity
+				m.Remote = locked.RepoSpec.Attributes.Remote
+				m.Commit = locked.RepoSpec.Attributes.Commit
+			}
+			for _, dep := range locked.Deps {
+				m.Deps = append(m.Deps, dep)
+			}
+			sort.Strings(m.Deps)
+			g.Modules[key] = m
+		}
+		for _, m := range g.Modules {
+			for _, dep := range m.Deps {
+				if _, ok := g.Modules[dep]; !ok {
+					return nil, fmt.Errorf("%w %s: unknown module %q", reader.ErrMalformedFile, path, dep)
+				}
+			}
+		}
+		return g, nil
+	}
+
+	for url := range lock.RegistryFileHashes {
+		// e.g. https://bcr.bazel.build/modules/rules_go/0.41.0/source.json
+		if !strings.HasSuffix(url, "/source.json") {
+			continue
+		}
+		i := strings.LastIndex(url, "/modules/")
+		if i < 0 {
+			continue
+		}
+		parts := strings.Split(strings.TrimSuffix(url[i+len("/modules/"):], "/source.json"), "/")
+		if len(parts) != 2 {
+			continue
+		}
+		key := fmt.Sprintf("%s@%s", parts[0], parts[1])
+		g.Modules[key] = &bazelModule{Key: key, Name: parts[0], Version: parts[1], Registry: url[:i]}
+	}
+	return g, nil
+}
+
+// graphNode is a node of the json output of bazel mod graph, a module already expanded is unexpanded
+type graphNode struct {
+	Key          string      `json:"key"`
+	Name         string      `json:"name"`
+	Version      string      `json:"version"`
+	Unexpanded   bool        `json:"unexpanded"`
+	Dependencies []graphNode `json:"dependencies"`
+}
+
+// parseModGraph parses the output of bazel mod graph --output=json
+func parseModGraph(data []byte) (*moduleGraph, error) {
+	root := graphNode{}
+	if err := reader.DecodeJSON("bazel mod graph", data, &root); err != nil {
+		return nil, err
+	}
+
+	g := &moduleGraph{Modules: map[string]*bazelModule{}, Linked: true}
+	var visit func(n graphNode)
+	visit = func(n graphNode) {
+		key := n.Key
+		if key == "" {
+			key = fmt.Sprintf("%s@%s", n.Name, n.Version)
+		}
+		m, ok := g.Modules[key]
+		if !ok {
+			m = &bazelModule{Key: key, Name: n.Name, Version: n.Version, Deps: []string{}}
+			g.Modules[key] = m
+		}
+		if n.Unexpanded {
+			return
+		}
+		for _, dep := range n.Dependencies {
+			depKey := dep.Key
+			if depKey == "" {
+				depKey = fmt.Sprintf("%s@%s", dep.Name, dep.Version)
+			}
+			m.Deps = append(m.Deps, depKey)
+			visit(dep)
+		}
+		sort.Strings(m.Deps)
+	}
+	visit(root)
+	return g, nil
+}
+
+// merge adds the sources of the modules of another graph to the modules of the graph
+func (g *moduleGraph) merge(other *moduleGraph) {
+	for key, m := range g.Modules {
+		o, ok := other.Modules[key]
+		if !ok {
+			continue
+		}
+		if m.Registry == "" {
+			m.Registry = o.Registry
+		}
+		if len(m.URLs) == 0 {
+			m.URLs, m.Integrity = o.URLs, o.Integrity
+		}
+		if m.Remote == "" {
+			m.Remote, m.Commit = o.Remote, o.Commit
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+// moduleFile is a MODULE.bazel, the module of the project and the modules it depends on
+type moduleFile struct {
+	Name    string
+	Version string
+	Deps    []bazelDep
+	// Overrides are the overrides of the modules, keyed by module name
+	Overrides map[string]override
+}
+
+// bazelDep is a bazel_dep of a MODULE.bazel, a dev dependency is ignored when the project is a dependency
+type bazelDep struct {
+	Name    string
+	Version string
+	Dev     bool
+}
+
+// the kinds of the overrides of a module
+const (
+	gitOverride       = "git_override"
+	archiveOverride   = "archive_override"
+	localPathOverride = "local_path_override"
+)
+
+// override is an override of a module replacing its registry source, e.g. git_override
+type override struct {
+	Kind      string
+	Remote    string
+	Commit    string
+	URLs      []string
+	Integrity string
+	Path      string
+}
+
+// readModuleFile reads the module and the bazel_dep and override calls of a MODULE.bazel
+func readModuleFile(path string) (*moduleFile, error) {
+	calls, err := readCalls(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &moduleFile{Overrides: map[string]override{}}
+	for _, c := range calls {
+		switch c.Function {
+		case "module":
+			m.Name, m.Version = c.String("name"), c.String("version")
+		case "bazel_dep":
+			if name := c.String("name"); name != "" {
+				m.Deps = append(m.Deps, bazelDep{Name: name, Version: c.String("version"), Dev: c.Bool("dev_dependency")})
+			}
+		case gitOverride, archiveOverride, localPathOverride:
+			o := override{
+				Kind:      c.Function,
+				Remote:    c.String("remote"),
+				Commit:    c.String("commit"),
+				URLs:      c.Strings("urls"),
+				Integrity: c.String("integrity"),
+				Path:      c.String("path"),
+			}
+			if len(o.URLs) == 0 {
+				o.URLs = c.Strings("url")
+			}
+			m.Overrides[c.String("module_name")] = o
+		}
+	}
+	return m, nil
+}
+
+// workspaceRepository is a repository of a WORKSPACE downloaded by a repository rule, e.g. http_archive
+type workspaceRepository struct {
+	Name      string
+	URLs      []string
+	SHA256    string
+	Integrity string
+	Remote    string
+	Commit    string
+	Tag       string
+}
+
+// workspaceRules are the repository rules of a WORKSPACE the repositories are read from, the ones
+// macros declare are not known without running bazel
+var workspaceRules = map[string]bool{
+	"http_archive":       true,
+	"http_file":          true,
+	"http_jar":           true,
+	"git_repository":     true,
+	"new_git_repository": true,
+}
+
+// readWorkspace reads the repositories a WORKSPACE declares with the bazel repository rules
+func readWorkspace(path string) ([]workspaceRepository, error) {
+	calls, err := readCalls(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var repositories []workspaceRepository
+	for _, c := range calls {
+		if !workspaceRules[c.Function] || c.String("name") == "" {
+			continue
+		}
+		r := workspaceRepository{
+			Name:      c.String("name"),
+			URLs:      c.Strings("urls"),
+			SHA256:    c.String("sha256"),
+			Integrity: c.String("integrity"),
+			Remote:    c.String("remote"),
+			Commit:    c.String("commit"),
+			Tag:       c.String("tag"),
+		}
+		if len(r.URLs) == 0 {
+			r.URLs = c.Strings("url")
+		}
+		repositories = append(repositories, r)
+	}
+	return repositories, nil
+}
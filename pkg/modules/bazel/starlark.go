@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// call is a top level call of a starlark file, e.g. bazel_dep(name = "rules_go", version = "0.41.0").
+// Its keyword arguments are strings, lists of strings and booleans, the other values are skipped
+type call struct {
+	Function string
+	Args     map[string]interface{}
+	Line     int
+}
+
+// String returns the string argument of the call, "" when it is missing or not a string
+func (c call) String(name string) string {
+	value, _ := c.Args[name].(string)
+	return value
+}
+
+// Strings returns the list of strings argument of the call, a string is a list of one string
+func (c call) Strings(name string) []string {
+	switch value := c.Args[name].(type) {
+	case []string:
+		return value
+	case string:
+		return []string{value}
+	}
+	return nil
+}
+
+// Bool returns the boolean argument of the call, false when it is missing
+func (c call) Bool(name string) bool {
+	value, _ := c.Args[name].(bool)
+	return value
+}
+
+type tokenKind int
+
+const (
+	identToken tokenKind = iota
+	stringToken
+	otherToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// readCalls reads the top level calls of a starlark file, e.g. a MODULE.bazel or a WORKSPACE
+func readCalls(path string) ([]call, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenize(path, string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{path: path, tokens: tokens}
+	var calls []call
+	for p.pos < len(p.tokens) {
+		if c, ok, err := p.parseCall(); err != nil {
+			return nil, err
+		} else if ok {
+			calls = append(calls, c)
+			continue
+		}
+		p.pos++
+	}
+	return calls, nil
+}
+
+// tokenize splits a starlark file into identifiers, strings and punctuation, the comments are dropped
+func tokenize(path string, s string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\\':
+			i++
+		case c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			quote := string(c)
+			if strings.HasPrefix(s[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+			start := line
+			j := i + len(quote)
+			var sb strings.Builder
+			for {
+				if j >= len(s) || (len(quote) == 1 && s[j] == '\n') {
+					return nil, reader.MalformedError(path, start, "unterminated string")
+				}
+				if strings.HasPrefix(s[j:], quote) {
+					break
+				}
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				if s[j] == '\n' {
+					line++
+				}
+				sb.WriteByte(s[j])
+				j++
+			}
+			tokens = append(tokens, token{kind: stringToken, value: sb.String(), line: start})
+			i = j + len(quote)
+		case isIdentByte(c):
+			j := i
+			for j < len(s) && isIdentByte(s[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: identToken, value: s[i:j], line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: otherToken, value: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+type parser struct {
+	path   string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek(offset int) token {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return token{kind: otherToken}
+}
+
+// parseCall parses the call at the position, e.g. go_deps.from_file(go_mod = "//:go.mod"), it reports
+// false when there is none
+func (p *parser) parseCall() (call, bool, error) {
+	n := 0
+	for p.peek(n).kind == identToken && p.peek(n+1).value == "." {
+		n += 2
+	}
+	if p.peek(n).kind != identToken || p.peek(n+1).value != "(" {
+		return call{}, false, nil
+	}
+
+	var function []string
+	for i := 0; i <= n; i += 2 {
+		function = append(function, p.peek(i).value)
+	}
+	c := call{Function: strings.Join(function, "."), Args: map[string]interface{}{}, Line: p.peek(0).line}
+	p.pos += n + 2
+	for {
+		t := p.peek(0)
+		switch {
+		case p.pos >= len(p.tokens):
+			return call{}, false, reader.MalformedError(p.path, c.Line, fmt.Sprintf("unterminated call of %s", c.Function))
+		case t.value == ")" && t.kind == otherToken:
+			p.pos++
+			return c, true, nil
+		case t.value == "," && t.kind == otherToken:
+			p.pos++
+		case t.kind == identToken && p.peek(1).value == "=" && p.peek(2).value != "=":
+			p.pos += 2
+			value, err := p.parseValue()
+			if err != nil {
+				return call{}, false, err
+			}
+			if value != nil {
+				c.Args[t.value] = value
+			}
+		default:
+			before := p.pos
+			if _, err := p.parseValue(); err != nil {
+				return call{}, false, err
+			}
+			// an unbalanced closing bracket ends no value
+			if p.pos == before {
+				p.pos++
+			}
+		}
+	}
+}
+
+// parseValue parses a value up to the next comma or closing bracket: a string, possibly concatenated, a
+// list of strings or a boolean. The other values are skipped and returned as nil
+func (p *parser) parseValue() (interface{}, error) {
+	start := p.pos
+	depth := 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		t := p.tokens[p.pos]
+		if t.kind == otherToken {
+			switch t.value {
+			case "(", "[", "{":
+				depth++
+				continue
+			case ")", "]", "}":
+				if depth == 0 {
+					return p.simpleValue(start), nil
+				}
+				depth--
+				continue
+			case ",":
+				if depth == 0 {
+					return p.simpleValue(start), nil
+				}
+				continue
+			}
+		}
+	}
+	return nil, reader.MalformedError(p.path, p.tokens[start].line, "unterminated value")
+}
+
+// simpleValue returns the value of the tokens from start to the position when they are a string, a
+// concatenation of strings, a list of strings or a boolean
+func (p *parser) simpleValue(start int) interface{} {
+	tokens := p.tokens[start:p.pos]
+	if len(tokens) == 1 && tokens[0].kind == identToken {
+		switch tokens[0].value {
+		case "True":
+			return true
+		case "False":
+			return false
+		}
+		return nil
+	}
+	if len(tokens) >= 2 && tokens[0].value == "[" && tokens[len(tokens)-1].value == "]" {
+		values := []string{}
+		for i, t := range tokens[1 : len(tokens)-1] {
+			switch {
+			case i%2 == 0 && t.kind == stringToken:
+				values = append(values, t.value)
+			case i%2 == 1 && t.kind == otherToken && t.value == ",":
+			default:
+				return nil
+			}
+		}
+		return values
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	for i, t := range tokens {
+		switch {
+		case t.kind == stringToken:
+			sb.WriteString(t.value)
+		case i > 0 && t.kind == otherToken && t.value == "+":
+		default:
+			return nil
+		}
+	}
+	return sb.String()
+}
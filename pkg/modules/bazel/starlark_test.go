@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bazel
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ModuleFile)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+# a comment with bazel_dep(name = "commented")
+module(name = "shop", version = "1." + "0")
+bazel_dep(
+    name = 'rules_go',
+    version = "0.41.0",  # pinned
+    dev_dependency = True,
+)
+ext = use_extension("@rules_go//go:extensions.bzl", "go_sdk", dev_dependency = False)
+ext.download(version = VERSION, goos = select({"linux": "linux"}), patches = ["//:a.patch", "//:b.patch"])
+`), 0644))
+
+	calls, err := readCalls(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, calls, 4) {
+		return
+	}
+	assert.Equal(t, call{Function: "module", Args: map[string]interface{}{"name": "shop", "version": "1.0"}, Line: 3}, calls[0])
+	assert.Equal(t, "rules_go", calls[1].String("name"))
+	assert.True(t, calls[1].Bool("dev_dependency"))
+	assert.Equal(t, "use_extension", calls[2].Function)
+	assert.Equal(t, "ext.download", calls[3].Function)
+	assert.Equal(t, map[string]interface{}{"patches": []string{"//:a.patch", "//:b.patch"}}, calls[3].Args)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("bazel_dep(name = \"rules_go\"\n"), 0644))
+	_, err = readCalls(path)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package modules
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// buildTools maps a plugin slug to the name of the tool driving the build
+var buildTools = map[string]string{
+	"Java-Maven":  "maven",
+	"Java-Gradle": "gradle",
+	"npm":         "npm",
+	"yarn":        "yarn",
+	"pnpm":        "pnpm",
+	"go-mod":      "go",
+	"cargo":       "cargo",
+	"composer":    "composer",
+	"bundler":     "bundler",
+	"nuget":       "dotnet",
+	"swift":       "swift",
+	"pipenv":      "pipenv",
+	"poetry":      "poetry",
+	"pyenv":       "pip",
+	"conda":       "conda",
+	"sbt":         "sbt",
+	"ivy":         "ant",
+}
+
+var toolVersionRegex = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// parseToolVersion extracts the first dotted version from a tool version output,
+// e.g. `Apache Maven 3.8.1 (...)` or `go version go1.16.5 linux/amd64`
+func parseToolVersion(output string) string {
+	return toolVersionRegex.FindString(output)
+}
+
+// buildToolModule returns the module describing the build tool of a plugin
+func buildToolModule(metadata models.PluginMetadata, versionOutput string) (models.Module, error) {
+	name, ok := buildTools[metadata.Slug]
+	if !ok {
+		return models.Module{}, fmt.Errorf("%w: %s", errUnknownBuildTool, metadata.Slug)
+	}
+
+	version := parseToolVersion(versionOutput)
+	if version == "" {
+		return models.Module{}, fmt.Errorf("%w: %s", errUnknownBuildToolVersion, name)
+	}
+
+	return models.Module{
+		Name:    name,
+		Version: version,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA1,
+			Content:   []byte(fmt.Sprintf("%s-%s", name, version)),
+		},
+		Modules:      map[string]*models.Module{},
+		Relationship: models.RelationshipBuildToolOf,
+	}, nil
+}
+
+// toolCreator returns the SPDX creator of the package manager binary the plugin ran, e.g. Tool: mvnw-3.8.1,
+// or "" for the plugins not telling their binary
+func toolCreator(plugin models.IPlugin, versionOutput string) string {
+	executable, ok := plugin.(models.IExecutablePlugin)
+	if !ok || executable.GetExecutable() == "" {
+		return ""
+	}
+
+	if version := parseToolVersion(versionOutput); version != "" {
+		return fmt.Sprintf("Tool: %s-%s", executable.GetExecutable(), version)
+	}
+	return fmt.Sprintf("Tool: %s", executable.GetExecutable())
+}
+
+// addBuildTool appends the build tool to modules and links it to the root module
+func addBuildTool(modules []models.Module, tool models.Module) []models.Module {
+	for i := range modules {
+		if !modules[i].Root {
+			continue
+		}
+
+		if modules[i].Modules == nil {
+			modules[i].Modules = map[string]*models.Module{}
+		}
+		modules[i].Modules[tool.Name] = &tool
+		break
+	}
+
+	return append(modules, tool)
+}
@@ -8,9 +8,5 @@ import (
 
 type errType error
 
-var errNoCmd errType = errors.New("No cargo command")
-var errDependenciesNotFound errType = errors.New("Unable to generate SPDX file, no modules or vendors found. Please install them before running spdx-sbom-generator, e.g.: `cargo build`")
-var errBuildlingModuleDependencies errType = errors.New("Error building modules dependencies")
-var errNoCargoCommand errType = errors.New("No Cargo command")
-var erroRootPackageInformation errType = errors.New("Failed to read root folder information. Please verify you can run `cargo pkgid`")
-var errFailedToConvertModules errType = errors.New("Failed to convert modules")
+var errDependenciesNotFound errType = errors.New("Unable to generate SPDX file, no Cargo.lock found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `cargo generate-lockfile`")
+var errUnsupportedLockfileVersion errType = errors.New("unsupported Cargo.lock version")
@@ -3,113 +3,382 @@
 package cargo
 
 import (
+	"fmt"
+	"net/mail"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
 )
 
+const (
+	CargoTomlFile string = "Cargo.toml"
+	CargoLockFile string = "Cargo.lock"
+)
+
+// cratesIOURL is the registry the crates of the crates.io index are downloaded from
+const cratesIOURL = "https://crates.io"
+
 type mod struct {
-	metadata      models.PluginMetadata
-	rootModule    *models.Module
-	command       *helper.Cmd
-	cargoMetadata CargoMetadata
+	metadata models.PluginMetadata
+	options  models.PluginOptions
 }
 
+// New creates a new cargo instance
 func New() *mod {
 	return &mod{
 		metadata: models.PluginMetadata{
 			Name:       "Cargo Modules",
 			Slug:       "cargo",
-			Manifest:   []string{CargoTomlFile},
+			Manifest:   []string{CargoTomlFile, CargoLockFile},
 			ModulePath: []string{"vendor"},
 		},
 	}
 }
+
+// GetMetadata returns the plugin metadata
 func (m *mod) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
+// SetRootModule sets root package information base on path given
 func (m *mod) SetRootModule(path string) error {
-	module, err := m.getRootModule(path)
-	if err != nil {
-		return err
-	}
-
-	m.rootModule = &module
 	return nil
 }
 
+// SetOptions ...
+func (m *mod) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the cargo version
 func (m *mod) GetVersion() (string, error) {
-	if err := m.buildCmd(VersionCmd, "."); err != nil {
+	output, err := helper.CommandContext(m.options.Context, "cargo", "--version").Output()
+	if err != nil {
 		return "", err
 	}
-
-	return m.command.Output()
+	return strings.TrimSpace(string(output)), nil
 }
 
+// GetRootModule returns the package of the Cargo.toml as the root package, or the workspace named
+// after its directory for a virtual manifest
 func (m *mod) GetRootModule(path string) (*models.Module, error) {
-	if err := m.SetRootModule(path); err != nil {
-		return nil, err
-	}
-
-	return m.rootModule, nil
-}
-
-func (m *mod) ListUsedModules(path string) ([]models.Module, error) {
-	var collection []models.Module
-
-	rootModule, err := m.GetRootModule(path)
+	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
 
-	collection = append(collection, *rootModule)
-	meta, err := m.getCargoMetadata(path)
+	p, err := readProject(absPath)
 	if err != nil {
 		return nil, err
 	}
-	modules, err := convertMetadataToModulesList(meta.Packages)
+	return rootModule(p, m.options.LicenseMatcher), nil
+}
+
+// ListUsedModules returns the crates of the Cargo.lock, without the root package
+func (m *mod) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
 	if err != nil {
 		return nil, err
 	}
-
-	collection = append(collection, modules...)
-
-	return collection, nil
+	return modules[1:], nil
 }
 
+// ListModulesWithDeps returns the root package followed by the crates of the Cargo.lock, each linked to the
+// crates it depends on. The dependencies of the crates of the project are related as their Cargo.toml
+// declares them: the dev and build ones are DEV_DEPENDENCY_OF and BUILD_DEPENDENCY_OF them, the optional
+// ones no default feature enables OPTIONAL_DEPENDENCY_OF. The root CONTAINS the other members of its workspace
 func (m *mod) ListModulesWithDeps(path string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
 
-	meta, err := m.getCargoMetadata(path)
+	p, err := readProject(absPath)
 	if err != nil {
 		return nil, err
 	}
-
-	err = addDepthModules(modules, meta.Packages)
+	lock, err := readLockfile(filepath.Join(absPath, CargoLockFile))
+	if os.IsNotExist(err) {
+		return nil, errDependenciesNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	modules := []models.Module{*rootModule(p, m.options.LicenseMatcher)}
+	index := map[*lockPackage]int{}
+	for _, pkg := range lock.Packages {
+		if pkg.Source == "" && pkg.Name == p.Manifest.Name {
+			index[pkg] = 0
+			continue
+		}
+		index[pkg] = len(modules)
+		modules = append(modules, packageModule(pkg, p, m.options.LicenseMatcher))
+	}
+
+	for _, pkg := range lock.Packages {
+		var c *crate
+		if pkg.Source == "" {
+			c = p.Crates[pkg.Name]
+		}
+		for _, name := range pkg.Dependencies {
+			dependency, ok := lock.lookup(name)
+			if !ok {
+				continue
+			}
+			relationship := models.RelationshipType("")
+			if c != nil {
+				relationship = c.Manifest.relationship(dependency.Name)
+			}
+			helper.LinkModule(modules, index[pkg], index[dependency], relationship)
+		}
+	}
+
+	if p.Manifest.Workspace {
+		for _, pkg := range lock.Packages {
+			if _, ok := p.Crates[pkg.Name]; !ok || pkg.Source != "" || index[pkg] == 0 {
+				continue
+			}
+			// a member the root depends on is linked as its dependency
+			if _, linked := modules[0].Modules[pkg.Name]; !linked {
+				helper.LinkModule(modules, 0, index[pkg], models.RelationshipContains)
+			}
+		}
+	}
 	return modules, nil
 }
 
+// IsValid checks if the Cargo.toml exists
 func (m *mod) IsValid(path string) bool {
-	for i := range m.metadata.Manifest {
-		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
-			return true
-		}
-	}
-	return false
+	return helper.Exists(filepath.Join(path, CargoTomlFile))
 }
 
+// HasModulesInstalled checks the dependencies are locked, the Cargo.lock is enough to list them
 func (m *mod) HasModulesInstalled(path string) error {
 	if helper.Exists(filepath.Join(path, CargoLockFile)) {
 		return nil
 	}
 	return errDependenciesNotFound
 }
+
+// relationship returns how the crate of the name relates to the crate of the manifest depending on it,
+// a crate of several dependencies is related by the one most needed
+func (m *manifest) relationship(name string) models.RelationshipType {
+	defaults := m.defaultDependencies()
+	found, required, build, optional := false, false, false, false
+	for _, d := range m.Dependencies {
+		if d.Name != name {
+			continue
+		}
+		found = true
+		switch {
+		case d.Kind == kindDev:
+		case d.Optional && !defaults[d.Key]:
+			optional = true
+		case d.Kind == kindBuild:
+			build = true
+		default:
+			required = true
+		}
+	}
+
+	switch {
+	case !found || required:
+		return ""
+	case build:
+		return models.RelationshipBuildDependencyOf
+	case optional:
+		return models.RelationshipOptionalDependencyOf
+	}
+	return models.RelationshipDevDependencyOf
+}
+
+// rootModule returns the package of the Cargo.toml, or the workspace of a virtual manifest
+func rootModule(p *project, matcher helper.LicenseMatcher) *models.Module {
+	name, version := p.Manifest.Name, p.Manifest.Version
+	if name == "" {
+		name = filepath.Base(p.Path)
+	}
+
+	mod := &models.Module{
+		Name:            name,
+		Version:         version,
+		Root:            true,
+		LocalPath:       p.Path,
+		PackageHomePage: p.Manifest.Homepage,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(fmt.Sprintf("%s-%s", name, version)),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	if p.Manifest.Name != "" {
+		mod.PackageURL = purl.New("cargo", "", name, version).String()
+		mod.Supplier = getPackageSupplier(p.Manifest.Authors, name)
+	}
+	mod.PackageDownloadLocation = p.Manifest.Repository
+	if mod.PackageDownloadLocation == "" {
+		mod.PackageDownloadLocation = "NONE"
+	}
+	setLicense(mod, p.Manifest, matcher)
+	return mod
+}
+
+// packageModule returns the module of a locked crate, enriched with the Cargo.toml of its sources when
+// they are in the project, vendored or downloaded to the cargo registry
+func packageModule(pkg *lockPackage, p *project, matcher helper.LicenseMatcher) models.Module {
+	s := pkg.source()
+	mod := models.Module{
+		Name:                    pkg.Name,
+		Version:                 pkg.Version,
+		PackageURL:              packageURL(pkg.Name, pkg.Version, s),
+		PackageDownloadLocation: downloadLocation(pkg.Name, pkg.Version, s),
+		Supplier:                models.SupplierContact{Name: pkg.Name},
+		Modules:                 map[string]*models.Module{},
+	}
+
+	switch {
+	case pkg.Checksum != "":
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: pkg.Checksum}
+	case s.Kind == sourcePath:
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Content: []byte(fmt.Sprintf("%s-%s", pkg.Name, pkg.Version))}
+	default:
+		mod.Unresolved = append(mod.Unresolved, fmt.Sprintf("the crate is locked from its %s source, its checksum is unknown", s.Kind))
+	}
+	if s.isCratesIO() {
+		mod.PackageHomePage = fmt.Sprintf("%s/crates/%s/%s", cratesIOURL, pkg.Name, pkg.Version)
+	}
+
+	// the sources only enrich the crate, it is listed without them
+	dir, m := crateSources(p, pkg, s)
+	if m == nil {
+		return mod
+	}
+	mod.LocalPath = dir
+	mod.Supplier = getPackageSupplier(m.Authors, pkg.Name)
+	if m.Homepage != "" {
+		mod.PackageHomePage = m.Homepage
+	}
+	setLicense(&mod, m, matcher)
+	return mod
+}
+
+// packageURL returns the pkg:cargo purl of the crate, qualified by its registry when it is not crates.io
+// and by its repository and commit for a git crate
+func packageURL(name, version string, s crateSource) string {
+	p := purl.New("cargo", "", name, version)
+	switch {
+	case s.Kind == sourceGit:
+		p = p.WithQualifier("vcs_url", fmt.Sprintf("git+%s@%s", s.URL, s.Commit))
+	case s.Kind != sourcePath && !s.isCratesIO():
+		p = p.WithQualifier("repository_url", s.URL)
+	}
+	return p.String()
+}
+
+// downloadLocation returns where the crate is downloaded from: crates.io, its git repository at the
+// locked commit, NONE for a path crate of the project and NOASSERTION for the other registries
+func downloadLocation(name, version string, s crateSource) string {
+	switch {
+	case s.isCratesIO():
+		return fmt.Sprintf("%s/api/v1/crates/%s/%s/download", cratesIOURL, name, version)
+	case s.Kind == sourceGit:
+		return fmt.Sprintf("git+%s@%s", s.URL, s.Commit)
+	case s.Kind == sourcePath:
+		return "NONE"
+	}
+	return "NOASSERTION"
+}
+
+// crateSources returns the directory and the Cargo.toml of the sources of the crate, nil when they are not
+// found: the ones of a crate of the project, of a vendored crate or of a crate the cargo registry downloaded
+func crateSources(p *project, pkg *lockPackage, s crateSource) (string, *manifest) {
+	if s.Kind == sourcePath {
+		if c, ok := p.Crates[pkg.Name]; ok {
+			return c.Dir, c.Manifest
+		}
+		return "", nil
+	}
+
+	dirs := []string{
+		filepath.Join(p.Path, "vendor", pkg.Name+"-"+pkg.Version),
+		filepath.Join(p.Path, "vendor", pkg.Name),
+	}
+	if s.Kind != sourceGit {
+		downloaded, _ := filepath.Glob(filepath.Join(cargoHome(), "registry", "src", "*", pkg.Name+"-"+pkg.Version))
+		dirs = append(dirs, downloaded...)
+	}
+	for _, dir := range dirs {
+		if m, err := readManifest(filepath.Join(dir, CargoTomlFile), nil); err == nil && m.Version == pkg.Version {
+			return dir, m
+		}
+	}
+	return "", nil
+}
+
+// cargoHome returns the directory cargo downloads the crates to, $CARGO_HOME or ~/.cargo
+func cargoHome() string {
+	if home := os.Getenv("CARGO_HOME"); home != "" {
+		return home
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cargo")
+}
+
+// setLicense sets the license of the module to the one of its Cargo.toml when it is an SPDX expression,
+// else to the one of its license files
+func setLicense(mod *models.Module, m *manifest, matcher helper.LicenseMatcher) {
+	// the / separating the licenses of the older crates stands for OR
+	license := strings.Join(strings.Split(m.License, "/"), " OR ")
+	if expression := helper.SPDXExpression(license); expression != "" {
+		mod.LicenseDeclared = expression
+		mod.LicenseConcluded = expression
+		return
+	}
+	if mod.LocalPath == "" {
+		return
+	}
+	if license, err := helper.GetLicenses(matcher, mod.LocalPath); err == nil {
+		mod.LicenseDeclared = helper.BuildLicenseDeclared(license.ID)
+		mod.LicenseConcluded = helper.BuildLicenseConcluded(license.ID)
+		mod.Copyright = helper.GetCopyright(license.ExtractedText)
+		mod.CommentsLicense = license.Comments
+		if !helper.LicenseSPDXExists(license.ID) {
+			mod.OtherLicense = append(mod.OtherLicense, license)
+		}
+	}
+}
+
+// getPackageSupplier returns the first author of the crate, a person when it has an email
+func getPackageSupplier(authors []string, defaultValue string) models.SupplierContact {
+	if len(authors) == 0 || authors[0] == "" {
+		return models.SupplierContact{Name: defaultValue}
+	}
+
+	mainAuthor := authors[0]
+	if author, err := mail.ParseAddress(mainAuthor); err == nil {
+		supplier := models.SupplierContact{Name: author.Name, Email: author.Address, Type: models.Person}
+		if supplier.Name == "" {
+			supplier.Name = mainAuthor
+		}
+		return supplier
+	}
+	return models.SupplierContact{Name: mainAuthor, Type: models.Organization}
+}
+
+func readLockfile(path string) (*lockfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseLockfile(path, file)
+}
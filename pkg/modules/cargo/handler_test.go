@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cargo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func modulesByName(modules []models.Module) map[string]models.Module {
+	result := map[string]models.Module{}
+	for _, mod := range modules {
+		result[mod.Name+"@"+mod.Version] = mod
+	}
+	return result
+}
+
+// withCargoHome runs the test with CARGO_HOME set to dir
+func withCargoHome(t *testing.T, dir string) func() {
+	previous, set := os.LookupEnv("CARGO_HOME")
+	assert.NoError(t, os.Setenv("CARGO_HOME", dir))
+	return func() {
+		if set {
+			os.Setenv("CARGO_HOME", previous)
+		} else {
+			os.Unsetenv("CARGO_HOME")
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	workspace := filepath.Join("testdata", "workspace")
+	assert.True(t, m.IsValid(workspace))
+	assert.False(t, m.IsValid("testdata"))
+	assert.NoError(t, m.HasModulesInstalled(workspace))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled(filepath.Join(workspace, "crates", "cli")))
+}
+
+func TestGetRootModule(t *testing.T) {
+	root, err := New().GetRootModule(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	assert.True(t, root.Root)
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, "0.3.0", root.Version)
+	assert.Equal(t, "pkg:cargo/app@0.3.0", root.PackageURL)
+	assert.Equal(t, "https://github.com/example/app", root.PackageDownloadLocation)
+	assert.Equal(t, "MIT OR Apache-2.0", root.LicenseDeclared)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	defer withCargoHome(t, filepath.Join("testdata", "cargo-home"))()
+
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 15)
+	byName := modulesByName(modules)
+
+	root := modules[0]
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, []string{"cc", "cli", "internal-lib", "log", "pretty_assertions", "rand", "serde", "tokio", "utils", "winapi"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipType(""), root.Modules["serde"].Relationship)
+	assert.Equal(t, models.RelationshipType(""), root.Modules["rand"].Relationship)
+	assert.Equal(t, models.RelationshipType(""), root.Modules["winapi"].Relationship)
+	assert.Equal(t, models.RelationshipType(""), root.Modules["utils"].Relationship)
+	assert.Equal(t, models.RelationshipOptionalDependencyOf, root.Modules["tokio"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["pretty_assertions"].Relationship)
+	assert.Equal(t, models.RelationshipBuildDependencyOf, root.Modules["cc"].Relationship)
+	assert.Equal(t, models.RelationshipContains, root.Modules["cli"].Relationship)
+
+	// the crates depending on a crate of several versions name the one they depend on
+	assert.Equal(t, "2.0.37", byName["serde@1.0.188"].Modules["syn"].Version)
+	assert.Equal(t, "1.0.109", byName["tokio@1.32.0"].Modules["syn"].Version)
+
+	serde := byName["serde@1.0.188"]
+	assert.Equal(t, "pkg:cargo/serde@1.0.188", serde.PackageURL)
+	assert.Equal(t, "https://crates.io/api/v1/crates/serde/1.0.188/download", serde.PackageDownloadLocation)
+	assert.Equal(t, "https://crates.io/crates/serde/1.0.188", serde.PackageHomePage)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "cf9e0fcba69a370eed61bcf2b728575f726b50b55cba78064753d708ddc7549e"}, serde.CheckSum)
+
+	regex := byName["regex@1.9.5"]
+	assert.Equal(t, "pkg:cargo/regex@1.9.5?vcs_url=git%2Bhttps:%2F%2Fgithub.com%2Frust-lang%2Fregex%402d5b8d3c8e6e0a4f5c1e8e4b9f4f7c6a3b2d1e0f", regex.PackageURL)
+	assert.Equal(t, "git+https://github.com/rust-lang/regex@2d5b8d3c8e6e0a4f5c1e8e4b9f4f7c6a3b2d1e0f", regex.PackageDownloadLocation)
+	assert.Nil(t, regex.CheckSum)
+	assert.Equal(t, []string{"the crate is locked from its git source, its checksum is unknown"}, regex.Unresolved)
+
+	internal := byName["internal-lib@0.1.2"]
+	assert.Equal(t, "NOASSERTION", internal.PackageDownloadLocation)
+	assert.Contains(t, internal.PackageURL, "repository_url=")
+
+	utils := byName["utils@0.3.0"]
+	assert.Equal(t, "NONE", utils.PackageDownloadLocation)
+	assert.Equal(t, "MIT OR Apache-2.0", utils.LicenseDeclared)
+	assert.Equal(t, []string{"regex"}, modulestest.LinkedNames(utils))
+
+	// the downloaded and the vendored crates are enriched with their Cargo.toml
+	log := byName["log@0.4.20"]
+	assert.Equal(t, "MIT OR Apache-2.0", log.LicenseDeclared)
+	assert.Equal(t, "Organization: The Rust Project Developers", log.Supplier.Get())
+	tokio := byName["tokio@1.32.0"]
+	assert.Equal(t, "MIT", tokio.LicenseDeclared)
+	assert.Equal(t, "https://tokio.rs", tokio.PackageHomePage)
+	assert.Equal(t, "Person: Tokio Contributors (team@tokio.rs)", tokio.Supplier.Get())
+
+	_, skipped := byName["skipped@0.1.0"]
+	assert.False(t, skipped)
+}
+
+func TestListUsedModules(t *testing.T) {
+	modules, err := New().ListUsedModules(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 14)
+	for _, mod := range modules {
+		assert.False(t, mod.Root)
+	}
+}
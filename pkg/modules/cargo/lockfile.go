@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cargo
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the latest Cargo.lock version, the lockfiles of versions 1 and 2 have no version field
+const maxLockfileVersion = 4
+
+// the indexes of crates.io, git and sparse, the crates without a source are path ones
+const (
+	cratesIOIndex       = "https://github.com/rust-lang/crates.io-index"
+	cratesIOSparseIndex = "https://index.crates.io/"
+)
+
+// the kinds of sources of the locked crates
+const (
+	sourceRegistry = "registry"
+	sourceSparse   = "sparse"
+	sourceGit      = "git"
+	sourcePath     = "path"
+)
+
+// lockPackage is a [[package]] of a Cargo.lock
+type lockPackage struct {
+	Name    string
+	Version string
+	Source  string
+	// Checksum is the sha256 hex digest of the .crate file of a registry crate
+	Checksum string
+	// Dependencies are the crates it depends on, as the lockfile names them: name, name version or
+	// name version (source) when several crates have the name
+	Dependencies []string
+}
+
+// lockfile is a Cargo.lock
+type lockfile struct {
+	Version  int
+	Packages []*lockPackage
+}
+
+// crateSource is the source of a locked crate, e.g. registry+https://github.com/rust-lang/crates.io-index
+// or git+https://github.com/serde-rs/serde?branch=master#<commit>
+type crateSource struct {
+	Kind string
+	URL  string
+	// Commit is the commit a git crate is locked at
+	Commit string
+}
+
+// parseLockfile reads the packages of a Cargo.lock, the checksums of the version 1 lockfiles are read from their metadata
+func parseLockfile(fileName string, r io.Reader) (*lockfile, error) {
+	document, err := reader.ParseTOML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &lockfile{Version: 1}
+	if version := document.Str("version"); version != "" {
+		l.Version, err = strconv.Atoi(version)
+		if err != nil || l.Version < 1 || l.Version > maxLockfileVersion {
+			return nil, fmt.Errorf("%w: %q in %s", errUnsupportedLockfileVersion, version, fileName)
+		}
+	}
+
+	checksums := document.Get("metadata")
+	for _, entry := range document.Tables("package") {
+		pkg := &lockPackage{
+			Name:         entry.Str("name"),
+			Version:      entry.Str("version"),
+			Source:       entry.Str("source"),
+			Checksum:     entry.Str("checksum"),
+			Dependencies: entry.Strings("dependencies"),
+		}
+		if pkg.Name == "" {
+			continue
+		}
+		if pkg.Checksum == "" {
+			key := fmt.Sprintf("checksum %s %s (%s)", pkg.Name, pkg.Version, pkg.Source)
+			if checksum := checksums.Str(key); checksum != "<none>" {
+				pkg.Checksum = checksum
+			}
+		}
+		l.Packages = append(l.Packages, pkg)
+	}
+	return l, nil
+}
+
+// lookup returns the package of a dependency of the lockfile: name, name version or name version (source)
+func (l *lockfile) lookup(dependency string) (*lockPackage, bool) {
+	name, version, source := dependency, "", ""
+	if i := strings.Index(dependency, " ("); i >= 0 && strings.HasSuffix(dependency, ")") {
+		source = dependency[i+2 : len(dependency)-1]
+		dependency = dependency[:i]
+	}
+	if fields := strings.Fields(dependency); len(fields) > 1 {
+		name, version = fields[0], fields[1]
+	} else {
+		name = strings.TrimSpace(dependency)
+	}
+
+	for _, pkg := range l.Packages {
+		if pkg.Name != name || (version != "" && pkg.Version != version) || (source != "" && pkg.Source != source) {
+			continue
+		}
+		return pkg, true
+	}
+	return nil, false
+}
+
+// source returns the source of the crate
+func (p *lockPackage) source() crateSource {
+	kind := sourcePath
+	rawURL := p.Source
+	if i := strings.Index(p.Source, "+"); i >= 0 {
+		kind, rawURL = p.Source[:i], p.Source[i+1:]
+	}
+	if kind != sourceGit {
+		return crateSource{Kind: kind, URL: rawURL}
+	}
+
+	s := crateSource{Kind: kind, URL: rawURL}
+	if u, err := url.Parse(rawURL); err == nil {
+		s.Commit = u.Fragment
+		u.Fragment, u.RawQuery = "", ""
+		s.URL = u.String()
+	}
+	return s
+}
+
+// isCratesIO tells whether the crate is published on crates.io
+func (s crateSource) isCratesIO() bool {
+	return (s.Kind == sourceRegistry && s.URL == cratesIOIndex) || (s.Kind == sourceSparse && s.URL == cratesIOSparseIndex)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cargo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLockfile(t *testing.T) {
+	lock, err := readLockfile("testdata/workspace/Cargo.lock")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, lock.Version)
+	assert.Len(t, lock.Packages, 15)
+
+	syn, ok := lock.lookup("syn 1.0.109")
+	assert.True(t, ok)
+	assert.Equal(t, "1.0.109", syn.Version)
+	_, ok = lock.lookup("syn 3.0.0")
+	assert.False(t, ok)
+
+	serde, ok := lock.lookup("serde")
+	assert.True(t, ok)
+	assert.Equal(t, "cf9e0fcba69a370eed61bcf2b728575f726b50b55cba78064753d708ddc7549e", serde.Checksum)
+	assert.Equal(t, []string{"syn 2.0.37"}, serde.Dependencies)
+	assert.True(t, serde.source().isCratesIO())
+
+	regex, _ := lock.lookup("regex")
+	assert.Equal(t, crateSource{Kind: sourceGit, URL: "https://github.com/rust-lang/regex", Commit: "2d5b8d3c8e6e0a4f5c1e8e4b9f4f7c6a3b2d1e0f"}, regex.source())
+	utils, _ := lock.lookup("utils")
+	assert.Equal(t, sourcePath, utils.source().Kind)
+	internal, _ := lock.lookup("internal-lib")
+	assert.Equal(t, crateSource{Kind: sourceSparse, URL: "https://cargo.example.com/index/"}, internal.source())
+	assert.False(t, internal.source().isCratesIO())
+}
+
+func TestParseLockfileV1(t *testing.T) {
+	lock, err := parseLockfile("Cargo.lock", strings.NewReader(`[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "libc 0.2.40 (registry+https://github.com/rust-lang/crates.io-index)",
+]
+
+[[package]]
+name = "libc"
+version = "0.2.40"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "local"
+version = "0.1.0"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[metadata]
+"checksum libc 0.2.40 (registry+https://github.com/rust-lang/crates.io-index)" = "6fd41f331ac7c5b8ac259b8bf82c75c0fb2e469bbf37d2becbba9a6a2221965b"
+"checksum local 0.1.0 (registry+https://github.com/rust-lang/crates.io-index)" = "<none>"
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, lock.Version)
+
+	libc, ok := lock.lookup(lock.Packages[0].Dependencies[0])
+	assert.True(t, ok)
+	assert.Equal(t, "6fd41f331ac7c5b8ac259b8bf82c75c0fb2e469bbf37d2becbba9a6a2221965b", libc.Checksum)
+	assert.Equal(t, "", lock.Packages[2].Checksum)
+}
+
+func TestParseLockfileUnsupportedVersion(t *testing.T) {
+	_, err := parseLockfile("Cargo.lock", strings.NewReader("version = 9\n"))
+	assert.True(t, errors.Is(err, errUnsupportedLockfileVersion))
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cargo
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the kinds of the dependencies of a Cargo.toml, the dev ones are only needed to test the crate
+// and the build ones to run its build script
+const (
+	kindNormal = "normal"
+	kindDev    = "dev"
+	kindBuild  = "build"
+)
+
+// dependencySections are the kinds of the dependency tables, the underscored names are the legacy ones
+var dependencySections = map[string]string{
+	"dependencies":       kindNormal,
+	"dev-dependencies":   kindDev,
+	"dev_dependencies":   kindDev,
+	"build-dependencies": kindBuild,
+	"build_dependencies": kindBuild,
+}
+
+// manifestDependency is a dependency of a Cargo.toml
+type manifestDependency struct {
+	// Name is the name of the crate, the key of the dependency unless it is renamed with package
+	Name string
+	// Key is the name the crate is referred to by in the features of the manifest
+	Key      string
+	Kind     string
+	Optional bool
+	// Target is the platform of a [target.<cfg>] dependency, "" for all of them
+	Target string
+	Path   string
+}
+
+// manifest is a Cargo.toml: the package of a crate, of a workspace or both
+type manifest struct {
+	Name         string
+	Version      string
+	License      string
+	LicenseFile  string
+	Homepage     string
+	Repository   string
+	Description  string
+	Authors      []string
+	Dependencies []manifestDependency
+	// Features are the features of the crate and the features and optional dependencies they enable
+	Features map[string][]string
+	// Workspace is set for the manifest of a workspace root, Members are the glob patterns of its
+	// members and Exclude the paths left out of them
+	Workspace bool
+	Members   []string
+	Exclude   []string
+
+	// workspacePackage and workspaceDependencies are the fields the members inherit with workspace = true
+	workspacePackage      reader.Table
+	workspaceDependencies reader.Table
+}
+
+// parseManifest reads the package, the dependencies of every kind and target, the features and the workspace of
+// a Cargo.toml. The fields inherited with workspace = true are read from the workspace root manifest,
+// nil for the manifest of the workspace root itself
+func parseManifest(fileName string, r io.Reader, workspace *manifest) (*manifest, error) {
+	document, err := reader.ParseTOML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &manifest{Features: map[string][]string{}}
+	if section := document.Get("workspace"); section != nil {
+		m.Workspace = true
+		m.Members = section.Strings("members")
+		m.Exclude = section.Strings("exclude")
+		m.workspacePackage = section.Get("package")
+		m.workspaceDependencies = section.Get("dependencies")
+	}
+	if workspace == nil {
+		workspace = m
+	}
+
+	pkg := document.Get("package")
+	if pkg == nil {
+		pkg = document.Get("project")
+	}
+	if pkg != nil {
+		m.Name = pkg.Str("name")
+		m.Version = inheritedString(pkg, workspace, "version")
+		m.License = inheritedString(pkg, workspace, "license")
+		m.LicenseFile = inheritedString(pkg, workspace, "license-file")
+		m.Homepage = inheritedString(pkg, workspace, "homepage")
+		m.Repository = inheritedString(pkg, workspace, "repository")
+		m.Description = inheritedString(pkg, workspace, "description")
+		m.Authors = pkg.Strings("authors")
+		if isInherited(pkg, "authors") {
+			m.Authors = workspace.workspacePackage.Strings("authors")
+		}
+	}
+
+	m.addDependencies(document, "", workspace)
+	if targets := document.Get("target"); targets != nil {
+		cfgs := make([]string, 0, len(targets))
+		for cfg := range targets {
+			cfgs = append(cfgs, cfg)
+		}
+		sort.Strings(cfgs)
+		for _, cfg := range cfgs {
+			m.addDependencies(targets.Get(cfg), cfg, workspace)
+		}
+	}
+
+	for feature := range document.Get("features") {
+		m.Features[feature] = document.Get("features").Strings(feature)
+	}
+	return m, nil
+}
+
+// addDependencies adds the dependencies of the dependency tables of section, sorted by key
+func (m *manifest) addDependencies(section reader.Table, target string, workspace *manifest) {
+	sections := make([]string, 0, len(dependencySections))
+	for name := range dependencySections {
+		sections = append(sections, name)
+	}
+	sort.Strings(sections)
+
+	for _, name := range sections {
+		dependencies := section.Get(name)
+		keys := make([]string, 0, len(dependencies))
+		for key := range dependencies {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			d := manifestDependency{Name: key, Key: key, Kind: dependencySections[name], Target: target}
+			definition, _ := dependencies[key].(reader.Table)
+			if definition != nil && definition["workspace"] == true {
+				// the workspace dependency is the one inherited, the member may only make it optional
+				if inherited, ok := workspace.workspaceDependencies[key].(reader.Table); ok {
+					d.Name = nonEmpty(inherited.Str("package"), d.Name)
+					d.Path = inherited.Str("path")
+				}
+			}
+			if definition != nil {
+				d.Name = nonEmpty(definition.Str("package"), d.Name)
+				d.Path = nonEmpty(definition.Str("path"), d.Path)
+				d.Optional = definition["optional"] == true
+			}
+			m.Dependencies = append(m.Dependencies, d)
+		}
+	}
+}
+
+// defaultDependencies returns the keys of the optional dependencies the default features enable
+func (m *manifest) defaultDependencies() map[string]bool {
+	enabled := map[string]bool{}
+	visited := map[string]bool{}
+	queue := []string{"default"}
+	for len(queue) > 0 {
+		feature := queue[0]
+		queue = queue[1:]
+		if visited[feature] {
+			continue
+		}
+		visited[feature] = true
+
+		for _, entry := range m.Features[feature] {
+			key, enables := featureDependency(entry)
+			switch {
+			case strings.HasPrefix(entry, "dep:"):
+				enabled[key] = true
+			case enables && strings.Contains(entry, "/"):
+				enabled[key] = true
+				if _, ok := m.Features[key]; ok {
+					queue = append(queue, key)
+				}
+			case enables:
+				if _, ok := m.Features[entry]; ok {
+					queue = append(queue, entry)
+				} else {
+					// an optional dependency is an implicit feature of the same name
+					enabled[entry] = true
+				}
+			}
+		}
+	}
+	return enabled
+}
+
+// featureDependency returns the dependency or the feature an entry of a feature names, e.g. serde of dep:serde,
+// serde/derive or serde?/derive. The weak serde?/derive enables a feature of serde but not serde itself
+func featureDependency(entry string) (string, bool) {
+	entry = strings.TrimPrefix(entry, "dep:")
+	if i := strings.Index(entry, "/"); i >= 0 {
+		name := entry[:i]
+		return strings.TrimSuffix(name, "?"), !strings.HasSuffix(name, "?")
+	}
+	return entry, true
+}
+
+// inheritedString returns the string field of the package, or the one of the workspace package
+// when the field is inherited
+func inheritedString(pkg reader.Table, workspace *manifest, key string) string {
+	if isInherited(pkg, key) {
+		return workspace.workspacePackage.Str(key)
+	}
+	return pkg.Str(key)
+}
+
+// isInherited tells whether the field of the package is inherited, e.g. version.workspace = true
+func isInherited(pkg reader.Table, key string) bool {
+	field, ok := pkg[key].(reader.Table)
+	return ok && field["workspace"] == true
+}
+
+func nonEmpty(value, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cargo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	root, err := readManifest("testdata/workspace/Cargo.toml", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, "0.3.0", root.Version)
+	assert.Equal(t, "MIT OR Apache-2.0", root.License)
+	assert.Equal(t, []string{"Jane Doe <jane@example.com>"}, root.Authors)
+	assert.True(t, root.Workspace)
+	assert.Equal(t, []string{"crates/*"}, root.Members)
+	assert.Equal(t, []string{"crates/skipped"}, root.Exclude)
+
+	dependencies := map[string]manifestDependency{}
+	for _, d := range root.Dependencies {
+		dependencies[d.Key] = d
+	}
+	assert.Len(t, dependencies, 9)
+	assert.Equal(t, manifestDependency{Name: "serde", Key: "serde", Kind: kindNormal}, dependencies["serde"])
+	assert.Equal(t, manifestDependency{Name: "internal-lib", Key: "internal", Kind: kindNormal}, dependencies["internal"])
+	assert.Equal(t, manifestDependency{Name: "utils", Key: "utils", Kind: kindNormal, Path: "crates/utils"}, dependencies["utils"])
+	assert.True(t, dependencies["tokio"].Optional)
+	assert.Equal(t, kindDev, dependencies["pretty_assertions"].Kind)
+	assert.Equal(t, kindBuild, dependencies["cc"].Kind)
+	assert.Equal(t, "cfg(windows)", dependencies["winapi"].Target)
+
+	assert.Equal(t, map[string]bool{"rand": true}, root.defaultDependencies())
+
+	member, err := readManifest("testdata/workspace/crates/utils/Cargo.toml", root)
+	assert.NoError(t, err)
+	assert.Equal(t, "utils", member.Name)
+	assert.Equal(t, "0.3.0", member.Version)
+	assert.Equal(t, "MIT OR Apache-2.0", member.License)
+	assert.False(t, member.Workspace)
+}
+
+func TestDefaultDependencies(t *testing.T) {
+	m, err := parseManifest("Cargo.toml", strings.NewReader(`[package]
+name = "features"
+
+[dependencies]
+a = { version = "1", optional = true }
+b = { version = "1", optional = true }
+c = { version = "1", optional = true }
+d = { version = "1", optional = true }
+e = { version = "1", optional = true }
+
+[features]
+default = ["full", "c"]
+full = ["dep:a", "b/std", "d?/std"]
+extra = ["e"]
+`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a": true, "b": true, "c": true}, m.defaultDependencies())
+}
+
+func TestRelationship(t *testing.T) {
+	m, err := readManifest("testdata/workspace/Cargo.toml", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", string(m.relationship("serde")))
+	assert.Equal(t, "", string(m.relationship("rand")))
+	assert.Equal(t, "", string(m.relationship("unknown")))
+	assert.Equal(t, "OPTIONAL_DEPENDENCY_OF", string(m.relationship("tokio")))
+	assert.Equal(t, "DEV_DEPENDENCY_OF", string(m.relationship("pretty_assertions")))
+	assert.Equal(t, "BUILD_DEPENDENCY_OF", string(m.relationship("cc")))
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cargo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// crate is a crate of the project, a member of its workspace or the package of its Cargo.toml
+type crate struct {
+	Dir      string
+	Manifest *manifest
+}
+
+// project is a Cargo.toml and the crates of its workspace keyed by name, the package of a Cargo.toml
+// which is not a virtual manifest is one of them
+type project struct {
+	Path     string
+	Manifest *manifest
+	Crates   map[string]*crate
+}
+
+// readProject reads the Cargo.toml of path and the ones of its workspace members: the directories
+// the members patterns match, less the excluded ones, and the path dependencies below path
+func readProject(path string) (*project, error) {
+	root, err := readManifest(filepath.Join(path, CargoTomlFile), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &project{Path: path, Manifest: root, Crates: map[string]*crate{}}
+	var queue []string
+	if root.Name != "" {
+		c := &crate{Dir: path, Manifest: root}
+		p.Crates[root.Name] = c
+		queue = append(queue, p.pathDependencies(c)...)
+	}
+	for _, pattern := range root.Members {
+		dirs, err := filepath.Glob(filepath.Join(path, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			if !p.excluded(dir) {
+				queue = append(queue, dir)
+			}
+		}
+	}
+
+	visited := map[string]bool{path: true}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		if visited[dir] {
+			continue
+		}
+		visited[dir] = true
+
+		m, err := readManifest(filepath.Join(dir, CargoTomlFile), root)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if m.Name == "" {
+			continue
+		}
+		c := &crate{Dir: dir, Manifest: m}
+		p.Crates[m.Name] = c
+		queue = append(queue, p.pathDependencies(c)...)
+	}
+	return p, nil
+}
+
+// pathDependencies returns the directories of the path dependencies of the crate within the project,
+// cargo makes them members of the workspace
+func (p *project) pathDependencies(c *crate) []string {
+	var dirs []string
+	for _, d := range c.Manifest.Dependencies {
+		if d.Path == "" {
+			continue
+		}
+		dir := filepath.Clean(filepath.Join(c.Dir, filepath.FromSlash(d.Path)))
+		if isBelow(p.Path, dir) && !p.excluded(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// excluded tells whether the directory is excluded from the workspace
+func (p *project) excluded(dir string) bool {
+	for _, exclude := range p.Manifest.Exclude {
+		if isBelow(filepath.Join(p.Path, filepath.FromSlash(exclude)), dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBelow tells whether path is dir or one of its subdirectories
+func isBelow(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func readManifest(path string, workspace *manifest) (*manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseManifest(path, file, workspace)
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package carthage
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the origins of a Cartfile entry
+const (
+	originGitHub = "github"
+	originGit    = "git"
+	originBinary = "binary"
+)
+
+// gitHubURL is the server of the github origins naming a repository by its owner and its name only
+const gitHubURL = "https://github.com/"
+
+// cartfileEntry is a dependency of a Cartfile, pinned to a version, a tag or a commit in a Cartfile.resolved
+// and to a requirement in a Cartfile, e.g. github "Alamofire/Alamofire" ~> 5.8
+type cartfileEntry struct {
+	Origin string
+	// Location is the owner/name or the url of a github repository, the url of a git one or the url of
+	// the json specification of a binary framework
+	Location string
+	Version  string
+}
+
+// readCartfile reads the entries of a Cartfile, a Cartfile.private or a Cartfile.resolved, the pinned
+// version of an entry is its last quoted value and a Cartfile requirement is kept as written
+func readCartfile(fileName string) ([]cartfileEntry, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []cartfileEntry
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected entry %q", line))
+		}
+		origin := fields[0]
+		if origin != originGitHub && origin != originGit && origin != originBinary {
+			return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unknown origin %q", origin))
+		}
+		location, rest, ok := quoted(strings.TrimSpace(fields[1]))
+		if !ok || location == "" {
+			return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected entry %q", line))
+		}
+		version, _, ok := quoted(rest)
+		if !ok {
+			version = rest
+		}
+		entries = append(entries, cartfileEntry{Origin: origin, Location: location, Version: version})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// quoted returns the value of the double quoted string text starts with and the text following it
+func quoted(text string) (string, string, bool) {
+	if !strings.HasPrefix(text, "\"") {
+		return "", text, false
+	}
+	end := strings.Index(text[1:], "\"")
+	if end < 0 {
+		return "", text, false
+	}
+	return text[1 : end+1], strings.TrimSpace(text[end+2:]), true
+}
+
+// stripComment drops the comment of a line, a # outside of the quoted values
+func stripComment(text string) string {
+	inQuotes := false
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case '#':
+			if !inQuotes {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
+
+// name returns the name Carthage checks the dependency out and builds it under: the last component of
+// its repository or of its binary specification, without the .git or .json extension
+func (e cartfileEntry) name() string {
+	location := strings.TrimSuffix(e.Location, "/")
+	if u, err := url.Parse(location); err == nil && u.Path != "" {
+		location = u.Path
+	}
+	name := path.Base(location)
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		// a scp-like url, e.g. git@github.com:owner/name.git
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(name, ".git"), ".json")
+}
+
+// repositoryURL returns the url of the repository of a github or a git entry, "" for a binary one
+func (e cartfileEntry) repositoryURL() string {
+	switch e.Origin {
+	case originGitHub:
+		if strings.Contains(e.Location, "://") {
+			return e.Location
+		}
+		return gitHubURL + strings.TrimSuffix(e.Location, ".git")
+	case originGit:
+		return e.Location
+	}
+	return ""
+}
+
+// gitHubRepository returns the owner and the name of the github.com repository of the entry, false when
+// it is not hosted by github.com
+func (e cartfileEntry) gitHubRepository() (string, string, bool) {
+	repository := strings.TrimSuffix(strings.TrimSuffix(e.repositoryURL(), "/"), ".git")
+	if !strings.HasPrefix(repository, gitHubURL) {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(repository, gitHubURL), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package carthage
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadCartfile(t *testing.T) {
+	entries, err := readCartfile(filepath.Join("testdata", "app", Cartfile))
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 4) {
+		return
+	}
+
+	assert.Equal(t, cartfileEntry{Origin: originGitHub, Location: "Alamofire/Alamofire", Version: "~> 5.8"}, entries[0])
+	assert.Equal(t, "master", entries[1].Version)
+	assert.Equal(t, "== 2.1.0", entries[2].Version)
+	assert.Equal(t, originBinary, entries[3].Origin)
+
+	resolved, err := readCartfile(filepath.Join("testdata", "app", ResolvedCartfile))
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 6)
+	assert.Equal(t, "10.15.0", resolved[0].Version)
+}
+
+func TestReadCartfileMalformed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ResolvedCartfile)
+	assert.NoError(t, ioutil.WriteFile(file, []byte("github \"Alamofire/Alamofire\" \"5.8.1\"\nsvn \"https://svn.example.com/repo\" \"1.0\"\n"), 0644))
+
+	_, err := readCartfile(file)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestEntryName(t *testing.T) {
+	assert.Equal(t, "Alamofire", cartfileEntry{Origin: originGitHub, Location: "Alamofire/Alamofire"}.name())
+	assert.Equal(t, "Analytics", cartfileEntry{Origin: originGit, Location: "https://git.acme.example.com/mobile/Analytics.git"}.name())
+	assert.Equal(t, "Kit", cartfileEntry{Origin: originGit, Location: "git@github.com:acme/Kit.git"}.name())
+	assert.Equal(t, "FirebaseAnalyticsBinary", cartfileEntry{Origin: originBinary, Location: "https://dl.google.com/FirebaseAnalyticsBinary.json"}.name())
+
+	owner, repo, ok := cartfileEntry{Origin: originGit, Location: "https://github.com/Quick/Nimble.git"}.gitHubRepository()
+	assert.True(t, ok)
+	assert.Equal(t, "Quick", owner)
+	assert.Equal(t, "Nimble", repo)
+	_, _, ok = cartfileEntry{Origin: originGitHub, Location: "https://ghe.acme.example.com/mobile/Kit"}.gitHubRepository()
+	assert.False(t, ok)
+}
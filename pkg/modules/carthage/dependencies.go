@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package carthage
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// gitRevision is a sha1 commit hash, a dependency pinned to a branch is resolved to its commit
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// rootModule returns the package of the project, with the license of the project files
+func (m *carthage) rootModule(path string) *models.Module {
+	name := filepath.Base(path)
+	mod := &models.Module{
+		Name:                    name,
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(name),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	helper.SetLicense(m.options.LicenseMatcher, path, mod)
+	return mod
+}
+
+// resolvedModules returns the root followed by the resolved dependencies. The root depends on the
+// dependencies of its Cartfile and the ones of its Cartfile.private are DEV_DEPENDENCY_OF it, or else it
+// depends on the ones no dependency depends on
+func (m *carthage) resolvedModules(path string, root *models.Module, resolved, direct, private []cartfileEntry) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	for _, entry := range resolved {
+		index[entry.name()] = len(modules)
+		modules = append(modules, m.entryModule(path, entry))
+	}
+
+	dependencies := map[int]bool{}
+	for _, entry := range resolved {
+		checkout := filepath.Join(path, CarthageDir, checkoutsDir, entry.name(), Cartfile)
+		if !helper.Exists(checkout) {
+			continue
+		}
+		requirements, err := readCartfile(checkout)
+		if err != nil {
+			log.Warnf("failed to read the dependencies of %s: %v", entry.name(), err)
+			continue
+		}
+		for _, requirement := range requirements {
+			if i, ok := index[requirement.name()]; ok {
+				helper.LinkModule(modules, index[entry.name()], i, "")
+				dependencies[i] = true
+			}
+		}
+	}
+
+	if len(direct) == 0 && len(private) == 0 {
+		for i := 1; i < len(modules); i++ {
+			if !dependencies[i] {
+				helper.LinkModule(modules, 0, i, "")
+			}
+		}
+		return modules
+	}
+	for _, entry := range private {
+		if i, ok := index[entry.name()]; ok {
+			helper.LinkModule(modules, 0, i, models.RelationshipDevDependencyOf)
+		}
+	}
+	for _, entry := range direct {
+		if i, ok := index[entry.name()]; ok {
+			helper.LinkModule(modules, 0, i, "")
+		}
+	}
+	return modules
+}
+
+// entryModule returns the package of a resolved dependency: a repository is downloaded at the pinned tag
+// or commit, the sha1 hash of a commit being its checksum, and a binary framework from an archive its
+// json specification lists
+func (m *carthage) entryModule(path string, entry cartfileEntry) models.Module {
+	name := entry.name()
+	mod := models.Module{
+		Name:                    name,
+		Version:                 entry.Version,
+		PackageDownloadLocation: "NOASSERTION",
+		Modules:                 map[string]*models.Module{},
+	}
+
+	p := purl.New("generic", "", name, entry.Version)
+	switch entry.Origin {
+	case originGitHub, originGit:
+		repository := entry.repositoryURL()
+		vcs := fmt.Sprintf("git+%s", repository)
+		if entry.Version != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, entry.Version)
+		}
+		if owner, repo, ok := entry.gitHubRepository(); ok {
+			// the github purls are case insensitive and lowercased
+			p = purl.New("github", strings.ToLower(owner), strings.ToLower(repo), entry.Version)
+		} else {
+			p = p.WithQualifier("vcs_url", vcs)
+		}
+		mod.PackageDownloadLocation = vcs
+		if strings.HasPrefix(repository, "https://") {
+			mod.PackageHomePage = strings.TrimSuffix(repository, ".git")
+		}
+		mod.LocalPath = filepath.Join(path, CarthageDir, checkoutsDir, name)
+	case originBinary:
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the framework is a binary, the archives of its versions are listed by %s", entry.Location))
+	}
+	mod.PackageURL = p.String()
+
+	if gitRevision.MatchString(entry.Version) {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: entry.Version}
+	}
+	if mod.LocalPath != "" && helper.Exists(mod.LocalPath) {
+		helper.SetLicense(m.options.LicenseMatcher, mod.LocalPath, &mod)
+	}
+	return mod
+}
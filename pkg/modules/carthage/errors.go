@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package carthage
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no Cartfile.resolved found. Please resolve the dependencies before running spdx-sbom-generator, e.g.: `carthage bootstrap`")
+)
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package carthage
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type carthage struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	Cartfile         string = "Cartfile"
+	PrivateCartfile  string = "Cartfile.private"
+	ResolvedCartfile string = "Cartfile.resolved"
+	CarthageDir      string = "Carthage"
+	checkoutsDir     string = "Checkouts"
+)
+
+// New creates a new carthage instance
+func New() *carthage {
+	return &carthage{
+		metadata: models.PluginMetadata{
+			Name:       "Carthage",
+			Slug:       "carthage",
+			Manifest:   []string{Cartfile, ResolvedCartfile},
+			ModulePath: []string{CarthageDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *carthage) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the Carthage version
+func (m *carthage) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "carthage", "version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *carthage) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *carthage) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the project as the root package, named after its directory
+func (m *carthage) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return m.rootModule(absPath), nil
+}
+
+// ListUsedModules returns the dependencies of the project, without the project itself
+func (m *carthage) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the dependencies its Cartfile.resolved pins. The
+// project depends on the ones of its Cartfile and on the ones of its Cartfile.private for development,
+// a dependency on the ones of the Cartfile of its checkout
+func (m *carthage) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	resolvedPath := filepath.Join(absPath, ResolvedCartfile)
+	if !helper.Exists(resolvedPath) {
+		return nil, errDependenciesNotFound
+	}
+	resolved, err := readCartfile(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var direct, private []cartfileEntry
+	if file := filepath.Join(absPath, Cartfile); helper.Exists(file) {
+		if direct, err = readCartfile(file); err != nil {
+			return nil, err
+		}
+	}
+	if file := filepath.Join(absPath, PrivateCartfile); helper.Exists(file) {
+		if private, err = readCartfile(file); err != nil {
+			return nil, err
+		}
+	}
+	return m.resolvedModules(absPath, m.rootModule(absPath), resolved, direct, private), nil
+}
+
+// IsValid checks if a Cartfile or a Cartfile.resolved exists
+func (m *carthage) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, Cartfile)) || helper.Exists(filepath.Join(path, ResolvedCartfile))
+}
+
+// HasModulesInstalled checks the dependencies are resolved, the checkouts are only read for the licenses
+// and the dependencies between them
+func (m *carthage) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, ResolvedCartfile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
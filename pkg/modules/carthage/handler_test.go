@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package carthage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "app")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "app"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 7) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, []string{"Alamofire", "Analytics", "FirebaseAnalyticsBinary", "Nimble", "ReactiveCocoa"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["Nimble"].Relationship)
+	assert.Equal(t, models.RelationshipType(""), root.Modules["Alamofire"].Relationship)
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	alamofire := byName["Alamofire"]
+	assert.Equal(t, "5.8.1", alamofire.Version)
+	assert.Equal(t, "pkg:github/alamofire/alamofire@5.8.1", alamofire.PackageURL)
+	assert.Equal(t, "git+https://github.com/Alamofire/Alamofire@5.8.1", alamofire.PackageDownloadLocation)
+	assert.Equal(t, "https://github.com/Alamofire/Alamofire", alamofire.PackageHomePage)
+	assert.Equal(t, "MIT", alamofire.LicenseDeclared)
+	assert.Nil(t, alamofire.CheckSum)
+
+	cocoa := byName["ReactiveCocoa"]
+	assert.Equal(t, models.HashAlgoSHA1, cocoa.CheckSum.Algorithm)
+	assert.Equal(t, "3b5c8f0e2a9d1c7b6e4f3a2d1c0b9a8e7f6d5c4b", cocoa.CheckSum.Value)
+	assert.Equal(t, []string{"ReactiveSwift"}, modulestest.LinkedNames(cocoa))
+
+	analytics := byName["Analytics"]
+	assert.Equal(t, "pkg:generic/Analytics@2.1.0?vcs_url=git%2Bhttps:%2F%2Fgit.acme.example.com%2Fmobile%2FAnalytics.git%402.1.0", analytics.PackageURL)
+	assert.Equal(t, "git+https://git.acme.example.com/mobile/Analytics.git@2.1.0", analytics.PackageDownloadLocation)
+
+	firebase := byName["FirebaseAnalyticsBinary"]
+	assert.Equal(t, "10.15.0", firebase.Version)
+	assert.Equal(t, "NOASSERTION", firebase.PackageDownloadLocation)
+	assert.Len(t, firebase.Annotations, 1)
+}
+
+func TestResolvedModulesWithoutCartfile(t *testing.T) {
+	resolved := []cartfileEntry{
+		{Origin: originGitHub, Location: "ReactiveCocoa/ReactiveCocoa", Version: "12.0.0"},
+		{Origin: originGitHub, Location: "ReactiveCocoa/ReactiveSwift", Version: "6.7.0"},
+	}
+	plugin := New()
+	modules := plugin.resolvedModules(filepath.Join("testdata", "app"), plugin.rootModule(t.TempDir()), resolved, nil, nil)
+	assert.Equal(t, []string{"ReactiveCocoa"}, modulestest.LinkedNames(modules[0]))
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package clojure
+
+import (
+	"errors"
+)
+
+var errDependenciesNotFound error = errors.New("unable to generate SPDX file, the build tool of the project was not found. Please install Leiningen for a project.clj or the Clojure CLI for a deps.edn")
+var errNoManifest error = errors.New("no project.clj or deps.edn found")
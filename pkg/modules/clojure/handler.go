@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package clojure
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type clojure struct {
+	metadata models.PluginMetadata
+	tool     string
+	options  models.PluginOptions
+}
+
+const (
+	LeinManifest string = "project.clj"
+	DepsManifest string = "deps.edn"
+
+	leinTool string = "lein"
+	cliTool  string = "clojure"
+)
+
+// defprojectRegex matches the name and version of a Leiningen project, e.g. (defproject org.example/app "1.0.0"
+var defprojectRegex = regexp.MustCompile(`\(defproject\s+(\S+)\s+"([^"]*)"`)
+
+// dependencyTreeOutput runs the tool printing the dependency tree in the project directory, offline when asked, until ctx is done, tests replace it
+var dependencyTreeOutput = func(ctx context.Context, path string, tool string, offline bool) ([]byte, error) {
+	var args []string
+	switch tool {
+	case leinTool:
+		if offline {
+			args = append(args, "-o")
+		}
+		args = append(args, "deps", ":tree")
+	case cliTool:
+		args = []string{"-Stree"}
+	}
+
+	cmd := helper.CommandContext(ctx, tool, args...)
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, helper.OfflineError(offline, err, "resolve the dependencies online first")
+	}
+	return out, nil
+}
+
+// New creates a new Clojure instance, for Leiningen and Clojure CLI projects
+func New() *clojure {
+	return &clojure{
+		metadata: models.PluginMetadata{
+			Name:       "Clojure",
+			Slug:       "clojure",
+			Manifest:   []string{LeinManifest, DepsManifest},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// projectTool returns the tool resolving the dependencies of the project, Leiningen when both manifests exist
+func projectTool(path string) (string, string, error) {
+	if helper.Exists(filepath.Join(path, LeinManifest)) {
+		return leinTool, LeinManifest, nil
+	}
+	if helper.Exists(filepath.Join(path, DepsManifest)) {
+		return cliTool, DepsManifest, nil
+	}
+	return "", "", errNoManifest
+}
+
+// SetOptions ...
+func (m *clojure) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the version of the tool of the project
+func (m *clojure) GetVersion() (string, error) {
+	if m.tool == "" {
+		return "", errNoManifest
+	}
+	if _, err := exec.LookPath(m.tool); err != nil {
+		return "", err
+	}
+
+	flag := "version"
+	if m.tool == cliTool {
+		flag = "--version"
+	}
+	output, err := helper.CommandContext(m.options.Context, m.tool, flag).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]), nil
+}
+
+// GetExecutable returns the tool of the project, lein or clojure
+func (m *clojure) GetExecutable() string {
+	return m.tool
+}
+
+// GetMetadata returns the plugin metadata
+func (m *clojure) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule selects the tool of the project
+func (m *clojure) SetRootModule(path string) error {
+	tool, _, err := projectTool(path)
+	if err != nil {
+		return err
+	}
+	m.tool = tool
+	return nil
+}
+
+// GetRootModule returns the Leiningen project, or the directory of a deps.edn, as the root package
+func (m *clojure) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, manifest, err := projectTool(absPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(filepath.Join(absPath, manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	root := models.Module{
+		Name:      filepath.Base(absPath),
+		Root:      true,
+		LocalPath: absPath,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA1,
+			Content:   content,
+		},
+		Modules: map[string]*models.Module{},
+	}
+
+	// a deps.edn names no project
+	if match := defprojectRegex.FindStringSubmatch(string(content)); match != nil && manifest == LeinManifest {
+		project := newArtifact(match[1], match[2], "")
+		root.Name = project.ArtifactID
+		root.Version = project.Version
+		root.Path = project.GroupID + ":" + project.ArtifactID
+		root.PackageURL = purl.New("maven", project.GroupID, project.ArtifactID, project.Version).String()
+		root.Supplier = models.SupplierContact{Type: models.Organization, Name: project.GroupID}
+	}
+	return &root, nil
+}
+
+// ListUsedModules returns the dependencies of the project, without the project itself
+func (m *clojure) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the root package followed by the dependencies of the tree the tool of the
+// project prints, linked to the ones depending on them
+func (m *clojure) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tool, _, err := projectTool(path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := dependencyTreeOutput(m.options.Context, path, tool, m.options.Offline)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := parseCLITree(out)
+	if tool == leinTool {
+		tree = parseLeinTree(out)
+	}
+	return treeModules(*root, tree, localRepository()), nil
+}
+
+// treeModules returns the root package and the dependencies of the tree, the root depends on the direct ones
+func treeModules(root models.Module, tree dependencyTree, repository string) []models.Module {
+	modules := []models.Module{root}
+	indexes := map[string]int{}
+	keys := []string{""}
+	for _, a := range tree.Artifacts {
+		indexes[a.key()] = len(modules)
+		keys = append(keys, a.key())
+		modules = append(modules, newModule(a, repository))
+	}
+
+	for _, key := range tree.Direct {
+		helper.LinkModuleAs(modules, 0, indexes[key], modules[indexes[key]].Name)
+	}
+	for i := 1; i < len(modules); i++ {
+		for _, dependency := range tree.Dependencies[keys[i]] {
+			helper.LinkModuleAs(modules, i, indexes[dependency], modules[indexes[dependency]].Name)
+		}
+	}
+	return modules
+}
+
+// newModule returns the package of the artifact. The checksum is the one of its jar in the local
+// repository, which also tells the repository it was downloaded from
+func newModule(a artifact, repository string) models.Module {
+	mod := models.Module{
+		Name:       a.ArtifactID,
+		Version:    a.Version,
+		Path:       a.GroupID + ":" + a.ArtifactID,
+		Packaging:  "jar",
+		Classifier: a.Classifier,
+		Supplier: models.SupplierContact{
+			Type: models.Organization,
+			Name: a.GroupID,
+		},
+		Modules: map[string]*models.Module{},
+	}
+
+	packageURL := purl.New("maven", a.GroupID, a.ArtifactID, a.Version).WithQualifier("classifier", a.Classifier)
+	if repositoryURL, ok := remoteRepositories[remoteRepository(repository, a)]; ok {
+		mod.PackageDownloadLocation = repositoryURL + filepath.ToSlash(artifactDir(a))
+		// Maven Central is the default repository of maven purls
+		if repositoryURL != mavenCentralURL {
+			packageURL = packageURL.WithQualifier("repository_url", strings.TrimSuffix(repositoryURL, "/"))
+		}
+	}
+	mod.PackageURL = packageURL.String()
+
+	sum, err := jarChecksum(repository, a)
+	if err != nil {
+		log.Printf("no jar of %s in the local repository: %v", a.key(), err)
+		mod.Unresolved = append(mod.Unresolved, "the jar is not in the local repository, its checksum is unknown")
+		return mod
+	}
+	mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: sum}
+	return mod
+}
+
+// IsValid checks if a project.clj or a deps.edn exists
+func (m *clojure) IsValid(path string) bool {
+	_, _, err := projectTool(path)
+	return err == nil
+}
+
+// HasModulesInstalled checks the tool of the project is installed, it downloads the dependencies itself
+func (m *clojure) HasModulesInstalled(path string) error {
+	tool, _, err := projectTool(path)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return errDependenciesNotFound
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package clojure
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func stubDependencyTree(t *testing.T, file string) func() {
+	out, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	home, err := filepath.Abs(filepath.Join("testdata", "home"))
+	assert.NoError(t, err)
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	original := dependencyTreeOutput
+	dependencyTreeOutput = func(ctx context.Context, path string, tool string, offline bool) ([]byte, error) {
+		return out, nil
+	}
+	return func() {
+		dependencyTreeOutput = original
+		os.Setenv("HOME", originalHome)
+	}
+}
+
+func TestListModulesWithDepsLein(t *testing.T) {
+	defer stubDependencyTree(t, "testdata/lein/deps-tree.txt")()
+
+	plugin := New()
+	assert.NoError(t, plugin.SetRootModule(filepath.Join("testdata", "lein")))
+	assert.Equal(t, "lein", plugin.GetExecutable())
+
+	modules, err := plugin.ListModulesWithDeps(filepath.Join("testdata", "lein"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 12)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "hello", root.Name)
+	assert.Equal(t, "0.1.0-SNAPSHOT", root.Version)
+	assert.Equal(t, "pkg:maven/org.example/hello@0.1.0-SNAPSHOT", root.PackageURL)
+	assert.Len(t, root.Modules, 5)
+
+	byName := map[string]models.Module{}
+	for _, module := range modules {
+		byName[module.Name] = module
+	}
+
+	// the jar was downloaded from Clojars
+	ring := byName["ring-core"]
+	assert.Equal(t, "pkg:maven/ring/ring-core@1.9.4?repository_url=https:%2F%2Frepo.clojars.org", ring.PackageURL)
+	assert.Equal(t, "https://repo.clojars.org/ring/ring-core/1.9.4", ring.PackageDownloadLocation)
+	assert.Equal(t, "a008e68d75e72aad65c9635872c3f1382ec5e8b3", ring.CheckSum.Value)
+	assert.Contains(t, ring.Modules, "ring-codec")
+
+	clojure := byName["clojure"]
+	assert.Equal(t, "pkg:maven/org.clojure/clojure@1.10.3", clojure.PackageURL)
+	assert.Equal(t, "https://repo1.maven.org/maven2/org/clojure/clojure/1.10.3", clojure.PackageDownloadLocation)
+	assert.Equal(t, "d648f89059bd02d5fe1940ba8c1ed7f60fe731ae", clojure.CheckSum.Value)
+
+	lwjgl := byName["lwjgl"]
+	assert.Equal(t, "natives-linux", lwjgl.Classifier)
+	assert.Equal(t, "pkg:maven/org.lwjgl/lwjgl@3.3.1?classifier=natives-linux", lwjgl.PackageURL)
+	assert.Nil(t, lwjgl.CheckSum)
+	assert.NotEmpty(t, lwjgl.Unresolved)
+}
+
+func TestListModulesWithDepsCLI(t *testing.T) {
+	defer stubDependencyTree(t, "testdata/deps/deps-tree.txt")()
+
+	plugin := New()
+	assert.NoError(t, plugin.SetRootModule(filepath.Join("testdata", "deps")))
+	assert.Equal(t, "clojure", plugin.GetExecutable())
+
+	modules, err := plugin.ListModulesWithDeps(filepath.Join("testdata", "deps"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 9)
+	assert.Equal(t, "deps", modules[0].Name)
+	assert.Empty(t, modules[0].PackageURL)
+	assert.Contains(t, modules[0].Modules, "ring-core")
+}
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, New().IsValid(filepath.Join("testdata", "lein")))
+	assert.True(t, New().IsValid(filepath.Join("testdata", "deps")))
+	assert.False(t, New().IsValid("testdata"))
+}
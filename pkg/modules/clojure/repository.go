@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package clojure
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	mavenCentralURL = "https://repo1.maven.org/maven2/"
+	clojarsURL      = "https://repo.clojars.org/"
+)
+
+// remoteRepositories are the urls of the repositories Leiningen and the Clojure CLI know by default
+var remoteRepositories = map[string]string{
+	"central": mavenCentralURL,
+	"clojars": clojarsURL,
+}
+
+// localRepository returns the Maven local repository both Leiningen and the Clojure CLI download to
+func localRepository() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// artifactDir returns the directory of the artifact in a Maven repository
+func artifactDir(a artifact) string {
+	return filepath.Join(filepath.FromSlash(strings.ReplaceAll(a.GroupID, ".", "/")), a.ArtifactID, a.Version)
+}
+
+// jarName returns the file name of the jar of the artifact
+func jarName(a artifact) string {
+	name := a.ArtifactID + "-" + a.Version
+	if a.Classifier != "" {
+		name += "-" + a.Classifier
+	}
+	return name + ".jar"
+}
+
+// jarChecksum returns the hex SHA1 of the jar of the artifact in the local repository
+func jarChecksum(repository string, a artifact) (string, error) {
+	file, err := os.Open(filepath.Join(repository, artifactDir(a), jarName(a)))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// remoteRepository returns the id of the repository the jar was downloaded from, as recorded in the
+// _remote.repositories file of the local repository, e.g. clojars. It is empty when not recorded
+func remoteRepository(repository string, a artifact) string {
+	file, err := os.Open(filepath.Join(repository, artifactDir(a), "_remote.repositories"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	// the lines are <file>><repository id>=
+	prefix := jarName(a) + ">"
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSuffix(strings.TrimPrefix(line, prefix), "=")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,6 @@
+(defproject org.example/hello "0.1.0-SNAPSHOT"
+  :description "Hello"
+  :dependencies [[org.clojure/clojure "1.10.3"]
+                 [ring/ring-core "1.9.4"]
+                 [org.lwjgl/lwjgl "3.3.1" :classifier "natives-linux"]]
+  :main hello.core)
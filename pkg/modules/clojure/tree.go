@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package clojure
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+// leinTreeRegex matches a dependency of lein deps :tree, e.g. [ring/ring-core "1.9.4" :exclusions [...]],
+// every level of the tree is indented by two more spaces
+var leinTreeRegex = regexp.MustCompile(`^(\s*)\[(\S+)\s+"([^"]+)"(.*)\]\s*$`)
+
+// leinClassifierRegex matches the classifier option of a lein dependency
+var leinClassifierRegex = regexp.MustCompile(`:classifier\s+"([^"]+)"`)
+
+// cliTreeRegex matches a dependency of clojure -Stree, e.g. "  . ring/ring-codec 1.2.0". The dependencies
+// of the dependencies are marked with . when included and X when excluded, e.g. for an older version
+var cliTreeRegex = regexp.MustCompile(`^(\s*)(?:([.X])\s+)?([\w.\-]+(?:/[\w.\-]+)?(?:\$[\w.\-]+)?)\s+(\S+)`)
+
+// artifact is a Maven coordinate of the dependency tree
+type artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string
+}
+
+func (a artifact) key() string {
+	key := a.GroupID + ":" + a.ArtifactID + ":" + a.Version
+	if a.Classifier != "" {
+		key += ":" + a.Classifier
+	}
+	return key
+}
+
+// newArtifact returns the artifact of a Clojure library name, group/artifact or artifact alone when
+// both are the same. The Clojure CLI appends the classifier with $, e.g. org.lwjgl/lwjgl$natives-linux
+func newArtifact(lib string, version string, classifier string) artifact {
+	if index := strings.Index(lib, "$"); index >= 0 {
+		lib, classifier = lib[:index], lib[index+1:]
+	}
+
+	groupID, artifactID := lib, lib
+	if index := strings.Index(lib, "/"); index >= 0 {
+		groupID, artifactID = lib[:index], lib[index+1:]
+	}
+	return artifact{GroupID: groupID, ArtifactID: artifactID, Version: version, Classifier: classifier}
+}
+
+// dependencyTree is the parsed dependency tree of the project. Artifacts are the dependencies in the
+// order they are first listed, Direct the keys of the ones the project declares and Dependencies the
+// direct dependencies of every artifact by its key
+type dependencyTree struct {
+	Artifacts    []artifact
+	Direct       []string
+	Dependencies map[string][]string
+}
+
+// treeNode is a dependency of the tree at its depth, the ones the project declares are at depth 0
+type treeNode struct {
+	artifact
+	depth    int
+	excluded bool
+}
+
+// parseLeinTree parses the output of lein deps :tree
+func parseLeinTree(out []byte) dependencyTree {
+	var nodes []treeNode
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		match := leinTreeRegex.FindStringSubmatch(sc.Text())
+		if match == nil {
+			continue
+		}
+
+		classifier := ""
+		if options := leinClassifierRegex.FindStringSubmatch(match[4]); options != nil {
+			classifier = options[1]
+		}
+		nodes = append(nodes, treeNode{
+			artifact: newArtifact(match[2], match[3], classifier),
+			// the dependencies of the project are indented by one space
+			depth: (len(match[1]) - 1) / 2,
+		})
+	}
+	return buildTree(nodes)
+}
+
+// parseCLITree parses the output of clojure -Stree. Dependencies resolved from git or a local
+// directory have a sha or path for version, they are listed as is
+func parseCLITree(out []byte) dependencyTree {
+	var nodes []treeNode
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		match := cliTreeRegex.FindStringSubmatch(sc.Text())
+		if match == nil {
+			continue
+		}
+
+		nodes = append(nodes, treeNode{
+			artifact: newArtifact(match[3], match[4], ""),
+			depth:    len(match[1]) / 2,
+			excluded: match[2] == "X",
+		})
+	}
+	return buildTree(nodes)
+}
+
+// buildTree links the nodes to the closest node above them one level up. An excluded node is not
+// on the classpath, it is skipped with the nodes below it
+func buildTree(nodes []treeNode) dependencyTree {
+	tree := dependencyTree{Dependencies: map[string][]string{}}
+	seen := map[string]bool{}
+
+	var parents []string
+	excludedDepth := -1
+	for _, node := range nodes {
+		if excludedDepth >= 0 && node.depth > excludedDepth {
+			continue
+		}
+		excludedDepth = -1
+		if node.excluded {
+			excludedDepth = node.depth
+			continue
+		}
+		if node.depth > len(parents) {
+			// a node deeper than the last one with no parent listed, the output is not a tree
+			continue
+		}
+
+		key := node.key()
+		parents = parents[:node.depth]
+		if node.depth == 0 {
+			tree.Direct = helper.AppendUnique(tree.Direct, key)
+		} else {
+			parent := parents[node.depth-1]
+			tree.Dependencies[parent] = helper.AppendUnique(tree.Dependencies[parent], key)
+		}
+		parents = append(parents, key)
+
+		if !seen[key] {
+			seen[key] = true
+			tree.Artifacts = append(tree.Artifacts, node.artifact)
+		}
+	}
+
+	return tree
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package clojure
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLeinTree(t *testing.T) {
+	out, err := ioutil.ReadFile("testdata/lein/deps-tree.txt")
+	assert.NoError(t, err)
+
+	tree := parseLeinTree(out)
+	assert.Len(t, tree.Artifacts, 11)
+	assert.Equal(t, artifact{GroupID: "clojure-complete", ArtifactID: "clojure-complete", Version: "0.2.5"}, tree.Artifacts[0])
+	assert.Equal(t, artifact{GroupID: "org.lwjgl", ArtifactID: "lwjgl", Version: "3.3.1", Classifier: "natives-linux"}, tree.Artifacts[5])
+
+	assert.Equal(t, []string{
+		"clojure-complete:clojure-complete:0.2.5",
+		"nrepl:nrepl:0.8.3",
+		"org.clojure:clojure:1.10.3",
+		"org.lwjgl:lwjgl:3.3.1:natives-linux",
+		"ring:ring-core:1.9.4",
+	}, tree.Direct)
+	assert.Equal(t, []string{"commons-fileupload:commons-fileupload:1.4", "ring:ring-codec:1.1.3"}, tree.Dependencies["ring:ring-core:1.9.4"])
+	assert.Equal(t, []string{"commons-io:commons-io:2.6"}, tree.Dependencies["commons-fileupload:commons-fileupload:1.4"])
+}
+
+func TestParseCLITree(t *testing.T) {
+	out, err := ioutil.ReadFile("testdata/deps/deps-tree.txt")
+	assert.NoError(t, err)
+
+	tree := parseCLITree(out)
+	assert.Equal(t, []string{"org.clojure:clojure:1.11.1", "ring:ring-core:1.9.6"}, tree.Direct)
+	// the excluded commons-fileupload is skipped with the commons-io it depends on
+	assert.Equal(t, []string{
+		"ring:ring-codec:1.2.0",
+		"commons-io:commons-io:2.11.0",
+		"crypto-random:crypto-random:1.2.1",
+	}, tree.Dependencies["ring:ring-core:1.9.6"])
+	assert.Equal(t, []string{"commons-codec:commons-codec:1.15"}, tree.Dependencies["ring:ring-codec:1.2.0"])
+	assert.Len(t, tree.Artifacts, 8)
+}
+
+func TestNewArtifact(t *testing.T) {
+	assert.Equal(t, artifact{GroupID: "nrepl", ArtifactID: "nrepl", Version: "0.8.3"}, newArtifact("nrepl", "0.8.3", ""))
+	assert.Equal(t, artifact{GroupID: "org.lwjgl", ArtifactID: "lwjgl", Version: "3.3.1", Classifier: "natives-linux"},
+		newArtifact("org.lwjgl/lwjgl$natives-linux", "3.3.1", ""))
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cocoapods
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no Podfile.lock found. Please install the pods before running spdx-sbom-generator, e.g.: `pod install`")
+)
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cocoapods
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type cocoapods struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	LockFile    string = "Podfile.lock"
+	PodsDir     string = "Pods"
+	podspecsDir string = "Local Podspecs"
+)
+
+// New creates a new cocoapods instance
+func New() *cocoapods {
+	return &cocoapods{
+		metadata: models.PluginMetadata{
+			Name:       "CocoaPods",
+			Slug:       "cocoapods",
+			Manifest:   []string{LockFile},
+			ModulePath: []string{PodsDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *cocoapods) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the CocoaPods version
+func (m *cocoapods) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "pod", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *cocoapods) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *cocoapods) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the project as the root package, named after its directory
+func (m *cocoapods) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return rootModule(absPath, m.options.LicenseMatcher), nil
+}
+
+// ListUsedModules returns the pods of the project, without the project itself
+func (m *cocoapods) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the pods of its Podfile.lock, linked to the pods
+// they depend on
+func (m *cocoapods) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(absPath, LockFile)
+	if !helper.Exists(lockPath) {
+		return nil, errDependenciesNotFound
+	}
+	lock, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	return lockModules(absPath, rootModule(absPath, m.options.LicenseMatcher), lock, m.options.LicenseMatcher), nil
+}
+
+// IsValid checks if a Podfile.lock exists
+func (m *cocoapods) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks the pods are locked, the Pods directory is only read for the licenses
+func (m *cocoapods) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
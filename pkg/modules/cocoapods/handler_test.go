@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cocoapods
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "app")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "app"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 9) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, []string{"Alamofire", "Charts", "Firebase/Analytics", "Firebase/Core", "Firebase/CoreOnly", "MyKit"}, modulestest.LinkedNames(root))
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	alamofire := byName["Alamofire"]
+	assert.Equal(t, "pkg:cocoapods/Alamofire@5.8.1", alamofire.PackageURL)
+	assert.Equal(t, "NOASSERTION", alamofire.PackageDownloadLocation)
+	assert.Equal(t, models.HashAlgoSHA1, alamofire.CheckSum.Algorithm)
+	assert.Equal(t, "3ca42e259043ee0dc5c0cdd76c4bc568b8e42af7", alamofire.CheckSum.Value)
+	assert.Equal(t, "MIT", alamofire.LicenseDeclared)
+
+	analytics := byName["Firebase/Analytics"]
+	assert.Equal(t, "pkg:cocoapods/Firebase@10.15.0#Analytics", analytics.PackageURL)
+	assert.Equal(t, "66043bd4579e5b73811f96829c694c7af8d67435", analytics.CheckSum.Value)
+	assert.Equal(t, []string{"Firebase/Core"}, modulestest.LinkedNames(analytics))
+
+	charts := byName["Charts"]
+	assert.Equal(t, "git+https://github.com/danielgindi/Charts.git@v4.1.0", charts.PackageDownloadLocation)
+	assert.Contains(t, charts.PackageURL, "vcs_url=")
+	assert.Equal(t, []string{"Charts/Core"}, modulestest.LinkedNames(charts))
+	assert.Equal(t, []string{"SwiftAlgorithms"}, modulestest.LinkedNames(byName["Charts/Core"]))
+
+	myKit := byName["MyKit"]
+	assert.Equal(t, "NOASSERTION", myKit.PackageDownloadLocation)
+	assert.Equal(t, "Apache-2.0", myKit.LicenseDeclared)
+	assert.Equal(t, "https://acme.example.com/mykit", myKit.PackageHomePage)
+	assert.Len(t, myKit.Annotations, 1)
+	assert.Equal(t, []string{"Alamofire"}, modulestest.LinkedNames(myKit))
+
+	algorithms := byName["SwiftAlgorithms"]
+	assert.Equal(t, []string{"the pod is published in the spec repo https://github.com/acme/Specs.git"}, algorithms.Annotations)
+}
+
+func TestLockModulesWithoutDependencies(t *testing.T) {
+	lock := &podfileLock{
+		Pods: []*lockedPod{
+			{Name: "Kingfisher", Version: "7.9.1", Dependencies: []string{"Alamofire"}},
+			{Name: "Alamofire", Version: "5.8.1"},
+		},
+		SpecChecksums: map[string]string{},
+	}
+	modules := lockModules(t.TempDir(), rootModule(t.TempDir(), nil), lock, nil)
+	assert.Equal(t, []string{"Kingfisher"}, modulestest.LinkedNames(modules[0]))
+	assert.Nil(t, modules[2].CheckSum)
+}
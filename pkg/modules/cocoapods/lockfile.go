@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cocoapods
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the options of an external source, a pod fetched from a repository or a directory instead of a spec repo
+const (
+	optionGit     = ":git"
+	optionPath    = ":path"
+	optionPodspec = ":podspec"
+	optionCommit  = ":commit"
+	optionTag     = ":tag"
+	optionBranch  = ":branch"
+)
+
+// trunkRepo is the name of the public spec repo in the SPEC REPOS of a lockfile
+const trunkRepo = "trunk"
+
+// lockedPod is a pod of the PODS of a lockfile, a subspec is listed as its own pod, e.g. Firebase/Analytics
+type lockedPod struct {
+	Name    string
+	Version string
+	// Dependencies are the names of the pods it depends on
+	Dependencies []string
+}
+
+// podfileLock is a Podfile.lock
+type podfileLock struct {
+	Pods []*lockedPod
+	// Dependencies are the names of the pods the Podfile requires
+	Dependencies []string
+	// SpecRepos are the spec repos of the pods, keyed by the name of their root spec
+	SpecRepos map[string]string
+	// ExternalSources are the options of the pods of an external source, keyed by the name of their root spec
+	ExternalSources map[string]map[string]string
+	// CheckoutOptions are the options the external sources were fetched with, e.g. the :commit of a :git one
+	CheckoutOptions map[string]map[string]string
+	// SpecChecksums are the sha1 hashes of the podspecs, keyed by the name of their root spec
+	SpecChecksums   map[string]string
+	PodfileChecksum string
+	// Version is the version of CocoaPods writing the lockfile
+	Version string
+}
+
+func readLockfile(path string) (*podfileLock, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseLockfile(path, file)
+}
+
+// parseLockfile reads a Podfile.lock, its PODS are listed as `Name (version)` and followed by the
+// requirements of their dependencies when they have some:
+//
+//	PODS:
+//	  - Firebase/Analytics (10.15.0):
+//	    - Firebase/Core
+//	  - Firebase/Core (10.15.0)
+func parseLockfile(fileName string, r io.Reader) (*podfileLock, error) {
+	document, err := reader.ParseYAML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &podfileLock{
+		SpecRepos:       map[string]string{},
+		ExternalSources: options(document.Get("EXTERNAL SOURCES")),
+		CheckoutOptions: options(document.Get("CHECKOUT OPTIONS")),
+		SpecChecksums:   map[string]string{},
+		PodfileChecksum: document.Str("PODFILE CHECKSUM"),
+		Version:         document.Str("COCOAPODS"),
+	}
+
+	for _, item := range document.List("PODS") {
+		entry, dependencies := item.Value, []*reader.YAMLNode(nil)
+		if len(item.Keys) == 1 {
+			entry, dependencies = item.Keys[0], item.Fields[item.Keys[0]].Items
+		}
+		name, version := parsePodLine(entry)
+		if name == "" || version == "" {
+			return nil, reader.MalformedError(fileName, item.Line, fmt.Sprintf("unexpected pod %q", entry))
+		}
+
+		pod := &lockedPod{Name: name, Version: version}
+		for _, dependency := range dependencies {
+			dependencyName, _ := parsePodLine(dependency.Value)
+			pod.Dependencies = append(pod.Dependencies, dependencyName)
+		}
+		lock.Pods = append(lock.Pods, pod)
+	}
+
+	for _, item := range document.List("DEPENDENCIES") {
+		name, _ := parsePodLine(item.Value)
+		lock.Dependencies = append(lock.Dependencies, name)
+	}
+
+	repos := document.Get("SPEC REPOS")
+	for _, repo := range repos.Keys {
+		for _, pod := range repos.Fields[repo].Items {
+			lock.SpecRepos[pod.Value] = repo
+		}
+	}
+
+	checksums := document.Get("SPEC CHECKSUMS")
+	for _, name := range checksums.Keys {
+		lock.SpecChecksums[name] = checksums.Fields[name].Value
+	}
+	return lock, nil
+}
+
+// options returns the options of the pods of an EXTERNAL SOURCES or a CHECKOUT OPTIONS section
+func options(section *reader.YAMLNode) map[string]map[string]string {
+	result := map[string]map[string]string{}
+	if section == nil {
+		return result
+	}
+	for _, name := range section.Keys {
+		values := map[string]string{}
+		for _, option := range section.Fields[name].Keys {
+			values[option] = section.Fields[name].Fields[option].Value
+		}
+		result[name] = values
+	}
+	return result
+}
+
+// parsePodLine reads a pod line: name (version), a dependency line holds a requirement instead,
+// e.g. Alamofire (~> 5.8), or none and a DEPENDENCIES one may hold its source, e.g. MyKit (from `../MyKit`)
+func parsePodLine(value string) (name, version string) {
+	value = strings.TrimSpace(value)
+	open := strings.Index(value, " (")
+	if open < 0 || !strings.HasSuffix(value, ")") {
+		return value, ""
+	}
+	return value[:open], strings.TrimSpace(value[open+2 : len(value)-1])
+}
+
+// rootSpec returns the name of the root spec of a pod, e.g. Firebase for Firebase/Analytics
+func rootSpec(name string) string {
+	return strings.SplitN(name, "/", 2)[0]
+}
+
+// subspec returns the path of a subspec in its root spec, e.g. Analytics for Firebase/Analytics, "" for a root spec
+func subspec(name string) string {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cocoapods
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadLockfile(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "app", LockFile))
+	assert.NoError(t, err)
+	if !assert.Len(t, lock.Pods, 8) {
+		return
+	}
+
+	charts := lock.Pods[1]
+	assert.Equal(t, "Charts", charts.Name)
+	assert.Equal(t, "4.1.0", charts.Version)
+	assert.Equal(t, []string{"Charts/Core"}, charts.Dependencies)
+	assert.Equal(t, "Firebase/Analytics", lock.Pods[3].Name)
+	assert.Empty(t, lock.Pods[0].Dependencies)
+
+	assert.Equal(t, []string{"Alamofire", "Charts", "Firebase", "MyKit"}, lock.Dependencies)
+	assert.Equal(t, "trunk", lock.SpecRepos["Firebase"])
+	assert.Equal(t, "https://github.com/acme/Specs.git", lock.SpecRepos["SwiftAlgorithms"])
+	assert.Equal(t, "MyKit", lock.ExternalSources["MyKit"][optionPath])
+	assert.Equal(t, "v4.1.0", lock.CheckoutOptions["Charts"][optionTag])
+	assert.Equal(t, "66043bd4579e5b73811f96829c694c7af8d67435", lock.SpecChecksums["Firebase"])
+	assert.Equal(t, "9b1c8c7b2e6f4a5d3e2f1a0b9c8d7e6f5a4b3c2d", lock.PodfileChecksum)
+	assert.Equal(t, "1.12.1", lock.Version)
+}
+
+func TestParseLockfileMalformed(t *testing.T) {
+	_, err := parseLockfile(LockFile, strings.NewReader("PODS:\n  - Alamofire\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestParsePodLine(t *testing.T) {
+	name, version := parsePodLine("Firebase/Analytics (10.15.0)")
+	assert.Equal(t, "Firebase/Analytics", name)
+	assert.Equal(t, "10.15.0", version)
+
+	name, version = parsePodLine("MyKit (from `MyKit`)")
+	assert.Equal(t, "MyKit", name)
+	assert.Equal(t, "from `MyKit`", version)
+
+	name, version = parsePodLine("Firebase/Core")
+	assert.Equal(t, "Firebase/Core", name)
+	assert.Empty(t, version)
+
+	assert.Equal(t, "Firebase", rootSpec("Firebase/Core"))
+	assert.Equal(t, "Core", subspec("Firebase/Core"))
+	assert.Empty(t, subspec("Firebase"))
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cocoapods
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// localPodspec is the part of a podspec CocoaPods stores in Pods/Local Podspecs for the pods of an
+// external source, the license is either its type or an object with a type and a file
+type localPodspec struct {
+	License  json.RawMessage `json:"license"`
+	Homepage string          `json:"homepage"`
+}
+
+// rootModule returns the package of the project, with the license of the project files
+func rootModule(path string, matcher helper.LicenseMatcher) *models.Module {
+	name := filepath.Base(path)
+	mod := &models.Module{
+		Name:                    name,
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(name),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	helper.SetLicense(matcher, path, mod)
+	return mod
+}
+
+// lockModules returns the root followed by the pods of the lockfile. The root depends on the pods the
+// Podfile requires, or else on the ones no pod depends on
+func lockModules(path string, root *models.Module, lock *podfileLock, matcher helper.LicenseMatcher) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	for _, pod := range lock.Pods {
+		index[pod.Name] = len(modules)
+		modules = append(modules, podModule(path, lock, pod, matcher))
+	}
+
+	dependencies := map[int]bool{}
+	for _, pod := range lock.Pods {
+		for _, name := range pod.Dependencies {
+			for _, i := range lookupPod(index, name) {
+				helper.LinkModule(modules, index[pod.Name], i, "")
+				dependencies[i] = true
+			}
+		}
+	}
+
+	var direct []int
+	if len(lock.Dependencies) > 0 {
+		for _, name := range lock.Dependencies {
+			direct = append(direct, lookupPod(index, name)...)
+		}
+	} else {
+		for i := 1; i < len(modules); i++ {
+			if !dependencies[i] {
+				direct = append(direct, i)
+			}
+		}
+	}
+	for _, i := range direct {
+		helper.LinkModule(modules, 0, i, "")
+	}
+	return modules
+}
+
+// lookupPod returns the indexes of the pods a dependency names: the pod itself, else the subspecs of
+// the root spec it names, the lockfile lists the subspecs of a root spec depended on without its own pod
+func lookupPod(index map[string]int, name string) []int {
+	if i, ok := index[name]; ok {
+		return []int{i}
+	}
+	var indexes []int
+	for podName, i := range index {
+		if strings.HasPrefix(podName, name+"/") {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// podModule returns the package of a locked pod: a pod of a spec repo, a pod fetched from a git repository
+// or a pod built from a directory of the project. The checksum is the sha1 hash of its podspec
+func podModule(path string, lock *podfileLock, pod *lockedPod, matcher helper.LicenseMatcher) models.Module {
+	name := rootSpec(pod.Name)
+	p := purl.New("cocoapods", "", name, pod.Version)
+	p.Subpath = subspec(pod.Name)
+
+	mod := models.Module{
+		Name:                    pod.Name,
+		Version:                 pod.Version,
+		Supplier:                models.SupplierContact{Name: name},
+		PackageDownloadLocation: "NOASSERTION",
+		Modules:                 map[string]*models.Module{},
+	}
+	if checksum := lock.SpecChecksums[name]; checksum != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: checksum}
+	}
+
+	external, checkout := lock.ExternalSources[name], lock.CheckoutOptions[name]
+	switch {
+	case external[optionGit] != "":
+		vcs := fmt.Sprintf("git+%s", external[optionGit])
+		if ref := nonEmpty(checkout[optionCommit], external[optionCommit], external[optionTag], external[optionBranch]); ref != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, ref)
+		}
+		p = p.WithQualifier("vcs_url", vcs)
+		mod.PackageDownloadLocation = vcs
+		mod.LocalPath = filepath.Join(path, PodsDir, name)
+	case external[optionPath] != "":
+		mod.LocalPath = filepath.Join(path, filepath.FromSlash(external[optionPath]))
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the pod is built from the directory %s", external[optionPath]))
+	case external[optionPodspec] != "":
+		mod.LocalPath = filepath.Join(path, PodsDir, name)
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the pod is specified by the podspec %s", external[optionPodspec]))
+	default:
+		mod.LocalPath = filepath.Join(path, PodsDir, name)
+		if repo := lock.SpecRepos[name]; repo != "" && repo != trunkRepo {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the pod is published in the spec repo %s", repo))
+		}
+	}
+	mod.PackageURL = p.String()
+
+	if len(external) > 0 {
+		setPodspecMetadata(filepath.Join(path, PodsDir, podspecsDir, name+".podspec.json"), &mod)
+	}
+	if mod.LicenseDeclared == "" && helper.Exists(mod.LocalPath) {
+		helper.SetLicense(matcher, mod.LocalPath, &mod)
+	}
+	return mod
+}
+
+// setPodspecMetadata sets the license and the home page of the podspec CocoaPods stored for a pod of an
+// external source
+func setPodspecMetadata(file string, mod *models.Module) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return
+	}
+	spec := localPodspec{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return
+	}
+
+	mod.PackageHomePage = spec.Homepage
+	var license string
+	if err := json.Unmarshal(spec.License, &license); err != nil {
+		object := struct {
+			Type string `json:"type"`
+		}{}
+		if err := json.Unmarshal(spec.License, &object); err == nil {
+			license = object.Type
+		}
+	}
+	if license = strings.TrimSpace(license); license == "" {
+		return
+	}
+	expression := helper.SPDXExpression(license)
+	if expression == "" {
+		expression = helper.BuildLicenseDeclared(license)
+	}
+	mod.LicenseDeclared = expression
+	mod.LicenseConcluded = expression
+}
+
+// nonEmpty returns the first of the values that is not blank, "" when they all are
+func nonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
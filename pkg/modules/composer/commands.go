@@ -36,6 +36,7 @@ func (m *composer) buildCmd(cmd command, path string) error {
 		Name:      cmdArgs[0],
 		Args:      cmdArgs[1:],
 		Directory: path,
+		Context:   m.options.Context,
 	})
 
 	m.command = command
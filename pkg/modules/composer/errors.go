@@ -9,7 +9,11 @@ import (
 type errType error
 
 var errDependenciesNotFound = errors.New("no dependencies installed. Please install Modules before running spdx-sbom-generator, e.g.: `composer install`")
+var errLockfileNotFound = errors.New("no composer.lock found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `composer update`")
 var errNoComposerCommand = errors.New("no Composer command")
 var errFailedToReadComposerFile errType = errors.New("Failed to read composer lock files")
 var errFailedToShowComposerTree errType = errors.New("Failed to show composer tree")
 var errRootProject errType = errors.New("Failed to read root project info")
+var errRepositoryUnreachable errType = errors.New("repository unreachable")
+var errUnexpectedStatus errType = errors.New("unexpected repository response")
+var errPackageNotFound errType = errors.New("package not found in the repositories")
@@ -3,6 +3,8 @@
 package composer
 
 import (
+	"context"
+	"log"
 	"path/filepath"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
@@ -12,6 +14,7 @@ import (
 type composer struct {
 	metadata models.PluginMetadata
 	command  *helper.Cmd
+	options  models.PluginOptions
 }
 
 // New ...
@@ -31,6 +34,11 @@ func (m *composer) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
+// SetOptions ...
+func (m *composer) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // IsValid ...
 func (m *composer) IsValid(path string) bool {
 	for i := range m.metadata.Manifest {
@@ -41,8 +49,14 @@ func (m *composer) IsValid(path string) bool {
 	return false
 }
 
-// HasModulesInstalled ...
+// HasModulesInstalled checks the packages are installed, or locked in the lockfile-only mode
 func (m *composer) HasModulesInstalled(path string) error {
+	if m.options.LockfileOnly {
+		if helper.Exists(filepath.Join(path, COMPOSER_LOCK_FILE_NAME)) {
+			return nil
+		}
+		return errLockfileNotFound
+	}
 	for i := range m.metadata.ModulePath {
 		if helper.Exists(filepath.Join(path, m.metadata.ModulePath[i])) {
 			return nil
@@ -82,6 +96,19 @@ func (m *composer) ListUsedModules(path string) ([]models.Module, error) {
 		return nil, errFailedToReadComposerFile
 	}
 
+	if m.options.AllowNetwork && !m.options.Offline {
+		if err := m.fetchMetadata(path, modules); err != nil {
+			log.Println(err)
+		}
+	}
+
+	if m.options.LockfileOnly {
+		if err := linkLockedRequirements(path, modules); err != nil {
+			return nil, errFailedToReadComposerFile
+		}
+		return modules, nil
+	}
+
 	treeList, err := m.getTreeListFromComposerShowTree(path)
 	if err != nil {
 		return nil, errFailedToShowComposerTree
@@ -93,3 +120,27 @@ func (m *composer) ListUsedModules(path string) ([]models.Module, error) {
 
 	return modules, nil
 }
+
+// fetchMetadata sets the licenses and the download locations missing from the lockfile to the ones published
+// by the composer repositories of composer.json and Packagist, authenticating with the auth.json credentials
+func (m *composer) fetchMetadata(path string, modules []models.Module) error {
+	composerJSON, err := getComposerJSONFileData(path)
+	if err != nil {
+		return err
+	}
+	auth, err := readAuth(path)
+	if err != nil {
+		return err
+	}
+
+	fetcher := newMetadataFetcher(repositories(composerJSON.Repositories, auth))
+	fetcher.enrich(m.context(), modules, m.options.Cache)
+	return nil
+}
+
+func (m *composer) context() context.Context {
+	if m.options.Context == nil {
+		return context.Background()
+	}
+	return m.options.Context
+}
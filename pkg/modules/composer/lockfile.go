@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// lockfileRootModule returns the project of the composer.json, as composer show -s does, for the lockfile-only
+// mode. The project is named after its directory when composer.json does not name it
+func (m *composer) lockfileRootModule(path string) (models.Module, error) {
+	composerJSON, err := getComposerJSONFileData(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+
+	project := ComposerProjectInfo{Name: composerJSON.Name, Versions: []string{composerJSON.Version}}
+	if project.Name == "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return models.Module{}, err
+		}
+		project.Name = filepath.Base(absPath)
+	}
+
+	module, err := m.convertProjectInfoToModule(project, path)
+	if err != nil {
+		return models.Module{}, err
+	}
+	module.Modules = map[string]*models.Module{}
+	return module, nil
+}
+
+// linkLockedRequirements links the packages to the ones they require, as composer.lock records them, and the
+// root to the packages composer.json requires, the require-dev ones being DEV_DEPENDENCY_OF it. The platform
+// requirements, e.g. php or ext-json, are not packages of the lockfile and are skipped
+func linkLockedRequirements(path string, modules []models.Module) error {
+	lock, err := getComposerLockFileData(path)
+	if err != nil {
+		return err
+	}
+	composerJSON, err := getComposerJSONFileData(path)
+	if err != nil {
+		return err
+	}
+
+	index := map[string]int{}
+	for i := 1; i < len(modules); i++ {
+		index[modules[i].Name] = i
+	}
+	link := func(parent int, requirements map[string]string, relationship models.RelationshipType) {
+		for _, name := range sortedNames(requirements) {
+			child, ok := index[getName(name)]
+			if !ok {
+				continue
+			}
+			addSubModuleToAModule(modules, parent, modules[child])
+			modules[parent].Modules[modules[child].Name].Relationship = relationship
+		}
+	}
+
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		if parent, ok := index[getName(pkg.Name)]; ok {
+			link(parent, pkg.Require, "")
+		}
+	}
+	link(0, composerJSON.Require, "")
+	link(0, composerJSON.RequireDev, models.RelationshipDevDependencyOf)
+	return nil
+}
+
+// sortedNames returns the names of the requirements in order
+func sortedNames(requirements map[string]string) []string {
+	names := make([]string, 0, len(requirements))
+	for name := range requirements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
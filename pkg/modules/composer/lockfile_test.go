@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestLockfileOnly(t *testing.T) {
+	m := New()
+	m.SetOptions(models.PluginOptions{LockfileOnly: true})
+
+	path := filepath.Join("testdata", "locked")
+	assert.NoError(t, m.HasModulesInstalled(path))
+	assert.Equal(t, errLockfileNotFound, m.HasModulesInstalled(filepath.Join("testdata", "private")))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 4) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "2.1.0", root.Version)
+	if assert.Len(t, root.Modules, 2) {
+		assert.Equal(t, models.RelationshipType(""), root.Modules["monolog"].Relationship)
+		assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["phpunit"].Relationship)
+	}
+
+	monolog := modules[1]
+	assert.Equal(t, "monolog", monolog.Name)
+	assert.Equal(t, "MIT", monolog.LicenseDeclared)
+	assert.Len(t, monolog.Modules, 1)
+	assert.Contains(t, monolog.Modules, "log")
+	assert.Empty(t, modules[2].Modules)
+}
@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// minifiedFormat is the format of the metadata-url files whose versions only list the fields changed
+// since the previous one, a field set to __unset being removed
+const minifiedFormat = "composer/2.0"
+
+// repositoryRoot is the packages.json of a composer repository
+type repositoryRoot struct {
+	// MetadataURL is the url of the versions of a package, its %package% replaced by the package name
+	MetadataURL string     `json:"metadata-url"`
+	Packages    packageMap `json:"packages"`
+	// Includes are the files listing more packages, keyed by their url relative to the repository
+	Includes map[string]json.RawMessage `json:"includes"`
+}
+
+// repositoryPackages are the packages of a packages.json, an include or a metadata-url file
+type repositoryPackages struct {
+	Packages packageMap `json:"packages"`
+	Minified string     `json:"minified"`
+}
+
+// packageMap are the versions of the packages keyed by name, an empty one is written as a list
+type packageMap map[string]json.RawMessage
+
+// UnmarshalJSON ...
+func (p *packageMap) UnmarshalJSON(data []byte) error {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil && len(list) == 0 {
+		*p = packageMap{}
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]json.RawMessage)(p))
+}
+
+// repositoryPackage is a version of a package published by a repository
+type repositoryPackage struct {
+	Name     string                  `json:"name"`
+	Version  string                  `json:"version"`
+	License  []string                `json:"license"`
+	Homepage string                  `json:"homepage"`
+	Dist     ComposerLockPackageDist `json:"dist"`
+}
+
+// metadataFetcher reads the versions of the packages published by the composer repositories
+type metadataFetcher struct {
+	client       *http.Client
+	repositories []composerRepository
+
+	// files are the downloaded files of the repositories keyed by url, the packages.json and the includes
+	// are downloaded once
+	files map[string][]byte
+}
+
+func newMetadataFetcher(repositories []composerRepository) *metadataFetcher {
+	return &metadataFetcher{
+		client:       &http.Client{Timeout: 30 * time.Second},
+		repositories: repositories,
+		files:        map[string][]byte{},
+	}
+}
+
+// fetchPackage returns the version of the package published by the first repository serving it
+func (f *metadataFetcher) fetchPackage(ctx context.Context, name, version string) (repositoryPackage, error) {
+	for _, repository := range f.repositories {
+		if !repository.serves(name) {
+			continue
+		}
+		versions, err := f.packageVersions(ctx, repository, name)
+		if err != nil {
+			// never log the credentials, only the repository url
+			log.Debugf("package %s not available from repository %s: %v", name, repository.URL, err)
+			continue
+		}
+		for _, pkg := range versions {
+			if normalizePackageVersion(pkg.Version) == normalizePackageVersion(version) {
+				return pkg, nil
+			}
+		}
+	}
+
+	return repositoryPackage{}, fmt.Errorf("%w: %s %s", errPackageNotFound, name, version)
+}
+
+// packageVersions returns the versions of the package published by the repository, read from its
+// metadata-url, from the packages of its packages.json or from the ones of its includes
+func (f *metadataFetcher) packageVersions(ctx context.Context, repository composerRepository, name string) ([]repositoryPackage, error) {
+	body, err := f.download(ctx, repository, "packages.json", true)
+	if err != nil {
+		return nil, err
+	}
+	var root repositoryRoot
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, err
+	}
+
+	if root.MetadataURL != "" {
+		body, err := f.download(ctx, repository, strings.Replace(root.MetadataURL, "%package%", name, -1), false)
+		if err == nil {
+			return decodeVersions(body, name)
+		}
+		if len(root.Packages) == 0 && len(root.Includes) == 0 {
+			return nil, err
+		}
+	}
+
+	if raw, ok := root.Packages[name]; ok {
+		return parseVersions(raw, "")
+	}
+	includes := make([]string, 0, len(root.Includes))
+	for include := range root.Includes {
+		includes = append(includes, include)
+	}
+	sort.Strings(includes)
+	for _, include := range includes {
+		body, err := f.download(ctx, repository, include, true)
+		if err != nil {
+			return nil, err
+		}
+		if versions, err := decodeVersions(body, name); err == nil && len(versions) > 0 {
+			return versions, nil
+		}
+	}
+	return nil, nil
+}
+
+// decodeVersions returns the versions of the package listed in the packages of a repository file
+func decodeVersions(body []byte, name string) ([]repositoryPackage, error) {
+	var file repositoryPackages
+	if err := json.Unmarshal(body, &file); err != nil {
+		return nil, err
+	}
+	raw, ok := file.Packages[name]
+	if !ok {
+		return nil, nil
+	}
+	return parseVersions(raw, file.Minified)
+}
+
+// parseVersions reads the versions of a package, either a list or an object keyed by version,
+// expanding the minified ones
+func parseVersions(raw json.RawMessage, minified string) ([]repositoryPackage, error) {
+	var list []map[string]interface{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var byVersion map[string]map[string]interface{}
+		if err := json.Unmarshal(raw, &byVersion); err != nil {
+			return nil, err
+		}
+		for _, version := range byVersion {
+			list = append(list, version)
+		}
+	}
+
+	if minified == minifiedFormat {
+		list = expandVersions(list)
+	}
+
+	versions := make([]repositoryPackage, 0, len(list))
+	for _, fields := range list {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var pkg repositoryPackage
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return nil, err
+		}
+		versions = append(versions, pkg)
+	}
+	return versions, nil
+}
+
+// expandVersions returns the full versions of a minified list, each version carrying the fields of the
+// previous one it does not change or unset
+func expandVersions(list []map[string]interface{}) []map[string]interface{} {
+	expanded := make([]map[string]interface{}, 0, len(list))
+	previous := map[string]interface{}{}
+	for _, fields := range list {
+		version := map[string]interface{}{}
+		for key, value := range previous {
+			version[key] = value
+		}
+		for key, value := range fields {
+			if value == "__unset" {
+				delete(version, key)
+				continue
+			}
+			version[key] = value
+		}
+		expanded = append(expanded, version)
+		previous = version
+	}
+	return expanded
+}
+
+// download returns the file at the path, or the url, of the repository. Only the files kept are
+// downloaded once
+func (f *metadataFetcher) download(ctx context.Context, repository composerRepository, path string, keep bool) ([]byte, error) {
+	fileURL, err := resolveURL(repository.URL, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if body, ok := f.files[fileURL]; ok {
+		return body, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	// the credentials of the repository are only sent to its own host, packages.json may point anywhere
+	if sameOrigin(repository.URL, fileURL) {
+		if repository.username != "" || repository.password != "" {
+			req.SetBasicAuth(repository.username, repository.password)
+		} else if repository.token != "" {
+			req.Header.Set("Authorization", "Bearer "+repository.token)
+		}
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, errRepositoryUnreachable
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", errUnexpectedStatus, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if keep {
+		f.files[fileURL] = body
+	}
+	return body, nil
+}
+
+// resolveURL returns the url of a repository file, the paths starting with / are relative to the
+// repository host and the others to the repository url
+func resolveURL(repositoryURL, path string) (string, error) {
+	base, err := url.Parse(repositoryURL + "/")
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// sameOrigin tells whether the file url has the scheme and the host of the repository url
+func sameOrigin(repositoryURL, fileURL string) bool {
+	repository, err := url.Parse(repositoryURL)
+	if err != nil {
+		return false
+	}
+	file, err := url.Parse(fileURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(repository.Scheme, file.Scheme) && strings.EqualFold(repository.Host, file.Host)
+}
+
+// enrich sets the license and the download location published by the repositories on the packages
+// of the lockfile missing them. The ones already in the cache are not fetched again
+func (f *metadataFetcher) enrich(ctx context.Context, modules []models.Module, c *cache.Cache) {
+	for i := range modules {
+		mod := &modules[i]
+		if mod.Root || mod.Path == "" || (mod.LicenseDeclared != "" && mod.PackageDownloadLocation != "") {
+			continue
+		}
+
+		vendor, name := mod.Path, ""
+		if parts := strings.SplitN(mod.Path, "/", 2); len(parts) == 2 {
+			vendor, name = parts[0], parts[1]
+		}
+		key := purl.New("composer", vendor, name, mod.Version).String()
+		entry, ok := c.Get(key)
+		if !ok || (entry.License == "" && entry.DownloadLocation == "") {
+			pkg, err := f.fetchPackage(ctx, mod.Path, mod.Version)
+			if err != nil {
+				log.Debug(err)
+				continue
+			}
+			entry.License = helper.LicenseAlternatives(pkg.License)
+			entry.DownloadLocation = pkg.Dist.URL
+			if entry.HomePage == "" {
+				entry.HomePage = pkg.Homepage
+			}
+			if err := c.Put(key, entry); err != nil {
+				log.Warnf("failed to write the resume cache: %v", err)
+			}
+		}
+
+		if mod.LicenseDeclared == "" && entry.License != "" {
+			mod.LicenseDeclared = entry.License
+			mod.LicenseConcluded = entry.License
+		}
+		if mod.PackageDownloadLocation == "" {
+			mod.PackageDownloadLocation = entry.DownloadLocation
+		}
+		if mod.PackageHomePage == "" {
+			mod.PackageHomePage = entry.HomePage
+		}
+	}
+}
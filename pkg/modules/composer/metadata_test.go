@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestEnrichFromPrivatePackagist(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "token" || password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/acme/packages.json":
+			w.Write([]byte(`{"metadata-url": "/acme/p2/%package%.json"}`))
+		case "/acme/p2/acme/widgets.json":
+			w.Write([]byte(`{"minified": "composer/2.0", "packages": {"acme/widgets": [
+				{"name": "acme/widgets", "version": "1.3.0", "license": ["MIT"], "homepage": "https://acme.example.com",
+				 "dist": {"type": "zip", "url": "https://repo.packagist.com/acme/dists/widgets-1.3.0.zip"}},
+				{"version": "v1.2.0", "homepage": "__unset",
+				 "dist": {"type": "zip", "url": "https://repo.packagist.com/acme/dists/widgets-1.2.0.zip"}}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	fetcher := newMetadataFetcher([]composerRepository{{URL: ts.URL + "/acme", username: "token", password: "s3cret"}})
+	modules := []models.Module{
+		{Name: "shop", Root: true},
+		{Name: "widgets", Version: "1.2.0", Path: "acme/widgets"},
+		{Name: "gadgets", Version: "1.0.0", Path: "acme/gadgets"},
+	}
+	fetcher.enrich(context.Background(), modules, nil)
+
+	assert.Equal(t, "MIT", modules[1].LicenseDeclared)
+	assert.Equal(t, "MIT", modules[1].LicenseConcluded)
+	assert.Equal(t, "https://repo.packagist.com/acme/dists/widgets-1.2.0.zip", modules[1].PackageDownloadLocation)
+	assert.Empty(t, modules[1].PackageHomePage)
+	assert.Empty(t, modules[2].LicenseDeclared)
+	assert.Empty(t, modules[2].PackageDownloadLocation)
+}
+
+func TestEnrichDoesNotSendCredentialsToOtherHosts(t *testing.T) {
+	var authorization []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"packages": {"acme/widgets": [{"name": "acme/widgets", "version": "1.2.0", "license": ["MIT"]}]}}`))
+	}))
+	defer other.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"metadata-url": "` + other.URL + `/p2/%package%.json"}`))
+	}))
+	defer ts.Close()
+
+	fetcher := newMetadataFetcher([]composerRepository{{URL: ts.URL, username: "token", password: "s3cret"}})
+	modules := []models.Module{{Name: "widgets", Version: "1.2.0", Path: "acme/widgets"}}
+	fetcher.enrich(context.Background(), modules, nil)
+
+	assert.Equal(t, "MIT", modules[0].LicenseDeclared)
+	assert.Equal(t, []string{""}, authorization)
+}
+
+func TestEnrichFromSatisIncludes(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer satis-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/packages.json":
+			w.Write([]byte(`{"packages": [], "includes": {"include/all$0a1b.json": {"sha1": "0a1b"}}}`))
+		case "/include/all$0a1b.json":
+			w.Write([]byte(`{"packages": {"acme/tools": {"2.0.1": {"name": "acme/tools", "version": "2.0.1",
+				"license": ["GPL-2.0-only", "MIT"], "dist": {"type": "tar", "url": "https://satis.example.com/dist/tools-2.0.1.tar"}}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	fetcher := newMetadataFetcher([]composerRepository{{URL: ts.URL, token: "satis-token"}})
+	modules := []models.Module{
+		{Name: "tools", Version: "2.0.1", Path: "acme/tools"},
+		{Name: "tools", Version: "2.0.1", Path: "acme/tools", LicenseDeclared: "Apache-2.0"},
+	}
+	fetcher.enrich(context.Background(), modules, nil)
+
+	assert.Equal(t, "GPL-2.0-only OR MIT", modules[0].LicenseDeclared)
+	assert.Equal(t, "https://satis.example.com/dist/tools-2.0.1.tar", modules[0].PackageDownloadLocation)
+	assert.Equal(t, "Apache-2.0", modules[1].LicenseDeclared)
+	// the packages.json and its include are downloaded once
+	assert.Equal(t, 2, requests)
+}
+
+func TestEnrichUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	fetcher := newMetadataFetcher([]composerRepository{{URL: ts.URL}})
+	_, err := fetcher.fetchPackage(context.Background(), "acme/widgets", "1.0.0")
+	assert.Error(t, err)
+}
+
+func TestExpandVersions(t *testing.T) {
+	var list []map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"name": "acme/widgets", "version": "2.0.0", "license": ["MIT"], "homepage": "https://acme.example.com"},
+		{"version": "1.0.0", "license": "__unset"}
+	]`), &list))
+
+	expanded := expandVersions(list)
+	assert.Len(t, expanded, 2)
+	assert.Equal(t, "acme/widgets", expanded[1]["name"])
+	assert.Equal(t, "https://acme.example.com", expanded[1]["homepage"])
+	assert.NotContains(t, expanded[1], "license")
+}
@@ -2,6 +2,8 @@
 
 package composer
 
+import "encoding/json"
+
 type ComposerLockFile struct {
 	Packages    []ComposerLockPackage
 	PackagesDev []ComposerLockPackage `json:"packages-dev"`
@@ -17,6 +19,8 @@ type ComposerLockPackage struct {
 	Source      ComposerLockPackageSource
 	Authors     []ComposerLockPackageAuthor
 	Homepage    string
+	// Require are the packages the package requires, keyed by name, with the platform ones, e.g. php
+	Require map[string]string
 }
 type ComposerLockPackageAuthor struct {
 	Name  string
@@ -58,11 +62,17 @@ type ComposerJSONObject struct {
 	Description string   `json:"description"`
 	Keywords    []string `json:"keywords"`
 	Homepage    string   `json:"homepage"`
-	License     string   `json:"license"`
-	Authors     []struct {
+	Version     string   `json:"version"`
+	// License is either a license or a list of them
+	License json.RawMessage `json:"license"`
+	Authors []struct {
 		Name  string `json:"name"`
 		Email string `json:"email"`
 	} `json:"authors"`
+	// Repositories is either a list of repositories or an object keyed by their name
+	Repositories json.RawMessage   `json:"repositories"`
+	Require      map[string]string `json:"require"`
+	RequireDev   map[string]string `json:"require-dev"`
 }
 
 type PackageJSONObject struct {
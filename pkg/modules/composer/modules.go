@@ -8,10 +8,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
 )
 
 func (m *composer) getRootProjectInfo(path string) (models.Module, error) {
@@ -35,7 +37,7 @@ func (m *composer) getRootProjectInfo(path string) (models.Module, error) {
 		return models.Module{}, errRootProject
 	}
 
-	module, err := convertProjectInfoToModule(projectInfo, path)
+	module, err := m.convertProjectInfoToModule(projectInfo, path)
 	if err != nil {
 		return models.Module{}, err
 	}
@@ -43,13 +45,13 @@ func (m *composer) getRootProjectInfo(path string) (models.Module, error) {
 	return module, nil
 }
 
-func convertProjectInfoToModule(project ComposerProjectInfo, path string) (models.Module, error) {
+func (m *composer) convertProjectInfoToModule(project ComposerProjectInfo, path string) (models.Module, error) {
 
 	version := normalizePackageVersion(project.Versions[0])
 	packageUrl := genComposerUrl(project.Name, version)
 
 	if packageUrl == "" {
-		composerJson, _ := getComposerJSONFileData()
+		composerJson, _ := getComposerJSONFileData(path)
 		packageUrl = composerJson.Homepage
 	}
 
@@ -57,7 +59,7 @@ func convertProjectInfoToModule(project ComposerProjectInfo, path string) (model
 
 	checkSumValue := readCheckSum(packageUrl)
 	name := getName(project.Name)
-	supplier := rootProjectSupplier(name)
+	supplier := rootProjectSupplier(name, path)
 
 	module := models.Module{
 		Name:       name,
@@ -72,7 +74,7 @@ func convertProjectInfoToModule(project ComposerProjectInfo, path string) (model
 		Supplier:                supplier,
 	}
 
-	licensePkg, err := helper.GetLicenses(path)
+	licensePkg, err := helper.GetLicenses(m.options.LicenseMatcher, path)
 	if err == nil {
 		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
 		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
@@ -105,9 +107,9 @@ func rootPackageDownloadLocation(defaultValue string) string {
 	return packageDownloadLocation
 }
 
-func rootProjectSupplier(projectName string) models.SupplierContact {
+func rootProjectSupplier(projectName string, path string) models.SupplierContact {
 
-	composerJson, _ := getComposerJSONFileData()
+	composerJson, _ := getComposerJSONFileData(path)
 	if len(composerJson.Authors) > 0 {
 		author := composerJson.Authors[0]
 		return models.SupplierContact{
@@ -201,23 +203,24 @@ func addSubModuleToAModule(modules []models.Module, moduleIndex int, subModule m
 	}
 }
 
-func getComposerLockFileData() (ComposerLockFile, error) {
+func getComposerLockFileData(path string) (ComposerLockFile, error) {
 
-	raw, err := ioutil.ReadFile(COMPOSER_LOCK_FILE_NAME)
+	lockFile := filepath.Join(path, COMPOSER_LOCK_FILE_NAME)
+	raw, err := ioutil.ReadFile(lockFile)
 	if err != nil {
 		return ComposerLockFile{}, err
 	}
 
 	var fileData ComposerLockFile
-	err = json.Unmarshal(raw, &fileData)
+	err = reader.DecodeJSON(lockFile, raw, &fileData)
 	if err != nil {
 		return ComposerLockFile{}, err
 	}
 	return fileData, nil
 }
-func getComposerJSONFileData() (ComposerJSONObject, error) {
+func getComposerJSONFileData(path string) (ComposerJSONObject, error) {
 
-	raw, err := ioutil.ReadFile(COMPOSER_JSON_FILE_NAME)
+	raw, err := ioutil.ReadFile(filepath.Join(path, COMPOSER_JSON_FILE_NAME))
 	if err != nil {
 		return ComposerJSONObject{}, err
 	}
@@ -248,12 +251,17 @@ func (m *composer) getModulesFromComposerLockFile(path string) ([]models.Module,
 
 	modules := make([]models.Module, 0)
 
-	info, err := getComposerLockFileData()
+	info, err := getComposerLockFileData(path)
 	if err != nil {
 		return nil, err
 	}
 
-	mainMod, err := m.getRootProjectInfo(path)
+	var mainMod models.Module
+	if m.options.LockfileOnly {
+		mainMod, err = m.lockfileRootModule(path)
+	} else {
+		mainMod, err = m.getRootProjectInfo(path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -262,14 +270,14 @@ func (m *composer) getModulesFromComposerLockFile(path string) ([]models.Module,
 
 	if len(info.Packages) > 0 {
 		for _, pckg := range info.Packages {
-			mod := convertLockPackageToModule(pckg)
+			mod := m.convertLockPackageToModule(pckg)
 			modules = append(modules, mod)
 		}
 	}
 
 	if len(info.PackagesDev) > 0 {
 		for _, pckg := range info.PackagesDev {
-			mod := convertLockPackageToModule(pckg)
+			mod := m.convertLockPackageToModule(pckg)
 			modules = append(modules, mod)
 		}
 	}
@@ -277,14 +285,15 @@ func (m *composer) getModulesFromComposerLockFile(path string) ([]models.Module,
 	return modules, nil
 }
 
-func convertLockPackageToModule(dep ComposerLockPackage) models.Module {
+func (m *composer) convertLockPackageToModule(dep ComposerLockPackage) models.Module {
 
 	module := models.Module{
 		Version:                 normalizePackageVersion(dep.Version),
 		Name:                    getName(dep.Name),
 		Root:                    false,
+		Path:                    dep.Name,
 		PackageURL:              genUrlFromComposerPackage(dep),
-		PackageDownloadLocation: dep.Source.URL,
+		PackageDownloadLocation: getDownloadLocation(dep),
 		CheckSum: &models.CheckSum{
 			Algorithm: models.HashAlgoSHA1,
 			Value:     getCheckSumValue(dep),
@@ -294,7 +303,7 @@ func convertLockPackageToModule(dep ComposerLockPackage) models.Module {
 		Modules:   map[string]*models.Module{},
 	}
 	path := getLocalPath(dep)
-	licensePkg, err := helper.GetLicenses(path)
+	licensePkg, err := helper.GetLicenses(m.options.LicenseMatcher, path)
 	if err == nil {
 		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
 		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
@@ -309,6 +318,15 @@ func convertLockPackageToModule(dep ComposerLockPackage) models.Module {
 	return module
 }
 
+// getDownloadLocation returns the source repository of the package, or its dist archive when the
+// lockfile has no source, e.g. for the packages of a private repository only publishing archives
+func getDownloadLocation(dep ComposerLockPackage) string {
+	if dep.Source.URL != "" {
+		return dep.Source.URL
+	}
+	return dep.Dist.URL
+}
+
 func getAuthorFromComposerLockFileDep(dep ComposerLockPackage) models.SupplierContact {
 
 	authors := dep.Authors
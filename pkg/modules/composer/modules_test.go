@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestGetTruncatedComposerLockFileData(t *testing.T) {
+	_, err := getComposerLockFileData(filepath.Join("testdata", "truncated"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file testdata/truncated/composer.lock: line 10, column 1: unexpected end of JSON input")
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// PackagistURL is the repository composer resolves the packages from unless composer.json disables it
+var PackagistURL = "https://repo.packagist.org"
+
+// AUTH_JSON_FILE_NAME holds the credentials of the private repositories, in the project or the composer home
+const AUTH_JSON_FILE_NAME = "auth.json"
+
+// composerRepository is a repository of type composer, e.g. a private Packagist or a Satis one,
+// with its credentials if any
+type composerRepository struct {
+	URL string
+	// Only and Exclude are the package names, or * patterns, the repository is restricted to or never serves
+	Only    []string
+	Exclude []string
+
+	username string
+	password string
+	token    string
+}
+
+// repositoryDefinition is an entry of the repositories of composer.json
+type repositoryDefinition struct {
+	Type    string   `json:"type"`
+	URL     string   `json:"url"`
+	Only    []string `json:"only"`
+	Exclude []string `json:"exclude"`
+}
+
+// authConfig is the subset of auth.json used to reach the repositories, keyed by host
+type authConfig struct {
+	HTTPBasic map[string]basicCredentials `json:"http-basic"`
+	Bearer    map[string]string           `json:"bearer"`
+}
+
+type basicCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// repositories returns the composer repositories of composer.json then Packagist, unless it is disabled
+// with "packagist.org": false, with the credentials of their host. Only the composer repositories serve
+// package metadata, the vcs, path and package ones are left out
+func repositories(raw json.RawMessage, auth authConfig) []composerRepository {
+	var result []composerRepository
+	packagist := true
+	for _, entry := range repositoryEntries(raw) {
+		var disabled map[string]bool
+		if json.Unmarshal(entry, &disabled) == nil && len(disabled) == 1 {
+			if enabled, ok := disabled["packagist.org"]; ok && !enabled {
+				packagist = false
+			}
+			continue
+		}
+
+		var definition repositoryDefinition
+		if err := json.Unmarshal(entry, &definition); err != nil || definition.Type != "composer" || definition.URL == "" {
+			continue
+		}
+		result = append(result, newComposerRepository(definition, auth))
+	}
+
+	if packagist {
+		result = append(result, newComposerRepository(repositoryDefinition{URL: PackagistURL}, auth))
+	}
+	return result
+}
+
+// repositoryEntries returns the entries of the repositories of composer.json, either a list or an object
+// keyed by name. The "packagist.org": false entry of the object form is returned as a one key object
+func repositoryEntries(raw json.RawMessage) []json.RawMessage {
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &named); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if string(named[name]) == "false" {
+			disabled, _ := json.Marshal(map[string]bool{name: false})
+			list = append(list, disabled)
+			continue
+		}
+		list = append(list, named[name])
+	}
+	return list
+}
+
+func newComposerRepository(definition repositoryDefinition, auth authConfig) composerRepository {
+	repository := composerRepository{
+		URL:     strings.TrimSuffix(definition.URL, "/"),
+		Only:    definition.Only,
+		Exclude: definition.Exclude,
+	}
+
+	u, err := url.Parse(repository.URL)
+	if err != nil {
+		return repository
+	}
+	for _, host := range []string{u.Host, u.Hostname()} {
+		if credentials, ok := auth.HTTPBasic[host]; ok {
+			repository.username, repository.password = credentials.Username, credentials.Password
+			break
+		}
+		if token, ok := auth.Bearer[host]; ok {
+			repository.token = token
+			break
+		}
+	}
+	return repository
+}
+
+// serves tells whether the repository may serve the package, given its only and exclude filters
+func (r composerRepository) serves(name string) bool {
+	for _, pattern := range r.Exclude {
+		if matchPackageName(pattern, name) {
+			return false
+		}
+	}
+	if len(r.Only) == 0 {
+		return true
+	}
+	for _, pattern := range r.Only {
+		if matchPackageName(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPackageName matches a package name to a pattern whose * match any characters, e.g. acme/*
+func matchPackageName(pattern, name string) bool {
+	parts := strings.Split(strings.ToLower(pattern), "*")
+	name = strings.ToLower(name)
+	if len(parts) == 1 {
+		return parts[0] == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
+
+// readAuth reads the credentials composer uses: the auth.json of the composer home, then the one of
+// the project, then the COMPOSER_AUTH environment variable, the later ones overriding the hosts
+// of the earlier ones
+func readAuth(path string) (authConfig, error) {
+	auth := authConfig{}
+	files := []string{filepath.Join(path, AUTH_JSON_FILE_NAME)}
+	if home := composerHome(); home != "" {
+		files = append([]string{filepath.Join(home, AUTH_JSON_FILE_NAME)}, files...)
+	}
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return authConfig{}, err
+		}
+		if err := mergeAuth(&auth, file, raw); err != nil {
+			return authConfig{}, err
+		}
+	}
+
+	if raw := os.Getenv("COMPOSER_AUTH"); raw != "" {
+		if err := mergeAuth(&auth, "COMPOSER_AUTH", []byte(raw)); err != nil {
+			return authConfig{}, err
+		}
+	}
+	return auth, nil
+}
+
+func mergeAuth(auth *authConfig, fileName string, raw []byte) error {
+	var config authConfig
+	if err := reader.DecodeJSON(fileName, raw, &config); err != nil {
+		return err
+	}
+
+	if auth.HTTPBasic == nil {
+		auth.HTTPBasic = map[string]basicCredentials{}
+	}
+	if auth.Bearer == nil {
+		auth.Bearer = map[string]string{}
+	}
+	for host, credentials := range config.HTTPBasic {
+		auth.HTTPBasic[host] = credentials
+	}
+	for host, token := range config.Bearer {
+		auth.Bearer[host] = token
+	}
+	return nil
+}
+
+// composerHome returns the COMPOSER_HOME directory, ~/.config/composer or ~/.composer by default
+func composerHome() string {
+	if home := os.Getenv("COMPOSER_HOME"); home != "" {
+		return home
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if config := filepath.Join(home, ".config", "composer"); helper.Exists(config) {
+		return config
+	}
+	return filepath.Join(home, ".composer")
+}
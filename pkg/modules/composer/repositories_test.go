@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package composer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositories(t *testing.T) {
+	composerJSON, err := getComposerJSONFileData(filepath.Join("testdata", "private"))
+	assert.NoError(t, err)
+	auth := authConfig{
+		HTTPBasic: map[string]basicCredentials{"repo.packagist.com": {Username: "token", Password: "s3cret"}},
+		Bearer:    map[string]string{"satis.example.com": "satis-token"},
+	}
+
+	result := repositories(composerJSON.Repositories, auth)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "https://repo.packagist.com/acme", result[0].URL)
+	assert.Equal(t, "token", result[0].username)
+	assert.Equal(t, "s3cret", result[0].password)
+	assert.Equal(t, "https://satis.example.com", result[1].URL)
+	assert.Equal(t, "satis-token", result[1].token)
+
+	assert.True(t, result[0].serves("acme/widgets"))
+	assert.False(t, result[0].serves("monolog/monolog"))
+	assert.True(t, result[1].serves("monolog/monolog"))
+	assert.False(t, result[1].serves("acme/legacy"))
+}
+
+func TestRepositoriesObjectForm(t *testing.T) {
+	raw := json.RawMessage(`{"private": {"type": "composer", "url": "https://composer.example.com"}}`)
+	result := repositories(raw, authConfig{})
+	assert.Len(t, result, 2)
+	assert.Equal(t, "https://composer.example.com", result[0].URL)
+	assert.Equal(t, PackagistURL, result[1].URL)
+
+	raw = json.RawMessage(`{"packagist.org": false}`)
+	assert.Empty(t, repositories(raw, authConfig{}))
+
+	assert.Len(t, repositories(nil, authConfig{}), 1)
+}
+
+func TestMatchPackageName(t *testing.T) {
+	assert.True(t, matchPackageName("acme/*", "acme/widgets"))
+	assert.True(t, matchPackageName("*/widgets", "Acme/Widgets"))
+	assert.True(t, matchPackageName("acme/*-bundle", "acme/shop-bundle"))
+	assert.False(t, matchPackageName("acme/*-bundle", "acme/shop"))
+	assert.False(t, matchPackageName("acme/widgets", "acme/widgets-extra"))
+}
+
+func TestReadAuth(t *testing.T) {
+	home, err := ioutil.TempDir("", "composer-home")
+	assert.NoError(t, err)
+	defer os.RemoveAll(home)
+	err = ioutil.WriteFile(filepath.Join(home, AUTH_JSON_FILE_NAME), []byte(`{
+		"http-basic": {
+			"repo.packagist.com": {"username": "token", "password": "global-secret"},
+			"composer.example.com": {"username": "deployer", "password": "global-secret"}
+		}
+	}`), 0644)
+	assert.NoError(t, err)
+
+	os.Setenv("COMPOSER_HOME", home)
+	defer os.Unsetenv("COMPOSER_HOME")
+	os.Setenv("COMPOSER_AUTH", `{"bearer": {"satis.example.com": "env-token"}}`)
+	defer os.Unsetenv("COMPOSER_AUTH")
+
+	auth, err := readAuth(filepath.Join("testdata", "private"))
+	assert.NoError(t, err)
+	assert.Equal(t, basicCredentials{Username: "token", Password: "project-secret"}, auth.HTTPBasic["repo.packagist.com"])
+	assert.Equal(t, basicCredentials{Username: "deployer", Password: "global-secret"}, auth.HTTPBasic["composer.example.com"])
+	assert.Equal(t, "env-token", auth.Bearer["satis.example.com"])
+}
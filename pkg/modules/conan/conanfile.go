@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// requirement is a requirement of a conanfile, its reference may have a version range, e.g. zlib/[>=1.2 <2]
+type requirement struct {
+	Name         string
+	Relationship models.RelationshipType
+}
+
+// recipe is the metadata of a conanfile, the ones of a conanfile.txt only have requirements
+type recipe struct {
+	Name         string
+	Version      string
+	License      []string
+	Homepage     string
+	Author       string
+	Requirements []requirement
+}
+
+// requirementRelationships are the relationships of the requirement kinds of a conanfile, the
+// build_requires are the tool_requires of conan 1
+var requirementRelationships = map[string]models.RelationshipType{
+	"requires":        "",
+	"tool_requires":   models.RelationshipBuildToolOf,
+	"build_requires":  models.RelationshipBuildToolOf,
+	"test_requires":   models.RelationshipDevDependencyOf,
+	"python_requires": models.RelationshipBuildDependencyOf,
+}
+
+var (
+	// recipeAttribute is an attribute of the recipe class, e.g. license = "MIT"
+	recipeAttribute = regexp.MustCompile(`(?m)^(?:    |\t)(\w+)[ \t]*=[ \t]*`)
+	// requireCall is a requirement of a requirements method, e.g. self.requires("zlib/1.2.13")
+	requireCall = regexp.MustCompile(`self\.(\w+)\(\s*["']([^"']+)["']`)
+	// stringLiteral is a python string
+	stringLiteral = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// readConanfileTxt reads the requirements of the sections of a conanfile.txt:
+//
+//	[requires]
+//	zlib/1.2.13
+//	[tool_requires]
+//	cmake/3.25.3
+func readConanfileTxt(path string) (*recipe, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := &recipe{}
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		relationship, ok := requirementRelationships[section]
+		if !ok {
+			continue
+		}
+		if name := requirementName(line); name != "" {
+			r.Requirements = append(r.Requirements, requirement{Name: name, Relationship: relationship})
+		}
+	}
+	return r, scanner.Err()
+}
+
+// readConanfilePy reads the literal attributes and requirements of the recipe class of a conanfile.py, the
+// ones it computes are not known without running it
+func readConanfilePy(path string) (*recipe, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+
+	r := &recipe{}
+	for _, match := range recipeAttribute.FindAllStringSubmatchIndex(content, -1) {
+		attribute := content[match[2]:match[3]]
+		values := stringValues(attributeValue(content[match[1]:]))
+		if len(values) == 0 {
+			continue
+		}
+		switch attribute {
+		case "name":
+			r.Name = values[0]
+		case "version":
+			r.Version = values[0]
+		case "license":
+			r.License = values
+		case "homepage":
+			r.Homepage = values[0]
+		case "author":
+			r.Author = values[0]
+		default:
+			if relationship, ok := requirementRelationships[attribute]; ok {
+				for _, value := range values {
+					if name := requirementName(value); name != "" {
+						r.Requirements = append(r.Requirements, requirement{Name: name, Relationship: relationship})
+					}
+				}
+			}
+		}
+	}
+	for _, match := range requireCall.FindAllStringSubmatch(content, -1) {
+		if relationship, ok := requirementRelationships[match[1]]; ok {
+			if name := requirementName(match[2]); name != "" {
+				r.Requirements = append(r.Requirements, requirement{Name: name, Relationship: relationship})
+			}
+		}
+	}
+	return r, nil
+}
+
+// attributeValue returns the value an attribute is assigned, up to the end of its line or of its
+// tuple or list when it spans several lines
+func attributeValue(s string) string {
+	if s == "" || (s[0] != '(' && s[0] != '[') {
+		if i := strings.Index(s, "\n"); i >= 0 {
+			return s[:i]
+		}
+		return s
+	}
+
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+			if depth == 0 {
+				return s[:i+1]
+			}
+		}
+	}
+	return s
+}
+
+// stringValues returns the strings of a value, e.g. ("MIT", "Zlib")
+func stringValues(value string) []string {
+	var values []string
+	for _, match := range stringLiteral.FindAllStringSubmatch(value, -1) {
+		values = append(values, match[1]+match[2])
+	}
+	return values
+}
+
+// requirementName returns the package name of a requirement, e.g. zlib/[>=1.2 <2]
+func requirementName(s string) string {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[0]
+}
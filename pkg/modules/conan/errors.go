@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no conan.lock or graph.json found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `conan lock create .`")
+)
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// rootID is the id of the node of the conanfile in a graph
+const rootID = "0"
+
+// node is a package of a dependency graph, the metadata of its recipe is only known from a graph info
+type node struct {
+	ID           string
+	Reference    reference
+	License      []string
+	Homepage     string
+	Author       string
+	Dependencies []dependency
+}
+
+// dependency is an edge of a dependency graph
+type dependency struct {
+	ID           string
+	Relationship models.RelationshipType
+}
+
+// graph is the dependency graph of a conanfile, the root is the node of the conanfile
+type graph struct {
+	Nodes map[string]*node
+}
+
+// packages returns the nodes of the graph but the root, sorted by reference
+func (g *graph) packages() []*node {
+	var nodes []*node
+	for id, n := range g.Nodes {
+		if id != rootID {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Reference.Name != nodes[j].Reference.Name {
+			return nodes[i].Reference.Name < nodes[j].Reference.Name
+		}
+		return nodes[i].Reference.Version < nodes[j].Reference.Version
+	})
+	return nodes
+}
+
+// lockfile is a conan.lock, conan 2 lists the references by context and conan 1 locks the whole graph
+type lockfile struct {
+	Version        string   `json:"version"`
+	Requires       []string `json:"requires"`
+	BuildRequires  []string `json:"build_requires"`
+	PythonRequires []string `json:"python_requires"`
+	GraphLock      *struct {
+		Nodes map[string]struct {
+			Ref            string   `json:"ref"`
+			PackageID      string   `json:"package_id"`
+			Prev           string   `json:"prev"`
+			Requires       []string `json:"requires"`
+			BuildRequires  []string `json:"build_requires"`
+			PythonRequires []string `json:"python_requires"`
+		} `json:"nodes"`
+	} `json:"graph_lock"`
+}
+
+// readLockfile reads the graph of a conan.lock. The references of a conan 2 lockfile are not linked to
+// the ones requiring them so the root depends on all of them
+func readLockfile(path string) (*graph, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lock := lockfile{}
+	if err := reader.DecodeJSON(path, data, &lock); err != nil {
+		return nil, err
+	}
+
+	g := &graph{Nodes: map[string]*node{rootID: {ID: rootID}}}
+	addReference := func(parent *node, s string, relationship models.RelationshipType) error {
+		ref, ok := parseReference(s)
+		if !ok {
+			return fmt.Errorf("%w %s: invalid reference %q", reader.ErrMalformedFile, path, s)
+		}
+		if _, ok := g.Nodes[s]; !ok {
+			g.Nodes[s] = &node{ID: s, Reference: ref}
+		}
+		parent.Dependencies = append(parent.Dependencies, dependency{ID: s, Relationship: relationship})
+		return nil
+	}
+
+	if lock.GraphLock == nil {
+		root := g.Nodes[rootID]
+		for _, section := range []struct {
+			refs         []string
+			relationship models.RelationshipType
+		}{
+			{lock.Requires, ""},
+			{lock.BuildRequires, models.RelationshipBuildToolOf},
+			{lock.PythonRequires, models.RelationshipBuildDependencyOf},
+		} {
+			for _, s := range section.refs {
+				if err := addReference(root, s, section.relationship); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return g, nil
+	}
+
+	for id, locked := range lock.GraphLock.Nodes {
+		n := &node{ID: id}
+		if locked.Ref != "" {
+			if ref, ok := parseReference(locked.Ref); ok {
+				n.Reference = ref
+			} else if id != rootID {
+				return nil, fmt.Errorf("%w %s: invalid reference %q", reader.ErrMalformedFile, path, locked.Ref)
+			}
+		}
+		n.Reference.PackageID, n.Reference.PackageRevision = locked.PackageID, locked.Prev
+		g.Nodes[id] = n
+	}
+	for id, locked := range lock.GraphLock.Nodes {
+		n := g.Nodes[id]
+		for _, section := range []struct {
+			ids          []string
+			relationship models.RelationshipType
+		}{
+			{locked.Requires, ""},
+			{locked.BuildRequires, models.RelationshipBuildToolOf},
+		} {
+			for _, dependencyID := range section.ids {
+				if _, ok := g.Nodes[dependencyID]; !ok {
+					return nil, fmt.Errorf("%w %s: unknown node %q", reader.ErrMalformedFile, path, dependencyID)
+				}
+				n.Dependencies = append(n.Dependencies, dependency{ID: dependencyID, Relationship: section.relationship})
+			}
+		}
+		for _, s := range locked.PythonRequires {
+			if err := addReference(n, s, models.RelationshipBuildDependencyOf); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+// graphInfo is the json output of conan graph info
+type graphInfo struct {
+	Graph struct {
+		Nodes map[string]struct {
+			Ref       string          `json:"ref"`
+			Name      string          `json:"name"`
+			Version   string          `json:"version"`
+			User      string          `json:"user"`
+			Channel   string          `json:"channel"`
+			Rrev      string          `json:"rrev"`
+			PackageID string          `json:"package_id"`
+			Prev      string          `json:"prev"`
+			License   json.RawMessage `json:"license"`
+			Homepage  string          `json:"homepage"`
+			Author    string          `json:"author"`
+			// Dependencies are all the nodes the node depends on, the transitive ones are not direct
+			Dependencies map[string]struct {
+				Direct bool `json:"direct"`
+				Build  bool `json:"build"`
+				Test   bool `json:"test"`
+			} `json:"dependencies"`
+		} `json:"nodes"`
+	} `json:"graph"`
+}
+
+// readGraphInfo reads the graph of the output of conan graph info --format=json, the nodes are linked to
+// their direct dependencies
+func readGraphInfo(path string) (*graph, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info := graphInfo{}
+	if err := reader.DecodeJSON(path, data, &info); err != nil {
+		return nil, err
+	}
+	if len(info.Graph.Nodes) == 0 {
+		return nil, fmt.Errorf("%w %s: no graph nodes", reader.ErrMalformedFile, path)
+	}
+
+	g := &graph{Nodes: map[string]*node{}}
+	for id, infoNode := range info.Graph.Nodes {
+		n := &node{
+			ID: id,
+			Reference: reference{
+				Name:            infoNode.Name,
+				Version:         infoNode.Version,
+				User:            infoNode.User,
+				Channel:         infoNode.Channel,
+				RecipeRevision:  infoNode.Rrev,
+				PackageID:       infoNode.PackageID,
+				PackageRevision: infoNode.Prev,
+			},
+			License:  stringList(infoNode.License),
+			Homepage: infoNode.Homepage,
+			Author:   infoNode.Author,
+		}
+		if n.Reference.Name == "" && id != rootID {
+			ref, ok := parseReference(infoNode.Ref)
+			if !ok {
+				return nil, fmt.Errorf("%w %s: invalid reference %q", reader.ErrMalformedFile, path, infoNode.Ref)
+			}
+			n.Reference.Name, n.Reference.Version = ref.Name, ref.Version
+		}
+		for dependencyID, edge := range infoNode.Dependencies {
+			if !edge.Direct {
+				continue
+			}
+			if _, ok := info.Graph.Nodes[dependencyID]; !ok {
+				return nil, fmt.Errorf("%w %s: unknown node %q", reader.ErrMalformedFile, path, dependencyID)
+			}
+			relationship := models.RelationshipType("")
+			switch {
+			case edge.Test:
+				relationship = models.RelationshipDevDependencyOf
+			case edge.Build:
+				relationship = models.RelationshipBuildToolOf
+			}
+			n.Dependencies = append(n.Dependencies, dependency{ID: dependencyID, Relationship: relationship})
+		}
+		sort.Slice(n.Dependencies, func(i, j int) bool { return n.Dependencies[i].ID < n.Dependencies[j].ID })
+		g.Nodes[id] = n
+	}
+	if _, ok := g.Nodes[rootID]; !ok {
+		return nil, fmt.Errorf("%w %s: no root node", reader.ErrMalformedFile, path)
+	}
+	return g, nil
+}
+
+// stringList returns the strings of a value that is a string or a list of strings, e.g. the license of a recipe
+func stringList(raw json.RawMessage) []string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values
+	}
+	return nil
+}
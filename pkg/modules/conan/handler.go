@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type conan struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ConanfileTxt string = "conanfile.txt"
+	ConanfilePy  string = "conanfile.py"
+	LockFile     string = "conan.lock"
+	// GraphFile is the output of conan graph info . --format=json > graph.json
+	GraphFile string = "graph.json"
+)
+
+// New creates a new conan instance
+func New() *conan {
+	return &conan{
+		metadata: models.PluginMetadata{
+			Name:       "Conan Package Manager",
+			Slug:       "conan",
+			Manifest:   []string{ConanfileTxt, ConanfilePy, LockFile},
+			ModulePath: []string{},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *conan) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the conan version
+func (m *conan) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "conan", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *conan) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *conan) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the conanfile.py of the project, named after its directory when it
+// has none
+func (m *conan) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root, _, err := rootModule(absPath, m.options.LicenseMatcher)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// ListUsedModules returns the packages of the project, without the project itself
+func (m *conan) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the packages of its graph info, else of its
+// conan.lock, linked to the packages they require
+func (m *conan) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var g *graph
+	if graphPath := filepath.Join(absPath, GraphFile); helper.Exists(graphPath) {
+		g, err = readGraphInfo(graphPath)
+	} else if lockPath := filepath.Join(absPath, LockFile); helper.Exists(lockPath) {
+		g, err = readLockfile(lockPath)
+	} else {
+		return nil, errDependenciesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root, r, err := rootModule(absPath, m.options.LicenseMatcher)
+	if err != nil {
+		return nil, err
+	}
+	return graphModules(root, r, g), nil
+}
+
+// IsValid checks if a conanfile.txt or a conanfile.py exists
+func (m *conan) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ConanfileTxt)) || helper.Exists(filepath.Join(path, ConanfilePy))
+}
+
+// HasModulesInstalled checks the graph of the packages is locked by a conan.lock or written by conan graph info
+func (m *conan) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) || helper.Exists(filepath.Join(path, GraphFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// rootModule returns the package of the conanfile of the project, the conanfile.py is preferred as conan does
+func rootModule(path string, matcher helper.LicenseMatcher) (*models.Module, *recipe, error) {
+	var r *recipe
+	var err error
+	if conanfilePath := filepath.Join(path, ConanfilePy); helper.Exists(conanfilePath) {
+		r, err = readConanfilePy(conanfilePath)
+	} else if conanfilePath := filepath.Join(path, ConanfileTxt); helper.Exists(conanfilePath) {
+		r, err = readConanfileTxt(conanfilePath)
+	} else {
+		r = &recipe{}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		PackageHomePage:         r.Homepage,
+		Modules:                 map[string]*models.Module{},
+	}
+	if r.Name != "" {
+		mod.Name = r.Name
+		mod.Version = r.Version
+		mod.PackageURL = purl.New("conan", "", mod.Name, mod.Version).String()
+	}
+	if r.Author != "" {
+		mod.Supplier = authorSupplier(r.Author)
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicenseExpression(mod, licenseExpression(r.License))
+	if mod.LicenseDeclared == "" {
+		helper.SetLicense(matcher, path, mod)
+	}
+	return mod, r, nil
+}
+
+// graphModules returns the root followed by the packages of the graph, linked to their dependencies. The
+// root takes the relationships of the requirements of its conanfile, e.g. the test_requires of a conan 2
+// lockfile are listed with its requires
+func graphModules(root *models.Module, r *recipe, g *graph) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{rootID: 0}
+	for _, n := range g.packages() {
+		index[n.ID] = len(modules)
+		modules = append(modules, nodeModule(n))
+	}
+
+	relationships := map[string]models.RelationshipType{}
+	for _, requirement := range r.Requirements {
+		relationships[requirement.Name] = requirement.Relationship
+	}
+	for id, n := range g.Nodes {
+		for _, dep := range n.Dependencies {
+			relationship := dep.Relationship
+			if requirementRelationship, ok := relationships[g.Nodes[dep.ID].Reference.Name]; ok && id == rootID {
+				relationship = requirementRelationship
+			}
+			helper.LinkModule(modules, index[id], index[dep.ID], relationship)
+		}
+	}
+	return modules
+}
+
+// nodeModule returns the package of a node of the graph, it is identified by its revisions as conan does
+// not publish a checksum of its sources
+func nodeModule(n *node) models.Module {
+	mod := models.Module{
+		Name:                    n.Reference.Name,
+		Version:                 n.Reference.Version,
+		PackageURL:              n.Reference.purl(),
+		PackageDownloadLocation: "NOASSERTION",
+		PackageHomePage:         n.Homepage,
+		Supplier:                models.SupplierContact{Name: n.Reference.Name},
+		Modules:                 map[string]*models.Module{},
+	}
+	if n.Reference.User != "" {
+		mod.Supplier = models.SupplierContact{Name: n.Reference.User}
+	}
+	if n.Author != "" {
+		mod.Supplier = authorSupplier(n.Author)
+	}
+	helper.SetLicenseExpression(&mod, licenseExpression(n.License))
+	return mod
+}
+
+// authorSupplier returns the supplier of the author of a recipe, e.g. Jane Doe <jane@example.com>
+func authorSupplier(author string) models.SupplierContact {
+	author = strings.TrimSpace(author)
+	if i := strings.Index(author, "<"); i >= 0 && strings.HasSuffix(author, ">") {
+		return models.SupplierContact{
+			Name:  strings.TrimSpace(author[:i]),
+			Email: author[i+1 : len(author)-1],
+			Type:  models.Person,
+		}
+	}
+	return models.SupplierContact{Name: author, Type: models.Person}
+}
+
+// licenseExpression returns the SPDX expression of the licenses of a recipe, all of them apply. It is ""
+// when one of them is not an SPDX expression
+func licenseExpression(licenses []string) string {
+	var parts []string
+	for _, license := range licenses {
+		expression := helper.SPDXExpression(license)
+		if expression == "" {
+			return ""
+		}
+		if len(licenses) > 1 && strings.Contains(expression, " ") {
+			expression = fmt.Sprintf("(%s)", expression)
+		}
+		parts = append(parts, expression)
+	}
+	return strings.Join(parts, " AND ")
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func rootRelationships(root models.Module) map[string]models.RelationshipType {
+	relationships := map[string]models.RelationshipType{}
+	for name, mod := range root.Modules {
+		relationships[name] = mod.Relationship
+	}
+	return relationships
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "app")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	assert.Equal(t, "app", modules[0].Name)
+	assert.Equal(t, map[string]models.RelationshipType{
+		"zlib":    "",
+		"openssl": "",
+		"cmake":   models.RelationshipBuildToolOf,
+		"gtest":   models.RelationshipDevDependencyOf,
+	}, rootRelationships(modules[0]))
+
+	cmake := modules[1]
+	assert.Equal(t, "cmake", cmake.Name)
+	assert.Equal(t, "3.25.3", cmake.Version)
+	assert.Equal(t, "pkg:conan/cmake@3.25.3?rrev=3cc8af2b7af0fbd3dd8a85d2fd25e9e4", cmake.PackageURL)
+	assert.Equal(t, "NOASSERTION", cmake.PackageDownloadLocation)
+	assert.Empty(t, cmake.Modules)
+}
+
+func TestListModulesWithDepsLegacyLockfile(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "legacy"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 4) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "MIT", root.LicenseDeclared)
+	assert.Equal(t, "https://example.com/shop", root.PackageHomePage)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+	assert.Equal(t, map[string]models.RelationshipType{
+		"poco":  "",
+		"cmake": models.RelationshipBuildToolOf,
+	}, rootRelationships(root))
+
+	poco := modules[2]
+	assert.Equal(t, "pkg:conan/poco@1.12.4?channel=stable&package_id=6af9cc7cb931c5ad942174fd7838eb655717c709&prev=0a5b3d52d8b8a9e4b1fc1a0b38d6d7a9&rrev=2e0b3ea1a9c1a3b8c6a0f0d8f0b1e6a3&user=acme", poco.PackageURL)
+	assert.Equal(t, "Organization: acme", poco.Supplier.Get())
+	assert.Len(t, poco.Modules, 1)
+	assert.Contains(t, poco.Modules, "zlib")
+}
+
+func TestListModulesWithDepsGraphInfo(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "graph"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 3) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "pkg:conan/shop@2.0.0", root.PackageURL)
+	assert.Equal(t, "Apache-2.0 AND MIT", root.LicenseDeclared)
+	assert.Equal(t, map[string]models.RelationshipType{
+		"fmt":    "",
+		"catch2": models.RelationshipDevDependencyOf,
+	}, rootRelationships(root))
+
+	fmt := modules[2]
+	assert.Equal(t, "MIT", fmt.LicenseDeclared)
+	assert.Equal(t, "https://github.com/fmtlib/fmt", fmt.PackageHomePage)
+	assert.Equal(t, "pkg:conan/fmt@10.1.1?package_id=5a9c6c4e2b3c6f0d8c3f0e2a1b4c6d8e0f2a4b6c&prev=f1e2d3c4b5a697887766554433221100&rrev=0e7e9d3a2a1c5a3b0f8f4a0e4b1c7d2e", fmt.PackageURL)
+}
+
+func TestHasModulesInstalled(t *testing.T) {
+	assert.Equal(t, errDependenciesNotFound, New().HasModulesInstalled("testdata"))
+	_, err := New().ListModulesWithDeps("testdata")
+	assert.Equal(t, errDependenciesNotFound, err)
+}
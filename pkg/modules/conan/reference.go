@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// reference is a conan reference, e.g. zlib/1.2.13@user/channel#rrev%timestamp:package_id#prev%timestamp
+type reference struct {
+	Name    string
+	Version string
+	User    string
+	Channel string
+	// RecipeRevision is the revision of the recipe, the hash of its files
+	RecipeRevision string
+	// PackageID is the hash of the settings and options the binary package is built with
+	PackageID string
+	// PackageRevision is the revision of the binary package, the hash of its files
+	PackageRevision string
+}
+
+// parseReference parses a conan reference, the user and channel _ are the ones of a reference without them
+func parseReference(s string) (reference, bool) {
+	ref := reference{}
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ":"); i >= 0 {
+		ref.PackageID, ref.PackageRevision = splitRevision(s[i+1:])
+		s = s[:i]
+	}
+	s, ref.RecipeRevision = splitRevision(s)
+	if i := strings.Index(s, "@"); i >= 0 {
+		userChannel := strings.SplitN(s[i+1:], "/", 2)
+		ref.User = userChannel[0]
+		if len(userChannel) == 2 {
+			ref.Channel = userChannel[1]
+		}
+		s = s[:i]
+	}
+	if ref.User == "_" {
+		ref.User = ""
+	}
+	if ref.Channel == "_" {
+		ref.Channel = ""
+	}
+
+	nameVersion := strings.SplitN(s, "/", 2)
+	if len(nameVersion) != 2 || nameVersion[0] == "" || nameVersion[1] == "" || strings.Contains(nameVersion[1], "/") {
+		return reference{}, false
+	}
+	ref.Name, ref.Version = nameVersion[0], nameVersion[1]
+	return ref, true
+}
+
+// splitRevision splits a value from its revision, e.g. zlib/1.2.13#rrev%timestamp, dropping the timestamp
+func splitRevision(s string) (string, string) {
+	i := strings.Index(s, "#")
+	if i < 0 {
+		return s, ""
+	}
+	revision := s[i+1:]
+	if j := strings.Index(revision, "%"); j >= 0 {
+		revision = revision[:j]
+	}
+	return s[:i], revision
+}
+
+// purl returns the package url of the reference, the package id qualifier scopes the package revision
+func (r reference) purl() string {
+	return purl.New("conan", "", r.Name, r.Version).
+		WithQualifier("user", r.User).
+		WithQualifier("channel", r.Channel).
+		WithQualifier("rrev", r.RecipeRevision).
+		WithQualifier("package_id", r.PackageID).
+		WithQualifier("prev", r.PackageRevision).
+		String()
+}
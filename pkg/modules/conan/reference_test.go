@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	ref, ok := parseReference("zlib/1.2.13#97d5730b529b4224045fe7090592d4c1%1692672717.049")
+	assert.True(t, ok)
+	assert.Equal(t, reference{Name: "zlib", Version: "1.2.13", RecipeRevision: "97d5730b529b4224045fe7090592d4c1"}, ref)
+	assert.Equal(t, "pkg:conan/zlib@1.2.13?rrev=97d5730b529b4224045fe7090592d4c1", ref.purl())
+
+	ref, ok = parseReference("poco/1.12.4@acme/stable#rrev:6af9cc7cb931c5ad942174fd7838eb655717c709#prev%1692672717")
+	assert.True(t, ok)
+	assert.Equal(t, reference{
+		Name:            "poco",
+		Version:         "1.12.4",
+		User:            "acme",
+		Channel:         "stable",
+		RecipeRevision:  "rrev",
+		PackageID:       "6af9cc7cb931c5ad942174fd7838eb655717c709",
+		PackageRevision: "prev",
+	}, ref)
+	assert.Equal(t, "pkg:conan/poco@1.12.4?channel=stable&package_id=6af9cc7cb931c5ad942174fd7838eb655717c709&prev=prev&rrev=rrev&user=acme", ref.purl())
+
+	ref, ok = parseReference("zlib/1.2.13@_/_")
+	assert.True(t, ok)
+	assert.Equal(t, reference{Name: "zlib", Version: "1.2.13"}, ref)
+
+	_, ok = parseReference("conanfile")
+	assert.False(t, ok)
+}
@@ -0,0 +1,15 @@
+from conan import ConanFile
+
+
+class ShopRecipe(ConanFile):
+    name = "shop"
+    version = "2.0.0"
+    license = (
+        "Apache-2.0",
+        "MIT",
+    )
+    settings = "os", "compiler", "build_type", "arch"
+
+    def requirements(self):
+        self.requires("fmt/10.1.1")
+        self.test_requires("catch2/3.4.0")
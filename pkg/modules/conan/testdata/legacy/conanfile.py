@@ -0,0 +1,20 @@
+from conans import ConanFile, CMake
+
+
+class ShopConan(ConanFile):
+    name = "shop"
+    version = "1.0.0"
+    license = "MIT"
+    author = "Jane Doe <jane@example.com>"
+    homepage = "https://example.com/shop"
+    settings = "os", "compiler", "build_type", "arch"
+    requires = "poco/1.12.4@acme/stable"
+    generators = "cmake"
+
+    def build_requirements(self):
+        self.build_requires("cmake/3.25.3")
+
+    def build(self):
+        cmake = CMake(self)
+        cmake.configure()
+        cmake.build()
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+var (
+	// specNameRegex splits a conda match spec into its name and its version and build constraints
+	specNameRegex = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_.\-]*)\s*(.*)$`)
+	// requirementRegex splits a PEP 508 requirement of the pip section, e.g. requests[socks]==2.31.0
+	requirementRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._\-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+)
+
+// managerConda and managerPip are the package managers installing the packages of an environment
+const (
+	managerConda = "conda"
+	managerPip   = "pip"
+)
+
+// spec is a dependency of an environment.yml, Version is set when the spec pins one
+type spec struct {
+	Manager string
+	Channel string
+	Name    string
+	Version string
+	Build   string
+	// Constraint is the version range of the specs not pinning a version
+	Constraint string
+}
+
+// environment is an environment.yml
+type environment struct {
+	Name     string
+	Channels []string
+	Specs    []spec
+}
+
+// parseEnvironment reads the name, the channels and the conda and pip dependencies of an environment.yml.
+// The pip options, e.g. -r requirements.txt, and the pip urls are not packages and are left out
+func parseEnvironment(fileName string, r io.Reader) (*environment, error) {
+	document, err := reader.ParseYAML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &environment{Name: document.Str("name")}
+	for _, channel := range document.List("channels") {
+		if channel.Value != "" {
+			env.Channels = append(env.Channels, channel.Value)
+		}
+	}
+
+	for _, dependency := range document.List("dependencies") {
+		if dependency.Value != "" {
+			if s, ok := parseCondaSpec(dependency.Value); ok {
+				env.Specs = append(env.Specs, s)
+			}
+			continue
+		}
+		for _, requirement := range dependency.List("pip") {
+			if s, ok := parsePipSpec(requirement.Value); ok {
+				env.Specs = append(env.Specs, s)
+			}
+		}
+	}
+	return env, nil
+}
+
+// parseCondaSpec parses a conda match spec, e.g. numpy, numpy>=1.24, conda-forge::numpy=1.24.3=py311h_0
+// or numpy 1.24.3 py311h_0. A version is pinned by = or ==, without wildcard nor range
+func parseCondaSpec(value string) (spec, bool) {
+	s := spec{Manager: managerConda}
+	if i := strings.LastIndex(value, "::"); i >= 0 {
+		s.Channel, value = value[:i], value[i+2:]
+	}
+	match := specNameRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return spec{}, false
+	}
+	s.Name = match[1]
+	rest := strings.TrimSpace(match[2])
+
+	switch {
+	case rest == "" || strings.HasPrefix(rest, "["):
+		s.Constraint = rest
+	case strings.Contains(rest, " "):
+		fields := strings.Fields(rest)
+		s.Version = fields[0]
+		if len(fields) > 1 {
+			s.Build = fields[1]
+		}
+	case strings.HasPrefix(rest, "=="):
+		s.Version = rest[2:]
+	case strings.HasPrefix(rest, "="):
+		parts := strings.SplitN(rest[1:], "=", 2)
+		s.Version = parts[0]
+		if len(parts) == 2 {
+			s.Build = parts[1]
+		}
+	default:
+		s.Constraint = rest
+	}
+
+	if s.Version != "" && strings.ContainsAny(s.Version, "*<>!,|~") {
+		s.Version, s.Build, s.Constraint = "", "", rest
+	}
+	if strings.ContainsAny(s.Build, "*") {
+		s.Build = ""
+	}
+	return s, true
+}
+
+// parsePipSpec parses a requirement of the pip section, a version is pinned by == or ===
+func parsePipSpec(value string) (spec, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "-") || strings.Contains(value, "://") {
+		return spec{}, false
+	}
+	// the environment markers do not change the package
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	match := requirementRegex.FindStringSubmatch(value)
+	if match == nil {
+		return spec{}, false
+	}
+
+	s := spec{Manager: managerPip, Name: match[1]}
+	rest := strings.TrimSpace(match[2])
+	version := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(rest, "==="), "=="))
+	if version != rest && version != "" && !strings.ContainsAny(version, "*<>!,~=") {
+		s.Version = version
+	} else {
+		s.Constraint = rest
+	}
+	return s, true
+}
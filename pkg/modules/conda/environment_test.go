@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCondaSpec(t *testing.T) {
+	for value, expected := range map[string]spec{
+		"numpy":                                {Manager: managerConda, Name: "numpy"},
+		"numpy>=1.24":                          {Manager: managerConda, Name: "numpy", Constraint: ">=1.24"},
+		"numpy==1.24.3":                        {Manager: managerConda, Name: "numpy", Version: "1.24.3"},
+		"python=3.11.*":                        {Manager: managerConda, Name: "python", Constraint: "=3.11.*"},
+		"numpy=1.24.3=py311h64a7726_0":         {Manager: managerConda, Name: "numpy", Version: "1.24.3", Build: "py311h64a7726_0"},
+		"numpy 1.24.3 py311h64a7726_0":         {Manager: managerConda, Name: "numpy", Version: "1.24.3", Build: "py311h64a7726_0"},
+		"conda-forge::pandas=2.0.3":            {Manager: managerConda, Channel: "conda-forge", Name: "pandas", Version: "2.0.3"},
+		"numpy[version='>=1.24',build=py311*]": {Manager: managerConda, Name: "numpy", Constraint: "[version='>=1.24',build=py311*]"},
+	} {
+		s, ok := parseCondaSpec(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, s, value)
+	}
+}
+
+func TestParsePipSpec(t *testing.T) {
+	s, ok := parsePipSpec("requests[socks]==2.31.0 ; python_version >= '3.8'")
+	assert.True(t, ok)
+	assert.Equal(t, spec{Manager: managerPip, Name: "requests", Version: "2.31.0"}, s)
+
+	s, ok = parsePipSpec("Flask_Login>=0.6")
+	assert.True(t, ok)
+	assert.Equal(t, spec{Manager: managerPip, Name: "Flask_Login", Constraint: ">=0.6"}, s)
+
+	for _, value := range []string{"-r requirements.txt", "-e .", "git+https://github.com/psf/requests.git"} {
+		_, ok := parsePipSpec(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestParseEnvironment(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "environment", EnvironmentFile))
+	assert.NoError(t, err)
+	defer file.Close()
+
+	env, err := parseEnvironment(EnvironmentFile, file)
+	assert.NoError(t, err)
+	assert.Equal(t, "analysis", env.Name)
+	assert.Equal(t, []string{"conda-forge", "defaults"}, env.Channels)
+
+	var names []string
+	for _, s := range env.Specs {
+		names = append(names, s.Manager+"/"+s.Name)
+	}
+	assert.Equal(t, []string{"conda/python", "conda/numpy", "conda/pandas", "conda/scipy", "conda/matplotlib-base", "conda/pip", "pip/requests", "pip/Flask_Login"}, names)
+}
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound       = errors.New("unable to generate SPDX file, no environment.yml nor conda-lock.yml found. Please declare the environment before running spdx-sbom-generator, e.g.: `conda env export > environment.yml`")
+	errUnsupportedLockfileVersion = errors.New("unsupported conda-lock version")
+)
@@ -0,0 +1,355 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type conda struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	LockFile        string = "conda-lock.yml"
+	EnvironmentFile string = "environment.yml"
+)
+
+// environmentFiles are the names conda reads an environment file from
+var environmentFiles = []string{EnvironmentFile, "environment.yaml"}
+
+// platformArchitectures are the conda architectures of the Go ones, conda names the 64 bits ARM
+// linux-aarch64 on linux and osx-arm64 or win-arm64 elsewhere
+var platformArchitectures = map[string]string{
+	"amd64":   "64",
+	"386":     "32",
+	"arm64":   "arm64",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// New creates a new conda instance
+func New() *conda {
+	return &conda{
+		metadata: models.PluginMetadata{
+			Name:       "Conda Package Manager",
+			Slug:       "conda",
+			Manifest:   []string{EnvironmentFile, LockFile},
+			ModulePath: []string{},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *conda) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the conda version
+func (m *conda) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "conda", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *conda) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *conda) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the environment as the root package, named after the environment.yml or its directory
+func (m *conda) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	env, err := readEnvironment(absPath)
+	if err != nil {
+		return nil, err
+	}
+	return rootModule(absPath, env, m.options.LicenseMatcher), nil
+}
+
+// ListUsedModules returns the packages of the environment, without the environment itself
+func (m *conda) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the environment followed by its packages. The conda-lock.yml packages of the
+// current platform, else of its first one, are linked to the packages they depend on; without a lockfile
+// only the packages the environment.yml declares are listed, at the versions it pins
+func (m *conda) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	env, err := readEnvironment(absPath)
+	if err != nil {
+		return nil, err
+	}
+	root := rootModule(absPath, env, m.options.LicenseMatcher)
+
+	lockPath := filepath.Join(absPath, LockFile)
+	if !helper.Exists(lockPath) {
+		if env == nil {
+			return nil, errDependenciesNotFound
+		}
+		return environmentModules(root, env), nil
+	}
+	lock, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	return lockModules(root, env, lock, selectPlatform(lock.Platforms, currentPlatform())), nil
+}
+
+// IsValid checks if an environment.yml or a conda-lock.yml exists
+func (m *conda) IsValid(path string) bool {
+	return environmentFile(path) != "" || helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks the environment is declared, installing it does not change the packages listed
+func (m *conda) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// lockModules returns the root followed by the packages of the lockfile for the platform. The root depends
+// on the packages the environment.yml declares, or else on the ones no package depends on; the packages
+// of another category than main are DEV_DEPENDENCY_OF it
+func lockModules(root *models.Module, env *environment, lock *lockfile, platform string) []models.Module {
+	modules := []models.Module{*root}
+	packages := lock.platformPackages(platform)
+	index := map[string]int{}
+	for _, pkg := range packages {
+		index[packageKey(pkg.Manager, pkg.Name)] = len(modules)
+		modules = append(modules, lockPackageModule(pkg))
+	}
+
+	dependencies := map[int]bool{}
+	for _, pkg := range packages {
+		for _, name := range pkg.Dependencies {
+			if i, ok := lookupPackage(index, pkg.Manager, name); ok {
+				helper.LinkModule(modules, index[packageKey(pkg.Manager, pkg.Name)], i, "")
+				dependencies[i] = true
+			}
+		}
+	}
+
+	var direct []int
+	if env != nil && len(env.Specs) > 0 {
+		for _, s := range env.Specs {
+			if i, ok := index[packageKey(s.Manager, s.Name)]; ok {
+				direct = append(direct, i)
+			}
+		}
+	} else {
+		for i := 1; i < len(modules); i++ {
+			if !dependencies[i] {
+				direct = append(direct, i)
+			}
+		}
+	}
+	for _, i := range direct {
+		relationship := models.RelationshipType("")
+		if packages[i-1].Category != mainCategory {
+			relationship = models.RelationshipDevDependencyOf
+		}
+		helper.LinkModule(modules, 0, i, relationship)
+	}
+	return modules
+}
+
+// environmentModules returns the root followed by the packages the environment.yml declares, their
+// versions are the ones the specs pin
+func environmentModules(root *models.Module, env *environment) []models.Module {
+	root.Unresolved = append(root.Unresolved, fmt.Sprintf("the transitive dependencies are not listed, only the ones %s declares", EnvironmentFile))
+	modules := []models.Module{*root}
+
+	listed := map[string]bool{}
+	for _, s := range env.Specs {
+		key := packageKey(s.Manager, s.Name)
+		if listed[key] {
+			continue
+		}
+		listed[key] = true
+
+		mod := models.Module{
+			Name:                    s.Name,
+			Version:                 s.Version,
+			PackageURL:              packageURL(s.Manager, s.Name, s.Version, condaFile{Channel: s.Channel, Build: s.Build}),
+			PackageDownloadLocation: "NOASSERTION",
+			Modules:                 map[string]*models.Module{},
+		}
+		if s.Version == "" {
+			constraint := s.Constraint
+			if constraint == "" {
+				constraint = "any version"
+			}
+			mod.Unresolved = append(mod.Unresolved, fmt.Sprintf("%s requires %s, the installed version is unknown", EnvironmentFile, constraint))
+		}
+		modules = append(modules, mod)
+		helper.LinkModule(modules, 0, len(modules)-1, "")
+	}
+	return modules
+}
+
+// rootModule returns the package of the environment, with the license of the project files
+func rootModule(path string, env *environment, matcher helper.LicenseMatcher) *models.Module {
+	name := filepath.Base(path)
+	if env != nil && env.Name != "" {
+		name = env.Name
+	}
+
+	mod := &models.Module{
+		Name:                    name,
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(name),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	helper.SetLicense(matcher, path, mod)
+	return mod
+}
+
+// lockPackageModule returns the package of the lockfile, a conda one is identified by its channel,
+// subdir and build string
+func lockPackageModule(pkg *lockPackage) models.Module {
+	file, _ := parseCondaURL(pkg.URL, pkg.Name, pkg.Version)
+	mod := models.Module{
+		Name:                    pkg.Name,
+		Version:                 pkg.Version,
+		PackageURL:              packageURL(pkg.Manager, pkg.Name, pkg.Version, file),
+		PackageDownloadLocation: pkg.URL,
+		CheckSum:                pkg.checksum(),
+		Modules:                 map[string]*models.Module{},
+	}
+	if mod.PackageDownloadLocation == "" {
+		mod.PackageDownloadLocation = "NOASSERTION"
+	}
+	return mod
+}
+
+// packageURL returns the pkg:conda purl of a conda package, with the qualifiers its file tells,
+// or the pkg:pypi one of a pip package
+func packageURL(manager, name, version string, file condaFile) string {
+	if manager == managerPip {
+		return purl.New("pypi", "", worker.NormalizeName(name), version).String()
+	}
+	return purl.New("conda", "", name, version).
+		WithQualifier("build", file.Build).
+		WithQualifier("channel", file.Channel).
+		WithQualifier("subdir", file.Subdir).
+		WithQualifier("type", file.Extension).
+		String()
+}
+
+// packageKey identifies a package of an environment, the pip names are compared normalized
+func packageKey(manager, name string) string {
+	if manager == managerPip {
+		return managerPip + "/" + worker.NormalizeName(name)
+	}
+	return manager + "/" + strings.ToLower(name)
+}
+
+// lookupPackage returns the index of a dependency, a package of the same manager or else a conda one,
+// e.g. the python a pip package depends on
+func lookupPackage(index map[string]int, manager, name string) (int, bool) {
+	if i, ok := index[packageKey(manager, name)]; ok {
+		return i, true
+	}
+	i, ok := index[packageKey(managerConda, name)]
+	return i, ok
+}
+
+// selectPlatform returns the platform of the lockfile packages to list: the current one when locked,
+// else the first one
+func selectPlatform(platforms []string, current string) string {
+	for _, platform := range platforms {
+		if platform == current {
+			return platform
+		}
+	}
+	if len(platforms) > 0 {
+		return platforms[0]
+	}
+	return current
+}
+
+// currentPlatform returns the conda subdir of the platform running the generator, e.g. linux-64 or osx-arm64
+func currentPlatform() string {
+	system := map[string]string{"darwin": "osx", "windows": "win"}[runtime.GOOS]
+	if system == "" {
+		system = runtime.GOOS
+	}
+	architecture := platformArchitectures[runtime.GOARCH]
+	if architecture == "" {
+		architecture = runtime.GOARCH
+	}
+	if system == "linux" && architecture == "arm64" {
+		architecture = "aarch64"
+	}
+	return system + "-" + architecture
+}
+
+// environmentFile returns the path of the environment file of dir, "" when it has none
+func environmentFile(dir string) string {
+	for _, name := range environmentFiles {
+		if file := filepath.Join(dir, name); helper.Exists(file) {
+			return file
+		}
+	}
+	return ""
+}
+
+// readEnvironment reads the environment file of dir, nil when it has none
+func readEnvironment(dir string) (*environment, error) {
+	path := environmentFile(dir)
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseEnvironment(path, file)
+}
+
+func readLockfile(path string) (*lockfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseLockfile(path, file)
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "lock")))
+	assert.True(t, m.IsValid(filepath.Join("testdata", "environment")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+}
+
+func TestListLockModules(t *testing.T) {
+	root, err := New().GetRootModule(filepath.Join("testdata", "lock"))
+	assert.NoError(t, err)
+	env, err := readEnvironment(filepath.Join("testdata", "lock"))
+	assert.NoError(t, err)
+	lock, err := readLockfile(filepath.Join("testdata", "lock", LockFile))
+	assert.NoError(t, err)
+
+	modules := lockModules(root, env, lock, "linux-64")
+	assert.Len(t, modules, 6)
+	assert.Equal(t, "locked", modules[0].Name)
+	assert.Equal(t, []string{"numpy", "python", "requests"}, modulestest.LinkedNames(modules[0]))
+
+	byName := modulestest.ByName(modules)
+	python := byName["python"]
+	assert.Equal(t, "pkg:conda/python@3.11.4?build=hab00c5b_0_cpython&channel=conda-forge&subdir=linux-64&type=conda", python.PackageURL)
+	assert.Equal(t, "https://conda.anaconda.org/conda-forge/linux-64/python-3.11.4-hab00c5b_0_cpython.conda", python.PackageDownloadLocation)
+	assert.Equal(t, []string{"libzlib"}, modulestest.LinkedNames(python))
+
+	libzlib := byName["libzlib"]
+	assert.Equal(t, "pkg:conda/libzlib@1.2.13?build=hd590300_5&channel=main&subdir=linux-64&type=tar.bz2", libzlib.PackageURL)
+	assert.Equal(t, models.HashAlgoMD5, libzlib.CheckSum.Algorithm)
+
+	assert.Equal(t, "pkg:conda/numpy@1.25.2?build=py311h64a7726_0&channel=https:%2F%2Fconda.example.com%2Finternal&subdir=linux-64&type=conda", byName["numpy"].PackageURL)
+
+	// the pip packages depend on the conda python
+	requests := byName["requests"]
+	assert.Equal(t, "pkg:pypi/requests@2.31.0", requests.PackageURL)
+	assert.Equal(t, []string{"certifi", "python"}, modulestest.LinkedNames(requests))
+}
+
+func TestListLockModulesWithoutEnvironment(t *testing.T) {
+	lock, err := parseLockfile(LockFile, strings.NewReader(`version: 1
+metadata:
+  platforms:
+  - linux-64
+package:
+- name: python
+  version: 3.11.4
+  manager: conda
+  platform: linux-64
+  dependencies: {}
+  url: https://conda.anaconda.org/conda-forge/linux-64/python-3.11.4-hab00c5b_0_cpython.conda
+  hash:
+    md5: abc
+  category: main
+- name: pytest
+  version: 7.4.0
+  manager: conda
+  platform: linux-64
+  dependencies:
+    python: '>=3.7'
+  url: https://conda.anaconda.org/conda-forge/noarch/pytest-7.4.0-pyhd8ed1ab_0.conda
+  hash:
+    md5: def
+  category: dev
+`))
+	assert.NoError(t, err)
+
+	modules := lockModules(&models.Module{Name: "app", Root: true, Modules: map[string]*models.Module{}}, nil, lock, selectPlatform(lock.Platforms, "osx-arm64"))
+	// the packages no package depends on are the direct ones
+	assert.Equal(t, []string{"pytest"}, modulestest.LinkedNames(modules[0]))
+	assert.Equal(t, models.RelationshipDevDependencyOf, modules[0].Modules["pytest"].Relationship)
+}
+
+func TestListEnvironmentModules(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "environment"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 9)
+
+	root := modules[0]
+	assert.Equal(t, "analysis", root.Name)
+	assert.Len(t, root.Modules, 8)
+	assert.NotEmpty(t, root.Unresolved)
+
+	byName := modulestest.ByName(modules)
+	assert.Equal(t, "pkg:conda/pandas@2.0.3?build=py311h320fe9a_1&channel=conda-forge", byName["pandas"].PackageURL)
+	assert.Equal(t, "pkg:conda/scipy@1.11.1?build=py311h64a7726_0", byName["scipy"].PackageURL)
+	assert.Equal(t, "pkg:pypi/requests@2.31.0", byName["requests"].PackageURL)
+	assert.Equal(t, "pkg:pypi/flask-login", byName["Flask_Login"].PackageURL)
+	assert.Equal(t, []string{"environment.yml requires >=1.24, the installed version is unknown"}, byName["numpy"].Unresolved)
+	assert.Empty(t, byName["pandas"].Annotations)
+}
+
+func TestSelectPlatform(t *testing.T) {
+	assert.Equal(t, "osx-arm64", selectPlatform([]string{"linux-64", "osx-arm64"}, "osx-arm64"))
+	assert.Equal(t, "linux-64", selectPlatform([]string{"linux-64", "osx-arm64"}, "win-64"))
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// mainCategory is the category of the dependencies the environment needs at runtime
+const mainCategory = "main"
+
+// condaExtensions are the extensions of the conda package files, the purl type of the ones conda installs
+var condaExtensions = []string{".tar.bz2", ".conda"}
+
+// lockPackage is a package of a conda-lock.yml, installed by conda or pip for one platform
+type lockPackage struct {
+	Name     string
+	Version  string
+	Manager  string
+	Platform string
+	URL      string
+	Category string
+	MD5      string
+	SHA256   string
+	// Dependencies are the names of the packages it depends on
+	Dependencies []string
+}
+
+// lockfile is a conda-lock.yml of the unified format, version 1
+type lockfile struct {
+	Version   string
+	Platforms []string
+	Packages  []*lockPackage
+}
+
+// parseLockfile reads a conda-lock.yml, the multi-platform file conda-lock writes since its version 1
+func parseLockfile(fileName string, r io.Reader) (*lockfile, error) {
+	document, err := reader.ParseYAML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &lockfile{Version: document.Str("version")}
+	if l.Version != "1" {
+		return nil, fmt.Errorf("%w: %q in %s", errUnsupportedLockfileVersion, l.Version, fileName)
+	}
+
+	metadata := document.Get("metadata")
+	for _, platform := range metadata.List("platforms") {
+		l.Platforms = append(l.Platforms, platform.Value)
+	}
+
+	for _, entry := range document.List("package") {
+		pkg := &lockPackage{
+			Name:     entry.Str("name"),
+			Version:  entry.Str("version"),
+			Manager:  entry.Str("manager"),
+			Platform: entry.Str("platform"),
+			URL:      entry.Str("url"),
+			Category: entry.Str("category"),
+			MD5:      entry.Get("hash").Str("md5"),
+			SHA256:   entry.Get("hash").Str("sha256"),
+		}
+		if pkg.Name == "" {
+			continue
+		}
+		if pkg.Manager == "" {
+			pkg.Manager = managerConda
+		}
+		if pkg.Category == "" {
+			pkg.Category = mainCategory
+		}
+		if dependencies := entry.Get("dependencies"); dependencies != nil {
+			pkg.Dependencies = append(pkg.Dependencies, dependencies.Keys...)
+		}
+		l.Packages = append(l.Packages, pkg)
+	}
+	return l, nil
+}
+
+// platformPackages returns the packages of the platform, a package of several categories is listed once
+func (l *lockfile) platformPackages(platform string) []*lockPackage {
+	var packages []*lockPackage
+	listed := map[string]*lockPackage{}
+	for _, pkg := range l.Packages {
+		if pkg.Platform != platform {
+			continue
+		}
+		key := pkg.Manager + "/" + pkg.Name
+		if existing, ok := listed[key]; ok {
+			if pkg.Category == mainCategory {
+				existing.Category = mainCategory
+			}
+			continue
+		}
+		listed[key] = pkg
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// checksum returns the sha256 hash of the package, else its md5 one
+func (p *lockPackage) checksum() *models.CheckSum {
+	switch {
+	case p.SHA256 != "":
+		return &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: p.SHA256}
+	case p.MD5 != "":
+		return &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: p.MD5}
+	}
+	return nil
+}
+
+// condaFile is the channel, subdir and build a conda package file url tells, e.g.
+// https://conda.anaconda.org/conda-forge/linux-64/numpy-1.24.3-py311h64a7726_0.conda
+type condaFile struct {
+	Channel   string
+	Subdir    string
+	Build     string
+	Extension string
+}
+
+// parseCondaURL returns the channel, subdir, build string and extension of the file of a conda package.
+// The channels of anaconda.org are named, e.g. conda-forge, the ones of repo.anaconda.com by their name
+// under pkgs, e.g. main, the other ones by their url
+func parseCondaURL(rawURL, name, version string) (condaFile, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return condaFile{}, false
+	}
+
+	var file condaFile
+	base := path.Base(u.Path)
+	for _, extension := range condaExtensions {
+		if strings.HasSuffix(base, extension) {
+			file.Extension = strings.TrimPrefix(extension, ".")
+			base = strings.TrimSuffix(base, extension)
+		}
+	}
+	if file.Extension == "" {
+		return condaFile{}, false
+	}
+	file.Build = strings.TrimPrefix(base, name+"-"+version+"-")
+	if file.Build == base {
+		file.Build = ""
+	}
+
+	dir := path.Dir(u.Path)
+	file.Subdir = path.Base(dir)
+	channelPath := strings.Trim(path.Dir(dir), "/")
+	switch {
+	case u.Host == "conda.anaconda.org":
+		file.Channel = channelPath
+	case u.Host == "repo.anaconda.com" && strings.HasPrefix(channelPath, "pkgs/"):
+		file.Channel = strings.TrimPrefix(channelPath, "pkgs/")
+	default:
+		file.Channel = fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, channelPath)
+	}
+	return file, true
+}
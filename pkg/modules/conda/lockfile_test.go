@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conda
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLockfile(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "lock", LockFile))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linux-64", "osx-arm64"}, lock.Platforms)
+	assert.Len(t, lock.Packages, 6)
+
+	packages := lock.platformPackages("linux-64")
+	assert.Len(t, packages, 5)
+	python := packages[0]
+	assert.Equal(t, "python", python.Name)
+	assert.Equal(t, managerConda, python.Manager)
+	assert.Equal(t, []string{"libzlib"}, python.Dependencies)
+	assert.Equal(t, "11a4a60b518bf24989d481468076e5d5982884626aed9faeb35b8576fcd223e1", python.checksum().Value)
+
+	// libzlib only has an md5 hash
+	assert.Equal(t, "84f974ecf7039d0eb1e6d5e18e94e659", packages[1].checksum().Value)
+	assert.Equal(t, []string{"certifi", "python"}, packages[3].Dependencies)
+	assert.Len(t, lock.platformPackages("osx-arm64"), 1)
+}
+
+func TestParseLockfileUnsupportedVersion(t *testing.T) {
+	_, err := parseLockfile(LockFile, strings.NewReader("version: 2\npackage: []\n"))
+	assert.True(t, errors.Is(err, errUnsupportedLockfileVersion))
+}
+
+func TestParseCondaURL(t *testing.T) {
+	file, ok := parseCondaURL("https://conda.anaconda.org/conda-forge/linux-64/python-3.11.4-hab00c5b_0_cpython.conda", "python", "3.11.4")
+	assert.True(t, ok)
+	assert.Equal(t, condaFile{Channel: "conda-forge", Subdir: "linux-64", Build: "hab00c5b_0_cpython", Extension: "conda"}, file)
+
+	file, ok = parseCondaURL("https://repo.anaconda.com/pkgs/main/noarch/tzdata-2023c-h04d1e81_0.tar.bz2", "tzdata", "2023c")
+	assert.True(t, ok)
+	assert.Equal(t, condaFile{Channel: "main", Subdir: "noarch", Build: "h04d1e81_0", Extension: "tar.bz2"}, file)
+
+	file, ok = parseCondaURL("https://conda.example.com/internal/linux-64/numpy-1.25.2-py311h64a7726_0.conda", "numpy", "1.25.2")
+	assert.True(t, ok)
+	assert.Equal(t, "https://conda.example.com/internal", file.Channel)
+
+	_, ok = parseCondaURL("https://files.pythonhosted.org/packages/requests-2.31.0-py3-none-any.whl", "requests", "2.31.0")
+	assert.False(t, ok)
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// the statements of a cpanfile, matched at the position the cpanfile is read at
+var (
+	// cpanfileRequirement is a requirement, e.g. requires 'Plack', '1.0'; or test_requires "Test::More"
+	cpanfileRequirement = regexp.MustCompile(`^(requires|recommends|suggests|test_requires|author_requires|build_requires|configure_requires)\b\s*\(?\s*['"]([\w:]+)['"]`)
+	// cpanfilePhase opens the block of the requirements of a phase, e.g. on 'test' => sub {
+	cpanfilePhase = regexp.MustCompile(`^on\s*\(?\s*['"]?(\w+)['"]?\s*=>\s*sub\s*\{`)
+	// cpanfileFeature opens the block of the requirements of an optional feature, e.g. feature 'sqlite', 'SQLite support' => sub {
+	cpanfileFeature = regexp.MustCompile(`^feature\b[^{;]*=>\s*sub\s*\{`)
+)
+
+// requirement is a module the project requires and how it relates to the project
+type requirement struct {
+	Module       string
+	Relationship models.RelationshipType
+}
+
+// readCpanfile reads the requirements of a cpanfile. The file is Perl code so it is not evaluated: the
+// requirements are matched line by line and the phase of the on blocks they are in is tracked with the
+// braces. The test and develop requirements are DEV_DEPENDENCY_OF the project, the configure and build
+// ones BUILD_DEPENDENCY_OF it and the recommended, suggested and feature ones OPTIONAL_DEPENDENCY_OF it
+func readCpanfile(path string) ([]requirement, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	type block struct {
+		phase   string
+		feature bool
+	}
+	blocks := []block{{phase: "runtime"}}
+
+	var requirements []requirement
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := stripPerlComment(scanner.Text())
+		for pos := 0; pos < len(line); pos++ {
+			current := blocks[len(blocks)-1]
+			// the statements start at a word boundary
+			if pos > 0 && isWordChar(line[pos-1]) {
+				continue
+			}
+
+			rest := line[pos:]
+			if match := cpanfileRequirement.FindStringSubmatch(rest); match != nil {
+				phase, kind := current.phase, match[1]
+				if i := strings.Index(kind, "_"); i >= 0 {
+					phase, kind = kind[:i], "requires"
+				}
+				requirements = append(requirements, requirement{Module: match[2], Relationship: relationship(phase, kind, current.feature)})
+				pos += len(match[0]) - 1
+			} else if match := cpanfilePhase.FindStringSubmatch(rest); match != nil {
+				blocks = append(blocks, block{phase: match[1], feature: current.feature})
+				pos += len(match[0]) - 1
+			} else if match := cpanfileFeature.FindString(rest); match != "" {
+				blocks = append(blocks, block{phase: current.phase, feature: true})
+				pos += len(match) - 1
+			} else if line[pos] == '{' {
+				blocks = append(blocks, current)
+			} else if line[pos] == '}' && len(blocks) > 1 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		}
+	}
+	return requirements, scanner.Err()
+}
+
+// relationship returns the relationship of a requirement of a phase, e.g. recommends of test
+func relationship(phase, kind string, feature bool) models.RelationshipType {
+	switch {
+	case feature || kind == "recommends" || kind == "suggests":
+		return models.RelationshipOptionalDependencyOf
+	case phase == "test" || phase == "develop" || phase == "author":
+		return models.RelationshipDevDependencyOf
+	case phase == "configure" || phase == "build":
+		return models.RelationshipBuildDependencyOf
+	}
+	return ""
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// stripPerlComment removes the # comment of a line, a # in a quoted string is kept
+func stripPerlComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no cpanfile.snapshot or installed distribution found. Please install the dependencies before running spdx-sbom-generator, e.g.: `carton install`")
+)
@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type cpan struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ManifestFile string = "cpanfile"
+	SnapshotFile string = "cpanfile.snapshot"
+	MetaFile     string = "META.json"
+	MyMetaFile   string = "MYMETA.json"
+	// LocalDir is the directory carton installs the distributions to
+	LocalDir string = "local"
+)
+
+// CacheDir is the directory carton bundle copies the archives of the distributions to
+var CacheDir = filepath.Join("vendor", "cache")
+
+const (
+	// cpanURL is the directory of the archives the authors upload to CPAN
+	cpanURL = "https://cpan.metacpan.org/authors/id"
+	// metacpanURL is the search engine of CPAN, the distributions have a page on it
+	metacpanURL = "https://metacpan.org"
+)
+
+// New creates a new cpan instance
+func New() *cpan {
+	return &cpan{
+		metadata: models.PluginMetadata{
+			Name:       "CPAN Carton",
+			Slug:       "cpan",
+			Manifest:   []string{ManifestFile, SnapshotFile},
+			ModulePath: []string{LocalDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *cpan) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the carton version
+func (m *cpan) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "carton", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *cpan) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *cpan) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the distribution of the META.json of the project, named after its directory when it has none
+func (m *cpan) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := rootModule(absPath, m.options.LicenseMatcher)
+	return root, nil
+}
+
+// ListUsedModules returns the distributions of the project, without the project itself
+func (m *cpan) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the distributions of its cpanfile.snapshot, else
+// the ones installed to its local directory, linked to the distributions of the modules they require
+func (m *cpan) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := readInstalled(filepath.Join(absPath, LocalDir))
+	var dists []*distribution
+	if snapshotPath := filepath.Join(absPath, SnapshotFile); helper.Exists(snapshotPath) {
+		if dists, err = readSnapshot(snapshotPath); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, dist := range installed {
+			dists = append(dists, &dist.distribution)
+		}
+		sort.Slice(dists, func(i, j int) bool { return dists[i].Name < dists[j].Name })
+	}
+	if len(dists) == 0 {
+		return nil, errDependenciesNotFound
+	}
+
+	root, meta := rootModule(absPath, m.options.LicenseMatcher)
+	var requirements []requirement
+	if cpanfilePath := filepath.Join(absPath, ManifestFile); helper.Exists(cpanfilePath) {
+		if requirements, err = readCpanfile(cpanfilePath); err != nil {
+			return nil, err
+		}
+	} else if meta != nil {
+		requirements = meta.projectRequirements()
+	}
+	return distModules(root, requirements, dists, installed, filepath.Join(absPath, CacheDir)), nil
+}
+
+// IsValid checks if a cpanfile or a cpanfile.snapshot exists
+func (m *cpan) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ManifestFile)) || helper.Exists(filepath.Join(path, SnapshotFile))
+}
+
+// HasModulesInstalled checks the distributions are locked by a cpanfile.snapshot or installed to the local directory
+func (m *cpan) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, SnapshotFile)) || len(readInstalled(filepath.Join(path, LocalDir))) > 0 {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// rootModule returns the distribution of the project described by its META.json, else its MYMETA.json,
+// with the license of the project files else the one of its meta
+func rootModule(path string, matcher helper.LicenseMatcher) (*models.Module, *distMeta) {
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+
+	var meta *distMeta
+	for _, file := range []string{MetaFile, MyMetaFile} {
+		if m, err := readMeta(filepath.Join(path, file)); err == nil && m.Name != "" {
+			meta = m
+			break
+		}
+	}
+	if meta != nil {
+		mod.Name = meta.Name
+		mod.Version = meta.version()
+		if supplier, ok := meta.supplier(); ok {
+			mod.Supplier = supplier
+		}
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicense(matcher, path, mod)
+	if mod.LicenseDeclared == "" && meta != nil {
+		helper.SetLicenseExpression(mod, meta.licenseExpression())
+	}
+	return mod, meta
+}
+
+// distModules returns the root followed by the distributions. A distribution depends on the ones providing
+// the modules it requires, the modules of perl itself are not listed. The root is linked to the distributions
+// of the modules of its requirements, else to the distributions no distribution requires
+func distModules(root *models.Module, requirements []requirement, dists []*distribution, installed map[string]*installedDist, cacheDir string) []models.Module {
+	modules := []models.Module{*root}
+	providers := map[string]int{}
+	for _, dist := range dists {
+		i := len(modules)
+		modules = append(modules, distModule(dist, installed[dist.Name], cacheDir))
+		for module := range dist.Provides {
+			providers[module] = i
+		}
+	}
+
+	required := map[int]bool{}
+	for i, dist := range dists {
+		for _, module := range dist.Requirements {
+			if j, ok := providers[module]; ok {
+				helper.LinkModule(modules, i+1, j, "")
+				required[j] = true
+			}
+		}
+	}
+
+	linked := false
+	for _, req := range requirements {
+		i, ok := providers[req.Module]
+		if !ok {
+			continue
+		}
+		linked = true
+		// a distribution required by several phases keeps the relationship of the first one unless
+		// a later one requires it at runtime
+		if dependency, ok := modules[0].Modules[modules[i].Name]; ok && (dependency.Relationship == "" || req.Relationship != "") {
+			continue
+		}
+		helper.LinkModule(modules, 0, i, req.Relationship)
+	}
+	if !linked {
+		for i := 1; i < len(modules); i++ {
+			if !required[i] {
+				helper.LinkModule(modules, 0, i, "")
+			}
+		}
+	}
+	return modules
+}
+
+// distModule returns the package of a distribution, downloaded from the archive its author uploaded to
+// CPAN. The checksum is the sha256 hash of the archive carton bundle copied, the author and the license
+// are the ones of the meta of the installed distribution
+func distModule(dist *distribution, installed *installedDist, cacheDir string) models.Module {
+	author := dist.author()
+	mod := models.Module{
+		Name:                    dist.Name,
+		Version:                 dist.Version,
+		PackageURL:              purl.New("cpan", author, dist.Name, dist.Version).String(),
+		PackageHomePage:         fmt.Sprintf("%s/dist/%s", metacpanURL, dist.Name),
+		PackageDownloadLocation: "NOASSERTION",
+		Supplier:                models.SupplierContact{Name: dist.Name},
+		Modules:                 map[string]*models.Module{},
+	}
+	if author != "" {
+		mod.PackageDownloadLocation = fmt.Sprintf("%s/%s", cpanURL, dist.Pathname)
+		mod.Supplier = models.SupplierContact{Name: author, Email: fmt.Sprintf("%s@cpan.org", strings.ToLower(author)), Type: models.Person}
+		if checksum, err := fileSHA256(filepath.Join(cacheDir, "authors", "id", filepath.FromSlash(dist.Pathname))); err == nil {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: checksum}
+		}
+	}
+
+	if installed != nil && installed.Meta != nil {
+		if supplier, ok := installed.Meta.supplier(); ok {
+			mod.Supplier = supplier
+		}
+		helper.SetLicenseExpression(&mod, installed.Meta.licenseExpression())
+		if homepage := installed.Meta.Resources.Homepage; homepage != "" {
+			mod.PackageHomePage = homepage
+		}
+	}
+	return mod
+}
+
+// fileSHA256 returns the sha256 hash of a file
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "app")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "Shop-App", root.Name)
+	assert.Equal(t, "0.01", root.Version)
+	assert.Equal(t, "Artistic-1.0-Perl OR GPL-1.0-or-later", root.LicenseDeclared)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+	relationships := map[string]models.RelationshipType{}
+	for name, mod := range root.Modules {
+		relationships[name] = mod.Relationship
+	}
+	assert.Equal(t, map[string]models.RelationshipType{
+		"Plack":       "",
+		"Try-Tiny":    "",
+		"Test-Simple": models.RelationshipDevDependencyOf,
+		"Class-Tiny":  models.RelationshipDevDependencyOf,
+	}, relationships)
+
+	classTiny := modules[1]
+	assert.Equal(t, "pkg:cpan/DAGOLDEN/Class-Tiny@1.008", classTiny.PackageURL)
+	assert.Equal(t, "https://cpan.metacpan.org/authors/id/D/DA/DAGOLDEN/Class-Tiny-1.008.tar.gz", classTiny.PackageDownloadLocation)
+	assert.Equal(t, "https://metacpan.org/dist/Class-Tiny", classTiny.PackageHomePage)
+	assert.Equal(t, "Person: DAGOLDEN (dagolden@cpan.org)", classTiny.Supplier.Get())
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "6fd74a1b43bcff26214b19f00ea0b02625afe1ef12197da8534cbd6ff2f4d058"}, classTiny.CheckSum)
+
+	plack := modules[2]
+	assert.Nil(t, plack.CheckSum)
+	assert.Contains(t, plack.Modules, "Try-Tiny")
+	assert.Len(t, plack.Modules, 1)
+
+	tryTiny := modules[4]
+	assert.Equal(t, "MIT", tryTiny.LicenseDeclared)
+	assert.Equal(t, "Person: Yuval Kogman (nothingmuch@woobling.org)", tryTiny.Supplier.Get())
+	assert.Equal(t, "https://github.com/p5sagit/Try-Tiny", tryTiny.PackageHomePage)
+}
+
+func TestListModulesWithDepsInstalled(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "installed")
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 3) {
+		return
+	}
+
+	assert.Equal(t, "installed", modules[0].Name)
+	assert.Len(t, modules[0].Modules, 1)
+	assert.Contains(t, modules[0].Modules, "Plack")
+
+	plack := modules[1]
+	assert.Equal(t, "1.0050", plack.Version)
+	assert.Equal(t, "pkg:cpan/MIYAGAWA/Plack@1.0050", plack.PackageURL)
+	assert.Equal(t, "Artistic-1.0-Perl OR GPL-1.0-or-later", plack.LicenseDeclared)
+	assert.Len(t, plack.Modules, 1)
+	assert.Contains(t, plack.Modules, "Try-Tiny")
+}
+
+func TestHasModulesInstalled(t *testing.T) {
+	assert.Equal(t, errDependenciesNotFound, New().HasModulesInstalled("testdata"))
+	_, err := New().ListModulesWithDeps("testdata")
+	assert.Equal(t, errDependenciesNotFound, err)
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// metaLicenses maps the license codes of the CPAN meta spec to SPDX expressions, the codes of no
+// specific license, e.g. open_source or unknown, are not mapped
+var metaLicenses = map[string]string{
+	"agpl_3":      "AGPL-3.0-only",
+	"apache_1_1":  "Apache-1.1",
+	"apache_2_0":  "Apache-2.0",
+	"artistic_1":  "Artistic-1.0",
+	"artistic_2":  "Artistic-2.0",
+	"bsd":         "BSD-3-Clause",
+	"freebsd":     "BSD-2-Clause",
+	"gfdl_1_2":    "GFDL-1.2-only",
+	"gfdl_1_3":    "GFDL-1.3-only",
+	"gpl_1":       "GPL-1.0-only",
+	"gpl_2":       "GPL-2.0-only",
+	"gpl_3":       "GPL-3.0-only",
+	"lgpl_2_1":    "LGPL-2.1-only",
+	"lgpl_3_0":    "LGPL-3.0-only",
+	"mit":         "MIT",
+	"mozilla_1_0": "MPL-1.0",
+	"mozilla_1_1": "MPL-1.1",
+	"openssl":     "OpenSSL",
+	"perl_5":      "Artistic-1.0-Perl OR GPL-1.0-or-later",
+	"qpl_1_0":     "QPL-1.0",
+	"sun":         "SISSL",
+	"zlib":        "Zlib",
+}
+
+// metaAuthor is an author of the meta spec, e.g. Tatsuhiko Miyagawa <miyagawa@bulknews.net>
+var metaAuthor = regexp.MustCompile(`^\s*([^<]*?)\s*<([^>]+)>\s*$`)
+
+// distMeta is the part of a META.json or a MYMETA.json the packages are described with
+type distMeta struct {
+	Name    string          `json:"name"`
+	Version json.RawMessage `json:"version"`
+	Author  []string        `json:"author"`
+	License []string        `json:"license"`
+	// Prereqs maps the phases to the kinds of requirements and these to the modules and their versions
+	Prereqs   map[string]map[string]map[string]interface{} `json:"prereqs"`
+	Resources struct {
+		Homepage   string `json:"homepage"`
+		Repository struct {
+			URL string `json:"url"`
+			Web string `json:"web"`
+		} `json:"repository"`
+	} `json:"resources"`
+}
+
+// installed is the install.json cpanm writes to the .meta directory of an installed distribution
+type installed struct {
+	Dist     string `json:"dist"`
+	Pathname string `json:"pathname"`
+	Provides map[string]struct {
+		Version json.RawMessage `json:"version"`
+	} `json:"provides"`
+}
+
+// installedDist is a distribution installed to the local directory of the project
+type installedDist struct {
+	distribution
+	Meta *distMeta
+}
+
+func readMeta(path string) (*distMeta, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	meta := &distMeta{}
+	if err := reader.DecodeJSON(path, data, meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// version returns the version of a distribution, the meta spec allows it to be a number
+func (m *distMeta) version() string {
+	return jsonVersion(m.Version)
+}
+
+// runtimeModules returns the modules the runtime phase requires, sorted
+func (m *distMeta) runtimeModules() []string {
+	var modules []string
+	for module := range m.Prereqs["runtime"]["requires"] {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// projectRequirements returns the requirements of all the phases of the distribution of the project,
+// sorted by module
+func (m *distMeta) projectRequirements() []requirement {
+	var requirements []requirement
+	for phase, kinds := range m.Prereqs {
+		for kind, modules := range kinds {
+			if kind == "conflicts" {
+				continue
+			}
+			for module := range modules {
+				requirements = append(requirements, requirement{Module: module, Relationship: relationship(phase, kind, false)})
+			}
+		}
+	}
+	sort.Slice(requirements, func(i, j int) bool {
+		if requirements[i].Module != requirements[j].Module {
+			return requirements[i].Module < requirements[j].Module
+		}
+		return requirements[i].Relationship < requirements[j].Relationship
+	})
+	return requirements
+}
+
+// supplier returns the first author of the distribution
+func (m *distMeta) supplier() (models.SupplierContact, bool) {
+	for _, author := range m.Author {
+		if match := metaAuthor.FindStringSubmatch(author); match != nil && match[1] != "" {
+			return models.SupplierContact{Name: match[1], Email: match[2], Type: models.Person}, true
+		}
+		if name := strings.TrimSpace(author); name != "" && !strings.EqualFold(name, "unknown") {
+			return models.SupplierContact{Name: name, Type: models.Person}, true
+		}
+	}
+	return models.SupplierContact{}, false
+}
+
+// licenseExpression returns the expression of the licenses of the distribution, "" when one has no SPDX
+// id. The licenses are the ones the distribution is available under so they are alternatives
+func (m *distMeta) licenseExpression() string {
+	var parts []string
+	for _, code := range m.License {
+		expression, ok := metaLicenses[code]
+		if !ok || helper.SPDXExpression(expression) == "" {
+			return ""
+		}
+		if len(m.License) > 1 && strings.Contains(expression, " ") {
+			expression = "(" + expression + ")"
+		}
+		parts = append(parts, expression)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// readInstalled reads the distributions cpanm installed to the local directory, keyed by name. A
+// distribution without an install.json is skipped
+func readInstalled(localDir string) map[string]*installedDist {
+	dists := map[string]*installedDist{}
+	dirs, _ := filepath.Glob(filepath.Join(localDir, "lib", "perl5", "*", ".meta", "*"))
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "install.json"))
+		if err != nil {
+			continue
+		}
+		install := &installed{}
+		if err := json.Unmarshal(data, install); err != nil || install.Dist == "" {
+			continue
+		}
+
+		name, version := splitDist(install.Dist)
+		dist := &installedDist{distribution: distribution{
+			Name:     name,
+			Version:  version,
+			Pathname: install.Pathname,
+			Provides: map[string]string{},
+		}}
+		for module, provided := range install.Provides {
+			dist.Provides[module] = jsonVersion(provided.Version)
+		}
+		if meta, err := readMeta(filepath.Join(dir, "MYMETA.json")); err == nil {
+			dist.Meta = meta
+			dist.Requirements = meta.runtimeModules()
+		}
+		dists[name] = dist
+	}
+	return dists
+}
+
+// jsonVersion returns a version written as a string or as a number
+func jsonVersion(raw json.RawMessage) string {
+	var version string
+	if err := json.Unmarshal(raw, &version); err == nil {
+		return version
+	}
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String()
+	}
+	return ""
+}
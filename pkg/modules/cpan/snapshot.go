@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// distVersion is the version suffix of a distribution, e.g. -1.008 of Class-Tiny-1.008 or -v1.2.3
+var distVersion = regexp.MustCompile(`-(v?[0-9][0-9._]*(?:-TRIAL)?)$`)
+
+// distribution is a CPAN distribution, e.g. Class-Tiny-1.008
+type distribution struct {
+	Name    string
+	Version string
+	// Pathname is the path of the archive of the distribution on CPAN, e.g. D/DA/DAGOLDEN/Class-Tiny-1.008.tar.gz
+	Pathname string
+	// Provides maps the modules of the distribution to their versions
+	Provides map[string]string
+	// Requirements are the modules the distribution requires
+	Requirements []string
+}
+
+// author returns the CPAN id of the author who uploaded the distribution, the directory of its archive
+func (d *distribution) author() string {
+	parts := strings.Split(d.Pathname, "/")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// splitDist splits a distribution into its name and its version, the version is empty when it has none
+func splitDist(dist string) (string, string) {
+	match := distVersion.FindStringSubmatchIndex(dist)
+	if match == nil || match[0] == 0 {
+		return dist, ""
+	}
+	return dist[:match[0]], dist[match[2]:match[3]]
+}
+
+func readSnapshot(path string) ([]*distribution, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseSnapshot(path, file)
+}
+
+// parseSnapshot reads the distributions of a cpanfile.snapshot Carton writes:
+//
+//	# carton snapshot format: version 1.0
+//	DISTRIBUTIONS
+//	  Class-Tiny-1.008
+//	    pathname: D/DA/DAGOLDEN/Class-Tiny-1.008.tar.gz
+//	    provides:
+//	      Class::Tiny 1.008
+//	    requirements:
+//	      Carp 0
+func parseSnapshot(fileName string, r io.Reader) ([]*distribution, error) {
+	var dists []*distribution
+	var dist *distribution
+	section := ""
+	inDistributions := false
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		text := strings.TrimLeft(line, " ")
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		switch indent := len(line) - len(text); {
+		case indent == 0:
+			inDistributions = text == "DISTRIBUTIONS"
+			dist = nil
+		case !inDistributions:
+		case indent == 2:
+			name, version := splitDist(text)
+			dist = &distribution{Name: name, Version: version, Provides: map[string]string{}}
+			dists = append(dists, dist)
+			section = ""
+		case dist == nil:
+			return nil, reader.MalformedError(fileName, number, "unexpected indentation")
+		case indent == 4:
+			field := strings.SplitN(text, ":", 2)
+			if len(field) != 2 {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", text))
+			}
+			section = field[0]
+			if section == "pathname" {
+				dist.Pathname = strings.TrimSpace(field[1])
+			}
+		case indent == 6:
+			fields := strings.Fields(text)
+			switch section {
+			case "provides":
+				version := "undef"
+				if len(fields) > 1 {
+					version = fields[1]
+				}
+				dist.Provides[fields[0]] = version
+			case "requirements":
+				dist.Requirements = append(dist.Requirements, fields[0])
+			}
+		default:
+			return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", text))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dists, nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpan
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadSnapshot(t *testing.T) {
+	dists, err := readSnapshot(filepath.Join("testdata", "app", SnapshotFile))
+	assert.NoError(t, err)
+	if !assert.Len(t, dists, 4) {
+		return
+	}
+
+	plack := dists[1]
+	assert.Equal(t, "Plack", plack.Name)
+	assert.Equal(t, "1.0050", plack.Version)
+	assert.Equal(t, "MIYAGAWA", plack.author())
+	assert.Equal(t, map[string]string{"Plack": "1.0050", "Plack::Request": "1.0050", "Plack::Util": "undef"}, plack.Provides)
+	assert.Equal(t, []string{"ExtUtils::MakeMaker", "Try::Tiny", "perl"}, plack.Requirements)
+}
+
+func TestParseSnapshotMalformed(t *testing.T) {
+	_, err := parseSnapshot(SnapshotFile, strings.NewReader("DISTRIBUTIONS\n    pathname: D/DA/DAGOLDEN/Class-Tiny-1.008.tar.gz\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+
+	_, err = parseSnapshot(SnapshotFile, strings.NewReader("DISTRIBUTIONS\n  Class-Tiny-1.008\n    pathname D/DA/DAGOLDEN/Class-Tiny-1.008.tar.gz\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestSplitDist(t *testing.T) {
+	for dist, expected := range map[string][2]string{
+		"Class-Tiny-1.008":         {"Class-Tiny", "1.008"},
+		"libwww-perl-6.76":         {"libwww-perl", "6.76"},
+		"Module-Build-0.4234":      {"Module-Build", "0.4234"},
+		"version-0.9930":           {"version", "0.9930"},
+		"Moose-2.2206-TRIAL":       {"Moose", "2.2206-TRIAL"},
+		"Perl-Version-v1.2.3":      {"Perl-Version", "v1.2.3"},
+		"Test-Simple-1.302195_001": {"Test-Simple", "1.302195_001"},
+		"Unversioned":              {"Unversioned", ""},
+	} {
+		name, version := splitDist(dist)
+		assert.Equal(t, expected, [2]string{name, version}, dist)
+	}
+}
+
+func TestReadCpanfile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ManifestFile)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`requires 'Plack', '1.0047'; # requires 'Commented'
+requires "Moo" => "2.0";
+test_requires 'Test::Deep';
+recommends 'JSON::XS';
+on 'configure' => sub {
+    requires 'Module::Build::Tiny';
+};
+on test => sub { requires 'Test::More', '0.98'; };
+feature 'sqlite', 'SQLite support' => sub {
+    on runtime => sub {
+        requires 'DBD::SQLite';
+    };
+};
+author_requires 'Perl::Critic';
+`), 0644))
+
+	requirements, err := readCpanfile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []requirement{
+		{Module: "Plack"},
+		{Module: "Moo"},
+		{Module: "Test::Deep", Relationship: models.RelationshipDevDependencyOf},
+		{Module: "JSON::XS", Relationship: models.RelationshipOptionalDependencyOf},
+		{Module: "Module::Build::Tiny", Relationship: models.RelationshipBuildDependencyOf},
+		{Module: "Test::More", Relationship: models.RelationshipDevDependencyOf},
+		{Module: "DBD::SQLite", Relationship: models.RelationshipOptionalDependencyOf},
+		{Module: "Perl::Critic", Relationship: models.RelationshipDevDependencyOf},
+	}, requirements)
+}
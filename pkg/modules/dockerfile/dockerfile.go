@@ -0,0 +1,304 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// scratch is the empty image, FROM scratch has no base image
+const scratch = "scratch"
+
+var (
+	// escapeDirective is the parser directive changing the line continuation character, e.g. # escape=`
+	escapeDirective = regexp.MustCompile("^#\\s*escape\\s*=\\s*([\\\\`])\\s*$")
+	// heredocRegex matches the heredocs of an instruction, e.g. RUN <<EOF or COPY <<-"EOT" /file
+	heredocRegex = regexp.MustCompile(`<<-?(["']?)([A-Za-z_][A-Za-z0-9_]*)(["']?)`)
+	// variableRegex matches the variables of an instruction, e.g. $VERSION, ${VERSION} or ${VERSION:-1.0}
+	variableRegex = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+)
+
+// instruction is an instruction of a Dockerfile, its continuation lines joined
+type instruction struct {
+	line    int
+	command string
+	args    string
+}
+
+// String returns the instruction as written, e.g. FROM golang:1.21 AS build
+func (i instruction) String() string {
+	return strings.TrimSpace(i.command + " " + i.args)
+}
+
+// stage is a build stage of a Dockerfile, started by a FROM instruction
+type stage struct {
+	name     string
+	from     instruction
+	image    string
+	platform string
+	// parent is the index of the earlier stage the stage is built from, -1 when it is built from an image
+	parent int
+	// copies are the images files are copied from with COPY --from, with their instruction
+	copies []stageCopy
+}
+
+type stageCopy struct {
+	image string
+	from  instruction
+}
+
+// baseImage is an image a Dockerfile is built from, or copies files from
+type baseImage struct {
+	reference    imageRef
+	platform     string
+	from         instruction
+	relationship models.RelationshipType
+}
+
+// parseInstructions returns the instructions of the Dockerfile, without the comments nor the heredoc bodies
+func parseInstructions(fileName string, r io.Reader) ([]instruction, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	escape := `\`
+	var instructions []instruction
+	var current *instruction
+	heredocs := []string{}
+	directives := true
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+
+		if len(heredocs) > 0 {
+			if strings.TrimLeft(text, "\t") == heredocs[0] {
+				heredocs = heredocs[1:]
+			}
+			continue
+		}
+		if directives {
+			if match := escapeDirective.FindStringSubmatch(trimmed); match != nil {
+				escape = match[1]
+				continue
+			}
+			directives = strings.HasPrefix(trimmed, "#") && strings.Contains(trimmed, "=")
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if current == nil {
+			command, rest := trimmed, ""
+			if i := strings.IndexAny(trimmed, " \t"); i >= 0 {
+				command, rest = trimmed[:i], strings.TrimSpace(trimmed[i:])
+			}
+			current = &instruction{line: line, command: strings.ToUpper(command)}
+			if strings.HasSuffix(current.command, escape) {
+				current.command = strings.TrimSuffix(current.command, escape)
+				continue
+			}
+			trimmed = rest
+		}
+
+		continued := strings.HasSuffix(trimmed, escape)
+		trimmed = strings.TrimSuffix(trimmed, escape)
+		current.args = strings.TrimSpace(current.args + " " + trimmed)
+		if continued {
+			continue
+		}
+
+		switch current.command {
+		case "RUN", "COPY", "ADD":
+			for _, match := range heredocRegex.FindAllStringSubmatch(current.args, -1) {
+				heredocs = append(heredocs, match[2])
+			}
+		}
+		instructions = append(instructions, *current)
+		current = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, reader.MalformedError(fileName, line, err.Error())
+	}
+	// a continuation on the last line ends the instruction, as docker does
+	if current != nil {
+		instructions = append(instructions, *current)
+	}
+	return instructions, nil
+}
+
+// parseStages returns the build stages of the Dockerfile instructions, the variables of their images
+// expanded with the defaults of the ARG instructions before the first FROM
+func parseStages(fileName string, instructions []instruction) ([]stage, error) {
+	globals := map[string]string{}
+	var stages []stage
+	names := map[string]int{}
+
+	for _, in := range instructions {
+		switch in.command {
+		case "ARG":
+			if len(stages) > 0 {
+				continue
+			}
+			for _, field := range strings.Fields(in.args) {
+				parts := strings.SplitN(field, "=", 2)
+				if len(parts) == 2 {
+					// a default may use the arguments declared before it
+					globals[parts[0]], _ = expand(unquote(parts[1]), globals)
+				} else if _, ok := globals[parts[0]]; !ok {
+					globals[parts[0]] = ""
+				}
+			}
+		case "FROM":
+			s, err := parseFrom(fileName, in, globals)
+			if err != nil {
+				return nil, err
+			}
+			s.parent = -1
+			if index, ok := names[strings.ToLower(s.image)]; ok {
+				s.parent = index
+			}
+			if s.name != "" {
+				names[strings.ToLower(s.name)] = len(stages)
+			}
+			stages = append(stages, s)
+		case "COPY", "ADD":
+			if len(stages) == 0 {
+				return nil, reader.MalformedError(fileName, in.line, fmt.Sprintf("%s before the first FROM", in.command))
+			}
+			for _, field := range strings.Fields(in.args) {
+				if !strings.HasPrefix(field, "--from=") {
+					continue
+				}
+				from, _ := expand(strings.TrimPrefix(field, "--from="), globals)
+				if _, ok := names[strings.ToLower(from)]; ok {
+					continue
+				}
+				if _, err := strconv.Atoi(from); err == nil {
+					continue
+				}
+				current := &stages[len(stages)-1]
+				current.copies = append(current.copies, stageCopy{image: from, from: in})
+			}
+		}
+	}
+	return stages, nil
+}
+
+// parseFrom parses a FROM instruction, e.g. FROM --platform=linux/amd64 golang:1.21 AS build
+func parseFrom(fileName string, in instruction, args map[string]string) (stage, error) {
+	var s stage
+	fields := strings.Fields(in.args)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		if strings.HasPrefix(fields[0], "--platform=") {
+			s.platform = strings.TrimPrefix(fields[0], "--platform=")
+		}
+		fields = fields[1:]
+	}
+
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		s.name = fields[2]
+	default:
+		return stage{}, reader.MalformedError(fileName, in.line, fmt.Sprintf("unexpected %q", in.String()))
+	}
+
+	image, resolved := expand(fields[0], args)
+	if !resolved {
+		log.Warnf("%s line %d: the image of %q depends on a build argument without a default, it is skipped", fileName, in.line, in.String())
+		image = ""
+	}
+	s.image = image
+	s.platform, _ = expand(s.platform, args)
+	s.from = in
+	return s, nil
+}
+
+// baseImages returns the images the stages are built from or copy files from. The final image is
+// built from the image its last stage descends from, the other images only build it
+func baseImages(fileName string, stages []stage) []baseImage {
+	final := map[int]bool{}
+	for index := len(stages) - 1; index >= 0; index = stages[index].parent {
+		final[index] = true
+	}
+
+	var images []baseImage
+	add := func(image, platform string, from instruction, relationship models.RelationshipType) {
+		if image == "" || strings.EqualFold(image, scratch) {
+			return
+		}
+		reference, err := parseReference(image)
+		if err != nil {
+			log.Warnf("%s line %d: %v", fileName, from.line, err)
+			return
+		}
+		images = append(images, baseImage{reference: reference, platform: platform, from: from, relationship: relationship})
+	}
+
+	for index, s := range stages {
+		if s.parent < 0 {
+			relationship := models.RelationshipBuildToolOf
+			if final[index] {
+				relationship = models.RelationshipDependsOn
+			}
+			add(s.image, s.platform, s.from, relationship)
+		}
+		for _, c := range s.copies {
+			add(c.image, "", c.from, models.RelationshipBuildToolOf)
+		}
+	}
+	return images
+}
+
+// expand replaces the variables of s with their values, false when one has none
+func expand(s string, args map[string]string) (string, bool) {
+	resolved := true
+	expanded := variableRegex.ReplaceAllStringFunc(s, func(variable string) string {
+		match := variableRegex.FindStringSubmatch(variable)
+		name, operator, word := match[1], match[2], match[3]
+		if name == "" {
+			name = match[4]
+		}
+		value, ok := args[name]
+		switch operator {
+		case ":-":
+			if value == "" {
+				return word
+			}
+		case "-":
+			if !ok {
+				return word
+			}
+		case ":+", "+":
+			if value != "" || (operator == "+" && ok) {
+				return word
+			}
+			return ""
+		}
+		if value == "" {
+			resolved = false
+		}
+		return value
+	})
+	return expanded, resolved
+}
+
+// unquote removes the quotes around an ARG default value
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
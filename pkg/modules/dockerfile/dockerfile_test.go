@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestParseInstructions(t *testing.T) {
+	content := "# escape=`\n" +
+		"FROM alpine:3.18 `\n" +
+		"  # a comment in the continuation\n" +
+		"  AS base\n" +
+		"RUN <<-EOT cat > /etc/motd\n" +
+		"\tFROM not-an-image\n" +
+		"\tEOT\n" +
+		"copy --from=base / /\n"
+	instructions, err := parseInstructions("Dockerfile", strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []instruction{
+		{line: 2, command: "FROM", args: "alpine:3.18 AS base"},
+		{line: 5, command: "RUN", args: "<<-EOT cat > /etc/motd"},
+		{line: 8, command: "COPY", args: "--from=base / /"},
+	}, instructions)
+}
+
+func TestBaseImages(t *testing.T) {
+	content := "ARG VARIANT\n" +
+		"ARG NODE=node:${VARIANT:-20}\n" +
+		"FROM ${NODE} AS deps\n" +
+		"FROM deps AS build\n" +
+		"COPY --from=busybox:1.36@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79 /bin/sh /bin/sh\n" +
+		"FROM nginx:1.25 AS web\n" +
+		"FROM build\n" +
+		"COPY --from=web /etc/nginx /etc/nginx\n"
+	instructions, err := parseInstructions("Dockerfile", strings.NewReader(content))
+	assert.NoError(t, err)
+	stages, err := parseStages("Dockerfile", instructions)
+	assert.NoError(t, err)
+	assert.Len(t, stages, 4)
+	assert.Equal(t, 1, stages[3].parent)
+
+	var images []string
+	for _, image := range baseImages("Dockerfile", stages) {
+		images = append(images, image.reference.String()+" "+string(image.relationship))
+	}
+	assert.Equal(t, []string{
+		"node:20 " + string(models.RelationshipDependsOn),
+		"busybox:1.36@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79 " + string(models.RelationshipBuildToolOf),
+		"nginx:1.25 " + string(models.RelationshipBuildToolOf),
+	}, images)
+}
+
+func TestParseStagesMalformed(t *testing.T) {
+	_, err := parseStages("Dockerfile", []instruction{{line: 3, command: "FROM", args: "alpine AS"}})
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, `malformed file Dockerfile: line 3: unexpected "FROM alpine AS"`)
+
+	_, err = parseStages("Dockerfile", []instruction{{line: 1, command: "COPY", args: "--from=alpine / /"}})
+	assert.EqualError(t, err, "malformed file Dockerfile: line 1: COPY before the first FROM")
+}
+
+func TestParseReference(t *testing.T) {
+	ref, err := parseReference("alpine")
+	assert.NoError(t, err)
+	assert.Equal(t, imageRef{Registry: "docker.io", Repository: "library/alpine"}, ref)
+	assert.Equal(t, "latest", ref.Version())
+	assert.Equal(t, "pkg:docker/alpine@latest", ref.packageURL(""))
+
+	ref, err = parseReference("localhost:5000/team/app:2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, imageRef{Registry: "localhost:5000", Repository: "team/app", Tag: "2.0"}, ref)
+	assert.Equal(t, "localhost:5000/team/app", ref.Name())
+	assert.Equal(t, "pkg:docker/team/app@2.0?arch=arm64&repository_url=localhost:5000", ref.packageURL("linux/arm64/v8"))
+
+	ref, err = parseReference("index.docker.io/library/debian@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79")
+	assert.NoError(t, err)
+	assert.Equal(t, "debian", ref.Name())
+	assert.Equal(t, "sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79", ref.Version())
+
+	for _, invalid := range []string{"Alpine", "alpine@sha256", "alpine:", "team//app", "alpine:$TAG"} {
+		_, err := parseReference(invalid)
+		assert.True(t, errors.Is(err, errInvalidImage), invalid)
+	}
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerfile
+
+import (
+	"errors"
+)
+
+var errDockerfileNotFound error = errors.New("unable to generate SPDX file, no Dockerfile found")
+var errInvalidImage error = errors.New("invalid image reference")
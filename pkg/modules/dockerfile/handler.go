@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type dockerfile struct {
+	metadata models.PluginMetadata
+}
+
+// New creates a new Dockerfile instance
+func New() *dockerfile {
+	return &dockerfile{
+		metadata: models.PluginMetadata{
+			Name:       "Dockerfile",
+			Slug:       "dockerfile",
+			Manifest:   []string{"Dockerfile", "Containerfile"},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// GetMetadata returns the plugin metadata
+func (m *dockerfile) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// GetVersion returns no version, the Dockerfiles are read without docker
+func (m *dockerfile) GetVersion() (string, error) {
+	return "", nil
+}
+
+// SetRootModule sets root package information base on path given
+func (m *dockerfile) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the project directory as the root package
+func (m *dockerfile) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := dockerfiles(absPath)
+	if len(files) == 0 {
+		return nil, errDockerfileNotFound
+	}
+	var content bytes.Buffer
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		content.Write(data)
+	}
+
+	return &models.Module{
+		Name:      filepath.Base(absPath),
+		Root:      true,
+		LocalPath: absPath,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   content.Bytes(),
+		},
+		Modules: map[string]*models.Module{},
+	}, nil
+}
+
+// ListUsedModules returns the images the Dockerfiles are built from or copy files from
+func (m *dockerfile) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the root package followed by the images of its Dockerfiles. The final image
+// of a Dockerfile depends on the image its last stage is built from, the images of the other stages and the
+// ones files are copied from are build tools
+func (m *dockerfile) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []models.Module{*root}
+	indexes := map[string]int{}
+	for _, file := range dockerfiles(root.LocalPath) {
+		images, err := readDockerfile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fileName, _ := filepath.Rel(root.LocalPath, file)
+		for _, image := range images {
+			key := image.reference.String()
+			index, ok := indexes[key]
+			if !ok {
+				indexes[key] = len(modules)
+				modules = append(modules, imageModule(filepath.ToSlash(fileName), image))
+				continue
+			}
+			// an image the final image of another Dockerfile is built from is not only a build tool
+			if image.relationship == models.RelationshipDependsOn {
+				modules[index].Relationship = models.RelationshipDependsOn
+			}
+		}
+	}
+
+	for i := range modules[1:] {
+		modules[0].Modules[modules[i+1].Path] = &modules[i+1]
+	}
+	return modules, nil
+}
+
+// IsValid checks if the project has a Dockerfile
+func (m *dockerfile) IsValid(path string) bool {
+	return len(dockerfiles(path)) > 0
+}
+
+// HasModulesInstalled checks the project has a Dockerfile, the images are not pulled
+func (m *dockerfile) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errDockerfileNotFound
+}
+
+// readDockerfile returns the images of the Dockerfile at file
+func readDockerfile(file string) ([]baseImage, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	instructions, err := parseInstructions(file, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	stages, err := parseStages(file, instructions)
+	if err != nil {
+		return nil, err
+	}
+	return baseImages(file, stages), nil
+}
+
+// dockerfiles returns the Dockerfiles of the directory, e.g. Dockerfile, Containerfile, Dockerfile.prod
+// or app.Dockerfile, sorted by name
+func dockerfiles(path string) []string {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.ToLower(entry.Name())
+		if strings.HasSuffix(name, ".dockerignore") {
+			continue
+		}
+		for _, base := range []string{"dockerfile", "containerfile"} {
+			if name == base || strings.HasPrefix(name, base+".") || strings.HasSuffix(name, "."+base) {
+				files = append(files, filepath.Join(path, entry.Name()))
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerfile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const distrolessDigest = "9ecc53c269509f63c69a266168e4a687c7eb8c0cfd753bd8bfcaa4f58a90876f"
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid("testdata/project"))
+	assert.NoError(t, m.HasModulesInstalled("testdata/project"))
+
+	modules, err := m.ListModulesWithDeps("testdata/project")
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "project", root.Name)
+	assert.Len(t, root.Modules, 3)
+
+	golang := modules[1]
+	assert.Equal(t, "golang", golang.Name)
+	assert.Equal(t, "1.21-alpine", golang.Version)
+	assert.Equal(t, "pkg:docker/golang@1.21-alpine", golang.PackageURL)
+	assert.Equal(t, "https://hub.docker.com/_/golang", golang.PackageHomePage)
+	assert.Equal(t, models.PurposeContainer, golang.PrimaryPackagePurpose)
+	assert.Equal(t, "Dockerfile line 5: FROM --platform=$BUILDPLATFORM golang:${GO_VERSION}-alpine AS build", golang.PackageComment)
+	assert.Equal(t, []string{unpinnedAnnotation}, golang.Annotations)
+	assert.Nil(t, golang.CheckSum)
+	// the build stage image is also the one tools.Dockerfile is built from
+	assert.Equal(t, models.RelationshipDependsOn, golang.Relationship)
+
+	xx := modules[2]
+	assert.Equal(t, "tonistiigi/xx", xx.Name)
+	assert.Equal(t, "pkg:docker/tonistiigi/xx@1.3.0", xx.PackageURL)
+	assert.Equal(t, "https://hub.docker.com/r/tonistiigi/xx", xx.PackageHomePage)
+	assert.Equal(t, models.RelationshipBuildToolOf, xx.Relationship)
+	assert.Equal(t, "Dockerfile line 12: COPY --from=docker.io/tonistiigi/xx:1.3.0 / /", xx.PackageComment)
+
+	distroless := modules[3]
+	assert.Equal(t, "gcr.io/distroless/static-debian12", distroless.Name)
+	assert.Equal(t, "nonroot", distroless.Version)
+	assert.Equal(t, "gcr.io/distroless/static-debian12:nonroot@sha256:"+distrolessDigest, distroless.Path)
+	assert.Equal(t, "pkg:docker/distroless/static-debian12@sha256:"+distrolessDigest+"?repository_url=gcr.io&tag=nonroot", distroless.PackageURL)
+	assert.Equal(t, "", distroless.PackageHomePage)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: distrolessDigest}, distroless.CheckSum)
+	assert.Equal(t, models.RelationshipDependsOn, distroless.Relationship)
+	assert.Empty(t, distroless.Annotations)
+
+	assert.Equal(t, &modules[3], root.Modules[distroless.Path])
+}
+
+func TestListModulesFromScratch(t *testing.T) {
+	modules, err := New().ListModulesWithDeps("testdata/empty")
+	assert.NoError(t, err)
+	assert.Len(t, modules, 1)
+	assert.Empty(t, modules[0].Modules)
+}
+
+func TestNoDockerfile(t *testing.T) {
+	m := New()
+	assert.False(t, m.IsValid("testdata"))
+	assert.True(t, errors.Is(m.HasModulesInstalled("testdata"), errDockerfileNotFound))
+	_, err := m.ListModulesWithDeps("testdata")
+	assert.True(t, errors.Is(err, errDockerfileNotFound))
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerfile
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// dockerHub is the registry of the images without one, e.g. golang:1.21
+const dockerHub = "docker.io"
+
+// unpinnedAnnotation notes the image is referenced by tag only, the image it points to may change
+const unpinnedAnnotation = "the image is not pinned to a digest, the tag may point to another image on the next build"
+
+var (
+	digestRegex = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+	tagRegex    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+)
+
+// imageRef is an image reference, e.g. gcr.io/distroless/static:nonroot@sha256:...
+type imageRef struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseReference parses an image reference, the registry defaults to Docker Hub and its official
+// images to the library namespace
+func parseReference(reference string) (imageRef, error) {
+	var ref imageRef
+	name := reference
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !digestRegex.MatchString(ref.Digest) {
+			return imageRef{}, fmt.Errorf("%w: %s", errInvalidImage, reference)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+		if !tagRegex.MatchString(ref.Tag) {
+			return imageRef{}, fmt.Errorf("%w: %s", errInvalidImage, reference)
+		}
+	}
+
+	ref.Registry = dockerHub
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry, name = host, name[i+1:]
+		}
+	}
+	if ref.Registry == "index.docker.io" || ref.Registry == "registry-1.docker.io" {
+		ref.Registry = dockerHub
+	}
+	if ref.Registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	if name == "" || name != strings.ToLower(name) || strings.Contains(name, "//") || strings.HasSuffix(name, "/") {
+		return imageRef{}, fmt.Errorf("%w: %s", errInvalidImage, reference)
+	}
+	ref.Repository = name
+	return ref, nil
+}
+
+// Name returns the name of the image as it is usually written, without the Docker Hub registry nor the
+// library namespace of the official images
+func (r imageRef) Name() string {
+	if r.Registry != dockerHub {
+		return r.Registry + "/" + r.Repository
+	}
+	return strings.TrimPrefix(r.Repository, "library/")
+}
+
+// Version returns the tag of the image, its digest when it has none and latest when it has neither,
+// the tag docker pulls
+func (r imageRef) Version() string {
+	switch {
+	case r.Tag != "":
+		return r.Tag
+	case r.Digest != "":
+		return r.Digest
+	}
+	return "latest"
+}
+
+// String returns the reference of the image, e.g. golang:1.21@sha256:...
+func (r imageRef) String() string {
+	reference := r.Name()
+	if r.Tag != "" {
+		reference += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		reference += "@" + r.Digest
+	}
+	return reference
+}
+
+// packageURL returns the pkg:docker purl of the image, its version is the digest when it is pinned
+func (r imageRef) packageURL(platform string) string {
+	namespace, name := "", r.Repository
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		namespace, name = name[:i], name[i+1:]
+	}
+	version, tag := r.Version(), ""
+	if r.Digest != "" {
+		version, tag = r.Digest, r.Tag
+	}
+
+	if r.Registry == dockerHub && namespace == "library" {
+		namespace = ""
+	}
+
+	packageURL := purl.New("docker", namespace, name, version)
+	if r.Registry != dockerHub {
+		packageURL = packageURL.WithQualifier("repository_url", r.Registry)
+	}
+	return packageURL.
+		WithQualifier("arch", platformArch(platform)).
+		WithQualifier("tag", tag).String()
+}
+
+// homePage returns the Docker Hub page of the image, empty for the other registries
+func (r imageRef) homePage() string {
+	if r.Registry != dockerHub {
+		return ""
+	}
+	if strings.HasPrefix(r.Repository, "library/") {
+		return fmt.Sprintf("https://hub.docker.com/_/%s", strings.TrimPrefix(r.Repository, "library/"))
+	}
+	return fmt.Sprintf("https://hub.docker.com/r/%s", r.Repository)
+}
+
+// checkSum returns the sha256 digest the image is pinned to
+func (r imageRef) checkSum() *models.CheckSum {
+	if !strings.HasPrefix(r.Digest, "sha256:") {
+		return nil
+	}
+	return &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Value:     strings.TrimPrefix(r.Digest, "sha256:"),
+	}
+}
+
+// platformArch returns the architecture of a platform, e.g. arm64 for linux/arm64/v8
+func platformArch(platform string) string {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || strings.Contains(platform, "$") {
+		return ""
+	}
+	return parts[1]
+}
+
+// imageModule returns the module of the image, the Dockerfile instruction it is found at as comment
+func imageModule(fileName string, image baseImage) models.Module {
+	mod := models.Module{
+		Name:                  image.reference.Name(),
+		Version:               image.reference.Version(),
+		Path:                  image.reference.String(),
+		PackageURL:            image.reference.packageURL(image.platform),
+		PackageHomePage:       image.reference.homePage(),
+		CheckSum:              image.reference.checkSum(),
+		PrimaryPackagePurpose: models.PurposeContainer,
+		PackageComment:        fmt.Sprintf("%s line %d: %s", fileName, image.from.line, image.from.String()),
+		Relationship:          image.relationship,
+		Modules:               map[string]*models.Module{},
+	}
+	if image.reference.Digest == "" {
+		mod.Annotations = append(mod.Annotations, unpinnedAnnotation)
+	}
+	return mod
+}
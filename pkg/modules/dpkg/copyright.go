@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dpkg
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// gnuLicenseRegex matches the short names of the GNU licenses, e.g. GPL-2+ or LGPL-2.1
+var gnuLicenseRegex = regexp.MustCompile(`^(A?GPL|LGPL|GFDL)-(\d+(?:\.\d+)?)(\+)?$`)
+
+// debianLicenses are the short names of the machine-readable copyright format that are not SPDX ids
+var debianLicenses = map[string]string{
+	"expat":        "MIT",
+	"bsd-2-clause": "BSD-2-Clause",
+	"bsd-3-clause": "BSD-3-Clause",
+	"bsd-4-clause": "BSD-4-Clause",
+	"zlib":         "Zlib",
+	"artistic":     "Artistic-1.0-Perl",
+	"psf-2":        "PSF-2.0",
+	"mpl-1.1":      "MPL-1.1",
+	"mpl-2.0":      "MPL-2.0",
+}
+
+// copyright is what a copyright file in the machine-readable format tells of the package, see
+// https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+type copyright struct {
+	// Licenses are the licenses of the files of the package, in the Debian format, e.g. GPL-2+
+	Licenses []string
+	// Copyrights are the copyright notices of the files of the package
+	Copyrights []string
+}
+
+// readCopyright reads the copyright file of a package, nil when it is not in the machine-readable format
+func readCopyright(fileName string) (*copyright, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("Format:")) {
+		return nil, nil
+	}
+	paragraphs, err := reader.ReadParagraphs(fileName, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// the license of the header applies to the whole package when no files paragraph tells theirs
+	licenses := map[string]bool{}
+	header := ""
+	var copyrights []string
+	for i, paragraph := range paragraphs {
+		license := strings.TrimSpace(strings.SplitN(paragraph["License"], "\n", 2)[0])
+		switch {
+		case i == 0:
+			header = license
+		case paragraph["Files"] != "":
+			if license != "" {
+				licenses[license] = true
+			}
+			if notice := paragraph["Copyright"]; notice != "" && !containsString(copyrights, notice) {
+				copyrights = append(copyrights, notice)
+			}
+		}
+	}
+	if len(licenses) == 0 && header != "" {
+		licenses[header] = true
+	}
+
+	c := &copyright{Copyrights: copyrights}
+	for license := range licenses {
+		c.Licenses = append(c.Licenses, license)
+	}
+	sort.Strings(c.Licenses)
+	return c, nil
+}
+
+// setLicense sets the license of the package to the ones of its files, when all of them are SPDX licenses.
+// The copyright file names them otherwise
+func (c *copyright) setLicense(mod *models.Module) {
+	mod.Copyright = strings.Join(c.Copyrights, "\n")
+
+	var expressions []string
+	for _, license := range c.Licenses {
+		expression, ok := licenseExpression(license)
+		if !ok {
+			mod.CommentsLicense = fmt.Sprintf("the copyright file lists the licenses: %s", strings.Join(c.Licenses, ", "))
+			return
+		}
+		expressions = append(expressions, expression)
+	}
+	if len(expressions) > 0 {
+		mod.LicenseDeclared = helper.BuildLicenseExpression(expressions)
+		mod.LicenseConcluded = mod.LicenseDeclared
+	}
+}
+
+// licenseExpression returns the SPDX expression of a license of the copyright format, e.g. GPL-2+ or
+// Artistic is GPL-2.0-or-later OR Artistic-1.0-Perl. false when one of its licenses is not an SPDX one
+func licenseExpression(license string) (string, bool) {
+	var tokens []string
+	for _, token := range strings.Fields(license) {
+		switch strings.ToLower(token) {
+		case "or", "and":
+			if len(tokens) == 0 {
+				return "", false
+			}
+			tokens = append(tokens, strings.ToUpper(token))
+			continue
+		}
+		id, ok := spdxLicense(token)
+		if !ok {
+			return "", false
+		}
+		tokens = append(tokens, id)
+	}
+	if len(tokens) == 0 || tokens[len(tokens)-1] == "OR" || tokens[len(tokens)-1] == "AND" {
+		return "", false
+	}
+	return strings.Join(tokens, " "), true
+}
+
+// spdxLicense returns the SPDX id of a short name of the copyright format, e.g. GPL-2+ is GPL-2.0-or-later
+func spdxLicense(name string) (string, bool) {
+	if id, ok := debianLicenses[strings.ToLower(name)]; ok {
+		return id, true
+	}
+	if match := gnuLicenseRegex.FindStringSubmatch(name); match != nil {
+		version := match[2]
+		if !strings.Contains(version, ".") {
+			version += ".0"
+		}
+		suffix := "-only"
+		if match[3] != "" {
+			suffix = "-or-later"
+		}
+		id := match[1] + "-" + version + suffix
+		return id, helper.LicenseSPDXExists(id)
+	}
+	return name, helper.LicenseSPDXExists(name)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dpkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicenseExpression(t *testing.T) {
+	for license, want := range map[string]string{
+		"GPL-2+":                 "GPL-2.0-or-later",
+		"LGPL-2.1":               "LGPL-2.1-only",
+		"GPL-2+ or Artistic":     "GPL-2.0-or-later OR Artistic-1.0-Perl",
+		"Expat and BSD-3-clause": "MIT AND BSD-3-Clause",
+		"Apache-2.0":             "Apache-2.0",
+	} {
+		expression, ok := licenseExpression(license)
+		assert.True(t, ok, license)
+		assert.Equal(t, want, expression)
+	}
+
+	for _, license := range []string{"", "public-domain", "GPL-2+ with OpenSSL exception", "or MIT", "MIT or"} {
+		_, ok := licenseExpression(license)
+		assert.False(t, ok, license)
+	}
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dpkg
+
+import (
+	"errors"
+)
+
+var (
+	errStatusNotFound = errors.New("unable to generate SPDX file, no dpkg status database found. Please point --root to the root filesystem the Debian packages are installed to")
+)
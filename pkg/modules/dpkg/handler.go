@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dpkg
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+type dpkg struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+var (
+	// statusFile is the status database of the installed packages, relative to the root filesystem
+	statusFile = filepath.Join("var", "lib", "dpkg", "status")
+	// statusDir holds a status paragraph per installed package, e.g. in the distroless images
+	statusDir = filepath.Join("var", "lib", "dpkg", "status.d")
+	// docDir holds the copyright files of the installed packages
+	docDir = filepath.Join("usr", "share", "doc")
+)
+
+// New creates a new dpkg instance
+func New() *dpkg {
+	return &dpkg{
+		metadata: models.PluginMetadata{
+			Name:       "Debian Packages",
+			Slug:       "dpkg",
+			Manifest:   []string{statusFile, statusDir},
+			ModulePath: []string{docDir},
+		},
+	}
+}
+
+// GetMetadata returns the plugin metadata
+func (m *dpkg) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetOptions sets the root filesystem the packages are read from
+func (m *dpkg) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns no version, the status database is read without dpkg. The one of the host may not
+// be the one of the root filesystem
+func (m *dpkg) GetVersion() (string, error) {
+	return "", nil
+}
+
+// SetRootModule sets root package information base on path given
+func (m *dpkg) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the distribution of the root filesystem
+func (m *dpkg) GetRootModule(path string) (*models.Module, error) {
+	root, _, err := helper.DistributionModule(m.root(path))
+	return root, err
+}
+
+// ListUsedModules returns the installed packages, without the distribution
+func (m *dpkg) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the distribution followed by the installed packages, linked to the packages
+// they depend on
+func (m *dpkg) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, release, err := helper.DistributionModule(m.root(path))
+	if err != nil {
+		return nil, err
+	}
+	packages, err := readStatus(root.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	return packageModules(root, release, packages), nil
+}
+
+// IsValid checks if the root filesystem has a dpkg status database
+func (m *dpkg) IsValid(path string) bool {
+	root := m.root(path)
+	return helper.Exists(filepath.Join(root, statusFile)) || helper.Exists(filepath.Join(root, statusDir))
+}
+
+// HasModulesInstalled checks the root filesystem has a dpkg status database
+func (m *dpkg) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errStatusNotFound
+}
+
+// root returns the root filesystem, the project path unless one is given
+func (m *dpkg) root(path string) string {
+	if m.options.RootFS != "" {
+		return m.options.RootFS
+	}
+	return path
+}
+
+// packageModules returns the distribution followed by a package per installed package, the distribution
+// contains all of them. A dependency is linked to the first of its alternatives that is installed
+func packageModules(root *models.Module, release reader.OSRelease, packages []*debPackage) []models.Module {
+	modules := []models.Module{*root}
+	installed := map[string][]int{}
+	for _, p := range packages {
+		installed[p.Name] = append(installed[p.Name], len(modules))
+		modules = append(modules, packageModule(p, release, filepath.Join(root.LocalPath, docDir, p.Name)))
+	}
+	// the virtual packages are provided by the installed ones, e.g. mail-transport-agent
+	provided := map[string][]int{}
+	for i, p := range packages {
+		for _, name := range p.Provides {
+			provided[name] = append(provided[name], i+1)
+		}
+	}
+
+	for i, p := range packages {
+		for _, alternatives := range p.Depends {
+			if j, ok := resolveDependency(alternatives, p.Architecture, packages, installed, provided); ok {
+				helper.LinkModuleAs(modules, i+1, j, packages[j-1].key())
+			}
+		}
+	}
+	for i, p := range packages {
+		linked := modules[i+1]
+		linked.Relationship = models.RelationshipContains
+		modules[0].Modules[p.key()] = &linked
+	}
+	return modules
+}
+
+// resolveDependency returns the index of the module of the first installed alternative, the one of the
+// architecture of the depending package when the alternative is installed for several
+func resolveDependency(alternatives []string, architecture string, packages []*debPackage, installed, provided map[string][]int) (int, bool) {
+	for _, name := range alternatives {
+		candidates, ok := installed[name]
+		if !ok {
+			candidates = provided[name]
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		for _, i := range candidates {
+			if packages[i-1].Architecture == architecture {
+				return i, true
+			}
+		}
+		return candidates[0], true
+	}
+	return 0, false
+}
+
+// packageModule returns the module of an installed package. The status database has no checksum of the
+// package, its license and copyright are the ones of its copyright file
+func packageModule(p *debPackage, release reader.OSRelease, docPath string) models.Module {
+	namespace := release.ID
+	if namespace == "" {
+		namespace = "debian"
+	}
+	distro := release.VersionCodename
+	if distro == "" && release.ID != "" && release.VersionID != "" {
+		distro = fmt.Sprintf("%s-%s", release.ID, release.VersionID)
+	}
+
+	mod := models.Module{
+		Name:    p.Name,
+		Version: p.Version,
+		PackageURL: purl.New("deb", namespace, p.Name, p.Version).
+			WithQualifier("arch", p.Architecture).
+			WithQualifier("distro", distro).String(),
+		PackageDownloadLocation: "NOASSERTION",
+		PackageHomePage:         p.Homepage,
+		Supplier:                helper.MaintainerSupplier(p.Maintainer),
+		Modules:                 map[string]*models.Module{},
+	}
+	if p.Source != "" {
+		mod.SourceInfo = fmt.Sprintf("built from the source package %s %s", p.Source, p.SourceVersion)
+	}
+	if helper.Exists(docPath) {
+		mod.LocalPath = docPath
+	}
+	if c, err := readCopyright(filepath.Join(docPath, "copyright")); err == nil && c != nil {
+		c.setLicense(&mod)
+	}
+	return mod
+}
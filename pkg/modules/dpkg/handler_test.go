@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dpkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func moduleNames(modules map[string]*models.Module) []string {
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	return names
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "rootfs")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 6) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "debian", root.Name)
+	assert.Equal(t, "12", root.Version)
+	assert.Equal(t, models.PurposeOperatingSystem, root.PrimaryPackagePurpose)
+	assert.Len(t, root.Modules, 5)
+	for _, mod := range root.Modules {
+		assert.Equal(t, models.RelationshipContains, mod.Relationship)
+	}
+
+	libc := modules[2]
+	assert.Equal(t, "libc6", libc.Name)
+	assert.Equal(t, "2.36-9+deb12u3", libc.Version)
+	assert.Equal(t, "pkg:deb/debian/libc6@2.36-9%2Bdeb12u3?arch=amd64&distro=bookworm", libc.PackageURL)
+	assert.Equal(t, models.SupplierContact{Name: "GNU Libc Maintainers", Email: "debian-glibc@lists.debian.org", Type: models.Person}, libc.Supplier)
+	assert.Equal(t, "https://www.gnu.org/software/libc/libc.html", libc.PackageHomePage)
+	assert.Equal(t, "built from the source package glibc 2.36-9+deb12u3", libc.SourceInfo)
+	assert.Equal(t, "GPL-2.0-or-later AND LGPL-2.1-or-later", libc.LicenseDeclared)
+	assert.Equal(t, "1991-2023 Free Software Foundation, Inc.", libc.Copyright)
+	assert.Equal(t, []string{"libgcc-s1"}, moduleNames(libc.Modules))
+
+	libgcc := modules[3]
+	assert.Equal(t, "built from the source package gcc-12 12.2.0-14", libgcc.SourceInfo)
+	assert.Empty(t, libgcc.LicenseDeclared)
+	// gcc-12-base is not installed
+	assert.Equal(t, []string{"libc6"}, moduleNames(libgcc.Modules))
+
+	// awk is provided by mawk
+	debianutils := modules[1]
+	assert.ElementsMatch(t, []string{"libc6", "mawk"}, moduleNames(debianutils.Modules))
+
+	mawk := modules[4]
+	assert.Empty(t, mawk.LicenseDeclared)
+	assert.Equal(t, "the copyright file lists the licenses: GPL-2, mawk-permissive", mawk.CommentsLicense)
+
+	zlib := modules[5]
+	assert.Equal(t, "1:1.2.13.dfsg-1", zlib.Version)
+	assert.Equal(t, "Zlib", zlib.LicenseDeclared)
+	assert.Nil(t, zlib.CheckSum)
+}
+
+func TestListModulesWithDepsRootFS(t *testing.T) {
+	m := New()
+	path := t.TempDir()
+	assert.False(t, m.IsValid(path))
+	assert.Equal(t, errStatusNotFound, m.HasModulesInstalled(path))
+
+	m.SetOptions(models.PluginOptions{RootFS: filepath.Join("testdata", "distroless")})
+	assert.True(t, m.IsValid(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 3) {
+		return
+	}
+	assert.Equal(t, "distroless", modules[0].Name)
+	assert.Equal(t, "pkg:deb/debian/base-files@12.4%2Bdeb12u5?arch=amd64", modules[1].PackageURL)
+	assert.Equal(t, "pkg:deb/debian/netbase@6.4?arch=all", modules[2].PackageURL)
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package dpkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// constraintRegex matches the version constraints, the architecture restrictions and the build profiles of a
+// relationship, e.g. libc6 (>= 2.34) [amd64] <!nocheck>
+var constraintRegex = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]|<[^>]*>`)
+
+// debPackage is a package the status database lists as installed
+type debPackage struct {
+	Name         string
+	Version      string
+	Architecture string
+	Maintainer   string
+	Homepage     string
+	// Source is the source package the package is built from, and SourceVersion its version
+	Source        string
+	SourceVersion string
+	// Depends are the Pre-Depends and Depends of the package, each a list of alternatives
+	Depends  [][]string
+	Provides []string
+}
+
+// key identifies the package among the installed ones, a package may be installed for several architectures
+func (p *debPackage) key() string {
+	return fmt.Sprintf("%s:%s", p.Name, p.Architecture)
+}
+
+// readStatus reads the packages installed to the root filesystem: the ones of the status database and of
+// the status.d directory, where the distroless images list a package per file. The packages are returned
+// sorted by name and architecture
+func readStatus(root string) ([]*debPackage, error) {
+	var files []string
+	if statusPath := filepath.Join(root, statusFile); helper.Exists(statusPath) {
+		files = append(files, statusPath)
+	}
+	entries, err := ioutil.ReadDir(filepath.Join(root, statusDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		// the md5sums of the files of the package are stored beside it
+		if !entry.IsDir() && !strings.HasSuffix(entry.Name(), ".md5sums") {
+			files = append(files, filepath.Join(root, statusDir, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, errStatusNotFound
+	}
+
+	installed := map[string]*debPackage{}
+	for _, fileName := range files {
+		if err := readStatusFile(fileName, installed); err != nil {
+			return nil, err
+		}
+	}
+
+	packages := make([]*debPackage, 0, len(installed))
+	for _, p := range installed {
+		packages = append(packages, p)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].key() < packages[j].key() })
+	return packages, nil
+}
+
+func readStatusFile(fileName string, installed map[string]*debPackage) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	paragraphs, err := reader.ReadParagraphs(fileName, file)
+	if err != nil {
+		return err
+	}
+	for _, paragraph := range paragraphs {
+		if paragraph["Package"] == "" {
+			return fmt.Errorf("%w %s: paragraph without a Package", reader.ErrMalformedFile, fileName)
+		}
+		// the removed packages whose configuration files are left, or half installed, are listed too
+		if status := strings.Fields(paragraph["Status"]); len(status) > 0 && status[len(status)-1] != "installed" {
+			continue
+		}
+
+		p := &debPackage{
+			Name:          paragraph["Package"],
+			Version:       paragraph["Version"],
+			Architecture:  paragraph["Architecture"],
+			Maintainer:    paragraph["Maintainer"],
+			Homepage:      paragraph["Homepage"],
+			Source:        paragraph["Source"],
+			SourceVersion: paragraph["Version"],
+			Depends:       append(parseRelations(paragraph["Pre-Depends"]), parseRelations(paragraph["Depends"])...),
+		}
+		// the source version is given when it is not the one of the package, e.g. glibc (2.36-9)
+		if fields := strings.Fields(p.Source); len(fields) > 1 {
+			p.Source = fields[0]
+			p.SourceVersion = strings.Trim(fields[1], "()")
+		}
+		for _, provided := range parseRelations(paragraph["Provides"]) {
+			p.Provides = append(p.Provides, provided...)
+		}
+		installed[p.key()] = p
+	}
+	return nil
+}
+
+// parseRelations parses a relationship field, e.g. libc6 (>= 2.34), libgcc-s1 | libgcc1. The constraints
+// and the architecture qualifiers of the packages are left out, e.g. python3:any is python3
+func parseRelations(field string) [][]string {
+	var relations [][]string
+	for _, relation := range strings.Split(constraintRegex.ReplaceAllString(field, ""), ",") {
+		var alternatives []string
+		for _, alternative := range strings.Split(relation, "|") {
+			name := strings.TrimSpace(alternative)
+			if i := strings.Index(name, ":"); i >= 0 {
+				name = name[:i]
+			}
+			if name != "" {
+				alternatives = append(alternatives, name)
+			}
+		}
+		if len(alternatives) > 0 {
+			relations = append(relations, alternatives)
+		}
+	}
+	return relations
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gem
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// rubygemsURL is the default gem server
+const rubygemsURL = "https://rubygems.org/"
+
+// gemCPUs are the gem cpu names of the Go architectures
+var gemCPUs = map[string]string{
+	"amd64": "x86_64",
+	"386":   "x86",
+	"arm64": "aarch64",
+}
+
+// lockRootModule returns the package of the project: the gem a PATH source builds from the project
+// directory, named after the directory when the project is not a gem
+func lockRootModule(path string, lock *gemLockfile, matcher helper.LicenseMatcher) *models.Module {
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+	if spec := lock.projectSpec(); spec != nil {
+		mod.Name = spec.Name
+		mod.Version = spec.Version
+		mod.PackageURL = purl.New("gem", "", spec.Name, spec.Version).String()
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	setLicenseInfo(matcher, path, mod)
+	return mod
+}
+
+// projectSpec returns the gem the lockfile builds from the project directory, nil when there is none
+func (l *gemLockfile) projectSpec() *lockedSpec {
+	for _, source := range l.Sources {
+		if source.Type == sourcePath && filepath.Clean(source.Remote) == "." && len(source.Specs) > 0 {
+			return source.Specs[0]
+		}
+	}
+	return nil
+}
+
+// lockModules returns the root followed by the gems of the lockfile, one platform per gem: the current
+// one when locked, else the ruby one or else the first one. The root depends on the gems of the Gemfile
+func lockModules(path string, root *models.Module, lock *gemLockfile, metadata *gemMetadata) []models.Module {
+	project := lock.projectSpec()
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	specs := map[int]*lockedSpec{}
+	for _, name := range lock.gemNames() {
+		variants := lock.variants(name)
+		spec := selectVariant(variants, currentPlatform())
+		if project != nil && spec.Name == project.Name && spec.Source == project.Source {
+			index[name] = 0
+			specs[0] = spec
+			continue
+		}
+
+		mod := specModule(path, spec, lock.Checksums[spec.fullName()])
+		metadata.enrich(&mod, spec)
+		if len(variants) > 1 {
+			var platforms []string
+			for _, variant := range variants {
+				platforms = append(platforms, variant.Platform)
+			}
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the gem is locked for the platforms %s, the %s one is listed",
+				strings.Join(platforms, ", "), spec.Platform))
+		}
+		index[name] = len(modules)
+		specs[len(modules)] = spec
+		modules = append(modules, mod)
+	}
+
+	for i, spec := range specs {
+		for _, dependency := range spec.Dependencies {
+			if j, ok := index[dependency]; ok {
+				helper.LinkModule(modules, i, j, "")
+			}
+		}
+	}
+	for _, name := range lock.Dependencies {
+		if i, ok := index[name]; ok {
+			helper.LinkModule(modules, 0, i, "")
+		}
+	}
+	return modules
+}
+
+// gemNames returns the names of the locked gems, sorted
+func (l *gemLockfile) gemNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, source := range l.Sources {
+		for _, spec := range source.Specs {
+			if !seen[spec.Name] {
+				seen[spec.Name] = true
+				names = append(names, spec.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// variants returns the platform variants of the gem, bundler locks a single version of a gem
+func (l *gemLockfile) variants(name string) []*lockedSpec {
+	var variants []*lockedSpec
+	for _, source := range l.Sources {
+		for _, spec := range source.Specs {
+			if spec.Name == name {
+				variants = append(variants, spec)
+			}
+		}
+	}
+	return variants
+}
+
+// selectVariant returns the variant of the platform, else the ruby one or else the first one
+func selectVariant(variants []*lockedSpec, platform string) *lockedSpec {
+	for _, variant := range variants {
+		if matchPlatform(variant.Platform, platform) {
+			return variant
+		}
+	}
+	for _, variant := range variants {
+		if variant.Platform == rubyPlatform {
+			return variant
+		}
+	}
+	return variants[0]
+}
+
+// matchPlatform tells whether a locked platform, e.g. x86_64-linux-gnu or arm64-darwin-22, is the one of
+// the generator, e.g. x86_64-linux
+func matchPlatform(locked, platform string) bool {
+	return locked == platform || strings.HasPrefix(locked, platform+"-")
+}
+
+// currentPlatform returns the gem platform of the generator, e.g. x86_64-linux, arm64-darwin or x64-mingw
+func currentPlatform() string {
+	cpu, ok := gemCPUs[runtime.GOARCH]
+	if !ok {
+		cpu = runtime.GOARCH
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		if runtime.GOARCH == "arm64" {
+			cpu = "arm64"
+		}
+	case "windows":
+		if runtime.GOARCH == "amd64" {
+			return "x64-mingw"
+		}
+		return cpu + "-mingw32"
+	}
+	return cpu + "-" + runtime.GOOS
+}
+
+// specModule returns the package of a locked gem: a GEM one is downloaded from its gem server, a GIT one
+// from its repository at the locked revision and a PATH one is built from a directory of the project
+func specModule(path string, spec *lockedSpec, checksum string) models.Module {
+	p := purl.New("gem", "", spec.Name, spec.Version)
+	if spec.Platform != rubyPlatform {
+		p = p.WithQualifier("platform", spec.Platform)
+	}
+
+	mod := models.Module{
+		Name:     spec.Name,
+		Version:  spec.Version,
+		Supplier: models.SupplierContact{Name: spec.Name},
+		Modules:  map[string]*models.Module{},
+	}
+	switch spec.Source.Type {
+	case sourceGem:
+		remote := nonEmpty(spec.Source.Remote, rubygemsURL)
+		if !strings.HasSuffix(remote, "/") {
+			remote += "/"
+		}
+		if strings.TrimSuffix(remote, "/") != strings.TrimSuffix(rubygemsURL, "/") {
+			p = p.WithQualifier("repository_url", strings.TrimSuffix(remote, "/"))
+		}
+		mod.PackageDownloadLocation = fmt.Sprintf("%sgems/%s.gem", remote, spec.fullName())
+	case sourceGit:
+		vcs := fmt.Sprintf("git+%s", spec.Source.Remote)
+		if spec.Source.Revision != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, spec.Source.Revision)
+		}
+		p = p.WithQualifier("vcs_url", vcs)
+		mod.PackageDownloadLocation = vcs
+	case sourcePath:
+		mod.LocalPath = filepath.Join(path, filepath.FromSlash(spec.Source.Remote))
+		mod.PackageDownloadLocation = "NOASSERTION"
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the gem is built from the directory %s", spec.Source.Remote))
+	}
+	mod.PackageURL = p.String()
+
+	if checksum != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: checksum}
+	}
+	return mod
+}
+
+func nonEmpty(value, defaultValue string) string {
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gem
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestLockModules(t *testing.T) {
+	path := filepath.Join("testdata", "app")
+	lock, err := readLockfile(filepath.Join(path, "Gemfile.lock"))
+	assert.NoError(t, err)
+
+	metadata := &gemMetadata{gemPaths: []string{filepath.Join(path, "vendor", "bundle", "ruby", "3.2.0")}, ctx: context.Background()}
+	modules := lockModules(path, lockRootModule(path, lock, nil), lock, metadata)
+	if !assert.Len(t, modules, 7) {
+		return
+	}
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "app", root.Name)
+	assert.Len(t, root.Modules, 4)
+	assert.Contains(t, root.Modules, "billing")
+
+	rack := byName["rack"]
+	assert.Equal(t, "3.0.8", rack.Version)
+	assert.Equal(t, "pkg:gem/rack@3.0.8", rack.PackageURL)
+	assert.Equal(t, "https://rubygems.org/gems/rack-3.0.8.gem", rack.PackageDownloadLocation)
+	assert.Equal(t, "MIT", rack.LicenseDeclared)
+	assert.Equal(t, "https://github.com/rack/rack", rack.PackageHomePage)
+	assert.Equal(t, "Leah Neukirchen", rack.Supplier.Name)
+	assert.Equal(t, "1f3c5e7a9b0d2f4a6c8e0b2d4f6a8c0e2b4d6f8a0c2e4b6d8f0a2c4e6b8d0f2a", rack.CheckSum.Value)
+
+	nokogiri := byName["nokogiri"]
+	assert.Len(t, nokogiri.Annotations, 1)
+	assert.Contains(t, nokogiri.Modules, "racc")
+	assert.NotNil(t, nokogiri.CheckSum)
+
+	flags := byName["feature_flags"]
+	assert.Equal(t, "git+https://github.com/acme/feature_flags.git@4f2c1a9e0b7d3c5e8f6a1b2c3d4e5f60718293a4", flags.PackageDownloadLocation)
+	assert.Contains(t, flags.PackageURL, "vcs_url=")
+	assert.Contains(t, flags.Modules, "rack")
+
+	billing := byName["billing"]
+	assert.Equal(t, "NOASSERTION", billing.PackageDownloadLocation)
+	assert.Equal(t, filepath.Join(path, "engines", "billing"), billing.LocalPath)
+	assert.Contains(t, billing.Modules, "nokogiri")
+
+	auth := byName["acme-auth"]
+	assert.Equal(t, "https://gems.acme.example.com/gems/acme-auth-2.1.0.gem", auth.PackageDownloadLocation)
+	assert.Equal(t, "pkg:gem/acme-auth@2.1.0?repository_url=https:%2F%2Fgems.acme.example.com", auth.PackageURL)
+	assert.Nil(t, auth.CheckSum)
+}
+
+func TestLockRootModuleOfGem(t *testing.T) {
+	lock := &gemLockfile{Sources: []*gemSource{{Type: sourcePath, Remote: "."}}}
+	lock.Sources[0].Specs = []*lockedSpec{{Name: "widgets", Version: "0.2.0", Platform: rubyPlatform, Source: lock.Sources[0]}}
+
+	root := lockRootModule(t.TempDir(), lock, nil)
+	assert.Equal(t, "widgets", root.Name)
+	assert.Equal(t, "0.2.0", root.Version)
+	assert.Equal(t, "pkg:gem/widgets@0.2.0", root.PackageURL)
+}
+
+func TestEnrichFromGemServer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/rubygems/acme-auth/versions/2.1.0.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name": "acme-auth", "version": "2.1.0", "licenses": ["Apache-2.0", "MIT"],
+			"homepage_uri": "https://acme.example.com/auth", "sha": "5d0a3c1e"}`))
+	}))
+	defer ts.Close()
+
+	metadata := &gemMetadata{fetcher: newRubygemsFetcher(), ctx: context.Background()}
+	source := &gemSource{Type: sourceGem, Remote: ts.URL + "/"}
+	spec := &lockedSpec{Name: "acme-auth", Version: "2.1.0", Platform: rubyPlatform, Source: source}
+	mod := specModule(".", spec, "")
+	metadata.enrich(&mod, spec)
+
+	assert.Equal(t, "Apache-2.0 OR MIT", mod.LicenseDeclared)
+	assert.Equal(t, "https://acme.example.com/auth", mod.PackageHomePage)
+	assert.Equal(t, "5d0a3c1e", mod.CheckSum.Value)
+
+	missing := &lockedSpec{Name: "acme-missing", Version: "1.0.0", Platform: rubyPlatform, Source: source}
+	mod = specModule(".", missing, "")
+	metadata.enrich(&mod, missing)
+	assert.Empty(t, mod.LicenseDeclared)
+}
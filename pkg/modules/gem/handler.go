@@ -3,9 +3,9 @@
 package gem
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -17,6 +17,7 @@ type gem struct {
 	metadata   models.PluginMetadata
 	rootModule *models.Module
 	command    *helper.Cmd
+	options    models.PluginOptions
 }
 
 var errDependenciesNotFound, errInvalidProjectType = errors.New(
@@ -26,6 +27,8 @@ var errDependenciesNotFound, errInvalidProjectType = errors.New(
 `), errors.New(
     `* Tool only supports ruby gems projects with valid .gemspec manifest in project root directory`)
 
+var errUnexpectedStatus = errors.New("unexpected response from the gem server")
+
 // New ...
 func New() *gem {
 	return &gem{
@@ -43,6 +46,11 @@ func (g *gem) GetMetadata() models.PluginMetadata {
 	return g.metadata
 }
 
+// SetOptions ...
+func (g *gem) SetOptions(opts models.PluginOptions) {
+	g.options = opts
+}
+
 // IsValid ...
 func (g *gem) IsValid(path string) bool {
 
@@ -56,6 +64,10 @@ func (g *gem) IsValid(path string) bool {
 
 // HasModulesInstalled ...
 func (g *gem) HasModulesInstalled(path string) error {
+	// the lockfile lists the gems, whether they are installed or not
+	if lockFile(path) != "" {
+		return nil
+	}
 
     if !validateProjectType(path) {
         return errInvalidProjectType
@@ -76,7 +88,7 @@ func (g *gem) HasModulesInstalled(path string) error {
 // GetVersion ...
 func (g *gem) GetVersion() (string, error) {
 
-	cmd := exec.Command("bundler", "version")
+	cmd := helper.CommandContext(g.options.Context, "bundler", "version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -108,10 +120,17 @@ func (g *gem) SetRootModule(path string) error {
 
 // GetRootModule...
 func (g *gem) GetRootModule(path string) (*models.Module, error) {
+	if file := lockFile(path); file != "" {
+		lock, err := readLockfile(file)
+		if err != nil {
+			return nil, err
+		}
+		return lockRootModule(absPath(path), lock, g.options.LicenseMatcher), nil
+	}
 	if err := g.HasModulesInstalled(path); err != nil {
 		return &models.Module{}, err
 	}
-	return getGemRootModule(path)
+	return getGemRootModule(g.options.Context, path, g.options.LicenseMatcher)
 }
 
 // GetModule ...
@@ -126,8 +145,41 @@ func (g *gem) ListUsedModules(path string) ([]models.Module, error) {
 
 // ListModulesWithDeps ...
 func (g *gem) ListModulesWithDeps(path string) ([]models.Module, error) {
+	if file := lockFile(path); file != "" {
+		lock, err := readLockfile(file)
+		if err != nil {
+			return nil, err
+		}
+		return lockModules(path, lockRootModule(absPath(path), lock, g.options.LicenseMatcher), lock, g.gemMetadata(path)), nil
+	}
 	if err := g.HasModulesInstalled(path); err != nil {
 		return []models.Module{}, err
 	}
-	return listGemRootModule(path)
+	return listGemRootModule(g.options.Context, path, g.options.LicenseMatcher)
+}
+
+// gemMetadata returns the resolver of the metadata the lockfile misses, the gem servers are only queried when
+// the network is allowed
+func (g *gem) gemMetadata(path string) *gemMetadata {
+	m := &gemMetadata{gemPaths: gemPaths(g.context(), path), ctx: g.context(), cache: g.options.Cache,
+		matcher: g.options.LicenseMatcher}
+	if g.options.AllowNetwork && !g.options.Offline {
+		m.fetcher = newRubygemsFetcher()
+	}
+	return m
+}
+
+func (g *gem) context() context.Context {
+	if g.options.Context == nil {
+		return context.Background()
+	}
+	return g.options.Context
+}
+
+// absPath returns the absolute path of the project, the root is named after its directory
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
 }
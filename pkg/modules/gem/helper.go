@@ -3,6 +3,7 @@
 package gem
 
 import (
+	"context"
 	"bufio"
 	"crypto/sha256"
 	"encoding/hex"
@@ -11,7 +12,6 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -118,19 +118,19 @@ type (
 )
 
 // Returns the root module
-func getGemRootModule(path string) (*models.Module, error) {
+func getGemRootModule(ctx context.Context, path string, matcher helper.LicenseMatcher) (*models.Module, error) {
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
-		initializeDepCache(&wg)
+		initializeDepCache(ctx, &wg)
 	}()
 	wg.Wait()
 
 	rootPath = &path
 	rootModule := models.Module{}
 	rootModule.Modules = make(map[string]*models.Module)
-	spec, err := getSpecDependencies(path)
+	spec, err := getSpecDependencies(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +141,7 @@ func getGemRootModule(path string) (*models.Module, error) {
 		supplier.Name = authors[0]
 	}
 
-	setLicenseInfo(spec.GemLocationDir, &rootModule)
+	setLicenseInfo(matcher, spec.GemLocationDir, &rootModule)
 	rootModule.Name = gemName(spec.Name)
 	rootModule.Version = spec.Version
 	rootModule.Supplier = supplier
@@ -159,7 +159,7 @@ func getGemRootModule(path string) (*models.Module, error) {
 }
 
 // Returns the root module and associated dependencies
-func listGemRootModule(path string) ([]models.Module, error) {
+func listGemRootModule(ctx context.Context, path string, matcher helper.LicenseMatcher) ([]models.Module, error) {
 
 	rootPath = &path
 	modules := make([]models.Module, 0)
@@ -181,13 +181,13 @@ func listGemRootModule(path string) ([]models.Module, error) {
 		secondLayerModule models.Module
 
 	// Parent Layer - Root
-	rootModule, err := getGemRootModule(path)
+	rootModule, err := getGemRootModule(ctx, path, matcher)
 	if err != nil {
 		return nil, err
 	}
 
 	modules = append(modules, *rootModule)
-	rootSpec, err := getSpecDependencies(path)
+	rootSpec, err := getSpecDependencies(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -201,7 +201,7 @@ func listGemRootModule(path string) ([]models.Module, error) {
 		}
 
 		parentLayerModule := parseSpec(dep)
-		setLicenseInfo(dep.GemLocationDir, &parentLayerModule)
+		setLicenseInfo(matcher, dep.GemLocationDir, &parentLayerModule)
 
 		for _, firstDescendant := range dep.RuntimeDependencies {
 
@@ -212,7 +212,7 @@ func listGemRootModule(path string) ([]models.Module, error) {
 				continue
 			}
 			// Add 1st Layer
-			layerOneGems, firstLayerModule = addGemLayer(firstDescendantSpec, name, &parentLayerModule, _1stLayerMapped, layerOneGems)
+			layerOneGems, firstLayerModule = addGemLayer(firstDescendantSpec, name, &parentLayerModule, _1stLayerMapped, layerOneGems, matcher)
 
 			for _, secondDescendant := range firstDescendantSpec.RuntimeDependencies {
 				secondDescendantSpec, name, err := getDescendantInfo(secondDescendant)
@@ -222,7 +222,7 @@ func listGemRootModule(path string) ([]models.Module, error) {
 					continue
 				}
 				//Add 2nd Layer
-				layerTwoGems, secondLayerModule = addGemLayer(secondDescendantSpec, name, &firstLayerModule, _2ndLayerMapped, layerTwoGems)
+				layerTwoGems, secondLayerModule = addGemLayer(secondDescendantSpec, name, &firstLayerModule, _2ndLayerMapped, layerTwoGems, matcher)
 
 				for _, thirdDescendant := range secondDescendantSpec.RuntimeDependencies {
 					thirdDescendantSpec, name, err := getDescendantInfo(thirdDescendant)
@@ -232,7 +232,7 @@ func listGemRootModule(path string) ([]models.Module, error) {
 						continue
 					}
 					//Add 3rd Layer
-					layerThreeGems, _ = addGemLayer(thirdDescendantSpec, name, &secondLayerModule, _3rdLayerMapped, layerThreeGems)
+					layerThreeGems, _ = addGemLayer(thirdDescendantSpec, name, &secondLayerModule, _3rdLayerMapped, layerThreeGems, matcher)
 				}
 
 			}
@@ -285,9 +285,9 @@ func parseSpec(spec Spec) models.Module {
 }
 
 // Adds a new layer to the dependency tree
-func addGemLayer(descendant Spec, name string, parent *models.Module, layer map[string]bool, gems []models.Module) ([]models.Module, models.Module) {
+func addGemLayer(descendant Spec, name string, parent *models.Module, layer map[string]bool, gems []models.Module, matcher helper.LicenseMatcher) ([]models.Module, models.Module) {
 	descendantModule := parseSpec(descendant)
-	setLicenseInfo(descendant.GemLocationDir, &descendantModule)
+	setLicenseInfo(matcher, descendant.GemLocationDir, &descendantModule)
 	return setChildModule(name, parent, &descendantModule, layer, gems), descendantModule
 }
 
@@ -302,9 +302,9 @@ func setChildModule(name string, parent, child *models.Module, layer map[string]
 }
 
 // Sets license info from generic helper
-func setLicenseInfo(path string, module *models.Module) {
+func setLicenseInfo(matcher helper.LicenseMatcher, path string, module *models.Module) {
 
-	licensePkg, err := helper.GetLicenses(path)
+	licensePkg, err := helper.GetLicenses(matcher, path)
 	if err == nil {
 		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
 		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
@@ -332,14 +332,14 @@ func getDescendantInfo(child string) (Spec, string, error) {
 }
 
 // Gets parent and child dependency tree from .gemspec
-func getSpecDependencies(path string) (Spec, error) {
+func getSpecDependencies(ctx context.Context, path string) (Spec, error) {
 
 	manifest, err := detectManifest(path, DETECTION_MODE_SPEC)
 	if err != nil {
 		return Spec{}, err
 	}
 	module := getSpecs(filepath.Join(path, manifest))
-	BuildSpecDependencies(filepath.Join(path, SPEC_DEPENDENCY_PATH), false, &module)
+	BuildSpecDependencies(ctx, filepath.Join(path, SPEC_DEPENDENCY_PATH), false, &module)
 	return module, nil
 }
 
@@ -358,7 +358,7 @@ func GetLockedDependencies(path string) ([]Package, error) {
 }
 
 // Builds parent and child dependency tree from .gemspec
-func BuildSpecDependencies(path string, isFullPath bool, module *Spec) {
+func BuildSpecDependencies(ctx context.Context, path string, isFullPath bool, module *Spec) {
 
 	files, err := ioutil.ReadDir(path)
 
@@ -370,7 +370,7 @@ func BuildSpecDependencies(path string, isFullPath bool, module *Spec) {
 		for _, dir := range files {
 			if dir.IsDir() {
 				fullPath := filepath.Join(path, dir.Name(), SPEC_DEFAULT_DIR)
-				BuildSpecDependencies(fullPath, true, module)
+				BuildSpecDependencies(ctx, fullPath, true, module)
 				return
 			}
 		}
@@ -381,7 +381,7 @@ func BuildSpecDependencies(path string, isFullPath bool, module *Spec) {
 	name, version, _ := rootGem(cachePath, cleanName(module.Name))
 	versionedName := fmt.Sprintf("%s-%s", name, version)
 
-	rootSha, err := checkSum(cachePath, versionedName, true)
+	rootSha, err := checkSum(ctx, cachePath, versionedName, true)
 	if err == nil && rootSha != "" {
 		module.Checksum = rootSha
 	}
@@ -404,7 +404,7 @@ func BuildSpecDependencies(path string, isFullPath bool, module *Spec) {
 			module.Specifications = append(module.Specifications, getSpecs(specPath))
 			fileName := cleanName(strings.Replace(f.Name(), SPEC_EXTENSION, "", 1))
 
-			sha, err := checkSum(cachePath, fileName, true)
+			sha, err := checkSum(ctx, cachePath, fileName, true)
 			if err == nil {
 				module.Specifications[i].Checksum = sha
 			}
@@ -608,7 +608,7 @@ func BuildLockDependencyTree(rows []string) {
 }
 
 // Compute SHA 256 Checksum for gems
-func checkSum(path string, filename string, isFullPath bool) (string, error) {
+func checkSum(ctx context.Context, path string, filename string, isFullPath bool) (string, error) {
 
 	var sha string
 	files, err := ioutil.ReadDir(path)
@@ -622,7 +622,7 @@ func checkSum(path string, filename string, isFullPath bool) (string, error) {
 		for _, f := range files {
 			if f.IsDir() {
 				fullPath := filepath.Join(path, f.Name(), CACHE_DEFAULT_DIR)
-				return checkSum(fullPath, filename, true)
+				return checkSum(ctx, fullPath, filename, true)
 			}
 		}
 
@@ -632,7 +632,7 @@ func checkSum(path string, filename string, isFullPath bool) (string, error) {
 			return "", nil
 		}
 		if _, err := os.Stat(path); os.IsNotExist(err) {
-			path = gemDir()
+			path = gemDir(ctx)
 		}
 		var ops = runtime.GOOS
 		if strings.Contains(strings.ToLower(ops), "linux") {
@@ -647,7 +647,7 @@ func checkSum(path string, filename string, isFullPath bool) (string, error) {
 		switch ops {
 		case "linux":
 			linuxcmd := "sha256sum"
-			cmd := exec.Command(linuxcmd, filepath.Join(path, filename+GEM_DEFAULT_EXTENSION))
+			cmd := helper.CommandContext(ctx, linuxcmd, filepath.Join(path, filename+GEM_DEFAULT_EXTENSION))
 			output, err := cmd.Output()
 			if err != nil {
 				return "", err
@@ -661,7 +661,7 @@ func checkSum(path string, filename string, isFullPath bool) (string, error) {
 			sha = sha256
 		case "darwin":
 			osxCmd := `shasum`
-			cmd := exec.Command(osxCmd, "-a", "256", filepath.Join(path, filename+GEM_DEFAULT_EXTENSION))
+			cmd := helper.CommandContext(ctx, osxCmd, "-a", "256", filepath.Join(path, filename+GEM_DEFAULT_EXTENSION))
 			output, err := cmd.Output()
 			if err != nil {
 				return "", err
@@ -977,11 +977,11 @@ func getInsertIndex(rows []string) (int, int) {
 }
 
 // Get local gem paths from env
-func getGemPaths() ([]string, []string) {
+func getGemPaths(ctx context.Context) ([]string, []string) {
 
 	var start, stop, reading bool
 	locations, secondaryLocation := []string{}, []string{}
-	cmd := exec.Command("gem", "env")
+	cmd := helper.CommandContext(ctx, "gem", "env")
 	output, err := cmd.Output()
 	if err != nil {
 		fmt.Println(err)
@@ -1016,14 +1016,14 @@ func getGemPaths() ([]string, []string) {
 }
 
 // Build tree mapping from all gems detected in gem paths
-func buildLocalTree(paths []string, secondaryLocation string) []Spec {
+func buildLocalTree(ctx context.Context, paths []string, secondaryLocation string) []Spec {
 
 	localSpecs := []Spec{}
 
 	for _, installPath := range paths {
 		specPath := filepath.Join(installPath, SPEC_DEFAULT_DIR)
 		cachePath := filepath.Join(installPath, CACHE_DEFAULT_DIR)
-		primaryLocation := gemDir()
+		primaryLocation := gemDir(ctx)
 		checkSumPaths := []string{cachePath, secondaryLocation, primaryLocation}
 		licensePath := filepath.Join(installPath, GEM_DEFAULT_DIR)
 
@@ -1040,7 +1040,7 @@ func buildLocalTree(paths []string, secondaryLocation string) []Spec {
 				fullSpecsPath := filepath.Join(specPath, f.Name())
 				spec := getSpecs(fullSpecsPath)
 				if spec.Version == "" {
-					spec.Version = getExistingVersion(cleanName(spec.Name))
+					spec.Version = getExistingVersion(ctx, cleanName(spec.Name))
 				}
 				fileName := strings.Replace(f.Name(), SPEC_EXTENSION, "", 1)
 
@@ -1048,7 +1048,7 @@ func buildLocalTree(paths []string, secondaryLocation string) []Spec {
 					if _, err := os.Stat(csp); os.IsNotExist(err) {
 						continue
 					}
-					sha, err := checkSum(csp, fileName, true)
+					sha, err := checkSum(ctx, csp, fileName, true)
 					if err == nil && sha != "" {
 						spec.Checksum = sha
 						break
@@ -1104,14 +1104,14 @@ func lookupGemInfo(name, version string) Spec {
 }
 
 // Initialize in-memory dependency cache
-func initializeDepCache(wg *sync.WaitGroup) error {
+func initializeDepCache(ctx context.Context, wg *sync.WaitGroup) error {
 
-	paths, secPaths := getGemPaths()
-	secondaryCachePath := gemDir()
+	paths, secPaths := getGemPaths(ctx)
+	secondaryCachePath := gemDir(ctx)
 	if len(secPaths) > 0 {
 		secondaryCachePath = filepath.Join(secPaths[0], CACHE_DEFAULT_DIR)
 	}
-	depSpecs := buildLocalTree(paths, secondaryCachePath)
+	depSpecs := buildLocalTree(ctx, paths, secondaryCachePath)
 	for _, dep := range depSpecs {
 		name, v := cleanName(dep.Name), dep.Version
 		if dependencyMap[name].count > 0 {
@@ -1148,9 +1148,9 @@ func getRangedVersion(rv string) string {
 }
 
 // gets version existing on file system
-func getExistingVersion(gem string) string {
+func getExistingVersion(ctx context.Context, gem string) string {
 
-	cmd := exec.Command("gem", "query", "-e", gem)
+	cmd := helper.CommandContext(ctx, "gem", "query", "-e", gem)
 	output, err := cmd.Output()
 	if err != nil {
 		return NONE
@@ -1317,8 +1317,8 @@ func hasRakefile(path string) bool {
 }
 
 // Gets the gem installation directory
-func gemDir() string {
-	cmd := exec.Command("gem", "environment", "gemdir")
+func gemDir(ctx context.Context) string {
+	cmd := helper.CommandContext(ctx, "gem", "environment", "gemdir")
 	output, err := cmd.Output()
 	if err != nil {
 		fmt.Println(err)
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gem
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the lockfiles of bundler, gems.locked is the one of a gems.rb
+var lockFiles = []string{"Gemfile.lock", "gems.locked"}
+
+// the sections of a lockfile listing the locked gems, by source type
+const (
+	sourceGem  = "GEM"
+	sourceGit  = "GIT"
+	sourcePath = "PATH"
+)
+
+// the other sections of a lockfile
+const (
+	sectionDependencies = "DEPENDENCIES"
+	sectionChecksums    = "CHECKSUMS"
+)
+
+// rubyPlatform is the platform of the gems built for any platform
+const rubyPlatform = "ruby"
+
+// gemSource is a GEM, GIT or PATH section of a lockfile
+type gemSource struct {
+	Type string
+	// Remote is the rubygems server of a GEM source, the repository of a GIT one and the directory of a PATH one
+	Remote   string
+	Revision string
+	Ref      string
+	Specs    []*lockedSpec
+}
+
+// lockedSpec is a gem of a source, a gem built for several platforms is locked once per platform
+type lockedSpec struct {
+	Name     string
+	Version  string
+	Platform string
+	// Dependencies are the names of the gems it depends on
+	Dependencies []string
+	Source       *gemSource
+}
+
+// gemLockfile is a Gemfile.lock or a gems.locked
+type gemLockfile struct {
+	Sources []*gemSource
+	// Dependencies are the names of the gems the Gemfile requires
+	Dependencies []string
+	// Checksums are keyed by the full name of the gems, e.g. nokogiri-1.15.4-x86_64-linux
+	Checksums map[string]string
+}
+
+// lockFile returns the lockfile of the directory, "" when the gems are not locked
+func lockFile(path string) string {
+	for _, name := range lockFiles {
+		if file := filepath.Join(path, name); helper.Exists(file) {
+			return file
+		}
+	}
+	return ""
+}
+
+// readLockfile reads the sources, the dependencies and the checksums of a lockfile, its sections are
+// indented by two spaces, the gems of a source by four and their dependencies by six:
+//
+//	GEM
+//	  remote: https://rubygems.org/
+//	  specs:
+//	    nokogiri (1.15.4-x86_64-linux)
+//	      racc (~> 1.4)
+func readLockfile(path string) (*gemLockfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &gemLockfile{Checksums: map[string]string{}}
+	section := ""
+	var source *gemSource
+	var spec *lockedSpec
+	for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		value := strings.TrimSpace(line)
+
+		if indent == 0 {
+			section, source, spec = value, nil, nil
+			if section == sourceGem || section == sourceGit || section == sourcePath {
+				source = &gemSource{Type: section}
+				lock.Sources = append(lock.Sources, source)
+			}
+			continue
+		}
+
+		switch {
+		case source != nil && indent == 2:
+			key, val := splitOption(value)
+			switch key {
+			case "remote":
+				// the lockfiles of old bundler versions list several remotes, the first one is kept
+				if source.Remote == "" {
+					source.Remote = val
+				}
+			case "revision":
+				source.Revision = val
+			case "ref", "branch", "tag":
+				source.Ref = val
+			}
+		case source != nil && indent == 4:
+			name, version, platform, ok := parseGemLine(value)
+			if !ok {
+				return nil, reader.MalformedError(path, i+1, fmt.Sprintf("unexpected gem %q", value))
+			}
+			spec = &lockedSpec{Name: name, Version: version, Platform: platform, Source: source}
+			source.Specs = append(source.Specs, spec)
+		case source != nil && indent == 6:
+			if spec == nil {
+				return nil, reader.MalformedError(path, i+1, "dependency before the first gem")
+			}
+			name, _, _, _ := parseGemLine(value)
+			spec.Dependencies = append(spec.Dependencies, name)
+		case section == sectionDependencies && indent == 2:
+			name, _, _, _ := parseGemLine(strings.TrimSuffix(value, "!"))
+			lock.Dependencies = append(lock.Dependencies, name)
+		case section == sectionChecksums && indent == 2:
+			// nokogiri (1.15.4-x86_64-linux) sha256=...
+			fields := strings.Fields(value)
+			if len(fields) < 3 {
+				continue
+			}
+			name, version, platform, ok := parseGemLine(strings.Join(fields[:len(fields)-1], " "))
+			if !ok {
+				continue
+			}
+			for _, checksum := range strings.Split(fields[len(fields)-1], ",") {
+				if strings.HasPrefix(checksum, "sha256=") {
+					lock.Checksums[fullName(name, version, platform)] = strings.TrimPrefix(checksum, "sha256=")
+				}
+			}
+		}
+	}
+	return lock, nil
+}
+
+// splitOption splits a source option, e.g. remote: https://rubygems.org/
+func splitOption(value string) (string, string) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return value, ""
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
+
+// parseGemLine reads a gem line: name (version[-platform]), a dependency line holds a requirement instead,
+// e.g. racc (~> 1.4), and a DEPENDENCIES line may hold none. The platform is ruby unless stated
+func parseGemLine(value string) (name, version, platform string, ok bool) {
+	open := strings.Index(value, " (")
+	if open < 0 {
+		return strings.TrimSpace(value), "", rubyPlatform, false
+	}
+	name = value[:open]
+	version = strings.TrimSuffix(strings.TrimSpace(value[open+2:]), ")")
+	platform = rubyPlatform
+	if parts := strings.SplitN(version, "-", 2); len(parts) == 2 {
+		version, platform = parts[0], parts[1]
+	}
+	return name, version, platform, name != "" && version != ""
+}
+
+// fullName returns the name of the .gem of a version: name-version, followed by the platform unless it is ruby
+func fullName(name, version, platform string) string {
+	if platform == "" || platform == rubyPlatform {
+		return fmt.Sprintf("%s-%s", name, version)
+	}
+	return fmt.Sprintf("%s-%s-%s", name, version, platform)
+}
+
+// fullName returns the name of the .gem of the spec
+func (s *lockedSpec) fullName() string {
+	return fullName(s.Name, s.Version, s.Platform)
+}
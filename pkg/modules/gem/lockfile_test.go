@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gem
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadLockfile(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "app", "Gemfile.lock"))
+	assert.NoError(t, err)
+	if !assert.Len(t, lock.Sources, 4) {
+		return
+	}
+
+	git := lock.Sources[0]
+	assert.Equal(t, sourceGit, git.Type)
+	assert.Equal(t, "https://github.com/acme/feature_flags.git", git.Remote)
+	assert.Equal(t, "4f2c1a9e0b7d3c5e8f6a1b2c3d4e5f60718293a4", git.Revision)
+	assert.Equal(t, "main", git.Ref)
+	assert.Equal(t, []string{"rack"}, git.Specs[0].Dependencies)
+
+	assert.Equal(t, "engines/billing", lock.Sources[1].Remote)
+
+	gems := lock.Sources[2]
+	assert.Len(t, gems.Specs, 5)
+	assert.Equal(t, "nokogiri", gems.Specs[1].Name)
+	assert.Equal(t, "1.15.4", gems.Specs[1].Version)
+	assert.Equal(t, "arm64-darwin", gems.Specs[1].Platform)
+	assert.Equal(t, rubyPlatform, gems.Specs[0].Platform)
+
+	assert.Equal(t, "https://gems.acme.example.com/", lock.Sources[3].Remote)
+	assert.Equal(t, []string{"acme-auth", "billing", "feature_flags", "rack"}, lock.Dependencies)
+	assert.Len(t, lock.Checksums, 3)
+	assert.Contains(t, lock.Checksums, "nokogiri-1.15.4-x86_64-linux")
+}
+
+func TestReadLockfileMalformed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "Gemfile.lock")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("GEM\n  remote: https://rubygems.org/\n  specs:\n    rack\n"), 0644))
+
+	_, err := readLockfile(file)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestParseGemLine(t *testing.T) {
+	name, version, platform, ok := parseGemLine("nokogiri (1.15.4-x86_64-linux-gnu)")
+	assert.True(t, ok)
+	assert.Equal(t, "nokogiri", name)
+	assert.Equal(t, "1.15.4", version)
+	assert.Equal(t, "x86_64-linux-gnu", platform)
+
+	name, _, _, ok = parseGemLine("rails")
+	assert.False(t, ok)
+	assert.Equal(t, "rails", name)
+}
+
+func TestSelectVariant(t *testing.T) {
+	variants := []*lockedSpec{
+		{Name: "nokogiri", Platform: "aarch64-linux"},
+		{Name: "nokogiri", Platform: rubyPlatform},
+		{Name: "nokogiri", Platform: "x86_64-linux-gnu"},
+	}
+	assert.Equal(t, "x86_64-linux-gnu", selectVariant(variants, "x86_64-linux").Platform)
+	assert.Equal(t, rubyPlatform, selectVariant(variants, "arm64-darwin").Platform)
+	assert.Equal(t, "aarch64-linux", selectVariant(variants[:1], "arm64-darwin").Platform)
+}
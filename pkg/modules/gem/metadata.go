@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// specAttribute is an attribute of an installed gemspec, e.g. s.licenses = ["MIT".freeze]
+var specAttribute = regexp.MustCompile(`^\s*s\.(license|licenses|homepage|authors)\s*=\s*(.*)$`)
+
+// specString is a string of a gemspec attribute
+var specString = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// installedSpec is the metadata of the gemspec rubygems writes in the specifications directory of a gem path
+type installedSpec struct {
+	Licenses []string
+	HomePage string
+	Authors  []string
+}
+
+// gemMetadata resolves the licenses, the home pages and the checksums the lockfile misses, from the gems
+// installed in the gem paths and, when the network is allowed, from the gem servers
+type gemMetadata struct {
+	// gemPaths are the directories the gems are installed in, with a specifications, a gems and a cache directory
+	gemPaths []string
+	// fetcher is nil when the network is not allowed
+	fetcher *rubygemsFetcher
+	ctx     context.Context
+	cache   *cache.Cache
+	// matcher identifies the license files of the installed gems
+	matcher helper.LicenseMatcher
+}
+
+// rubygemsFetcher fetches the metadata of the gem versions from the rubygems.org API of the gem servers
+type rubygemsFetcher struct {
+	client *http.Client
+}
+
+func newRubygemsFetcher() *rubygemsFetcher {
+	return &rubygemsFetcher{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// gemPaths returns the directories the gems of the project may be installed in: the bundle path of the
+// project, e.g. vendor/bundle, then the GEM_HOME, the GEM_PATH and the ones the gem command reports
+func gemPaths(ctx context.Context, path string) []string {
+	var paths []string
+	bundlePaths := []string{filepath.Join(path, "vendor", "bundle")}
+	if bundlePath := os.Getenv("BUNDLE_PATH"); bundlePath != "" {
+		if !filepath.IsAbs(bundlePath) {
+			bundlePath = filepath.Join(path, bundlePath)
+		}
+		bundlePaths = append(bundlePaths, bundlePath)
+	}
+	for _, bundlePath := range bundlePaths {
+		// bundler installs the gems in ruby/<abi version>, e.g. vendor/bundle/ruby/3.2.0
+		versions, _ := filepath.Glob(filepath.Join(bundlePath, "ruby", "*"))
+		paths = append(paths, versions...)
+	}
+
+	paths = append(paths, filepath.SplitList(os.Getenv("GEM_HOME"))...)
+	paths = append(paths, filepath.SplitList(os.Getenv("GEM_PATH"))...)
+	if output, err := helper.CommandContext(ctx, "gem", "environment", "gempath").Output(); err == nil {
+		paths = append(paths, filepath.SplitList(strings.TrimSpace(string(output)))...)
+	}
+	return paths
+}
+
+// readInstalledSpec reads the licenses, the home page and the authors of an installed gemspec
+func readInstalledSpec(path string) (*installedSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &installedSpec{}
+	for _, line := range strings.Split(string(data), "\n") {
+		match := specAttribute.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		var values []string
+		for _, value := range specString.FindAllStringSubmatch(match[2], -1) {
+			values = append(values, value[1])
+		}
+		switch match[1] {
+		case "license", "licenses":
+			spec.Licenses = append(spec.Licenses, values...)
+		case "homepage":
+			if len(values) > 0 {
+				spec.HomePage = values[0]
+			}
+		case "authors":
+			spec.Authors = values
+		}
+	}
+	return spec, nil
+}
+
+// enrich sets the metadata of the installed gem, else the one the gem server publishes
+func (m *gemMetadata) enrich(mod *models.Module, spec *lockedSpec) {
+	if m == nil || spec.Source.Type == sourcePath {
+		return
+	}
+
+	name := spec.fullName()
+	for _, gemPath := range m.gemPaths {
+		installed, err := readInstalledSpec(filepath.Join(gemPath, SPEC_DEFAULT_DIR, name+SPEC_EXTENSION))
+		if err != nil {
+			continue
+		}
+		mod.LocalPath = filepath.Join(gemPath, GEM_DEFAULT_DIR, name)
+		if license := helper.LicenseAlternatives(installed.Licenses); license != "" {
+			mod.LicenseDeclared = license
+			mod.LicenseConcluded = license
+		} else {
+			setLicenseInfo(m.matcher, mod.LocalPath, mod)
+		}
+		mod.PackageHomePage = installed.HomePage
+		if len(installed.Authors) > 0 {
+			mod.Supplier = models.SupplierContact{Type: models.Person, Name: installed.Authors[0]}
+		}
+		if mod.CheckSum == nil {
+			if sha, err := getSHA(filepath.Join(gemPath, CACHE_DEFAULT_DIR, name+GEM_DEFAULT_EXTENSION)); err == nil && sha != "" {
+				mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: sha}
+			}
+		}
+		return
+	}
+
+	if m.fetcher == nil || spec.Source.Type != sourceGem {
+		return
+	}
+	key := mod.PackageURL
+	entry, ok := m.cache.Get(key)
+	if !ok {
+		metadata, err := m.fetcher.fetchVersion(m.ctx, nonEmpty(spec.Source.Remote, rubygemsURL), spec)
+		if err != nil {
+			log.Debugf("failed to fetch the metadata of %s: %v", name, err)
+			return
+		}
+		entry = cache.Entry{
+			License:          helper.LicenseAlternatives(metadata.Licenses),
+			HomePage:         metadata.HomepageURI,
+			SourceRepository: metadata.SourceCodeURI,
+		}
+		if metadata.SHA != "" {
+			entry.ChecksumAlgorithm, entry.Checksum = string(models.HashAlgoSHA256), metadata.SHA
+		}
+		if err := m.cache.Put(key, entry); err != nil {
+			log.Warnf("failed to write the resume cache: %v", err)
+		}
+	}
+
+	if entry.License != "" {
+		mod.LicenseDeclared = entry.License
+		mod.LicenseConcluded = entry.License
+	}
+	mod.PackageHomePage = entry.HomePage
+	if mod.CheckSum == nil && entry.Checksum != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: entry.Checksum}
+	}
+}
+
+// fetchVersion fetches the metadata of a gem version from /api/v2/rubygems/<name>/versions/<version>.json
+func (f *rubygemsFetcher) fetchVersion(ctx context.Context, remote string, spec *lockedSpec) (*GemMetaVM, error) {
+	u := fmt.Sprintf("%s/api/v2/rubygems/%s/versions/%s.json", strings.TrimSuffix(remote, "/"),
+		url.PathEscape(spec.Name), url.PathEscape(spec.Version))
+	if spec.Platform != rubyPlatform {
+		u += "?platform=" + url.QueryEscape(spec.Platform)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := f.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s %s", errUnexpectedStatus, u, response.Status)
+	}
+
+	metadata := &GemMetaVM{}
+	if err := json.NewDecoder(response.Body).Decode(metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
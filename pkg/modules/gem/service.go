@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 )
 
 type (
@@ -63,6 +65,8 @@ type (
 		response *http.Response
 		name     string
 		err      error
+		// offline fails the requests with ErrOffline
+		offline bool
 	}
 )
 
@@ -72,7 +76,7 @@ const (
 	DEFAULT_RESPONSE_TYPE = ".json"
 )
 
-func NewService(name string) (*GemService, error) {
+func NewService(name string, offline bool) (*GemService, error) {
 	url := fmt.Sprintf("%s/%s%s", DEFAULT_URL, name, DEFAULT_RESPONSE_TYPE)
 	request, err := http.NewRequest(DEFAULT_METHOD, url, nil)
 	if err != nil {
@@ -83,12 +87,17 @@ func NewService(name string) (*GemService, error) {
 		response: nil,
 		name:     name,
 		err:      nil,
+		offline:  offline,
 	}, nil
 }
 
 func (service *GemService) GetGem() (GemMetaVM, error) {
 
 	var metadata GemMetaVM
+	if service.offline {
+		service.err = helper.ErrOffline
+		return GemMetaVM{}, service.err
+	}
 	service.response, service.err = http.DefaultClient.Do(service.request)
 
 	if service.err != nil {
@@ -13,6 +13,8 @@ var (
 	RootModuleCmd  command = "go list -mod readonly -json -m"
 	ModulesCmd     command = "go list -deps -json ./..."
 	GraphModuleCmd command = "go mod graph"
+	// WorkspaceModulesCmd is followed by the package patterns of the workspace modules
+	WorkspaceModulesCmd command = "go list -deps -json"
 )
 
 // Parse ...
@@ -24,13 +24,15 @@ var errFailedtoReadMod = errors.New("Failed to read go.mod line")
 
 // Decoder
 type Decoder struct {
-	reader io.Reader
+	reader  io.Reader
+	matcher helper.LicenseMatcher
 }
 
-// NewDecoder ...
-func NewDecoder(r io.Reader) *Decoder {
+// NewDecoder returns a decoder identifying the licenses of the modules with the matcher, nil for the SPDX license list
+func NewDecoder(r io.Reader, matcher helper.LicenseMatcher) *Decoder {
 	return &Decoder{
-		reader: r,
+		reader:  r,
+		matcher: matcher,
 	}
 }
 
@@ -108,13 +110,16 @@ func (d *Decoder) ConvertJSONReaderToModules(path string, modules *[]models.Modu
 		}
 
 		pathMap[j.Module.Path] = true
-		md, err := buildModule(j.Module)
+		md, err := buildModule(j.Module, d.matcher)
 		if err != nil {
 			return err
 		}
 
 		if j.Module.Path == path {
 			md.Root = true
+		}
+		// the modules of a workspace are local, like the main module
+		if j.Module.Path == path || j.Module.Main {
 			md.PackageDownloadLocation = buildRootDownloadURL(md.LocalPath)
 		}
 		*modules = append(*modules, *md)
@@ -133,7 +138,7 @@ func (d *Decoder) ConvertJSONReaderToSingleModule(module *models.Module) error {
 	return err
 }
 
-func buildModule(m *Module) (*models.Module, error) {
+func buildModule(m *Module, matcher helper.LicenseMatcher) (*models.Module, error) {
 	localDir := buildLocalPath(m.Path, m.Dir)
 	contentCheckSum := helper.BuildManifestContent(localDir)
 	module := models.Module{
@@ -141,6 +146,7 @@ func buildModule(m *Module) (*models.Module, error) {
 		Version:                 m.Version,
 		LocalPath:               localDir,
 		PackageURL:              m.Path,
+		PackageHomePage:         buildHomePage(m.Path),
 		PackageDownloadLocation: buildDownloadURL(m.Path, m.Version),
 		CheckSum: &models.CheckSum{
 			Algorithm: models.HashAlgoSHA256,
@@ -151,7 +157,12 @@ func buildModule(m *Module) (*models.Module, error) {
 			Name: helper.BuildModuleName(m.Path, m.Replace.Path, m.Replace.Dir),
 		},
 	}
-	licensePkg, err := helper.GetLicenses(localDir)
+	// a module replaced by a local directory is built from it, not downloaded
+	if m.Replace.Dir != "" && m.Replace.Version == "" {
+		module.PackageDownloadLocation = ""
+		module.Annotations = append(module.Annotations, fmt.Sprintf("the module is replaced by the local directory %s", m.Replace.Path))
+	}
+	licensePkg, err := findLicense(m, localDir, matcher)
 	if err == nil {
 		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
 		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
@@ -219,6 +230,16 @@ func buildRootDownloadURL(localPath string) string {
 	return fmt.Sprintf("git+%s", url)
 }
 
+// buildHomePage returns the module path as an url, paths without a domain, e.g. local modules, have none
+func buildHomePage(path string) string {
+	domain := strings.SplitN(path, "/", 2)[0]
+	if !strings.Contains(domain, ".") {
+		return ""
+	}
+
+	return fmt.Sprintf("https://%s", path)
+}
+
 func buildDownloadURL(path, version string) string {
 	if strings.HasPrefix(path, "github.com") {
 		if version != "" {
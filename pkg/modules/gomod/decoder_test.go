@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHomePage(t *testing.T) {
+	assert.Equal(t, "https://github.com/spf13/cobra", buildHomePage("github.com/spf13/cobra"))
+	assert.Equal(t, "https://golang.org/x/mod", buildHomePage("golang.org/x/mod"))
+	assert.Equal(t, "", buildHomePage("example/local"))
+	assert.Equal(t, "", buildHomePage(""))
+}
+
+func TestFindLicenseFromModuleCache(t *testing.T) {
+	os.Setenv("GOMODCACHE", filepath.Join("testdata", "modcache"))
+	defer os.Unsetenv("GOMODCACHE")
+
+	license, err := findLicense(&Module{Path: "github.com/Example/lib", Version: "v1.0.0"}, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT", license.ID)
+
+	module, err := buildModule(&Module{Path: "github.com/example/repo/sub", Version: "v0.1.0"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT", module.LicenseConcluded)
+	assert.Equal(t, "MIT", module.LicenseDeclared)
+
+	_, err = findLicense(&Module{Path: "github.com/example/missing", Version: "v1.0.0"}, "", nil)
+	assert.Error(t, err)
+}
+
+// proprietaryLicenseMatcher concludes every license text is the ACME proprietary license
+type proprietaryLicenseMatcher struct{}
+
+func (proprietaryLicenseMatcher) Match(text string) (string, float64) {
+	return "ACME-Proprietary", 0.9
+}
+
+func TestBuildModuleWithLicenseMatcher(t *testing.T) {
+	module, err := buildModule(&Module{Path: "github.com/Example/lib", Version: "v1.0.0",
+		Dir: filepath.Join("testdata", "modcache", "github.com", "!example", "lib@v1.0.0")}, proprietaryLicenseMatcher{})
+	assert.NoError(t, err)
+	assert.Equal(t, "LicenseRef-ACME-Proprietary", module.LicenseConcluded)
+	assert.Equal(t, "LicenseRef-ACME-Proprietary", module.LicenseDeclared)
+	assert.Len(t, module.OtherLicense, 1)
+}
@@ -14,3 +14,4 @@ var errBuildlingModuleDependencies errType = errors.New("Error building modules
 var errNoGoCommand errType = errors.New("No Golang command")
 var errFailedToConvertModules errType = errors.New("Failed to convert modules")
 var errNoMainModule errType = errors.New("No main module found")
+var errWorkspaceModuleNotFound errType = errors.New("No go.mod found for the workspace module")
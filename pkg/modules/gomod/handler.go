@@ -4,7 +4,9 @@ package gomod
 
 import (
 	"bytes"
+	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
@@ -16,7 +18,7 @@ func New() *mod {
 		metadata: models.PluginMetadata{
 			Name:     "Go Modules",
 			Slug:     "go-mod",
-			Manifest: []string{"go.mod"},
+			Manifest: []string{goModFile, goWorkFile},
 		},
 	}
 }
@@ -26,6 +28,11 @@ func (m *mod) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
+// SetOptions ...
+func (m *mod) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // SetRootModule ...
 func (m *mod) SetRootModule(path string) error {
 	module, err := m.getModule(path)
@@ -50,6 +57,9 @@ func (m *mod) IsValid(path string) bool {
 
 // HasModulesInstalled ...
 func (m *mod) HasModulesInstalled(path string) error {
+	if m.options.GoVendor && !hasVendoredModules(path) {
+		return errDependenciesNotFound
+	}
 	// we dont need to validate if packages are installed as process to read depedencies will download them
 	return nil
 }
@@ -79,23 +89,41 @@ func (m *mod) GetRootModule(path string) (*models.Module, error) {
 
 // ListUsedModules...
 func (m *mod) ListUsedModules(path string) ([]models.Module, error) {
-	if err := m.buildCmd(ModulesCmd, path); err != nil {
-		return nil, err
+	if m.options.GoVendor {
+		return listVendoredModules(path, m.options.LicenseMatcher)
 	}
-
-	buffer := new(bytes.Buffer)
-	if err := m.command.Execute(buffer); err != nil {
-		return nil, err
+	if m.options.LockfileOnly {
+		return listRequiredModules(path, m.options.LicenseMatcher)
 	}
-	defer buffer.Reset()
 
 	mainModule, err := m.GetRootModule(path)
 	if err != nil {
 		return nil, err
 	}
 
+	cmd, dir := ModulesCmd, path
+	if m.workspace != nil {
+		// the workspace directory is not a module when its go.work uses subdirectories only
+		cmd = command(fmt.Sprintf("%s %s", WorkspaceModulesCmd, strings.Join(m.workspace.packagePatterns(), " ")))
+		dir = m.workspace.Dir
+	}
+	if err := m.buildCmd(cmd, dir); err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := m.command.Execute(buffer); err != nil {
+		return nil, helper.OfflineError(m.options.Offline, err, "run go mod download first")
+	}
+	defer buffer.Reset()
+
 	modules := []models.Module{}
-	if err := NewDecoder(buffer).ConvertJSONReaderToModules(mainModule.Path, &modules); err != nil {
+	if m.workspace != nil {
+		if _, ok := m.workspace.module(mainModule.Path); !ok {
+			modules = append(modules, *mainModule)
+		}
+	}
+	if err := NewDecoder(buffer, m.options.LicenseMatcher).ConvertJSONReaderToModules(mainModule.Path, &modules); err != nil {
 		return nil, err
 	}
 
@@ -108,25 +136,48 @@ func (m *mod) ListModulesWithDeps(path string) ([]models.Module, error) {
 	if err != nil {
 		return nil, err
 	}
+	if m.options.GoVendor || m.options.LockfileOnly {
+		return modules, nil
+	}
 
-	if err := m.buildCmd(GraphModuleCmd, path); err != nil {
+	dir := path
+	if m.workspace != nil {
+		dir = m.workspace.Dir
+	}
+	if err := m.buildCmd(GraphModuleCmd, dir); err != nil {
 		return nil, err
 	}
 
 	buffer := new(bytes.Buffer)
 	if err := m.command.Execute(buffer); err != nil {
-		return nil, err
+		return nil, helper.OfflineError(m.options.Offline, err, "run go mod download first")
 	}
 	defer buffer.Reset()
 
-	if err := NewDecoder(buffer).ConvertPlainReaderToModules(modules); err != nil {
+	if err := NewDecoder(buffer, m.options.LicenseMatcher).ConvertPlainReaderToModules(modules); err != nil {
 		return nil, err
 	}
+	if m.workspace != nil {
+		m.workspace.linkModules(modules)
+	}
 
 	return modules, nil
 }
 
 func (m *mod) getModule(path string) (models.Module, error) {
+	if m.options.GoVendor || m.options.LockfileOnly {
+		return vendorRootModule(path, m.options.LicenseMatcher)
+	}
+
+	w, err := readWorkspace(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+	m.workspace = w
+	if w != nil {
+		return w.rootModule(path)
+	}
+
 	if err := m.buildCmd(RootModuleCmd, path); err != nil {
 		return models.Module{}, err
 	}
@@ -138,7 +189,7 @@ func (m *mod) getModule(path string) (models.Module, error) {
 	defer buffer.Reset()
 
 	module := models.Module{}
-	if err := NewDecoder(buffer).ConvertJSONReaderToSingleModule(&module); err != nil {
+	if err := NewDecoder(buffer, m.options.LicenseMatcher).ConvertJSONReaderToSingleModule(&module); err != nil {
 		return models.Module{}, err
 	}
 
@@ -155,11 +206,17 @@ func (m *mod) buildCmd(cmd command, path string) error {
 		return errNoGoCommand
 	}
 
-	command := helper.NewCmd(helper.CmdOptions{
+	opts := helper.CmdOptions{
 		Name:      cmdArgs[0],
 		Args:      cmdArgs[1:],
 		Directory: path,
-	})
+		Context:   m.options.Context,
+	}
+	if m.options.Offline {
+		// the module cache and vendor directory only, a missing module fails instead of being downloaded
+		opts.Env = []string{"GOPROXY=off"}
+	}
+	command := helper.NewCmd(opts)
 
 	m.command = command
 
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// moduleCacheRoot returns the module cache location, GOMODCACHE or GOPATH/pkg/mod
+func moduleCacheRoot() string {
+	if cache := os.Getenv("GOMODCACHE"); cache != "" {
+		return cache
+	}
+
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
+	if gopath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// moduleCacheDir returns the directory the module version is extracted to in the cache
+func moduleCacheDir(cacheRoot, modulePath, version string) (string, bool) {
+	if cacheRoot == "" || version == "" {
+		return "", false
+	}
+
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", false
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.Join(cacheRoot, filepath.FromSlash(escapedPath)+"@"+escapedVersion), true
+}
+
+// repositoryCacheDirs returns the cached versions of the modules the module path is nested in,
+// e.g. github.com/org/repo for github.com/org/repo/sub, the license of the repository lives there.
+// The latest versions come first
+func repositoryCacheDirs(cacheRoot, modulePath string) []string {
+	if cacheRoot == "" {
+		return nil
+	}
+
+	var dirs []string
+	for parent := path.Dir(modulePath); strings.Contains(parent, "/"); parent = path.Dir(parent) {
+		escapedPath, err := module.EscapePath(parent)
+		if err != nil {
+			return dirs
+		}
+
+		matches, _ := filepath.Glob(filepath.Join(cacheRoot, filepath.FromSlash(escapedPath)+"@*"))
+		sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+		dirs = append(dirs, matches...)
+	}
+
+	return dirs
+}
+
+// findLicense detects the license of the module, looking at its local directory, then its
+// module cache directory and last the cached repository the module is nested in
+func findLicense(m *Module, localDir string, matcher helper.LicenseMatcher) (*models.License, error) {
+	license, err := helper.GetLicenses(matcher, localDir)
+	if err == nil {
+		return license, nil
+	}
+
+	var candidates []string
+	cacheRoot := moduleCacheRoot()
+	if dir, ok := moduleCacheDir(cacheRoot, m.Path, m.Version); ok && dir != localDir {
+		candidates = append(candidates, dir)
+	}
+	candidates = append(candidates, repositoryCacheDirs(cacheRoot, m.Path)...)
+
+	for _, dir := range candidates {
+		if !helper.Exists(dir) {
+			continue
+		}
+		if license, cacheErr := helper.GetLicenses(matcher, dir); cacheErr == nil {
+			return license, nil
+		}
+	}
+
+	return nil, err
+}
@@ -11,10 +11,13 @@ type mod struct {
 	metadata   models.PluginMetadata
 	rootModule *models.Module
 	command    *helper.Cmd
+	options    models.PluginOptions
+	// workspace is the go.work of the path, nil outside the workspace mode
+	workspace *workspace
 }
 
 type JSONOutput struct {
-	Dir        string  `json: "Dir,omitempty"`
+	Dir        string  `json:"Dir,omitempty"`
 	ImportPath string  `json:"ImportPath,omitempty"`
 	Name       string  `json:"Name,omitempty"`
 	Module     *Module `json:"Module,omitempty"`
@@ -27,10 +30,13 @@ type Module struct {
 	Replace   modReplace `json:"Replace,omitempty"`
 	GoMod     string     `json:"GoMod,omitempty"`
 	GoVersion string     `json:"GoVersion,omitempty"`
+	// Main is set for the main module, and for every module of the workspace in the workspace mode
+	Main bool `json:"Main,omitempty"`
 }
 
 type modReplace struct {
 	Path      string `json:"Path,omitempty"`
+	Version   string `json:"Version,omitempty"`
 	Dir       string `json:"Dir,noempty"`
 	GoMod     string `json:"GoMod,omitempty"`
 	GoVersion string `json:"GoVersion,omitempty"`
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// indirectComment marks the requirements of a go.mod no package of the main module imports
+const indirectComment = "indirect"
+
+// requirement is a require directive of a go.mod
+type requirement struct {
+	Path    string
+	Version string
+	// Indirect is set for the modules the main module only requires through its dependencies
+	Indirect bool
+}
+
+// readRequirements returns the modules the go.mod of the directory requires and its replacements
+func readRequirements(path string) ([]requirement, []replacement, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, goModFile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var requirements []requirement
+	var replacements []replacement
+	for _, d := range parseDirectives(data) {
+		switch {
+		case d.Verb == "require" && len(d.Args) >= 2:
+			requirements = append(requirements, requirement{Path: d.Args[0], Version: d.Args[1], Indirect: d.Comment == indirectComment})
+		case d.Verb == "replace":
+			if r, ok := parseReplacement(d.Args); ok {
+				replacements = append(replacements, r)
+			}
+		}
+	}
+	return requirements, replacements, nil
+}
+
+// replacementOf returns the replacement of the module version, a replacement without an old version
+// replaces every version
+func replacementOf(replacements []replacement, path, version string) (replacement, bool) {
+	for _, r := range replacements {
+		if r.Old == path && (r.OldVersion == "" || r.OldVersion == version) {
+			return r, true
+		}
+	}
+	return replacement{}, false
+}
+
+// listRequiredModules returns the main module and the modules its go.mod requires, without the go command,
+// or the vendored ones when the project vendors them. The go.mod of Go 1.17 and later requires every module
+// of the build, the earlier ones only the direct dependencies. The go.mod does not tell which module requires
+// an indirect one, so the root depends on all of them
+func listRequiredModules(path string, matcher helper.LicenseMatcher) ([]models.Module, error) {
+	if hasVendoredModules(path) {
+		return listVendoredModules(path, matcher)
+	}
+
+	root, err := vendorRootModule(path, matcher)
+	if err != nil {
+		return nil, err
+	}
+	requirements, replacements, err := readRequirements(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheRoot := moduleCacheRoot()
+	modules := []models.Module{root}
+	for _, req := range requirements {
+		m := &Module{Path: req.Path, Version: req.Version}
+		if r, ok := replacementOf(replacements, req.Path, req.Version); ok {
+			if r.isLocal() {
+				dir := r.New
+				if !filepath.IsAbs(dir) {
+					dir = filepath.Join(root.LocalPath, filepath.FromSlash(dir))
+				}
+				m.Dir = dir
+				m.Replace = modReplace{Path: r.New, Dir: dir}
+			} else {
+				// the module is downloaded at the version of its replacement
+				m.Replace = modReplace{Path: r.New, Version: r.NewVersion}
+				m.Version = r.NewVersion
+			}
+		}
+		if m.Dir == "" {
+			modulePath := m.Path
+			if m.Replace.Path != "" {
+				modulePath = m.Replace.Path
+			}
+			if dir, ok := moduleCacheDir(cacheRoot, modulePath, m.Version); ok && helper.Exists(dir) {
+				m.Dir = dir
+			}
+		}
+
+		md, err := buildModule(m, matcher)
+		if err != nil {
+			return nil, err
+		}
+		md.LocalPath = m.Dir
+		md.Name = req.Path
+		md.Supplier.Name = req.Path
+		if m.Dir == "" {
+			// the module is neither downloaded nor vendored, there is nothing to hash
+			md.CheckSum = nil
+		}
+		if req.Indirect {
+			md.Unresolved = append(md.Unresolved, "the module is required as an indirect dependency, the module requiring it is unknown")
+		}
+		modules = append(modules, *md)
+	}
+
+	for i := 1; i < len(modules); i++ {
+		linked := modules[i]
+		modules[0].Modules[linked.Name] = &linked
+	}
+	return modules, nil
+}
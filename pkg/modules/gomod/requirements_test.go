@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListRequiredModules(t *testing.T) {
+	os.Setenv("GOMODCACHE", filepath.Join("testdata", "modcache"))
+	defer os.Unsetenv("GOMODCACHE")
+
+	modules, err := listRequiredModules(filepath.Join("testdata", "required"), nil)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "example.com/api", root.Name)
+	assert.True(t, root.Root)
+	assert.Len(t, root.Modules, 4)
+
+	lib := modules[1]
+	assert.Equal(t, "github.com/Example/lib", lib.Name)
+	assert.Equal(t, "v1.0.0", lib.Version)
+	assert.Equal(t, "MIT", lib.LicenseDeclared)
+	assert.NotNil(t, lib.CheckSum)
+
+	text := modules[2]
+	assert.Equal(t, "golang.org/x/text", text.Name)
+	assert.Equal(t, "v0.3.8", text.Version)
+	assert.Nil(t, text.CheckSum)
+	assert.Len(t, text.Unresolved, 1)
+
+	local := modules[3]
+	assert.Equal(t, "example.com/lib", local.Name)
+	assert.Empty(t, local.PackageDownloadLocation)
+
+	assert.Equal(t, "github.com/pkg/errors", modules[4].Name)
+	assert.Empty(t, modules[4].Annotations)
+}
+
+func TestLockfileOnlyMode(t *testing.T) {
+	m := New()
+	m.SetOptions(models.PluginOptions{LockfileOnly: true})
+
+	path := filepath.Join("testdata", "required")
+	assert.NoError(t, m.SetRootModule(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 5)
+
+	// the vendored modules are listed when the project vendors them
+	modules, err = m.ListModulesWithDeps(filepath.Join("testdata", "vendored"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 5)
+}
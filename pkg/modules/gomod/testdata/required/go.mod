@@ -0,0 +1,15 @@
+module example.com/api
+
+go 1.21
+
+require (
+	github.com/Example/lib v1.0.0
+	golang.org/x/text v0.3.0 // indirect
+	example.com/lib v0.0.0
+)
+
+require github.com/pkg/errors v0.9.1
+
+replace golang.org/x/text v0.3.0 => golang.org/x/text v0.3.8
+
+replace example.com/lib => ./lib
@@ -0,0 +1,14 @@
+module example.com/shop
+
+go 1.21
+
+require (
+	example.com/tools v0.0.0
+	example.com/unused v1.0.0
+	github.com/pkg/errors v0.9.1
+	golang.org/x/text v0.3.0
+)
+
+replace golang.org/x/text v0.3.0 => golang.org/x/text v0.3.8
+
+replace example.com/tools => ./tools
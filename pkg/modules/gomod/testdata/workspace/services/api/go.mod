@@ -0,0 +1,13 @@
+module example.com/shop/api
+
+go 1.21
+
+require (
+	example.com/shop/auth v0.0.0
+	example.com/shop/tools v0.0.0
+	golang.org/x/text v0.3.0 // indirect
+)
+
+replace example.com/shop/auth => ../../libs/auth
+
+replace example.com/shop/tools => ../../tools
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// vendorModulesFile is written by go mod vendor, it lists the vendored modules and their packages
+var vendorModulesFile = filepath.Join(vendorFolder, "modules.txt")
+
+// vendoredModule is a module of vendor/modules.txt
+type vendoredModule struct {
+	Path    string
+	Version string
+	// Replace is the replacement module, or directory, of the module
+	Replace replacement
+	// Explicit is set for the modules the go.mod requires
+	Explicit bool
+	// Packages are the vendored packages of the module, the modules none is built from have none
+	Packages []string
+}
+
+// readVendorModules reads the modules of vendor/modules.txt:
+//
+//	# github.com/pkg/errors v0.9.1
+//	## explicit; go 1.13
+//	github.com/pkg/errors
+//	# example.com/lib v1.0.0 => ../lib
+func readVendorModules(path string) ([]vendoredModule, error) {
+	file := filepath.Join(path, vendorModulesFile)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []vendoredModule
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "## "):
+			if len(modules) == 0 {
+				return nil, reader.MalformedError(file, i+1, "annotation before the first module")
+			}
+			for _, annotation := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+				if strings.TrimSpace(annotation) == "explicit" {
+					modules[len(modules)-1].Explicit = true
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			module, ok := parseVendoredModule(strings.Fields(strings.TrimPrefix(line, "# ")))
+			if !ok {
+				return nil, reader.MalformedError(file, i+1, fmt.Sprintf("unexpected module line %q", line))
+			}
+			modules = append(modules, module)
+		case strings.HasPrefix(line, "#"):
+		default:
+			if len(modules) == 0 {
+				return nil, reader.MalformedError(file, i+1, "package before the first module")
+			}
+			modules[len(modules)-1].Packages = append(modules[len(modules)-1].Packages, line)
+		}
+	}
+	return modules, nil
+}
+
+// parseVendoredModule reads the fields of a module line: path [version] [=> new [version]]
+func parseVendoredModule(fields []string) (vendoredModule, bool) {
+	if len(fields) == 0 || fields[0] == "=>" {
+		return vendoredModule{}, false
+	}
+
+	module := vendoredModule{Path: fields[0]}
+	if r, ok := parseReplacement(fields); ok {
+		module.Version = r.OldVersion
+		module.Replace = r
+		return module, true
+	}
+	if len(fields) > 2 {
+		return vendoredModule{}, false
+	}
+	if len(fields) == 2 {
+		module.Version = fields[1]
+	}
+	return module, true
+}
+
+// readModulePath returns the module path of the go.mod of the directory
+func readModulePath(path string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, goModFile))
+	if err != nil {
+		return "", err
+	}
+	for _, d := range parseDirectives(data) {
+		if d.Verb == "module" && len(d.Args) > 0 {
+			return d.Args[0], nil
+		}
+	}
+	return "", errNoMainModule
+}
+
+// vendorRootModule returns the main module of the directory, read from its go.mod without the go command
+func vendorRootModule(path string, matcher helper.LicenseMatcher) (models.Module, error) {
+	modulePath, err := readModulePath(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+
+	md, err := buildModule(&Module{Path: modulePath, Dir: dir}, matcher)
+	if err != nil {
+		return models.Module{}, err
+	}
+	md.Path = modulePath
+	md.LocalPath = dir
+	md.Root = true
+	md.PackageDownloadLocation = buildRootDownloadURL(dir)
+	return *md, nil
+}
+
+// listVendoredModules returns the main module and the vendored modules packages are built from. The root
+// depends on all of them: vendor/modules.txt records which modules are vendored, not which module requires them
+func listVendoredModules(path string, matcher helper.LicenseMatcher) ([]models.Module, error) {
+	root, err := vendorRootModule(path, matcher)
+	if err != nil {
+		return nil, err
+	}
+	vendored, err := readVendorModules(path)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []models.Module{root}
+	for _, v := range vendored {
+		if len(v.Packages) == 0 {
+			continue
+		}
+
+		m := &Module{
+			Path:    v.Path,
+			Version: v.Version,
+			Dir:     filepath.Join(path, vendorFolder, filepath.FromSlash(v.Path)),
+		}
+		if v.Replace.New != "" {
+			m.Replace = modReplace{Path: v.Replace.New, Version: v.Replace.NewVersion, Dir: m.Dir}
+			if !v.Replace.isLocal() {
+				// the module is downloaded at the version of its replacement
+				m.Version = v.Replace.NewVersion
+			}
+		}
+		md, err := buildModule(m, matcher)
+		if err != nil {
+			return nil, err
+		}
+		// the module is the one vendored in the project, not in the vendor directory of the working directory
+		md.LocalPath = m.Dir
+		md.Name = v.Path
+		md.Supplier.Name = v.Path
+		if !v.Explicit {
+			md.Unresolved = append(md.Unresolved, "the module is vendored as an indirect dependency, the module requiring it is unknown")
+		}
+		modules = append(modules, *md)
+	}
+
+	for i := 1; i < len(modules); i++ {
+		linked := modules[i]
+		modules[0].Modules[linked.Name] = &linked
+	}
+	return modules, nil
+}
+
+// hasVendoredModules tells whether go mod vendor wrote the vendor/modules.txt of the directory
+func hasVendoredModules(path string) bool {
+	return helper.Exists(filepath.Join(path, vendorModulesFile))
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadVendorModules(t *testing.T) {
+	modules, err := readVendorModules(filepath.Join("testdata", "vendored"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 6)
+
+	assert.Equal(t, "example.com/tools", modules[0].Path)
+	assert.Equal(t, "v0.0.0", modules[0].Version)
+	assert.True(t, modules[0].Replace.isLocal())
+	assert.Equal(t, []string{"example.com/tools/lint"}, modules[0].Packages)
+	assert.True(t, modules[1].Explicit)
+	assert.Empty(t, modules[1].Packages)
+	assert.False(t, modules[3].Explicit)
+	assert.Equal(t, "v0.3.8", modules[4].Replace.NewVersion)
+	assert.Len(t, modules[4].Packages, 2)
+}
+
+func TestReadVendorModulesMalformed(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, vendorFolder), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, vendorModulesFile), []byte("github.com/pkg/errors\n"), 0644))
+
+	_, err := readVendorModules(dir)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestListVendoredModules(t *testing.T) {
+	os.Setenv("GOMODCACHE", t.TempDir())
+	defer os.Unsetenv("GOMODCACHE")
+
+	modules, err := listVendoredModules(filepath.Join("testdata", "vendored"), nil)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "example.com/shop", root.Name)
+	assert.True(t, root.Root)
+	assert.Len(t, root.Modules, 4)
+
+	tools := modules[1]
+	assert.Equal(t, "example.com/tools", tools.Name)
+	assert.Empty(t, tools.PackageDownloadLocation)
+
+	pkgErrors := modules[2]
+	assert.Equal(t, "github.com/pkg/errors", pkgErrors.Name)
+	assert.Equal(t, "v0.9.1", pkgErrors.Version)
+	assert.Equal(t, "MIT", pkgErrors.LicenseDeclared)
+	assert.Empty(t, pkgErrors.Annotations)
+
+	assert.Len(t, modules[3].Unresolved, 1)
+
+	text := modules[4]
+	assert.Equal(t, "golang.org/x/text", text.Name)
+	assert.Equal(t, "v0.3.8", text.Version)
+}
+
+func TestVendorMode(t *testing.T) {
+	m := New()
+	m.SetOptions(models.PluginOptions{GoVendor: true})
+
+	assert.NoError(t, m.HasModulesInstalled(filepath.Join("testdata", "vendored")))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled(filepath.Join("testdata", "workspace")))
+
+	root, err := m.GetRootModule(filepath.Join("testdata", "vendored"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/shop", root.Path)
+}
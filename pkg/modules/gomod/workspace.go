@@ -0,0 +1,325 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	goModFile  = "go.mod"
+	goWorkFile = "go.work"
+)
+
+// directive is a line of a go.mod or a go.work, e.g. use ./app, or a line of a block, e.g. ./app of use ( ./app ).
+// Comment is its trailing comment, e.g. indirect
+type directive struct {
+	Verb    string
+	Args    []string
+	Comment string
+}
+
+// replacement is a replace directive, New is a directory when it is replaced by a local module
+type replacement struct {
+	Old        string
+	OldVersion string
+	New        string
+	NewVersion string
+}
+
+// workspace is a go.work and the modules it uses
+type workspace struct {
+	Dir      string
+	Modules  []workspaceModule
+	Replaces []replacement
+}
+
+// workspaceModule is a module of the workspace, read from its go.mod
+type workspaceModule struct {
+	// Dir is the absolute directory of the module and Use the directory the use directive names
+	Dir      string
+	Use      string
+	Path     string
+	Requires []string
+	Replaces []replacement
+}
+
+// readWorkspace reads the go.work of the directory, or the one GOWORK names, and the go.mod of the modules it
+// uses. It returns nil when the directory is not a workspace or the workspace mode is off
+func readWorkspace(path string) (*workspace, error) {
+	file := filepath.Join(path, goWorkFile)
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return nil, nil
+	case "", "auto":
+	default:
+		file = gowork
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return nil, err
+	}
+	w := &workspace{Dir: dir}
+	for _, d := range parseDirectives(data) {
+		switch d.Verb {
+		case "use":
+			if len(d.Args) == 0 {
+				continue
+			}
+			module, err := readWorkspaceModule(dir, d.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			w.Modules = append(w.Modules, module)
+		case "replace":
+			if r, ok := parseReplacement(d.Args); ok {
+				w.Replaces = append(w.Replaces, r)
+			}
+		}
+	}
+	return w, nil
+}
+
+func readWorkspaceModule(workspaceDir, use string) (workspaceModule, error) {
+	module := workspaceModule{Dir: filepath.Join(workspaceDir, filepath.FromSlash(use)), Use: use}
+	if filepath.IsAbs(use) {
+		module.Dir = use
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(module.Dir, goModFile))
+	if err != nil {
+		return workspaceModule{}, fmt.Errorf("%w: %s", errWorkspaceModuleNotFound, use)
+	}
+	for _, d := range parseDirectives(data) {
+		switch {
+		case d.Verb == "module" && len(d.Args) > 0:
+			module.Path = d.Args[0]
+		case d.Verb == "require" && len(d.Args) > 0:
+			module.Requires = append(module.Requires, d.Args[0])
+		case d.Verb == "replace":
+			if r, ok := parseReplacement(d.Args); ok {
+				module.Replaces = append(module.Replaces, r)
+			}
+		}
+	}
+	return module, nil
+}
+
+// parseDirectives returns the directives of a go.mod or a go.work, the ones of a block are named after it
+func parseDirectives(data []byte) []directive {
+	var directives []directive
+	block := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := directiveFields(line)
+		switch {
+		case len(fields) == 0:
+		case block != "" && fields[0] == ")":
+			block = ""
+		case block != "":
+			directives = append(directives, directive{Verb: block, Args: fields, Comment: lineComment(line)})
+		case len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+		default:
+			directives = append(directives, directive{Verb: fields[0], Args: fields[1:], Comment: lineComment(line)})
+		}
+	}
+	return directives
+}
+
+// directiveFields splits a line into its unquoted tokens, leaving out its comment
+func directiveFields(line string) []string {
+	var fields []string
+	for line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "//"); line = strings.TrimSpace(line) {
+		end := strings.IndexAny(line, " \t")
+		if line[0] == '"' || line[0] == '`' {
+			end = strings.IndexByte(line[1:], line[0]) + 2
+		}
+		if end <= 0 || end > len(line) {
+			end = len(line)
+		}
+
+		field := line[:end]
+		if unquoted, err := strconv.Unquote(field); err == nil {
+			field = unquoted
+		}
+		fields = append(fields, field)
+		line = line[end:]
+	}
+	return fields
+}
+
+// lineComment returns the trailing comment of a line, without its slashes
+func lineComment(line string) string {
+	i := strings.Index(line, "//")
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSpace(line[i+2:])
+}
+
+// parseReplacement reads the arguments of a replace directive: old [version] => new [version]
+func parseReplacement(args []string) (replacement, bool) {
+	for i, arg := range args {
+		if arg != "=>" || i == 0 || i == len(args)-1 {
+			continue
+		}
+		r := replacement{Old: args[0], New: args[i+1]}
+		if i > 1 {
+			r.OldVersion = args[1]
+		}
+		if len(args) > i+2 {
+			r.NewVersion = args[i+2]
+		}
+		return r, true
+	}
+	return replacement{}, false
+}
+
+// isLocal tells whether the module is replaced by a directory, its path is then absolute or starts with ./ or ../
+func (r replacement) isLocal() bool {
+	return r.NewVersion == "" && (filepath.IsAbs(r.New) || strings.HasPrefix(r.New, "./") || strings.HasPrefix(r.New, "../") ||
+		r.New == "." || r.New == "..")
+}
+
+// packagePatterns returns the go list patterns of the packages of the workspace modules, relative to the workspace
+func (w *workspace) packagePatterns() []string {
+	patterns := make([]string, 0, len(w.Modules))
+	for _, module := range w.Modules {
+		rel, err := filepath.Rel(w.Dir, module.Dir)
+		if err != nil {
+			rel = module.Dir
+		}
+		pattern := filepath.ToSlash(rel) + "/..."
+		if !filepath.IsAbs(rel) {
+			pattern = "./" + pattern
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// rootModule returns the workspace module of the directory, or a module of the workspace directory itself
+// when none is there, e.g. a repository whose modules are all in subdirectories
+func (w *workspace) rootModule(path string) (models.Module, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+	for _, module := range w.Modules {
+		if module.Dir == dir {
+			return models.Module{Name: module.Path, Path: module.Path, LocalPath: module.Dir}, nil
+		}
+	}
+
+	name := filepath.Base(w.Dir)
+	return models.Module{
+		Name:                    name,
+		LocalPath:               w.Dir,
+		PackageDownloadLocation: buildRootDownloadURL(w.Dir),
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   helper.BuildManifestContent(w.Dir),
+		},
+		Supplier: models.SupplierContact{Type: models.Organization, Name: name},
+		Root:     true,
+		Modules:  map[string]*models.Module{},
+	}, nil
+}
+
+// module returns the workspace module of the path
+func (w *workspace) module(path string) (workspaceModule, bool) {
+	for _, module := range w.Modules {
+		if module.Path == path {
+			return module, true
+		}
+	}
+	return workspaceModule{}, false
+}
+
+// linkModules links the workspace modules to the ones they require or replace with a local workspace
+// module, which the module graph may leave out, and the root module CONTAINS the workspace modules
+// nothing else links it to
+func (w *workspace) linkModules(modules []models.Module) {
+	index := map[string]int{}
+	for i, module := range modules {
+		index[module.Name] = i
+	}
+	link := func(parent, child int, relationship models.RelationshipType) {
+		if parent == child {
+			return
+		}
+		linked := modules[child]
+		linked.Relationship = relationship
+		modules[parent].Modules[linked.Name] = &linked
+	}
+
+	for _, module := range w.Modules {
+		parent, ok := index[module.Path]
+		if !ok {
+			continue
+		}
+		dependencies := append([]string{}, module.Requires...)
+		for _, r := range module.Replaces {
+			if _, ok := w.module(r.Old); ok && r.isLocal() {
+				dependencies = append(dependencies, r.Old)
+			}
+		}
+		for _, dependency := range dependencies {
+			if _, ok := w.module(dependency); !ok {
+				continue
+			}
+			if _, linked := modules[parent].Modules[dependency]; !linked {
+				if child, ok := index[dependency]; ok {
+					link(parent, child, "")
+				}
+			}
+		}
+	}
+
+	root := -1
+	for i := range modules {
+		if modules[i].Root {
+			root = i
+		}
+	}
+	if root < 0 {
+		return
+	}
+	for _, module := range w.Modules {
+		child, ok := index[module.Path]
+		if !ok {
+			continue
+		}
+		if isLinked(modules, child) {
+			continue
+		}
+		link(root, child, models.RelationshipContains)
+	}
+}
+
+// isLinked tells whether another module links the module
+func isLinked(modules []models.Module, index int) bool {
+	for i := range modules {
+		if _, ok := modules[i].Modules[modules[index].Name]; ok && i != index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestParseDirectives(t *testing.T) {
+	directives := parseDirectives([]byte(`module "example.com/app" // the app
+
+require (
+	example.com/lib v1.0.0 // indirect
+	` + "`example.com/raw`" + ` v2.0.0
+)
+replace example.com/lib v1.0.0 => ../lib
+`))
+
+	assert.Equal(t, []directive{
+		{Verb: "module", Args: []string{"example.com/app"}, Comment: "the app"},
+		{Verb: "require", Args: []string{"example.com/lib", "v1.0.0"}, Comment: "indirect"},
+		{Verb: "require", Args: []string{"example.com/raw", "v2.0.0"}},
+		{Verb: "replace", Args: []string{"example.com/lib", "v1.0.0", "=>", "../lib"}},
+	}, directives)
+}
+
+func TestParseReplacement(t *testing.T) {
+	r, ok := parseReplacement([]string{"example.com/lib", "v1.0.0", "=>", "../lib"})
+	assert.True(t, ok)
+	assert.Equal(t, replacement{Old: "example.com/lib", OldVersion: "v1.0.0", New: "../lib"}, r)
+	assert.True(t, r.isLocal())
+
+	r, ok = parseReplacement([]string{"golang.org/x/text", "=>", "golang.org/x/text", "v0.3.8"})
+	assert.True(t, ok)
+	assert.False(t, r.isLocal())
+
+	_, ok = parseReplacement([]string{"example.com/lib", "=>"})
+	assert.False(t, ok)
+}
+
+func TestReadWorkspace(t *testing.T) {
+	w, err := readWorkspace(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	if !assert.NotNil(t, w) {
+		return
+	}
+
+	dir, _ := filepath.Abs(filepath.Join("testdata", "workspace"))
+	assert.Equal(t, dir, w.Dir)
+	assert.Len(t, w.Modules, 2)
+	assert.Equal(t, "example.com/shop/api", w.Modules[0].Path)
+	assert.Equal(t, filepath.Join(dir, "services", "api"), w.Modules[0].Dir)
+	assert.Equal(t, []string{"example.com/shop/auth", "example.com/shop/tools", "golang.org/x/text"}, w.Modules[0].Requires)
+	assert.Len(t, w.Modules[0].Replaces, 2)
+	assert.Equal(t, "example.com/shop/auth", w.Modules[1].Path)
+	assert.Len(t, w.Replaces, 1)
+	assert.Equal(t, []string{"./services/api/...", "./libs/auth/..."}, w.packagePatterns())
+
+	root, err := w.rootModule(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	assert.Equal(t, "workspace", root.Name)
+	assert.True(t, root.Root)
+
+	root, err = w.rootModule(filepath.Join("testdata", "workspace", "libs", "auth"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/shop/auth", root.Path)
+}
+
+func TestReadWorkspaceOff(t *testing.T) {
+	os.Setenv("GOWORK", "off")
+	defer os.Unsetenv("GOWORK")
+
+	w, err := readWorkspace(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	assert.Nil(t, w)
+}
+
+func TestReadWorkspaceMissingModule(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, goWorkFile), []byte("go 1.21\n\nuse ./missing\n"), 0644))
+
+	_, err := readWorkspace(dir)
+	assert.True(t, errors.Is(err, errWorkspaceModuleNotFound))
+}
+
+func TestLinkWorkspaceModules(t *testing.T) {
+	w, err := readWorkspace(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+
+	modules := []models.Module{
+		{Name: "workspace", Root: true, Modules: map[string]*models.Module{}},
+		{Name: "example.com/shop/api", Modules: map[string]*models.Module{}},
+		{Name: "example.com/shop/auth", Modules: map[string]*models.Module{}},
+		{Name: "example.com/shop/tools", Modules: map[string]*models.Module{}},
+	}
+	w.linkModules(modules)
+
+	assert.Len(t, modules[0].Modules, 1)
+	assert.Equal(t, models.RelationshipContains, modules[0].Modules["example.com/shop/api"].Relationship)
+	// the auth module is a workspace module, the tools one is not
+	assert.Len(t, modules[1].Modules, 1)
+	assert.Equal(t, models.RelationshipType(""), modules[1].Modules["example.com/shop/auth"].Relationship)
+}
+
+func TestBuildModuleReplacedByLocalDirectory(t *testing.T) {
+	module, err := buildModule(&Module{Path: "example.com/shop/tools", Version: "v0.0.0",
+		Replace: modReplace{Path: "./tools", Dir: filepath.Join("testdata", "workspace", "tools")}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/shop/tools", module.Name)
+	assert.Empty(t, module.PackageDownloadLocation)
+	assert.Len(t, module.Annotations, 1)
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no dist-newstyle/cache/plan.json or cabal.project.freeze found. Please plan the build before running spdx-sbom-generator, e.g.: `cabal build --dry-run` or `cabal freeze`")
+)
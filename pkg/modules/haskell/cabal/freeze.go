@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// frozenPackage is a version constraint of a cabal.project.freeze, an installed one is the version
+// installed with the compiler
+type frozenPackage struct {
+	Name      string
+	Version   string
+	Installed bool
+}
+
+// readFreeze reads the version constraints of a cabal.project.freeze, the flag constraints are skipped:
+//
+//	active-repositories: hackage.haskell.org:merge
+//	constraints: any.aeson ==2.1.2.1,
+//	             aeson -ordered-keymap,
+//	             any.base ==4.17.2.1,
+//	index-state: hackage.haskell.org 2024-01-01T00:00:00Z
+func readFreeze(path string) ([]*frozenPackage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var packages []*frozenPackage
+	seen := map[string]bool{}
+	inConstraints := false
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// a field starts unindented and its value continues on the indented lines
+		if line[0] != ' ' && line[0] != '\t' {
+			field := strings.SplitN(line, ":", 2)
+			inConstraints = len(field) == 2 && strings.EqualFold(strings.TrimSpace(field[0]), "constraints")
+			if !inConstraints {
+				continue
+			}
+			line = field[1]
+		} else if !inConstraints {
+			continue
+		}
+
+		for _, constraint := range strings.Split(line, ",") {
+			constraint = strings.TrimSpace(constraint)
+			if constraint == "" {
+				continue
+			}
+			pkg, err := parseConstraint(constraint)
+			if err != nil {
+				return nil, reader.MalformedError(path, number, err.Error())
+			}
+			if pkg != nil && !seen[pkg.Name] {
+				seen[pkg.Name] = true
+				packages = append(packages, pkg)
+			}
+		}
+	}
+	return packages, scanner.Err()
+}
+
+// parseConstraint parses a version constraint, e.g. any.aeson ==2.1.2.1 or base installed, nil for a
+// flag constraint. The qualifier of the package is dropped, a package name has no dots
+func parseConstraint(constraint string) (*frozenPackage, error) {
+	fields := strings.Fields(constraint)
+	name := fields[0]
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	rest := strings.TrimSpace(strings.TrimPrefix(constraint, fields[0]))
+
+	switch {
+	case strings.HasPrefix(rest, "=="):
+		version := strings.TrimSpace(strings.TrimPrefix(rest, "=="))
+		if version == "" {
+			return nil, fmt.Errorf("constraint %q has no version", constraint)
+		}
+		return &frozenPackage{Name: name, Version: version}, nil
+	case rest == "installed":
+		return &frozenPackage{Name: name, Installed: true}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/haskell/hackage"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type cabal struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ProjectFile string = "cabal.project"
+	FreezeFile  string = "cabal.project.freeze"
+	DistDir     string = "dist-newstyle"
+)
+
+// PlanFile is the build plan cabal writes when it builds the project
+var PlanFile = filepath.Join(DistDir, "cache", "plan.json")
+
+// gitRevision is a sha1 commit hash
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// New creates a new cabal instance
+func New() *cabal {
+	return &cabal{
+		metadata: models.PluginMetadata{
+			Name:       "Haskell Cabal",
+			Slug:       "cabal",
+			Manifest:   []string{ProjectFile, FreezeFile, PlanFile},
+			ModulePath: []string{DistDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *cabal) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the cabal version
+func (m *cabal) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "cabal", "--numeric-version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *cabal) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *cabal) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the project, named after its directory when it has no .cabal file
+func (m *cabal) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return hackage.RootModule(absPath, m.options.LicenseMatcher), nil
+}
+
+// ListUsedModules returns the packages of the project, without the project itself
+func (m *cabal) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the packages of its build plan, linked to the ones
+// they depend on, else the packages of its freeze file
+func (m *cabal) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := hackage.RootModule(absPath, m.options.LicenseMatcher)
+	if planPath := filepath.Join(absPath, PlanFile); helper.Exists(planPath) {
+		plan, err := readPlan(planPath)
+		if err != nil {
+			return nil, err
+		}
+		return planModules(root, plan, m.options.LicenseMatcher), nil
+	}
+	if freezePath := filepath.Join(absPath, FreezeFile); helper.Exists(freezePath) {
+		packages, err := readFreeze(freezePath)
+		if err != nil {
+			return nil, err
+		}
+		return freezeModules(root, packages), nil
+	}
+	return nil, errDependenciesNotFound
+}
+
+// IsValid checks if a cabal.project, a cabal.project.freeze or a .cabal file exists
+func (m *cabal) IsValid(path string) bool {
+	if helper.Exists(filepath.Join(path, ProjectFile)) || helper.Exists(filepath.Join(path, FreezeFile)) {
+		return true
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.cabal"))
+	return err == nil && len(files) > 0
+}
+
+// HasModulesInstalled checks the project has a build plan or a freeze file
+func (m *cabal) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, PlanFile)) || helper.Exists(filepath.Join(path, FreezeFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// planModules returns the root followed by the packages of the build plan, one for all the units of a
+// package. A package depends on the packages of the libraries its components depend on; the ones of the
+// test suites and benchmarks are DEV_DEPENDENCY_OF it, the ones of its Setup.hs and the executables its
+// components build with are BUILD_DEPENDENCY_OF it. The root is the package of the project directory and
+// depends on the other packages of the project
+func planModules(root *models.Module, plan *buildPlan, matcher helper.LicenseMatcher) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	units := map[string]int{}
+	var local []int
+	for _, unit := range plan.InstallPlan {
+		if i, ok := index[unit.Name]; ok {
+			units[unit.ID] = i
+			continue
+		}
+		if unit.isLocal() && unit.Name == root.Name {
+			index[unit.Name] = 0
+			units[unit.ID] = 0
+			continue
+		}
+
+		i := len(modules)
+		index[unit.Name] = i
+		units[unit.ID] = i
+		modules = append(modules, unitModule(unit, matcher))
+		if unit.isLocal() {
+			local = append(local, i)
+		}
+	}
+
+	for _, unit := range plan.InstallPlan {
+		from := units[unit.ID]
+		for _, component := range unit.components() {
+			relationship := componentRelationship(component.Name)
+			for _, id := range component.Depends {
+				if to, ok := units[id]; ok {
+					linkDependency(modules, from, to, relationship)
+				}
+			}
+			for _, id := range component.ExeDepends {
+				if to, ok := units[id]; ok {
+					linkDependency(modules, from, to, models.RelationshipBuildDependencyOf)
+				}
+			}
+		}
+	}
+
+	for _, i := range local {
+		linkDependency(modules, 0, i, "")
+	}
+	return modules
+}
+
+// componentRelationship returns the relationship of the dependencies of a component, e.g. test:spec
+func componentRelationship(component string) models.RelationshipType {
+	switch {
+	case strings.HasPrefix(component, "test:"), strings.HasPrefix(component, "bench:"):
+		return models.RelationshipDevDependencyOf
+	case component == "setup":
+		return models.RelationshipBuildDependencyOf
+	}
+	return ""
+}
+
+// linkDependency links the module at index to the one at parentIndex, a dependency of several components
+// keeps the relationship of the first one unless a later one depends on it at runtime
+func linkDependency(modules []models.Module, parentIndex int, index int, relationship models.RelationshipType) {
+	if linked, ok := modules[parentIndex].Modules[modules[index].Name]; ok && (linked.Relationship == "" || relationship != "") {
+		return
+	}
+	hackage.LinkModule(modules, parentIndex, index, relationship)
+}
+
+// unitModule returns the package of a unit: a repository one is downloaded from the tarball of its
+// package repository, the checksum being the sha256 hash of the tarball, else of the revision of its
+// .cabal file; a source repository one from its repository at the locked commit and a local one is built
+// from a directory of the project
+func unitModule(unit *planUnit, matcher helper.LicenseMatcher) models.Module {
+	mod := hackage.Module(unit.Name, unit.Version)
+	p := purl.New("hackage", "", unit.Name, unit.Version)
+
+	if unit.Type == unitPreExisting {
+		mod.PackageHomePage = ""
+		mod.PackageDownloadLocation = "NOASSERTION"
+		mod.Annotations = append(mod.Annotations, "the package is installed with the compiler")
+		return mod
+	}
+
+	checksum := unit.SourceSHA256
+	if checksum == "" {
+		checksum = unit.CabalSHA256
+	}
+	if checksum != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: checksum}
+	}
+
+	source := unit.Source
+	if source == nil {
+		return mod
+	}
+	switch source.Type {
+	case sourceRepoTar:
+		if source.Repo == nil || source.Repo.URI == "" {
+			mod.Annotations = append(mod.Annotations, "the package is published in a local package repository")
+			break
+		}
+		if !hackage.IsHackage(source.Repo.URI) {
+			repository := strings.TrimSuffix(source.Repo.URI, "/")
+			p = p.WithQualifier("repository_url", repository)
+			mod.PackageHomePage = ""
+			mod.PackageDownloadLocation = hackage.TarballURL(repository, unit.Name, unit.Version)
+		}
+	case sourceRemoteTarball:
+		p = p.WithQualifier("download_url", source.URI)
+		mod.PackageHomePage = ""
+		mod.PackageDownloadLocation = source.URI
+	case sourceRepo:
+		mod.PackageHomePage = ""
+		mod.PackageDownloadLocation = "NOASSERTION"
+		repo := source.SourceRepo
+		if repo == nil {
+			break
+		}
+		vcs := fmt.Sprintf("%s+%s", repo.Type, repo.Location)
+		if repo.Tag != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, repo.Tag)
+		}
+		p = p.WithQualifier("vcs_url", vcs)
+		mod.PackageDownloadLocation = vcs
+		if gitRevision.MatchString(repo.Tag) {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: repo.Tag}
+		}
+		if repo.Subdir != "" && repo.Subdir != "." {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is in the directory %s of its repository", repo.Subdir))
+		}
+	case sourceLocal, sourceLocalTarball:
+		mod.PackageHomePage = ""
+		mod.PackageDownloadLocation = "NOASSERTION"
+		mod.LocalPath = source.Path
+		if source.Type == sourceLocal {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is built from the directory %s", source.Path))
+			helper.SetLicense(matcher, source.Path, &mod)
+		} else {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is built from the archive %s", source.Path))
+		}
+	}
+	mod.PackageURL = p.String()
+	return mod
+}
+
+// freezeModules returns the root followed by the packages of the freeze file, the root depends on all of
+// them as the freeze file does not tell which package requires which
+func freezeModules(root *models.Module, packages []*frozenPackage) []models.Module {
+	modules := []models.Module{*root}
+	for _, pkg := range packages {
+		if pkg.Name == root.Name {
+			continue
+		}
+		mod := hackage.Module(pkg.Name, pkg.Version)
+		if pkg.Installed {
+			mod.PackageHomePage = ""
+			mod.PackageDownloadLocation = "NOASSERTION"
+			mod.Annotations = append(mod.Annotations, "the package is installed with the compiler")
+		}
+		modules = append(modules, mod)
+		hackage.LinkModule(modules, 0, len(modules)-1, "")
+	}
+	return modules
+}
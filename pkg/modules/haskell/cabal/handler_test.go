@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestListModulesWithDepsFromPlan(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "plan")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 10) {
+		return
+	}
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	root := modules[0]
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "0.2.0", root.Version)
+	relationships := map[string]models.RelationshipType{}
+	for name, mod := range root.Modules {
+		relationships[name] = mod.Relationship
+	}
+	assert.Equal(t, map[string]models.RelationshipType{
+		"Cabal":       models.RelationshipBuildDependencyOf,
+		"base":        "",
+		"aeson":       "",
+		"json-stream": "",
+		"utils":       "",
+		"alex":        models.RelationshipBuildDependencyOf,
+		"hspec":       models.RelationshipDevDependencyOf,
+	}, relationships)
+
+	base := byName["base"]
+	assert.Equal(t, "NOASSERTION", base.PackageDownloadLocation)
+	assert.Equal(t, []string{"the package is installed with the compiler"}, base.Annotations)
+	assert.Contains(t, base.Modules, "ghc-prim")
+
+	aeson := byName["aeson"]
+	assert.Equal(t, "pkg:hackage/aeson@2.1.2.1", aeson.PackageURL)
+	assert.Equal(t, "https://hackage.haskell.org/package/aeson-2.1.2.1/aeson-2.1.2.1.tar.gz", aeson.PackageDownloadLocation)
+	assert.Equal(t, "5e30ff2ecb41ee4ae7a1e4ea42ab1fde3a4d8f2b4a7c1d5e9f3b2a6c8d0e4f17", aeson.CheckSum.Value)
+	assert.Len(t, aeson.Modules, 2)
+
+	// the revision of the .cabal file is the checksum of a package without the hash of its tarball
+	assert.Equal(t, "4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a", byName["alex"].CheckSum.Value)
+
+	hspec := byName["hspec"]
+	assert.Equal(t, "pkg:hackage/hspec@2.11.7?repository_url=https:%2F%2Fhackage.example.com", hspec.PackageURL)
+	assert.Equal(t, "https://hackage.example.com/package/hspec-2.11.7/hspec-2.11.7.tar.gz", hspec.PackageDownloadLocation)
+
+	stream := byName["json-stream"]
+	assert.Equal(t, "git+https://github.com/example/json-stream.git@0123456789abcdef0123456789abcdef01234567", stream.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "0123456789abcdef0123456789abcdef01234567"}, stream.CheckSum)
+	assert.Empty(t, stream.Annotations)
+
+	assert.Equal(t, []string{"the package is built from the directory /home/dev/shop/utils/"}, byName["utils"].Annotations)
+}
+
+func TestListModulesWithDepsFromFreeze(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "freeze"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 6) {
+		return
+	}
+
+	assert.Equal(t, "freeze", modules[0].Name)
+	assert.Len(t, modules[0].Modules, 5)
+	var names, versions []string
+	for _, mod := range modules[1:] {
+		names = append(names, mod.Name)
+		versions = append(versions, mod.Version)
+	}
+	assert.Equal(t, []string{"aeson", "base", "ghc-prim", "Cabal", "text"}, names)
+	assert.Equal(t, []string{"2.1.2.1", "4.17.2.1", "", "3.8.1.0", "2.0.2"}, versions)
+	assert.Equal(t, []string{"the package is installed with the compiler"}, modules[3].Annotations)
+}
+
+func TestReadPlanMalformed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "plan.json")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"install-plan":[{"type":"configured","pkg-name":"aeson"}]}`), 0644))
+	_, err := readPlan(file)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"install-plan":[`), 0644))
+	_, err = readPlan(file)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestHasModulesInstalled(t *testing.T) {
+	assert.Equal(t, errDependenciesNotFound, New().HasModulesInstalled("testdata"))
+	assert.False(t, New().IsValid("testdata"))
+}
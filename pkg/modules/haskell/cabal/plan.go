@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the types of a unit of a build plan, a pre-existing one is installed with the compiler
+const (
+	unitPreExisting = "pre-existing"
+	unitConfigured  = "configured"
+	unitInstalled   = "installed"
+)
+
+// the types of the source of a configured unit
+const (
+	sourceRepoTar       = "repo-tar"
+	sourceRemoteTarball = "remote-tarball"
+	sourceLocal         = "local"
+	sourceLocalTarball  = "local-tarball"
+	sourceRepo          = "source-repo"
+)
+
+// buildPlan is the dist-newstyle/cache/plan.json cabal writes the solved build plan of a project to
+type buildPlan struct {
+	CabalVersion string      `json:"cabal-version"`
+	CompilerID   string      `json:"compiler-id"`
+	InstallPlan  []*planUnit `json:"install-plan"`
+}
+
+// planUnit is a unit of a build plan: a package, or a component of a package when cabal builds its
+// components apart, e.g. lib and exe:app
+type planUnit struct {
+	Type          string                    `json:"type"`
+	ID            string                    `json:"id"`
+	Name          string                    `json:"pkg-name"`
+	Version       string                    `json:"pkg-version"`
+	Style         string                    `json:"style"`
+	Source        *planSource               `json:"pkg-src"`
+	CabalSHA256   string                    `json:"pkg-cabal-sha256"`
+	SourceSHA256  string                    `json:"pkg-src-sha256"`
+	ComponentName string                    `json:"component-name"`
+	Depends       []string                  `json:"depends"`
+	ExeDepends    []string                  `json:"exe-depends"`
+	Components    map[string]*planComponent `json:"components"`
+}
+
+type planComponent struct {
+	Depends    []string `json:"depends"`
+	ExeDepends []string `json:"exe-depends"`
+}
+
+// planSource is the source of a configured unit: the tarball of a package repository, a remote or a
+// local tarball, a local directory or a version control repository
+type planSource struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+	URI  string `json:"uri"`
+	Repo *struct {
+		Type string `json:"type"`
+		URI  string `json:"uri"`
+		Path string `json:"path"`
+	} `json:"repo"`
+	SourceRepo *struct {
+		Type     string `json:"type"`
+		Location string `json:"location"`
+		Tag      string `json:"tag"`
+		Branch   string `json:"branch"`
+		Subdir   string `json:"subdir"`
+	} `json:"source-repo"`
+}
+
+// unitComponent is the dependencies of a component of a unit
+type unitComponent struct {
+	Name       string
+	Depends    []string
+	ExeDepends []string
+}
+
+func readPlan(path string) (*buildPlan, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &buildPlan{}
+	if err := reader.DecodeJSON(path, data, plan); err != nil {
+		return nil, err
+	}
+	for _, unit := range plan.InstallPlan {
+		if unit.ID == "" || unit.Name == "" {
+			return nil, fmt.Errorf("%w %s: a unit of the install-plan has no id or pkg-name", reader.ErrMalformedFile, path)
+		}
+	}
+	return plan, nil
+}
+
+// components returns the components of a unit sorted by name, the unit itself when it is a single component
+func (u *planUnit) components() []unitComponent {
+	if len(u.Components) == 0 {
+		return []unitComponent{{Name: u.ComponentName, Depends: u.Depends, ExeDepends: u.ExeDepends}}
+	}
+
+	names := make([]string, 0, len(u.Components))
+	for name := range u.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	components := make([]unitComponent, 0, len(names))
+	for _, name := range names {
+		component := u.Components[name]
+		components = append(components, unitComponent{Name: name, Depends: component.Depends, ExeDepends: component.ExeDepends})
+	}
+	return components
+}
+
+// isLocal tells whether the unit is a package of the project
+func (u *planUnit) isLocal() bool {
+	return u.Style == "local" || (u.Source != nil && u.Source.Type == sourceLocal && u.Style != "global")
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hackage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// URL is the central package repository of Haskell
+const URL = "https://hackage.haskell.org"
+
+// HpackFile is the package description hpack generates the .cabal file of a package from
+const HpackFile = "package.yaml"
+
+// identifierVersion is the version suffix of a package identifier, e.g. -2.1.2.1 of aeson-2.1.2.1
+var identifierVersion = regexp.MustCompile(`-([0-9]+(?:\.[0-9]+)*)$`)
+
+// descriptionField is a top level field of a .cabal or a package.yaml file, e.g. name: aeson
+var descriptionField = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*)\s*:\s*(.*)$`)
+
+// Package is the part of the description of a package the root module is named with
+type Package struct {
+	Name    string
+	Version string
+	License string
+}
+
+// SplitIdentifier splits a package identifier into its name and its version, e.g. aeson-2.1.2.1,
+// ok is false when it has no version
+func SplitIdentifier(identifier string) (name string, version string, ok bool) {
+	match := identifierVersion.FindStringSubmatchIndex(identifier)
+	if match == nil || match[0] == 0 {
+		return identifier, "", false
+	}
+	return identifier[:match[0]], identifier[match[2]:match[3]], true
+}
+
+// Module returns a package of hackage, downloaded from the tarball hackage serves it with
+func Module(name, version string) models.Module {
+	return models.Module{
+		Name:                    name,
+		Version:                 version,
+		PackageURL:              purl.New("hackage", "", name, version).String(),
+		Supplier:                models.SupplierContact{Name: name},
+		PackageHomePage:         fmt.Sprintf("%s/package/%s", URL, name),
+		PackageDownloadLocation: TarballURL(URL, name, version),
+		Modules:                 map[string]*models.Module{},
+	}
+}
+
+// TarballURL returns the tarball of a package in a repository laid out as hackage
+func TarballURL(repository, name, version string) string {
+	return fmt.Sprintf("%s/package/%s-%s/%s-%s.tar.gz", strings.TrimSuffix(repository, "/"), name, version, name, version)
+}
+
+// IsHackage tells whether a repository url is the one of hackage, whatever its scheme
+func IsHackage(repository string) bool {
+	repository = strings.TrimSuffix(repository, "/")
+	return strings.TrimPrefix(strings.TrimPrefix(repository, "https://"), "http://") == strings.TrimPrefix(URL, "https://")
+}
+
+// ReadPackage reads the name, the version and the license of the package of a directory from its
+// .cabal file, else from its package.yaml
+func ReadPackage(dir string) (*Package, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.cabal"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	files = append(files, filepath.Join(dir, HpackFile))
+
+	for _, file := range files {
+		pkg, err := readDescription(file)
+		if err == nil && pkg.Name != "" {
+			return pkg, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+// readDescription reads the unindented name, version and license fields of a package description,
+// the field names of a .cabal file are case insensitive
+func readDescription(path string) (*Package, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pkg := &Package{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := descriptionField.FindStringSubmatch(strings.TrimRight(scanner.Text(), " \r"))
+		if match == nil {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(match[2]), `"'`)
+		switch strings.ToLower(match[1]) {
+		case "name":
+			pkg.Name = value
+		case "version":
+			pkg.Version = value
+		case "license":
+			pkg.License = value
+		}
+	}
+	return pkg, scanner.Err()
+}
+
+// RootModule returns the package of the project, named after its directory when it has no package
+// description, with the license of the project files else the one of its description
+func RootModule(path string, matcher helper.LicenseMatcher) *models.Module {
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+	pkg, err := ReadPackage(path)
+	if err == nil {
+		mod.Name = pkg.Name
+		mod.Version = pkg.Version
+		mod.PackageURL = purl.New("hackage", "", pkg.Name, pkg.Version).String()
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicense(matcher, path, mod)
+	if mod.LicenseDeclared == "" && pkg != nil {
+		if expression := helper.SPDXExpression(pkg.License); expression != "" {
+			mod.LicenseDeclared = expression
+			mod.LicenseConcluded = expression
+		}
+	}
+	return mod
+}
+
+// LinkModule adds a copy of the module at index to the modules of the one at parentIndex
+func LinkModule(modules []models.Module, parentIndex int, index int, relationship models.RelationshipType) {
+	if parentIndex == index {
+		return
+	}
+
+	linked := modules[index]
+	linked.Relationship = relationship
+	modules[parentIndex].Modules[linked.Name] = &linked
+}
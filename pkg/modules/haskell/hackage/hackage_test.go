@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hackage
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitIdentifier(t *testing.T) {
+	name, version, ok := SplitIdentifier("aeson-2.1.2.1")
+	assert.True(t, ok)
+	assert.Equal(t, "aeson", name)
+	assert.Equal(t, "2.1.2.1", version)
+
+	name, version, ok = SplitIdentifier("http-client-tls-0.3.6")
+	assert.True(t, ok)
+	assert.Equal(t, "http-client-tls", name)
+	assert.Equal(t, "0.3.6", version)
+
+	_, _, ok = SplitIdentifier("http-client")
+	assert.False(t, ok)
+	_, _, ok = SplitIdentifier("-1.0")
+	assert.False(t, ok)
+}
+
+func TestModule(t *testing.T) {
+	mod := Module("aeson", "2.1.2.1")
+	assert.Equal(t, "pkg:hackage/aeson@2.1.2.1", mod.PackageURL)
+	assert.Equal(t, "https://hackage.haskell.org/package/aeson-2.1.2.1/aeson-2.1.2.1.tar.gz", mod.PackageDownloadLocation)
+	assert.Equal(t, "https://hackage.haskell.org/package/aeson", mod.PackageHomePage)
+
+	assert.True(t, IsHackage("http://hackage.haskell.org/"))
+	assert.False(t, IsHackage("https://hackage.example.com/"))
+}
+
+func TestReadPackage(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ReadPackage(dir)
+	assert.Error(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, HpackFile), []byte("name: from-hpack\nversion: 1.0.0\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "shop.cabal"), []byte(`cabal-version: 3.0
+Name:    shop
+version: 0.2.0
+license: MIT
+
+library
+  build-depends: base
+  -- name: not-a-field
+`), 0644))
+
+	pkg, err := ReadPackage(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &Package{Name: "shop", Version: "0.2.0", License: "MIT"}, pkg)
+
+	root := RootModule(dir, nil)
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "pkg:hackage/shop@0.2.0", root.PackageURL)
+	assert.Equal(t, "MIT", root.LicenseDeclared)
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package haskell
+
+import (
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/haskell/cabal"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/haskell/stack"
+)
+
+type haskell struct {
+	plugin  models.IPlugin
+	options models.PluginOptions
+}
+
+// New creates a new haskell instance, delegating to stack or cabal
+func New() *haskell {
+	return &haskell{}
+}
+
+// GetMetadata returns the metadata of the plugin the project is built with
+func (m *haskell) GetMetadata() models.PluginMetadata {
+	return m.plugin.GetMetadata()
+}
+
+// Plugins returns the haskell plugins with the options of the group, in the order they are tried: a stack project usually has .cabal
+// files too so stack is tried first
+func (m *haskell) Plugins() []models.IPlugin {
+	plugins := []models.IPlugin{stack.New(), cabal.New()}
+	for _, p := range plugins {
+		if configurable, ok := p.(models.IConfigurablePlugin); ok {
+			configurable.SetOptions(m.options)
+		}
+	}
+	return plugins
+}
+
+// IsValid checks if one of the haskell plugins is valid for the path, it is the one the others delegate to
+func (m *haskell) IsValid(path string) bool {
+	for _, p := range m.Plugins() {
+		if p.IsValid(path) {
+			m.plugin = p
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasModulesInstalled ...
+func (m *haskell) HasModulesInstalled(path string) error {
+	return m.plugin.HasModulesInstalled(path)
+}
+
+// SetOptions keeps the options of the generation for the plugins of the group
+func (m *haskell) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion ...
+func (m *haskell) GetVersion() (string, error) {
+	return m.plugin.GetVersion()
+}
+
+// SetRootModule ...
+func (m *haskell) SetRootModule(path string) error {
+	return m.plugin.SetRootModule(path)
+}
+
+// GetRootModule ...
+func (m *haskell) GetRootModule(path string) (*models.Module, error) {
+	return m.plugin.GetRootModule(path)
+}
+
+// ListUsedModules ...
+func (m *haskell) ListUsedModules(path string) ([]models.Module, error) {
+	return m.plugin.ListUsedModules(path)
+}
+
+// ListModulesWithDeps ...
+func (m *haskell) ListModulesWithDeps(path string) ([]models.Module, error) {
+	return m.plugin.ListModulesWithDeps(path)
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package stack
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no stack.yaml.lock found. Please build the project before running spdx-sbom-generator, e.g.: `stack build`")
+)
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package stack
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/haskell/hackage"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type stack struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ManifestFile string = "stack.yaml"
+	LockFile     string = "stack.yaml.lock"
+)
+
+// gitRevision is a sha1 commit hash
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// New creates a new stack instance
+func New() *stack {
+	return &stack{
+		metadata: models.PluginMetadata{
+			Name:       "Haskell Stack",
+			Slug:       "stack",
+			Manifest:   []string{ManifestFile, LockFile},
+			ModulePath: []string{".stack-work"},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *stack) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the stack version
+func (m *stack) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "stack", "--numeric-version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *stack) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *stack) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the project, named after its directory when it has no package description
+func (m *stack) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return hackage.RootModule(absPath, m.options.LicenseMatcher), nil
+}
+
+// ListUsedModules returns the packages of the project, without the project itself
+func (m *stack) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the extra-deps of its stack.yaml.lock
+func (m *stack) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(absPath, LockFile)
+	if !helper.Exists(lockPath) {
+		return nil, errDependenciesNotFound
+	}
+	lock, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	return lockModules(hackage.RootModule(absPath, m.options.LicenseMatcher), lock), nil
+}
+
+// IsValid checks if a stack.yaml or a stack.yaml.lock exists
+func (m *stack) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ManifestFile)) || helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks the extra-deps are locked
+func (m *stack) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// lockModules returns the root followed by the packages of the lockfile, the root depends on all of them as
+// the lockfile does not tell which package requires which. The lockfile only locks the extra-deps, the
+// packages of the snapshots are not listed so the root is annotated with them
+func lockModules(root *models.Module, lock *stackLock) []models.Module {
+	for _, snapshot := range lock.Snapshots {
+		root.Unresolved = append(root.Unresolved, fmt.Sprintf("the packages of the snapshot %s are not listed", snapshot))
+	}
+
+	modules := []models.Module{*root}
+	for _, pkg := range lock.Packages {
+		modules = append(modules, packageModule(pkg))
+		hackage.LinkModule(modules, 0, len(modules)-1, "")
+	}
+	return modules
+}
+
+// packageModule returns the package of a locked package: a hackage one is downloaded from hackage, the
+// checksum being the sha256 hash of the revision of its .cabal file, a git one from its repository at the
+// locked commit and an archive one from its url with the sha256 hash of the archive
+func packageModule(pkg *lockedPackage) models.Module {
+	mod := hackage.Module(pkg.Name, pkg.Version)
+	p := purl.New("hackage", "", pkg.Name, pkg.Version)
+
+	switch pkg.Source {
+	case sourceHackage:
+		if pkg.CabalSHA256 != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: pkg.CabalSHA256}
+		}
+		if pkg.Revision != "" {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is pinned to the revision %s of its .cabal file", pkg.Revision))
+		}
+	case sourceGit:
+		vcs := fmt.Sprintf("git+%s", pkg.URL)
+		if pkg.Commit != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, pkg.Commit)
+		}
+		p = p.WithQualifier("vcs_url", vcs)
+		mod.PackageHomePage = ""
+		mod.PackageDownloadLocation = vcs
+		if gitRevision.MatchString(pkg.Commit) {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: pkg.Commit}
+		}
+	case sourceArchive:
+		p = p.WithQualifier("download_url", pkg.URL)
+		mod.PackageHomePage = ""
+		mod.PackageDownloadLocation = pkg.URL
+		if pkg.SHA256 != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: pkg.SHA256}
+		}
+	}
+	if pkg.Subdir != "" && pkg.Subdir != "." {
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is in the directory %s of its repository", pkg.Subdir))
+	}
+	mod.PackageURL = p.String()
+	return mod
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package stack
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestParseLockfile(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "app", LockFile))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lts-21.25"}, lock.Snapshots)
+	if !assert.Len(t, lock.Packages, 4) {
+		return
+	}
+
+	assert.Equal(t, &lockedPackage{
+		Name:        "acme-missiles",
+		Version:     "0.3",
+		Source:      sourceHackage,
+		CabalSHA256: "2ba66a092a32593880a87fb00f3213762d7bca65a687d45965778deb8694c5d1",
+	}, lock.Packages[0])
+	assert.Equal(t, "1", lock.Packages[1].Revision)
+	assert.Equal(t, &lockedPackage{
+		Name:    "json-stream",
+		Version: "0.4.5.3",
+		Source:  sourceGit,
+		URL:     "https://github.com/example/json-stream.git",
+		Commit:  "0123456789abcdef0123456789abcdef01234567",
+	}, lock.Packages[2])
+	assert.Equal(t, sourceArchive, lock.Packages[3].Source)
+	assert.Equal(t, "wai-extra", lock.Packages[3].Subdir)
+}
+
+func TestParseLockfileMalformed(t *testing.T) {
+	_, err := parseLockfile(LockFile, strings.NewReader("packages:\n- completed:\n    hackage: acme-missiles\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+
+	_, err = parseLockfile(LockFile, strings.NewReader("packages:\n- original:\n    hackage: acme-missiles-0.3\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "app")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, "0.1.0.0", root.Version)
+	assert.Equal(t, "BSD-3-Clause", root.LicenseDeclared)
+	assert.Equal(t, []string{"the packages of the snapshot lts-21.25 are not listed"}, root.Unresolved)
+	assert.Len(t, root.Modules, 4)
+
+	missiles := modules[1]
+	assert.Equal(t, "pkg:hackage/acme-missiles@0.3", missiles.PackageURL)
+	assert.Equal(t, "https://hackage.haskell.org/package/acme-missiles-0.3/acme-missiles-0.3.tar.gz", missiles.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "2ba66a092a32593880a87fb00f3213762d7bca65a687d45965778deb8694c5d1"}, missiles.CheckSum)
+
+	assert.Nil(t, modules[2].CheckSum)
+	assert.Equal(t, []string{"the package is pinned to the revision 1 of its .cabal file"}, modules[2].Annotations)
+
+	stream := modules[3]
+	assert.Equal(t, "git+https://github.com/example/json-stream.git@0123456789abcdef0123456789abcdef01234567", stream.PackageDownloadLocation)
+	assert.Equal(t, "pkg:hackage/json-stream@0.4.5.3?vcs_url=git%2Bhttps:%2F%2Fgithub.com%2Fexample%2Fjson-stream.git%400123456789abcdef0123456789abcdef01234567", stream.PackageURL)
+	assert.Equal(t, models.HashAlgoSHA1, stream.CheckSum.Algorithm)
+
+	wai := modules[4]
+	assert.Equal(t, "https://github.com/yesodweb/wai/archive/3b7f4fd5a4e5d2d6c2b7a7e0c1e4b0f6b2b0d6f1.tar.gz", wai.PackageDownloadLocation)
+	assert.Equal(t, "5b1a6e2c4d9f8e7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a", wai.CheckSum.Value)
+	assert.Equal(t, []string{"the package is in the directory wai-extra of its repository"}, wai.Annotations)
+}
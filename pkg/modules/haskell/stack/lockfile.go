@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package stack
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/haskell/hackage"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the sources of a locked package
+const (
+	sourceHackage = "hackage"
+	sourceGit     = "git"
+	sourceArchive = "archive"
+)
+
+// lockedPackage is a completed extra-dep of a stack.yaml.lock
+type lockedPackage struct {
+	Name    string
+	Version string
+	Source  string
+	// CabalSHA256 is the sha256 hash of the revision of the .cabal file of a hackage package, Revision
+	// the number of the revision it is pinned to when it has no hash
+	CabalSHA256 string
+	Revision    string
+	// URL is the repository of a git package and the url of an archive one, Commit the commit of a git package
+	URL    string
+	Commit string
+	// SHA256 is the hash of the archive of an archive package
+	SHA256 string
+	// Subdir is the directory of the package in its repository or its archive
+	Subdir string
+}
+
+// stackLock is a stack.yaml.lock
+type stackLock struct {
+	Packages []*lockedPackage
+	// Snapshots are the snapshots the other packages are resolved from, e.g. lts-21.25
+	Snapshots []string
+}
+
+func readLockfile(path string) (*stackLock, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseLockfile(path, file)
+}
+
+// parseLockfile reads a stack.yaml.lock, the completed location of a package is the one it is locked to:
+//
+//	packages:
+//	- completed:
+//	    hackage: acme-missiles-0.3@sha256:2ba66a09...,613
+//	    pantry-tree:
+//	      sha256: 614bc0cc...
+//	      size: 226
+//	  original:
+//	    hackage: acme-missiles-0.3
+//	snapshots:
+//	- completed:
+//	    sha256: e5cac927...
+//	    size: 650044
+//	    url: https://raw.githubusercontent.com/commercialhaskell/stackage-snapshots/master/lts/21/25.yaml
+//	  original: lts-21.25
+func parseLockfile(fileName string, r io.Reader) (*stackLock, error) {
+	document, err := reader.ParseYAML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &stackLock{}
+	for _, entry := range document.List("packages") {
+		completed := entry.Get("completed")
+		if completed == nil || completed.Fields == nil {
+			return nil, reader.MalformedError(fileName, entry.Line, "package has no completed location")
+		}
+
+		pkg := &lockedPackage{
+			Name:    completed.Str("name"),
+			Version: completed.Str("version"),
+			Subdir:  completed.Str("subdir"),
+		}
+		switch {
+		case completed.Str("hackage") != "":
+			pkg.Source = sourceHackage
+			if err := pkg.setHackage(completed.Str("hackage")); err != nil {
+				return nil, reader.MalformedError(fileName, completed.Line, err.Error())
+			}
+		case completed.Str("git") != "" || completed.Str("github") != "":
+			pkg.Source = sourceGit
+			pkg.URL = completed.Str("git")
+			if github := completed.Str("github"); github != "" {
+				pkg.URL = fmt.Sprintf("https://github.com/%s", github)
+			}
+			pkg.Commit = completed.Str("commit")
+		case completed.Str("url") != "":
+			pkg.Source = sourceArchive
+			pkg.URL = completed.Str("url")
+			pkg.SHA256 = completed.Str("sha256")
+		default:
+			return nil, reader.MalformedError(fileName, completed.Line, "unknown package location")
+		}
+		if pkg.Name == "" {
+			return nil, reader.MalformedError(fileName, completed.Line, "package has no name")
+		}
+		lock.Packages = append(lock.Packages, pkg)
+	}
+
+	for _, entry := range document.List("snapshots") {
+		snapshot := entry.Str("original")
+		if snapshot == "" {
+			snapshot = entry.Get("original").Str("url")
+		}
+		if snapshot == "" {
+			snapshot = entry.Get("completed").Str("url")
+		}
+		if snapshot != "" {
+			lock.Snapshots = append(lock.Snapshots, snapshot)
+		}
+	}
+	return lock, nil
+}
+
+// setHackage sets the package of a hackage location, e.g. aeson-2.1.2.1@sha256:<hash>,<size> or
+// aeson-2.1.2.1@rev:2
+func (p *lockedPackage) setHackage(location string) error {
+	identifier, revision := location, ""
+	if i := strings.Index(location, "@"); i >= 0 {
+		identifier, revision = location[:i], location[i+1:]
+	}
+
+	name, version, ok := hackage.SplitIdentifier(identifier)
+	if !ok {
+		return fmt.Errorf("hackage package %s has no version", identifier)
+	}
+	p.Name, p.Version = name, version
+
+	switch {
+	case strings.HasPrefix(revision, "sha256:"):
+		hash := strings.TrimPrefix(revision, "sha256:")
+		if i := strings.Index(hash, ","); i >= 0 {
+			hash = hash[:i]
+		}
+		p.CabalSHA256 = hash
+	case strings.HasPrefix(revision, "rev:"):
+		p.Revision = strings.TrimPrefix(revision, "rev:")
+	}
+	return nil
+}
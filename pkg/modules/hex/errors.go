@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no mix.lock found. Please get the dependencies before running spdx-sbom-generator, e.g.: `mix deps.get`")
+	errUnexpectedStatus     = errors.New("unexpected response status")
+)
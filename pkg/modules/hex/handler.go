@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type hex struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ManifestFile string = "mix.exs"
+	LockFile     string = "mix.lock"
+	DepsDir      string = "deps"
+)
+
+// hexRepoURL is the repository the tarballs of hex.pm are downloaded from
+const hexRepoURL = "https://repo.hex.pm"
+
+// gitRevision is a sha1 commit hash
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// New creates a new hex instance
+func New() *hex {
+	return &hex{
+		metadata: models.PluginMetadata{
+			Name:       "Hex Package Manager",
+			Slug:       "hex",
+			Manifest:   []string{ManifestFile, LockFile},
+			ModulePath: []string{DepsDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *hex) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the mix version
+func (m *hex) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "mix", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *hex) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *hex) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the application of the mix.exs, named after its directory when it has none
+func (m *hex) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return m.rootModule(absPath, readProject(absPath)), nil
+}
+
+// ListUsedModules returns the dependencies of the project, without the project itself
+func (m *hex) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the dependencies of its mix.lock, the hex ones
+// are linked to the ones they depend on
+func (m *hex) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(absPath, LockFile)
+	if !helper.Exists(lockPath) {
+		return nil, errDependenciesNotFound
+	}
+	entries, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	project := readProject(absPath)
+	metadata := &hexMetadata{depsPath: filepath.Join(absPath, DepsDir), ctx: m.context(), cache: m.options.Cache,
+		matcher: m.options.LicenseMatcher}
+	if m.options.AllowNetwork && !m.options.Offline {
+		metadata.fetcher = newHexFetcher()
+	}
+	return lockModules(m.rootModule(absPath, project), project, entries, metadata), nil
+}
+
+// IsValid checks if a mix.exs or a mix.lock exists
+func (m *hex) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ManifestFile)) || helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks the dependencies are locked, the deps directory is only read for the licenses
+func (m *hex) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+func (m *hex) context() context.Context {
+	if m.options.Context == nil {
+		return context.Background()
+	}
+	return m.options.Context
+}
+
+// readProject reads the mix.exs of the project, nil when it has none
+func readProject(path string) *mixProject {
+	project, err := readMixProject(filepath.Join(path, ManifestFile))
+	if err != nil {
+		return nil
+	}
+	return project
+}
+
+// rootModule returns the package of the project, with the license of the project files
+func (m *hex) rootModule(path string, project *mixProject) *models.Module {
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+	if project != nil && project.App != "" {
+		mod.Name = project.App
+		mod.Version = project.Version
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicense(m.options.LicenseMatcher, path, mod)
+	return mod
+}
+
+// lockModules returns the root followed by the dependencies of the lockfile. The root depends on the
+// dependencies of its mix.exs, the ones of the dev and test environments only being DEV_DEPENDENCY_OF it,
+// or else on the ones no dependency depends on
+func lockModules(root *models.Module, project *mixProject, entries []*lockEntry, metadata *hexMetadata) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	for _, entry := range entries {
+		mod := entryModule(entry)
+		metadata.enrich(&mod, entry)
+		index[entry.Name] = len(modules)
+		modules = append(modules, mod)
+	}
+
+	dependencies := map[int]bool{}
+	for _, entry := range entries {
+		for _, name := range entry.Dependencies {
+			if i, ok := index[name]; ok {
+				helper.LinkModule(modules, index[entry.Name], i, "")
+				dependencies[i] = true
+			}
+		}
+	}
+
+	direct := false
+	if project != nil {
+		for _, dependency := range project.Dependencies {
+			i, ok := index[dependency.Name]
+			if !ok {
+				continue
+			}
+			direct = true
+			relationship := models.RelationshipType("")
+			if dependency.Dev {
+				relationship = models.RelationshipDevDependencyOf
+			}
+			helper.LinkModule(modules, 0, i, relationship)
+		}
+	}
+	if !direct {
+		for i := 1; i < len(modules); i++ {
+			if !dependencies[i] {
+				helper.LinkModule(modules, 0, i, "")
+			}
+		}
+	}
+	return modules
+}
+
+// entryModule returns the package of a locked dependency: a hex one is downloaded from its repository,
+// the sha256 hash of its tarball being its checksum, and a git one from its repository at the locked commit
+func entryModule(entry *lockEntry) models.Module {
+	mod := models.Module{
+		Name:                    entry.Name,
+		Version:                 entry.Version,
+		Supplier:                models.SupplierContact{Name: entry.Name},
+		PackageDownloadLocation: "NOASSERTION",
+		Modules:                 map[string]*models.Module{},
+	}
+
+	switch entry.Source {
+	case sourceHex:
+		p := purl.New("hex", "", entry.Package, entry.Version)
+		switch {
+		case entry.Repo == hexpmRepo:
+			mod.PackageDownloadLocation = fmt.Sprintf("%s/tarballs/%s-%s.tar", hexRepoURL, entry.Package, entry.Version)
+			mod.PackageHomePage = fmt.Sprintf("https://hex.pm/packages/%s", entry.Package)
+		case strings.HasPrefix(entry.Repo, hexpmRepo+":"):
+			// the packages of an organization are namespaced by it
+			organization := strings.TrimPrefix(entry.Repo, hexpmRepo+":")
+			p = purl.New("hex", organization, entry.Package, entry.Version)
+			mod.PackageDownloadLocation = fmt.Sprintf("%s/repos/%s/tarballs/%s-%s.tar", hexRepoURL, organization, entry.Package, entry.Version)
+		default:
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is published in the hex repository %s", entry.Repo))
+		}
+		mod.PackageURL = p.String()
+		if entry.OuterChecksum != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: strings.ToLower(entry.OuterChecksum)}
+		}
+	case sourceGit:
+		mod.Version = entry.Revision
+		vcs := fmt.Sprintf("git+%s", entry.URL)
+		if entry.Revision != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, entry.Revision)
+		}
+		mod.PackageURL = purl.New("generic", "", entry.Name, entry.Revision).WithQualifier("vcs_url", vcs).String()
+		mod.PackageDownloadLocation = vcs
+		if gitRevision.MatchString(entry.Revision) {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: entry.Revision}
+		}
+		if entry.Ref != "" {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the dependency is required at %s", entry.Ref))
+		}
+	}
+	return mod
+}
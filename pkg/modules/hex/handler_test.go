@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "app")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "app"))
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 10) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "0.3.1", root.Version)
+	assert.Equal(t, []string{"acme_billing", "credo", "ecto_sql", "jason", "plug_cowboy"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["credo"].Relationship)
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	cowboy := byName["cowboy"]
+	assert.Equal(t, "pkg:hex/cowboy@2.10.0", cowboy.PackageURL)
+	assert.Equal(t, "https://repo.hex.pm/tarballs/cowboy-2.10.0.tar", cowboy.PackageDownloadLocation)
+	assert.Equal(t, models.HashAlgoSHA256, cowboy.CheckSum.Algorithm)
+	assert.Equal(t, "3afdccb7183cc6f143cb14d3cf51fa00e53db9ec80cdcd525482f5e99bc41d6b", cowboy.CheckSum.Value)
+	assert.Equal(t, []string{"cowlib"}, modulestest.LinkedNames(cowboy))
+	assert.Equal(t, []string{"cowboy", "plug"}, modulestest.LinkedNames(byName["plug_cowboy"]))
+
+	plug := byName["plug"]
+	assert.Equal(t, "Apache-2.0", plug.LicenseDeclared)
+	assert.Equal(t, filepath.Join("deps", "plug"), plug.LocalPath[len(plug.LocalPath)-len(filepath.Join("deps", "plug")):])
+	assert.Equal(t, "MIT", byName["bunt"].LicenseDeclared)
+
+	billing := byName["acme_billing"]
+	assert.Equal(t, "pkg:hex/acme/acme_billing@1.2.0", billing.PackageURL)
+	assert.Equal(t, "https://repo.hex.pm/repos/acme/tarballs/acme_billing-1.2.0.tar", billing.PackageDownloadLocation)
+
+	ecto := byName["ecto_sql"]
+	assert.Equal(t, "2f9c2d1a6d5e0b3c7a8f4e1d9c6b5a4f3e2d1c0b", ecto.Version)
+	assert.Equal(t, "git+https://github.com/elixir-ecto/ecto_sql.git@2f9c2d1a6d5e0b3c7a8f4e1d9c6b5a4f3e2d1c0b", ecto.PackageDownloadLocation)
+	assert.Equal(t, models.HashAlgoSHA1, ecto.CheckSum.Algorithm)
+	assert.Equal(t, []string{"the dependency is required at master"}, ecto.Annotations)
+}
+
+func TestEnrichFromHex(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/packages/cowlib" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name": "cowlib", "html_url": "https://hex.pm/packages/cowlib",
+			"meta": {"licenses": ["ISC"], "links": {"GitHub": "https://github.com/ninenines/cowlib"}}}`))
+	}))
+	defer ts.Close()
+
+	fetcher := newHexFetcher()
+	fetcher.baseURL = ts.URL
+	metadata := &hexMetadata{depsPath: t.TempDir(), fetcher: fetcher, ctx: context.Background()}
+
+	entry := &lockEntry{Name: "cowlib", Source: sourceHex, Package: "cowlib", Version: "2.12.1", Repo: hexpmRepo}
+	mod := entryModule(entry)
+	metadata.enrich(&mod, entry)
+	assert.Equal(t, "ISC", mod.LicenseDeclared)
+	assert.Equal(t, "https://hex.pm/packages/cowlib", mod.PackageHomePage)
+
+	missing := &lockEntry{Name: "missing", Source: sourceHex, Package: "missing", Version: "1.0.0", Repo: hexpmRepo}
+	mod = entryModule(missing)
+	metadata.enrich(&mod, missing)
+	assert.Empty(t, mod.LicenseDeclared)
+
+	private := &lockEntry{Name: "cowlib", Source: sourceHex, Package: "cowlib", Version: "2.12.1", Repo: "hexpm:acme"}
+	mod = entryModule(private)
+	metadata.enrich(&mod, private)
+	assert.Empty(t, mod.LicenseDeclared)
+}
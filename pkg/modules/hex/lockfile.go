@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the sources of a locked dependency, mix.lock does not lock the path ones
+const (
+	sourceHex = "hex"
+	sourceGit = "git"
+)
+
+// hexpmRepo is the public repository of hex.pm, an organization one is named hexpm:<organization>
+const hexpmRepo = "hexpm"
+
+// lockEntry is a dependency of a mix.lock, keyed by its application name
+type lockEntry struct {
+	Name   string
+	Source string
+	// Package is the name of the hex package, it may differ from the one of the application
+	Package string
+	Version string
+	// InnerChecksum is the sha256 hash of the contents of a hex package, OuterChecksum the one of its tarball
+	InnerChecksum string
+	OuterChecksum string
+	Repo          string
+	// Dependencies are the names of the applications it depends on
+	Dependencies []string
+	// URL is the repository of a git dependency and Revision its locked commit
+	URL      string
+	Revision string
+	// Ref is the branch, the tag or the ref a git dependency is required at
+	Ref string
+}
+
+// readLockfile reads the dependencies of a mix.lock, sorted by name. A hex one is locked as
+// {:hex, :name, "version", "inner checksum", [build tools], [dependencies], "repo", "outer checksum"},
+// the lockfiles of mix versions before 1.10 miss the outer checksum and the older ones the repo
+func readLockfile(path string) ([]*lockEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	document, err := parseTerm(path, string(data))
+	if err != nil {
+		return nil, err
+	}
+	if document.Kind != termMap {
+		return nil, reader.MalformedError(path, document.line, "the lockfile is not a map")
+	}
+
+	var entries []*lockEntry
+	for _, pair := range document.Items {
+		if pair.Kind != termPair || pair.Items[1].Kind != termTuple {
+			return nil, reader.MalformedError(path, pair.line, "unexpected entry")
+		}
+		name, lock := pair.Items[0].str(), pair.Items[1]
+
+		entry := &lockEntry{Name: name, Source: lock.item(0).str()}
+		switch entry.Source {
+		case sourceHex:
+			entry.Package = lock.item(1).str()
+			entry.Version = lock.item(2).str()
+			entry.InnerChecksum = lock.item(3).str()
+			entry.Repo = lock.item(6).str()
+			entry.OuterChecksum = lock.item(7).str()
+			if entry.Package == "" || entry.Version == "" {
+				return nil, reader.MalformedError(path, lock.line, fmt.Sprintf("dependency %s has no package or version", name))
+			}
+			if entry.Repo == "" {
+				entry.Repo = hexpmRepo
+			}
+			if dependencies := lock.item(5); dependencies != nil {
+				for _, dependency := range dependencies.Items {
+					entry.Dependencies = append(entry.Dependencies, dependency.item(0).str())
+				}
+			}
+		case sourceGit:
+			entry.URL = lock.item(1).str()
+			entry.Revision = lock.item(2).str()
+			options := lock.item(3)
+			for _, key := range []string{"tag", "branch", "ref"} {
+				if ref := options.get(key).str(); ref != "" {
+					entry.Ref = ref
+					break
+				}
+			}
+			if entry.URL == "" {
+				return nil, reader.MalformedError(path, lock.line, fmt.Sprintf("dependency %s has no repository", name))
+			}
+		default:
+			return nil, reader.MalformedError(path, lock.line, fmt.Sprintf("unknown source %q of %s", entry.Source, name))
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadLockfile(t *testing.T) {
+	entries, err := readLockfile(filepath.Join("testdata", "app", LockFile))
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 9) {
+		return
+	}
+
+	billing := entries[0]
+	assert.Equal(t, "acme_billing", billing.Name)
+	assert.Equal(t, sourceHex, billing.Source)
+	assert.Equal(t, "1.2.0", billing.Version)
+	assert.Equal(t, "hexpm:acme", billing.Repo)
+	assert.Equal(t, "9a8b7c6d5e4f30211203948576a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8", billing.OuterChecksum)
+	assert.Equal(t, []string{"jason"}, billing.Dependencies)
+
+	plugCowboy := entries[8]
+	assert.Equal(t, "plug_cowboy", plugCowboy.Name)
+	assert.Equal(t, []string{"cowboy", "plug"}, plugCowboy.Dependencies)
+	assert.Equal(t, "de36e1a21f451a18b790f37765db198075c25875c64834bcc82d90b309eb6613", plugCowboy.OuterChecksum)
+
+	ecto := entries[5]
+	assert.Equal(t, sourceGit, ecto.Source)
+	assert.Equal(t, "https://github.com/elixir-ecto/ecto_sql.git", ecto.URL)
+	assert.Equal(t, "2f9c2d1a6d5e0b3c7a8f4e1d9c6b5a4f3e2d1c0b", ecto.Revision)
+	assert.Equal(t, "master", ecto.Ref)
+}
+
+func TestReadLockfileWithoutOuterChecksum(t *testing.T) {
+	file := filepath.Join(t.TempDir(), LockFile)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`%{"poison": {:hex, :poison, "3.1.0", "d9eb636610e096f86f25d9a46f35a9facac35609a7591b3be3326e99a0484665", [:mix], [], "hexpm"},
+  "mime" => {:hex, :mime, "1.6.0", "dabde576a497cef4bbdd60aceee8160e02a6c89250d6c0b29e56c0dfb00db3d2", [:mix], []}}`), 0644))
+
+	entries, err := readLockfile(file)
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 2) {
+		return
+	}
+	assert.Equal(t, "mime", entries[0].Name)
+	assert.Equal(t, hexpmRepo, entries[0].Repo)
+	assert.Empty(t, entries[1].OuterChecksum)
+}
+
+func TestReadLockfileMalformed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), LockFile)
+	assert.NoError(t, ioutil.WriteFile(file, []byte("%{\n  \"plug\": {:hex, :plug, \"1.15.2\",\n"), 0644))
+
+	_, err := readLockfile(file)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestParseTerm(t *testing.T) {
+	value, err := parseTerm("test.exs", `[app: :shop, deps: [{:jason, "~> 1.4", [only: [:dev]]}], "key": "a \"b\"", 1 => true] # comment`)
+	assert.NoError(t, err)
+	assert.Equal(t, termList, value.Kind)
+	assert.Equal(t, "shop", value.get("app").str())
+	assert.Equal(t, "jason", value.get("deps").item(0).item(0).str())
+	assert.Equal(t, "dev", value.get("deps").item(0).item(2).get("only").item(0).str())
+	assert.Equal(t, `a "b"`, value.get("key").str())
+	assert.Equal(t, "true", value.get("1").str())
+	assert.Nil(t, value.get("missing").item(0))
+}
+
+func TestReadMixProject(t *testing.T) {
+	project, err := readMixProject(filepath.Join("testdata", "app", ManifestFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "shop", project.App)
+	assert.Equal(t, "0.3.1", project.Version)
+	assert.Equal(t, []mixDependency{
+		{Name: "plug_cowboy"},
+		{Name: "jason"},
+		{Name: "acme_billing"},
+		{Name: "ecto_sql"},
+		{Name: "credo", Dev: true},
+	}, project.Dependencies)
+}
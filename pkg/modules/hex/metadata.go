@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// hexAPIURL is the API of hex.pm
+const hexAPIURL = "https://hex.pm/api"
+
+// hexMetadataFile is the metadata hex extracts to the directory of a fetched package, an Erlang term file
+const hexMetadataFile = "hex_metadata.config"
+
+// metadataLicenses is the licenses entry of a hex_metadata.config, e.g. {<<"licenses">>,[<<"Apache-2.0">>]}
+var metadataLicenses = regexp.MustCompile(`\{<<"licenses">>,\s*\[([^\]]*)\]\}`)
+
+// metadataBinary is an Erlang binary of a hex_metadata.config, e.g. <<"MIT">>
+var metadataBinary = regexp.MustCompile(`<<"((?:[^"\\]|\\.)*)">>`)
+
+// hexPackage is the part of a package of the hex.pm API the modules are enriched with
+type hexPackage struct {
+	HTMLURL string `json:"html_url"`
+	Meta    struct {
+		Licenses []string          `json:"licenses"`
+		Links    map[string]string `json:"links"`
+	} `json:"meta"`
+}
+
+// hexMetadata resolves the licenses the lockfile misses, from the packages mix fetched to the deps
+// directory and, when the network is allowed, from hex.pm
+type hexMetadata struct {
+	depsPath string
+	// fetcher is nil when the network is not allowed
+	fetcher *hexFetcher
+	ctx     context.Context
+	cache   *cache.Cache
+	// matcher identifies the license files of the packages
+	matcher helper.LicenseMatcher
+}
+
+// hexFetcher fetches the packages of the hex.pm API
+type hexFetcher struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newHexFetcher() *hexFetcher {
+	return &hexFetcher{client: &http.Client{Timeout: 30 * time.Second}, baseURL: hexAPIURL}
+}
+
+// enrich sets the license of the fetched package, else the one hex.pm publishes for a public package
+func (m *hexMetadata) enrich(mod *models.Module, entry *lockEntry) {
+	if m == nil {
+		return
+	}
+
+	dir := filepath.Join(m.depsPath, entry.Name)
+	if helper.Exists(dir) {
+		mod.LocalPath = dir
+		if licenses, err := readMetadataLicenses(filepath.Join(dir, hexMetadataFile)); err == nil && len(licenses) > 0 {
+			helper.SetLicenseExpression(mod, helper.LicenseAlternatives(licenses))
+		} else {
+			helper.SetLicense(m.matcher, dir, mod)
+		}
+	}
+
+	if mod.LicenseDeclared != "" || m.fetcher == nil || entry.Source != sourceHex || entry.Repo != hexpmRepo {
+		return
+	}
+	key := mod.PackageURL
+	cached, ok := m.cache.Get(key)
+	if !ok {
+		pkg, err := m.fetcher.fetchPackage(m.ctx, entry.Package)
+		if err != nil {
+			log.Debugf("failed to fetch the metadata of %s: %v", entry.Package, err)
+			return
+		}
+		cached = cache.Entry{
+			License:          helper.LicenseAlternatives(pkg.Meta.Licenses),
+			HomePage:         pkg.HTMLURL,
+			SourceRepository: sourceRepository(pkg.Meta.Links),
+		}
+		if err := m.cache.Put(key, cached); err != nil {
+			log.Warnf("failed to write the resume cache: %v", err)
+		}
+	}
+
+	helper.SetLicenseExpression(mod, cached.License)
+	if cached.HomePage != "" {
+		mod.PackageHomePage = cached.HomePage
+	}
+}
+
+// fetchPackage fetches a package from /packages/<name>
+func (f *hexFetcher) fetchPackage(ctx context.Context, name string) (*hexPackage, error) {
+	u := fmt.Sprintf("%s/packages/%s", strings.TrimSuffix(f.baseURL, "/"), url.PathEscape(name))
+	request, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := f.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s %s", errUnexpectedStatus, u, response.Status)
+	}
+
+	pkg := &hexPackage{}
+	if err := json.NewDecoder(response.Body).Decode(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// readMetadataLicenses reads the licenses of a hex_metadata.config
+func readMetadataLicenses(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	match := metadataLicenses.FindSubmatch(data)
+	if match == nil {
+		return nil, nil
+	}
+	var licenses []string
+	for _, license := range metadataBinary.FindAllSubmatch(match[1], -1) {
+		licenses = append(licenses, string(license[1]))
+	}
+	return licenses, nil
+}
+
+// sourceRepository returns the repository link of a package, e.g. its GitHub one, "" when it has none
+func sourceRepository(links map[string]string) string {
+	names := make([]string, 0, len(links))
+	for name := range links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case "github", "gitlab", "source", "repository":
+			return links[name]
+		}
+	}
+	return ""
+}
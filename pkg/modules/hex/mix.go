@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// mixApp and mixVersion are the application and the version of the project function of a mix.exs
+var (
+	mixApp     = regexp.MustCompile(`\bapp:\s*:(\w+)`)
+	mixVersion = regexp.MustCompile(`\bversion:\s*"([^"]+)"`)
+	// mixDependencyTuple starts a dependency tuple, e.g. {:phoenix, "~> 1.7"}
+	mixDependencyTuple = regexp.MustCompile(`\{\s*:(\w+)\s*,`)
+	// mixOnly is the environments a dependency is restricted to, e.g. only: [:dev, :test]
+	mixOnly = regexp.MustCompile(`\bonly:\s*(:\w+|\[[^\]]*\])`)
+)
+
+// mixProject is the part of a mix.exs the project is listed with
+type mixProject struct {
+	App     string
+	Version string
+	// Dependencies are the dependencies of the project, the ones of the dev and test environments only
+	// are marked so
+	Dependencies []mixDependency
+}
+
+type mixDependency struct {
+	Name string
+	Dev  bool
+}
+
+// readMixProject reads the application, the version and the dependencies of a mix.exs. The file is Elixir
+// code so it is not evaluated: the first app and version keywords are the ones of the project and the
+// tuples starting with an atom are its dependencies, the module attributes they may use are not resolved
+func readMixProject(path string) (*mixProject, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+
+	project := &mixProject{}
+	if match := mixApp.FindStringSubmatch(text); match != nil {
+		project.App = match[1]
+	}
+	if match := mixVersion.FindStringSubmatch(text); match != nil {
+		project.Version = match[1]
+	}
+
+	for _, match := range mixDependencyTuple.FindAllStringSubmatchIndex(text, -1) {
+		tuple := closingTuple(text, match[0])
+		dependency := mixDependency{Name: text[match[2]:match[3]]}
+		if only := mixOnly.FindStringSubmatch(tuple); only != nil {
+			dependency.Dev = !strings.Contains(only[1], ":prod")
+		}
+		project.Dependencies = append(project.Dependencies, dependency)
+	}
+	return project, nil
+}
+
+// closingTuple returns the text of the tuple starting at start, up to the end of the text when it is not closed
+func closingTuple(text string, start int) string {
+	depth := 0
+	inString := false
+	for i := start; i < len(text); i++ {
+		switch c := text[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return text[start : i+1]
+			}
+		}
+	}
+	return text[start:]
+}
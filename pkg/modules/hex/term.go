@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// termKind is the kind of an Elixir term
+type termKind int
+
+const (
+	termString termKind = iota
+	termAtom
+	// termWord is a literal that is neither a string nor an atom, e.g. true or 42
+	termWord
+	termList
+	termTuple
+	termMap
+	// termPair is an entry of a map or of a keyword list, its items are its key and its value
+	termPair
+)
+
+// term is a value of the subset of the Elixir syntax mix writes its lockfiles with: maps, tuples,
+// lists and keyword lists of strings, atoms and literals
+type term struct {
+	Kind  termKind
+	Value string
+	Items []*term
+	// line is the line the term starts at
+	line int
+}
+
+// get returns the value of a map or keyword list entry, nil for the missing ones and for a nil term
+func (t *term) get(key string) *term {
+	if t == nil {
+		return nil
+	}
+	for _, item := range t.Items {
+		if item.Kind == termPair && item.Items[0].Value == key {
+			return item.Items[1]
+		}
+	}
+	return nil
+}
+
+// item returns the item of a tuple or a list at index, nil when it has fewer items
+func (t *term) item(index int) *term {
+	if t == nil || index >= len(t.Items) {
+		return nil
+	}
+	return t.Items[index]
+}
+
+// str returns the value of a string, an atom or a literal, "" for a nil term
+func (t *term) str() string {
+	if t == nil {
+		return ""
+	}
+	return t.Value
+}
+
+type termParser struct {
+	fileName string
+	text     string
+	pos      int
+	line     int
+}
+
+// parseTerm parses the single term of a file, e.g. the map of a mix.lock. Errors name the file and the line
+func parseTerm(fileName, text string) (*term, error) {
+	p := &termParser{fileName: fileName, text: text, line: 1}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.text) {
+		return nil, p.errorf("unexpected %q after the term", p.text[p.pos])
+	}
+	return value, nil
+}
+
+func (p *termParser) errorf(format string, args ...interface{}) error {
+	return reader.MalformedError(p.fileName, p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips the blanks and the # comments
+func (p *termParser) skipSpace() {
+	for p.pos < len(p.text) {
+		switch c := p.text[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.text) && p.text[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *termParser) parseValue() (*term, error) {
+	p.skipSpace()
+	if p.pos >= len(p.text) {
+		return nil, p.errorf("unexpected end of file")
+	}
+
+	line := p.line
+	switch c := p.text[p.pos]; {
+	case strings.HasPrefix(p.text[p.pos:], "%{"):
+		p.pos += 2
+		items, err := p.parseItems('}', true)
+		return &term{Kind: termMap, Items: items, line: line}, err
+	case c == '{':
+		p.pos++
+		items, err := p.parseItems('}', false)
+		return &term{Kind: termTuple, Items: items, line: line}, err
+	case c == '[':
+		p.pos++
+		items, err := p.parseItems(']', true)
+		return &term{Kind: termList, Items: items, line: line}, err
+	case c == '"':
+		value, err := p.parseString()
+		return &term{Kind: termString, Value: value, line: line}, err
+	case c == ':':
+		p.pos++
+		if p.pos < len(p.text) && p.text[p.pos] == '"' {
+			value, err := p.parseString()
+			return &term{Kind: termAtom, Value: value, line: line}, err
+		}
+		word := p.parseWord()
+		if word == "" {
+			return nil, p.errorf("unexpected %q", c)
+		}
+		return &term{Kind: termAtom, Value: word, line: line}, nil
+	}
+
+	word := p.parseWord()
+	if word == "" {
+		return nil, p.errorf("unexpected %q", p.text[p.pos])
+	}
+	return &term{Kind: termWord, Value: word, line: line}, nil
+}
+
+// parseItems parses the comma separated items up to the closing delimiter, the items of a map or a list
+// may be pairs: "key": value, key: value or key => value
+func (p *termParser) parseItems(closing byte, pairs bool) ([]*term, error) {
+	var items []*term
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.text) {
+			return nil, p.errorf("missing %q", closing)
+		}
+		if p.text[p.pos] == closing {
+			p.pos++
+			return items, nil
+		}
+
+		item, err := p.parseItem(pairs)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		p.skipSpace()
+		if p.pos < len(p.text) && p.text[p.pos] == ',' {
+			p.pos++
+		} else if p.pos < len(p.text) && p.text[p.pos] != closing {
+			return nil, p.errorf("expected ',' or %q", closing)
+		}
+	}
+}
+
+func (p *termParser) parseItem(pairs bool) (*term, error) {
+	p.skipSpace()
+	line := p.line
+	if pairs {
+		if key, ok := p.parseKeyword(); ok {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			return &term{Kind: termPair, Items: []*term{{Kind: termAtom, Value: key, line: line}, value}, line: line}, nil
+		}
+	}
+
+	item, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if pairs && strings.HasPrefix(p.text[p.pos:], "=>") {
+		p.pos += 2
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &term{Kind: termPair, Items: []*term{item, value}, line: line}, nil
+	}
+	return item, nil
+}
+
+// parseKeyword parses the key of a keyword pair, a word or a string followed by a colon and a blank,
+// the position is kept when there is none
+func (p *termParser) parseKeyword() (string, bool) {
+	start, line := p.pos, p.line
+	var key string
+	if p.pos < len(p.text) && p.text[p.pos] == '"' {
+		value, err := p.parseString()
+		if err != nil {
+			p.pos, p.line = start, line
+			return "", false
+		}
+		key = value
+	} else {
+		key = p.parseWord()
+	}
+
+	if key != "" && p.pos+1 < len(p.text) && p.text[p.pos] == ':' && isSpace(p.text[p.pos+1]) {
+		p.pos++
+		return key, true
+	}
+	p.pos, p.line = start, line
+	return "", false
+}
+
+// parseString parses a double quoted string, its escapes are the ones of Go but for the #{} interpolation
+// mix never writes
+func (p *termParser) parseString() (string, error) {
+	start := p.pos
+	for p.pos++; p.pos < len(p.text); p.pos++ {
+		switch p.text[p.pos] {
+		case '\\':
+			p.pos++
+		case '\n':
+			p.line++
+		case '"':
+			p.pos++
+			quoted := p.text[start:p.pos]
+			if value, err := strconv.Unquote(quoted); err == nil {
+				return value, nil
+			}
+			return quoted[1 : len(quoted)-1], nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseWord parses an identifier or a literal, e.g. hex, true, 42 or Elixir.Module
+func (p *termParser) parseWord() string {
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if c == '_' || c == '.' || c == '-' || c == '?' || c == '!' || c == '@' ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.text[start:p.pos]
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
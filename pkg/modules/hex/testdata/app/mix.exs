@@ -0,0 +1,27 @@
+defmodule Shop.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :shop,
+      version: "0.3.1",
+      elixir: "~> 1.15",
+      start_permanent: Mix.env() == :prod,
+      deps: deps()
+    ]
+  end
+
+  def application do
+    [extra_applications: [:logger]]
+  end
+
+  defp deps do
+    [
+      {:plug_cowboy, "~> 2.6"},
+      {:jason, "~> 1.4"},
+      {:acme_billing, "~> 1.0", organization: "acme"},
+      {:ecto_sql, git: "https://github.com/elixir-ecto/ecto_sql.git", branch: "master"},
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false}
+    ]
+  end
+end
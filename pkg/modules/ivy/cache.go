@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// revisionPartRegex splits a revision into its numeric and text parts, e.g. 1.2.0-rc1 into 1 2 0 rc 1
+var revisionPartRegex = regexp.MustCompile(`\d+|[a-zA-Z]+`)
+
+// moduleDir returns the directory of the module in the cache
+func moduleDir(cache string, org string, name string) string {
+	return filepath.Join(cache, org, name)
+}
+
+// cachedIvyFile returns the ivy file of the revision in the cache, ivy converts the maven poms to ivy files
+func cachedIvyFile(cache string, org string, name string, rev string) string {
+	return filepath.Join(moduleDir(cache, org, name), "ivy-"+rev+".xml")
+}
+
+// cachedJar returns the jar of the revision in the cache, see cachePattern
+func cachedJar(cache string, org string, name string, rev string) string {
+	return filepath.Join(moduleDir(cache, org, name), "jars", name+"-"+rev+".jar")
+}
+
+// cachedLocation returns the url the jar of the revision was downloaded from, as recorded by the
+// ivydata properties of the cache, or ""
+func cachedLocation(cache string, org string, name string, rev string) string {
+	file, err := os.Open(filepath.Join(moduleDir(cache, org, name), "ivydata-"+rev+".properties"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		// the keys are artifact\:name\#type\#ext\#hash.location, the : of the values are escaped too
+		line := strings.ReplaceAll(sc.Text(), `\`, "")
+		split := strings.SplitN(line, ".location=", 2)
+		if len(split) == 2 && strings.Contains(split[0], "#jar#jar#") {
+			return split[1]
+		}
+	}
+	return ""
+}
+
+// isDynamic checks the revision is resolved by ivy, e.g. latest.integration, 1.+ or [1.0,2.0[
+func isDynamic(rev string) bool {
+	return strings.HasPrefix(rev, "latest.") || strings.HasSuffix(rev, "+") || isRange(rev)
+}
+
+func isRange(rev string) bool {
+	return len(rev) > 2 && strings.ContainsAny(rev[:1], "[](") && strings.ContainsAny(rev[len(rev)-1:], "[])") &&
+		strings.Contains(rev, ",")
+}
+
+// resolveRevision returns the highest revision of the cache the dynamic revision matches, the
+// revision as is when it is not dynamic or no cached one matches
+func resolveRevision(cache string, org string, name string, rev string) (string, bool) {
+	if !isDynamic(rev) {
+		return rev, true
+	}
+
+	files, _ := filepath.Glob(filepath.Join(moduleDir(cache, org, name), "ivy-*.xml"))
+	resolved := ""
+	for _, file := range files {
+		candidate := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "ivy-"), ".xml")
+		if matchesRevision(rev, candidate) && (resolved == "" || compareRevisions(candidate, resolved) > 0) {
+			resolved = candidate
+		}
+	}
+	if resolved == "" {
+		return rev, false
+	}
+	return resolved, true
+}
+
+// matchesRevision checks the candidate satisfies the dynamic revision
+func matchesRevision(rev string, candidate string) bool {
+	switch {
+	case strings.HasPrefix(rev, "latest."):
+		return true
+	case strings.HasSuffix(rev, "+"):
+		return strings.HasPrefix(candidate, strings.TrimSuffix(rev, "+"))
+	case isRange(rev):
+		bounds := strings.SplitN(rev[1:len(rev)-1], ",", 2)
+		lower, upper := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+		// [ includes a lower bound and ] an upper one, the other way round they exclude it
+		if lower != "" {
+			if c := compareRevisions(candidate, lower); c < 0 || (c == 0 && rev[0] != '[') {
+				return false
+			}
+		}
+		if upper != "" {
+			if c := compareRevisions(candidate, upper); c > 0 || (c == 0 && rev[len(rev)-1] != ']') {
+				return false
+			}
+		}
+		return true
+	}
+	return rev == candidate
+}
+
+// compareRevisions compares the revisions part by part, numbers numerically and higher than a text part
+func compareRevisions(a string, b string) int {
+	partsA, partsB := revisionPartRegex.FindAllString(a, -1), revisionPartRegex.FindAllString(b, -1)
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, errA := strconv.Atoi(partsA[i])
+		numberB, errB := strconv.Atoi(partsB[i])
+		switch {
+		case errA == nil && errB == nil:
+			if numberA != numberB {
+				return sign(numberA - numberB)
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		default:
+			if c := strings.Compare(partsA[i], partsB[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	// a release is higher than its qualified revisions, e.g. 1.0 > 1.0-rc1, but lower than 1.0.1
+	switch {
+	case len(partsA) == len(partsB):
+		return 0
+	case len(partsA) > len(partsB):
+		if _, err := strconv.Atoi(partsA[len(partsB)]); err != nil {
+			return -1
+		}
+		return 1
+	default:
+		if _, err := strconv.Atoi(partsB[len(partsA)]); err != nil {
+			return 1
+		}
+		return -1
+	}
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	if n > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRevision(t *testing.T) {
+	cache := filepath.Join("testdata", "cache")
+	for rev, want := range map[string]string{
+		"1.7.30":             "1.7.30",
+		"1.7.+":              "1.7.36",
+		"latest.integration": "1.7.36",
+		"[1.7.0,1.7.36[":     "1.7.30",
+		"[1.7.0,1.7.36]":     "1.7.36",
+	} {
+		resolved, ok := resolveRevision(cache, "org.slf4j", "slf4j-simple", rev)
+		assert.True(t, ok, rev)
+		assert.Equal(t, want, resolved, rev)
+	}
+
+	resolved, ok := resolveRevision(cache, "org.slf4j", "slf4j-simple", "2.+")
+	assert.False(t, ok)
+	assert.Equal(t, "2.+", resolved)
+}
+
+func TestCompareRevisions(t *testing.T) {
+	assert.Equal(t, 1, compareRevisions("1.7.36", "1.7.30"))
+	assert.Equal(t, 1, compareRevisions("1.10", "1.9"))
+	assert.Equal(t, 1, compareRevisions("1.0", "1.0-rc1"))
+	assert.Equal(t, -1, compareRevisions("1.0", "1.0.1"))
+	assert.Equal(t, 0, compareRevisions("2.6", "2.6"))
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"errors"
+)
+
+var errNoModuleInfo error = errors.New("ivy.xml has no <info> organisation and module")
+var errCacheNotFound error = errors.New("unable to generate SPDX file, the ivy cache was not found. Please resolve the dependencies before running spdx-sbom-generator, e.g.: `ant resolve`")
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type ivy struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	IvyFile string = "ivy.xml"
+)
+
+// New creates a new Apache Ivy instance
+func New() *ivy {
+	return &ivy{
+		metadata: models.PluginMetadata{
+			Name:       "Apache Ivy",
+			Slug:       "ivy",
+			Manifest:   []string{IvyFile},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *ivy) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the version of Ant
+func (m *ivy) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "ant", "-version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *ivy) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *ivy) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the module the ivy.xml describes as the root package
+func (m *ivy) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(absPath, IvyFile))
+	if err != nil {
+		return nil, err
+	}
+	module, err := readIvyFile(filepath.Join(absPath, IvyFile))
+	if err != nil {
+		return nil, err
+	}
+	if module.Info.Organisation == "" || module.Info.Module == "" {
+		return nil, errNoModuleInfo
+	}
+
+	root := newModule(module.Info.Organisation, module.Info.Module, module.Info.Revision)
+	root.Root = true
+	root.LocalPath = absPath
+	root.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA1,
+		Content:   content,
+	}
+	setInfo(&root, module.Info)
+	return &root, nil
+}
+
+// ListUsedModules returns the dependencies of the module, without the module itself
+func (m *ivy) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the root package followed by the dependencies the ivy.xml declares and,
+// from the ivy files of the cache, the dependencies of theirs
+func (m *ivy) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+	module, err := readIvyFile(filepath.Join(path, IvyFile))
+	if err != nil {
+		return nil, err
+	}
+	settings, err := readSettings(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := dependencyResolver{
+		cache:    settings.cacheDir(path),
+		patterns: settings.artifactPatterns(path),
+		indexes:  map[string]int{},
+		modules:  []models.Module{*root},
+	}
+	for _, dependency := range module.Dependencies {
+		index := resolver.resolve(dependency.organisation(module), dependency.Name, dependency.Rev)
+		resolver.link(0, index, models.ScopeRelationship(dependency.scope()))
+	}
+
+	return resolver.modules, nil
+}
+
+// dependencyResolver lists the dependencies once each, following the ivy files of the cache
+type dependencyResolver struct {
+	cache    string
+	patterns []string
+	indexes  map[string]int
+	modules  []models.Module
+}
+
+// resolve adds the package of the dependency and, recursively, of its dependencies and returns its index
+func (r *dependencyResolver) resolve(org string, name string, rev string) int {
+	resolvedRev, ok := resolveRevision(r.cache, org, name, rev)
+	key := org + ":" + name + ":" + resolvedRev
+	if index, ok := r.indexes[key]; ok {
+		return index
+	}
+
+	mod := newModule(org, name, resolvedRev)
+	if !ok {
+		mod.Unresolved = append(mod.Unresolved, fmt.Sprintf("the dynamic revision %s is not in the ivy cache, the resolved one is unknown", rev))
+	}
+	mod.PackageDownloadLocation = r.location(org, name, resolvedRev)
+	if sum, err := helper.FileSHA1(cachedJar(r.cache, org, name, resolvedRev)); err == nil {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: sum}
+	} else {
+		mod.Unresolved = append(mod.Unresolved, "the jar is not in the ivy cache, its checksum is unknown")
+	}
+
+	index := len(r.modules)
+	r.indexes[key] = index
+	r.modules = append(r.modules, mod)
+
+	cached, err := readIvyFile(cachedIvyFile(r.cache, org, name, resolvedRev))
+	if err != nil {
+		r.modules[index].Unresolved = append(r.modules[index].Unresolved, "the ivy file is not in the ivy cache, the dependencies are unknown")
+		return index
+	}
+	setInfo(&r.modules[index], cached.Info)
+	for _, dependency := range cached.Dependencies {
+		if !dependency.isTransitive() {
+			continue
+		}
+		r.link(index, r.resolve(dependency.organisation(cached), dependency.Name, dependency.Rev), models.RelationshipDependsOn)
+	}
+	return index
+}
+
+// location returns the url the jar was downloaded from, the one of the first resolver otherwise
+func (r *dependencyResolver) location(org string, name string, rev string) string {
+	if location := cachedLocation(r.cache, org, name, rev); location != "" {
+		return location
+	}
+	if len(r.patterns) == 0 {
+		return ""
+	}
+	return substitutePattern(r.patterns[0], org, name, rev)
+}
+
+// link adds the module at index to the modules of the one at parentIndex with the relationship
+func (r *dependencyResolver) link(parentIndex int, index int, relationship models.RelationshipType) {
+	if parentIndex == index {
+		return
+	}
+
+	linked := r.modules[index]
+	linked.Relationship = relationship
+	r.modules[parentIndex].Modules[linked.Name] = &linked
+}
+
+// substitutePattern returns the url of the jar of the artifact pattern, the optional parts between
+// parentheses are dropped as there is no classifier
+func substitutePattern(pattern string, org string, name string, rev string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range pattern {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.NewReplacer(
+		"[organisation]", org,
+		"[organization]", org,
+		"[orgPath]", strings.ReplaceAll(org, ".", "/"),
+		"[module]", name,
+		"[artifact]", name,
+		"[revision]", rev,
+		"[type]", "jar",
+		"[ext]", "jar",
+	).Replace(b.String())
+}
+
+// newModule returns the package of the module, ivy modules are mostly maven ones
+func newModule(org string, name string, rev string) models.Module {
+	return models.Module{
+		Name:       name,
+		Version:    rev,
+		Path:       org + ":" + name,
+		PackageURL: purl.New("maven", org, name, rev).String(),
+		Packaging:  "jar",
+		Supplier: models.SupplierContact{
+			Type: models.Organization,
+			Name: org,
+		},
+		Modules: map[string]*models.Module{},
+	}
+}
+
+// setInfo sets the home page and the license of the <info> of an ivy file, the license when it is an SPDX identifier
+func setInfo(mod *models.Module, info ivyInfo) {
+	mod.PackageHomePage = info.Description.HomePage
+	if len(info.Licenses) == 1 && helper.LicenseSPDXExists(info.Licenses[0].Name) {
+		mod.LicenseDeclared = info.Licenses[0].Name
+		mod.LicenseConcluded = info.Licenses[0].Name
+	}
+}
+
+// IsValid checks if the ivy.xml exists
+func (m *ivy) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, IvyFile))
+}
+
+// HasModulesInstalled checks the ivy cache exists, the dependencies are resolved to it, e.g. by ant resolve
+func (m *ivy) HasModulesInstalled(path string) error {
+	settings, err := readSettings(path)
+	if err != nil {
+		return err
+	}
+	if cache := settings.cacheDir(path); !helper.Exists(cache) {
+		return fmt.Errorf("%w: %s", errCacheNotFound, cache)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "project"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 5)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "hello", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "pkg:maven/org.example/hello@1.0.0", root.PackageURL)
+	assert.Equal(t, "https://example.org/hello", root.PackageHomePage)
+	assert.Equal(t, "Apache-2.0", root.LicenseDeclared)
+	assert.Len(t, root.Modules, 3)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["junit"].Relationship)
+
+	byName := map[string]models.Module{}
+	for _, module := range modules {
+		byName[module.Name] = module
+	}
+
+	// the download location is the one recorded in the cache
+	lang := byName["commons-lang"]
+	assert.Equal(t, "https://repo1.maven.org/maven2/commons-lang/commons-lang/2.6/commons-lang-2.6.jar", lang.PackageDownloadLocation)
+	assert.Equal(t, "a07e835ca7b554543fa0138ecd86f2bbf79f541b", lang.CheckSum.Value)
+	assert.Equal(t, "http://commons.apache.org/lang/", lang.PackageHomePage)
+	// the test dependency of commons-lang is not on the classpath
+	assert.Empty(t, lang.Modules)
+
+	// the dynamic revision is the highest cached one, its dependencies come from its cached ivy file
+	simple := byName["slf4j-simple"]
+	assert.Equal(t, "1.7.36", simple.Version)
+	assert.Equal(t, "97d658ee8b57011c1d3528f5fb5c69fd6cd128a1", simple.CheckSum.Value)
+	assert.Equal(t, "https://repo.example.org/ivy/org.slf4j/slf4j-simple/1.7.36/slf4j-simple-1.7.36.jar", simple.PackageDownloadLocation)
+	assert.Contains(t, simple.Modules, "slf4j-api")
+
+	api := byName["slf4j-api"]
+	assert.Equal(t, "pkg:maven/org.slf4j/slf4j-api@1.7.36", api.PackageURL)
+	assert.Equal(t, "MIT", api.LicenseDeclared)
+
+	junit := byName["junit"]
+	assert.Equal(t, "4.13.2", junit.Version)
+	assert.Nil(t, junit.CheckSum)
+	assert.Len(t, junit.Unresolved, 2)
+}
+
+func TestSubstitutePattern(t *testing.T) {
+	assert.Equal(t, "https://repo1.maven.org/maven2/org/slf4j/slf4j-api/1.7.36/slf4j-api-1.7.36.jar",
+		substitutePattern(mavenCentralURL+m2Pattern, "org.slf4j", "slf4j-api", "1.7.36"))
+	assert.Equal(t, "https://repo.example.org/ivy/org.slf4j/slf4j-api/1.7.36/slf4j-api-1.7.36.jar",
+		substitutePattern("https://repo.example.org/ivy/[organisation]/[module]/[revision]/[artifact]-[revision](-[classifier]).[ext]", "org.slf4j", "slf4j-api", "1.7.36"))
+}
+
+func TestHasModulesInstalled(t *testing.T) {
+	assert.NoError(t, New().HasModulesInstalled(filepath.Join("testdata", "project")))
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+)
+
+// ivyModule is an ivy.xml, the ivy files of the cache have the same format
+type ivyModule struct {
+	Info         ivyInfo         `xml:"info"`
+	Dependencies []ivyDependency `xml:"dependencies>dependency"`
+}
+
+type ivyInfo struct {
+	Organisation string       `xml:"organisation,attr"`
+	Module       string       `xml:"module,attr"`
+	Revision     string       `xml:"revision,attr"`
+	Licenses     []ivyLicense `xml:"license"`
+	Description  struct {
+		HomePage string `xml:"homepage,attr"`
+	} `xml:"description"`
+}
+
+type ivyLicense struct {
+	Name string `xml:"name,attr"`
+	URL  string `xml:"url,attr"`
+}
+
+type ivyDependency struct {
+	Org  string `xml:"org,attr"`
+	Name string `xml:"name,attr"`
+	Rev  string `xml:"rev,attr"`
+	Conf string `xml:"conf,attr"`
+}
+
+func readIvyFile(path string) (ivyModule, error) {
+	var module ivyModule
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return module, err
+	}
+
+	err = xml.Unmarshal(data, &module)
+	return module, err
+}
+
+// organisation returns the org of the dependency, the one of the module depending on it when not set
+func (d ivyDependency) organisation(module ivyModule) string {
+	if d.Org == "" {
+		return module.Info.Organisation
+	}
+	return d.Org
+}
+
+// masterConfs returns the configurations of the module the dependency is declared in, e.g. compile and
+// test for "compile->default;test->runtime(*)". No conf means the dependency is part of all of them
+func (d ivyDependency) masterConfs() []string {
+	var confs []string
+	for _, mapping := range strings.Split(d.Conf, ";") {
+		master := strings.SplitN(mapping, "->", 2)[0]
+		for _, conf := range strings.Split(master, ",") {
+			if conf = strings.TrimSpace(conf); conf != "" {
+				confs = append(confs, conf)
+			}
+		}
+	}
+	return confs
+}
+
+// scope returns the configuration the relationship of the dependency is told from, the first one
+// that is not a test or build only one, e.g. runtime for "test,runtime"
+func (d ivyDependency) scope() string {
+	confs := d.masterConfs()
+	if len(confs) == 0 {
+		return ""
+	}
+	for _, conf := range confs {
+		if transitiveConfs[conf] {
+			return conf
+		}
+	}
+	return confs[0]
+}
+
+// transitiveConfs are the configurations of a dependency whose own dependencies end up on the classpath,
+// maven poms are converted to ivy files with one configuration per scope in the cache
+var transitiveConfs = map[string]bool{
+	"*":       true,
+	"default": true,
+	"master":  true,
+	"compile": true,
+	"runtime": true,
+}
+
+// isTransitive checks the dependency of a cached ivy file is on the classpath of the ones depending on
+// its module, test, provided, system and optional dependencies are not
+func (d ivyDependency) isTransitive() bool {
+	confs := d.masterConfs()
+	if len(confs) == 0 {
+		return true
+	}
+	for _, conf := range confs {
+		if transitiveConfs[conf] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	SettingsFile string = "ivysettings.xml"
+
+	mavenCentralURL = "https://repo1.maven.org/maven2/"
+	// m2Pattern is the artifact pattern of the maven compatible ibiblio resolvers, [orgPath] is the
+	// organisation with its dots replaced by slashes
+	m2Pattern = "[orgPath]/[module]/[revision]/[artifact]-[revision](-[classifier]).[ext]"
+	// cachePattern is the default artifact pattern of the ivy cache
+	cachePattern = "[organisation]/[module]/[type]s/[artifact]-[revision](-[classifier]).[ext]"
+)
+
+// settingsVariableRegex matches a ${name} variable of the settings
+var settingsVariableRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// ivySettings is an ivysettings.xml, only the resolvers and the cache are read
+type ivySettings struct {
+	Settings struct {
+		DefaultResolver string `xml:"defaultResolver,attr"`
+	} `xml:"settings"`
+	Properties []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"property"`
+	Caches struct {
+		DefaultCacheDir string `xml:"defaultCacheDir,attr"`
+	} `xml:"caches"`
+	Resolvers resolverList `xml:"resolvers"`
+}
+
+// resolverList holds the resolvers of the settings, or the ones of a chain, in their declaration order
+type resolverList struct {
+	Resolvers []resolver
+}
+
+// resolver is an ibiblio, url or filesystem resolver, or a chain of resolvers
+type resolver struct {
+	Kind         string
+	Name         string
+	Root         string
+	M2Compatible bool
+	Patterns     []string
+	Chain        []resolver
+}
+
+// UnmarshalXML reads the resolvers whatever their element, ivy has many kinds of them
+func (l *resolverList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			var r resolver
+			if err := r.unmarshal(d, element); err != nil {
+				return err
+			}
+			l.Resolvers = append(l.Resolvers, r)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (r *resolver) unmarshal(d *xml.Decoder, start xml.StartElement) error {
+	r.Kind = start.Name.Local
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			r.Name = attr.Value
+		case "root":
+			r.Root = attr.Value
+		case "m2compatible":
+			r.M2Compatible = attr.Value == "true"
+		}
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Local == "artifact" {
+				for _, attr := range element.Attr {
+					if attr.Name.Local == "pattern" {
+						r.Patterns = append(r.Patterns, attr.Value)
+					}
+				}
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			// the other children of a chain are resolvers, the ivy patterns of a resolver are skipped
+			if r.Kind != "chain" {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			var child resolver
+			if err := child.unmarshal(d, element); err != nil {
+				return err
+			}
+			r.Chain = append(r.Chain, child)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readSettings reads the ivysettings.xml of the project, the default settings when there is none
+func readSettings(path string) (ivySettings, error) {
+	var settings ivySettings
+
+	data, err := ioutil.ReadFile(filepath.Join(path, SettingsFile))
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	err = xml.Unmarshal(data, &settings)
+	return settings, err
+}
+
+// variables returns the properties of the settings and the ones ivy defines
+func (s ivySettings) variables(path string) map[string]string {
+	home, _ := os.UserHomeDir()
+	absPath, _ := filepath.Abs(path)
+	variables := map[string]string{
+		"user.home":                home,
+		"basedir":                  absPath,
+		"ivy.settings.dir":         absPath,
+		"ivy.default.ivy.user.dir": filepath.Join(home, ".ivy2"),
+		"ivy.home":                 filepath.Join(home, ".ivy2"),
+	}
+	for _, property := range s.Properties {
+		if _, ok := variables[property.Name]; !ok {
+			variables[property.Name] = property.Value
+		}
+	}
+	return variables
+}
+
+// expand replaces the ${name} variables of the value, the unknown ones are left as is
+func expand(value string, variables map[string]string) string {
+	// the properties may refer to each other, a few passes resolve them
+	for i := 0; i < 4 && strings.Contains(value, "${"); i++ {
+		value = settingsVariableRegex.ReplaceAllStringFunc(value, func(variable string) string {
+			if resolved, ok := variables[variable[2:len(variable)-1]]; ok {
+				return resolved
+			}
+			return variable
+		})
+	}
+	return value
+}
+
+// cacheDir returns the ivy cache of the settings, ~/.ivy2/cache by default
+func (s ivySettings) cacheDir(path string) string {
+	variables := s.variables(path)
+	if dir := s.Caches.DefaultCacheDir; dir != "" {
+		return expand(dir, variables)
+	}
+	return filepath.Join(variables["ivy.default.ivy.user.dir"], "cache")
+}
+
+// artifactPatterns returns the remote artifact patterns of the default resolver, the chains are
+// flattened in order. Ivy resolves from Maven Central by default
+func (s ivySettings) artifactPatterns(path string) []string {
+	variables := s.variables(path)
+
+	resolvers := s.Resolvers.Resolvers
+	if name := s.Settings.DefaultResolver; name != "" {
+		for _, r := range flatten(resolvers) {
+			if r.Name == name {
+				resolvers = []resolver{r}
+				break
+			}
+		}
+	}
+
+	var patterns []string
+	for _, r := range flatten(resolvers) {
+		switch r.Kind {
+		case "ibiblio":
+			if !r.M2Compatible {
+				continue
+			}
+			root := r.Root
+			if root == "" {
+				root = mavenCentralURL
+			}
+			patterns = append(patterns, strings.TrimSuffix(expand(root, variables), "/")+"/"+m2Pattern)
+		case "url":
+			for _, pattern := range r.Patterns {
+				if r.M2Compatible {
+					pattern = strings.ReplaceAll(pattern, "[organisation]", "[orgPath]")
+				}
+				patterns = append(patterns, expand(pattern, variables))
+			}
+		}
+	}
+
+	if len(patterns) == 0 && len(s.Resolvers.Resolvers) == 0 {
+		return []string{mavenCentralURL + m2Pattern}
+	}
+	return patterns
+}
+
+// flatten returns the resolvers with the ones of the chains in their place
+func flatten(resolvers []resolver) []resolver {
+	var flat []resolver
+	for _, r := range resolvers {
+		flat = append(flat, r)
+		flat = append(flat, flatten(r.Chain)...)
+	}
+	return flat
+}
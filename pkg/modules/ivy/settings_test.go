@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ivy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSettings(t *testing.T) {
+	path := filepath.Join("testdata", "project")
+	settings, err := readSettings(path)
+	assert.NoError(t, err)
+
+	absPath, err := filepath.Abs(path)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(absPath, "..", "cache"), filepath.Clean(settings.cacheDir(path)))
+
+	// the filesystem resolver is not the default one, nor a remote one
+	assert.Equal(t, []string{
+		"https://repo.example.org/ivy/[organisation]/[module]/[revision]/[artifact]-[revision](-[classifier]).[ext]",
+		mavenCentralURL + m2Pattern,
+	}, settings.artifactPatterns(path))
+}
+
+func TestReadSettingsDefault(t *testing.T) {
+	settings, err := readSettings("testdata")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{mavenCentralURL + m2Pattern}, settings.artifactPatterns("testdata"))
+}
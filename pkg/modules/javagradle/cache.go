@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var sha1Dir = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// cachedArtifact is a dependency file found in the gradle cache
+type cachedArtifact struct {
+	path      string
+	sha1      string
+	extension string
+}
+
+// gradleUserHome returns the gradle user home holding the dependency cache
+func gradleUserHome() string {
+	if home := os.Getenv("GRADLE_USER_HOME"); home != "" {
+		return home
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gradle")
+}
+
+// findCachedArtifact looks up a dependency in the gradle cache, files are stored in
+// caches/modules-2/files-2.1/<group>/<artifact>/<version>/<sha1 of the file>/.
+// Without an explicit extension Android archives (aar) are preferred over jars
+func findCachedArtifact(gradleHome, dep string) (cachedArtifact, bool) {
+	groupId, artifactId, version, err := splitDep(dep)
+	if err != nil || gradleHome == "" {
+		return cachedArtifact{}, false
+	}
+
+	extensions := []string{"aar", "jar"}
+	if ext := depExtension(dep); ext != "" {
+		extensions = []string{ext}
+	}
+
+	versionDir := filepath.Join(gradleHome, "caches", "modules-2", "files-2.1", groupId, artifactId, version)
+	hashDirs, err := ioutil.ReadDir(versionDir)
+	if err != nil {
+		return cachedArtifact{}, false
+	}
+
+	for _, ext := range extensions {
+		name := artifactId + "-" + version + "." + ext
+		for _, hashDir := range hashDirs {
+			if !hashDir.IsDir() || !sha1Dir.MatchString(hashDir.Name()) {
+				continue
+			}
+
+			path := filepath.Join(versionDir, hashDir.Name(), name)
+			if _, err := os.Stat(path); err == nil {
+				return cachedArtifact{path: path, sha1: hashDir.Name(), extension: ext}, true
+			}
+		}
+	}
+
+	return cachedArtifact{}, false
+}
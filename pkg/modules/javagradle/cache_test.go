@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestFindCachedArtifactAAR(t *testing.T) {
+	home := filepath.Join("testdata", "gradle-home")
+
+	c, ok := findCachedArtifact(home, "androidx.appcompat:appcompat:1.3.0")
+	if !ok {
+		t.Fatal("aar artifact not found in cache")
+	}
+	if c.extension != "aar" || c.sha1 != "4d5d3f1c2b0a9e8d7c6b5a4f3e2d1c0b9a8f7e6d" {
+		t.Fatalf("unexpected cached artifact %+v", c)
+	}
+
+	c, ok = findCachedArtifact(home, "com.google.guava:guava:30.1-jre")
+	if !ok || c.extension != "jar" {
+		t.Fatalf("unexpected cached artifact %+v", c)
+	}
+
+	if _, ok := findCachedArtifact(home, "androidx.appcompat:appcompat:1.3.0@jar"); ok {
+		t.Fatal("explicit jar extension should not match the aar")
+	}
+}
+
+func TestGenerateModuleAAR(t *testing.T) {
+	home := filepath.Join("testdata", "gradle-home")
+	dep := "androidx.appcompat:appcompat:1.3.0"
+
+	c, ok := findCachedArtifact(home, dep)
+	if !ok {
+		t.Fatal("aar artifact not found in cache")
+	}
+	artifact := withExtension(dep, c.extension)
+
+	suffix, err := calculateURLSuffix(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "androidx/appcompat/appcompat/1.3.0/appcompat-1.3.0.aar"; suffix != want {
+		t.Fatalf("\n got: %v\nwant: %v", suffix, want)
+	}
+
+	depURL := "https://maven.google.com/" + suffix
+	checksum, err := artifactChecksum(artifact, depURL, verifiedChecksums{}, c, ok, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := generateModule(artifact, depURL, checksum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mod.Name != "appcompat" || mod.Version != "1.3.0" {
+		t.Fatalf("unexpected module %s %s", mod.Name, mod.Version)
+	}
+	if mod.PackageURL != "pkg:maven/androidx.appcompat/appcompat@1.3.0?type=aar" {
+		t.Fatalf("unexpected purl %s", mod.PackageURL)
+	}
+	if mod.PackageDownloadLocation != depURL {
+		t.Fatalf("unexpected download location %s", mod.PackageDownloadLocation)
+	}
+	if mod.CheckSum.Algorithm != models.HashAlgoSHA1 || mod.CheckSum.Value != c.sha1 {
+		t.Fatalf("unexpected checksum %+v", mod.CheckSum)
+	}
+}
+
+func TestSplitDepArtifactOnly(t *testing.T) {
+	groupId, artifactId, version, err := splitDep("androidx.core:core:1.5.0@aar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groupId != "androidx.core" || artifactId != "core" || version != "1.5.0" {
+		t.Fatalf("unexpected split %s %s %s", groupId, artifactId, version)
+	}
+	if ext := depExtension("androidx.core:core:1.5.0@aar"); ext != "aar" {
+		t.Fatalf("unexpected extension %s", ext)
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// versionCatalogFile is the version catalog the build scripts name libs, since Gradle 7.0
+var versionCatalogFile = filepath.Join("gradle", "libs.versions.toml")
+
+// catalogAccessorRegex matches a dependency the build scripts declare with a libs accessor and its configuration,
+// e.g. implementation(libs.androidx.core.ktx) or testImplementation libs.bundles.junit
+var catalogAccessorRegex = regexp.MustCompile(`\b(\w+)\s*\(?\s*libs\.([A-Za-z0-9_.]+)`)
+
+// versionCatalog are the libraries and bundles of a version catalog keyed by accessor, the alias whose
+// separators -, _ and . are all dots, e.g. androidx.core.ktx for androidx-core-ktx
+type versionCatalog struct {
+	// libraries are the group:artifact:version of the libraries, the ones without a version are left out
+	libraries map[string]string
+	// bundles are the accessors of the libraries of the bundles
+	bundles map[string][]string
+}
+
+// hasVersionCatalog tells whether the project declares its dependencies in a version catalog
+func hasVersionCatalog(path string) bool {
+	return helper.Exists(filepath.Join(path, versionCatalogFile))
+}
+
+// catalogAccessor returns the accessor of an alias
+func catalogAccessor(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// catalogVersion returns the version of a library or of the versions table, either a string or a rich version
+// of which the strict version wins over the required and the preferred ones
+func catalogVersion(value interface{}, versions reader.Table) string {
+	switch version := value.(type) {
+	case string:
+		return version
+	case reader.Table:
+		if ref := version.Str("ref"); ref != "" {
+			return catalogVersion(versions[ref], nil)
+		}
+		for _, key := range []string{"strictly", "require", "prefer"} {
+			if v := version.Str(key); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// readVersionCatalog reads the version catalog of the project, an empty one when there is none
+func readVersionCatalog(path string) (versionCatalog, error) {
+	catalog := versionCatalog{libraries: map[string]string{}, bundles: map[string][]string{}}
+
+	fileName := filepath.Join(path, versionCatalogFile)
+	file, err := os.Open(fileName)
+	if os.IsNotExist(err) {
+		return catalog, nil
+	}
+	if err != nil {
+		return catalog, err
+	}
+	defer file.Close()
+
+	document, err := reader.ParseTOML(fileName, file)
+	if err != nil {
+		return catalog, err
+	}
+
+	versions := document.Get("versions")
+	for alias, value := range document.Get("libraries") {
+		var module, version string
+		switch library := value.(type) {
+		case string:
+			// group:artifact:version
+			parts := strings.SplitN(library, ":", 3)
+			if len(parts) == 3 {
+				module, version = parts[0]+":"+parts[1], parts[2]
+			}
+		case reader.Table:
+			module = library.Str("module")
+			if module == "" && library.Str("group") != "" {
+				module = library.Str("group") + ":" + library.Str("name")
+			}
+			version = catalogVersion(library["version"], versions)
+		}
+		if module != "" && version != "" {
+			catalog.libraries[catalogAccessor(alias)] = module + ":" + version
+		}
+	}
+
+	bundles := document.Get("bundles")
+	for alias := range bundles {
+		var accessors []string
+		for _, library := range bundles.Strings(alias) {
+			accessors = append(accessors, catalogAccessor(library))
+		}
+		catalog.bundles[catalogAccessor(alias)] = accessors
+	}
+	return catalog, nil
+}
+
+// readCatalogDependencies returns the libraries of the version catalog the build scripts of the project declare a
+// dependency on, with the configurations declaring them. The plugins and the versions of the catalog are not
+// dependencies
+func readCatalogDependencies(path string) (map[string]*lockedDependency, error) {
+	catalog, err := readVersionCatalog(path)
+	if err != nil || len(catalog.libraries) == 0 {
+		return nil, err
+	}
+
+	dependencies := map[string]*lockedDependency{}
+	add := func(accessor, configuration string) {
+		coordinate, ok := catalog.libraries[accessor]
+		if !ok {
+			return
+		}
+		// the classpath of the buildscript block is the one of the build script
+		dependency, ok := dependencies[coordinate]
+		if !ok {
+			dependency = &lockedDependency{Coordinate: coordinate, Buildscript: configuration == "classpath"}
+			dependencies[coordinate] = dependency
+		} else if configuration != "classpath" {
+			dependency.Buildscript = false
+		}
+		dependency.Configurations = append(dependency.Configurations, configuration)
+	}
+
+	err = filepath.Walk(path, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if fileName != path && (strings.HasPrefix(info.Name(), ".") || info.Name() == "build") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() != "build.gradle" && info.Name() != "build.gradle.kts" {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		for _, match := range catalogAccessorRegex.FindAllStringSubmatch(string(data), -1) {
+			configuration, accessor := match[1], strings.TrimSuffix(match[2], ".get")
+			if bundle := strings.TrimPrefix(accessor, "bundles."); bundle != accessor {
+				for _, library := range catalog.bundles[bundle] {
+					add(library, configuration)
+				}
+				continue
+			}
+			add(accessor, configuration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
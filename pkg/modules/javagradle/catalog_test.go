@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListCatalogModules(t *testing.T) {
+	m := lockfileOnly(t)
+	path := filepath.Join("testdata", "catalog")
+
+	if err := m.HasModulesInstalled(path); err != nil {
+		t.Fatal(err)
+	}
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := modules[0]
+	if root.Name != "catalog" || root.Version != "2.0.0" || !root.Root {
+		t.Fatalf("unexpected root module %+v", root)
+	}
+
+	// the libs accessors resolve to the versions of the catalog, the unused and versionless libraries are left out
+	want := map[string]models.RelationshipType{
+		"com.google.guava:guava:31.1-jre":              "",
+		"com.squareup.okhttp3:okhttp:4.10.0":           "",
+		"org.jetbrains.kotlin:kotlin-stdlib:1.8.10":    "",
+		"org.junit.jupiter:junit-jupiter-api:5.9.2":    models.RelationshipDevDependencyOf,
+		"org.junit.jupiter:junit-jupiter-engine:5.9.2": models.RelationshipDevDependencyOf,
+	}
+	if len(root.Modules) != len(want) {
+		t.Fatalf("root depends on %d modules, want %d", len(root.Modules), len(want))
+	}
+	for coordinate, relationship := range want {
+		mod, ok := root.Modules[coordinate]
+		if !ok {
+			t.Fatalf("root does not depend on %s", coordinate)
+		}
+		if mod.Relationship != relationship {
+			t.Fatalf("%s: got relationship %q, want %q", coordinate, mod.Relationship, relationship)
+		}
+	}
+	if okhttp := root.Modules["com.squareup.okhttp3:okhttp:4.10.0"]; okhttp.Name != "okhttp" || okhttp.Version != "4.10.0" {
+		t.Fatalf("unexpected module %+v", okhttp)
+	}
+}
+
+func TestReadLockfilesPrefersLockedVersions(t *testing.T) {
+	path := t.TempDir()
+	files := map[string]string{
+		versionCatalogFile: "[libraries]\nguava = \"com.google.guava:guava:31.1-jre\"\njunit = \"junit:junit:4.13.2\"\n",
+		"build.gradle":     "dependencies {\n    implementation libs.guava\n    testImplementation libs.junit\n}\n",
+		lockfileName:       "com.google.guava:guava:31.0-jre=compileClasspath\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(path, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	locked, err := readLockfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) != 2 || locked[0].Coordinate != "com.google.guava:guava:31.0-jre" || locked[1].Coordinate != "junit:junit:4.13.2" {
+		t.Fatalf("unexpected dependencies %+v", locked)
+	}
+}
@@ -3,6 +3,7 @@
 package javagradle
 
 import (
+	"context"
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"os/exec"
 	"path/filepath"
@@ -13,9 +14,13 @@ import (
 type gradleExec struct {
 	executable string
 	workingDir string
+	// offline runs gradle with --offline, it resolves from its cache only
+	offline bool
+	// ctx kills gradle once done, e.g. on timeout
+	ctx context.Context
 }
 
-func newGradleExec(workingDir string) gradleExec {
+func newGradleExec(ctx context.Context, workingDir string, offline bool) gradleExec {
 	ge := gradleExec{}
 
 	if hasGradlew(workingDir) {
@@ -24,6 +29,8 @@ func newGradleExec(workingDir string) gradleExec {
 		ge.executable = "gradle"
 	}
 	ge.workingDir = workingDir
+	ge.offline = offline
+	ge.ctx = ctx
 	return ge
 }
 
@@ -33,7 +40,10 @@ func hasGradlew(workingDir string) bool {
 
 func (ge gradleExec) run(args ...string) *exec.Cmd {
 	args = append(args, "--console=plain")
-	cmd := exec.Command(ge.executable, args...)
+	if ge.offline {
+		args = append(args, "--offline")
+	}
+	cmd := helper.CommandContext(ge.ctx, ge.executable, args...)
 	cmd.Dir = ge.workingDir
 	return cmd
 }
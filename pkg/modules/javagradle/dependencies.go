@@ -5,6 +5,7 @@ package javagradle
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,6 +16,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
 )
 
 type depInfo struct {
@@ -25,9 +29,11 @@ type depInfo struct {
 
 // collect all non-transitive dependencies from all configuration (compile, test, runtime, etc)
 // perhaps this should be limited to just runtimeClasspath, but there's no real way to know
-// what the final packager is going to package into the bom, what a dilemma
-func getDependencies(dir string) (depInfo, error) {
-	return dependencies(dir, ":dependencies")
+// what the final packager is going to package into the bom, what a dilemma.
+// The task runs in every project of the build, the dependencies of a multi-project build,
+// e.g. an Android app, are declared by its subprojects
+func getDependencies(ctx context.Context, dir string, offline bool) (depInfo, error) {
+	return dependencies(ctx, dir, "dependencies", offline)
 }
 
 // collect all non-transitive dependencies from the build classpath, this is basically the dependencies
@@ -35,12 +41,12 @@ func getDependencies(dir string) (depInfo, error) {
 // can end up doing whatever they want to the final artifact. If we're trying to generate an sbom
 // *before* build.
 // Leave them out for now, but include them if we think we need to.
-func getBuildDependencies(dir string) (depInfo, error) {
-	return dependencies(dir, ":buildEnvironment")
+func getBuildDependencies(ctx context.Context, dir string, offline bool) (depInfo, error) {
+	return dependencies(ctx, dir, ":buildEnvironment", offline)
 }
 
-func dependencies(dir string, command string) (depInfo, error) {
-	out, err := newGradleExec(dir).run(command, "-q").CombinedOutput()
+func dependencies(ctx context.Context, dir string, command string, offline bool) (depInfo, error) {
+	out, err := newGradleExec(ctx, dir, offline).run(command, "-q").CombinedOutput()
 	if err != nil {
 		log.Println(string(out))
 		return depInfo{}, err
@@ -48,7 +54,45 @@ func dependencies(dir string, command string) (depInfo, error) {
 	return parseDependencyOutput(out)
 }
 
-// root dependencies, transitive dependency graph
+// the markers gradle appends to a dependency, (c) is a constraint, e.g. of a platform or a version catalog
+// bundle, (n) is declared in a configuration that is not resolved. Neither is on a classpath, nor are the
+// dependencies gradle FAILED to resolve
+var skippedDepMarkers = []string{" (c)", " (n)", " FAILED"}
+
+// parseDepNotation returns the group:artifact:version the dependency line resolved to, the version
+// selected by conflict resolution or a version catalog rich version follows ->, e.g.
+// "androidx.core:core-ktx:{strictly 1.9.0} -> 1.9.0 (*)". A dependency on a project of the build is
+// returned with isProject, a skipped one with an empty notation
+func parseDepNotation(dep string) (string, bool) {
+	dep = strings.TrimSuffix(strings.TrimSpace(dep), " (*)")
+	for _, marker := range skippedDepMarkers {
+		if strings.HasSuffix(dep, marker) {
+			return "", false
+		}
+	}
+	if strings.HasPrefix(dep, "project ") {
+		return "", true
+	}
+
+	split := strings.SplitN(dep, " -> ", 2)
+	if len(split) != 2 {
+		return dep, false
+	}
+	selected := strings.TrimSpace(split[1])
+	// a substitution selects another module, e.g. "a:b:1.0 -> c:d:2.0"
+	if strings.Count(selected, ":") >= 2 {
+		return selected, false
+	}
+
+	parts := strings.SplitN(split[0], ":", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+	return parts[0] + ":" + parts[1] + ":" + selected, false
+}
+
+// root dependencies, transitive dependency graph. The dependencies of a project of the build are
+// the ones of the node depending on the project, or root dependencies
 func parseDependencyOutput(out []byte) (depInfo, error) {
 	br := bytes.NewReader(out)
 	sc := bufio.NewScanner(br)
@@ -60,7 +104,9 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 	// map of deps and their children
 	deps := make(map[string][]string)
 
-	// the last spotted dependency
+	// the last spotted dependency, the parent of the next deeper one. A skipped dependency has no
+	// parent for its own dependencies, which are skipped too
+	const skipped = "\x00skipped"
 	var last string
 	// the current parent
 	var parents []string
@@ -72,7 +118,6 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 			if len(split) != 2 {
 				return depInfo{}, fmt.Errorf("Parse error %v on : %q", len(split), line)
 			}
-			current := split[1]
 
 			depth := (strings.Index(line, "---") - 1) / 4
 			if len(parents) > depth {
@@ -81,9 +126,25 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 				parents = append(parents, last)
 			}
 			parents = parents[:depth]
+
+			// "" is the parent of the root dependencies
+			parent := ""
 			if len(parents) > 0 {
-				cp := parents[len(parents)-1]
-				deps[cp] = append(deps[cp], current)
+				parent = parents[len(parents)-1]
+			}
+
+			current, isProject := parseDepNotation(split[1])
+			if isProject {
+				last = parent
+				continue
+			}
+			if current == "" || parent == skipped {
+				last = skipped
+				continue
+			}
+
+			if parent != "" {
+				deps[parent] = helper.AppendUnique(deps[parent], current)
 			} else {
 				rootDeps[current] = true
 			}
@@ -121,6 +182,12 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 // prefix output with spdx-repo as a parsing hint. Gradle builds can print out whatever they
 // want during "configuration" phase.
 var initRepos = `
+gradle.settingsEvaluated { settings ->
+  // the repositories of the whole build, gradle 6.8+ settings.gradle(.kts) dependencyResolutionManagement
+  if (settings.hasProperty('dependencyResolutionManagement')) {
+    settings.dependencyResolutionManagement.repositories.each { println "spdx-repo:" + it.url }
+  }
+}
 gradle.allprojects {
   tasks.register('spdxPrintRepos') {
     doLast {
@@ -131,8 +198,8 @@ gradle.allprojects {
 `
 
 // collect all dependency repositories in order
-func getRepositories(dir string) ([]string, error) {
-	return repositories(dir, initRepos)
+func getRepositories(ctx context.Context, dir string, offline bool) ([]string, error) {
+	return repositories(ctx, dir, initRepos, offline)
 }
 
 var initBuildRepos = `
@@ -146,12 +213,12 @@ gradle.allprojects {
 `
 
 // TODO: this doesn't differentiate between "plugin" repos and "buildscript" repos,
-func getBuildRepositories(dir string) ([]string, error) {
-	return repositories(dir, initBuildRepos)
+func getBuildRepositories(ctx context.Context, dir string, offline bool) ([]string, error) {
+	return repositories(ctx, dir, initBuildRepos, offline)
 }
 
 // inject an initscript to print out all repositories
-func repositories(dir string, initContents string) ([]string, error) {
+func repositories(ctx context.Context, dir string, initContents string, offline bool) ([]string, error) {
 	initFile, err := ioutil.TempFile("", "*-spdx-init.gradle")
 	if err != nil {
 		return nil, err
@@ -164,7 +231,7 @@ func repositories(dir string, initContents string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	out, err := newGradleExec(dir).run(":spdxPrintRepos", "--init-script", initPath, "-q").CombinedOutput()
+	out, err := newGradleExec(ctx, dir, offline).run(":spdxPrintRepos", "--init-script", initPath, "-q").CombinedOutput()
 	if err != nil {
 		log.Println(string(out))
 	}
@@ -191,7 +258,7 @@ func parseRepoOutput(out []byte) ([]string, error) {
 	return result, nil
 }
 
-// groupId, artifactId, version
+// groupId, artifactId, version, an artifact only notation suffix (e.g. @aar) is dropped
 func splitDep(dep string) (string, string, string, error) {
 	parts := strings.SplitN(dep, ":", 3)
 	if len(parts) != 3 {
@@ -200,9 +267,43 @@ func splitDep(dep string) (string, string, string, error) {
 	groupId := parts[0]
 	artifactId := parts[1]
 	version := parts[2]
+	if idx := strings.LastIndex(version, "@"); idx >= 0 {
+		version = version[:idx]
+	}
 	return groupId, artifactId, version, nil
 }
 
+// depExtension returns the extension of an artifact only notation, e.g. aar for group:artifact:1.0@aar
+func depExtension(dep string) string {
+	idx := strings.LastIndex(dep, "@")
+	if idx < 0 || strings.Contains(dep[idx:], ":") {
+		return ""
+	}
+	return dep[idx+1:]
+}
+
+// withExtension returns the dependency notation with an explicit extension
+func withExtension(dep, ext string) string {
+	if depExtension(dep) != "" || ext == "" {
+		return dep
+	}
+	return dep + "@" + ext
+}
+
+// buildPurl returns the package url of a dependency, non jar artifacts carry their type
+func buildPurl(dep string) (string, error) {
+	groupId, artifactId, version, err := splitDep(dep)
+	if err != nil {
+		return "", err
+	}
+
+	p := purl.New("maven", groupId, artifactId, version)
+	if ext := depExtension(dep); ext != "jar" {
+		p = p.WithQualifier("type", ext)
+	}
+	return p.String(), nil
+}
+
 // returns the path to a jar for a dependency for any valid repository
 // append this to a repository url to get a dependency location
 func calculateURLSuffix(dep string) (string, error) {
@@ -215,7 +316,9 @@ func calculateURLSuffix(dep string) (string, error) {
 	artifactName := artifactId + "-" + version
 	// gradle plugins are pom pointing to jar, this is a simple hueristic to
 	// handle that. It might not cover all cases though
-	if strings.HasSuffix(artifactId, "gradle.plugin") {
+	if ext := depExtension(dep); ext != "" {
+		artifactName += "." + ext
+	} else if strings.HasSuffix(artifactId, "gradle.plugin") {
 		artifactName += ".pom"
 	} else {
 		artifactName += ".jar"
@@ -235,13 +338,23 @@ func mergeURL(base, suffix string) (string, error) {
 	return url.String(), nil
 }
 
-func findDownloadLocations(repos []string, deps []string) (map[string]string, error) {
+// findDownloadLocations returns the first repository serving each dependency. Offline the repositories
+// are not checked, the first one declared is assumed
+func findDownloadLocations(repos []string, deps []string, offline bool) (map[string]string, error) {
 	depUrls := map[string]string{}
 	for _, dep := range deps {
 		suffix, err := calculateURLSuffix(dep)
 		if err != nil {
 			return nil, err
 		}
+		if offline && len(repos) > 0 {
+			remote, err := mergeURL(repos[0], suffix)
+			if err != nil {
+				return nil, err
+			}
+			depUrls[dep] = remote
+			continue
+		}
 		for _, repo := range repos {
 			remote, err := mergeURL(repo, suffix)
 			if err != nil {
@@ -98,6 +98,69 @@ func TestParseDependencyOutput(t *testing.T) {
 
 }
 
+func TestParseDependencyOutputMultiProject(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/dependencies-kts.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	di, err := parseDependencyOutput(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the dependencies of the :core project the app depends on are root dependencies, the versions
+	// are the ones gradle selected, constraints and unresolved dependencies are skipped
+	{
+		want := map[string][]string{
+			"androidx.core:core-ktx:1.9.0": {
+				"androidx.annotation:annotation:1.5.0",
+				"org.jetbrains.kotlin:kotlin-stdlib:1.8.20",
+			},
+			"androidx.annotation:annotation:1.5.0":       {},
+			"org.jetbrains.kotlin:kotlin-stdlib:1.8.20":  {},
+			"com.squareup.okhttp3:okhttp:4.11.0":         {"com.squareup.okio:okio:3.2.0"},
+			"com.squareup.okio:okio:3.2.0":               {},
+			"com.squareup.okhttp3:okhttp-bom:4.11.0":     {},
+			"com.google.code.findbugs:annotations:3.0.1": {},
+		}
+		if reflect.DeepEqual(di.graph, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", di.graph, want)
+		}
+	}
+	{
+		want := []string{
+			"androidx.core:core-ktx:1.9.0",
+			"com.google.code.findbugs:annotations:3.0.1",
+			"com.squareup.okhttp3:okhttp-bom:4.11.0",
+			"com.squareup.okhttp3:okhttp:4.11.0",
+			"org.jetbrains.kotlin:kotlin-stdlib:1.8.20",
+		}
+		sorted := di.root
+		sort.Strings(sorted)
+		if reflect.DeepEqual(sorted, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", sorted, want)
+		}
+	}
+}
+
+func TestParseDepNotation(t *testing.T) {
+	for dep, want := range map[string]string{
+		"com.google.guava:guava:28.2-jre":                                        "com.google.guava:guava:28.2-jre",
+		"androidx.core:core-ktx:{strictly 1.9.0} -> 1.9.0 (*)":                   "androidx.core:core-ktx:1.9.0",
+		"com.squareup.okhttp3:okhttp -> 4.11.0":                                  "com.squareup.okhttp3:okhttp:4.11.0",
+		"com.squareup.okhttp3:okhttp:4.11.0 (c)":                                 "",
+		"com.example:missing:1.0 FAILED":                                         "",
+		"javax.inject:javax.inject:1 -> jakarta.inject:jakarta.inject-api:2.0.1": "jakarta.inject:jakarta.inject-api:2.0.1",
+	} {
+		if got, isProject := parseDepNotation(dep); got != want || isProject {
+			t.Fatalf("\n got: %q %v\nwant: %q", got, isProject, want)
+		}
+	}
+	if _, isProject := parseDepNotation("project :core"); !isProject {
+		t.Fatal("want a project dependency")
+	}
+}
+
 func TestParseRepoOutput(t *testing.T) {
 	data, err := ioutil.ReadFile("testdata/repositories.out")
 	if err != nil {
@@ -141,7 +204,7 @@ func TestCalculateURLSuffixPlugin(t *testing.T) {
 func TestFindDownloadLocations(t *testing.T) {
 	repos := []string{"https://repo.maven.apache.org/maven2", "https://plugins.gradle.org/m2"}
 	deps := []string{"com.google.guava:guava:10.0", "com.google.cloud.tools:com.google.cloud.tools.jib.gradle.plugin:1.0.0"}
-	locs, err := findDownloadLocations(repos, deps)
+	locs, err := findDownloadLocations(repos, deps, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -153,3 +216,22 @@ func TestFindDownloadLocations(t *testing.T) {
 		t.Fatalf("\n got: %v\nwant: %v", locs, want)
 	}
 }
+
+func TestFindDownloadLocationsOffline(t *testing.T) {
+	repos := []string{"https://repo.maven.apache.org/maven2", "https://plugins.gradle.org/m2"}
+	locs, err := findDownloadLocations(repos, []string{"com.google.guava:guava:10.0"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"com.google.guava:guava:10.0": "https://repo.maven.apache.org/maven2/com/google/guava/guava/10.0/guava-10.0.jar",
+	}
+	if reflect.DeepEqual(locs, want) == false {
+		t.Fatalf("\n got: %v\nwant: %v", locs, want)
+	}
+
+	checksum, err := artifactChecksum("com.google.guava:guava:10.0", want["com.google.guava:guava:10.0"], verifiedChecksums{}, cachedArtifact{}, false, true)
+	if err != nil || checksum != nil {
+		t.Fatalf("unexpected checksum %+v, error %v", checksum, err)
+	}
+}
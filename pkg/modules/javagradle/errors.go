@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"errors"
+)
+
+var (
+	errLockfileNotFound = errors.New("unable to generate SPDX file, no gradle.lockfile found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `gradle dependencies --write-locks`")
+)
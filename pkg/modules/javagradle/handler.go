@@ -3,6 +3,7 @@
 package javagradle
 
 import (
+	"context"
 	"fmt"
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
@@ -15,6 +16,7 @@ type gradle struct {
 	metadata models.PluginMetadata
 	ge       gradleExec
 	basepath string
+	options  models.PluginOptions
 }
 
 func New() *gradle {
@@ -22,19 +24,24 @@ func New() *gradle {
 		metadata: models.PluginMetadata{
 			Name:       "Java Gradle",
 			Slug:       "Java-Gradle",
-			Manifest:   []string{"build.gradle", "settings.gradle"},
+			Manifest:   []string{"build.gradle", "settings.gradle", "build.gradle.kts", "settings.gradle.kts"},
 			ModulePath: []string{"."},
 		},
 	}
 }
 
+// SetOptions ...
+func (m *gradle) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 func (m *gradle) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
 func (m *gradle) SetRootModule(path string) error {
 	m.basepath = path
-	m.ge = newGradleExec(path)
+	m.ge = newGradleExec(m.options.Context, path, m.options.Offline)
 	return nil
 }
 
@@ -69,7 +76,11 @@ func (m *gradle) ListUsedModules(path string) ([]models.Module, error) {
 }
 
 func (m *gradle) ListModulesWithDeps(path string) ([]models.Module, error) {
-	pi, err := getProjectInfo(path)
+	if m.options.LockfileOnly {
+		return listLockedModules(m.options.Context, path)
+	}
+
+	pi, err := getProjectInfo(m.options.Context, path, m.options.Offline)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +95,7 @@ func (m *gradle) ListModulesWithDeps(path string) ([]models.Module, error) {
 		Modules: make(map[string]*models.Module),
 	}
 	// mediocre effort to read git info
-	origin, sha1, err := getGitInfo(path)
+	origin, sha1, err := getGitInfo(m.options.Context, path)
 	if err != nil {
 		rootModule.CheckSum = &models.CheckSum{
 			Algorithm: models.HashAlgorithm("None"),
@@ -97,32 +108,56 @@ func (m *gradle) ListModulesWithDeps(path string) ([]models.Module, error) {
 		}
 		rootModule.PackageDownloadLocation = origin
 	}
-	all, err := getDependencyModules(rootModule, path)
+	all, err := getDependencyModules(m.options.Context, rootModule, path, m.options.Offline)
 	if err != nil {
 		return nil, err
 	}
 	return all, nil
 }
 
-func getDependencyModules(project models.Module, path string) ([]models.Module, error) {
+func getDependencyModules(ctx context.Context, project models.Module, path string, offline bool) ([]models.Module, error) {
 	modsMap := map[string]*models.Module{}
 	mods := []models.Module{project}
 
-	deps, err := getDependencies(path)
+	deps, err := getDependencies(ctx, path, offline)
 	if err != nil {
 		return nil, err
 	}
-	repos, err := getRepositories(path)
+	repos, err := getRepositories(ctx, path, offline)
 	if err != nil {
 		return nil, err
 	}
-	depLoc, err := findDownloadLocations(repos, deps.all)
+
+	// resolve the artifact type from the gradle cache, Android libraries are aar archives
+	gradleHome := gradleUserHome()
+	cached := map[string]cachedArtifact{}
+	artifacts := make([]string, 0, len(deps.all))
+	for _, dep := range deps.all {
+		artifact := dep
+		if c, ok := findCachedArtifact(gradleHome, dep); ok {
+			cached[dep] = c
+			artifact = withExtension(dep, c.extension)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	depLoc, err := findDownloadLocations(repos, artifacts, offline)
 	if err != nil {
 		return nil, err
 	}
 
-	for dep, remote := range depLoc {
-		mod, err := generateModule(dep, remote)
+	verified, err := readVerificationMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, dep := range deps.all {
+		c, ok := cached[dep]
+		checksum, err := artifactChecksum(artifacts[i], depLoc[artifacts[i]], verified, c, ok, offline)
+		if err != nil {
+			return nil, err
+		}
+		mod, err := generateModule(artifacts[i], depLoc[artifacts[i]], checksum)
 		if err != nil {
 			return nil, err
 		}
@@ -158,17 +193,44 @@ func getDependencyModules(project models.Module, path string) ([]models.Module,
 	return mods, nil
 }
 
+// artifactChecksum returns the checksum of the dependency verification metadata, then the one of the
+// cached artifact, then the remote one. Offline an artifact missing from the cache has no checksum
+func artifactChecksum(name, depURL string, verified verifiedChecksums, cached cachedArtifact, isCached, offline bool) (*models.CheckSum, error) {
+	if checksum, ok := verified.lookup(name); ok {
+		return checksum, nil
+	}
+
+	if !isCached && offline {
+		log.Printf("%s is not in the gradle cache, its checksum is not fetched offline", name)
+		return nil, nil
+	}
+
+	sha1 := cached.sha1
+	if !isCached {
+		var err error
+		sha1, err = getSHA1(depURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &models.CheckSum{
+		Algorithm: models.HashAlgoSHA1,
+		Value:     sha1,
+	}, nil
+}
+
 // generate gradle dependency module (non-root)
-func generateModule(name, depURL string) (models.Module, error) {
+func generateModule(name, depURL string, checksum *models.CheckSum) (models.Module, error) {
 	mod := models.Module{}
 	groupId, artifactId, version, err := splitDep(name)
 	if err != nil {
 		return mod, err
 	}
-	sha1, err := getSHA1(depURL)
+	purl, err := buildPurl(name)
 	if err != nil {
 		return mod, err
 	}
+	mod.PackageURL = purl
 	mod.Supplier = models.SupplierContact{
 		Type: "Group Id",
 		Name: groupId,
@@ -176,10 +238,7 @@ func generateModule(name, depURL string) (models.Module, error) {
 	mod.Name = artifactId
 	mod.Version = version
 	mod.PackageDownloadLocation = depURL
-	mod.CheckSum = &models.CheckSum{
-		Algorithm: models.HashAlgoSHA1,
-		Value:     sha1,
-	}
+	mod.CheckSum = checksum
 	mod.Modules = make(map[string]*models.Module)
 	mod.Root = false
 
@@ -187,6 +246,14 @@ func generateModule(name, depURL string) (models.Module, error) {
 }
 
 func (m *gradle) HasModulesInstalled(path string) error {
+	// the lock files and the version catalog are read without gradle
+	if m.options.LockfileOnly {
+		if !hasLockfiles(path) && !hasVersionCatalog(path) {
+			return errLockfileNotFound
+		}
+		return nil
+	}
+
 	// check if root has gradlew wrapper script
 	if hasGradlew(path) {
 		return nil
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+const (
+	// lockfileName locks the dependencies of every configuration of the project, since Gradle 6.8
+	lockfileName = "gradle.lockfile"
+	// buildscriptLockfileName locks the classpath of the build script, i.e. the plugins
+	buildscriptLockfileName = "buildscript-gradle.lockfile"
+	// propertiesFile holds the group and the version of the project
+	propertiesFile = "gradle.properties"
+)
+
+// legacyLockDir holds a lock file per configuration, named after it, before Gradle 6.8
+var legacyLockDir = filepath.Join("gradle", "dependency-locks")
+
+// rootProjectNameRegex matches the project name of a settings.gradle or settings.gradle.kts, e.g. rootProject.name = 'app'
+var rootProjectNameRegex = regexp.MustCompile(`rootProject\.name\s*=\s*['"]([^'"]+)['"]`)
+
+// lockedDependency is a dependency of the lock files and the configurations locking it
+type lockedDependency struct {
+	Coordinate     string
+	Configurations []string
+	// Buildscript is set for the dependencies of the build script
+	Buildscript bool
+}
+
+// relationship returns the relationship of the dependency to the project: the build script dependencies are
+// BUILD_TOOL_OF it and the ones only the test configurations lock DEV_DEPENDENCY_OF it
+func (d lockedDependency) relationship() models.RelationshipType {
+	if d.Buildscript {
+		return models.RelationshipBuildToolOf
+	}
+	for _, configuration := range d.Configurations {
+		if !strings.HasPrefix(configuration, "test") && !strings.Contains(configuration, "Test") {
+			return ""
+		}
+	}
+	return models.RelationshipDevDependencyOf
+}
+
+// hasLockfiles tells whether the dependencies of the project are locked
+func hasLockfiles(path string) bool {
+	if helper.Exists(filepath.Join(path, lockfileName)) {
+		return true
+	}
+	legacy, _ := filepath.Glob(filepath.Join(path, legacyLockDir, "*.lockfile"))
+	return len(legacy) > 0
+}
+
+// readLockfile reads the dependencies of a lock file: group:artifact:version=configuration,... lines. A legacy lock
+// file has no configurations, the one given is the one it is named after. The empty= line lists the
+// configurations without dependencies
+func readLockfile(fileName, configuration string, dependencies map[string]*lockedDependency) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "empty=") {
+			continue
+		}
+
+		coordinate, configurations := line, []string{configuration}
+		if i := strings.Index(line, "="); i >= 0 {
+			coordinate, configurations = line[:i], strings.Split(line[i+1:], ",")
+		}
+		if strings.Count(coordinate, ":") != 2 {
+			return reader.MalformedError(fileName, number, "expected group:artifact:version")
+		}
+
+		dependency, ok := dependencies[coordinate]
+		if !ok {
+			dependency = &lockedDependency{Coordinate: coordinate}
+			dependencies[coordinate] = dependency
+		}
+		dependency.Configurations = append(dependency.Configurations, configurations...)
+	}
+	return scanner.Err()
+}
+
+// readLockfiles returns the dependencies the lock files of the project lock, sorted by coordinate. The libraries
+// of the version catalog the lock files do not lock any version of are added with their catalog version
+func readLockfiles(path string) ([]lockedDependency, error) {
+	dependencies := map[string]*lockedDependency{}
+	if fileName := filepath.Join(path, lockfileName); helper.Exists(fileName) {
+		if err := readLockfile(fileName, "", dependencies); err != nil {
+			return nil, err
+		}
+	}
+	legacy, _ := filepath.Glob(filepath.Join(path, legacyLockDir, "*.lockfile"))
+	for _, fileName := range legacy {
+		if err := readLockfile(fileName, strings.TrimSuffix(filepath.Base(fileName), ".lockfile"), dependencies); err != nil {
+			return nil, err
+		}
+	}
+
+	buildscript := map[string]*lockedDependency{}
+	if fileName := filepath.Join(path, buildscriptLockfileName); helper.Exists(fileName) {
+		if err := readLockfile(fileName, "classpath", buildscript); err != nil {
+			return nil, err
+		}
+	}
+	for coordinate, dependency := range buildscript {
+		if _, ok := dependencies[coordinate]; !ok {
+			dependency.Buildscript = true
+			dependencies[coordinate] = dependency
+		}
+	}
+
+	catalog, err := readCatalogDependencies(path)
+	if err != nil {
+		return nil, err
+	}
+	lockedModules := map[string]bool{}
+	for coordinate := range dependencies {
+		lockedModules[coordinate[:strings.LastIndex(coordinate, ":")]] = true
+	}
+	for coordinate, dependency := range catalog {
+		if !lockedModules[coordinate[:strings.LastIndex(coordinate, ":")]] {
+			dependencies[coordinate] = dependency
+		}
+	}
+
+	var locked []lockedDependency
+	for _, dependency := range dependencies {
+		locked = append(locked, *dependency)
+	}
+	sort.Slice(locked, func(i, j int) bool { return locked[i].Coordinate < locked[j].Coordinate })
+	return locked, nil
+}
+
+// readProjectFiles returns the project as its settings and properties files name it, the directory names it
+// when the settings do not
+func readProjectFiles(path string) (projectInfo, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return projectInfo{}, err
+	}
+
+	pi := projectInfo{name: filepath.Base(absPath)}
+	for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+		data, err := ioutil.ReadFile(filepath.Join(absPath, settings))
+		if err != nil {
+			continue
+		}
+		if match := rootProjectNameRegex.FindSubmatch(data); match != nil {
+			pi.name = string(match[1])
+			break
+		}
+	}
+
+	file, err := os.Open(filepath.Join(absPath, propertiesFile))
+	if os.IsNotExist(err) {
+		return pi, nil
+	}
+	if err != nil {
+		return projectInfo{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := propertyLine(scanner.Text())
+		switch {
+		case !ok:
+		case key == "version":
+			pi.version = value
+		case key == "group":
+			pi.group = value
+		}
+	}
+	return pi, scanner.Err()
+}
+
+// propertyLine splits a line of a properties file into its key and value
+func propertyLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		return "", "", false
+	}
+	i := strings.IndexAny(line, "=:")
+	if i < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+// listLockedModules returns the project followed by the dependencies its lock files lock, without gradle. The
+// lock files do not tell which dependency requires which, so the project depends on all of them. The
+// checksums are the ones of the dependency verification metadata or of the gradle cache
+func listLockedModules(ctx context.Context, path string) ([]models.Module, error) {
+	pi, err := readProjectFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := readLockfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := readVerificationMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := models.Module{
+		Name:    pi.name,
+		Version: pi.version,
+		Supplier: models.SupplierContact{
+			Type: "Group Id",
+			Name: pi.group,
+		},
+		Root:    true,
+		Modules: make(map[string]*models.Module),
+	}
+	if origin, sha1, err := getGitInfo(ctx, path); err == nil {
+		root.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: sha1}
+		root.PackageDownloadLocation = origin
+	}
+
+	gradleHome := gradleUserHome()
+	modules := []models.Module{root}
+	for _, dependency := range locked {
+		artifact := dependency.Coordinate
+		c, isCached := findCachedArtifact(gradleHome, dependency.Coordinate)
+		if isCached {
+			artifact = withExtension(artifact, c.extension)
+		}
+		checksum, err := artifactChecksum(artifact, "", verified, c, isCached, true)
+		if err != nil {
+			return nil, err
+		}
+		mod, err := generateModule(artifact, "", checksum)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, mod)
+
+		linked := mod
+		linked.Relationship = dependency.relationship()
+		// the artifact ids of different groups may be the same, the coordinate keeps them apart
+		modules[0].Modules[dependency.Coordinate] = &linked
+	}
+	return modules, nil
+}
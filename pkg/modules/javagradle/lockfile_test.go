@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// lockfileOnly returns the plugin reading the lockfiles, with the gradle cache of the testdata
+func lockfileOnly(t *testing.T) *gradle {
+	os.Setenv("GRADLE_USER_HOME", filepath.Join("testdata", "gradle-home"))
+	t.Cleanup(func() {
+		os.Unsetenv("GRADLE_USER_HOME")
+	})
+
+	m := New()
+	m.SetOptions(models.PluginOptions{Offline: true, LockfileOnly: true})
+	return m
+}
+
+func TestListLockedModules(t *testing.T) {
+	m := lockfileOnly(t)
+	path := filepath.Join("testdata", "locked")
+
+	if err := m.SetRootModule(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.HasModulesInstalled(path); err != nil {
+		t.Fatal(err)
+	}
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 5 {
+		t.Fatalf("got %d modules, want 5", len(modules))
+	}
+
+	root := modules[0]
+	if root.Name != "shop" || root.Version != "1.2.0" || root.Supplier.Name != "com.example" || !root.Root {
+		t.Fatalf("unexpected root module %+v", root)
+	}
+
+	want := map[string]models.RelationshipType{
+		"com.diffplug.spotless:spotless-plugin-gradle:6.11.0": models.RelationshipBuildToolOf,
+		"com.google.guava:failureaccess:1.0.1":                "",
+		"com.google.guava:guava:30.1-jre":                     "",
+		"junit:junit:4.13.2":                                  models.RelationshipDevDependencyOf,
+	}
+	if len(root.Modules) != len(want) {
+		t.Fatalf("root depends on %d modules, want %d", len(root.Modules), len(want))
+	}
+	for coordinate, relationship := range want {
+		mod, ok := root.Modules[coordinate]
+		if !ok {
+			t.Fatalf("root does not depend on %s", coordinate)
+		}
+		if mod.Relationship != relationship {
+			t.Fatalf("%s: got relationship %q, want %q", coordinate, mod.Relationship, relationship)
+		}
+	}
+
+	guava := modules[3]
+	if guava.Name != "guava" || guava.Version != "30.1-jre" {
+		t.Fatalf("unexpected module %+v", guava)
+	}
+	if guava.CheckSum == nil || guava.CheckSum.Value != "1c8d4f0b4a2ffb2c4a1cbd8ba98bd5cb84a0c1e7" {
+		t.Fatalf("unexpected guava checksum %+v", guava.CheckSum)
+	}
+	if junit := modules[4]; junit.CheckSum != nil {
+		t.Fatalf("junit is not cached, got checksum %+v", junit.CheckSum)
+	}
+}
+
+func TestListLockedModulesLegacy(t *testing.T) {
+	lockfileOnly(t)
+
+	modules, err := listLockedModules(context.Background(), filepath.Join("testdata", "legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 3 || modules[0].Name != "legacy" {
+		t.Fatalf("unexpected modules %+v", modules)
+	}
+	if rel := modules[0].Modules["org.mockito:mockito-core:3.6.0"].Relationship; rel != models.RelationshipDevDependencyOf {
+		t.Fatalf("mockito: got relationship %q", rel)
+	}
+	if rel := modules[0].Modules["org.slf4j:slf4j-api:1.7.30"].Relationship; rel != "" {
+		t.Fatalf("slf4j: got relationship %q", rel)
+	}
+}
+
+func TestLockfileNotFound(t *testing.T) {
+	if err := lockfileOnly(t).HasModulesInstalled(filepath.Join("testdata", "verification")); err != errLockfileNotFound {
+		t.Fatalf("got %v, want %v", err, errLockfileNotFound)
+	}
+}
+
+func TestReadLockfileMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), lockfileName)
+	if err := ioutil.WriteFile(path, []byte("com.google.guava:guava=compileClasspath\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readLockfiles(filepath.Dir(path)); !errors.Is(err, reader.ErrMalformedFile) {
+		t.Fatalf("got %v, want a malformed file error", err)
+	}
+}
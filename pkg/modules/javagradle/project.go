@@ -5,9 +5,11 @@ package javagradle
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 )
 
 type projectInfo struct {
@@ -17,8 +19,8 @@ type projectInfo struct {
 }
 
 // returns name, version
-func getProjectInfo(path string) (projectInfo, error) {
-	cmd := newGradleExec(path).run("properties", "-q")
+func getProjectInfo(ctx context.Context, path string, offline bool) (projectInfo, error) {
+	cmd := newGradleExec(ctx, path, offline).run("properties", "-q")
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return projectInfo{}, err
@@ -69,12 +71,12 @@ func parseProjectInfo(out []byte) (projectInfo, error) {
 
 // origin, hash
 // perhaps this can be moved to util
-func getGitInfo(path string) (string, string, error) {
-	sha, err := exec.Command("git", "describe", `--match=""`, "--always", "--abbrev=40", "--dirty").Output()
+func getGitInfo(ctx context.Context, path string) (string, string, error) {
+	sha, err := helper.CommandContext(ctx, "git", "describe", `--match=""`, "--always", "--abbrev=40", "--dirty").Output()
 	if err != nil {
 		return "", "", err
 	}
-	origin, err := exec.Command("git", "config", "--get", "remote.origin.url").Output()
+	origin, err := helper.CommandContext(ctx, "git", "config", "--get", "remote.origin.url").Output()
 	if err != nil {
 		return "", "", err
 	}
@@ -0,0 +1,15 @@
+plugins {
+    alias(libs.plugins.kotlin.jvm)
+}
+
+dependencies {
+    implementation(libs.kotlin.stdlib)
+    implementation(libs.okhttp)
+    implementation(libs.guava.get())
+    implementation(libs.slf4j.api)
+    testImplementation(libs.bundles.junit)
+}
+
+kotlin {
+    jvmToolchain(libs.versions.kotlin.get().toInt())
+}
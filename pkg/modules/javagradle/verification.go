@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// verificationMetadataFile is the dependency verification file of the build, written by
+// gradle --write-verification-metadata sha256
+var verificationMetadataFile = filepath.Join("gradle", "verification-metadata.xml")
+
+type verificationMetadata struct {
+	Components []verificationComponent `xml:"components>component"`
+}
+
+type verificationComponent struct {
+	Group     string                 `xml:"group,attr"`
+	Name      string                 `xml:"name,attr"`
+	Version   string                 `xml:"version,attr"`
+	Artifacts []verificationArtifact `xml:"artifact"`
+}
+
+type verificationArtifact struct {
+	Name   string                 `xml:"name,attr"`
+	SHA512 []verificationChecksum `xml:"sha512"`
+	SHA256 []verificationChecksum `xml:"sha256"`
+	SHA1   []verificationChecksum `xml:"sha1"`
+}
+
+type verificationChecksum struct {
+	Value string `xml:"value,attr"`
+}
+
+// verifiedChecksums are the checksums of the verification metadata keyed by group:artifact:version/file
+type verifiedChecksums map[string]*models.CheckSum
+
+// readVerificationMetadata reads the checksums of the dependency verification file of the project,
+// none when the build does not verify its dependencies
+func readVerificationMetadata(dir string) (verifiedChecksums, error) {
+	fileName := filepath.Join(dir, verificationMetadataFile)
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return verifiedChecksums{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata verificationMetadata
+	if err := reader.DecodeXML(fileName, data, &metadata); err != nil {
+		return nil, err
+	}
+
+	checksums := verifiedChecksums{}
+	for _, component := range metadata.Components {
+		for _, artifact := range component.Artifacts {
+			if checksum := artifact.checksum(); checksum != nil {
+				checksums[component.Group+":"+component.Name+":"+component.Version+"/"+artifact.Name] = checksum
+			}
+		}
+	}
+	return checksums, nil
+}
+
+// checksum returns the strongest checksum of the artifact, Gradle trusts any of them
+func (a verificationArtifact) checksum() *models.CheckSum {
+	candidates := []struct {
+		algorithm models.HashAlgorithm
+		values    []verificationChecksum
+	}{
+		{models.HashAlgoSHA512, a.SHA512},
+		{models.HashAlgoSHA256, a.SHA256},
+		{models.HashAlgoSHA1, a.SHA1},
+	}
+
+	for _, candidate := range candidates {
+		for _, value := range candidate.values {
+			if value := strings.ToLower(strings.TrimSpace(value.Value)); value != "" {
+				return &models.CheckSum{Algorithm: candidate.algorithm, Value: value}
+			}
+		}
+	}
+	return nil
+}
+
+// lookup returns the verified checksum of a dependency notation, e.g. group:artifact:1.0@aar
+func (v verifiedChecksums) lookup(dep string) (*models.CheckSum, bool) {
+	groupId, artifactId, version, err := splitDep(dep)
+	if err != nil {
+		return nil, false
+	}
+	suffix, err := calculateURLSuffix(dep)
+	if err != nil {
+		return nil, false
+	}
+
+	checksum, ok := v[groupId+":"+artifactId+":"+version+"/"+path.Base(suffix)]
+	return checksum, ok
+}
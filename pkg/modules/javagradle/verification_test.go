@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestReadVerificationMetadata(t *testing.T) {
+	verified, err := readVerificationMetadata(filepath.Join("testdata", "verification"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, ok := verified.lookup("androidx.appcompat:appcompat:1.3.0@aar")
+	if !ok {
+		t.Fatal("aar artifact not found in verification metadata")
+	}
+	if checksum.Algorithm != models.HashAlgoSHA256 || checksum.Value != "2a1f6b8c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8" {
+		t.Fatalf("unexpected checksum %+v", checksum)
+	}
+
+	checksum, ok = verified.lookup("com.google.guava:guava:30.1-jre")
+	if !ok || checksum.Algorithm != models.HashAlgoSHA1 {
+		t.Fatalf("unexpected checksum %+v", checksum)
+	}
+
+	if _, ok := verified.lookup("androidx.appcompat:appcompat:1.3.0@jar"); ok {
+		t.Fatal("jar artifact should not match the verified aar")
+	}
+}
+
+func TestReadVerificationMetadataMissing(t *testing.T) {
+	verified, err := readVerificationMetadata(filepath.Join("testdata", "gradle-home"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verified) != 0 {
+		t.Fatalf("unexpected checksums %+v", verified)
+	}
+}
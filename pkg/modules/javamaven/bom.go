@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/vifraa/gopom"
+)
+
+// isBomImport checks whether the managed dependency imports the dependencyManagement of a BOM
+func isBomImport(dependency gopom.Dependency) bool {
+	return dependency.Scope == "import" && dependency.Type == "pom"
+}
+
+// importBoms replaces the BOM imports of the managed dependencies by the dependencies the BOMs manage.
+// The ones declared in the poms come first so they win over the imported ones, and a BOM imported
+// earlier wins over a later one, as in Maven. BOMs are read from the local repository
+func importBoms(managed []gopom.Dependency, project gopom.Project, imported map[string]bool) []gopom.Dependency {
+	var declared, fromBoms []gopom.Dependency
+	for _, dependency := range managed {
+		if !isBomImport(dependency) {
+			declared = append(declared, dependency)
+			continue
+		}
+		fromBoms = append(fromBoms, readBom(dependency, project, imported)...)
+	}
+
+	return append(declared, fromBoms...)
+}
+
+// readBom returns the managed dependencies of the imported BOM with their versions resolved against
+// the properties of the BOM, nothing when the BOM is not in the local repository
+func readBom(dependency gopom.Dependency, project gopom.Project, imported map[string]bool) []gopom.Dependency {
+	coordinate := mavenCoordinate{
+		GroupID:    resolveProperty(dependency.GroupID, project),
+		ArtifactID: dependency.ArtifactID,
+		Version:    resolveProperty(dependency.Version, project),
+		Type:       "pom",
+	}
+	key := coordinate.GroupID + ":" + coordinate.ArtifactID + ":" + coordinate.Version
+	if imported[key] {
+		return nil
+	}
+	imported[key] = true
+
+	if localRepository == "" || coordinate.GroupID == "" || coordinate.Version == "" {
+		return nil
+	}
+
+	bom, err := readPom(filepath.Join(localRepository, filepath.FromSlash(artifactPath(coordinate, nil))))
+	if err != nil {
+		log.Printf("BOM %s not found, the versions it manages are not imported", key)
+		return nil
+	}
+
+	bom, managed := mergeParents("", bom, imported)
+	resolved := make([]gopom.Dependency, len(managed))
+	for i, dependency := range managed {
+		resolved[i] = dependency
+		resolved[i].GroupID = resolveProperty(dependency.GroupID, bom)
+		resolved[i].Version = resolveProperty(dependency.Version, bom)
+	}
+
+	return resolved
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAndLoadPomFileImportsBoms(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "bom"), mavenOptions{})
+	assert.NoError(t, err)
+
+	versions := map[string]string{}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		versions[mod.Name] = mod.Version
+	}
+	assert.Equal(t, map[string]string{
+		// declared in the pom, wins over the BOM
+		"slf4j-api": "1.7.30",
+		// a property of the BOM
+		"jackson-databind": "2.12.3",
+		// imported by the imported BOM
+		"netty-handler":     "4.1.65.Final",
+		"example-unmanaged": "",
+	}, versions)
+}
+
+func TestReadAndLoadPomFileWithoutBom(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "missing")
+
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "bom"), mavenOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.7.30", project.Dependencies[0].Version)
+	assert.Equal(t, "", project.Dependencies[1].Version)
+}
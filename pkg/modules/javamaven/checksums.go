@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// CentralRepositoryURL is the default remote repository
+var CentralRepositoryURL = "https://repo.maven.apache.org/maven2"
+
+var sha1Regex = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// remoteRepository is a repository checksums are fetched from, with its credentials if any
+type remoteRepository struct {
+	ID       string
+	URL      string
+	username string
+	password string
+}
+
+// packagingExtensions are the packagings whose artifact is not a file of the same extension,
+// build extensions register more of them, see models.PluginOptions.PackagingExtensions
+var packagingExtensions = map[string]string{
+	"":             "jar",
+	"bundle":       "jar",
+	"maven-plugin": "jar",
+	"ejb":          "jar",
+	"ejb-client":   "jar",
+	"test-jar":     "jar",
+	"java-source":  "jar",
+	"javadoc":      "jar",
+}
+
+// typeClassifiers are the classifiers the dependency types imply when none is declared
+var typeClassifiers = map[string]string{
+	"test-jar":    "tests",
+	"java-source": "sources",
+	"javadoc":     "javadoc",
+	"ejb-client":  "client",
+}
+
+// artifactExtension returns the artifact file extension of a packaging, the user supplied
+// extensions come first and an unknown packaging is its own extension, e.g. war
+func artifactExtension(packaging string, extensions map[string]string) string {
+	if extension := extensions[packaging]; extension != "" {
+		return extension
+	}
+	if extension, ok := packagingExtensions[packaging]; ok {
+		return extension
+	}
+	return packaging
+}
+
+// artifactPath returns the repository path of an artifact file, e.g. org/slf4j/slf4j-api/1.7.30/slf4j-api-1.7.30.jar
+func artifactPath(coordinate mavenCoordinate, extensions map[string]string) string {
+	extension := artifactExtension(coordinate.Type, extensions)
+
+	file := fmt.Sprintf("%s-%s", coordinate.ArtifactID, coordinate.Version)
+	if coordinate.Classifier != "" {
+		file += "-" + coordinate.Classifier
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s.%s", strings.ReplaceAll(coordinate.GroupID, ".", "/"),
+		coordinate.ArtifactID, coordinate.Version, file, extension)
+}
+
+// repositories returns the project repositories then Maven Central, with the credentials
+// of the settings.xml server of the same id. A repository served by a settings.xml mirror is
+// replaced by the mirror, with the credentials of the mirror id, and listed once
+func repositories(project gopom.Project, settings mavenSettings) []remoteRepository {
+	var result []remoteRepository
+	seen := map[string]bool{}
+	add := func(id, url string) {
+		if mirror, ok := settings.mirror(id, url); ok {
+			id, url = mirror.ID, mirror.URL
+		}
+		repository := newRemoteRepository(id, url, settings)
+		if seen[repository.URL] {
+			return
+		}
+		seen[repository.URL] = true
+		result = append(result, repository)
+	}
+
+	for _, repository := range project.Repositories {
+		if repository.URL == "" {
+			continue
+		}
+		add(repository.ID, repository.URL)
+	}
+	add("central", CentralRepositoryURL)
+
+	return result
+}
+
+func newRemoteRepository(id, url string, settings mavenSettings) remoteRepository {
+	repository := remoteRepository{ID: id, URL: strings.TrimSuffix(url, "/")}
+	if server, ok := settings.server(id); ok {
+		repository.username = server.Username
+		repository.password = server.Password
+	}
+	return repository
+}
+
+// checksumFetcher downloads the .sha1 files and the poms published next to the artifacts
+type checksumFetcher struct {
+	client       *http.Client
+	repositories []remoteRepository
+	// types are the dependency types declared in the pom keyed by groupId:artifactId, jar otherwise
+	types map[string]string
+	// extensions are the artifact file extensions of the custom packagings
+	extensions map[string]string
+	// parallelism is the number of modules looked up at once, as many as CPUs when 0
+	parallelism int
+}
+
+func newChecksumFetcher(repositories []remoteRepository) *checksumFetcher {
+	return &checksumFetcher{
+		client:       &http.Client{Timeout: 30 * time.Second},
+		repositories: repositories,
+	}
+}
+
+// fetchSHA1 returns the checksum of the artifact from the first repository publishing it
+func (f *checksumFetcher) fetchSHA1(ctx context.Context, coordinate mavenCoordinate) (string, error) {
+	path := artifactPath(coordinate, f.extensions) + ".sha1"
+	for _, repository := range f.repositories {
+		checksum, err := f.fetch(ctx, repository, path)
+		if err != nil {
+			// never log the credentials, only the repository id and the artifact path
+			log.Debugf("checksum %s not available from repository %s: %v", path, repository.ID, err)
+			continue
+		}
+		return checksum, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", errChecksumNotFound, path)
+}
+
+func (f *checksumFetcher) fetch(ctx context.Context, repository remoteRepository, path string) (string, error) {
+	body, err := f.download(ctx, repository, path)
+	if err != nil {
+		return "", err
+	}
+
+	// the file may be `<sha1>` or `<sha1>  <filename>`, as the ones of the local repository
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || !sha1Regex.MatchString(fields[0]) {
+		return "", errInvalidChecksum
+	}
+
+	return strings.ToLower(fields[0]), nil
+}
+
+// fetchPom returns the pom of the coordinate from the first repository publishing it
+func (f *checksumFetcher) fetchPom(ctx context.Context, coordinate mavenCoordinate) (gopom.Project, error) {
+	coordinate.Type, coordinate.Classifier = "pom", ""
+	path := artifactPath(coordinate, nil)
+	for _, repository := range f.repositories {
+		body, err := f.download(ctx, repository, path)
+		if err != nil {
+			log.Debugf("pom %s not available from repository %s: %v", path, repository.ID, err)
+			continue
+		}
+
+		var project gopom.Project
+		err = xml.Unmarshal(body, &project)
+		return project, err
+	}
+
+	return gopom.Project{}, fmt.Errorf("%w: %s", errPomNotFound, path)
+}
+
+// download returns the file at the path of the repository
+func (f *checksumFetcher) download(ctx context.Context, repository remoteRepository, path string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, repository.URL+"/"+path, nil)
+	if err != nil {
+		return nil, errInvalidRepositoryURL
+	}
+	req = req.WithContext(ctx)
+	if repository.username != "" || repository.password != "" {
+		req.SetBasicAuth(repository.username, repository.password)
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, errRepositoryUnreachable
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", errUnexpectedStatus, res.Status)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
+
+// enrichChecksums sets the checksum published by the repositories on the modules without one, i.e. the
+// ones not in the local repository. The checksums already in the cache are not fetched again, the
+// others are fetched for parallelism modules at once
+func (f *checksumFetcher) enrichChecksums(ctx context.Context, modules []models.Module, c *cache.Cache) {
+	forEachParallel(len(modules), f.parallelism, func(i int) {
+		if modules[i].CheckSum != nil {
+			return
+		}
+		coordinate, ok := moduleCoordinate(modules[i])
+		if !ok {
+			return
+		}
+		if packaging := f.types[modules[i].Path]; packaging != "" {
+			coordinate.Type = packaging
+		}
+
+		key := purl.New("maven", coordinate.GroupID, coordinate.ArtifactID, coordinate.Version).String()
+		if entry, ok := c.Get(key); ok && entry.Checksum != "" {
+			modules[i].CheckSum = &models.CheckSum{
+				Algorithm: models.HashAlgorithm(entry.ChecksumAlgorithm),
+				Value:     entry.Checksum,
+			}
+			return
+		}
+
+		checksum, err := f.fetchSHA1(ctx, coordinate)
+		if err != nil {
+			log.Debug(err)
+			return
+		}
+		entry, _ := c.Get(key)
+		entry.ChecksumAlgorithm, entry.Checksum = string(models.HashAlgoSHA1), checksum
+		if err := c.Put(key, entry); err != nil {
+			log.Warnf("failed to write the resume cache: %v", err)
+		}
+
+		modules[i].CheckSum = &models.CheckSum{
+			Algorithm: models.HashAlgoSHA1,
+			Value:     checksum,
+		}
+	})
+}
+
+// enrichPomMetadata sets the license of the pom published by the repositories, or of its closest parent
+// declaring one, and its home page and scm repository on the dependencies missing them, i.e. the ones whose
+// pom is not in the local repository. The parent poms of the local repository are read first
+func (f *checksumFetcher) enrichPomMetadata(ctx context.Context, modules []models.Module, c *cache.Cache) {
+	local := localPomReader(localRepository)
+	read := func(coordinate mavenCoordinate) (gopom.Project, error) {
+		if project, err := local(coordinate); err == nil {
+			return project, nil
+		}
+		return f.fetchPom(ctx, coordinate)
+	}
+
+	forEachParallel(len(modules), f.parallelism, func(i int) {
+		mod := &modules[i]
+		if mod.Root || (mod.LicenseDeclared != "" && mod.PackageHomePage != "" && mod.SourceInfo != "") {
+			return
+		}
+		coordinate, ok := moduleCoordinate(*mod)
+		if !ok {
+			return
+		}
+
+		key := purl.New("maven", coordinate.GroupID, coordinate.ArtifactID, coordinate.Version).String()
+		entry, _ := c.Get(key)
+		if entry.License == "" && entry.HomePage == "" && entry.SourceRepository == "" {
+			project, err := read(coordinate)
+			if err != nil {
+				return
+			}
+			var metadata models.Module
+			setPomMetadata(&metadata, project)
+			entry.HomePage, entry.SourceRepository = metadata.PackageHomePage, pomSourceRepository(project)
+			// the pom is read once, only its parents are read again
+			entry.License = licenseExpression(pomLicenses(coordinate, func(parent mavenCoordinate) (gopom.Project, error) {
+				if parent.GroupID == coordinate.GroupID && parent.ArtifactID == coordinate.ArtifactID && parent.Version == coordinate.Version {
+					return project, nil
+				}
+				return read(parent)
+			}))
+			if entry.License == "" && entry.HomePage == "" && entry.SourceRepository == "" {
+				return
+			}
+			if err := c.Put(key, entry); err != nil {
+				log.Warnf("failed to write the resume cache: %v", err)
+			}
+		}
+
+		if mod.LicenseDeclared == "" && entry.License != "" {
+			mod.LicenseDeclared = entry.License
+			mod.LicenseConcluded = entry.License
+		}
+		if mod.PackageHomePage == "" {
+			mod.PackageHomePage = entry.HomePage
+		}
+		if mod.SourceInfo == "" && entry.SourceRepository != "" {
+			mod.SourceInfo = sourceInfo(entry.SourceRepository)
+		}
+	})
+}
+
+// dependencyTypes returns the <type> of the pom dependencies keyed by groupId:artifactId
+func dependencyTypes(project gopom.Project) map[string]string {
+	types := map[string]string{}
+	dependencies := append(append([]gopom.Dependency{}, project.DependencyManagement.Dependencies...), project.Dependencies...)
+	for _, dependency := range dependencies {
+		if dependency.Type == "" {
+			continue
+		}
+		types[fmt.Sprintf("%s:%s", dependency.GroupID, strings.TrimSpace(dependency.ArtifactID))] = strings.TrimSpace(dependency.Type)
+	}
+	return types
+}
+
+// moduleCoordinate returns the coordinate of the artifact of a module, its Path holds groupId:artifactId
+func moduleCoordinate(module models.Module) (mavenCoordinate, bool) {
+	parts := strings.Split(module.Path, ":")
+	if len(parts) != 2 || parts[0] == "" || module.Version == "" {
+		return mavenCoordinate{}, false
+	}
+
+	packaging, classifier := artifactType(module.Packaging, module.Classifier)
+	return mavenCoordinate{
+		GroupID:    parts[0],
+		ArtifactID: parts[1],
+		Type:       packaging,
+		Classifier: classifier,
+		Version:    module.Version,
+	}, true
+}
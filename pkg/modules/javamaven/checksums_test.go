@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const testChecksum = "3a7d0e3f8b2c1a9f0e1d2c3b4a5f6e7d8c9b0a1f"
+
+func TestFetchChecksumWithCredentials(t *testing.T) {
+	os.Setenv("SPDX_TEST_REPOSITORY_PASSWORD", "s3cret")
+	defer os.Unsetenv("SPDX_TEST_REPOSITORY_PASSWORD")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "deployer" || password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/repository/com/acme/acme-core/1.0.0/acme-core-1.0.0.jar.sha1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(testChecksum + "  acme-core-1.0.0.jar\n"))
+	}))
+	defer ts.Close()
+
+	settings, err := readSettings(filepath.Join("testdata", "settings.xml"))
+	assert.NoError(t, err)
+
+	project := gopom.Project{Repositories: []gopom.Repository{{ID: "internal", URL: ts.URL + "/repository/"}}}
+	fetcher := newChecksumFetcher(repositories(project, settings)[:1])
+
+	modules := []models.Module{
+		{Name: "acme-core", Version: "1.0.0", Path: "com.acme:acme-core"},
+		{Name: "missing", Version: "1.0.0", Path: "com.acme:missing", CheckSum: &models.CheckSum{Value: "unchanged"}},
+	}
+	fetcher.enrichChecksums(context.Background(), modules, nil)
+
+	assert.Equal(t, models.HashAlgoSHA1, modules[0].CheckSum.Algorithm)
+	assert.Equal(t, testChecksum, modules[0].CheckSum.Value)
+	assert.Equal(t, "unchanged", modules[1].CheckSum.Value)
+}
+
+func TestFetchChecksumUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	fetcher := newChecksumFetcher([]remoteRepository{{ID: "internal", URL: ts.URL}})
+	_, err := fetcher.fetchSHA1(context.Background(), mavenCoordinate{GroupID: "com.acme", ArtifactID: "acme-core", Type: "jar", Version: "1.0.0"})
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), ts.URL)
+}
+
+func TestArtifactPath(t *testing.T) {
+	assert.Equal(t, "io/netty/netty-transport-native-epoll/4.1.65.Final/netty-transport-native-epoll-4.1.65.Final-linux-x86_64.jar",
+		artifactPath(mavenCoordinate{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Type: "jar", Classifier: "linux-x86_64", Version: "4.1.65.Final"}, nil))
+}
+
+func TestArtifactPathPackagingExtension(t *testing.T) {
+	bundle := mavenCoordinate{GroupID: "org.apache.felix", ArtifactID: "org.apache.felix.scr", Type: "bundle", Version: "2.1.26"}
+	assert.Equal(t, "org/apache/felix/org.apache.felix.scr/2.1.26/org.apache.felix.scr-2.1.26.jar", artifactPath(bundle, nil))
+	assert.Equal(t, "org/apache/felix/org.apache.felix.scr/2.1.26/org.apache.felix.scr-2.1.26.zip",
+		artifactPath(bundle, map[string]string{"bundle": "zip"}))
+
+	nbm := mavenCoordinate{GroupID: "org.netbeans.api", ArtifactID: "org-openide-util", Type: "nbm-file", Version: "RELEASE126"}
+	assert.Equal(t, "org/netbeans/api/org-openide-util/RELEASE126/org-openide-util-RELEASE126.nbm-file", artifactPath(nbm, nil))
+	assert.Equal(t, "org/netbeans/api/org-openide-util/RELEASE126/org-openide-util-RELEASE126.nbm",
+		artifactPath(nbm, map[string]string{"nbm-file": "nbm"}))
+}
+
+func TestArtifactPathClassifiedType(t *testing.T) {
+	packaging, classifier := artifactType("test-jar", "")
+	tests := mavenCoordinate{GroupID: "org.example", ArtifactID: "example-core", Type: packaging, Classifier: classifier, Version: "1.0"}
+	assert.Equal(t, "org/example/example-core/1.0/example-core-1.0-tests.jar", artifactPath(tests, nil))
+}
+
+func TestFetchChecksumPackagingExtension(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(testChecksum))
+	}))
+	defer ts.Close()
+
+	project := gopom.Project{Dependencies: []gopom.Dependency{
+		{GroupID: "com.acme", ArtifactID: "acme-osgi", Type: "bundle", Version: "1.0.0"},
+	}}
+	fetcher := newChecksumFetcher([]remoteRepository{{ID: "internal", URL: ts.URL}})
+	fetcher.types = dependencyTypes(project)
+	fetcher.extensions = map[string]string{"bundle": "jar"}
+
+	modules := []models.Module{{Name: "acme-osgi", Version: "1.0.0", Path: "com.acme:acme-osgi"}}
+	fetcher.enrichChecksums(context.Background(), modules, nil)
+
+	assert.Equal(t, []string{"/com/acme/acme-osgi/1.0.0/acme-osgi-1.0.0.jar.sha1"}, paths)
+	assert.Equal(t, testChecksum, modules[0].CheckSum.Value)
+}
+
+func TestFetchChecksumResumesFromCache(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(testChecksum))
+	}))
+	defer ts.Close()
+
+	fetcher := newChecksumFetcher([]remoteRepository{{ID: "internal", URL: ts.URL}})
+	testModules := func() []models.Module {
+		return []models.Module{
+			{Name: "acme-core", Version: "1.0.0", Path: "com.acme:acme-core"},
+			{Name: "acme-web", Version: "1.0.0", Path: "com.acme:acme-web"},
+			{Name: "acme-cli", Version: "1.0.0", Path: "com.acme:acme-cli"},
+		}
+	}
+
+	expected := testModules()
+	fetcher.enrichChecksums(context.Background(), expected, nil)
+	assert.Equal(t, 3, requests)
+
+	// the first run is interrupted after two modules
+	path := filepath.Join(t.TempDir(), "cache.json")
+	interrupted, err := cache.Open(path)
+	assert.NoError(t, err)
+	requests = 0
+	fetcher.enrichChecksums(context.Background(), testModules()[:2], interrupted)
+	assert.Equal(t, 2, requests)
+	assert.NoError(t, interrupted.Flush())
+
+	resumed, err := cache.Open(path)
+	assert.NoError(t, err)
+	requests = 0
+	modules := testModules()
+	fetcher.enrichChecksums(context.Background(), modules, resumed)
+
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, expected, modules)
+}
@@ -4,9 +4,9 @@ package javamaven
 
 import (
 	"bufio"
+	"context"
 	"encoding/xml"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -19,71 +19,39 @@ import (
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
 )
 
 // RepositoryUrl is the repository url
 var RepositoryUrl string = "https://mvnrepository.com/artifact/"
 
-// captures os.Stdout data and writes buffers
-func stdOutCapture() func() (string, error) {
-	readFromPipe, writeToPipe, err := os.Pipe()
-	if err != nil {
-		panic(err)
-	}
-
-	done := make(chan error, 1)
+// flattenedPomFile is the pom written by the flatten-maven-plugin next to pom.xml
+const flattenedPomFile = ".flattened-pom.xml"
 
-	save := os.Stdout
-	os.Stdout = writeToPipe
-
-	var buffer strings.Builder
-
-	go func() {
-		_, err := io.Copy(&buffer, readFromPipe)
-		readFromPipe.Close()
-		done <- err
-	}()
+// localRepository is where the poms and jars of the dependencies are read from
+var localRepository = defaultLocalRepository()
 
-	return func() (string, error) {
-		os.Stdout = save
-		writeToPipe.Close()
-		err := <-done
-		return buffer.String(), err
-	}
-}
+// dependencyList runs mvn dependency:list, replaced by the tests
+var dependencyList = getDependencyList
 
-func getDependencyList() ([]string, error) {
-	done := stdOutCapture()
-	var err error
-
-	cmd1 := exec.Command("mvn", "-o", "dependency:list")
-	cmd2 := exec.Command("grep", ":.*:.*:.*")
-	cmd3 := exec.Command("cut", "-d]", "-f2-")
-	cmd4 := exec.Command("sort", "-u")
-	cmd2.Stdin, err = cmd1.StdoutPipe()
-	cmd3.Stdin, err = cmd2.StdoutPipe()
-	cmd4.Stdin, err = cmd3.StdoutPipe()
-	cmd4.Stdout = os.Stdout
-	err = cmd4.Start()
-	err = cmd3.Start()
-	err = cmd2.Start()
-	err = cmd1.Run()
-	err = cmd2.Wait()
-	err = cmd3.Wait()
-	err = cmd4.Wait()
-
-	capturedOutput, err := done()
+// getDependencyList returns the output lines of mvn dependency:list run in the project directory,
+// parseDependencyList picks the coordinates out of them. A failed run still returns what was listed
+func getDependencyList(ctx context.Context, workingDir string, opts mavenOptions) ([]string, error) {
+	command := exec.CommandContext(ctx, opts.executable(), opts.args("-B", "dependency:list")...)
+	command.Dir = workingDir
+	output, err := command.Output()
 	if err != nil {
-		fmt.Println(err)
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("mvn dependency:list failed: %v", offlineError(err, opts))
 	}
 
-	s := strings.Split(capturedOutput, "\n")
-	return s, err
+	return strings.Split(string(output), "\n"), nil
 }
 
-func updateLicenseInformationToModule(mod *models.Module) {
-	licensePkg, err := helper.GetLicenses(".")
+func updateLicenseInformationToModule(mod *models.Module, matcher helper.LicenseMatcher) {
+	licensePkg, err := helper.GetLicenses(matcher, ".")
 	if err == nil {
 		mod.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
 		mod.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
@@ -104,14 +72,20 @@ func updatePackageSuppier(project gopom.Project, mod *models.Module, developers
 			mod.Supplier.Name = project.ArtifactID
 		}
 
+		if len(strings.TrimSpace(project.Organization.Name)) > 0 {
+			mod.Supplier.Type = models.Organization
+			mod.Supplier.Name = strings.TrimSpace(project.Organization.Name)
+		}
+
 		for _, developer := range developers {
-			if len(developer.Name) > 0 && len(developer.Email) > 0 {
+			name := strings.TrimSpace(developer.Name)
+			if len(name) > 0 && len(developer.Email) > 0 {
 				mod.Supplier.Type = models.Person
-				mod.Supplier.Name = developer.Name
+				mod.Supplier.Name = name
 				mod.Supplier.Email = developer.Email
-			} else if len(developer.Email) == 0 && len(developer.Name) > 0 {
+			} else if len(developer.Email) == 0 && len(name) > 0 {
 				mod.Supplier.Type = models.Person
-				mod.Supplier.Name = developer.Name
+				mod.Supplier.Name = name
 			}
 		}
 	} else {
@@ -139,19 +113,13 @@ func updatePackageDownloadLocation(groupID string, project gopom.Project, mod *m
 	}
 }
 
-func convertProjectLevelPackageToModule(project gopom.Project) models.Module {
+func convertProjectLevelPackageToModule(project gopom.Project, matcher helper.LicenseMatcher) models.Module {
 	// package to module
 	var modName string
 	if len(project.Name) == 0 {
 		modName = strings.Replace(strings.TrimSpace(project.ArtifactID), " ", "-", -1)
 	} else {
-		modName = strings.TrimSpace(project.Name)
-		if strings.HasPrefix(modName, "$") {
-			name := strings.TrimLeft(strings.TrimRight(modName, "}"), "${")
-			if strings.HasPrefix(name, "project.artifactId") {
-				modName = project.ArtifactID
-			}
-		}
+		modName = strings.TrimSpace(resolveProperty(project.Name, project))
 		modName = strings.Replace(modName, " ", "-", -1)
 	}
 
@@ -161,74 +129,276 @@ func convertProjectLevelPackageToModule(project gopom.Project) models.Module {
 	} else if len(project.Parent.Version) > 0 {
 		modVersion = project.Parent.Version
 	}
-	if strings.HasPrefix(modVersion, "$") {
-		version := strings.TrimLeft(strings.TrimRight(modVersion, "}"), "${")
-		modVersion = project.Properties.Entries[version]
-	}
+	modVersion = resolveProperty(modVersion, project)
 
 	var mod models.Module
 	mod.Name = modName
 	mod.Version = modVersion
 	mod.Modules = map[string]*models.Module{}
-	mod.CheckSum = &models.CheckSum{
-		Algorithm: models.HashAlgoSHA1,
-		Value:     readCheckSum(modName),
-	}
 	mod.Root = true
+	mod.PrimaryPackagePurpose = packagingPurpose(project.Packaging)
+	mod.Packaging, _ = artifactType(project.Packaging, "")
 	updatePackageSuppier(project, &mod, project.Developers)
 	updatePackageDownloadLocation(project.GroupID, project, &mod, project.DistributionManagement)
-	updateLicenseInformationToModule(&mod)
-	if len(project.URL) > 0 {
-		mod.PackageURL = project.URL
+	updateLicenseInformationToModule(&mod, matcher)
+	if mod.LicenseDeclared == "" {
+		setPomLicense(&mod, project.Licenses)
 	}
+	mod.PackageURL = mavenArtifactPackageURL(resolveProperty(project.GroupID, project), project.ArtifactID, modVersion, mod.Packaging, "")
+	setPomMetadata(&mod, project)
 
 	return mod
 }
 
-func findInDependency(slice []gopom.Dependency, val string) bool {
-	for _, item := range slice {
-		if item.ArtifactID == val {
-			return true
-		}
-	}
-	return false
+// packagingPurpose maps the pom <packaging> to the primary package purpose, jar is the default packaging
+func packagingPurpose(packaging string) models.PackagePurpose {
+	switch strings.TrimSpace(packaging) {
+	case "", "jar":
+		return models.PurposeLibrary
+	case "war", "ear", "maven-plugin":
+		return models.PurposeApplication
+	case "pom":
+		return models.PurposeOther
+	}
+	return ""
 }
 
-func findInPlugins(slice []gopom.Plugin, val string) bool {
-	for _, item := range slice {
-		if item.ArtifactID == val {
-			return true
+// projectProperty returns the value of the built-in project properties, e.g. ${project.version} or
+// ${parent.version}, falling back to the parent values the project inherits
+func projectProperty(name string, project gopom.Project) (string, bool) {
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "project."), "pom.")
+	switch name {
+	case "groupId":
+		if len(project.GroupID) > 0 {
+			return project.GroupID, true
+		}
+		return project.Parent.GroupID, true
+	case "artifactId":
+		return project.ArtifactID, true
+	case "version":
+		if len(project.Version) > 0 {
+			return project.Version, true
+		}
+		return project.Parent.Version, true
+	case "parent.groupId":
+		return project.Parent.GroupID, true
+	case "parent.artifactId":
+		return project.Parent.ArtifactID, true
+	case "parent.version":
+		return project.Parent.Version, true
+	case "name":
+		return project.Name, true
+	case "description":
+		return project.Description, true
+	case "url":
+		return project.URL, true
+	case "packaging":
+		if len(project.Packaging) > 0 {
+			return project.Packaging, true
 		}
+		return "jar", true
 	}
-	return false
+
+	return "", false
 }
 
 func createModule(groupID string, name string, version string, project gopom.Project) models.Module {
+	mod := newModule(mavenCoordinate{GroupID: groupID, ArtifactID: name, Version: version}, project)
+	readLocalArtifact(&mod)
+	return mod
+}
+
+// newModule creates the module of a dependency from its coordinate as the pom declares it, readLocalArtifact
+// adds what the local repository holds of it
+func newModule(coordinate mavenCoordinate, project gopom.Project) models.Module {
 	var mod models.Module
-	modVersion := version
-	if strings.HasPrefix(version, "$") {
-		version1 := strings.TrimLeft(strings.TrimRight(version, "}"), "${")
-		modVersion = project.Properties.Entries[version1]
-	}
+	modVersion := resolveProperty(coordinate.Version, project)
+	groupID := resolveProperty(coordinate.GroupID, project)
 
-	name = path.Base(name)
+	name := path.Base(coordinate.ArtifactID)
 	name = strings.TrimSpace(name)
 	mod.Name = strings.Replace(name, " ", "-", -1)
 	mod.Version = modVersion
+	mod.Path = fmt.Sprintf("%s:%s", groupID, name)
+	mod.Packaging, mod.Classifier = artifactType(coordinate.Type, coordinate.Classifier)
+	mod.PackageURL = mavenArtifactPackageURL(groupID, mod.Name, modVersion, mod.Packaging, mod.Classifier)
 	mod.Modules = map[string]*models.Module{}
-	mod.CheckSum = &models.CheckSum{
-		Algorithm: models.HashAlgoSHA1,
-		Value:     readCheckSum(name),
-	}
 	updatePackageSuppier(project, &mod, project.Developers)
 	updatePackageDownloadLocation(groupID, project, &mod, project.DistributionManagement)
-	updateLicenseInformationToModule(&mod)
 	return mod
 }
 
-func readAndLoadPomFile(fpath string) (gopom.Project, error) {
+// dependencyCoordinate returns the coordinate of a pom dependency
+func dependencyCoordinate(dependency gopom.Dependency) mavenCoordinate {
+	return mavenCoordinate{
+		GroupID:    dependency.GroupID,
+		ArtifactID: dependency.ArtifactID,
+		Version:    dependency.Version,
+		Type:       dependency.Type,
+		Classifier: dependency.Classifier,
+	}
+}
+
+// pluginCoordinate returns the coordinate of a build plugin
+func pluginCoordinate(plugin gopom.Plugin) mavenCoordinate {
+	return mavenCoordinate{GroupID: plugin.GroupID, ArtifactID: plugin.ArtifactID, Version: plugin.Version, Type: "maven-plugin"}
+}
+
+// readLocalArtifact sets the checksum of the artifact, the license, home page and scm of the pom and the
+// attribution texts of the module from the local repository
+func readLocalArtifact(mod *models.Module) {
+	// Path holds groupId:artifactId
+	groupID, name := mod.Path, ""
+	if index := strings.Index(mod.Path, ":"); index >= 0 {
+		groupID, name = mod.Path[:index], mod.Path[index+1:]
+	}
+	packaging, classifier := artifactType(mod.Packaging, mod.Classifier)
+	jar := mavenCoordinate{GroupID: groupID, ArtifactID: name, Version: mod.Version, Type: packaging, Classifier: classifier}
+	pom := mavenCoordinate{GroupID: groupID, ArtifactID: name, Version: mod.Version, Type: "pom"}
+
+	checksum, err := localChecksum(localRepository, jar)
+	if err != nil {
+		mod.Unresolved = append(mod.Unresolved, err.Error())
+	}
+	mod.CheckSum = checksum
+	setPomLicense(mod, pomLicenses(pom, localPomReader(localRepository)))
+	if project, err := localPomReader(localRepository)(pom); err == nil {
+		setPomMetadata(mod, project)
+	}
+	mod.AttributionTexts = readAttributionTexts(localRepository, jar)
+}
+
+// setPomMetadata sets the home page and the source info of the module to the <url> and <scm> of its pom
+func setPomMetadata(mod *models.Module, project gopom.Project) {
+	if url := strings.TrimSpace(project.URL); url != "" && !strings.Contains(url, "${") {
+		mod.PackageHomePage = url
+	}
+	if repository := pomSourceRepository(project); repository != "" {
+		mod.SourceInfo = sourceInfo(repository)
+	}
+}
+
+// pomSourceRepository returns the <scm> url of the pom, or its connection without the scm:<provider>: prefix,
+// e.g. scm:git:https://github.com/FasterXML/jackson-core.git, the ones of unresolved properties are skipped
+func pomSourceRepository(project gopom.Project) string {
+	for _, value := range []string{project.SCM.URL, project.SCM.Connection, project.SCM.DeveloperConnection} {
+		value = strings.TrimSpace(value)
+		if value == "" || strings.Contains(value, "${") {
+			continue
+		}
+		if strings.HasPrefix(value, "scm:") {
+			parts := strings.SplitN(value, ":", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			value = parts[2]
+		}
+		return value
+	}
+	return ""
+}
+
+// sourceInfo returns the SPDX package source info of a scm repository
+func sourceInfo(repository string) string {
+	return "scm repository: " + repository
+}
+
+// readLocalArtifacts reads the local artifacts of the modules, hashing the jars and parsing the poms
+// of parallelism modules at once
+func readLocalArtifacts(modules []models.Module, parallelism int) {
+	forEachParallel(len(modules), parallelism, func(i int) {
+		readLocalArtifact(&modules[i])
+	})
+}
+
+// mavenPackageURL returns the purl of the coordinate, e.g. pkg:maven/org.slf4j/slf4j-api@1.7.30.
+// A version left unresolved is omitted, the purl still names the package
+func mavenPackageURL(groupID string, artifactID string, version string) string {
+	return mavenArtifactPackageURL(groupID, artifactID, version, "", "")
+}
+
+// mavenArtifactPackageURL returns the purl of the artifact, qualified with its type when it is not
+// the default jar and with its classifier, e.g. pkg:maven/org.example/app@1.0?classifier=tests&type=test-jar
+func mavenArtifactPackageURL(groupID string, artifactID string, version string, packaging string, classifier string) string {
+	groupID, artifactID = strings.TrimSpace(groupID), strings.TrimSpace(artifactID)
+	if groupID == "" || artifactID == "" || strings.Contains(groupID, "${") || strings.Contains(artifactID, "${") {
+		return ""
+	}
+	if strings.Contains(version, "${") {
+		version = ""
+	}
+
+	packageURL := purl.New("maven", groupID, artifactID, strings.TrimSpace(version)).WithQualifier("classifier", classifier)
+	if packaging != "jar" {
+		packageURL = packageURL.WithQualifier("type", packaging)
+	}
+	return packageURL.String()
+}
+
+// artifactType returns the type of an artifact, jar when not declared, and its classifier, which
+// some types imply, e.g. the tests classifier of a test-jar
+func artifactType(packaging string, classifier string) (string, string) {
+	packaging, classifier = strings.TrimSpace(packaging), strings.TrimSpace(classifier)
+	if packaging == "" {
+		packaging = "jar"
+	}
+	if classifier == "" {
+		classifier = typeClassifiers[packaging]
+	}
+	return packaging, classifier
+}
+
+// appendListedDependencies adds the dependency:list coordinates not declared in the pom to modules,
+// every coordinate line is kept however short the list is
+func appendListedDependencies(modules []models.Module, parentMod models.Module, project gopom.Project, dependencyList []string, opts mavenOptions) []models.Module {
+	for _, coordinate := range parseDependencyList(dependencyList) {
+		if !opts.includesScope(coordinate.Scope) {
+			continue
+		}
+
+		found := false
+		// iterate over dependencies
+		for _, dep := range project.Dependencies {
+			if dep.ArtifactID == coordinate.ArtifactID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			for _, dependencyManagement := range project.DependencyManagement.Dependencies {
+				if dependencyManagement.ArtifactID == coordinate.ArtifactID {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			mod := newModule(coordinate, project)
+			mod.Relationship = models.ScopeRelationship(coordinate.Scope)
+			modules = append(modules, mod)
+			parentMod.Modules[mod.Name] = &mod
+		}
+	}
+
+	return modules
+}
+
+func readAndLoadPomFile(ctx context.Context, fpath string, opts mavenOptions) (gopom.Project, error) {
 	var project gopom.Project
 
+	if opts.EffectivePom && !opts.LockfileOnly {
+		project, err := effectivePom(ctx, fpath, opts)
+		if err == nil {
+			return project, nil
+		}
+		if ctx.Err() != nil {
+			return project, ctx.Err()
+		}
+		log.Printf("mvn help:effective-pom failed, reading pom.xml instead: %v", err)
+	}
+
 	filePath := fpath + "/pom.xml"
 	pomFile, err := os.Open(filePath)
 	if err != nil {
@@ -254,202 +424,168 @@ func readAndLoadPomFile(fpath string) (gopom.Project, error) {
 		return project, err
 	}
 
-	return project, nil
-}
-
-func getModule(modules []models.Module, name string) (models.Module, error) {
-	for _, module := range modules {
-		if module.Name == name {
-			return module, nil
-		}
-	}
-	return models.Module{}, moduleNotFound
+	return interpolateProject(inheritParents(fpath, applyProfiles(fpath, applyFlattenedPom(fpath, project), opts))), nil
 }
 
-// If parent pom.xml has modules information in it, go to individual modules pom.xml
-func convertPkgModulesToModule(existingModules []models.Module, fpath string, moduleName string, parentPom gopom.Project) ([]models.Module, error) {
-	var modules []models.Module
-	filePath := fpath + "/" + moduleName
-	project, err := readAndLoadPomFile(filePath)
+// applyFlattenedPom prefers the coordinates and dependencies of the pom written by the
+// flatten-maven-plugin, they hold concrete versions. Modules and build only exist in the pom
+func applyFlattenedPom(fpath string, project gopom.Project) gopom.Project {
+	pomData, err := ioutil.ReadFile(filepath.Join(fpath, flattenedPomFile))
 	if err != nil {
-		return []models.Module{}, err
+		return project
 	}
 
-	parentMod := convertProjectLevelPackageToModule(project)
-	parentMod.Root = false
-	modules = append(modules, parentMod)
-
-	// Include dependecy from module pom.xml if it is not existing in ParentPom
-	for _, element := range project.Dependencies {
-		name := strings.Replace(strings.TrimSpace(element.ArtifactID), " ", "-", -1)
-		found1 := false
-		found := findInDependency(parentPom.Dependencies, name)
-		if !found {
-			found1 = findInDependency(parentPom.DependencyManagement.Dependencies, name)
-			if !found1 {
-				mod := createModule(element.GroupID, name, element.Version, project)
-				modules = append(modules, mod)
-				parentMod.Modules[mod.Name] = &mod
-			}
-		}
-
-		if found || found1 {
-			module, err := getModule(existingModules, name)
-			if err == nil {
-				parentMod.Modules[name] = &module
-			}
-		}
+	var flattened gopom.Project
+	if err := xml.Unmarshal(pomData, &flattened); err != nil {
+		log.Printf("unable to unmarshal %s, using pom.xml. Reason: %v", flattenedPomFile, err)
+		return project
 	}
 
-	// Include plugins from module pom.xml if it is not existing in ParentPom
-	for _, element := range project.Build.Plugins {
-		name := strings.Replace(strings.TrimSpace(element.ArtifactID), " ", "-", -1)
-		found1 := false
-		found := findInPlugins(parentPom.Build.Plugins, name)
-		if !found {
-			found1 = findInPlugins(parentPom.Build.PluginManagement.Plugins, name)
-			if !found1 {
-				mod := createModule(element.GroupID, name, element.Version, project)
-				modules = append(modules, mod)
-				parentMod.Modules[mod.Name] = &mod
-			}
-		}
-
-		if found || found1 {
-			module, err := getModule(existingModules, name)
-			if err == nil {
-				parentMod.Modules[name] = &module
-			}
-		}
+	if flattened.GroupID != "" {
+		project.GroupID = flattened.GroupID
 	}
-	return modules, nil
+	if flattened.Version != "" {
+		project.Version = flattened.Version
+	}
+	if flattened.URL != "" {
+		project.URL = flattened.URL
+	}
+	project.Dependencies = flattened.Dependencies
+	if len(flattened.DependencyManagement.Dependencies) > 0 {
+		project.DependencyManagement = flattened.DependencyManagement
+	}
+
+	return project
 }
 
-func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Module, error) {
+func convertPOMReaderToModules(ctx context.Context, fpath string, lookForDepenent bool, opts mavenOptions) ([]models.Module, error) {
 	modules := make([]models.Module, 0)
-	project, err := readAndLoadPomFile(fpath)
+	project, err := readAndLoadPomFile(ctx, fpath, opts)
 	if err != nil {
 		return []models.Module{}, err
 	}
-	parentMod := convertProjectLevelPackageToModule(project)
+	parentMod := convertProjectLevelPackageToModule(project, opts.LicenseMatcher)
 	parentMod.Root = true
+	parentMod.CheckSum = pomChecksum(fpath)
 	modules = append(modules, parentMod)
 
-	// iterate over dependencyManagement
+	// iterate over dependencyManagement, a BOM import that could not be read manages nothing itself
 	for _, dependencyManagement := range project.DependencyManagement.Dependencies {
-		mod := createModule(dependencyManagement.GroupID, dependencyManagement.ArtifactID, dependencyManagement.Version, project)
+		if isBomImport(dependencyManagement) || !opts.includesScope(dependencyManagement.Scope) {
+			continue
+		}
+		mod := newModule(dependencyCoordinate(dependencyManagement), project)
 		modules = append(modules, mod)
 		parentMod.Modules[mod.Name] = &mod
 	}
 
 	// iterate over dependencies
 	for _, dep := range project.Dependencies {
-		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		if !opts.includesScope(dep.Scope) {
+			continue
+		}
+		mod := newModule(dependencyCoordinate(dep), project)
+		mod.Relationship = models.ScopeRelationship(dep.Scope)
 		modules = append(modules, mod)
 		parentMod.Modules[mod.Name] = &mod
 	}
 
-	// iterate over Plugins
-	for _, plugin := range project.Build.Plugins {
-		// If plugin has groupId, skip here. Plugin details will be available at PluginManagement
-		if len(plugin.GroupID) == 0 {
-			mod := createModule(plugin.GroupID, plugin.ArtifactID, plugin.Version, project)
+	if opts.IncludeBuildTools {
+		// iterate over Plugins
+		for _, plugin := range project.Build.Plugins {
+			// If plugin has groupId, skip here. Plugin details will be available at PluginManagement
+			if len(plugin.GroupID) == 0 {
+				mod := newModule(pluginCoordinate(plugin), project)
+				mod.Relationship = models.RelationshipBuildToolOf
+				modules = append(modules, mod)
+				parentMod.Modules[mod.Name] = &mod
+			}
+		}
+
+		// iterate over PluginManagement
+		for _, plugin := range project.Build.PluginManagement.Plugins {
+			mod := newModule(pluginCoordinate(plugin), project)
+			mod.Relationship = models.RelationshipBuildToolOf
 			modules = append(modules, mod)
 			parentMod.Modules[mod.Name] = &mod
 		}
 	}
 
-	// iterate over PluginManagement
-	for _, plugin := range project.Build.PluginManagement.Plugins {
-		mod := createModule(plugin.GroupID, plugin.ArtifactID, plugin.Version, project)
-		modules = append(modules, mod)
-		parentMod.Modules[mod.Name] = &mod
-	}
-
-	dependencyList, err := getDependencyList()
-	if err != nil {
-		fmt.Println("error in getting mvn dependency list and parsing it")
-		return modules, err
+	// mvn is never run in the lockfile-only mode, the dependencies the poms declare are listed
+	var listed []string
+	if !opts.LockfileOnly {
+		listed, err = dependencyList(ctx, fpath, opts)
+		if err != nil {
+			fmt.Println("error in getting mvn dependency list and parsing it")
+			return modules, err
+		}
 	}
 
 	// Add additional dependency from mvn dependency list to pom.xml dependency list
-	var i int
-	for i < len(dependencyList)-2 { // skip 1 empty line and Finished statement line
-		// If any errors captured in mvn dependency, ignore that
-		if strings.Contains(dependencyList[i], "Invalid module name") {
-			i++
-			continue
-		}
-		dependencyItem := strings.Split(dependencyList[i], ":")[1]
-
-		found := false
-		// iterate over dependencies
-		for _, dep := range project.Dependencies {
-			if dep.ArtifactID == dependencyItem {
-				found = true
-				break
-			}
-		}
+	modules = appendListedDependencies(modules, parentMod, project, listed, opts)
+	readLocalArtifacts(modules[1:], opts.Parallelism)
 
-		if !found {
-			for _, dependencyManagement := range project.DependencyManagement.Dependencies {
-				if dependencyManagement.ArtifactID == dependencyItem {
-					found = true
-					break
-				}
-			}
-		}
+	if lookForDepenent {
+		modules = appendReactorModules(ctx, modules, 0, fpath, project, opts)
+	}
+	refreshLinkedModules(modules)
+	return modules, nil
+}
 
-		if !found {
-			groupID := strings.Split(dependencyList[i], ":")[0]
-			version := strings.Split(dependencyList[i], ":")[3]
-			mod := createModule(strings.TrimSpace(groupID), dependencyItem, version, project)
-			modules = append(modules, mod)
-			parentMod.Modules[mod.Name] = &mod
-		}
-		i++
+// refreshLinkedModules replaces the copies linked to the modules with the modules as they are now,
+// linking happens before the local artifacts are read
+func refreshLinkedModules(modules []models.Module) {
+	byName := map[string]int{}
+	for i, module := range modules {
+		byName[module.Name] = i
 	}
 
-	if lookForDepenent {
-		// iterate over Modules
-		for _, module := range project.Modules {
-			additionalModules, err := convertPkgModulesToModule(modules, fpath, module, project)
-			if err != nil {
-				// continue reading other module pom.xml file
+	for i := range modules {
+		for name, linked := range modules[i].Modules {
+			index, ok := byName[name]
+			if !ok || linked == nil {
 				continue
 			}
-			modules = append(modules, additionalModules...)
+			refreshed := modules[index]
+			refreshed.Relationship = linked.Relationship
+			modules[i].Modules[name] = &refreshed
 		}
 	}
-	return modules, nil
 }
 
-func getTransitiveDependencyList(workingDir string) (map[string][]string, error) {
-	path := filepath.Join(os.TempDir(), "JavaMavenTDTreeOutput.txt")
-	os.Remove(path)
+// transitiveDependencyList runs mvn dependency:tree, replaced by the tests
+var transitiveDependencyList = getTransitiveDependencyList
+
+// getTransitiveDependencyList writes the verbose trees of the project and its modules, which tell the
+// nodes omitted for conflict, to a temporary file of its own, so concurrent runs never read each other's
+// trees, and parses them
+func getTransitiveDependencyList(ctx context.Context, workingDir string, opts mavenOptions) (dependencyTree, error) {
+	file, err := ioutil.TempFile("", "spdx-mvn-dependency-tree-*.txt")
+	if err != nil {
+		return dependencyTree{}, err
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
 
-	command := exec.Command("mvn", "dependency:tree", "-DoutputType=dot", "-DappendOutput=true", "-DoutputFile="+path)
+	command := exec.CommandContext(ctx, opts.executable(), opts.args("dependency:tree", "-Dverbose", "-DoutputType=text", "-DappendOutput=true", "-DoutputFile="+path)...)
 	command.Dir = workingDir
 	out, err := command.CombinedOutput()
 	if err != nil {
 		log.Print(string(out))
-		return nil, err
+		return dependencyTree{}, offlineError(err, opts)
 	}
 
-	tdList, err := readAndgetTransitiveDependencyList(path)
-	if err != nil {
-		return nil, err
-	}
-	return tdList, nil
+	return readAndgetTransitiveDependencyList(path)
 }
 
-func readAndgetTransitiveDependencyList(path string) (map[string][]string, error) {
+func readAndgetTransitiveDependencyList(path string) (dependencyTree, error) {
 
 	file, err := os.Open(path)
 
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return dependencyTree{}, err
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -462,47 +598,7 @@ func readAndgetTransitiveDependencyList(path string) (map[string][]string, error
 	}
 	file.Close()
 
-	tdList := map[string][]string{}
-	handlePkgs(text, tdList)
-	return tdList, nil
-}
-
-func doesDependencyExists(tdList map[string][]string, lData string, val string) bool {
-	for _, item := range tdList[lData] {
-		if item == val {
-			return true
-		}
-	}
-	return false
-}
-
-func handlePkgs(text []string, tdList map[string][]string) {
-	i := 0
-	var pkgName string
-	isEmptyMainPkg := false
-
-	for i < len(text) {
-		if strings.Contains(text[i], "{") {
-			pkgName = strings.Split(text[i], ":")[1]
-		} else if strings.Contains(text[i], "->") {
-			lhsData := strings.Split(text[i], "->")[0]
-			rhsData := strings.Split(text[i], "->")[1]
-			lData := strings.Split(lhsData, ":")[1]
-			rData := strings.Split(rhsData, ":")[1]
-
-			// If package name is same, add right hand side dependency
-			if !isEmptyMainPkg && lData == pkgName {
-				tdList[pkgName] = append(tdList[pkgName], rData)
-			} else if !doesDependencyExists(tdList, lData, rData) { // check whether dependency already exists
-				tdList[lData] = append(tdList[lData], rData)
-			}
-		} else if strings.Contains(text[i], "}") {
-			if i == 1 {
-				isEmptyMainPkg = true
-			}
-		}
-		i++
-	}
+	return parseDependencyTree(text), nil
 }
 
 func buildDependenciesGraph(modules []models.Module, tdList map[string][]string) {
@@ -529,6 +625,12 @@ func buildDependenciesGraph(modules []models.Module, tdList map[string][]string)
 					continue
 				}
 
+				// the relationship is the one of the dependency declaration, e.g. its test scope
+				var relationship models.RelationshipType
+				if declared, ok := modules[moduleIndex[moduleName]].Modules[depName]; ok && declared != nil {
+					relationship = declared.Relationship
+				}
+
 				modules[moduleIndex[moduleName]].Modules[depName] = &models.Module{
 					Name:                    depModule.Name,
 					Version:                 depModule.Version,
@@ -538,6 +640,7 @@ func buildDependenciesGraph(modules []models.Module, tdList map[string][]string)
 					PackageURL:              depModule.PackageURL,
 					CheckSum:                depModule.CheckSum,
 					PackageHomePage:         depModule.PackageHomePage,
+					SourceInfo:              depModule.SourceInfo,
 					PackageDownloadLocation: depModule.PackageDownloadLocation,
 					LicenseConcluded:        depModule.LicenseConcluded,
 					LicenseDeclared:         depModule.LicenseDeclared,
@@ -546,6 +649,7 @@ func buildDependenciesGraph(modules []models.Module, tdList map[string][]string)
 					Copyright:               depModule.Copyright,
 					PackageComment:          depModule.PackageComment,
 					Root:                    depModule.Root,
+					Relationship:            relationship,
 				}
 			}
 		}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestCreateModuleResolvesProjectProperties(t *testing.T) {
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "self-reference"), mavenOptions{})
+	assert.NoError(t, err)
+
+	expected := []struct {
+		name     string
+		version  string
+		location string
+	}{
+		{"example-api", "2.1.0", RepositoryUrl + "org.example/example-api/2.1.0"},
+		{"example-model", "2.1.0", RepositoryUrl + "org.example/example-model/2.1.0"},
+		{"junit", "4.13.2", RepositoryUrl + "junit/junit/4.13.2"},
+	}
+
+	assert.Len(t, project.Dependencies, len(expected))
+	for i, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+
+		assert.Equal(t, expected[i].name, mod.Name)
+		assert.Equal(t, expected[i].version, mod.Version)
+		assert.Equal(t, expected[i].location, mod.PackageDownloadLocation)
+	}
+
+	root := convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "2.1.0", root.Version)
+}
+
+func TestMavenPackageURL(t *testing.T) {
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "self-reference"), mavenOptions{})
+	assert.NoError(t, err)
+
+	purls := map[string]string{}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		purls[mod.Name] = mod.PackageURL
+	}
+	assert.Equal(t, map[string]string{
+		"example-api":   "pkg:maven/org.example/example-api@2.1.0",
+		"example-model": "pkg:maven/org.example/example-model@2.1.0",
+		"junit":         "pkg:maven/junit/junit@4.13.2",
+	}, purls)
+
+	// the purl identifies the root package, the pom url is its home page
+	root := convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "pkg:maven/org.example/example-service@2.1.0", root.PackageURL)
+	assert.Equal(t, "https://example.org/service", root.PackageHomePage)
+
+	assert.Equal(t, "pkg:maven/org.example/example-api", mavenPackageURL("org.example", "example-api", "${revision}"))
+	assert.Empty(t, mavenPackageURL("${groupId}", "example-api", "1.0"))
+}
+
+func TestMavenArtifactPackageURL(t *testing.T) {
+	assert.Equal(t, "pkg:maven/org.example/example-web@1.0?type=war", mavenArtifactPackageURL("org.example", "example-web", "1.0", "war", ""))
+	assert.Equal(t, "pkg:maven/org.example/example-api@1.0?classifier=tests&type=test-jar",
+		mavenArtifactPackageURL("org.example", "example-api", "1.0", "test-jar", "tests"))
+	assert.Equal(t, "pkg:maven/io.netty/netty-transport-native-epoll@4.1.65.Final?classifier=linux-x86_64",
+		mavenArtifactPackageURL("io.netty", "netty-transport-native-epoll", "4.1.65.Final", "jar", "linux-x86_64"))
+	assert.Equal(t, mavenPackageURL("org.example", "example-api", "1.0"), mavenArtifactPackageURL("org.example", "example-api", "1.0", "jar", ""))
+}
+
+func TestCreateModuleRecordsArtifactType(t *testing.T) {
+	dependencies := []gopom.Dependency{
+		{GroupID: "org.example", ArtifactID: "example-api", Version: "1.0"},
+		{GroupID: "org.example", ArtifactID: "example-web", Version: "1.0", Type: "war"},
+		{GroupID: "org.example", ArtifactID: "example-core", Version: "1.0", Type: "test-jar"},
+		{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Version: "4.1.65.Final", Classifier: "linux-x86_64"},
+	}
+	expected := []struct {
+		packaging  string
+		classifier string
+	}{
+		{"jar", ""},
+		{"war", ""},
+		// the test-jar type implies the tests classifier
+		{"test-jar", "tests"},
+		{"jar", "linux-x86_64"},
+	}
+
+	for i, dependency := range dependencies {
+		mod := newModule(dependencyCoordinate(dependency), gopom.Project{})
+		assert.Equal(t, expected[i].packaging, mod.Packaging)
+		assert.Equal(t, expected[i].classifier, mod.Classifier)
+
+		coordinate, ok := moduleCoordinate(mod)
+		assert.True(t, ok)
+		assert.Equal(t, expected[i].packaging, coordinate.Type)
+		assert.Equal(t, expected[i].classifier, coordinate.Classifier)
+	}
+
+	root := convertProjectLevelPackageToModule(gopom.Project{GroupID: "org.example", ArtifactID: "example-web", Version: "1.0", Packaging: "war"}, nil)
+	assert.Equal(t, "war", root.Packaging)
+	assert.Equal(t, "pkg:maven/org.example/example-web@1.0?type=war", root.PackageURL)
+}
+
+func TestCreateModuleReadsHomePage(t *testing.T) {
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "self-reference"), mavenOptions{})
+	assert.NoError(t, err)
+
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	homePages, sourceInfos := map[string]string{}, map[string]string{}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		homePages[mod.Name] = mod.PackageHomePage
+		sourceInfos[mod.Name] = mod.SourceInfo
+	}
+	assert.Equal(t, map[string]string{
+		"example-api":   "",
+		"example-model": "",
+		"junit":         "http://junit.org",
+	}, homePages)
+	assert.Equal(t, map[string]string{
+		"example-api":   "",
+		"example-model": "",
+		"junit":         "scm repository: https://github.com/junit-team/junit4",
+	}, sourceInfos)
+
+	root := convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "https://example.org/service", root.PackageHomePage)
+}
+
+func TestPomSourceRepository(t *testing.T) {
+	tests := []struct {
+		name     string
+		scm      gopom.Scm
+		expected string
+	}{
+		{"url", gopom.Scm{URL: " https://github.com/FasterXML/jackson-core ", Connection: "scm:git:git@github.com:FasterXML/jackson-core.git"}, "https://github.com/FasterXML/jackson-core"},
+		{"connection", gopom.Scm{Connection: "scm:git:https://github.com/qos-ch/slf4j.git"}, "https://github.com/qos-ch/slf4j.git"},
+		{"developer connection", gopom.Scm{DeveloperConnection: "scm:svn:https://svn.apache.org/repos/asf/commons/proper/lang"}, "https://svn.apache.org/repos/asf/commons/proper/lang"},
+		{"unresolved property", gopom.Scm{URL: "${project.url}", Connection: "scm:git:https://github.com/example/lib.git"}, "https://github.com/example/lib.git"},
+		{"none", gopom.Scm{}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, pomSourceRepository(gopom.Project{SCM: test.scm}))
+		})
+	}
+}
+
+func TestCreateModuleReadsAttributionTexts(t *testing.T) {
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "self-reference"), mavenOptions{})
+	assert.NoError(t, err)
+
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	attributions := map[string][]string{}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		attributions[mod.Name] = mod.AttributionTexts
+	}
+	assert.Equal(t, map[string][]string{
+		"example-api":   {"Example API\nCopyright 2021 The Example Foundation\n\nThis product includes software developed at\nThe Example Foundation (https://example.org/)."},
+		"example-model": nil,
+		"junit":         nil,
+	}, attributions)
+}
+
+func TestReadFlattenedPom(t *testing.T) {
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "flattened"), mavenOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.4.2", project.Version)
+	assert.Len(t, project.Build.Plugins, 1)
+
+	versions := map[string]string{}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		versions[mod.Name] = mod.Version
+	}
+	assert.Equal(t, map[string]string{"guava": "30.1-jre", "slf4j-api": "1.7.30"}, versions)
+
+	root := convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "1.4.2", root.Version)
+}
+
+func TestPackagingPurpose(t *testing.T) {
+	tests := []struct {
+		packaging string
+		purpose   models.PackagePurpose
+	}{
+		{"", models.PurposeLibrary},
+		{"jar", models.PurposeLibrary},
+		{"war", models.PurposeApplication},
+		{"ear", models.PurposeApplication},
+		{"maven-plugin", models.PurposeApplication},
+		{"pom", models.PurposeOther},
+		{"nar", ""},
+	}
+
+	for _, test := range tests {
+		root := convertProjectLevelPackageToModule(gopom.Project{GroupID: "org.example", ArtifactID: "example", Version: "1.0.0", Packaging: test.packaging}, nil)
+		assert.Equal(t, test.purpose, root.PrimaryPackagePurpose, test.packaging)
+	}
+}
+
+func TestUpdatePackageSupplier(t *testing.T) {
+	project := gopom.Project{GroupID: "org.example", ArtifactID: "example", Version: "1.0.0"}
+
+	project.Organization = gopom.Organization{Name: "Example Corp"}
+	root := convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "Organization: Example Corp", root.Supplier.Get())
+
+	project.Developers = []gopom.Developer{{Name: "Jane Doe", Email: "jane@example.com"}}
+	root = convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+
+	project.Developers = []gopom.Developer{{Name: " ", Email: "nobody@example.com"}}
+	root = convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "Organization: Example Corp", root.Supplier.Get())
+}
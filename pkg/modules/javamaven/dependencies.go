@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// log level prefix of the maven output, e.g. [INFO]
+	logLevelPrefix = regexp.MustCompile(`^\[[A-Z]+\]`)
+	// a coordinate part, no space nor separator
+	coordinatePart = regexp.MustCompile(`^[A-Za-z0-9_.\-+${}]+$`)
+
+	mavenScopes = map[string]bool{
+		"compile":  true,
+		"provided": true,
+		"runtime":  true,
+		"test":     true,
+		"system":   true,
+		"import":   true,
+	}
+)
+
+// mavenCoordinate is a resolved dependency as listed by dependency:list
+type mavenCoordinate struct {
+	GroupID    string
+	ArtifactID string
+	Type       string
+	Classifier string
+	Version    string
+	Scope      string
+}
+
+// parseDependencyList extracts the coordinates from the dependency:list output, any other
+// line (blank, headers, `Finished`, build summary, localized messages...) is skipped
+func parseDependencyList(lines []string) []mavenCoordinate {
+	var coordinates []mavenCoordinate
+	seen := map[mavenCoordinate]bool{}
+	for _, line := range lines {
+		coordinate, ok := parseCoordinate(line)
+		if !ok || seen[coordinate] {
+			continue
+		}
+
+		seen[coordinate] = true
+		coordinates = append(coordinates, coordinate)
+	}
+
+	return coordinates
+}
+
+// parseCoordinate parses a groupId:artifactId:type[:classifier]:version:scope line
+func parseCoordinate(line string) (mavenCoordinate, bool) {
+	line = strings.TrimSpace(logLevelPrefix.ReplaceAllString(strings.TrimSpace(line), ""))
+
+	// maven 3.6+ appends the java module name, e.g. ` -- module slf4j.api`
+	if idx := strings.Index(line, " -- "); idx >= 0 {
+		line = line[:idx]
+	}
+	line = strings.TrimSpace(strings.TrimSuffix(line, "(optional)"))
+
+	parts := strings.Split(line, ":")
+	if len(parts) != 5 && len(parts) != 6 {
+		return mavenCoordinate{}, false
+	}
+
+	for _, part := range parts {
+		if !coordinatePart.MatchString(part) {
+			return mavenCoordinate{}, false
+		}
+	}
+
+	scope := parts[len(parts)-1]
+	if !mavenScopes[scope] {
+		return mavenCoordinate{}, false
+	}
+
+	coordinate := mavenCoordinate{
+		GroupID:    parts[0],
+		ArtifactID: parts[1],
+		Type:       parts[2],
+		Version:    parts[len(parts)-2],
+		Scope:      scope,
+	}
+	if len(parts) == 6 {
+		coordinate.Classifier = parts[3]
+	}
+
+	return coordinate, true
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestParseDependencyListLocalized(t *testing.T) {
+	output, err := ioutil.ReadFile(filepath.Join("testdata", "dependency-list-de.out"))
+	assert.NoError(t, err)
+
+	coordinates := parseDependencyList(strings.Split(string(output), "\n"))
+
+	assert.Equal(t, []mavenCoordinate{
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Type: "jar", Version: "1.7.30", Scope: "compile"},
+		{GroupID: "junit", ArtifactID: "junit", Type: "jar", Version: "4.13.2", Scope: "test"},
+		{GroupID: "org.hamcrest", ArtifactID: "hamcrest-core", Type: "jar", Version: "1.3", Scope: "test"},
+		{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Type: "jar", Classifier: "linux-x86_64", Version: "4.1.65.Final", Scope: "runtime"},
+	}, coordinates)
+}
+
+func TestParseDependencyListWithoutPrefix(t *testing.T) {
+	// coordinates without the [INFO] prefix, no trailing Finished line
+	lines := []string{
+		"   com.google.guava:guava:jar:30.1-jre:compile",
+		"",
+	}
+
+	assert.Equal(t, []mavenCoordinate{
+		{GroupID: "com.google.guava", ArtifactID: "guava", Type: "jar", Version: "30.1-jre", Scope: "compile"},
+	}, parseDependencyList(lines))
+}
+
+func TestAppendListedDependenciesTwoLines(t *testing.T) {
+	lines := []string{
+		"   org.slf4j:slf4j-api:jar:1.7.30:compile",
+		"   org.yaml:snakeyaml:jar:1.29:runtime",
+	}
+
+	parent := models.Module{Name: "root", Modules: map[string]*models.Module{}}
+	modules := appendListedDependencies(nil, parent, gopom.Project{}, lines, mavenOptions{})
+
+	assert.Len(t, modules, 2)
+	assert.Equal(t, "slf4j-api", modules[0].Name)
+	assert.Equal(t, "1.7.30", modules[0].Version)
+	assert.Equal(t, "snakeyaml", modules[1].Name)
+	assert.Equal(t, "1.29", modules[1].Version)
+	assert.Contains(t, parent.Modules, "slf4j-api")
+	assert.Contains(t, parent.Modules, "snakeyaml")
+}
+
+func TestAppendListedDependenciesTestScope(t *testing.T) {
+	lines := []string{
+		"   org.slf4j:slf4j-api:jar:1.7.30:compile",
+		"   junit:junit:jar:4.13.2:test",
+	}
+
+	parent := models.Module{Name: "root", Modules: map[string]*models.Module{}}
+	modules := append([]models.Module{parent}, appendListedDependencies(nil, parent, gopom.Project{}, lines, mavenOptions{})...)
+
+	assert.Equal(t, models.RelationshipDependsOn, parent.Modules["slf4j-api"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, parent.Modules["junit"].Relationship)
+
+	// linking the dependency tree keeps the scope of the declaration
+	buildDependenciesGraph(modules, map[string][]string{"root": {"slf4j-api", "junit"}})
+	assert.Equal(t, models.RelationshipDevDependencyOf, modules[0].Modules["junit"].Relationship)
+	assert.Equal(t, models.RelationshipDependsOn, modules[0].Modules["slf4j-api"].Relationship)
+}
+
+func TestAppendListedDependenciesFiltersScopes(t *testing.T) {
+	lines := []string{
+		"   org.slf4j:slf4j-api:jar:1.7.30:compile",
+		"   org.yaml:snakeyaml:jar:1.29:runtime",
+		"   javax.servlet:javax.servlet-api:jar:4.0.1:provided",
+		"   junit:junit:jar:4.13.2:test",
+	}
+
+	names := func(opts mavenOptions) []string {
+		parent := models.Module{Name: "root", Modules: map[string]*models.Module{}}
+		var listed []string
+		for _, module := range appendListedDependencies(nil, parent, gopom.Project{}, lines, opts) {
+			listed = append(listed, module.Name)
+		}
+		return listed
+	}
+
+	assert.Equal(t, []string{"slf4j-api", "snakeyaml", "javax.servlet-api", "junit"}, names(mavenOptions{}))
+	assert.Equal(t, []string{"slf4j-api", "snakeyaml"}, names(mavenOptions{ExcludeScopes: []string{"test", "provided"}}))
+	assert.Equal(t, []string{"slf4j-api", "javax.servlet-api"}, names(mavenOptions{IncludeScopes: []string{"compile", "provided"}}))
+	assert.Equal(t, []string{"slf4j-api"}, names(mavenOptions{IncludeScopes: []string{"compile", "provided"}, ExcludeScopes: []string{"provided"}}))
+
+	parent := models.Module{Name: "root", Modules: map[string]*models.Module{}}
+	appendListedDependencies(nil, parent, gopom.Project{}, lines, mavenOptions{})
+	assert.Equal(t, models.RelationshipRuntimeDependencyOf, parent.Modules["snakeyaml"].Relationship)
+	assert.Equal(t, models.RelationshipProvidedDependencyOf, parent.Modules["javax.servlet-api"].Relationship)
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+
+	"github.com/vifraa/gopom"
+)
+
+// effectivePom runs mvn help:effective-pom, replaced by the tests
+var effectivePom = getEffectivePom
+
+// getEffectivePom returns the effective model Maven computes for the pom of the directory: the parents,
+// imported BOMs and active profiles are merged and the properties interpolated. Only this pom is
+// computed, not the ones of its modules
+func getEffectivePom(ctx context.Context, workingDir string, opts mavenOptions) (gopom.Project, error) {
+	var project gopom.Project
+
+	output, err := ioutil.TempFile("", "spdx-effective-pom-*.xml")
+	if err != nil {
+		return project, err
+	}
+	output.Close()
+	defer os.Remove(output.Name())
+
+	command := exec.CommandContext(ctx, opts.executable(), opts.args("-B", "-N", "help:effective-pom", "-Doutput="+output.Name())...)
+	command.Dir = workingDir
+	if out, err := command.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return project, ctx.Err()
+		}
+		log.Print(string(out))
+		return project, offlineError(err, opts)
+	}
+
+	data, err := ioutil.ReadFile(output.Name())
+	if err != nil {
+		return project, err
+	}
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return project, err
+	}
+
+	return project, nil
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+)
+
+func stubEffectivePom(t *testing.T, err error) *[]string {
+	previous := effectivePom
+	t.Cleanup(func() { effectivePom = previous })
+
+	var calls []string
+	effectivePom = func(_ context.Context, dir string, _ mavenOptions) (gopom.Project, error) {
+		calls = append(calls, dir)
+
+		var project gopom.Project
+		if err != nil {
+			return project, err
+		}
+		data, readErr := ioutil.ReadFile(filepath.Join("testdata", "effective-pom.xml"))
+		assert.NoError(t, readErr)
+		assert.NoError(t, xml.Unmarshal(data, &project))
+		return project, nil
+	}
+	return &calls
+}
+
+func dependencyVersions(project gopom.Project) map[string]string {
+	versions := map[string]string{}
+	for _, dep := range project.Dependencies {
+		versions[dep.ArtifactID] = dep.Version
+	}
+	return versions
+}
+
+func TestReadAndLoadPomFileReadsEffectivePom(t *testing.T) {
+	calls := stubEffectivePom(t, nil)
+	path := filepath.Join("testdata", "self-reference")
+
+	project, err := readAndLoadPomFile(context.Background(), path, mavenOptions{EffectivePom: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{path}, *calls)
+	assert.Equal(t, map[string]string{
+		"example-api":   "2.1.0",
+		"example-model": "2.1.0",
+		"junit":         "4.13.2",
+		"slf4j-api":     "1.7.30",
+	}, dependencyVersions(project))
+}
+
+func TestReadAndLoadPomFileFallsBackWithoutEffectivePom(t *testing.T) {
+	path := filepath.Join("testdata", "self-reference")
+
+	calls := stubEffectivePom(t, errors.New("exit status 1"))
+	project, err := readAndLoadPomFile(context.Background(), path, mavenOptions{EffectivePom: true})
+	assert.NoError(t, err)
+	assert.Len(t, *calls, 1)
+	// the pom.xml is read instead, with its properties interpolated
+	assert.Equal(t, "4.13.2", dependencyVersions(project)["junit"])
+
+	calls = stubEffectivePom(t, nil)
+	_, err = readAndLoadPomFile(context.Background(), path, mavenOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, *calls)
+}
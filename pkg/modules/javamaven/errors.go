@@ -9,4 +9,13 @@ import (
 type errType error
 
 var errFailedToConvertModules errType = errors.New("failed to convert modules")
-var moduleNotFound errType = errors.New("module not found")
+
+var errChecksumNotFound errType = errors.New("checksum not found in any repository")
+var errInvalidRepositoryURL errType = errors.New("invalid repository url")
+var errRepositoryUnreachable errType = errors.New("repository unreachable")
+var errUnexpectedStatus errType = errors.New("unexpected repository response")
+var errPomNotFound errType = errors.New("pom not found in any repository")
+var errInvalidChecksum errType = errors.New("invalid checksum file")
+var errSettingsNotFound errType = errors.New("maven settings file not found")
+var errMissingOffline errType = errors.New("artifacts missing from the local repository while offline")
+var errChecksumMismatch errType = errors.New("the artifact in the local repository does not match its .sha1 file")
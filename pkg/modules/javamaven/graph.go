@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"sync"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// moduleGraph owns the modules of a project while they are enriched and linked.
+// Enrichment may run from several goroutines, linking mutates the Modules maps,
+// so both go through the same lock
+type moduleGraph struct {
+	mu      sync.Mutex
+	modules []models.Module
+}
+
+func newModuleGraph(modules []models.Module) *moduleGraph {
+	return &moduleGraph{modules: modules}
+}
+
+// update applies fn to the module at idx, fn must not retain the pointer
+func (g *moduleGraph) update(idx int, fn func(mod *models.Module)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fn(&g.modules[idx])
+}
+
+// link adds the transitive dependencies to the modules, it copies the dependency
+// as it is at that time so enrichment should be done before
+func (g *moduleGraph) link(tdList map[string][]string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	buildDependenciesGraph(g.modules, tdList)
+}
+
+// Modules returns the modules once enrichment and linking are done
+func (g *moduleGraph) Modules() []models.Module {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.modules
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// run with -race, enrichment goroutines update modules while the graph is linked
+func TestModuleGraphConcurrentEnrichment(t *testing.T) {
+	var modules []models.Module
+	tdList := map[string][]string{}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("artifact-%d", i)
+		modules = append(modules, models.Module{
+			Name:    name,
+			Version: "1.0.0",
+			Modules: map[string]*models.Module{},
+		})
+		if i > 0 {
+			tdList[name] = []string{fmt.Sprintf("artifact-%d", i-1)}
+		}
+	}
+
+	graph := newModuleGraph(modules)
+
+	var wg sync.WaitGroup
+	for i := range modules {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			graph.update(idx, func(mod *models.Module) {
+				mod.LicenseDeclared = "Apache-2.0"
+				mod.Modules[fmt.Sprintf("extra-%d", idx)] = &models.Module{Name: "extra"}
+			})
+		}(i)
+	}
+
+	graph.link(tdList)
+	wg.Wait()
+
+	result := graph.Modules()
+	assert.Len(t, result, 50)
+	for i, mod := range result {
+		assert.Equal(t, "Apache-2.0", mod.LicenseDeclared)
+		if i > 0 {
+			assert.Contains(t, mod.Modules, fmt.Sprintf("artifact-%d", i-1))
+		}
+	}
+}
@@ -3,21 +3,31 @@
 package javamaven
 
 import (
-	"crypto/sha1"
-	"encoding/hex"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os/exec"
 	"path/filepath"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/versionlock"
 )
 
+// transitiveSkippedAnnotation notes the document only lists the dependencies declared in the pom
+const transitiveSkippedAnnotation = "mvn dependency:tree failed, transitive dependencies were not resolved: only the dependencies declared in the pom are listed"
+
+// lockfileOnlyAnnotation notes the document only lists the dependencies declared in the pom, mvn was not run
+const lockfileOnlyAnnotation = "generated in the lockfile-only mode, transitive dependencies were not resolved: only the dependencies declared in the pom are listed"
+
 type javamaven struct {
 	metadata   models.PluginMetadata
 	rootModule *models.Module
 	command    *helper.Cmd
+	options    models.PluginOptions
+	// wrapper is the mvnw of the project, if any
+	wrapper string
 }
 
 // New ...
@@ -39,8 +49,18 @@ func (m *javamaven) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
+// SetOptions ...
+func (m *javamaven) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // SetRootModule ...
 func (m *javamaven) SetRootModule(path string) error {
+	m.wrapper = mavenWrapper(path)
+	if m.wrapper != "" {
+		log.Printf("using the maven wrapper %s", m.wrapper)
+	}
+
 	module, err := m.getModule(path)
 	if err != nil {
 		return err
@@ -63,6 +83,12 @@ func (m *javamaven) IsValid(path string) bool {
 
 // HasModulesInstalled ...
 func (m *javamaven) HasModulesInstalled(path string) error {
+	// the wrapper downloads the Maven version the project pins itself, and the lockfile-only mode
+	// reads the poms without Maven
+	if m.wrapper != "" || m.options.LockfileOnly {
+		return nil
+	}
+
 	// TODO: How to verify is java project is build
 	// Enforcing mvn path to be set in PATH variable
 	fname, err := exec.LookPath("mvn")
@@ -82,7 +108,13 @@ func (m *javamaven) HasModulesInstalled(path string) error {
 
 // GetVersion...
 func (m *javamaven) GetVersion() (string, error) {
-	err := m.buildCmd(VersionCmd, ".")
+	dir := "."
+	// the wrapper reads its .mvn/wrapper settings from the project directory
+	if m.wrapper != "" {
+		dir = filepath.Dir(m.wrapper)
+	}
+
+	err := m.buildCmd(VersionCmd, dir)
 	if err != nil {
 		return "", err
 	}
@@ -90,6 +122,11 @@ func (m *javamaven) GetVersion() (string, error) {
 	return m.command.Output()
 }
 
+// GetExecutable returns the Maven binary the generation runs, mvnw when the project has a wrapper
+func (m *javamaven) GetExecutable() string {
+	return filepath.Base(m.mavenOptions().executable())
+}
+
 // GetRootModule...
 func (m *javamaven) GetRootModule(path string) (*models.Module, error) {
 	if m.rootModule == nil {
@@ -106,36 +143,77 @@ func (m *javamaven) GetRootModule(path string) (*models.Module, error) {
 
 // ListUsedModules...
 func (m *javamaven) ListUsedModules(path string) ([]models.Module, error) {
-	modules, err := convertPOMReaderToModules(path, true)
+	modules, err := convertPOMReaderToModules(m.context(), path, true, m.mavenOptions())
 
 	if err != nil {
 		log.Println(err)
 		return modules, err
 	}
 
-	return modules, nil
+	return m.applyVersionLockAndMirrors(modules)
 }
 
 // ListModulesWithDeps ...
 func (m *javamaven) ListModulesWithDeps(path string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+	modules, err := convertPOMReaderToModules(m.context(), path, true, m.mavenOptions())
 	if err != nil {
+		log.Println(err)
 		return nil, err
 	}
 
-	tdList, err := getTransitiveDependencyList(path)
+	tree := dependencyTree{}
+	if m.options.LockfileOnly {
+		annotateRoot(modules, lockfileOnlyAnnotation)
+	} else if tree, err = transitiveDependencyList(m.context(), path, m.mavenOptions()); err != nil {
+		if m.context().Err() != nil {
+			return nil, err
+		}
+		// the pom still lists the direct dependencies, report them without their transitive ones
+		log.Printf("mvn dependency:tree failed, only the declared dependencies are reported: %v", err)
+		annotateRoot(modules, transitiveSkippedAnnotation)
+		tree = dependencyTree{}
+	}
+	applyResolvedVersions(modules, tree.Versions)
+
+	modules, err = m.applyVersionLockAndMirrors(modules)
 	if err != nil {
-		fmt.Println("error in getting mvn transitive dependency tree and parsing it")
 		return nil, err
 	}
 
-	buildDependenciesGraph(modules, tdList)
+	if m.options.AllowNetwork && !m.options.Offline {
+		if err := m.fetchChecksums(path, modules); err != nil {
+			log.Println(err)
+		}
+	}
+
+	graph := newModuleGraph(modules)
+	graph.link(tree.Dependencies)
+
+	return graph.Modules(), nil
+}
+
+// applyVersionLockAndMirrors pins the versions of the lock file, which win over the ones maven resolved,
+// then points the download locations to the mirror of Maven Central
+func (m *javamaven) applyVersionLockAndMirrors(modules []models.Module) ([]models.Module, error) {
+	if m.options.VersionLockFile != "" {
+		versions, err := versionlock.Load(m.options.VersionLockFile)
+		if err != nil {
+			return nil, err
+		}
+		applyVersionLock(modules, versions)
+	}
+
+	settings, err := m.settings()
+	if err != nil {
+		return nil, err
+	}
+	applyMirrorLocations(modules, settings)
 
 	return modules, nil
 }
 
 func (m *javamaven) getModule(path string) (models.Module, error) {
-	modules, err := convertPOMReaderToModules(path, false)
+	modules, err := convertPOMReaderToModules(m.context(), path, false, m.mavenOptions())
 
 	if err != nil {
 		log.Println(err)
@@ -151,11 +229,15 @@ func (m *javamaven) getModule(path string) (models.Module, error) {
 
 func (m *javamaven) buildCmd(cmd command, path string) error {
 	cmdArgs := cmd.Parse()
+	if cmdArgs[0] == "mvn" {
+		cmdArgs[0] = m.mavenOptions().executable()
+	}
 
 	command := helper.NewCmd(helper.CmdOptions{
 		Name:      cmdArgs[0],
 		Args:      cmdArgs[1:],
 		Directory: path,
+		Context:   m.context(),
 	})
 
 	m.command = command
@@ -163,8 +245,84 @@ func (m *javamaven) buildCmd(cmd command, path string) error {
 	return command.Build()
 }
 
-func readCheckSum(content string) string {
-	h := sha1.New()
-	h.Write([]byte(content))
-	return hex.EncodeToString(h.Sum(nil))
+// fetchChecksums sets the checksums, licenses, home pages and scm repositories of the modules missing from the
+// local repository to the ones published by the project repositories and Maven Central, authenticating with
+// the settings.xml servers
+func (m *javamaven) fetchChecksums(path string, modules []models.Module) error {
+	project, err := readAndLoadPomFile(m.context(), path, m.mavenOptions())
+	if err != nil {
+		return err
+	}
+
+	settings, err := m.settings()
+	if err != nil {
+		return err
+	}
+
+	fetcher := newChecksumFetcher(repositories(project, settings))
+	fetcher.client.Transport = &http.Transport{Proxy: settings.proxyFunc()}
+	fetcher.types = dependencyTypes(project)
+	fetcher.extensions = m.options.PackagingExtensions
+	fetcher.parallelism = m.options.MavenParallelism
+	fetcher.enrichChecksums(m.context(), modules, m.options.Cache)
+	fetcher.enrichPomMetadata(m.context(), modules, m.options.Cache)
+
+	return nil
+}
+
+// settings reads the settings.xml given in the options, the user one otherwise
+func (m *javamaven) settings() (mavenSettings, error) {
+	if m.options.MavenSettings == "" {
+		return readSettings(defaultSettingsPath())
+	}
+	if !helper.Exists(m.options.MavenSettings) {
+		return mavenSettings{}, fmt.Errorf("%w: %s", errSettingsNotFound, m.options.MavenSettings)
+	}
+	return readSettings(m.options.MavenSettings)
+}
+
+// annotateRoot adds the note to the root module
+func annotateRoot(modules []models.Module, annotation string) {
+	for i := range modules {
+		if modules[i].Root {
+			modules[i].Unresolved = append(modules[i].Unresolved, annotation)
+			return
+		}
+	}
+}
+
+// mavenOptions returns the options of the pom reads and mvn runs
+func (m *javamaven) mavenOptions() mavenOptions {
+	return mavenOptions{
+		Profiles:          m.options.MavenProfiles,
+		EffectivePom:      m.options.MavenEffectivePom,
+		IncludeScopes:     m.options.IncludeScopes,
+		ExcludeScopes:     m.options.ExcludeScopes,
+		Settings:          settingsPath(m.options.MavenSettings),
+		Offline:           m.options.Offline,
+		IncludeBuildTools: m.options.IncludeBuildTools,
+		Executable:        m.wrapper,
+		Parallelism:       m.options.MavenParallelism,
+		LockfileOnly:      m.options.LockfileOnly,
+		LicenseMatcher:    m.options.LicenseMatcher,
+	}
+}
+
+// settingsPath returns the absolute settings.xml path, mvn runs in the project directory
+func settingsPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		return absPath
+	}
+	return path
+}
+
+// context returns the generation context, falling back to a background one
+func (m *javamaven) context() context.Context {
+	if m.options.Context == nil {
+		return context.Background()
+	}
+	return m.options.Context
 }
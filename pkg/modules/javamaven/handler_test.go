@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDepsWithoutTree(t *testing.T) {
+	defer func(list func(context.Context, string, mavenOptions) (dependencyTree, error)) {
+		transitiveDependencyList = list
+	}(transitiveDependencyList)
+	transitiveDependencyList = func(context.Context, string, mavenOptions) (dependencyTree, error) {
+		return dependencyTree{}, errors.New("could not resolve dependencies, offline mode")
+	}
+
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "self-reference"))
+	assert.NoError(t, err)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, []string{transitiveSkippedAnnotation}, root.Unresolved)
+	assert.Contains(t, root.Modules, "example-api")
+	assert.Contains(t, root.Modules, "example-model")
+	assert.Contains(t, root.Modules, "junit")
+}
+
+func TestListModulesWithDepsLockfileOnly(t *testing.T) {
+	defer func(list func(context.Context, string, mavenOptions) ([]string, error), tree func(context.Context, string, mavenOptions) (dependencyTree, error)) {
+		dependencyList, transitiveDependencyList = list, tree
+	}(dependencyList, transitiveDependencyList)
+	dependencyList = func(context.Context, string, mavenOptions) ([]string, error) {
+		t.Error("mvn dependency:list is run in the lockfile-only mode")
+		return nil, nil
+	}
+	transitiveDependencyList = func(context.Context, string, mavenOptions) (dependencyTree, error) {
+		t.Error("mvn dependency:tree is run in the lockfile-only mode")
+		return dependencyTree{}, nil
+	}
+
+	m := New()
+	m.SetOptions(models.PluginOptions{LockfileOnly: true})
+	path := filepath.Join("testdata", "self-reference")
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+
+	root := modules[0]
+	assert.Equal(t, []string{lockfileOnlyAnnotation}, root.Unresolved)
+	assert.Contains(t, root.Modules, "example-api")
+	assert.Contains(t, root.Modules, "junit")
+}
+
+func TestListUsedModulesParsesDependencyListOutput(t *testing.T) {
+	defer func(list func(context.Context, string, mavenOptions) ([]string, error)) {
+		dependencyList = list
+	}(dependencyList)
+
+	path := filepath.Join("testdata", "self-reference")
+	var workingDir string
+	dependencyList = func(_ context.Context, dir string, _ mavenOptions) ([]string, error) {
+		workingDir = dir
+		output, err := ioutil.ReadFile(filepath.Join("testdata", "dependency-list-de.out"))
+		return strings.Split(string(output), "\n"), err
+	}
+
+	modules, err := New().ListUsedModules(path)
+	assert.NoError(t, err)
+	assert.Equal(t, path, workingDir)
+
+	versions := map[string]string{}
+	for _, module := range modules {
+		versions[module.Name] = module.Version
+	}
+	assert.Equal(t, "1.7.30", versions["slf4j-api"])
+	assert.Equal(t, "1.3", versions["hamcrest-core"])
+	assert.Equal(t, "4.1.65.Final", versions["netty-transport-native-epoll"])
+	assert.Equal(t, "4.13.2", versions["junit"])
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+// maxInterpolationDepth bounds the nested property references, properties referencing each other would never end
+const maxInterpolationDepth = 32
+
+// propertyReference matches the innermost ${...} references, so a reference in the name of another one,
+// e.g. ${lib.${flavor}.version}, resolves first
+var propertyReference = regexp.MustCompile(`\$\{([^${}]+)\}`)
+
+// resolveProperty interpolates the ${...} references of the value with the built-in project properties,
+// the properties of the project, which hold the ones of its parents and active profiles, and the env.
+// variables. The resolved values are interpolated in turn, a reference that cannot be resolved is left
+// as is, as Maven does, and so is a value whose properties reference each other
+func resolveProperty(value string, project gopom.Project) string {
+	seen := map[string]bool{}
+	resolved := value
+	for depth := 0; depth < maxInterpolationDepth && strings.Contains(resolved, "${"); depth++ {
+		seen[resolved] = true
+		interpolated := propertyReference.ReplaceAllStringFunc(resolved, func(reference string) string {
+			if property, ok := lookupProperty(reference[2:len(reference)-1], project); ok {
+				return property
+			}
+			return reference
+		})
+		if interpolated == resolved {
+			break
+		}
+		if seen[interpolated] {
+			return value
+		}
+		resolved = interpolated
+	}
+	return resolved
+}
+
+// lookupProperty returns the value of the property named in a ${...} reference
+func lookupProperty(name string, project gopom.Project) (string, bool) {
+	name = strings.TrimSpace(name)
+	switch {
+	case strings.HasPrefix(name, "project."), strings.HasPrefix(name, "pom."), strings.HasPrefix(name, "parent."):
+		if resolved, ok := projectProperty(name, project); ok {
+			return resolved, true
+		}
+	case strings.HasPrefix(name, "env."):
+		return os.LookupEnv(strings.TrimPrefix(name, "env."))
+	}
+
+	value, ok := project.Properties.Entries[name]
+	return value, ok
+}
+
+// interpolateProject resolves the property references of the coordinates, dependencies, plugins, modules,
+// repositories and licenses of the project once it holds the properties of its parents and profiles,
+// so ${project.version} in a dependency inherited from a parent is the version of the project
+func interpolateProject(project gopom.Project) gopom.Project {
+	resolved := project
+	resolved.GroupID = resolveProperty(project.GroupID, project)
+	resolved.ArtifactID = resolveProperty(project.ArtifactID, project)
+	resolved.Version = resolveProperty(project.Version, project)
+	resolved.Packaging = resolveProperty(project.Packaging, project)
+	resolved.Name = resolveProperty(project.Name, project)
+	resolved.URL = resolveProperty(project.URL, project)
+
+	resolved.Dependencies = interpolateDependencies(project.Dependencies, project)
+	resolved.DependencyManagement.Dependencies = interpolateDependencies(project.DependencyManagement.Dependencies, project)
+	resolved.Build.Plugins = interpolatePlugins(project.Build.Plugins, project)
+	resolved.Build.PluginManagement.Plugins = interpolatePlugins(project.Build.PluginManagement.Plugins, project)
+
+	if project.Modules != nil {
+		resolved.Modules = make([]string, len(project.Modules))
+		for i, module := range project.Modules {
+			resolved.Modules[i] = resolveProperty(module, project)
+		}
+	}
+	if project.Repositories != nil {
+		resolved.Repositories = make([]gopom.Repository, len(project.Repositories))
+		for i, repository := range project.Repositories {
+			resolved.Repositories[i] = repository
+			resolved.Repositories[i].URL = resolveProperty(repository.URL, project)
+		}
+	}
+	if project.Licenses != nil {
+		resolved.Licenses = make([]gopom.License, len(project.Licenses))
+		for i, license := range project.Licenses {
+			resolved.Licenses[i] = license
+			resolved.Licenses[i].Name = resolveProperty(license.Name, project)
+			resolved.Licenses[i].URL = resolveProperty(license.URL, project)
+		}
+	}
+
+	return resolved
+}
+
+// interpolateDependencies returns a copy of the dependencies with their references resolved, the
+// slice may be shared with a parent or a profile
+func interpolateDependencies(dependencies []gopom.Dependency, project gopom.Project) []gopom.Dependency {
+	if dependencies == nil {
+		return nil
+	}
+
+	resolved := make([]gopom.Dependency, len(dependencies))
+	for i, dependency := range dependencies {
+		resolved[i] = dependency
+		resolved[i].GroupID = resolveProperty(dependency.GroupID, project)
+		resolved[i].ArtifactID = resolveProperty(dependency.ArtifactID, project)
+		resolved[i].Version = resolveProperty(dependency.Version, project)
+		resolved[i].Type = resolveProperty(dependency.Type, project)
+		resolved[i].Classifier = resolveProperty(dependency.Classifier, project)
+		resolved[i].Scope = resolveProperty(dependency.Scope, project)
+		resolved[i].SystemPath = resolveProperty(dependency.SystemPath, project)
+	}
+	return resolved
+}
+
+// interpolatePlugins returns a copy of the plugins with their coordinates and dependencies resolved
+func interpolatePlugins(plugins []gopom.Plugin, project gopom.Project) []gopom.Plugin {
+	if plugins == nil {
+		return nil
+	}
+
+	resolved := make([]gopom.Plugin, len(plugins))
+	for i, plugin := range plugins {
+		resolved[i] = plugin
+		resolved[i].GroupID = resolveProperty(plugin.GroupID, project)
+		resolved[i].ArtifactID = resolveProperty(plugin.ArtifactID, project)
+		resolved[i].Version = resolveProperty(plugin.Version, project)
+		resolved[i].Dependencies = interpolateDependencies(plugin.Dependencies, project)
+	}
+	return resolved
+}
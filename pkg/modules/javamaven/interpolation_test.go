@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+)
+
+func TestResolveProperty(t *testing.T) {
+	os.Setenv("SPDX_TEST_INTERPOLATION_HOME", "/opt/example")
+	defer os.Unsetenv("SPDX_TEST_INTERPOLATION_HOME")
+
+	project := gopom.Project{
+		Parent:     gopom.Parent{GroupID: "org.example", ArtifactID: "example-parent", Version: "2.0.0"},
+		ArtifactID: "example-lib",
+		Packaging:  "bundle",
+		Properties: gopom.Properties{Entries: map[string]string{
+			"netty.major":   "4.1",
+			"netty.version": "${netty.major}.65",
+			"flavor":        "native",
+			"lib.native":    "epoll",
+			"self":          "${self}",
+			"ping":          "${pong}",
+			"pong":          "${ping}",
+			"empty":         "",
+		}},
+	}
+
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"1.0.0", "1.0.0"},
+		{"${project.version}", "2.0.0"},
+		{"${pom.groupId}", "org.example"},
+		{"${parent.artifactId}", "example-parent"},
+		{"${project.parent.version}", "2.0.0"},
+		{"${project.packaging}", "bundle"},
+		{"${netty.version}.Final", "4.1.65.Final"},
+		{"${project.artifactId}-${project.version}", "example-lib-2.0.0"},
+		{"${lib.${flavor}}", "epoll"},
+		{"${env.SPDX_TEST_INTERPOLATION_HOME}/lib", "/opt/example/lib"},
+		{"${empty}", ""},
+		{"${undefined}", "${undefined}"},
+		{"${undefined}-${project.version}", "${undefined}-2.0.0"},
+		{"${self}", "${self}"},
+		{"${ping}", "${ping}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			assert.Equal(t, test.expected, resolveProperty(test.value, project))
+		})
+	}
+}
+
+func TestReadAndLoadPomFileInterpolatesProperties(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = ""
+
+	path := filepath.Join("testdata", "interpolation", "app")
+
+	tests := []struct {
+		name     string
+		profiles []string
+		expected map[string]string
+	}{
+		{
+			name: "nested properties",
+			expected: map[string]string{
+				"example-interpolation-core":   "5.2.0-app",
+				"netty-transport-native-epoll": "4.1.65.Final",
+				"example-undefined":            "${undefined.version}",
+				"example-cycle":                "${ping}",
+			},
+		},
+		{
+			name:     "profile properties",
+			profiles: []string{"legacy"},
+			expected: map[string]string{
+				"example-interpolation-core":   "5.2.0-app",
+				"netty-transport-native-epoll": "4.0.65.Final",
+				"example-undefined":            "${undefined.version}",
+				"example-cycle":                "${ping}",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project, err := readAndLoadPomFile(context.Background(), path, mavenOptions{Profiles: test.profiles})
+			assert.NoError(t, err)
+
+			assert.Equal(t, "org.example.interpolation", project.GroupID)
+			assert.Equal(t, "5.2.0-app", project.Version)
+			assert.Equal(t, "example-interpolation-app", project.Name)
+			assert.Equal(t, test.expected, dependencyVersions(project))
+
+			index := findDependency(project.Dependencies, "io.netty", "netty-transport-native-epoll")
+			assert.Equal(t, "linux-x86_64", project.Dependencies[index].Classifier)
+			// the inherited dependency takes the groupId of the project
+			assert.GreaterOrEqual(t, findDependency(project.Dependencies, "org.example.interpolation", "example-interpolation-core"), 0)
+
+			root := convertProjectLevelPackageToModule(project, nil)
+			assert.Equal(t, "example-interpolation-app", root.Name)
+			assert.Equal(t, "5.2.0-app", root.Version)
+			assert.Equal(t, "pkg:maven/org.example.interpolation/example-interpolation-app@5.2.0-app", root.PackageURL)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// pomLicenseNames maps the license names poms commonly declare to their SPDX identifier,
+// the names are lower case with single spaces
+var pomLicenseNames = map[string]string{
+	"apache 2":                                 "Apache-2.0",
+	"apache 2.0":                               "Apache-2.0",
+	"apache license 2.0":                       "Apache-2.0",
+	"apache license, version 2.0":              "Apache-2.0",
+	"apache software license - version 2.0":    "Apache-2.0",
+	"the apache license, version 2.0":          "Apache-2.0",
+	"the apache software license, version 2.0": "Apache-2.0",
+	"mit":                                     "MIT",
+	"mit license":                             "MIT",
+	"the mit license":                         "MIT",
+	"the mit license (mit)":                   "MIT",
+	"bsd 2-clause license":                    "BSD-2-Clause",
+	"bsd 3-clause license":                    "BSD-3-Clause",
+	"new bsd license":                         "BSD-3-Clause",
+	"revised bsd license":                     "BSD-3-Clause",
+	"the bsd 3-clause license":                "BSD-3-Clause",
+	"eclipse distribution license - v 1.0":    "BSD-3-Clause",
+	"eclipse public license - v 1.0":          "EPL-1.0",
+	"eclipse public license 1.0":              "EPL-1.0",
+	"eclipse public license - v 2.0":          "EPL-2.0",
+	"eclipse public license 2.0":              "EPL-2.0",
+	"eclipse public license v2.0":             "EPL-2.0",
+	"mozilla public license 2.0":              "MPL-2.0",
+	"mozilla public license, version 2.0":     "MPL-2.0",
+	"cddl 1.1":                                "CDDL-1.1",
+	"cddl v1.1":                               "CDDL-1.1",
+	"cddl 1.0":                                "CDDL-1.0",
+	"the unlicense":                           "Unlicense",
+	"cc0":                                     "CC0-1.0",
+	"public domain, per creative commons cc0": "CC0-1.0",
+}
+
+// pomLicenseURLs maps the license urls poms commonly declare to their SPDX identifier,
+// the urls are lower case without scheme, www. prefix, extension or trailing slash
+var pomLicenseURLs = map[string]string{
+	"apache.org/licenses/license-2.0":           "Apache-2.0",
+	"opensource.org/licenses/apache-2.0":        "Apache-2.0",
+	"opensource.org/licenses/mit":               "MIT",
+	"opensource.org/licenses/mit-license":       "MIT",
+	"opensource.org/licenses/bsd-2-clause":      "BSD-2-Clause",
+	"opensource.org/licenses/bsd-3-clause":      "BSD-3-Clause",
+	"opensource.org/licenses/bsd-license":       "BSD-3-Clause",
+	"eclipse.org/org/documents/edl-v10":         "BSD-3-Clause",
+	"eclipse.org/legal/epl-v10":                 "EPL-1.0",
+	"eclipse.org/legal/epl-2.0":                 "EPL-2.0",
+	"eclipse.org/legal/epl-v20":                 "EPL-2.0",
+	"mozilla.org/mpl/2.0":                       "MPL-2.0",
+	"opensource.org/licenses/cddl-1.0":          "CDDL-1.0",
+	"unlicense.org":                             "Unlicense",
+	"creativecommons.org/publicdomain/zero/1.0": "CC0-1.0",
+}
+
+// pomReader reads the pom of a coordinate, from the local repository or a remote one
+type pomReader func(coordinate mavenCoordinate) (gopom.Project, error)
+
+// localPomReader reads the poms installed in the repository
+func localPomReader(repository string) pomReader {
+	return func(coordinate mavenCoordinate) (gopom.Project, error) {
+		if repository == "" {
+			return gopom.Project{}, os.ErrNotExist
+		}
+		coordinate.Type, coordinate.Classifier = "pom", ""
+		return readPom(filepath.Join(repository, filepath.FromSlash(artifactPath(coordinate, nil))))
+	}
+}
+
+// pomLicenses returns the <licenses> of the pom of the coordinate, or the ones of the closest parent
+// declaring them as Maven inherits them. A pom that cannot be read ends the lookup
+func pomLicenses(coordinate mavenCoordinate, read pomReader) []gopom.License {
+	if coordinate.GroupID == "" || coordinate.Version == "" {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for depth := 0; depth < maxParentDepth; depth++ {
+		key := coordinate.GroupID + ":" + coordinate.ArtifactID + ":" + coordinate.Version
+		if seen[key] {
+			break
+		}
+		seen[key] = true
+
+		project, err := read(coordinate)
+		if err != nil {
+			return nil
+		}
+		if len(project.Licenses) > 0 {
+			return project.Licenses
+		}
+
+		parent := project.Parent
+		if parent.ArtifactID == "" || parent.GroupID == "" || parent.Version == "" {
+			break
+		}
+		coordinate = mavenCoordinate{GroupID: parent.GroupID, ArtifactID: parent.ArtifactID, Version: parent.Version, Type: "pom"}
+	}
+
+	return nil
+}
+
+// licenseExpression returns the SPDX expression of the pom licenses, any of which may be chosen.
+// The expression is empty when a license is not known, it would misstate the licensing otherwise
+func licenseExpression(licenses []gopom.License) string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, license := range licenses {
+		id := spdxLicenseID(license)
+		if id == "" {
+			return ""
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return strings.Join(ids, " OR ")
+}
+
+// spdxLicenseID identifies a pom license by its name, which may be an SPDX identifier, then by its url
+func spdxLicenseID(license gopom.License) string {
+	name := strings.TrimSpace(license.Name)
+	if helper.LicenseSPDXExists(name) {
+		return name
+	}
+	if id, ok := pomLicenseNames[strings.Join(strings.Fields(strings.ToLower(name)), " ")]; ok {
+		return id
+	}
+
+	url := trimLicenseURL(license.URL)
+	// the identifiers are case sensitive, the url is only lower cased to look it up
+	if index := strings.Index(strings.ToLower(url), "spdx.org/licenses/"); index >= 0 {
+		if id := url[index+len("spdx.org/licenses/"):]; helper.LicenseSPDXExists(id) {
+			return id
+		}
+	}
+	return pomLicenseURLs[strings.ToLower(url)]
+}
+
+// trimLicenseURL removes the scheme, www. prefix, extension and trailing slash of a license url
+func trimLicenseURL(url string) string {
+	url = strings.TrimSpace(url)
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		if strings.HasPrefix(strings.ToLower(url), prefix) {
+			url = url[len(prefix):]
+		}
+	}
+	url = strings.TrimSuffix(url, "/")
+	for _, extension := range []string{".txt", ".html", ".php"} {
+		if strings.HasSuffix(strings.ToLower(url), extension) {
+			url = url[:len(url)-len(extension)]
+		}
+	}
+	return url
+}
+
+// setPomLicense declares the license of the pom licenses on the module, if they are all known
+func setPomLicense(mod *models.Module, licenses []gopom.License) {
+	if license := licenseExpression(licenses); license != "" {
+		mod.LicenseDeclared = license
+		mod.LicenseConcluded = license
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestCreateModuleReadsPomLicenses(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	junit := createModule("junit", "junit", "4.13.2", gopom.Project{})
+	assert.Equal(t, "EPL-1.0", junit.LicenseDeclared)
+	assert.Equal(t, "EPL-1.0", junit.LicenseConcluded)
+
+	// inherited from the parent pom, either license may be chosen
+	child := createModule("org.example", "example-child-lib", "1.0", gopom.Project{})
+	assert.Equal(t, "Apache-2.0 OR MIT", child.LicenseDeclared)
+
+	missing := createModule("org.example", "example-missing", "1.0", gopom.Project{})
+	assert.Empty(t, missing.LicenseDeclared)
+}
+
+func TestLicenseExpression(t *testing.T) {
+	tests := []struct {
+		name     string
+		licenses []gopom.License
+		expected string
+	}{
+		{"spdx identifier", []gopom.License{{Name: "BSD-3-Clause"}}, "BSD-3-Clause"},
+		{"known name", []gopom.License{{Name: "  Apache License,\n Version 2.0 "}}, "Apache-2.0"},
+		{"known url", []gopom.License{{Name: "EPL 2", URL: "https://www.eclipse.org/legal/epl-2.0/"}}, "EPL-2.0"},
+		{"spdx url", []gopom.License{{Name: "Apache", URL: "https://spdx.org/licenses/Apache-2.0.html"}}, "Apache-2.0"},
+		{"duplicates", []gopom.License{{Name: "MIT"}, {Name: "The MIT License"}}, "MIT"},
+		{"unknown license", []gopom.License{{Name: "MIT"}, {Name: "ACME Proprietary", URL: "https://acme.example/license"}}, ""},
+		{"none", nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, licenseExpression(test.licenses))
+		})
+	}
+}
+
+func TestFetchPomMetadataFromRepository(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/com/acme/acme-core/1.0.0/acme-core-1.0.0.pom" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// the parent is read from the local repository
+		w.Write([]byte(`<project><parent><groupId>org.example</groupId><artifactId>example-oss-parent</artifactId><version>3</version></parent>
+<artifactId>acme-core</artifactId><url>https://acme.example/core</url>
+<scm><connection>scm:git:https://git.acme.example/acme-core.git</connection></scm></project>`))
+	}))
+	defer ts.Close()
+
+	fetcher := newChecksumFetcher([]remoteRepository{{ID: "internal", URL: ts.URL}})
+	modules := []models.Module{
+		{Name: "root", Root: true},
+		{Name: "acme-core", Version: "1.0.0", Path: "com.acme:acme-core"},
+		{Name: "acme-missing", Version: "1.0.0", Path: "com.acme:acme-missing"},
+		{Name: "acme-known", Version: "1.0.0", Path: "com.acme:acme-known", LicenseDeclared: "MIT"},
+	}
+	fetcher.enrichPomMetadata(context.Background(), modules, nil)
+
+	assert.Empty(t, modules[0].LicenseDeclared)
+	assert.Equal(t, "Apache-2.0 OR MIT", modules[1].LicenseDeclared)
+	assert.Equal(t, "Apache-2.0 OR MIT", modules[1].LicenseConcluded)
+	assert.Equal(t, "https://acme.example/core", modules[1].PackageHomePage)
+	assert.Equal(t, "scm repository: https://git.acme.example/acme-core.git", modules[1].SourceInfo)
+	assert.Empty(t, modules[2].LicenseDeclared)
+	assert.Equal(t, "MIT", modules[3].LicenseDeclared)
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// localChecksum returns the SHA256 of the artifact file in the local repository. When only the .sha1
+// file Maven stores next to it is left, its SHA1 is returned, nil when the artifact was never downloaded.
+// An artifact not matching its .sha1 file is returned with errChecksumMismatch
+func localChecksum(repository string, coordinate mavenCoordinate) (*models.CheckSum, error) {
+	if repository == "" || coordinate.GroupID == "" || coordinate.Version == "" {
+		return nil, nil
+	}
+
+	file := filepath.Join(repository, filepath.FromSlash(artifactPath(coordinate, nil)))
+	stored, storedErr := readSHA1File(file + ".sha1")
+
+	artifact, err := os.Open(file)
+	if err != nil {
+		if storedErr != nil {
+			return nil, nil
+		}
+		return &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: stored}, nil
+	}
+	defer artifact.Close()
+
+	sha1Hash, sha256Hash := sha1.New(), sha256.New()
+	if _, err := io.Copy(io.MultiWriter(sha1Hash, sha256Hash), artifact); err != nil {
+		return nil, nil
+	}
+
+	checksum := &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: hex.EncodeToString(sha256Hash.Sum(nil))}
+	if storedErr == nil && stored != hex.EncodeToString(sha1Hash.Sum(nil)) {
+		return checksum, fmt.Errorf("%w: %s", errChecksumMismatch, filepath.Base(file))
+	}
+	return checksum, nil
+}
+
+// readSHA1File reads a .sha1 file, either `<sha1>` or `<sha1>  <filename>`
+func readSHA1File(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || !sha1Regex.MatchString(fields[0]) {
+		return "", errInvalidChecksum
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// pomChecksum returns the SHA256 of the pom.xml of the project directory, nil when it cannot be read
+func pomChecksum(fpath string) *models.CheckSum {
+	content, err := ioutil.ReadFile(filepath.Join(fpath, "pom.xml"))
+	if err != nil {
+		return nil
+	}
+
+	return &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   content,
+	}
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestLocalChecksum(t *testing.T) {
+	repository := filepath.Join("testdata", "repository")
+
+	checksum, err := localChecksum(repository, mavenCoordinate{GroupID: "org.example", ArtifactID: "example-api", Version: "2.1.0", Type: "jar"})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Value:     "769ef8b26217a35eed37f35f953f8bf5fe555a5012c68fe3fce2ede2aa121067",
+	}, checksum)
+
+	// only the .sha1 file is left
+	checksum, err = localChecksum(repository, mavenCoordinate{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Type: "jar"})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.CheckSum{
+		Algorithm: models.HashAlgoSHA1,
+		Value:     "e0e6e5b3c3a1e8e4ef3b7b8d5a1b4fd9a4f6e0c2",
+	}, checksum)
+
+	checksum, err = localChecksum(repository, mavenCoordinate{GroupID: "org.example", ArtifactID: "missing", Version: "1.0.0", Type: "jar"})
+	assert.NoError(t, err)
+	assert.Nil(t, checksum)
+}
+
+func TestLocalChecksumMismatch(t *testing.T) {
+	repository, err := ioutil.TempDir("", "repository")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repository)
+
+	dir := filepath.Join(repository, "com", "acme", "acme-core", "1.0.0")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "acme-core-1.0.0.jar"), []byte("tampered"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "acme-core-1.0.0.jar.sha1"), []byte(testChecksum), 0644))
+
+	checksum, err := localChecksum(repository, mavenCoordinate{GroupID: "com.acme", ArtifactID: "acme-core", Version: "1.0.0", Type: "jar"})
+	assert.True(t, errors.Is(err, errChecksumMismatch))
+	assert.Equal(t, models.HashAlgoSHA256, checksum.Algorithm)
+}
+
+func TestCreateModuleReadsLocalChecksum(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	mod := createModule("org.example", "example-api", "2.1.0", gopom.Project{})
+	assert.Equal(t, "769ef8b26217a35eed37f35f953f8bf5fe555a5012c68fe3fce2ede2aa121067", mod.CheckSum.String())
+
+	mod = createModule("org.example", "example-model", "2.1.0", gopom.Project{})
+	assert.Nil(t, mod.CheckSum)
+
+	root := pomChecksum(filepath.Join("testdata", "self-reference"))
+	assert.Equal(t, models.HashAlgoSHA256, root.Algorithm)
+	assert.Len(t, root.String(), 64)
+}
+
+func TestListUsedModulesReadsLocalArtifactsInParallel(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+	defer func(list func(context.Context, string, mavenOptions) ([]string, error)) {
+		dependencyList = list
+	}(dependencyList)
+	dependencyList = func(context.Context, string, mavenOptions) ([]string, error) {
+		return nil, nil
+	}
+
+	m := New()
+	m.SetOptions(models.PluginOptions{MavenParallelism: 2})
+	modules, err := m.ListUsedModules(filepath.Join("testdata", "self-reference"))
+	assert.NoError(t, err)
+
+	byName := map[string]models.Module{}
+	for _, module := range modules {
+		byName[module.Name] = module
+	}
+	assert.Equal(t, "769ef8b26217a35eed37f35f953f8bf5fe555a5012c68fe3fce2ede2aa121067", byName["example-api"].CheckSum.String())
+	assert.Nil(t, byName["example-model"].CheckSum)
+	assert.Equal(t, "EPL-1.0", byName["junit"].LicenseDeclared)
+
+	// the root links the dependencies as they are once read
+	root := byName["example-service"]
+	assert.Equal(t, byName["example-api"].CheckSum, root.Modules["example-api"].CheckSum)
+	assert.Equal(t, "EPL-1.0", root.Modules["junit"].LicenseDeclared)
+	assert.Equal(t, models.ScopeRelationship(""), root.Modules["junit"].Relationship)
+}
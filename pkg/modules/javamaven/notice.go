@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// noticeFileRegex matches the NOTICE files jars carry their attribution notices in, e.g. META-INF/NOTICE.txt
+var noticeFileRegex = regexp.MustCompile(`(?i)^META-INF/NOTICE(\.txt|\.md)?$`)
+
+// maxNoticeSize bounds the NOTICE content read from a jar
+const maxNoticeSize = 1 << 20
+
+// readAttributionTexts returns the NOTICE files of the dependency jar installed in the local repository, if any
+func readAttributionTexts(repository string, coordinate mavenCoordinate) []string {
+	if repository == "" || coordinate.GroupID == "" || coordinate.Version == "" {
+		return nil
+	}
+
+	archive, err := zip.OpenReader(filepath.Join(repository, filepath.FromSlash(artifactPath(coordinate, nil))))
+	if err != nil {
+		return nil
+	}
+	defer archive.Close()
+
+	var files []*zip.File
+	for _, file := range archive.File {
+		if noticeFileRegex.MatchString(file.Name) && file.UncompressedSize64 <= maxNoticeSize {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	var texts []string
+	seen := map[string]bool{}
+	for _, file := range files {
+		reader, err := file.Open()
+		if err != nil {
+			continue
+		}
+		content, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.Replace(string(content), "\r\n", "\n", -1))
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		texts = append(texts, text)
+	}
+
+	return texts
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+// mavenOptions is what the pom reads and mvn runs of a generation share
+type mavenOptions struct {
+	// Profiles are the profile ids to activate, a !id deactivates the profile.
+	// The activation rules of the poms apply when empty
+	Profiles []string
+	// EffectivePom reads the pom computed by mvn help:effective-pom, falling back to pom.xml
+	EffectivePom bool
+	// IncludeScopes are the only dependency scopes listed when not empty
+	IncludeScopes []string
+	// ExcludeScopes are the dependency scopes never listed
+	ExcludeScopes []string
+	// Settings is the settings.xml passed to mvn -s, the user one when empty
+	Settings string
+	// Offline runs mvn -o, only the artifacts of the local repository are resolved
+	Offline bool
+	// IncludeBuildTools lists the build plugins, BUILD_TOOL_OF the module declaring them
+	IncludeBuildTools bool
+	// Executable is the Maven wrapper of the project, mvn of the PATH when empty
+	Executable string
+	// Parallelism is the number of dependencies looked up at once, as many as CPUs when 0
+	Parallelism int
+	// LockfileOnly reads the poms and the version lock file only, mvn is never run
+	LockfileOnly bool
+	// LicenseMatcher identifies the license files of the project, the SPDX license list when nil
+	LicenseMatcher helper.LicenseMatcher
+}
+
+// executable returns the binary the mvn runs invoke
+func (o mavenOptions) executable() string {
+	if o.Executable == "" {
+		return "mvn"
+	}
+	return o.Executable
+}
+
+// args returns the mvn arguments selecting the options, followed by the goals
+func (o mavenOptions) args(goals ...string) []string {
+	var args []string
+	if o.Offline {
+		args = append(args, "-o")
+	}
+	if o.Settings != "" {
+		args = append(args, "-s", o.Settings)
+	}
+	if len(o.Profiles) > 0 {
+		args = append(args, "-P", strings.Join(o.Profiles, ","))
+	}
+	return append(args, goals...)
+}
+
+// offlineError explains a failed offline mvn run, the artifacts it misses are not in the local repository
+func offlineError(err error, opts mavenOptions) error {
+	if !opts.Offline {
+		return err
+	}
+	return fmt.Errorf("%w, run mvn dependency:go-offline or generate without --offline first: %v", errMissingOffline, err)
+}
+
+// includesScope checks whether the dependencies of the scope are listed, no scope is the compile scope
+func (o mavenOptions) includesScope(scope string) bool {
+	scope = strings.TrimSpace(scope)
+	if scope == "" {
+		scope = "compile"
+	}
+
+	if len(o.IncludeScopes) > 0 && !containsScope(o.IncludeScopes, scope) {
+		return false
+	}
+	return !containsScope(o.ExcludeScopes, scope)
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if strings.EqualFold(strings.TrimSpace(s), scope) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMavenOptionsOffline(t *testing.T) {
+	opts := mavenOptions{Offline: true, Settings: "/etc/maven/settings.xml"}
+	assert.Equal(t, []string{"-o", "-s", "/etc/maven/settings.xml", "-B", "dependency:list"}, opts.args("-B", "dependency:list"))
+	assert.Equal(t, []string{"-B", "dependency:list"}, mavenOptions{}.args("-B", "dependency:list"))
+
+	failed := errors.New("exit status 1")
+	assert.Equal(t, failed, offlineError(failed, mavenOptions{}))
+
+	err := offlineError(failed, opts)
+	assert.True(t, errors.Is(err, errMissingOffline))
+	assert.Contains(t, err.Error(), "dependency:go-offline")
+	assert.Contains(t, err.Error(), "exit status 1")
+}
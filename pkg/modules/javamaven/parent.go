@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/vifraa/gopom"
+)
+
+// maxParentDepth bounds the parent chain, a pom naming itself as parent would never end
+const maxParentDepth = 32
+
+// inheritParents merges the parent poms into the project the way Maven builds the effective pom:
+// the groupId, version, licenses, properties and dependencies are inherited, and the dependencies without
+// a version take it from the dependencyManagement of the project, of its parents or of the BOMs they import.
+// A parent is read from its relativePath, ../pom.xml by default, then from the local repository,
+// a parent found in neither ends the chain
+func inheritParents(fpath string, project gopom.Project) gopom.Project {
+	project, managed := mergeParents(fpath, project, map[string]bool{})
+	project.Dependencies = applyManagedVersions(project.Dependencies, managed, project)
+	return project
+}
+
+// mergeParents returns the project merged with its parents and the managed dependencies, the closest
+// pom's first and the ones of the imported BOMs last. imported holds the BOMs already imported
+func mergeParents(fpath string, project gopom.Project, imported map[string]bool) (gopom.Project, []gopom.Dependency) {
+	if project.GroupID == "" {
+		project.GroupID = project.Parent.GroupID
+	}
+	if project.Version == "" {
+		project.Version = project.Parent.Version
+	}
+
+	managed := project.DependencyManagement.Dependencies
+	seen := map[string]bool{}
+	child, dir := project, fpath
+	for depth := 0; child.Parent.ArtifactID != "" && depth < maxParentDepth; depth++ {
+		key := child.Parent.GroupID + ":" + child.Parent.ArtifactID + ":" + child.Parent.Version
+		if seen[key] {
+			break
+		}
+		seen[key] = true
+
+		parent, parentDir, ok := readParentPom(dir, child.Parent)
+		if !ok {
+			log.Printf("parent pom %s not found, its properties and versions are not inherited", key)
+			break
+		}
+
+		mergeParent(&project, parent)
+		managed = append(managed, parent.DependencyManagement.Dependencies...)
+		child, dir = parent, parentDir
+	}
+
+	return project, importBoms(managed, project, imported)
+}
+
+// readParentPom reads the parent pom from its relative path or from the local repository, with the
+// directory its own parent is looked up from. A pom of the repository has no relative path to follow
+func readParentPom(dir string, parent gopom.Parent) (gopom.Project, string, bool) {
+	if dir != "" {
+		relativePath := parent.RelativePath
+		if relativePath == "" {
+			relativePath = "../pom.xml"
+		}
+		pomPath := filepath.Join(dir, filepath.FromSlash(relativePath))
+		if info, err := os.Stat(pomPath); err == nil && info.IsDir() {
+			pomPath = filepath.Join(pomPath, "pom.xml")
+		}
+		if project, err := readPom(pomPath); err == nil && isParent(project, parent) {
+			return project, filepath.Dir(pomPath), true
+		}
+	}
+
+	if localRepository == "" || parent.GroupID == "" || parent.Version == "" {
+		return gopom.Project{}, "", false
+	}
+
+	coordinate := mavenCoordinate{GroupID: parent.GroupID, ArtifactID: parent.ArtifactID, Version: parent.Version, Type: "pom"}
+	project, err := readPom(filepath.Join(localRepository, filepath.FromSlash(artifactPath(coordinate, nil))))
+	if err != nil {
+		return gopom.Project{}, "", false
+	}
+	return project, "", true
+}
+
+func readPom(pomPath string) (gopom.Project, error) {
+	var project gopom.Project
+
+	data, err := ioutil.ReadFile(pomPath)
+	if err != nil {
+		return project, err
+	}
+
+	err = xml.Unmarshal(data, &project)
+	return project, err
+}
+
+// isParent checks the pom found at the relative path is the declared parent, the groupId and version
+// of the pom may be inherited from its own parent
+func isParent(project gopom.Project, parent gopom.Parent) bool {
+	groupID, version := project.GroupID, project.Version
+	if groupID == "" {
+		groupID = project.Parent.GroupID
+	}
+	if version == "" {
+		version = project.Parent.Version
+	}
+
+	return project.ArtifactID == parent.ArtifactID && groupID == parent.GroupID &&
+		(parent.Version == "" || version == parent.Version)
+}
+
+// mergeParent adds the licenses, properties and dependencies of the parent the project does not declare itself
+func mergeParent(project *gopom.Project, parent gopom.Project) {
+	if project.GroupID == "" {
+		project.GroupID = parent.GroupID
+	}
+	if project.Version == "" {
+		project.Version = parent.Version
+	}
+	if len(project.Licenses) == 0 {
+		project.Licenses = parent.Licenses
+	}
+
+	if project.Properties.Entries == nil {
+		project.Properties.Entries = map[string]string{}
+	}
+	for name, value := range parent.Properties.Entries {
+		if _, ok := project.Properties.Entries[name]; !ok {
+			project.Properties.Entries[name] = value
+		}
+	}
+
+	for _, dependency := range parent.Dependencies {
+		if findDependency(project.Dependencies, dependency.GroupID, dependency.ArtifactID) < 0 {
+			project.Dependencies = append(project.Dependencies, dependency)
+		}
+	}
+}
+
+// findDependency returns the index of the groupId:artifactId dependency, or -1
+func findDependency(dependencies []gopom.Dependency, groupID string, artifactID string) int {
+	for i, dependency := range dependencies {
+		if dependency.GroupID == groupID && dependency.ArtifactID == artifactID {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyManagedVersions fills the version and scope of the dependencies declared without them from
+// the first managed dependency of the same groupId and artifactId, the closest pom's comes first
+func applyManagedVersions(dependencies []gopom.Dependency, managed []gopom.Dependency, project gopom.Project) []gopom.Dependency {
+	if len(managed) == 0 {
+		return dependencies
+	}
+
+	resolved := make([]gopom.Dependency, len(managed))
+	for i, dependency := range managed {
+		resolved[i] = dependency
+		resolved[i].GroupID = resolveProperty(dependency.GroupID, project)
+	}
+
+	applied := make([]gopom.Dependency, len(dependencies))
+	for i, dependency := range dependencies {
+		applied[i] = dependency
+		if dependency.Version != "" && dependency.Scope != "" {
+			continue
+		}
+
+		index := findDependency(resolved, resolveProperty(dependency.GroupID, project), dependency.ArtifactID)
+		if index < 0 {
+			continue
+		}
+		if applied[i].Version == "" {
+			applied[i].Version = resolved[index].Version
+		}
+		if applied[i].Scope == "" {
+			applied[i].Scope = resolved[index].Scope
+		}
+	}
+
+	return applied
+}
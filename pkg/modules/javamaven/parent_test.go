@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestReadAndLoadPomFileInheritsParents(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "parent", "child"), mavenOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "org.example", project.GroupID)
+	assert.Equal(t, "3.0.0", project.Version)
+	// the closest pom wins
+	assert.Equal(t, "1.7.30", project.Properties.Entries["slf4j.version"])
+	assert.Equal(t, "4.13.2", project.Properties.Entries["junit.version"])
+
+	versions := map[string]string{}
+	relationships := map[string]models.RelationshipType{}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		versions[mod.Name] = mod.Version
+		relationships[mod.Name] = models.ScopeRelationship(dep.Scope)
+	}
+	assert.Equal(t, map[string]string{
+		"example-api": "3.0.0",
+		"slf4j-api":   "1.7.30",
+		"junit":       "4.13.2",
+		"jsr305":      "3.0.2",
+	}, versions)
+	assert.Equal(t, models.ScopeRelationship("test"), relationships["junit"])
+	assert.Equal(t, models.ScopeRelationship("provided"), relationships["jsr305"])
+
+	root := convertProjectLevelPackageToModule(project, nil)
+	assert.Equal(t, "3.0.0", root.Version)
+}
+
+func TestReadAndLoadPomFileWithoutParentPom(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = ""
+
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "parent", "child"), mavenOptions{})
+	assert.NoError(t, err)
+
+	// the relative path still finds the parent, the repository one is missing
+	assert.Equal(t, "1.7.30", project.Properties.Entries["slf4j.version"])
+	assert.Equal(t, "", project.Properties.Entries["junit.version"])
+	assert.Equal(t, "", project.Dependencies[2].Version)
+}
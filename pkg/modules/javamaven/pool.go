@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"runtime"
+	"sync"
+)
+
+// forEachParallel runs work for every index below count on at most parallelism goroutines, as many as
+// CPUs when parallelism is not positive. work must only change what belongs to its index
+func forEachParallel(count int, parallelism int, work func(i int)) {
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	if parallelism > count {
+		parallelism = count
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				work(i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
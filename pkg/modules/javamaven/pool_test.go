@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachParallel(t *testing.T) {
+	for _, parallelism := range []int{0, 1, 3, 100} {
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		visited := make([]int, 20)
+
+		forEachParallel(len(visited), parallelism, func(i int) {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+			visited[i]++
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		})
+
+		for i, count := range visited {
+			assert.Equal(t, 1, count, "index %d with parallelism %d", i, parallelism)
+		}
+		if parallelism > 0 {
+			assert.LessOrEqual(t, maxRunning, parallelism)
+		}
+	}
+
+	forEachParallel(0, 4, func(int) { t.Fatal("no index to visit") })
+}
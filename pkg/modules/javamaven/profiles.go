@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+// applyProfiles merges the active profiles of the pom into the project
+func applyProfiles(fpath string, project gopom.Project, opts mavenOptions) gopom.Project {
+	for _, profile := range activeProfiles(fpath, project, opts.Profiles) {
+		project = mergeProfile(project, profile)
+	}
+	return project
+}
+
+// activeProfiles returns the profiles of the pom Maven would activate: the selected ones and the ones
+// whose activation rules match, the activeByDefault ones when no other is active. A profile
+// deactivated with !id never is
+func activeProfiles(fpath string, project gopom.Project, selected []string) []gopom.Profile {
+	activated := map[string]bool{}
+	deactivated := map[string]bool{}
+	for _, id := range selected {
+		id = strings.TrimSpace(id)
+		if strings.HasPrefix(id, "!") || strings.HasPrefix(id, "-") {
+			deactivated[id[1:]] = true
+		} else if id != "" {
+			activated[id] = true
+		}
+	}
+
+	var active, byDefault []gopom.Profile
+	for _, profile := range project.Profiles {
+		if deactivated[profile.ID] {
+			continue
+		}
+		if activated[profile.ID] || isActivated(fpath, profile.Activation) {
+			active = append(active, profile)
+		} else if profile.Activation.ActiveByDefault {
+			byDefault = append(byDefault, profile)
+		}
+	}
+
+	if len(active) == 0 {
+		return byDefault
+	}
+	return active
+}
+
+// isActivated checks the activation rules of the profile, all of the rules given must match.
+// There are no system properties, so a property rule only matches on its absence or on an
+// env. property, and a jdk rule never matches as the jdk is not known
+func isActivated(fpath string, activation gopom.Activation) bool {
+	rules := 0
+
+	if activation.JDK != "" {
+		return false
+	}
+
+	if name := activation.Property.Name; name != "" {
+		rules++
+		negated := strings.HasPrefix(name, "!")
+		name = strings.TrimPrefix(name, "!")
+
+		value, ok := "", false
+		if strings.HasPrefix(name, "env.") {
+			value, ok = os.LookupEnv(strings.TrimPrefix(name, "env."))
+		}
+		if expected := activation.Property.Value; expected != "" && ok {
+			if strings.HasPrefix(expected, "!") {
+				ok = value != strings.TrimPrefix(expected, "!")
+			} else {
+				ok = value == expected
+			}
+		}
+		if ok == negated {
+			return false
+		}
+	}
+
+	if family := strings.ToLower(activation.OS.Family); family != "" {
+		rules++
+		negated := strings.HasPrefix(family, "!")
+		if osFamily(strings.TrimPrefix(family, "!")) == negated {
+			return false
+		}
+	}
+
+	if exists := activation.File.Exists; exists != "" {
+		rules++
+		if !fileExists(fpath, exists) {
+			return false
+		}
+	}
+
+	if missing := activation.File.Missing; missing != "" {
+		rules++
+		if fileExists(fpath, missing) {
+			return false
+		}
+	}
+
+	return rules > 0
+}
+
+// osFamily checks the operating system belongs to the Maven os family
+func osFamily(family string) bool {
+	switch family {
+	case "windows", "dos":
+		return runtime.GOOS == "windows"
+	case "mac":
+		return runtime.GOOS == "darwin"
+	case "unix":
+		return runtime.GOOS != "windows"
+	}
+	return false
+}
+
+// fileExists checks the file of a file activation rule, relative to the pom directory
+func fileExists(fpath string, file string) bool {
+	file = strings.NewReplacer("${basedir}", fpath, "${project.basedir}", fpath).Replace(file)
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(fpath, file)
+	}
+	_, err := os.Stat(file)
+	return err == nil
+}
+
+// mergeProfile adds the modules, properties, dependencies and plugins of the profile to the project,
+// the profile ones win over the ones the project declares
+func mergeProfile(project gopom.Project, profile gopom.Profile) gopom.Project {
+	properties := map[string]string{}
+	for name, value := range project.Properties.Entries {
+		properties[name] = value
+	}
+	for name, value := range profile.Properties.Entries {
+		properties[name] = value
+	}
+	project.Properties.Entries = properties
+
+	project.Modules = append(append([]string{}, project.Modules...), profile.Modules...)
+	project.Dependencies = mergeDependencies(project.Dependencies, profile.Dependencies)
+	project.DependencyManagement.Dependencies = mergeDependencies(project.DependencyManagement.Dependencies, profile.DependencyManagement.Dependencies)
+	project.Build.Plugins = append(append([]gopom.Plugin{}, project.Build.Plugins...), profile.Build.Plugins...)
+	project.Build.PluginManagement.Plugins = append(append([]gopom.Plugin{}, project.Build.PluginManagement.Plugins...), profile.Build.PluginManagement.Plugins...)
+
+	return project
+}
+
+// mergeDependencies returns the dependencies with the overrides replacing the ones of the same
+// groupId and artifactId, the others are appended
+func mergeDependencies(dependencies []gopom.Dependency, overrides []gopom.Dependency) []gopom.Dependency {
+	merged := append([]gopom.Dependency{}, dependencies...)
+	for _, dependency := range overrides {
+		if index := findDependency(merged, dependency.GroupID, dependency.ArtifactID); index >= 0 {
+			merged[index] = dependency
+		} else {
+			merged = append(merged, dependency)
+		}
+	}
+	return merged
+}
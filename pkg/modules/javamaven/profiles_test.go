@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAndLoadPomFileAppliesProfiles(t *testing.T) {
+	path := filepath.Join("testdata", "profiles")
+
+	tests := []struct {
+		name     string
+		profiles []string
+		expected map[string]string
+	}{
+		{
+			name: "activation rules",
+			expected: map[string]string{
+				"guava":      "30.1-jre",
+				"commons-io": "2.8.0",
+			},
+		},
+		{
+			name:     "selected profile",
+			profiles: []string{"release"},
+			expected: map[string]string{
+				"guava":           "31.0-jre",
+				"commons-io":      "2.8.0",
+				"example-signing": "1.0.0",
+			},
+		},
+		{
+			name:     "deactivated profile",
+			profiles: []string{"!without-marker"},
+			expected: map[string]string{
+				"guava":        "30.1-jre",
+				"slf4j-simple": "1.7.30",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project, err := readAndLoadPomFile(context.Background(), path, mavenOptions{Profiles: test.profiles})
+			assert.NoError(t, err)
+
+			versions := map[string]string{}
+			for _, dep := range project.Dependencies {
+				mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+				versions[mod.Name] = mod.Version
+			}
+			assert.Equal(t, test.expected, versions)
+		})
+	}
+}
+
+func TestMavenOptionsArgs(t *testing.T) {
+	assert.Equal(t, []string{"-o", "dependency:list"}, mavenOptions{}.args("-o", "dependency:list"))
+	assert.Equal(t, []string{"-P", "release,!without-marker", "dependency:tree"},
+		mavenOptions{Profiles: []string{"release", "!without-marker"}}.args("dependency:tree"))
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// reactorModule is a <module> of a multi-module build, index is the one of its package
+type reactorModule struct {
+	project gopom.Project
+	index   int
+}
+
+// appendReactorModules adds a package for every <module> of the project, recursively, contained by the
+// package of the pom aggregating it. The dependencies of the modules are linked once all of them are
+// listed, so a module depending on a sibling is linked to it, and a dependency already listed, e.g. by
+// the root pom, is linked rather than listed twice
+func appendReactorModules(ctx context.Context, modules []models.Module, rootIndex int, fpath string, project gopom.Project, opts mavenOptions) []models.Module {
+	reactor, modules := collectReactorModules(ctx, modules, rootIndex, fpath, project, opts, map[string]bool{})
+	dependencies := len(modules)
+	for _, module := range reactor {
+		modules = appendReactorDependencies(modules, module, opts)
+	}
+	readLocalArtifacts(modules[dependencies:], opts.Parallelism)
+	return modules
+}
+
+// collectReactorModules reads the poms of the modules of the project, a module replaces the package
+// listed with the same name, e.g. from the dependencyManagement of the root pom
+func collectReactorModules(ctx context.Context, modules []models.Module, parentIndex int, fpath string, project gopom.Project, opts mavenOptions, seen map[string]bool) ([]reactorModule, []models.Module) {
+	var reactor []reactorModule
+	for _, name := range project.Modules {
+		dir := filepath.Join(fpath, filepath.FromSlash(strings.TrimSpace(name)))
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		subProject, err := readAndLoadPomFile(ctx, dir, opts)
+		if err != nil {
+			// continue reading other module pom.xml file
+			log.Printf("unable to read the pom of module %s: %v", name, err)
+			continue
+		}
+
+		mod := convertProjectLevelPackageToModule(subProject, opts.LicenseMatcher)
+		mod.Root = false
+		mod.CheckSum = pomChecksum(dir)
+
+		index := moduleIndex(modules, mod.Name)
+		if index < 0 {
+			index = len(modules)
+			modules = append(modules, mod)
+		} else {
+			modules[index] = mod
+		}
+		helper.LinkModule(modules, parentIndex, index, models.RelationshipContains)
+
+		reactor = append(reactor, reactorModule{project: subProject, index: index})
+
+		var nested []reactorModule
+		nested, modules = collectReactorModules(ctx, modules, index, dir, subProject, opts, seen)
+		reactor = append(reactor, nested...)
+	}
+
+	return reactor, modules
+}
+
+// appendReactorDependencies links the module to its dependencies of the listed scopes and, with the build
+// tools, its plugins. The ones not listed yet are added, their local artifacts are read once all are
+func appendReactorDependencies(modules []models.Module, module reactorModule, opts mavenOptions) []models.Module {
+	project := module.project
+
+	for _, dep := range project.Dependencies {
+		if !opts.includesScope(dep.Scope) {
+			continue
+		}
+		name := strings.Replace(strings.TrimSpace(dep.ArtifactID), " ", "-", -1)
+		index := moduleIndex(modules, name)
+		if index < 0 {
+			index = len(modules)
+			coordinate := dependencyCoordinate(dep)
+			coordinate.ArtifactID = name
+			modules = append(modules, newModule(coordinate, project))
+		}
+		helper.LinkModule(modules, module.index, index, models.ScopeRelationship(dep.Scope))
+	}
+
+	if !opts.IncludeBuildTools {
+		return modules
+	}
+	for _, plugin := range project.Build.Plugins {
+		name := strings.Replace(strings.TrimSpace(plugin.ArtifactID), " ", "-", -1)
+		index := moduleIndex(modules, name)
+		if index < 0 {
+			index = len(modules)
+			coordinate := pluginCoordinate(plugin)
+			coordinate.ArtifactID = name
+			modules = append(modules, newModule(coordinate, project))
+		}
+		helper.LinkModule(modules, module.index, index, models.RelationshipBuildToolOf)
+	}
+
+	return modules
+}
+
+// moduleIndex returns the index of the module with the name, or -1
+func moduleIndex(modules []models.Module, name string) int {
+	for i, module := range modules {
+		if module.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListUsedModulesAggregatesReactorModules(t *testing.T) {
+	defer func(list func(context.Context, string, mavenOptions) ([]string, error)) {
+		dependencyList = list
+	}(dependencyList)
+	dependencyList = func(context.Context, string, mavenOptions) ([]string, error) {
+		return nil, nil
+	}
+
+	modules, err := New().ListUsedModules(filepath.Join("testdata", "multi-module"))
+	assert.NoError(t, err)
+
+	byName := map[string]models.Module{}
+	for _, module := range modules {
+		_, duplicate := byName[module.Name]
+		assert.False(t, duplicate, "%s is listed twice", module.Name)
+		byName[module.Name] = module
+	}
+
+	relationships := func(name string) map[string]models.RelationshipType {
+		linked := map[string]models.RelationshipType{}
+		for depName, dep := range byName[name].Modules {
+			linked[depName] = dep.Relationship
+		}
+		return linked
+	}
+
+	assert.True(t, byName["example-reactor"].Root)
+	assert.Equal(t, models.RelationshipContains, relationships("example-reactor")["example-api"])
+	assert.Equal(t, models.RelationshipContains, relationships("example-reactor")["example-service"])
+
+	assert.Equal(t, "1.2.0", byName["example-api"].Version)
+	assert.False(t, byName["example-api"].Root)
+	assert.Equal(t, map[string]models.RelationshipType{
+		"slf4j-api": models.RelationshipDependsOn,
+	}, relationships("example-api"))
+
+	assert.Equal(t, map[string]models.RelationshipType{
+		"example-api":       models.RelationshipDependsOn,
+		"commons-lang3":     models.RelationshipDependsOn,
+		"junit":             models.RelationshipDevDependencyOf,
+		"example-extension": models.RelationshipContains,
+	}, relationships("example-service"))
+
+	// the dependencies of the parent pom are inherited
+	assert.Equal(t, map[string]models.RelationshipType{
+		"example-service": models.RelationshipDependsOn,
+		"commons-lang3":   models.RelationshipDependsOn,
+		"example-api":     models.RelationshipDependsOn,
+		"junit":           models.RelationshipDevDependencyOf,
+	}, relationships("example-extension"))
+}
+
+func TestConvertPOMReaderToModulesBuildTools(t *testing.T) {
+	defer func(list func(context.Context, string, mavenOptions) ([]string, error)) {
+		dependencyList = list
+	}(dependencyList)
+	dependencyList = func(context.Context, string, mavenOptions) ([]string, error) {
+		return nil, nil
+	}
+
+	relationships := func(opts mavenOptions) map[string]models.RelationshipType {
+		modules, err := convertPOMReaderToModules(context.Background(), filepath.Join("testdata", "build-plugins"), true, opts)
+		assert.NoError(t, err)
+
+		found := map[string]models.RelationshipType{}
+		for _, module := range modules {
+			for name, sub := range module.Modules {
+				found[module.Name+">"+name] = sub.Relationship
+			}
+		}
+		return found
+	}
+
+	assert.Equal(t, map[string]models.RelationshipType{
+		"build-plugins>slf4j-api": models.RelationshipDependsOn,
+		"build-plugins>app":       models.RelationshipContains,
+		"app>slf4j-api":           models.RelationshipDependsOn,
+	}, relationships(mavenOptions{}))
+
+	assert.Equal(t, map[string]models.RelationshipType{
+		"build-plugins>slf4j-api":             models.RelationshipDependsOn,
+		"build-plugins>app":                   models.RelationshipContains,
+		"build-plugins>maven-compiler-plugin": models.RelationshipBuildToolOf,
+		"build-plugins>maven-surefire-plugin": models.RelationshipBuildToolOf,
+		"app>slf4j-api":                       models.RelationshipDependsOn,
+		"app>maven-jar-plugin":                models.RelationshipBuildToolOf,
+	}, relationships(mavenOptions{IncludeBuildTools: true}))
+}
+
+func TestConvertPOMReaderToModulesManagedScopes(t *testing.T) {
+	defer func(list func(context.Context, string, mavenOptions) ([]string, error)) {
+		dependencyList = list
+	}(dependencyList)
+	dependencyList = func(context.Context, string, mavenOptions) ([]string, error) {
+		return nil, nil
+	}
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "missing")
+
+	names := func(opts mavenOptions) []string {
+		modules, err := convertPOMReaderToModules(context.Background(), filepath.Join("testdata", "managed-scopes"), true, opts)
+		assert.NoError(t, err)
+
+		var found []string
+		for _, module := range modules[1:] {
+			found = append(found, module.Name)
+		}
+		return found
+	}
+
+	// the BOM that could not be read is never listed
+	assert.Equal(t, []string{"slf4j-api", "junit"}, names(mavenOptions{}))
+	assert.Equal(t, []string{"slf4j-api"}, names(mavenOptions{ExcludeScopes: []string{"test"}}))
+	assert.Equal(t, []string{"junit"}, names(mavenOptions{IncludeScopes: []string{"test"}}))
+}
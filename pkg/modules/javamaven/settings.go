@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// mavenSettings is the subset of settings.xml used to reach remote repositories
+type mavenSettings struct {
+	Servers []mavenServer `xml:"servers>server"`
+	Mirrors []mavenMirror `xml:"mirrors>mirror"`
+	Proxies []mavenProxy  `xml:"proxies>proxy"`
+}
+
+// mavenServer holds the credentials of the repository with the same id
+type mavenServer struct {
+	ID       string `xml:"id"`
+	Username string `xml:"username"`
+	Password string `xml:"password"`
+}
+
+// mavenMirror serves the repositories matched by MirrorOf, e.g. central, * or external:*,!internal
+type mavenMirror struct {
+	ID       string `xml:"id"`
+	URL      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
+}
+
+// mavenProxy is the proxy the remote repositories are reached through
+type mavenProxy struct {
+	ID            string `xml:"id"`
+	Active        string `xml:"active"`
+	Protocol      string `xml:"protocol"`
+	Host          string `xml:"host"`
+	Port          int    `xml:"port"`
+	Username      string `xml:"username"`
+	Password      string `xml:"password"`
+	NonProxyHosts string `xml:"nonProxyHosts"`
+}
+
+var envReference = regexp.MustCompile(`\$\{env\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// defaultSettingsPath returns the user settings.xml location
+func defaultSettingsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "settings.xml")
+}
+
+// defaultLocalRepository returns the user local repository location
+func defaultLocalRepository() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// readSettings loads a settings.xml file, a missing file gives empty settings
+func readSettings(path string) (mavenSettings, error) {
+	var settings mavenSettings
+	if path == "" {
+		return settings, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		return settings, err
+	}
+
+	for i := range settings.Servers {
+		settings.Servers[i].Username = expandEnv(settings.Servers[i].Username)
+		settings.Servers[i].Password = expandEnv(settings.Servers[i].Password)
+	}
+	for i := range settings.Mirrors {
+		settings.Mirrors[i].URL = expandEnv(settings.Mirrors[i].URL)
+	}
+	for i := range settings.Proxies {
+		settings.Proxies[i].Host = expandEnv(settings.Proxies[i].Host)
+		settings.Proxies[i].Username = expandEnv(settings.Proxies[i].Username)
+		settings.Proxies[i].Password = expandEnv(settings.Proxies[i].Password)
+	}
+
+	return settings, nil
+}
+
+// server returns the credentials declared for the repository id
+func (s mavenSettings) server(id string) (mavenServer, bool) {
+	for _, server := range s.Servers {
+		if server.ID == id {
+			return server, true
+		}
+	}
+	return mavenServer{}, false
+}
+
+// expandEnv resolves the ${env.NAME} references maven supports in settings.xml
+func expandEnv(value string) string {
+	return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		return os.Getenv(envReference.FindStringSubmatch(ref)[1])
+	})
+}
+
+// mirror returns the mirror serving the repository, the first one matching it as Maven does
+func (s mavenSettings) mirror(id string, url string) (mavenMirror, bool) {
+	for _, mirror := range s.Mirrors {
+		if mirror.URL != "" && mirrorOf(mirror.MirrorOf, id, url) {
+			return mirror, true
+		}
+	}
+	return mavenMirror{}, false
+}
+
+// mirrorOf matches a repository against a mirrorOf pattern: ids separated by commas, * for every
+// repository, external:* for the ones not on localhost or the file system, and !id to exclude one
+func mirrorOf(pattern string, id string, url string) bool {
+	matched := false
+	for _, part := range strings.Split(pattern, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, "!"):
+			if part[1:] == id {
+				return false
+			}
+		case part == "*" || part == id:
+			matched = true
+		case part == "external:*":
+			matched = matched || isExternal(url)
+		}
+	}
+	return matched
+}
+
+// isExternal checks the repository is neither a file one nor on localhost
+func isExternal(repositoryURL string) bool {
+	parsed, err := url.Parse(repositoryURL)
+	if err != nil || parsed.Scheme == "file" {
+		return false
+	}
+	host := parsed.Hostname()
+	return host != "localhost" && host != "127.0.0.1"
+}
+
+// proxy returns the URL of the first active proxy for the protocol, with its credentials.
+// nil when no proxy is declared
+func (s mavenSettings) proxy(protocol string) (*url.URL, []string) {
+	for _, proxy := range s.Proxies {
+		if strings.EqualFold(strings.TrimSpace(proxy.Active), "false") || proxy.Host == "" {
+			continue
+		}
+		scheme := strings.ToLower(strings.TrimSpace(proxy.Protocol))
+		if scheme == "" {
+			scheme = "http"
+		}
+		if scheme != protocol {
+			continue
+		}
+
+		proxyURL := &url.URL{Scheme: "http", Host: proxy.Host}
+		if proxy.Port > 0 {
+			proxyURL.Host = fmt.Sprintf("%s:%d", proxy.Host, proxy.Port)
+		}
+		if proxy.Username != "" {
+			proxyURL.User = url.UserPassword(proxy.Username, proxy.Password)
+		}
+		return proxyURL, strings.Split(proxy.NonProxyHosts, "|")
+	}
+	return nil, nil
+}
+
+// proxyFunc returns the proxy of the requests, the hosts matching nonProxyHosts, e.g. *.example.com,
+// are reached directly
+func (s mavenSettings) proxyFunc() func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, nonProxyHosts := s.proxy(req.URL.Scheme)
+		if proxyURL == nil {
+			return nil, nil
+		}
+		for _, pattern := range nonProxyHosts {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(req.URL.Hostname())); matched {
+				return nil, nil
+			}
+		}
+		return proxyURL, nil
+	}
+}
+
+// applyMirrorLocations points the download location of the dependencies to their artifact on the
+// mirror of Maven Central, if settings.xml declares one
+func applyMirrorLocations(modules []models.Module, settings mavenSettings) {
+	mirror, ok := settings.mirror("central", CentralRepositoryURL)
+	if !ok {
+		return
+	}
+	mirrorURL, err := url.Parse(strings.TrimSuffix(mirror.URL, "/"))
+	if err != nil {
+		return
+	}
+	// the credentials come from the servers, never from the document
+	mirrorURL.User = nil
+
+	for i := range modules {
+		mod := &modules[i]
+		coordinate, ok := moduleCoordinate(*mod)
+		if mod.Root || !ok || mod.PackageDownloadLocation != RepositoryUrl+coordinate.GroupID+"/"+mod.Name+"/"+mod.Version {
+			continue
+		}
+		mod.PackageDownloadLocation = mirrorURL.String() + "/" + artifactPath(coordinate, nil)
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestMirrorOf(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		id       string
+		url      string
+		expected bool
+	}{
+		{"*", "central", CentralRepositoryURL, true},
+		{"central", "central", CentralRepositoryURL, true},
+		{"central", "internal", "https://repo.example.com", false},
+		{"internal,central", "internal", "https://repo.example.com", true},
+		{"*,!internal", "internal", "https://repo.example.com", false},
+		{"external:*", "local", "http://localhost:8081/repository", false},
+		{"external:*", "file", "file:///srv/repository", false},
+		{"external:*", "central", CentralRepositoryURL, true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, mirrorOf(test.pattern, test.id, test.url), "%s %s", test.pattern, test.id)
+	}
+}
+
+func TestRepositoriesUseMirrors(t *testing.T) {
+	os.Setenv("SPDX_TEST_MIRROR_PASSWORD", "m1rror")
+	defer os.Unsetenv("SPDX_TEST_MIRROR_PASSWORD")
+
+	settings, err := readSettings(filepath.Join("testdata", "settings-mirrors.xml"))
+	assert.NoError(t, err)
+
+	project := gopom.Project{Repositories: []gopom.Repository{
+		{ID: "internal", URL: "https://repo.example.com/internal"},
+		{ID: "jboss", URL: "https://repository.jboss.org/nexus/content/groups/public"},
+	}}
+
+	assert.Equal(t, []remoteRepository{
+		{ID: "internal", URL: "https://repo.example.com/internal"},
+		{ID: "corporate", URL: "https://nexus.example.com/repository/maven-public", username: "reader", password: "m1rror"},
+	}, repositories(project, settings))
+}
+
+func TestSettingsProxy(t *testing.T) {
+	settings, err := readSettings(filepath.Join("testdata", "settings-mirrors.xml"))
+	assert.NoError(t, err)
+
+	proxy := func(rawURL string) *url.URL {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		assert.NoError(t, err)
+		proxyURL, err := settings.proxyFunc()(req)
+		assert.NoError(t, err)
+		return proxyURL
+	}
+
+	proxyURL := proxy("https://repo.maven.apache.org/maven2/junit/junit/4.13.2/junit-4.13.2.jar.sha1")
+	if assert.NotNil(t, proxyURL) {
+		assert.Equal(t, "proxy.example.com:3128", proxyURL.Host)
+		assert.Equal(t, "proxyuser", proxyURL.User.Username())
+	}
+	assert.Nil(t, proxy("https://nexus.example.com/repository/maven-public/junit/junit/4.13.2/junit-4.13.2.jar.sha1"))
+	assert.Nil(t, proxy("http://repo.maven.apache.org/maven2/junit/junit/4.13.2/junit-4.13.2.jar.sha1"))
+}
+
+func TestApplyMirrorLocations(t *testing.T) {
+	settings, err := readSettings(filepath.Join("testdata", "settings-mirrors.xml"))
+	assert.NoError(t, err)
+
+	modules := []models.Module{
+		{Name: "service", Root: true, PackageDownloadLocation: "https://example.org/service"},
+		{Name: "junit", Version: "4.13.2", Path: "junit:junit", PackageDownloadLocation: RepositoryUrl + "junit/junit/4.13.2"},
+		{Name: "acme", Version: "1.0.0", Path: "com.acme:acme", PackageDownloadLocation: "https://downloads.acme.com/acme-1.0.0.jar"},
+	}
+	applyMirrorLocations(modules, settings)
+
+	assert.Equal(t, "https://example.org/service", modules[0].PackageDownloadLocation)
+	assert.Equal(t, "https://nexus.example.com/repository/maven-public/junit/junit/4.13.2/junit-4.13.2.jar", modules[1].PackageDownloadLocation)
+	assert.Equal(t, "https://downloads.acme.com/acme-1.0.0.jar", modules[2].PackageDownloadLocation)
+
+	// without mirror the locations are kept
+	modules[1].PackageDownloadLocation = RepositoryUrl + "junit/junit/4.13.2"
+	applyMirrorLocations(modules, mavenSettings{})
+	assert.Equal(t, RepositoryUrl+"junit/junit/4.13.2", modules[1].PackageDownloadLocation)
+}
+
+func TestMavenOptionsArgsSettings(t *testing.T) {
+	assert.Equal(t, []string{"-s", "/etc/maven/settings.xml", "-P", "release", "dependency:list"},
+		mavenOptions{Settings: "/etc/maven/settings.xml", Profiles: []string{"release"}}.args("dependency:list"))
+}
+
+func TestSettingsNotFound(t *testing.T) {
+	m := New()
+	m.SetOptions(models.PluginOptions{MavenSettings: filepath.Join("testdata", "missing-settings.xml")})
+
+	_, err := m.settings()
+	assert.True(t, errors.Is(err, errSettingsNotFound))
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+var (
+	// a dot graph of dependency:tree -DoutputType=dot, e.g. digraph "org.example:app:jar:1.0.0" {
+	treeGraphRegex = regexp.MustCompile(`^\s*digraph\s+"([^"]+)"\s*\{`)
+	// a dot edge, e.g. "org.example:app:jar:1.0.0" -> "junit:junit:jar:4.13.2:test" ;
+	treeEdgeRegex = regexp.MustCompile(`^\s*"([^"]+)"\s*->\s*"([^"]+)"\s*;?\s*$`)
+	// a tgf node, e.g. 1414147750 junit:junit:jar:4.13.2:test
+	treeTGFNodeRegex = regexp.MustCompile(`^\s*(\d+)\s+(\S.*)$`)
+	// a tgf edge following the # separator, e.g. 1414147750 775931202 test
+	treeTGFEdgeRegex = regexp.MustCompile(`^\s*(\d+)\s+(\d+)(\s+\S+)?\s*$`)
+	// a text tree node, e.g. |  \- org.hamcrest:hamcrest-core:jar:1.3:test (*)
+	treeNodeRegex = regexp.MustCompile(`^((?:[| ]  )*)[+\\]- (.+)$`)
+	// a trailing node annotation, e.g. (*), (version managed from 1.0) or (omitted for duplicate)
+	treeAnnotationRegex = regexp.MustCompile(`\s*\([^()]*\)\s*$`)
+	// a node the verbose tree omits, e.g. (org.slf4j:slf4j-api:jar:1.7.25:compile - omitted for conflict with 1.7.30)
+	treeOmittedRegex = regexp.MustCompile(`^\((\S+) - ([^()]*)\)$`)
+	// the version nearest wins chose over the one of the node
+	treeConflictRegex = regexp.MustCompile(`omitted for conflict with ([^\s;)]+)`)
+)
+
+// dependencyTree is what dependency:tree resolved, keyed by artifactId
+type dependencyTree struct {
+	// Dependencies are the direct dependencies of every artifact
+	Dependencies map[string][]string
+	// Versions are the versions on the classpath, the nearest one wins a conflict
+	Versions map[string]string
+}
+
+// treeNode is an artifact of the dependency tree
+type treeNode struct {
+	ArtifactID string
+	Version    string
+	// Reference is set for the nodes whose dependencies are listed where the artifact first appears,
+	// e.g. the (*) and (omitted for duplicate) nodes
+	Reference bool
+	// Conflict is the version on the classpath of a node omitted for conflict, Version is never resolved
+	Conflict string
+}
+
+// parseTreeNode parses a groupId:artifactId:type[:classifier]:version[:scope] node, its annotations are stripped
+func parseTreeNode(label string) (treeNode, bool) {
+	label = strings.TrimSpace(label)
+	reference, conflict := false, ""
+	// the verbose tree wraps the omitted nodes with their annotation
+	if match := treeOmittedRegex.FindStringSubmatch(label); match != nil {
+		label = match[1]
+		reference = strings.Contains(match[2], "omitted for")
+		if conflictMatch := treeConflictRegex.FindStringSubmatch(match[2]); conflictMatch != nil {
+			conflict = conflictMatch[1]
+		}
+	}
+	for {
+		annotation := treeAnnotationRegex.FindString(label)
+		if annotation == "" {
+			break
+		}
+		annotation = strings.TrimSpace(annotation)
+		if annotation == "(*)" || strings.HasPrefix(annotation, "(omitted for") {
+			reference = true
+		}
+		if conflictMatch := treeConflictRegex.FindStringSubmatch(annotation); conflictMatch != nil {
+			conflict = conflictMatch[1]
+		}
+		label = strings.TrimSpace(label[:len(label)-len(treeAnnotationRegex.FindString(label))])
+	}
+
+	// the root of the tree has no scope
+	parts := strings.Split(label, ":")
+	if len(parts) == 4 {
+		for _, part := range parts {
+			if !coordinatePart.MatchString(part) {
+				return treeNode{}, false
+			}
+		}
+		return treeNode{ArtifactID: parts[1], Version: parts[3], Reference: reference, Conflict: conflict}, true
+	}
+
+	coordinate, ok := parseCoordinate(label)
+	if !ok {
+		return treeNode{}, false
+	}
+	return treeNode{ArtifactID: coordinate.ArtifactID, Version: coordinate.Version, Reference: reference, Conflict: conflict}, true
+}
+
+// parseDependencyTree lists the direct dependencies and the resolved version of every artifact of the
+// dependency:tree output. The dot, tgf and text outputs are read, several trees may be appended, the
+// first one resolving an artifact wins. A reference node only adds its edge, its dependencies are the
+// ones of the node expanded elsewhere, and a node omitted for conflict links the version that won it
+func parseDependencyTree(lines []string) dependencyTree {
+	tree := dependencyTree{Dependencies: map[string][]string{}, Versions: map[string]string{}}
+	resolve := func(node treeNode) {
+		version := node.Version
+		if node.Conflict != "" {
+			version = node.Conflict
+		} else if node.Reference {
+			return
+		}
+		if _, ok := tree.Versions[node.ArtifactID]; !ok && version != "" {
+			tree.Versions[node.ArtifactID] = version
+		}
+	}
+	seen := map[string]map[string]bool{}
+	link := func(parent, child treeNode) {
+		resolve(parent)
+		resolve(child)
+		if seen[parent.ArtifactID] == nil {
+			seen[parent.ArtifactID] = map[string]bool{}
+		}
+		if seen[parent.ArtifactID][child.ArtifactID] {
+			return
+		}
+		seen[parent.ArtifactID][child.ArtifactID] = true
+		tree.Dependencies[parent.ArtifactID] = append(tree.Dependencies[parent.ArtifactID], child.ArtifactID)
+	}
+
+	// the ancestors of the current text tree node, the root first
+	var ancestors []treeNode
+	// the nodes of the current tgf tree by id, its edges follow the # separator
+	tgfNodes := map[string]treeNode{}
+	tgfEdges := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		// the console output prefixes the tree with the log level, e.g. [INFO] +- junit:junit:jar:4.13.2:test
+		if prefix := logLevelPrefix.FindString(line); prefix != "" {
+			line = strings.TrimPrefix(line[len(prefix):], " ")
+		}
+
+		if match := treeEdgeRegex.FindStringSubmatch(line); match != nil {
+			parent, ok := parseTreeNode(match[1])
+			if !ok || parent.Reference {
+				continue
+			}
+			if child, ok := parseTreeNode(match[2]); ok {
+				link(parent, child)
+			}
+			continue
+		}
+		if treeGraphRegex.MatchString(line) {
+			continue
+		}
+
+		if strings.TrimSpace(line) == "#" {
+			tgfEdges = true
+			continue
+		}
+		if match := treeTGFEdgeRegex.FindStringSubmatch(line); match != nil && tgfEdges {
+			parent, parentOK := tgfNodes[match[1]]
+			child, childOK := tgfNodes[match[2]]
+			if parentOK && childOK && !parent.Reference {
+				link(parent, child)
+			}
+			continue
+		}
+		if match := treeTGFNodeRegex.FindStringSubmatch(line); match != nil {
+			if node, ok := parseTreeNode(match[2]); ok {
+				// a node after the edges starts the next appended tree
+				if tgfEdges {
+					tgfNodes, tgfEdges = map[string]treeNode{}, false
+				}
+				tgfNodes[match[1]] = node
+				continue
+			}
+		}
+
+		if match := treeNodeRegex.FindStringSubmatch(line); match != nil {
+			depth := len(match[1])/3 + 1
+			node, ok := parseTreeNode(match[2])
+			if !ok || depth > len(ancestors) {
+				continue
+			}
+			ancestors = ancestors[:depth]
+			link(ancestors[depth-1], node)
+			// the dependencies of a reference are never listed under it
+			if !node.Reference {
+				ancestors = append(ancestors, node)
+			}
+			continue
+		}
+
+		if root, ok := parseTreeNode(line); ok {
+			resolve(root)
+			ancestors = []treeNode{root}
+		}
+	}
+
+	return tree
+}
+
+// applyResolvedVersions sets the version maven resolved on the dependencies declared with another one,
+// e.g. in a dependencyManagement the project does not use. Their local artifacts are read again
+func applyResolvedVersions(modules []models.Module, versions map[string]string) {
+	for i := range modules {
+		mod := &modules[i]
+		version, ok := versions[mod.Name]
+		if mod.Root || !ok || version == mod.Version {
+			continue
+		}
+		// Path holds groupId:artifactId
+		parts := strings.Split(mod.Path, ":")
+		if len(parts) != 2 {
+			continue
+		}
+
+		setModuleVersion(mod, parts[0], parts[1], version)
+		mod.CheckSum, mod.Annotations, mod.Unresolved = nil, nil, nil
+		mod.LicenseDeclared, mod.LicenseConcluded = "", ""
+		mod.PackageHomePage, mod.SourceInfo, mod.AttributionTexts = "", "", nil
+		readLocalArtifact(mod)
+	}
+}
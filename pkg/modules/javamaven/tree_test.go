@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// expectedDependencyTree is the tree of the testdata/dependency-tree.txt, .dot and .tgf outputs
+var expectedDependencyTree = map[string][]string{
+	"app":              {"spring-context", "spring-web", "jackson-databind", "junit"},
+	"spring-context":   {"spring-core"},
+	"spring-core":      {"spring-jcl"},
+	"spring-web":       {"spring-beans", "spring-core"},
+	"spring-beans":     {"spring-core"},
+	"jackson-databind": {"jackson-core"},
+	"junit":            {"hamcrest-core"},
+}
+
+func TestReadDependencyTree(t *testing.T) {
+	for _, name := range []string{"dependency-tree.txt", "dependency-tree.dot", "dependency-tree.tgf"} {
+		tree, err := readAndgetTransitiveDependencyList(filepath.Join("testdata", name))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDependencyTree, tree.Dependencies, name)
+		assert.Equal(t, "5.3.9", tree.Versions["spring-core"], name)
+		assert.Equal(t, "1.0.0", tree.Versions["app"], name)
+	}
+}
+
+func TestParseDependencyTreeConsoleOutput(t *testing.T) {
+	output := `[INFO] --- maven-dependency-plugin:3.1.2:tree (default-cli) @ app ---
+[INFO] com.example:app:jar:1.0.0
+[INFO] +- org.springframework:spring-web:jar:5.3.9:compile
+[INFO] |  \- org.springframework:spring-core:jar:5.3.9:compile (*)
+[INFO] \- junit:junit:jar:4.13.2:test
+[INFO]    \- org.hamcrest:hamcrest-core:jar:1.3:test (omitted for duplicate)
+[INFO]       \- org.hamcrest:hamcrest-parent:pom:1.3:test
+[INFO] ------------------------------------------------------------------------`
+
+	assert.Equal(t, map[string][]string{
+		"app":        {"spring-web", "junit"},
+		"spring-web": {"spring-core"},
+		"junit":      {"hamcrest-core"},
+	}, parseDependencyTree(strings.Split(output, "\n")).Dependencies)
+}
+
+func TestParseDependencyTreeAppendedTGF(t *testing.T) {
+	// the trees of a reactor build appended to the same file, the ids of a tree are only its own
+	output := `11 org.example:example-api:jar:1.0.0
+12 org.slf4j:slf4j-api:jar:1.7.30:compile
+#
+11 12 compile
+21 org.example:example-app:war:1.0.0
+11 org.example:example-api:jar:1.0.0:compile
+12 junit:junit:jar:4.13.2:test
+#
+21 11 compile
+21 12 test
+`
+
+	assert.Equal(t, map[string][]string{
+		"example-api": {"slf4j-api"},
+		"example-app": {"example-api", "junit"},
+	}, parseDependencyTree(strings.Split(output, "\n")).Dependencies)
+}
+
+func TestParseDependencyTreeConflicts(t *testing.T) {
+	// mvn dependency:tree -Dverbose, nearest wins: the direct commons-io and the first slf4j-api found
+	output := `com.example:app:jar:1.0.0
++- org.example:example-client:jar:2.0.0:compile
+|  +- org.slf4j:slf4j-api:jar:1.7.30:compile
+|  \- (commons-io:commons-io:jar:2.6:compile - omitted for conflict with 2.8.0)
++- org.example:example-legacy:jar:1.0.0:compile
+|  \- (org.slf4j:slf4j-api:jar:1.7.25:compile - omitted for conflict with 1.7.30)
+\- commons-io:commons-io:jar:2.8.0:compile`
+
+	tree := parseDependencyTree(strings.Split(output, "\n"))
+	assert.Equal(t, map[string][]string{
+		"app":            {"example-client", "example-legacy", "commons-io"},
+		"example-client": {"slf4j-api", "commons-io"},
+		"example-legacy": {"slf4j-api"},
+	}, tree.Dependencies)
+	assert.Equal(t, map[string]string{
+		"app":            "1.0.0",
+		"example-client": "2.0.0",
+		"example-legacy": "1.0.0",
+		"slf4j-api":      "1.7.30",
+		"commons-io":     "2.8.0",
+	}, tree.Versions)
+}
+
+func TestApplyResolvedVersions(t *testing.T) {
+	defer func(repository string) { localRepository = repository }(localRepository)
+	localRepository = filepath.Join("testdata", "repository")
+
+	modules := []models.Module{
+		{Name: "app", Version: "1.0.0", Path: "com.example:app", Root: true},
+		createModule("junit", "junit", "4.12", gopom.Project{}),
+		createModule("org.slf4j", "slf4j-api", "1.7.30", gopom.Project{}),
+	}
+	assert.Nil(t, modules[1].CheckSum)
+
+	applyResolvedVersions(modules, map[string]string{"app": "2.0.0", "junit": "4.13.2", "slf4j-api": "1.7.30"})
+
+	assert.Equal(t, "1.0.0", modules[0].Version)
+	assert.Equal(t, "4.13.2", modules[1].Version)
+	assert.Equal(t, "pkg:maven/junit/junit@4.13.2", modules[1].PackageURL)
+	assert.Equal(t, RepositoryUrl+"junit/junit/4.13.2", modules[1].PackageDownloadLocation)
+	// the artifacts of the resolved version are read
+	assert.NotNil(t, modules[1].CheckSum)
+	assert.Equal(t, "EPL-1.0", modules[1].LicenseDeclared)
+	assert.Equal(t, "1.7.30", modules[2].Version)
+}
+
+func TestParseTreeNode(t *testing.T) {
+	for label, expected := range map[string]treeNode{
+		"org.springframework:spring-core:jar:5.3.9:compile (*)":                            {ArtifactID: "spring-core", Version: "5.3.9", Reference: true},
+		"org.springframework:spring-beans:jar:5.3.9:compile (version managed from 5.3.8)":  {ArtifactID: "spring-beans", Version: "5.3.9"},
+		"org.slf4j:slf4j-api:jar:1.7.30:compile (optional) (*)":                            {ArtifactID: "slf4j-api", Version: "1.7.30", Reference: true},
+		"org.example:example-model:test-jar:tests:2.1.0:test":                              {ArtifactID: "example-model", Version: "2.1.0"},
+		"com.example:app:jar:1.0.0":                                                        {ArtifactID: "app", Version: "1.0.0"},
+		"(org.slf4j:slf4j-api:jar:1.7.25:compile - omitted for conflict with 1.7.30)":      {ArtifactID: "slf4j-api", Version: "1.7.25", Reference: true, Conflict: "1.7.30"},
+		"(commons-io:commons-io:jar:2.8.0:compile - omitted for duplicate)":                {ArtifactID: "commons-io", Version: "2.8.0", Reference: true},
+		"(junit:junit:jar:4.13.2:test - version managed from 4.12; omitted for duplicate)": {ArtifactID: "junit", Version: "4.13.2", Reference: true},
+	} {
+		node, ok := parseTreeNode(label)
+		assert.True(t, ok, label)
+		assert.Equal(t, expected, node, label)
+	}
+
+	_, ok := parseTreeNode("spring-core (*)")
+	assert.False(t, ok)
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/versionlock"
+)
+
+// applyVersionLock replaces the version of the modules pinned in the lock file, the version
+// declared in the pom may be a placeholder resolved outside of maven
+func applyVersionLock(modules []models.Module, versions versionlock.Versions) {
+	for i := range modules {
+		applyModuleVersionLock(&modules[i], versions)
+		for _, subModule := range modules[i].Modules {
+			applyModuleVersionLock(subModule, versions)
+		}
+	}
+}
+
+func applyModuleVersionLock(mod *models.Module, versions versionlock.Versions) {
+	// Path holds groupId:artifactId
+	parts := strings.Split(mod.Path, ":")
+	if len(parts) != 2 {
+		return
+	}
+	groupID, artifactID := parts[0], parts[1]
+
+	version, ok := versions.Lookup(groupID, artifactID)
+	if !ok || version == mod.Version {
+		return
+	}
+
+	setModuleVersion(mod, groupID, artifactID, version)
+}
+
+// setModuleVersion changes the version of the module, with the repository location and the purl
+// embedding it
+func setModuleVersion(mod *models.Module, groupID string, artifactID string, version string) {
+	if mod.PackageDownloadLocation == RepositoryUrl+groupID+"/"+mod.Name+"/"+mod.Version {
+		mod.PackageDownloadLocation = RepositoryUrl + groupID + "/" + mod.Name + "/" + version
+	}
+	if mod.PackageURL == mavenArtifactPackageURL(groupID, artifactID, mod.Version, mod.Packaging, mod.Classifier) {
+		mod.PackageURL = mavenArtifactPackageURL(groupID, artifactID, version, mod.Packaging, mod.Classifier)
+	}
+	mod.Version = version
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/versionlock"
+)
+
+func TestApplyVersionLock(t *testing.T) {
+	project, err := readAndLoadPomFile(context.Background(), filepath.Join("testdata", "version-lock"), mavenOptions{})
+	assert.NoError(t, err)
+
+	versions, err := versionlock.Load(filepath.Join("testdata", "version-lock", "versions.properties"))
+	assert.NoError(t, err)
+
+	root := convertProjectLevelPackageToModule(project, nil)
+	modules := []models.Module{root}
+	for _, dep := range project.Dependencies {
+		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		modules = append(modules, mod)
+		root.Modules[mod.Name] = &mod
+	}
+
+	applyVersionLock(modules, versions)
+
+	assert.Equal(t, "1.0.0", modules[0].Version)
+	assert.Equal(t, "31.0.1-jre", modules[1].Version)
+	assert.Equal(t, RepositoryUrl+"com.google.guava/guava/31.0.1-jre", modules[1].PackageDownloadLocation)
+	assert.Equal(t, "pkg:maven/com.google.guava/guava@31.0.1-jre", modules[1].PackageURL)
+	assert.Equal(t, "1.7.30", modules[2].Version)
+	assert.Equal(t, "4.13.2", modules[3].Version)
+	assert.Equal(t, "31.0.1-jre", root.Modules["guava"].Version)
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+// mavenWrapper returns the absolute path of the Maven wrapper the project pins its Maven version with,
+// mvnw.cmd on windows, or "" when the project has none and the mvn of the PATH runs
+func mavenWrapper(path string) string {
+	name := "mvnw"
+	if runtime.GOOS == "windows" {
+		name = "mvnw.cmd"
+	}
+
+	wrapper, err := filepath.Abs(filepath.Join(path, name))
+	if err != nil || !helper.Exists(wrapper) {
+		return ""
+	}
+	return wrapper
+}
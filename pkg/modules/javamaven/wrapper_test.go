@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMavenWrapper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spdx-mvnw")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Equal(t, "", mavenWrapper(dir))
+	m := New()
+	assert.NoError(t, m.SetRootModule(filepath.Join("testdata", "self-reference")))
+	assert.Equal(t, "mvn", m.GetExecutable())
+
+	name := "mvnw"
+	if runtime.GOOS == "windows" {
+		name = "mvnw.cmd"
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755))
+	assert.Equal(t, filepath.Join(dir, name), mavenWrapper(dir))
+
+	m.wrapper = mavenWrapper(dir)
+	assert.Equal(t, name, m.GetExecutable())
+	assert.Equal(t, filepath.Join(dir, name), m.mavenOptions().executable())
+	assert.NoError(t, m.HasModulesInstalled(dir))
+}
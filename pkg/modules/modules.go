@@ -3,22 +3,47 @@
 package modules
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/javagradle"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/apk"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/bazel"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/cargo"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/carthage"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/clojure"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/cocoapods"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/composer"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/conan"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/conda"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/cpan"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/dockerfile"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/dpkg"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/gem"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/gomod"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/haskell"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/hex"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/ivy"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/javamaven"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/npm"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/nuget"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pnpm"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pub"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/renv"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/rpm"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/sbt"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/swift"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/terraform"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/vcpkg"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/yarn"
 )
 
@@ -26,45 +51,169 @@ var (
 	errNoPluginAvailable   = errors.New("no plugin system available for current path")
 	errNoModulesInstalled  = errors.New("there are no components in the BOM. The project may not contain dependencies, please install modules")
 	errFailedToReadModules = errors.New("failed to read modules")
+	errGenerationAborted   = errors.New("generation aborted before completion")
+
+	errUnknownBuildTool        = errors.New("no build tool known for plugin")
+	errUnknownBuildToolVersion = errors.New("failed to detect build tool version")
 )
 
-var registeredPlugins []models.IPlugin
+// registeredPlugins are the constructors of the plugins, in the order they are detected. Every generation
+// creates its own instances, the options set on them are not shared
+var registeredPlugins []func() models.IPlugin
 
 func init() {
 	registeredPlugins = append(registeredPlugins,
-		cargo.New(),
-		composer.New(),
-		gomod.New(),
-		gem.New(),
-		npm.New(),
-		javagradle.New(),
-		javamaven.New(),
-		nuget.New(),
-		yarn.New(),
-		pip.New(),
-		swift.New(),
+		func() models.IPlugin { return cargo.New() },
+		func() models.IPlugin { return clojure.New() },
+		func() models.IPlugin { return composer.New() },
+		func() models.IPlugin { return gomod.New() },
+		func() models.IPlugin { return gem.New() },
+		func() models.IPlugin { return npm.New() },
+		func() models.IPlugin { return pnpm.New() },
+		func() models.IPlugin { return javagradle.New() },
+		func() models.IPlugin { return javamaven.New() },
+		func() models.IPlugin { return ivy.New() },
+		func() models.IPlugin { return nuget.New() },
+		func() models.IPlugin { return yarn.New() },
+		func() models.IPlugin { return pip.New() },
+		func() models.IPlugin { return conda.New() },
+		func() models.IPlugin { return sbt.New() },
+		func() models.IPlugin { return swift.New() },
+		func() models.IPlugin { return cocoapods.New() },
+		func() models.IPlugin { return carthage.New() },
+		func() models.IPlugin { return pub.New() },
+		func() models.IPlugin { return hex.New() },
+		func() models.IPlugin { return haskell.New() },
+		func() models.IPlugin { return cpan.New() },
+		func() models.IPlugin { return renv.New() },
+		func() models.IPlugin { return conan.New() },
+		func() models.IPlugin { return vcpkg.New() },
+		func() models.IPlugin { return bazel.New() },
+		func() models.IPlugin { return terraform.New() },
+		func() models.IPlugin { return dpkg.New() },
+		func() models.IPlugin { return rpm.New() },
+		func() models.IPlugin { return apk.New() },
+		func() models.IPlugin { return dockerfile.New() },
 	)
 }
 
+// pluginGroup is a plugin delegating to the first of its plugins valid for the path, e.g. pip
+type pluginGroup interface {
+	Plugins() []models.IPlugin
+}
+
+// Supported returns the metadata of the registered plugins, in the order they are detected
+func Supported() []models.PluginMetadata {
+	var supported []models.PluginMetadata
+	for _, newPlugin := range registeredPlugins {
+		plugin := newPlugin()
+		if group, ok := plugin.(pluginGroup); ok {
+			for _, member := range group.Plugins() {
+				supported = append(supported, member.GetMetadata())
+			}
+			continue
+		}
+		supported = append(supported, plugin.GetMetadata())
+	}
+
+	return supported
+}
+
 // Manager ...
 type Manager struct {
 	Config  Config
 	Plugin  models.IPlugin
 	modules []models.Module
+	// creators are the tools the modules were resolved with besides the generator
+	creators []string
+	// licenses is the scan of the license identifiers of the project, shared by the managers of a run
+	licenses *licenseScan
 }
 
 // Config ...
 type Config struct {
 	Path string
+	// Context bounds the whole generation, a nil Context never expires
+	Context context.Context
+	// IncludeBuildTool adds the build tool as a package with a BUILD_TOOL_OF relationship to the root
+	IncludeBuildTool bool
+	// IncludeBuildPlugins adds the build plugins as packages BUILD_TOOL_OF the module declaring them
+	IncludeBuildPlugins bool
+	// AllowNetwork lets plugins query remote repositories, e.g. to fetch checksums
+	AllowNetwork bool
+	// Offline keeps the plugins and their package managers off the network, it overrides AllowNetwork
+	Offline bool
+	// Cache keeps what the plugins resolved, so an interrupted generation resumes
+	Cache *cache.Cache
+	// VersionLockFile pins the dependency versions over the ones of the build files
+	VersionLockFile string
+	// PackagingExtensions maps custom packagings to their artifact file extension, e.g. bundle=jar
+	PackagingExtensions map[string]string
+	// MavenProfiles are the maven profiles to activate, the activation rules of the poms apply when empty
+	MavenProfiles []string
+	// MavenEffectivePom reads the poms computed by mvn help:effective-pom rather than the pom.xml files
+	MavenEffectivePom bool
+	// IncludeScopes are the only dependency scopes listed when not empty, ExcludeScopes are never listed
+	IncludeScopes []string
+	ExcludeScopes []string
+	// MavenSettings is the settings.xml of the maven mirrors, proxies and credentials, the user one when empty
+	MavenSettings string
+	// MavenParallelism is the number of maven dependencies looked up at once, as many as CPUs when 0
+	MavenParallelism int
+	// GoVendor reads the go modules of vendor/modules.txt rather than running the go command
+	GoVendor bool
+	// LockfileOnly reads the manifests and lockfiles without running the package managers, it implies Offline
+	LockfileOnly bool
+	// RootFS is the root filesystem the operating system packages are read from, the project path when empty
+	RootFS string
+	// LicenseMatcher identifies the license files of the modules, the SPDX license list when nil
+	LicenseMatcher helper.LicenseMatcher
+}
+
+// pluginOptions returns the options passed to the plugins
+func (c Config) pluginOptions() models.PluginOptions {
+	return models.PluginOptions{
+		Context:             c.Context,
+		IncludeBuildTools:   c.IncludeBuildPlugins,
+		AllowNetwork:        c.AllowNetwork && !c.offline(),
+		Offline:             c.offline(),
+		Cache:               c.Cache,
+		VersionLockFile:     c.VersionLockFile,
+		PackagingExtensions: c.PackagingExtensions,
+		MavenProfiles:       c.MavenProfiles,
+		MavenEffectivePom:   c.MavenEffectivePom,
+		IncludeScopes:       c.IncludeScopes,
+		ExcludeScopes:       c.ExcludeScopes,
+		MavenSettings:       c.MavenSettings,
+		MavenParallelism:    c.MavenParallelism,
+		GoVendor:            c.GoVendor,
+		LockfileOnly:        c.LockfileOnly,
+		RootFS:              c.RootFS,
+		LicenseMatcher:      c.LicenseMatcher,
+	}
+}
+
+// offline tells whether the network is off, a lockfile-only generation has nothing to resolve remotely
+func (c Config) offline() bool {
+	return c.Offline || c.LockfileOnly
 }
 
 // New ...
 func New(cfg Config) ([]*Manager, error) {
 	var usePlugin models.IPlugin
 	var managerSlice []*Manager
-	for _, plugin := range registeredPlugins {
+	licenses := &licenseScan{}
+	for _, newPlugin := range registeredPlugins {
+		plugin := newPlugin()
+		// the options may tell where the manifests are, e.g. the root filesystem of the operating system packages
+		if configurable, ok := plugin.(models.IConfigurablePlugin); ok {
+			configurable.SetOptions(cfg.pluginOptions())
+		}
 		if plugin.IsValid(cfg.Path) {
-			if err := plugin.SetRootModule(cfg.Path); err != nil {
+			err := runWithContext(cfg.Context, func() error {
+				return plugin.SetRootModule(cfg.Path)
+			})
+			if err != nil {
 				return nil, err
 			}
 
@@ -74,8 +223,9 @@ func New(cfg Config) ([]*Manager, error) {
 			}
 
 			managerSlice = append(managerSlice, &Manager{
-				Config: cfg,
-				Plugin: usePlugin,
+				Config:   cfg,
+				Plugin:   usePlugin,
+				licenses: licenses,
 			})
 		}
 	}
@@ -85,13 +235,24 @@ func New(cfg Config) ([]*Manager, error) {
 
 // Run ...
 func (m *Manager) Run() error {
+	return runWithContext(m.Config.Context, m.run)
+}
+
+func (m *Manager) run() error {
 	modulePath := m.Config.Path
 	version, err := m.Plugin.GetVersion()
-	if err != nil {
+	switch {
+	case err != nil && m.Config.LockfileOnly:
+		// the lockfiles are read without the package manager, it need not be installed
+		log.Infof("%s is not available, the modules are read from the lockfiles only: %v", m.Plugin.GetMetadata().Name, err)
+	case err != nil:
 		return err
+	default:
+		log.Infof("Current Language Version %s", version)
+		if creator := toolCreator(m.Plugin, version); creator != "" && !m.Config.LockfileOnly {
+			m.creators = []string{creator}
+		}
 	}
-
-	log.Infof("Current Language Version %s", version)
 	if err := m.Plugin.HasModulesInstalled(modulePath); err != nil {
 		return err
 	}
@@ -102,6 +263,20 @@ func (m *Manager) Run() error {
 		return errFailedToReadModules
 	}
 
+	if m.licenses == nil {
+		m.licenses = &licenseScan{}
+	}
+	m.licenses.setRootLicense(modulePath, modules)
+
+	if m.Config.IncludeBuildTool {
+		tool, err := buildToolModule(m.Plugin.GetMetadata(), version)
+		if err != nil {
+			log.Warn(err)
+		} else {
+			modules = addBuildTool(modules, tool)
+		}
+	}
+
 	m.modules = modules
 
 	return nil
@@ -111,3 +286,63 @@ func (m *Manager) Run() error {
 func (m *Manager) GetSource() []models.Module {
 	return m.modules
 }
+
+// Creators returns the creators of the document besides the generator, e.g. the wrapper the build ran
+func (m *Manager) Creators() []string {
+	return m.creators
+}
+
+// licenseScan is the license expression of the SPDX-License-Identifier comments found in the project
+// sources, the source tree is walked once however many package managers the project has
+type licenseScan struct {
+	once       sync.Once
+	expression string
+}
+
+// setRootLicense sets the root license from the license identifiers of the project sources, if any,
+// unless the package manager declares one
+func (s *licenseScan) setRootLicense(path string, modules []models.Module) {
+	for i := range modules {
+		if !modules[i].Root {
+			continue
+		}
+		if modules[i].LicenseDeclared != "" && modules[i].LicenseDeclared != "NOASSERTION" {
+			return
+		}
+
+		s.once.Do(func() {
+			identifiers, err := helper.ScanLicenseIdentifiers(path)
+			if err == nil && len(identifiers) > 0 {
+				s.expression = helper.BuildLicenseExpression(identifiers)
+			}
+		})
+		if s.expression != "" {
+			modules[i].LicenseDeclared = s.expression
+			modules[i].LicenseConcluded = s.expression
+		}
+		return
+	}
+}
+
+// runWithContext runs fn and returns early once ctx is done. The plugins run their package managers
+// with the same context, which kills them, so the plugin call stops shortly after in the background
+func runWithContext(ctx context.Context, fn func() error) error {
+	if ctx == nil {
+		return fn()
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", errGenerationAborted, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", errGenerationAborted, ctx.Err())
+	}
+}
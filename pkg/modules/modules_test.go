@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// stubPlugin is a minimal plugin, block makes ListModulesWithDeps hang until the context is done
+type stubPlugin struct {
+	slug    string
+	version string
+	// versionErr is returned by GetVersion, as when the package manager is not installed
+	versionErr error
+	modules    []models.Module
+	block      bool
+	options    models.PluginOptions
+}
+
+func (s *stubPlugin) SetOptions(opts models.PluginOptions) { s.options = opts }
+func (s *stubPlugin) SetRootModule(path string) error      { return nil }
+func (s *stubPlugin) GetVersion() (string, error)          { return s.version, s.versionErr }
+func (s *stubPlugin) GetMetadata() models.PluginMetadata {
+	if s.slug == "" {
+		return models.PluginMetadata{Name: "Stub", Slug: "stub"}
+	}
+	return models.PluginMetadata{Name: s.slug, Slug: s.slug}
+}
+func (s *stubPlugin) GetRootModule(path string) (*models.Module, error) { return nil, nil }
+func (s *stubPlugin) ListUsedModules(path string) ([]models.Module, error) {
+	return s.modules, nil
+}
+func (s *stubPlugin) ListModulesWithDeps(path string) ([]models.Module, error) {
+	if s.block {
+		<-s.options.Context.Done()
+		return nil, s.options.Context.Err()
+	}
+	return s.modules, nil
+}
+func (s *stubPlugin) IsValid(path string) bool              { return true }
+func (s *stubPlugin) HasModulesInstalled(path string) error { return nil }
+
+func TestRunAbortsOnTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	plugin := &stubPlugin{block: true}
+	plugin.SetOptions(models.PluginOptions{Context: ctx})
+	manager := &Manager{
+		Config: Config{Path: ".", Context: ctx},
+		Plugin: plugin,
+	}
+
+	start := time.Now()
+	err := manager.Run()
+
+	assert.True(t, errors.Is(err, errGenerationAborted))
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	assert.Empty(t, manager.GetSource())
+}
+
+func TestRunWithoutContext(t *testing.T) {
+	manager := &Manager{
+		Config: Config{Path: "."},
+		Plugin: &stubPlugin{modules: []models.Module{{Name: "root", Root: true}}},
+	}
+
+	assert.NoError(t, manager.Run())
+	assert.Len(t, manager.GetSource(), 1)
+}
+
+func TestRunIncludesBuildTool(t *testing.T) {
+	manager := &Manager{
+		Config: Config{Path: ".", IncludeBuildTool: true},
+		Plugin: &stubPlugin{
+			slug:    "Java-Maven",
+			version: "Apache Maven 3.8.1 (05c21c65bdfed0f71a2f2ada8b84da59348c4c5d)\nMaven home: /usr/share/maven",
+			modules: []models.Module{{Name: "root", Root: true}},
+		},
+	}
+
+	assert.NoError(t, manager.Run())
+
+	source := manager.GetSource()
+	assert.Len(t, source, 2)
+
+	tool := source[1]
+	assert.Equal(t, "maven", tool.Name)
+	assert.Equal(t, "3.8.1", tool.Version)
+	assert.Equal(t, models.RelationshipBuildToolOf, tool.Relationship)
+	assert.Equal(t, "3.8.1", source[0].Modules["maven"].Version)
+}
+
+// executablePlugin is a stub plugin telling the binary it runs
+type executablePlugin struct {
+	stubPlugin
+	executable string
+}
+
+func (e *executablePlugin) GetExecutable() string { return e.executable }
+
+func TestRunRecordsExecutable(t *testing.T) {
+	version := "Apache Maven 3.8.6 (84538c9988a25aec085021c365c560670ad80f63)"
+	manager := &Manager{
+		Config: Config{Path: "."},
+		Plugin: &executablePlugin{
+			stubPlugin: stubPlugin{version: version, modules: []models.Module{{Name: "root", Root: true}}},
+			executable: "mvnw",
+		},
+	}
+	assert.NoError(t, manager.Run())
+	assert.Equal(t, []string{"Tool: mvnw-3.8.6"}, manager.Creators())
+
+	manager = &Manager{
+		Config: Config{Path: "."},
+		Plugin: &stubPlugin{version: version, modules: []models.Module{{Name: "root", Root: true}}},
+	}
+	assert.NoError(t, manager.Run())
+	assert.Empty(t, manager.Creators())
+}
+
+func TestRunLockfileOnly(t *testing.T) {
+	plugin := &executablePlugin{
+		stubPlugin: stubPlugin{versionErr: errors.New("exec: \"mvn\": executable file not found in $PATH"), modules: []models.Module{{Name: "root", Root: true}}},
+		executable: "mvn",
+	}
+	manager := &Manager{Config: Config{Path: "."}, Plugin: plugin}
+	assert.Equal(t, plugin.versionErr, manager.Run())
+
+	manager = &Manager{Config: Config{Path: ".", LockfileOnly: true}, Plugin: plugin}
+	assert.NoError(t, manager.Run())
+	assert.Len(t, manager.GetSource(), 1)
+	assert.Empty(t, manager.Creators())
+
+	options := Config{AllowNetwork: true, LockfileOnly: true}.pluginOptions()
+	assert.True(t, options.Offline)
+	assert.False(t, options.AllowNetwork)
+	assert.True(t, options.LockfileOnly)
+}
+
+func TestParseToolVersion(t *testing.T) {
+	tests := map[string]string{
+		"Apache Maven 3.8.1 (05c21c65bdfed0f71a2f2ada8b84da59348c4c5d)": "3.8.1",
+		"go version go1.16.5 linux/amd64":                               "1.16.5",
+		"7.5.1":                                                         "7.5.1",
+		"unknown":                                                       "",
+	}
+
+	for output, expected := range tests {
+		assert.Equal(t, expected, parseToolVersion(output), output)
+	}
+}
+
+func TestRunSetsRootLicenseFromIdentifiers(t *testing.T) {
+	manager := &Manager{
+		Config: Config{Path: filepath.Join("..", "helper", "testdata", "identifiers")},
+		Plugin: &stubPlugin{modules: []models.Module{{Name: "root", Root: true}}},
+	}
+
+	assert.NoError(t, manager.Run())
+
+	root := manager.GetSource()[0]
+	assert.Equal(t, "Apache-2.0 AND MIT AND (MIT OR Apache-2.0)", root.LicenseDeclared)
+	assert.Equal(t, root.LicenseDeclared, root.LicenseConcluded)
+}
+
+func TestRunKeepsDeclaredRootLicense(t *testing.T) {
+	licenses := &licenseScan{}
+	managers := []*Manager{{
+		Config:   Config{Path: filepath.Join("..", "helper", "testdata", "identifiers")},
+		Plugin:   &stubPlugin{modules: []models.Module{{Name: "root", Root: true, LicenseDeclared: "BSD-3-Clause", LicenseConcluded: "BSD-3-Clause"}}},
+		licenses: licenses,
+	}, {
+		Config:   Config{Path: filepath.Join("..", "helper", "testdata", "identifiers")},
+		Plugin:   &stubPlugin{modules: []models.Module{{Name: "root", Root: true, LicenseDeclared: "NOASSERTION"}}},
+		licenses: licenses,
+	}}
+
+	for _, manager := range managers {
+		assert.NoError(t, manager.Run())
+	}
+
+	assert.Equal(t, "BSD-3-Clause", managers[0].GetSource()[0].LicenseDeclared)
+	assert.Equal(t, "Apache-2.0 AND MIT AND (MIT OR Apache-2.0)", managers[1].GetSource()[0].LicenseDeclared)
+}
+
+func TestSupported(t *testing.T) {
+	slugs := map[string][]string{}
+	for _, metadata := range Supported() {
+		assert.NotEmpty(t, metadata.Name)
+		slugs[metadata.Slug] = metadata.Manifest
+	}
+
+	for _, slug := range []string{"cargo", "clojure", "composer", "go-mod", "bundler", "npm", "pnpm", "Java-Gradle", "Java-Maven", "ivy", "nuget", "yarn", "pipenv", "poetry", "pyenv", "conda", "sbt", "swift", "terraform"} {
+		assert.Contains(t, slugs, slug)
+	}
+	assert.Equal(t, []string{"pom.xml"}, slugs["Java-Maven"])
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package modulestest provides the helpers shared by the tests of the package manager plugins
+package modulestest
+
+import (
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// LinkedNames returns the sorted keys of the modules linked to the module
+func LinkedNames(mod models.Module) []string {
+	var names []string
+	for name := range mod.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ByName returns the modules by their name
+func ByName(modules []models.Module) map[string]models.Module {
+	result := map[string]models.Module{}
+	for _, mod := range modules {
+		result[mod.Name] = mod
+	}
+	return result
+}
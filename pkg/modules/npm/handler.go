@@ -5,7 +5,6 @@ package npm
 import (
 	"crypto/sha256"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -17,6 +16,7 @@ import (
 
 type npm struct {
 	metadata models.PluginMetadata
+	options  models.PluginOptions
 }
 
 var (
@@ -54,10 +54,11 @@ func (m *npm) IsValid(path string) bool {
 	return true
 }
 
-// HasModulesInstalled checks if modules of manifest file already installed
+// HasModulesInstalled checks if modules of manifest file already installed, the lockfile is enough in the
+// lockfile-only mode
 func (m *npm) HasModulesInstalled(path string) error {
 	for _, p := range m.metadata.ModulePath {
-		if !helper.Exists(filepath.Join(path, p)) {
+		if !helper.Exists(filepath.Join(path, p)) && !m.options.LockfileOnly {
 			return errDependenciesNotFound
 		}
 	}
@@ -70,9 +71,14 @@ func (m *npm) HasModulesInstalled(path string) error {
 	return nil
 }
 
+// SetOptions ...
+func (m *npm) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // GetVersion returns npm version
 func (m *npm) GetVersion() (string, error) {
-	cmd := exec.Command("npm", "--v")
+	cmd := helper.CommandContext(m.options.Context, "npm", "--v")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -119,8 +125,9 @@ func (m *npm) GetRootModule(path string) (*models.Module, error) {
 			mod.PackageDownloadLocation = repository.(map[string]interface{})["url"].(string)
 		}
 	}
-	if pkResult["homepage"] != nil {
-		mod.PackageURL = helper.RemoveURLProtocol(pkResult["homepage"].(string))
+	if homepage, ok := pkResult["homepage"].(string); ok {
+		mod.PackageURL = helper.RemoveURLProtocol(homepage)
+		mod.PackageHomePage = homepage
 	}
 	if !rg.MatchString(mod.PackageDownloadLocation) {
 		mod.PackageDownloadLocation = "NONE"
@@ -129,7 +136,7 @@ func (m *npm) GetRootModule(path string) (*models.Module, error) {
 	mod.Modules = map[string]*models.Module{}
 
 	mod.Copyright = getCopyright(path)
-	modLic, err := helper.GetLicenses(path)
+	modLic, err := helper.GetLicenses(m.options.LicenseMatcher, path)
 	if err != nil {
 		return mod, nil
 	}
@@ -177,19 +184,44 @@ func (m *npm) ListModulesWithDeps(path string) ([]models.Module, error) {
 		return []models.Module{}, err
 	}
 
-	deps, ok := pkResults["packages"].(map[string]interface{})
+	// v2 and v3 lockfiles list every install location, which gives the actual resolution tree
+	if packages, ok := pkResults["packages"].(map[string]interface{}); ok {
+		return m.buildPackages(path, packages)
+	}
+
+	deps, ok := pkResults["dependencies"].(map[string]interface{})
 	if !ok {
-		deps = pkResults["dependencies"].(map[string]interface{})
+		return []models.Module{}, fmt.Errorf("%w %s: neither packages nor dependencies are listed", reader.ErrMalformedFile, filepath.Join(path, pk))
+	}
+	if err := validateDependencies(deps, "dependencies"); err != nil {
+		return []models.Module{}, fmt.Errorf("%w %s: %v", reader.ErrMalformedFile, filepath.Join(path, pk), err)
 	}
 
 	return m.buildDependencies(path, deps)
 }
 
-func (m *npm) buildDependencies(path string, deps map[string]interface{}) ([]models.Module, error) {
-	modules := make([]models.Module, 0)
+func (m *npm) buildPackages(path string, packages map[string]interface{}) ([]models.Module, error) {
+	de, err := m.buildRootModule(path)
+	if err != nil {
+		return []models.Module{}, err
+	}
+
+	entries := make(map[string]map[string]interface{}, len(packages))
+	for location, entry := range packages {
+		if e, ok := entry.(map[string]interface{}); ok {
+			entries[location] = e
+		}
+	}
+
+	modules := m.buildPackagesGraph(path, de, entries)
+
+	return append([]models.Module{*de}, modules...), nil
+}
+
+func (m *npm) buildRootModule(path string) (*models.Module, error) {
 	de, err := m.GetRootModule(path)
 	if err != nil {
-		return modules, err
+		return nil, err
 	}
 	h := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s-%s", de.Name, de.Version))))
 	de.CheckSum = &models.CheckSum{
@@ -200,6 +232,16 @@ func (m *npm) buildDependencies(path string, deps map[string]interface{}) ([]mod
 	if de.PackageDownloadLocation == "" {
 		de.PackageDownloadLocation = de.Name
 	}
+
+	return de, nil
+}
+
+func (m *npm) buildDependencies(path string, deps map[string]interface{}) ([]models.Module, error) {
+	modules := make([]models.Module, 0)
+	de, err := m.buildRootModule(path)
+	if err != nil {
+		return modules, err
+	}
 	rootDeps := getPackageDependencies(deps, "dependencies")
 	for k, v := range rootDeps {
 		de.Modules[k] = v
@@ -227,7 +269,8 @@ func (m *npm) buildDependencies(path string, deps map[string]interface{}) ([]mod
 			}
 			mod.Supplier.Name = mod.Name
 
-			mod.PackageURL = getPackageHomepage(filepath.Join(path, m.metadata.ModulePath[0], key, m.metadata.Manifest[0]))
+			mod.PackageHomePage = getPackageHomepage(filepath.Join(path, m.metadata.ModulePath[0], key, m.metadata.Manifest[0]))
+			mod.PackageURL = helper.RemoveURLProtocol(mod.PackageHomePage)
 			h := fmt.Sprintf("%x", sha256.Sum256([]byte(mod.Name)))
 			mod.CheckSum = &models.CheckSum{
 				Algorithm: "SHA256",
@@ -252,7 +295,7 @@ func (m *npm) buildDependencies(path string, deps map[string]interface{}) ([]mod
 				}
 			}
 
-			modLic, err := helper.GetLicenses(filepath.Join(path, m.metadata.ModulePath[0], key))
+			modLic, err := helper.GetLicenses(m.options.LicenseMatcher, filepath.Join(path, m.metadata.ModulePath[0], key))
 			if err != nil {
 				modules = append(modules, mod)
 				continue
@@ -328,14 +371,15 @@ func getPackageDependencies(modDeps map[string]interface{}, t string) map[string
 	return m
 }
 
+// getPackageHomepage returns the homepage declared in the package.json at path
 func getPackageHomepage(path string) string {
 	r := reader.New(path)
 	pkResult, err := r.ReadJson()
 	if err != nil {
 		return ""
 	}
-	if pkResult["homepage"] != nil {
-		return helper.RemoveURLProtocol(pkResult["homepage"].(string))
+	if homepage, ok := pkResult["homepage"].(string); ok {
+		return homepage
 	}
 	return ""
 }
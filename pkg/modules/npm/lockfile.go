@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package npm
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const nodeModules = "node_modules/"
+
+// lockPackage is an entry of the "packages" section of a v2/v3 lockfile,
+// keyed by its install location, e.g. node_modules/a/node_modules/b
+type lockPackage struct {
+	location string
+	entry    map[string]interface{}
+}
+
+// name returns the package name, an aliased install carries the real name in the entry
+func (p lockPackage) name() string {
+	if name, ok := p.entry["name"].(string); ok && name != "" && p.location != "" {
+		return name
+	}
+
+	idx := strings.LastIndex(p.location, nodeModules)
+	if idx < 0 {
+		return p.location
+	}
+	return p.location[idx+len(nodeModules):]
+}
+
+func (p lockPackage) version() string {
+	version, _ := p.entry["version"].(string)
+	return version
+}
+
+func (p lockPackage) key() string {
+	return fmt.Sprintf("%s@%s", p.name(), p.version())
+}
+
+// devDependencies returns the names of the packages p only depends on for development
+func (p lockPackage) devDependencies() map[string]bool {
+	runtime := map[string]bool{}
+	for _, name := range p.dependencies(false) {
+		runtime[name] = true
+	}
+
+	dev := map[string]bool{}
+	for _, name := range p.dependencies(true) {
+		if !runtime[name] {
+			dev[name] = true
+		}
+	}
+	return dev
+}
+
+// dependencies returns the names of the packages p depends on
+func (p lockPackage) dependencies(includeDev bool) []string {
+	sections := []string{"dependencies", "optionalDependencies", "peerDependencies"}
+	if includeDev {
+		sections = append(sections, "devDependencies")
+	}
+
+	var names []string
+	for _, section := range sections {
+		deps, ok := p.entry[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name := range deps {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// resolveLocation finds where the dependency name of the package installed at location is,
+// following the node resolution algorithm: the closest node_modules folder walking up wins
+func resolveLocation(packages map[string]map[string]interface{}, location, name string) (string, bool) {
+	for {
+		candidate := nodeModules + name
+		if location != "" {
+			candidate = location + "/" + nodeModules + name
+		}
+		if _, ok := packages[candidate]; ok {
+			return candidate, true
+		}
+
+		if location == "" {
+			return "", false
+		}
+
+		idx := strings.LastIndex(location, nodeModules)
+		if idx <= 0 {
+			location = ""
+			continue
+		}
+		location = strings.TrimSuffix(location[:idx], "/")
+	}
+}
+
+// buildPackagesGraph builds the modules from the "packages" section of a v2/v3 lockfile.
+// Every distinct name@version is a module, so several versions of a package coexist.
+// The workspace packages, listed at their directory, are contained by the root
+func (m *npm) buildPackagesGraph(path string, root *models.Module, packages map[string]map[string]interface{}) []models.Module {
+	modules := map[string]*models.Module{}
+	var order []string
+	var workspaces []string
+	patterns := helper.WorkspacePatterns(packages[""])
+
+	locations := make([]string, 0, len(packages))
+	for location := range packages {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	for _, location := range locations {
+		pkg := lockPackage{location: location, entry: packages[location]}
+		if location == "" || isLink(pkg.entry) {
+			continue
+		}
+
+		if _, ok := modules[pkg.key()]; ok {
+			continue
+		}
+
+		mod := m.buildLockPackageModule(path, pkg)
+		if helper.MatchWorkspace(patterns, location) {
+			mod = m.buildWorkspaceModule(path, pkg)
+			workspaces = append(workspaces, pkg.key())
+		}
+		modules[pkg.key()] = &mod
+		order = append(order, pkg.key())
+	}
+
+	// link every installed package, and the root, to the packages it resolves to
+	for _, location := range locations {
+		pkg := lockPackage{location: location, entry: packages[location]}
+		if isLink(pkg.entry) {
+			continue
+		}
+
+		// only the devDependencies of the root and of the workspace packages are installed
+		parent := root
+		if location != "" {
+			parent = modules[pkg.key()]
+		}
+		project := location == "" || helper.MatchWorkspace(patterns, location)
+		devDependencies := map[string]bool{}
+		if project {
+			devDependencies = pkg.devDependencies()
+		}
+
+		for _, name := range pkg.dependencies(project) {
+			depLocation, ok := resolveLocation(packages, location, name)
+			if !ok {
+				continue
+			}
+
+			dep := lockPackage{location: depLocation, entry: packages[depLocation]}
+			if target, ok := dep.entry["resolved"].(string); ok && isLink(dep.entry) {
+				dep = lockPackage{location: target, entry: packages[target]}
+			}
+
+			depModule, ok := modules[dep.key()]
+			if !ok {
+				continue
+			}
+			if devDependencies[name] {
+				// the module is shared, the relationship only holds for this dependent
+				devModule := *depModule
+				devModule.Relationship = models.ScopeRelationship("devDependencies")
+				depModule = &devModule
+			}
+			parent.Modules[depModule.Name] = depModule
+		}
+	}
+
+	for _, key := range workspaces {
+		workspace := *modules[key]
+		workspace.Relationship = models.RelationshipContains
+		root.Modules[workspace.Name] = &workspace
+	}
+
+	result := make([]models.Module, 0, len(order))
+	for _, key := range order {
+		result = append(result, *modules[key])
+	}
+
+	return result
+}
+
+func (m *npm) buildLockPackageModule(path string, pkg lockPackage) models.Module {
+	var mod models.Module
+	mod.Name = pkg.name()
+	mod.Version = pkg.version()
+	mod.Supplier.Name = mod.Name
+	mod.Modules = map[string]*models.Module{}
+
+	if resolved, ok := pkg.entry["resolved"].(string); ok {
+		mod.PackageDownloadLocation = resolved
+	}
+	if mod.PackageDownloadLocation == "" {
+		mod.PackageDownloadLocation = fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", mod.Name, mod.Version)
+	}
+
+	installPath := filepath.Join(path, filepath.FromSlash(pkg.location))
+	mod.PackageHomePage = getPackageHomepage(filepath.Join(installPath, m.metadata.Manifest[0]))
+	mod.PackageURL = helper.RemoveURLProtocol(mod.PackageHomePage)
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: "SHA256",
+		Value:     fmt.Sprintf("%x", sha256.Sum256([]byte(mod.Name))),
+	}
+	mod.Copyright = getCopyright(installPath)
+
+	modLic, err := helper.GetLicenses(m.options.LicenseMatcher, installPath)
+	if err != nil {
+		return mod
+	}
+	mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
+	mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
+	mod.CommentsLicense = modLic.Comments
+	if !helper.LicenseSPDXExists(modLic.ID) {
+		mod.OtherLicense = append(mod.OtherLicense, modLic)
+	}
+
+	return mod
+}
+
+// buildWorkspaceModule builds the module of a workspace package from its package.json, as the root one,
+// the lockfile entry is used when it cannot be read
+func (m *npm) buildWorkspaceModule(path string, pkg lockPackage) models.Module {
+	mod, err := m.buildRootModule(filepath.Join(path, filepath.FromSlash(pkg.location)))
+	if err != nil {
+		return m.buildLockPackageModule(path, pkg)
+	}
+	mod.Name, mod.Version = pkg.name(), pkg.version()
+	return *mod
+}
+
+func isLink(entry map[string]interface{}) bool {
+	link, _ := entry["link"].(bool)
+	return link
+}
+
+// validateDependencies checks the "dependencies" tree of a v1 lockfile has the shape buildDependencies
+// walks, the error names the JSON path of the first malformed entry, e.g. dependencies.a.dependencies.b
+func validateDependencies(deps map[string]interface{}, parent string) error {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		location := parent + "." + name
+		entry, ok := deps[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: not an object", location)
+		}
+		if _, ok := entry["version"].(string); !ok {
+			return fmt.Errorf("%s: version is not a string", location)
+		}
+		if resolved, ok := entry["resolved"]; ok && resolved != nil {
+			if _, ok := resolved.(string); !ok {
+				return fmt.Errorf("%s: resolved is not a string", location)
+			}
+		}
+		if requires, ok := entry["requires"]; ok {
+			required, ok := requires.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.requires: not an object", location)
+			}
+			for requiredName, version := range required {
+				if _, ok := version.(string); !ok {
+					return fmt.Errorf("%s.requires.%s: not a string", location, requiredName)
+				}
+			}
+		}
+		if nested, ok := entry["dependencies"]; ok {
+			nestedDeps, ok := nested.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.dependencies: not an object", location)
+			}
+			if err := validateDependencies(nestedDeps, location+".dependencies"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
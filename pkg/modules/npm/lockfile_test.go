@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package npm
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestListModulesWithDepsMultipleVersions(t *testing.T) {
+	n := New()
+	mods, err := n.ListModulesWithDeps(filepath.Join("test", "lockfile-v2"))
+	assert.NoError(t, err)
+
+	byKey := map[string]models.Module{}
+	for _, mod := range mods {
+		byKey[mod.Name+"@"+mod.Version] = mod
+	}
+	assert.Len(t, mods, 7)
+
+	root := byKey["multi-version@1.0.0"]
+	assert.Equal(t, "4.3.2", root.Modules["debug"].Version)
+	assert.Equal(t, "4.17.1", root.Modules["express"].Version)
+	assert.Equal(t, "16.11.7", root.Modules["@types/node"].Version)
+
+	// both versions of debug and ms coexist, each resolved from its install location
+	assert.Contains(t, byKey, "debug@4.3.2")
+	assert.Contains(t, byKey, "debug@2.6.9")
+	assert.Contains(t, byKey, "ms@2.1.2")
+	assert.Contains(t, byKey, "ms@2.0.0")
+
+	assert.Equal(t, "2.6.9", byKey["express@4.17.1"].Modules["debug"].Version)
+	assert.Equal(t, "2.0.0", byKey["debug@2.6.9"].Modules["ms"].Version)
+	assert.Equal(t, "2.1.2", byKey["debug@4.3.2"].Modules["ms"].Version)
+	assert.Equal(t, "https://registry.npmjs.org/debug/-/debug-2.6.9.tgz", byKey["debug@2.6.9"].PackageDownloadLocation)
+}
+
+func TestListModulesWithDepsDevDependencies(t *testing.T) {
+	n := New()
+	mods, err := n.ListModulesWithDeps(filepath.Join("test", "lockfile-v2"))
+	assert.NoError(t, err)
+
+	byKey := map[string]models.Module{}
+	for _, mod := range mods {
+		byKey[mod.Name+"@"+mod.Version] = mod
+	}
+
+	root := byKey["multi-version@1.0.0"]
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["@types/node"].Relationship)
+	assert.Equal(t, models.RelationshipType(""), root.Modules["express"].Relationship)
+	assert.Equal(t, models.RelationshipType(""), byKey["express@4.17.1"].Modules["debug"].Relationship)
+}
+
+func TestListModulesWithDepsHomePage(t *testing.T) {
+	n := New()
+	mods, err := n.ListModulesWithDeps(filepath.Join("test", "lockfile-v2"))
+	assert.NoError(t, err)
+
+	homePages := map[string]string{}
+	for _, mod := range mods {
+		homePages[mod.Name+"@"+mod.Version] = mod.PackageHomePage
+	}
+
+	assert.Equal(t, "https://example.org/multi-version", homePages["multi-version@1.0.0"])
+	assert.Equal(t, "http://expressjs.com/", homePages["express@4.17.1"])
+	assert.Equal(t, "", homePages["debug@4.3.2"])
+}
+
+func TestListModulesWithDepsWorkspaces(t *testing.T) {
+	n := New()
+	mods, err := n.ListModulesWithDeps(filepath.Join("test", "workspaces"))
+	assert.NoError(t, err)
+
+	byKey := map[string]models.Module{}
+	for _, mod := range mods {
+		byKey[mod.Name+"@"+mod.Version] = mod
+	}
+	assert.Len(t, mods, 6)
+
+	root := byKey["monorepo@1.0.0"]
+	assert.Equal(t, models.RelationshipContains, root.Modules["app"].Relationship)
+	assert.Equal(t, models.RelationshipContains, root.Modules["@example/lib"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["ms"].Relationship)
+
+	// the workspace packages are read from their package.json and depend on each other through their links
+	app := byKey["app@0.1.0"]
+	assert.Equal(t, "1.0.0", app.Modules["@example/lib"].Version)
+	assert.Equal(t, models.RelationshipType(""), app.Modules["@example/lib"].Relationship)
+	assert.Equal(t, "4.3.4", app.Modules["debug"].Version)
+	assert.Equal(t, "NONE", app.PackageDownloadLocation)
+
+	lib := byKey["@example/lib@1.0.0"]
+	assert.Equal(t, "https://example.com/lib", lib.PackageHomePage)
+	assert.Equal(t, "2.1.3", lib.Modules["ms"].Version)
+	assert.Equal(t, models.RelationshipDevDependencyOf, lib.Modules["ms"].Relationship)
+	assert.Equal(t, "2.1.2", byKey["debug@4.3.4"].Modules["ms"].Version)
+}
+
+func TestResolveLocation(t *testing.T) {
+	packages := map[string]map[string]interface{}{
+		"node_modules/a":                                  {},
+		"node_modules/b":                                  {},
+		"node_modules/a/node_modules/b":                   {},
+		"node_modules/a/node_modules/b/node_modules/c":    {},
+		"node_modules/@scope/d":                           {},
+		"node_modules/a/node_modules/b/node_modules/@x/y": {},
+	}
+
+	tests := []struct {
+		location string
+		name     string
+		expected string
+	}{
+		{"", "a", "node_modules/a"},
+		{"node_modules/a", "b", "node_modules/a/node_modules/b"},
+		{"node_modules/a/node_modules/b", "b", "node_modules/a/node_modules/b"},
+		{"node_modules/a/node_modules/b/node_modules/c", "@scope/d", "node_modules/@scope/d"},
+		{"node_modules/a/node_modules/b/node_modules/@x/y", "c", "node_modules/a/node_modules/b/node_modules/c"},
+		{"node_modules/b", "c", ""},
+	}
+
+	for _, test := range tests {
+		location, _ := resolveLocation(packages, test.location, test.name)
+		assert.Equal(t, test.expected, location, test.location+" -> "+test.name)
+	}
+}
+
+func TestListModulesWithDepsMalformedLockfile(t *testing.T) {
+	n := New()
+	_, err := n.ListModulesWithDeps(filepath.Join("test", "truncated-lockfile"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file test/truncated-lockfile/package-lock.json: line 10, column 23: unexpected end of JSON input")
+
+	_, err = n.ListModulesWithDeps(filepath.Join("test", "malformed-lockfile-v1"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file test/malformed-lockfile-v1/package-lock.json: dependencies.debug.dependencies.ms: version is not a string")
+}
@@ -12,3 +12,4 @@ var errDependenciesNotFound errType = errors.New("Unable to generate SPDX file,
 var errNoDotnetCommand errType = errors.New("No dotnet command")
 var errNoDependencyCache errType = errors.New("local dependency cache not found")
 var errFailedToConvertModules errType = errors.New("Failed to convert modules")
+var errUnsupportedLockfileVersion errType = errors.New("unsupported packages.lock.json version")
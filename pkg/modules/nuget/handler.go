@@ -3,7 +3,6 @@
 package nuget
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -15,12 +14,14 @@ import (
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
 )
 
 type nuget struct {
 	metadata   models.PluginMetadata
 	rootModule *models.Module
 	command    *helper.Cmd
+	options    models.PluginOptions
 }
 
 var (
@@ -30,14 +31,13 @@ var (
 	pkgExt                 = ".nupkg"
 	sha512Ext              = ".nupkg.sha512"
 	nugetBaseUrl           = "https://api.nuget.org/v3-flatcontainer/"
-	manifestExtensions     = []string{".sln", ".csproj", ".vbproj"}
-	directoryFilterPattern = "*.[^d-u][^c-r]proj"
+	manifestExtensions     = []string{".sln", ".csproj", ".fsproj", ".vbproj"}
+	projectExtensions      = []string{".csproj", ".fsproj", ".vbproj"}
 	assetDirectoryJoinPath = "obj"
 	assetModuleFile        = "project.assets.json"
 	assetTargets           = "targets"
-	assetType              = "type"
 	assetPackage           = "package"
-	assetDependencies      = "dependencies"
+	lockModuleFile         = "packages.lock.json"
 	configModuleFile       = "packages.config"
 	nugetPackageSplit      = "global-packages:"
 )
@@ -54,6 +54,11 @@ func New() *nuget {
 	}
 }
 
+// SetOptions ...
+func (m *nuget) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // GetMetadata ...
 func (m *nuget) GetMetadata() models.PluginMetadata {
 	return m.metadata
@@ -76,8 +81,38 @@ func (m *nuget) IsValid(path string) bool {
 	return helper.Exists(projectPath)
 }
 
-// HasModulesInstalled ...
+// HasModulesInstalled restores the projects which have neither a packages.lock.json, a project.assets.json
+// nor a packages.config. The ones dotnet fails to restore are listed from the packages their project files reference
 func (m *nuget) HasModulesInstalled(path string) error {
+	projectPath := m.GetProjectManifestPath(path)
+	projectPaths, err := getProjectPaths(projectPath)
+	if err != nil {
+		return err
+	}
+	// no projects found
+	if len(projectPaths) == 0 {
+		return errDependenciesNotFound
+	}
+
+	var unrestored []string
+	for _, project := range projectPaths {
+		if !isRestored(filepath.Dir(project)) {
+			unrestored = append(unrestored, project)
+		}
+	}
+	if len(unrestored) == 0 {
+		return nil
+	}
+
+	log.Infof("trying to restore the packages: %s", projectPath)
+	if err := m.restore(projectPath); err != nil {
+		log.Warnf("failed to restore %s, only the packages the project files reference are listed: %v", projectPath, err)
+	}
+	return nil
+}
+
+// restore runs dotnet restore on the project, and records the global packages folder of dotnet
+func (m *nuget) restore(projectPath string) error {
 	// TODO: check nuGetFallBackFolderPath cache
 	if err := m.buildCmd(LocalPackageCacheCmd, "."); err != nil {
 		return err
@@ -95,121 +130,179 @@ func (m *nuget) HasModulesInstalled(path string) error {
 		packageCachePaths = append(packageCachePaths, strings.TrimSpace(cachePathArray[1]))
 	}
 
-	projectPath := m.GetProjectManifestPath(path)
-	log.Infof("trying to restore the packages: %s", projectPath)
-
 	restoreCommand := command(fmt.Sprintf("%s %s", RestorePackageCmd, projectPath))
 	if err := m.buildCmd(restoreCommand, "."); err != nil {
 		return err
 	}
 
 	_, err = m.command.Output()
-	if err != nil {
-		return err
-	}
-
-	log.Infof("looking for the project modules using location: %s", projectPath)
+	return err
+}
 
-	projectPaths, err := getProjectPaths(projectPath)
-	if err != nil {
-		return err
-	}
-	// no projects found
-	if len(projectPaths) == 0 {
-		return errDependenciesNotFound
-	}
-	projectArray := []string{}
-	// check asset path exists
-	modulePath := filepath.Join(assetDirectoryJoinPath, assetModuleFile)
-	for _, project := range projectPaths {
-		projectDirectory := filepath.Dir(project)
-		if helper.Exists(filepath.Join(projectDirectory, modulePath)) {
-			// check asset path exists
-			continue
-		} else if helper.Exists(filepath.Join(projectDirectory, configModuleFile)) {
-			// check config path exists
-			continue
-		}
-		projectArray = append(projectArray, project)
-	}
-	if len(projectArray) == 0 {
-		return nil
-	}
-	log.Infof("no modules found for project:%s", projectArray)
-	return errDependenciesNotFound
+// isRestored tells whether the packages of the project of the directory are locked or restored
+func isRestored(dir string) bool {
+	return helper.Exists(filepath.Join(dir, lockModuleFile)) ||
+		helper.Exists(filepath.Join(dir, assetDirectoryJoinPath, assetModuleFile)) ||
+		helper.Exists(filepath.Join(dir, configModuleFile))
 }
 
-// GetVersion...
+// GetVersion returns the dotnet version
 func (m *nuget) GetVersion() (string, error) {
 	if err := m.buildCmd(VersionCmd, "."); err != nil {
 		return "", err
 	}
 
-	return m.command.Output()
+	version, err := m.command.Output()
+	if err != nil {
+		return "", err
+	}
+	return version, nil
 }
 
-// GetRootModule...
+// GetRootModule returns the solution, or the project described by its project file
 func (m *nuget) GetRootModule(path string) (*models.Module, error) {
 	if m.rootModule == nil {
 		module := models.Module{}
 		projectPath := m.GetProjectManifestPath(path)
 		pathExtension := filepath.Ext(projectPath)
-		if helper.Exists(projectPath) {
+		if helper.Exists(projectPath) && pathExtension != ".sln" {
+			project, err := readProject(projectPath)
+			if err != nil {
+				return nil, err
+			}
+			module = projectModule(project, buildRootPackageURL(path))
+		} else if helper.Exists(projectPath) {
 			fileName := filepath.Base(projectPath)
 			rootProjectName := fileName[0 : len(fileName)-len(pathExtension)]
 			module.Name = rootProjectName
-			module.Root = true
 			module.CheckSum = &models.CheckSum{
 				Algorithm: models.HashAlgoSHA256,
 				Content:   []byte(fmt.Sprintf("%s%s", module.Name, module.Version)),
 			}
 			module.Supplier.Name = rootProjectName
 			module.PackageDownloadLocation = buildRootPackageURL(path)
+			module.Modules = map[string]*models.Module{}
 		}
+		module.Root = true
 		m.rootModule = &module
 	}
 	return m.rootModule, nil
 }
 
-// ListModulesWithDeps ...
+// ListModulesWithDeps returns the root module followed by the other projects and the packages restored for
+// them, read from their packages.lock.json, their project.assets.json or their packages.config. A project
+// depends on the packages and the projects it references, the packages whose assets are private are
+// DEV_DEPENDENCY_OF it, and the root CONTAINS the projects it does not reference
 func (m *nuget) ListModulesWithDeps(path string) ([]models.Module, error) {
-	var modules []models.Module
 	projectPath := m.GetProjectManifestPath(path)
 	projectPaths, err := getProjectPaths(projectPath)
 	if err != nil {
-		return modules, err
+		return nil, err
 	}
 	// no projects found
 	if len(projectPaths) == 0 {
-		return modules, errDependenciesNotFound
+		return nil, errDependenciesNotFound
 	}
-	modulePath := filepath.Join(assetDirectoryJoinPath, assetModuleFile)
-	for _, project := range projectPaths {
-		projectDirectory := filepath.Dir(project)
-		if helper.Exists(filepath.Join(projectDirectory, modulePath)) {
-			packages, err := m.parseAssetModules(filepath.Join(projectDirectory, modulePath))
-			if err != nil {
-				return modules, err
-			}
-			modules = append(modules, packages...)
-			log.Infof("dependency tree completed for project(a): %s", project)
-		} else if helper.Exists(filepath.Join(projectDirectory, configModuleFile)) {
-			packages, err := m.parsePackagesConfigModules(filepath.Join(projectDirectory, configModuleFile))
-			if err != nil {
-				return modules, err
+
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &moduleSet{modules: []models.Module{*root}, index: map[string]int{}}
+	projects := make([]*dotnetProject, 0, len(projectPaths))
+	projectIndex := map[string]int{}
+	for _, file := range projectPaths {
+		project, err := readProject(file)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+		if file == projectPath {
+			projectIndex[file] = 0
+			continue
+		}
+		projectIndex[file] = set.add("project:"+file, func() models.Module {
+			return projectModule(project, root.PackageDownloadLocation)
+		})
+	}
+
+	for _, project := range projects {
+		if err := m.addPackages(set, projectIndex[project.Path], project); err != nil {
+			return nil, err
+		}
+		log.Infof("dependency tree completed for project: %s", project.Path)
+	}
+
+	for _, project := range projects {
+		for _, reference := range project.ProjectReferences {
+			if i, ok := projectIndex[reference]; ok {
+				set.link(projectIndex[project.Path], i, "")
 			}
-			log.Infof("dependency tree completed for project(c): %s", project)
-			modules = append(modules, packages...)
 		}
 	}
-	if len(modules) == 0 {
-		return modules, errFailedToConvertModules
+	for _, project := range projects {
+		i := projectIndex[project.Path]
+		if _, linked := set.modules[0].Modules[set.modules[i].Name]; i != 0 && !linked {
+			set.link(0, i, models.RelationshipContains)
+		}
+	}
+	return set.modules, nil
+}
+
+// addPackages adds the packages restored for the project and links the ones it references, the project
+// which is not restored only references the packages of its project file
+func (m *nuget) addPackages(set *moduleSet, projectIndex int, project *dotnetProject) error {
+	dir := filepath.Dir(project.Path)
+	var graph *restoreGraph
+	var err error
+	switch {
+	case helper.Exists(filepath.Join(dir, lockModuleFile)):
+		graph, err = readLockfile(filepath.Join(dir, lockModuleFile))
+	case helper.Exists(filepath.Join(dir, assetDirectoryJoinPath, assetModuleFile)):
+		graph, err = readAssets(filepath.Join(dir, assetDirectoryJoinPath, assetModuleFile))
+	case helper.Exists(filepath.Join(dir, configModuleFile)):
+		packages, err := m.parsePackagesConfigModules(filepath.Join(dir, configModuleFile))
+		if err != nil {
+			return err
+		}
+		for _, pkg := range packages {
+			pkg := pkg
+			set.link(projectIndex, set.add(packageKey(pkg.Name, pkg.Version), func() models.Module { return pkg }), "")
+		}
+		return nil
+	default:
+		graph = newRestoreGraph()
+		for _, id := range sortedKeys(project.References) {
+			ref := project.References[id]
+			graph.addFramework([]frameworkPackage{{ID: ref.ID, Version: exactVersion(ref.Version), Direct: true}})
+		}
+		set.modules[projectIndex].Unresolved = append(set.modules[projectIndex].Unresolved,
+			"the project is not restored, only the packages its project file references are listed")
 	}
-	// set root module
-	if m.rootModule != nil {
-		modules = append(modules, *m.rootModule)
+	if err != nil {
+		return err
 	}
-	return modules, nil
+
+	for _, key := range graph.keys() {
+		pkg := graph.Packages[key]
+		set.add(key, func() models.Module { return m.packageModule(pkg) })
+	}
+	for _, key := range graph.keys() {
+		for _, dependency := range graph.Packages[key].Dependencies {
+			set.link(set.index[key], set.index[dependency], "")
+		}
+	}
+	for _, key := range graph.keys() {
+		if !graph.Direct[key] {
+			continue
+		}
+		relationship := models.RelationshipType("")
+		if project.References[strings.ToLower(graph.Packages[key].ID)].Private {
+			relationship = models.RelationshipDevDependencyOf
+		}
+		set.link(projectIndex, set.index[key], relationship)
+	}
+	return nil
 }
 
 // ListUsedModules ...
@@ -227,6 +320,7 @@ func (m *nuget) buildCmd(cmd command, path string) error {
 		Name:      cmdArgs[0],
 		Args:      cmdArgs[1:],
 		Directory: path,
+		Context:   m.options.Context,
 	})
 
 	m.command = command
@@ -260,7 +354,7 @@ func (m *nuget) parsePackagesConfigModules(modulePath string) ([]models.Module,
 		return modules, err
 	}
 	moduleData := PackageConfig{}
-	err = xml.Unmarshal(raw, &moduleData)
+	err = reader.DecodeXML(modulePath, raw, &moduleData)
 	if err != nil {
 		return modules, err
 	}
@@ -274,65 +368,7 @@ func (m *nuget) parsePackagesConfigModules(modulePath string) ([]models.Module,
 	return modules, nil
 }
 
-// parseAssetModules parses the output -- works for the project.assets.json
-func (m *nuget) parseAssetModules(modulePath string) ([]models.Module, error) {
-	modules := make([]models.Module, 0)
-	raw, err := ioutil.ReadFile(modulePath)
-	if err != nil {
-		return modules, err
-	}
-
-	moduleData := map[string]interface{}{}
-	err = json.Unmarshal(raw, &moduleData)
-	if err != nil {
-		return modules, err
-	}
-	// parse targets from the asset json
-	targetsData := moduleData[assetTargets].(map[string]interface{})
-	packageNameMap := map[string]string{}
-	for _, packageData := range targetsData {
-		data := packageData.(map[string]interface{})
-		for name, info := range data {
-			// split the package name and version
-			packageArray := strings.Split(name, "/")
-			packageInfo := info.(map[string]interface{})
-			// consider only the package type for building the dependencies
-			if packageInfo != nil &&
-				packageInfo[assetType] == assetPackage && len(packageArray) == 2 {
-				packageInfo := info.(map[string]interface{})
-				packageName := packageArray[0]
-				packageVersion := packageArray[1]
-				dependencies := map[string]string{}
-				// get the dependency packages
-				dependencyModules := packageInfo[assetDependencies]
-				if dependencyModules != nil {
-					dependencyPackages := dependencyModules.(map[string]interface{})
-					for dName, dInfo := range dependencyPackages {
-						dVersion, ok := dInfo.(string)
-						if ok {
-							dUniqueName := fmt.Sprintf("%s-%s", dName, dVersion)
-							if _, ok := packageNameMap[dUniqueName]; ok {
-								dependencies[dName] = dVersion
-							}
-						}
-					}
-				}
-				packageUniqueName := fmt.Sprintf("%s-%s", packageName, packageVersion)
-				if _, ok := packageNameMap[packageUniqueName]; !ok {
-					module, err := m.buildModule(packageName, packageVersion, dependencies)
-					if err != nil {
-						return modules, err
-					}
-					modules = append(modules, module)
-				}
-				packageNameMap[packageUniqueName] = packageVersion
-			}
-		}
-	}
-	return modules, nil
-}
-
-// getProjectPaths
+// getProjectPaths returns the C#, F# and Visual Basic projects below the directory of the manifest
 func getProjectPaths(path string) ([]string, error) {
 	var projectPath []string
 	directoryPath := filepath.Dir(path)
@@ -343,10 +379,11 @@ func getProjectPaths(path string) ([]string, error) {
 		if info.IsDir() {
 			return nil
 		}
-		if matched, err := filepath.Match(directoryFilterPattern, filepath.Base(path)); err != nil {
-			return err
-		} else if matched {
-			projectPath = append(projectPath, path)
+		extension := strings.ToLower(filepath.Ext(path))
+		for _, projectExtension := range projectExtensions {
+			if extension == projectExtension {
+				projectPath = append(projectPath, path)
+			}
 		}
 		return nil
 	})
@@ -362,13 +399,13 @@ func (m *nuget) buildModule(name string, version string, dependencies map[string
 	module.Name = name
 	module.Version = version
 	//get the hash checksum
-	checkSum, err := getHashCheckSum(name, version)
+	checkSum, err := m.getHashCheckSum(name, version)
 	if err != nil {
 		return module, err
 	}
 	module.CheckSum = checkSum
 	// get nuget spec file details
-	nuSpecFile, err := getNugetSpec(name, version)
+	nuSpecFile, err := m.getNugetSpec(name, version)
 	if err != nil {
 		return module, err
 	}
@@ -400,7 +437,7 @@ func (m *nuget) buildModule(name string, version string, dependencies map[string
 	// set dependencies
 	dependencyModules := map[string]*models.Module{}
 	for dName, dVersion := range dependencies {
-		checkSum, err := getHashCheckSum(name, version)
+		checkSum, err := m.getHashCheckSum(name, version)
 		if err != nil {
 			return module, err
 		}
@@ -420,8 +457,8 @@ func getCachedSpecFilename(name string, version string) string {
 	if name == "" || version == "" {
 		return specFilename
 	}
-	name = strings.ToLower(name)
-	for _, path := range packageCachePaths {
+	name, version = strings.ToLower(name), strings.ToLower(version)
+	for _, path := range packageFolders() {
 		var directory = filepath.Join(path, name, version)
 		var fileName = filepath.Join(directory, fmt.Sprintf("%s%s", name, specExt))
 		if helper.Exists(fileName) {
@@ -433,7 +470,7 @@ func getCachedSpecFilename(name string, version string) string {
 }
 
 // getNugetSpec ...
-func getNugetSpec(name string, version string) (*NugetSpec, error) {
+func (m *nuget) getNugetSpec(name string, version string) (*NugetSpec, error) {
 	nuSpecFile := NugetSpec{}
 	specFileName := getCachedSpecFilename(name, version)
 	if specFileName != "" {
@@ -447,6 +484,11 @@ func getNugetSpec(name string, version string) (*NugetSpec, error) {
 		}
 		return specFile, nil
 	}
+	if m.options.Offline {
+		// only the packages of the local cache are described offline
+		return nil, nil
+	}
+	name, version = strings.ToLower(name), strings.ToLower(version)
 	nugetUrlPrefix := fmt.Sprintf("%s%s/%s/%s", nugetBaseUrl, name, version, name)
 	nuspecUrl := fmt.Sprintf("%s%s", nugetUrlPrefix, specExt)
 	resp, err := getHttpResponseWithHeaders(nuspecUrl, map[string]string{"content-type": "application/xml"})
@@ -473,7 +515,7 @@ func getNugetSpec(name string, version string) (*NugetSpec, error) {
 }
 
 // getHashCheckSum ...
-func getHashCheckSum(name string, version string) (*models.CheckSum, error) {
+func (m *nuget) getHashCheckSum(name string, version string) (*models.CheckSum, error) {
 	var fileData []byte
 	specFileName := getCachedSpecFilename(name, version)
 	if specFileName != "" {
@@ -504,6 +546,9 @@ func getHashCheckSum(name string, version string) (*models.CheckSum, error) {
 			Content:   fileData,
 		}, nil
 	}
+	if m.options.Offline {
+		return nil, nil
+	}
 	nugetUrlPrefix := fmt.Sprintf("%s%s/%s/%s", nugetBaseUrl, name, version, name)
 	nuPkgUrl := fmt.Sprintf("%s.%s%s", nugetUrlPrefix, version, pkgExt)
 	resp, err := getHttpResponseWithHeaders(nuPkgUrl, map[string]string{"content-type": "application/xml"})
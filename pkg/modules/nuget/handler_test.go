@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nuget
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestParseTruncatedAssetModules(t *testing.T) {
+	_, err := readAssets(filepath.Join("testdata", "project.assets.json"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file testdata/project.assets.json: line 8, column 1: unexpected end of JSON input")
+}
+
+func TestParseCorruptAssetModules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.assets.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"version": 3, "targets": {"net6.0": []}}`), 0644))
+
+	_, err := readAssets(path)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file "+path+": targets.net6.0 is not an object")
+}
+
+func TestParseTruncatedPackagesConfigModules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages.config")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<packages>\n  <package id=\"Newtonsoft.Json\" version=\"13.0.1\""), 0644))
+
+	_, err := New().parsePackagesConfigModules(path)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file "+path+": line 3: unexpected EOF")
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	packages, err := filepath.Abs(filepath.Join("testdata", "packages"))
+	assert.NoError(t, err)
+	os.Setenv("NUGET_PACKAGES", packages)
+	defer os.Unsetenv("NUGET_PACKAGES")
+
+	path := filepath.Join("testdata", "solution")
+	plugin := New()
+	plugin.SetOptions(models.PluginOptions{Offline: true})
+	modules, err := plugin.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+
+	byName := map[string]models.Module{}
+	for _, module := range modules {
+		byName[module.Name] = module
+	}
+	assert.Len(t, modules, 12)
+	assert.Equal(t, "Shop", modules[0].Name)
+	assert.True(t, modules[0].Root)
+	for _, project := range []string{"Shop.Web", "Shop.Core", "Shop.Tests"} {
+		assert.Equal(t, models.RelationshipContains, modules[0].Modules[project].Relationship)
+	}
+
+	web := byName["Shop.Web"]
+	assert.Equal(t, "1.4.0", web.Version)
+	assert.Equal(t, "Apache-2.0", web.LicenseDeclared)
+	assert.Len(t, web.Modules, 4)
+	assert.Equal(t, models.RelationshipType(""), web.Modules["Newtonsoft.Json"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, web.Modules["Microsoft.SourceLink.GitHub"].Relationship)
+	assert.Contains(t, web.Modules, "Shop.Core")
+	assert.Len(t, byName["Microsoft.SourceLink.GitHub"].Modules, 2)
+	assert.Contains(t, byName["Shop.Core"].Modules, "FSharp.Core")
+
+	json := byName["Newtonsoft.Json"]
+	assert.Equal(t, "pkg:nuget/Newtonsoft.Json@13.0.3", json.PackageURL)
+	assert.Equal(t, "https://api.nuget.org/v3-flatcontainer/newtonsoft.json/13.0.3/newtonsoft.json.13.0.3.nupkg", json.PackageDownloadLocation)
+	assert.Equal(t, models.HashAlgoSHA512, json.CheckSum.Algorithm)
+	assert.Equal(t, "80d4f0416c1403769d7de95b5fca6507ae2060d0a75d3d6e28bb3324ce62d1f14433126b21399ad09e8e37f6c326e8b8b5ef3f50814d816360e53d4d93827241", json.CheckSum.Value)
+	assert.Equal(t, "MIT", json.LicenseDeclared)
+	assert.Equal(t, "https://www.newtonsoft.com/json", json.PackageHomePage)
+	assert.Equal(t, "James Newton-King", json.Supplier.Name)
+
+	tests := byName["Shop.Tests"]
+	assert.Equal(t, []string{"the project is not restored, only the packages its project file references are listed"}, tests.Unresolved)
+	assert.Equal(t, "2.4.2", tests.Modules["xunit"].Version)
+	assert.Equal(t, "3.2.0", tests.Modules["coverlet.collector"].Version)
+	assert.Equal(t, models.RelationshipDevDependencyOf, tests.Modules["coverlet.collector"].Relationship)
+	assert.Nil(t, tests.Modules["xunit"].CheckSum)
+	assert.Contains(t, tests.Modules, "Shop.Web")
+}
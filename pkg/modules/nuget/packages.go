@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nuget
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// moduleSet is the modules of the projects and of their packages, the packages restored for
+// several projects are listed once
+type moduleSet struct {
+	modules []models.Module
+	// index are the indexes of the package modules keyed by packageKey
+	index map[string]int
+}
+
+// add appends the module of the key unless it is listed already and returns its index
+func (s *moduleSet) add(key string, build func() models.Module) int {
+	if i, ok := s.index[key]; ok {
+		return i
+	}
+	s.index[key] = len(s.modules)
+	s.modules = append(s.modules, build())
+	return s.index[key]
+}
+
+// link copies the module into the modules of its parent
+func (s *moduleSet) link(parentIndex int, index int, relationship models.RelationshipType) {
+	if parentIndex == index {
+		return
+	}
+
+	linked := s.modules[index]
+	linked.Relationship = relationship
+	s.modules[parentIndex].Modules[linked.Name] = &linked
+}
+
+// projectModule returns the module of a project, described by its project file
+func projectModule(p *dotnetProject, downloadLocation string) models.Module {
+	mod := models.Module{
+		Name:      p.Name,
+		Version:   p.Version,
+		LocalPath: filepath.Dir(p.Path),
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(fmt.Sprintf("%s%s", p.Name, p.Version)),
+		},
+		Supplier:                models.SupplierContact{Name: nonEmpty(p.Authors, p.Name)},
+		PackageHomePage:         p.HomePage,
+		PackageDownloadLocation: nonEmpty(p.Repository, downloadLocation),
+		Modules:                 map[string]*models.Module{},
+	}
+	if license := helper.SPDXExpression(p.License); license != "" {
+		mod.LicenseDeclared = license
+		mod.LicenseConcluded = license
+	}
+	return mod
+}
+
+// packageModule returns the module of a restored package, described by its .nuspec
+func (m *nuget) packageModule(pkg *restoredPackage) models.Module {
+	mod := models.Module{
+		Name:                    pkg.ID,
+		Version:                 pkg.Version,
+		PackageURL:              purl.New("nuget", "", pkg.ID, pkg.Version).String(),
+		PackageDownloadLocation: packageDownloadURL(pkg.ID, pkg.Version),
+		Supplier:                models.SupplierContact{Name: pkg.ID},
+		Modules:                 map[string]*models.Module{},
+	}
+
+	sha512 := pkg.SHA512
+	if sha512 == "" {
+		sha512 = cachedSHA512(pkg.ID, pkg.Version)
+	}
+	if checksum, err := base64.StdEncoding.DecodeString(sha512); err == nil && len(checksum) == 64 {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: hex.EncodeToString(checksum)}
+	} else {
+		mod.Unresolved = append(mod.Unresolved, "the package is restored without a content hash, its checksum is unknown")
+	}
+
+	// the .nuspec only enriches the package, it is listed without it
+	spec, err := m.getNugetSpec(pkg.ID, pkg.Version)
+	if err != nil {
+		log.Debugf("failed to read the nuspec of %s %s: %v", pkg.ID, pkg.Version, err)
+		return mod
+	}
+	if spec != nil {
+		setSpecMetadata(&mod, spec)
+	}
+	return mod
+}
+
+// setSpecMetadata sets the license, the copyright, the supplier and the home page of the .nuspec
+func setSpecMetadata(mod *models.Module, spec *NugetSpec) {
+	license := helper.SPDXExpression(spec.Meta.License)
+	if license == "" {
+		license = extractLicence(spec.Meta.License)
+	}
+	mod.LicenseDeclared = license
+	mod.LicenseConcluded = license
+	mod.Copyright = spec.Meta.Copyright
+	mod.PackageHomePage = spec.Meta.ProjectURL
+	if supplier := nonEmpty(spec.Meta.Authors, spec.Meta.Owners); supplier != "" {
+		mod.Supplier.Name = supplier
+	}
+}
+
+// packageDownloadURL returns the url of the .nupkg on nuget.org, whose paths are lowercased
+func packageDownloadURL(id, version string) string {
+	id, version = strings.ToLower(id), strings.ToLower(version)
+	return fmt.Sprintf("%s%s/%s/%s.%s%s", nugetBaseUrl, id, version, id, version, pkgExt)
+}
+
+// packageFolders returns the local package folders: the global packages folder dotnet reported, the
+// NUGET_PACKAGES one and ~/.nuget/packages
+func packageFolders() []string {
+	folders := append([]string{}, packageCachePaths...)
+	if folder := os.Getenv("NUGET_PACKAGES"); folder != "" {
+		folders = append(folders, folder)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		folders = append(folders, filepath.Join(home, ".nuget", "packages"))
+	}
+	return folders
+}
+
+// cachedSHA512 returns the base64 sha512 of the .nupkg.sha512 file the restore wrote in the local package folder
+func cachedSHA512(id, version string) string {
+	id, version = strings.ToLower(id), strings.ToLower(version)
+	for _, folder := range packageFolders() {
+		raw, err := ioutil.ReadFile(filepath.Join(folder, id, version, fmt.Sprintf("%s.%s%s", id, version, sha512Ext)))
+		if err == nil {
+			return strings.TrimSpace(string(raw))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nuget
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// centralPackagesFile holds the versions of the packages of the projects below it with central package management
+const centralPackagesFile = "Directory.Packages.props"
+
+// msbuildProject is the subset of a .csproj, .fsproj or .vbproj, or of a Directory.Packages.props
+type msbuildProject struct {
+	PropertyGroups []msbuildProperties `xml:"PropertyGroup"`
+	ItemGroups     []msbuildItems      `xml:"ItemGroup"`
+}
+
+// msbuildProperties are the properties describing the package the project builds
+type msbuildProperties struct {
+	AssemblyName             string `xml:"AssemblyName"`
+	PackageID                string `xml:"PackageId"`
+	Version                  string `xml:"Version"`
+	Authors                  string `xml:"Authors"`
+	PackageLicenseExpression string `xml:"PackageLicenseExpression"`
+	PackageProjectURL        string `xml:"PackageProjectUrl"`
+	RepositoryURL            string `xml:"RepositoryUrl"`
+}
+
+type msbuildItems struct {
+	PackageReferences []packageReference `xml:"PackageReference"`
+	// PackageVersions are the central versions of a Directory.Packages.props
+	PackageVersions   []packageReference `xml:"PackageVersion"`
+	ProjectReferences []struct {
+		Include string `xml:"Include,attr"`
+	} `xml:"ProjectReference"`
+}
+
+// packageReference is a PackageReference or a PackageVersion item, its metadata are either attributes or elements
+type packageReference struct {
+	Include              string `xml:"Include,attr"`
+	Update               string `xml:"Update,attr"`
+	Version              string `xml:"Version,attr"`
+	VersionElement       string `xml:"Version"`
+	VersionOverride      string `xml:"VersionOverride,attr"`
+	PrivateAssets        string `xml:"PrivateAssets,attr"`
+	PrivateAssetsElement string `xml:"PrivateAssets"`
+}
+
+// reference is a package a project references
+type reference struct {
+	ID string
+	// Version is the version, or the version range, the project requests
+	Version string
+	// Private is set for the references whose assets do not flow to the consumers of the project, e.g.
+	// analyzers and build tools
+	Private bool
+}
+
+// dotnetProject is a project file and the packages and projects it references
+type dotnetProject struct {
+	Path       string
+	Name       string
+	Version    string
+	Authors    string
+	License    string
+	HomePage   string
+	Repository string
+	// References are the packages the project references keyed by lowercased id
+	References map[string]reference
+	// ProjectReferences are the paths of the project files it references
+	ProjectReferences []string
+}
+
+// readProject reads the package and the references of a project file, the versions of the references are
+// the central ones of the closest Directory.Packages.props unless the project overrides them
+func readProject(path string) (*dotnetProject, error) {
+	project, err := readMSBuildFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+	p := &dotnetProject{
+		Path:       path,
+		Name:       strings.TrimSuffix(name, filepath.Ext(name)),
+		References: map[string]reference{},
+	}
+	for _, group := range project.PropertyGroups {
+		p.Name = nonEmpty(group.PackageID, nonEmpty(group.AssemblyName, p.Name))
+		p.Version = nonEmpty(group.Version, p.Version)
+		p.Authors = nonEmpty(group.Authors, p.Authors)
+		p.License = nonEmpty(group.PackageLicenseExpression, p.License)
+		p.HomePage = nonEmpty(group.PackageProjectURL, p.HomePage)
+		p.Repository = nonEmpty(group.RepositoryURL, p.Repository)
+	}
+
+	central, err := centralVersions(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range project.ItemGroups {
+		for _, item := range group.PackageReferences {
+			id := strings.TrimSpace(item.Include)
+			if id == "" {
+				continue
+			}
+			version := nonEmpty(item.VersionOverride, nonEmpty(item.Version, strings.TrimSpace(item.VersionElement)))
+			if version == "" {
+				version = central[strings.ToLower(id)]
+			}
+			private := strings.EqualFold(nonEmpty(item.PrivateAssets, strings.TrimSpace(item.PrivateAssetsElement)), "all")
+			p.References[strings.ToLower(id)] = reference{ID: id, Version: version, Private: private}
+		}
+		for _, item := range group.ProjectReferences {
+			if item.Include == "" {
+				continue
+			}
+			// the project files use windows separators
+			include := filepath.FromSlash(strings.ReplaceAll(item.Include, `\`, "/"))
+			p.ProjectReferences = append(p.ProjectReferences, filepath.Clean(filepath.Join(filepath.Dir(path), include)))
+		}
+	}
+	return p, nil
+}
+
+// centralVersions returns the package versions of the closest Directory.Packages.props above dir, keyed by
+// lowercased id
+func centralVersions(dir string) (map[string]string, error) {
+	versions := map[string]string{}
+	for {
+		path := filepath.Join(dir, centralPackagesFile)
+		project, err := readMSBuildFile(path)
+		if err == nil {
+			for _, group := range project.ItemGroups {
+				for _, item := range group.PackageVersions {
+					id := nonEmpty(item.Include, item.Update)
+					versions[strings.ToLower(id)] = nonEmpty(item.Version, strings.TrimSpace(item.VersionElement))
+				}
+			}
+			return versions, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return versions, nil
+		}
+		dir = parent
+	}
+}
+
+func readMSBuildFile(path string) (*msbuildProject, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	project := &msbuildProject{}
+	if err := reader.DecodeXML(path, raw, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// exactVersion returns the version of a requested version when it is exact, e.g. 13.0.1 or [13.0.1],
+// "" for the ranges and the floating versions
+func exactVersion(requested string) string {
+	requested = strings.TrimSpace(requested)
+	if strings.HasPrefix(requested, "[") && strings.HasSuffix(requested, "]") && !strings.Contains(requested, ",") {
+		return strings.TrimSpace(requested[1 : len(requested)-1])
+	}
+	if requested == "" || strings.ContainsAny(requested, "[]()*, ") {
+		return ""
+	}
+	return requested
+}
+
+func nonEmpty(value, defaultValue string) string {
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nuget
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProject(t *testing.T) {
+	project, err := readProject(filepath.Join("testdata", "solution", "src", "Shop.Web", "Shop.Web.csproj"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Shop.Web", project.Name)
+	assert.Equal(t, "1.4.0", project.Version)
+	assert.Equal(t, "Acme", project.Authors)
+	assert.Equal(t, "Apache-2.0", project.License)
+	assert.Equal(t, map[string]reference{
+		"newtonsoft.json":             {ID: "Newtonsoft.Json", Version: "13.0.3"},
+		"serilog":                     {ID: "Serilog", Version: "2.12.0"},
+		"microsoft.sourcelink.github": {ID: "Microsoft.SourceLink.GitHub", Version: "1.1.1", Private: true},
+	}, project.References)
+	assert.Equal(t, []string{filepath.Join("testdata", "solution", "src", "Shop.Core", "Shop.Core.fsproj")}, project.ProjectReferences)
+}
+
+func TestReadProjectPrivateAttribute(t *testing.T) {
+	project, err := readProject(filepath.Join("testdata", "solution", "tests", "Shop.Tests", "Shop.Tests.csproj"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, reference{ID: "coverlet.collector", Version: "[3.2.0]", Private: true}, project.References["coverlet.collector"])
+	assert.Equal(t, reference{ID: "xunit", Version: "2.4.2"}, project.References["xunit"])
+	assert.Equal(t, []string{filepath.Join("testdata", "solution", "src", "Shop.Web", "Shop.Web.csproj")}, project.ProjectReferences)
+}
+
+func TestExactVersion(t *testing.T) {
+	assert.Equal(t, "13.0.1", exactVersion("13.0.1"))
+	assert.Equal(t, "3.2.0", exactVersion("[3.2.0]"))
+	assert.Equal(t, "", exactVersion("[1.0, 2.0)"))
+	assert.Equal(t, "", exactVersion("6.*"))
+	assert.Equal(t, "", exactVersion(""))
+}
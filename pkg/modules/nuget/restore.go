@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nuget
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the packages.lock.json versions, 2 adds the CentralTransitive packages of central package management
+const maxLockfileVersion = 2
+
+// the types of the packages.lock.json dependencies
+const (
+	lockDirect  = "Direct"
+	lockProject = "Project"
+)
+
+// restoredPackage is a package restored for a project
+type restoredPackage struct {
+	ID      string
+	Version string
+	// SHA512 is the base64 sha512 of the .nupkg, the contentHash of packages.lock.json or the sha512 of project.assets.json
+	SHA512 string
+	// Dependencies are the keys of the packages it depends on
+	Dependencies []string
+}
+
+// restoreGraph is the packages restored for a project, for all its target frameworks and runtimes
+type restoreGraph struct {
+	// Packages are keyed by packageKey
+	Packages map[string]*restoredPackage
+	// Direct are the keys of the packages the project references
+	Direct map[string]bool
+}
+
+// frameworkPackage is a package restored for a target framework, its dependencies are package ids
+// resolved to the versions restored for the same framework
+type frameworkPackage struct {
+	ID           string
+	Version      string
+	SHA512       string
+	Direct       bool
+	Dependencies []string
+}
+
+// packagesLock is a packages.lock.json
+type packagesLock struct {
+	Version int `json:"version"`
+	// Dependencies are the packages keyed by target framework, e.g. net6.0 or net6.0/linux-x64, then by id
+	Dependencies map[string]map[string]lockDependency `json:"dependencies"`
+}
+
+type lockDependency struct {
+	Type         string            `json:"type"`
+	Requested    string            `json:"requested"`
+	Resolved     string            `json:"resolved"`
+	ContentHash  string            `json:"contentHash"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// assetsTarget is a package or a project of the targets of a project.assets.json
+type assetsTarget struct {
+	Type         string            `json:"type"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// assetsLibrary is a package or a project of the libraries of a project.assets.json
+type assetsLibrary struct {
+	Type   string `json:"type"`
+	SHA512 string `json:"sha512"`
+	Path   string `json:"path"`
+}
+
+// assetsProject is the restore of the project of a project.assets.json
+type assetsProject struct {
+	Frameworks map[string]struct {
+		// Dependencies are the packages the project references for the framework
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	} `json:"frameworks"`
+}
+
+func newRestoreGraph() *restoreGraph {
+	return &restoreGraph{Packages: map[string]*restoredPackage{}, Direct: map[string]bool{}}
+}
+
+// packageKey is the key of a package version, the ids and the versions are case insensitive
+func packageKey(id, version string) string {
+	return strings.ToLower(id) + "/" + strings.ToLower(version)
+}
+
+// addFramework adds the packages restored for a target framework
+func (g *restoreGraph) addFramework(packages []frameworkPackage) {
+	versions := map[string]string{}
+	for _, pkg := range packages {
+		versions[strings.ToLower(pkg.ID)] = packageKey(pkg.ID, pkg.Version)
+	}
+
+	for _, pkg := range packages {
+		key := packageKey(pkg.ID, pkg.Version)
+		restored, ok := g.Packages[key]
+		if !ok {
+			restored = &restoredPackage{ID: pkg.ID, Version: pkg.Version}
+			g.Packages[key] = restored
+		}
+		if restored.SHA512 == "" {
+			restored.SHA512 = pkg.SHA512
+		}
+		if pkg.Direct {
+			g.Direct[key] = true
+		}
+		for _, id := range pkg.Dependencies {
+			if dependency, ok := versions[strings.ToLower(id)]; ok {
+				restored.addDependency(dependency)
+			}
+		}
+	}
+}
+
+func (p *restoredPackage) addDependency(key string) {
+	for _, existing := range p.Dependencies {
+		if existing == key {
+			return
+		}
+	}
+	p.Dependencies = append(p.Dependencies, key)
+	sort.Strings(p.Dependencies)
+}
+
+// keys returns the keys of the packages, sorted
+func (g *restoreGraph) keys() []string {
+	keys := make([]string, 0, len(g.Packages))
+	for key := range g.Packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readLockfile reads the packages of a packages.lock.json, the project references are left out
+func readLockfile(path string) (*restoreGraph, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lock packagesLock
+	if err := reader.DecodeJSON(path, raw, &lock); err != nil {
+		return nil, err
+	}
+	if lock.Version < 1 || lock.Version > maxLockfileVersion {
+		return nil, fmt.Errorf("%w: %d in %s", errUnsupportedLockfileVersion, lock.Version, path)
+	}
+
+	g := newRestoreGraph()
+	for _, framework := range sortedKeys(lock.Dependencies) {
+		var packages []frameworkPackage
+		for _, id := range sortedKeys(lock.Dependencies[framework]) {
+			dependency := lock.Dependencies[framework][id]
+			if dependency.Type == lockProject || dependency.Resolved == "" {
+				continue
+			}
+			packages = append(packages, frameworkPackage{
+				ID:           id,
+				Version:      dependency.Resolved,
+				SHA512:       dependency.ContentHash,
+				Direct:       dependency.Type == lockDirect,
+				Dependencies: sortedKeys(dependency.Dependencies),
+			})
+		}
+		g.addFramework(packages)
+	}
+	return g, nil
+}
+
+// readAssets reads the packages of a project.assets.json, the project references are left out
+func readAssets(path string) (*restoreGraph, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var assets struct {
+		Targets   map[string]json.RawMessage `json:"targets"`
+		Libraries map[string]assetsLibrary   `json:"libraries"`
+		Project   assetsProject              `json:"project"`
+	}
+	if err := reader.DecodeJSON(path, raw, &assets); err != nil {
+		return nil, err
+	}
+	if assets.Targets == nil {
+		return nil, fmt.Errorf("%w %s: %s is missing or not an object", reader.ErrMalformedFile, path, assetTargets)
+	}
+
+	// the frameworks of the project are named by their alias, e.g. netcoreapp3.1, and the targets by their
+	// full name, e.g. .NETCoreApp,Version=v3.1, so the references of all of them are direct
+	direct := map[string]bool{}
+	for _, framework := range assets.Project.Frameworks {
+		for id := range framework.Dependencies {
+			direct[strings.ToLower(id)] = true
+		}
+	}
+
+	g := newRestoreGraph()
+	for _, target := range sortedKeys(assets.Targets) {
+		var entries map[string]assetsTarget
+		if err := json.Unmarshal(assets.Targets[target], &entries); err != nil {
+			return nil, fmt.Errorf("%w %s: %s.%s is not an object", reader.ErrMalformedFile, path, assetTargets, target)
+		}
+
+		var packages []frameworkPackage
+		for _, name := range sortedKeys(entries) {
+			entry := entries[name]
+			parts := strings.SplitN(name, "/", 2)
+			if entry.Type != assetPackage || len(parts) != 2 {
+				continue
+			}
+			packages = append(packages, frameworkPackage{
+				ID:           parts[0],
+				Version:      parts[1],
+				SHA512:       assets.Libraries[name].SHA512,
+				Direct:       direct[strings.ToLower(parts[0])],
+				Dependencies: sortedKeys(entry.Dependencies),
+			})
+		}
+		g.addFramework(packages)
+	}
+	return g, nil
+}
+
+// sortedKeys returns the keys of a map of strings, sorted
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	sorted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, key.String())
+	}
+	sort.Strings(sorted)
+	return sorted
+}
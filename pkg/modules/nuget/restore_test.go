@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nuget
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadLockfile(t *testing.T) {
+	graph, err := readLockfile(filepath.Join("testdata", "solution", "src", "Shop.Web", lockModuleFile))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"fsharp.core/7.0.400",
+		"microsoft.build.tasks.git/1.1.1",
+		"microsoft.sourcelink.common/1.1.1",
+		"microsoft.sourcelink.github/1.1.1",
+		"newtonsoft.json/13.0.3",
+		"serilog/2.12.0",
+	}, graph.keys())
+	assert.Equal(t, map[string]bool{
+		"microsoft.sourcelink.github/1.1.1": true,
+		"newtonsoft.json/13.0.3":            true,
+		"serilog/2.12.0":                    true,
+	}, graph.Direct)
+
+	github := graph.Packages["microsoft.sourcelink.github/1.1.1"]
+	assert.Equal(t, "Microsoft.SourceLink.GitHub", github.ID)
+	assert.Equal(t, []string{"microsoft.build.tasks.git/1.1.1", "microsoft.sourcelink.common/1.1.1"}, github.Dependencies)
+	assert.Equal(t, "gNTwQWwUA3adfelbX8plB64gYNCnXT1uKLszJM5i0fFEMxJrITma0J6ON/bDJui4te8/UIFNgWNg5T1Nk4JyQQ==", graph.Packages["newtonsoft.json/13.0.3"].SHA512)
+}
+
+func TestReadLockfileUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), lockModuleFile)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"version": 3, "dependencies": {}}`), 0644))
+
+	_, err := readLockfile(path)
+	assert.True(t, errors.Is(err, errUnsupportedLockfileVersion))
+}
+
+func TestReadLockfileFrameworks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), lockModuleFile)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"version": 1, "dependencies": {
+		"net48": {
+			"System.Text.Json": {"type": "Direct", "resolved": "6.0.0", "dependencies": {"System.Memory": "4.5.4"}},
+			"System.Memory": {"type": "Transitive", "resolved": "4.5.4"}
+		},
+		"net6.0": {
+			"System.Text.Json": {"type": "Direct", "resolved": "7.0.0"}
+		}
+	}}`), 0644))
+
+	graph, err := readLockfile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"system.memory/4.5.4", "system.text.json/6.0.0", "system.text.json/7.0.0"}, graph.keys())
+	assert.Equal(t, []string{"system.memory/4.5.4"}, graph.Packages["system.text.json/6.0.0"].Dependencies)
+	assert.Empty(t, graph.Packages["system.text.json/7.0.0"].Dependencies)
+	assert.Len(t, graph.Direct, 2)
+}
+
+func TestReadAssets(t *testing.T) {
+	graph, err := readAssets(filepath.Join("testdata", "solution", "src", "Shop.Core", assetDirectoryJoinPath, assetModuleFile))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"fsharp.core/7.0.400"}, graph.keys())
+	assert.True(t, graph.Direct["fsharp.core/7.0.400"])
+	assert.Equal(t, "85TdrqwTnW2qDMlbvdaNFcG0BcO9qdp39rXm7PnE4vD7yGSCRjJCBv80EAsCP05iVzXhsBkwQMaVtpgw/4RhDQ==", graph.Packages["fsharp.core/7.0.400"].SHA512)
+}
@@ -10,7 +10,8 @@ import (
 )
 
 type pip struct {
-	plugin models.IPlugin
+	plugin  models.IPlugin
+	options models.PluginOptions
 }
 
 // New ...
@@ -25,21 +26,24 @@ func (m *pip) GetMetadata() models.PluginMetadata {
 	return m.plugin.GetMetadata()
 }
 
-// Is Valid ...
-func (m *pip) IsValid(path string) bool {
-	if p := pipenv.New(); p.IsValid(path) {
-		m.plugin = p
-		return true
-	}
-
-	if p := poetry.New(); p.IsValid(path) {
-		m.plugin = p
-		return true
+// Plugins returns the python plugins with the options of the group, in the order they are tried
+func (m *pip) Plugins() []models.IPlugin {
+	plugins := []models.IPlugin{pipenv.New(), poetry.New(), pyenv.New()}
+	for _, p := range plugins {
+		if configurable, ok := p.(models.IConfigurablePlugin); ok {
+			configurable.SetOptions(m.options)
+		}
 	}
+	return plugins
+}
 
-	if p := pyenv.New(); p.IsValid(path) {
-		m.plugin = p
-		return true
+// Is Valid ...
+func (m *pip) IsValid(path string) bool {
+	for _, p := range m.Plugins() {
+		if p.IsValid(path) {
+			m.plugin = p
+			return true
+		}
 	}
 
 	return false
@@ -50,6 +54,11 @@ func (m *pip) HasModulesInstalled(path string) error {
 	return m.plugin.HasModulesInstalled(path)
 }
 
+// SetOptions keeps the options of the generation for the plugins of the group
+func (m *pip) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // Get Version ...
 func (m *pip) GetVersion() (string, error) {
 	return m.plugin.GetVersion()
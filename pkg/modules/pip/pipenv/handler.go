@@ -4,36 +4,28 @@ package pipenv
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
 )
 
 const cmdName = "pipenv"
 const manifestFile = "Pipfile"
 const manifestLockFile = "Pipfile.lock"
-const placeholderPkgName = "{PACKAGE}"
-const packageSrcLocation = "/src/"
-const packageSiteLocation = "/site-packages"
 
-var errDependenciesNotFound = errors.New("Unable to generate SPDX file: no modules or vendors found. Please install them before running spdx-sbom-generator, e.g.: `pipenv install` or `pipenv update`")
-var errBuildlingModuleDependencies = errors.New("Error building module dependencies")
-var errNoPipCommand = errors.New("Cannot find the pipenv command")
-var errVersionNotFound = errors.New("Python version not found")
-var errFailedToConvertModules = errors.New("Failed to convert modules")
+// pypiURL is the default index of pipenv
+const pypiURL = "https://pypi.org"
+
+var errDependenciesNotFound = errors.New("Unable to generate SPDX file: no Pipfile.lock found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `pipenv lock`")
 
 type pipenv struct {
-	metadata   models.PluginMetadata
-	rootModule *models.Module
-	command    *helper.Cmd
-	basepath   string
-	version    string
-	pkgs       []worker.Packages
-	metainfo   map[string]worker.Metadata
-	allModules []models.Module
+	metadata models.PluginMetadata
+	options  models.PluginOptions
 }
 
 // New ...
@@ -42,7 +34,7 @@ func New() *pipenv {
 		metadata: models.PluginMetadata{
 			Name:       "The Python Package Index (PyPI)",
 			Slug:       "pipenv",
-			Manifest:   []string{manifestLockFile},
+			Manifest:   []string{manifestLockFile, manifestFile},
 			ModulePath: []string{},
 		},
 	}
@@ -53,159 +45,132 @@ func (m *pipenv) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
-// Is Valid ...
+// IsValid checks if the Pipfile.lock exists
 func (m *pipenv) IsValid(path string) bool {
-	for i := range m.metadata.Manifest {
-		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
-			return true
-		}
-	}
-	return false
+	return helper.Exists(filepath.Join(path, manifestLockFile))
 }
 
-// Has Modules Installed ...
+// HasModulesInstalled checks the dependencies are locked, the lockfile is enough to list them
 func (m *pipenv) HasModulesInstalled(path string) error {
-	if err := m.buildCmd(ModulesCmd, m.basepath); err != nil {
-		return err
-	}
-	result, err := m.command.Output()
-	if err == nil && len(result) > 0 && worker.IsRequirementMeet(result) {
+	if helper.Exists(filepath.Join(path, manifestLockFile)) {
 		return nil
 	}
 	return errDependenciesNotFound
 }
 
-// Get Version ...
+// SetOptions ...
+func (m *pipenv) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the pipenv version
 func (m *pipenv) GetVersion() (string, error) {
-	if err := m.buildCmd(VersionCmd, m.basepath); err != nil {
-		return "", err
-	}
-	version, err := m.command.Output()
-	m.version = worker.GetShortPythonVersion(version)
+	output, err := helper.CommandContext(m.options.Context, cmdName, "--version").Output()
 	if err != nil {
-		return "Python", errVersionNotFound
+		return "", err
 	}
-	return version, err
+	return strings.TrimSpace(string(output)), nil
 }
 
 // Set Root Module ...
 func (m *pipenv) SetRootModule(path string) error {
-	m.basepath = path
 	return nil
 }
 
-// Get Root Module ...
+// GetRootModule returns the project directory as the root package, a Pipfile does not name it
 func (m *pipenv) GetRootModule(path string) (*models.Module, error) {
-	if m.rootModule == nil {
-		module := m.fetchRootModule()
-		m.rootModule = &module
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
 	}
-	return m.rootModule, nil
-}
 
-// List Used Modules...
-func (m *pipenv) ListUsedModules(path string) ([]models.Module, error) {
-	if err := m.LoadModuleList(path); err != nil {
-		return m.allModules, errFailedToConvertModules
+	name := filepath.Base(absPath)
+	mod := &models.Module{
+		Name:                    name,
+		Root:                    true,
+		LocalPath:               absPath,
+		PackageDownloadLocation: "NONE",
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(name),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	if license, err := helper.GetLicenses(m.options.LicenseMatcher, absPath); err == nil {
+		mod.LicenseDeclared = helper.BuildLicenseDeclared(license.ID)
+		mod.LicenseConcluded = helper.BuildLicenseConcluded(license.ID)
+		mod.CommentsLicense = license.Comments
+		if !helper.LicenseSPDXExists(license.ID) {
+			mod.OtherLicense = append(mod.OtherLicense, license)
+		}
 	}
+	return mod, nil
+}
 
-	decoder := worker.NewMetadataDecoder(m.GetPackageDetails)
-	metainfo, err := decoder.ConvertMetadataToModules(m.pkgs, &m.allModules)
+// ListUsedModules returns the packages of the lockfile, without the project itself
+func (m *pipenv) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
 	if err != nil {
-		return m.allModules, err
+		return nil, err
 	}
-
-	m.metainfo = metainfo
-	return m.allModules, nil
+	return modules[1:], nil
 }
 
-// List Modules With Deps ...
+// ListModulesWithDeps returns the project followed by the packages of the default and develop sections of
+// the lockfile. The lockfile does not tell which package depends on which, so the project depends on all
+// the default packages and the develop ones are DEV_DEPENDENCY_OF it
 func (m *pipenv) ListModulesWithDeps(path string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+	root, err := m.GetRootModule(path)
 	if err != nil {
 		return nil, err
 	}
-	m.GetRootModule(path)
-	if err := worker.BuildDependencyGraph(&m.allModules, &m.metainfo); err != nil {
-		return nil, err
-	}
-	return modules, err
-}
-
-func (m *pipenv) buildCmd(cmd command, path string) error {
-	cmdArgs := cmd.Parse()
-	if cmdArgs[0] != cmdName {
-		return errNoPipCommand
-	}
-
-	command := helper.NewCmd(helper.CmdOptions{
-		Name:      cmdArgs[0],
-		Args:      cmdArgs[1:],
-		Directory: path,
-	})
-
-	m.command = command
-
-	return command.Build()
-}
-
-func (m *pipenv) GetPackageDetails(packageNameList string) (string, error) {
-	metatdataCmd := command(strings.ReplaceAll(string(MetadataCmd), placeholderPkgName, packageNameList))
-
-	m.buildCmd(metatdataCmd, m.basepath)
-	result, err := m.command.Output()
+	lock, err := readLockfile(filepath.Join(root.LocalPath, manifestLockFile))
 	if err != nil {
-		return "", err
-	}
-
-	return result, nil
-}
-
-func (m *pipenv) PushRootModuleToVenv() (bool, error) {
-	if err := m.buildCmd(InstallRootModuleCmd, m.basepath); err != nil {
-		return false, err
-	}
-	result, err := m.command.Output()
-	if err == nil && len(result) > 0 {
-		return true, err
+		return nil, err
 	}
-	return false, nil
-}
 
-func (m *pipenv) markRootModue() {
-	for i, pkg := range m.pkgs {
-		if worker.IsRootModule(pkg, m.metadata.Slug) {
-			m.pkgs[i].Root = true
-			break
+	modules := []models.Module{*root}
+	listed := map[string]bool{}
+	sections := []struct {
+		packages     map[string]*lockPackage
+		relationship models.RelationshipType
+	}{
+		{lock.Default, ""},
+		{lock.Develop, models.RelationshipDevDependencyOf},
+	}
+	for _, section := range sections {
+		for _, pkg := range packages(section.packages) {
+			// the project installed in editable mode is the root package, a develop package
+			// also locked as a default one is listed once
+			name := worker.NormalizeName(pkg.Name)
+			if pkg.Path == "." || listed[name] {
+				continue
+			}
+			listed[name] = true
+
+			mod := packageModule(pkg, lock.source(pkg))
+			link := mod
+			link.Relationship = section.relationship
+			modules[0].Modules[mod.Name] = &link
+			modules = append(modules, mod)
 		}
 	}
+	return modules, nil
 }
 
-func (m *pipenv) LoadModuleList(path string) error {
-	var state bool
-	var err error
-
-	if worker.IsValidRootModule(path) {
-		state, err = m.PushRootModuleToVenv()
-		if err != nil && !state {
-			return err
-		}
-		m.buildCmd(ModulesCmd, m.basepath)
-		result, err := m.command.Output()
-		if err == nil && len(result) > 0 && worker.IsRequirementMeet(result) {
-			m.pkgs = worker.LoadModules(result, m.version)
-			m.markRootModue()
-		}
-		return err
+// packageModule returns the package of the lockfile with the hash of one of its distributions
+func packageModule(pkg *lockPackage, index string) models.Module {
+	mod := models.Module{
+		Name:                    pkg.Name,
+		Version:                 pkg.version(),
+		PackageURL:              purl.New("pypi", "", worker.NormalizeName(pkg.Name), pkg.version()).String(),
+		PackageDownloadLocation: pkg.downloadLocation(index),
+		CheckSum:                pkg.checksum(),
+		Supplier:                models.SupplierContact{Name: pkg.Name},
+		Modules:                 map[string]*models.Module{},
 	}
-	return err
-}
-
-func (m *pipenv) fetchRootModule() models.Module {
-	for _, mod := range m.allModules {
-		if mod.Root {
-			return mod
-		}
+	if pkg.Git == "" && pkg.File == "" && pkg.Path == "" && (index == "" || strings.HasPrefix(index, pypiURL)) {
+		mod.PackageHomePage = fmt.Sprintf("%s/project/%s/%s/", pypiURL, pkg.Name, mod.Version)
 	}
-	return models.Module{}
+	return mod
 }
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipenv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "project")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.NoError(t, m.HasModulesInstalled(filepath.Join("testdata", "project")))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "project"))
+	assert.NoError(t, err)
+
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	// the editable project is the root, requests is listed once
+	assert.Equal(t, []string{"project", "certifi", "flask-login", "internal-lib", "requests", "iniconfig", "pytest"}, names)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, []string{"certifi", "flask-login", "iniconfig", "internal-lib", "pytest", "requests"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipType(""), root.Modules["requests"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["pytest"].Relationship)
+
+	certifi := modules[1]
+	assert.Equal(t, "2024.7.4", certifi.Version)
+	assert.Equal(t, "pkg:pypi/certifi@2024.7.4", certifi.PackageURL)
+	assert.Equal(t, "https://pypi.org/project/certifi/2024.7.4/", certifi.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "123be5b01e5a31549c3cba610a83c5d31916cf13d1d5d1a0dce697c78b887de7"}, certifi.CheckSum)
+
+	flaskLogin := modules[2]
+	assert.Equal(t, "4ee8fa0e2ba6d4a2bd8d38ea73f03a7a6fea6e33", flaskLogin.Version)
+	assert.Equal(t, "git+https://github.com/maxcountryman/flask-login.git@4ee8fa0e2ba6d4a2bd8d38ea73f03a7a6fea6e33", flaskLogin.PackageDownloadLocation)
+	assert.Nil(t, flaskLogin.CheckSum)
+	assert.Empty(t, flaskLogin.PackageHomePage)
+
+	internalLib := modules[3]
+	assert.Equal(t, "https://pypi.example.com/simple", internalLib.PackageDownloadLocation)
+	assert.Empty(t, internalLib.PackageHomePage)
+}
+
+func TestListModulesMalformedLockfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pipenv")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, manifestLockFile), []byte("{\n  \"default\": {\n"), 0644))
+
+	_, err = New().ListModulesWithDeps(dir)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipenv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// lockSource is a package index of the Pipfile
+type lockSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// lockPackage is a package of the default or develop section. The packages of an index have a version
+// pinned with ==, the other ones are a git repository at a ref, a local path or a file url
+type lockPackage struct {
+	Name     string   `json:"-"`
+	Version  string   `json:"version"`
+	Hashes   []string `json:"hashes"`
+	Index    string   `json:"index"`
+	Git      string   `json:"git"`
+	Ref      string   `json:"ref"`
+	Path     string   `json:"path"`
+	File     string   `json:"file"`
+	Editable bool     `json:"editable"`
+}
+
+// lockfile is a Pipfile.lock, its packages are sorted by name
+type lockfile struct {
+	Meta struct {
+		PipfileSpec int          `json:"pipfile-spec"`
+		Sources     []lockSource `json:"sources"`
+	} `json:"_meta"`
+	Default map[string]*lockPackage `json:"default"`
+	Develop map[string]*lockPackage `json:"develop"`
+}
+
+func readLockfile(path string) (*lockfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock lockfile
+	if err := reader.DecodeJSON(path, data, &lock); err != nil {
+		return nil, err
+	}
+	for name, pkg := range lock.Default {
+		pkg.Name = name
+	}
+	for name, pkg := range lock.Develop {
+		pkg.Name = name
+	}
+	return &lock, nil
+}
+
+// packages returns the packages of a section sorted by name
+func packages(section map[string]*lockPackage) []*lockPackage {
+	var pkgs []*lockPackage
+	for _, pkg := range section {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+	return pkgs
+}
+
+// source returns the url of the index the package is installed from
+func (l *lockfile) source(pkg *lockPackage) string {
+	for _, source := range l.Meta.Sources {
+		if source.Name == pkg.Index {
+			return source.URL
+		}
+	}
+	return ""
+}
+
+// version returns the pinned version of the package, the ref of a git one
+func (p *lockPackage) version() string {
+	if version := strings.TrimPrefix(p.Version, "=="); version != "" {
+		return version
+	}
+	return p.Ref
+}
+
+// checksum returns the first of the sha256 hashes of the package files, nil when it has none.
+// The lockfile does not tell which distribution each hash belongs to
+func (p *lockPackage) checksum() *models.CheckSum {
+	hashes := append([]string{}, p.Hashes...)
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		if value := strings.TrimPrefix(hash, "sha256:"); value != hash {
+			return &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: value}
+		}
+	}
+	return nil
+}
+
+// downloadLocation returns where the package is downloaded from: its git repository at the locked ref,
+// its file url, its PyPI release or else the index it is installed from
+func (p *lockPackage) downloadLocation(index string) string {
+	switch {
+	case p.Git != "":
+		return fmt.Sprintf("git+%s@%s", strings.TrimPrefix(p.Git, "git+"), p.Ref)
+	case p.File != "":
+		return p.File
+	case p.Path != "":
+		// the local packages are part of the project
+		return "NONE"
+	case index == "" || strings.HasPrefix(index, pypiURL):
+		return fmt.Sprintf("%s/project/%s/%s/", pypiURL, p.Name, p.version())
+	}
+	return index
+}
@@ -4,34 +4,39 @@ package poetry
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
 )
 
 const cmdName = "poetry"
 const manifestFile = "pyproject.toml"
 const manifestLockFile = "poetry.lock"
-const placeholderPkgName = "{PACKAGE}"
 
-var errDependenciesNotFound = errors.New("Unable to generate SPDX file: no modules or vendors found. Please install them before running spdx-sbom-generator, e.g.: `poetry install` or `poetry update`")
-var errBuildlingModuleDependencies = errors.New("Error building module dependencies")
-var errNoPipCommand = errors.New("Cannot find the poetry command")
-var errVersionNotFound = errors.New("Python version not found")
-var errFailedToConvertModules = errors.New("Failed to convert modules")
+// pypiURL is the index the packages without a source are resolved from
+const pypiURL = "https://pypi.org"
+
+var errDependenciesNotFound = errors.New("Unable to generate SPDX file: no poetry.lock found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `poetry lock`")
+var errUnsupportedLockfileVersion = errors.New("unsupported poetry lock version")
+
+// hashAlgorithms are the algorithms of the file hashes of the lockfile
+var hashAlgorithms = map[string]models.HashAlgorithm{
+	"md5":    models.HashAlgoMD5,
+	"sha1":   models.HashAlgoSHA1,
+	"sha256": models.HashAlgoSHA256,
+	"sha384": models.HashAlgoSHA384,
+	"sha512": models.HashAlgoSHA512,
+}
 
 type poetry struct {
-	metadata   models.PluginMetadata
-	rootModule *models.Module
-	command    *helper.Cmd
-	basepath   string
-	version    string
-	pkgs       []worker.Packages
-	metainfo   map[string]worker.Metadata
-	allModules []models.Module
+	metadata models.PluginMetadata
+	options  models.PluginOptions
 }
 
 // New ...
@@ -40,7 +45,7 @@ func New() *poetry {
 		metadata: models.PluginMetadata{
 			Name:       "The Python Package Index (PyPI)",
 			Slug:       "poetry",
-			Manifest:   []string{manifestLockFile},
+			Manifest:   []string{manifestLockFile, manifestFile},
 			ModulePath: []string{},
 		},
 	}
@@ -51,153 +56,296 @@ func (m *poetry) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
 
-// Is Valid ...
+// IsValid checks if the poetry.lock exists
 func (m *poetry) IsValid(path string) bool {
-	for i := range m.metadata.Manifest {
-		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
-			return true
-		}
-	}
-	return false
+	return helper.Exists(filepath.Join(path, manifestLockFile))
 }
 
-// Has Modules Installed ...
+// HasModulesInstalled checks the dependencies are locked, the lockfile is enough to list them
 func (m *poetry) HasModulesInstalled(path string) error {
-	if err := m.buildCmd(ModulesCmd, m.basepath); err != nil {
-		return err
-	}
-	result, err := m.command.Output()
-	if err == nil && len(result) > 0 && worker.IsRequirementMeet(result) {
+	if helper.Exists(filepath.Join(path, manifestLockFile)) {
 		return nil
 	}
 	return errDependenciesNotFound
 }
 
-// Get Version ...
+// SetOptions ...
+func (m *poetry) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the poetry version
 func (m *poetry) GetVersion() (string, error) {
-	if err := m.buildCmd(VersionCmd, m.basepath); err != nil {
-		return "", err
-	}
-	version, err := m.command.Output()
-	m.version = worker.GetShortPythonVersion(version)
+	output, err := helper.CommandContext(m.options.Context, cmdName, "--version").Output()
 	if err != nil {
-		return "Python", errVersionNotFound
+		return "", err
 	}
-	return version, err
+	return strings.TrimSpace(string(output)), nil
 }
 
 // Set Root Module ...
 func (m *poetry) SetRootModule(path string) error {
-	m.basepath = path
 	return nil
 }
 
-// Get Root Module ...
+// GetRootModule returns the project of the pyproject.toml as the root package, named after its
+// directory when it has none
 func (m *poetry) GetRootModule(path string) (*models.Module, error) {
-	if m.rootModule == nil {
-		module := m.fetchRootModule()
-		m.rootModule = &module
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
 	}
-	return m.rootModule, nil
-}
 
-// List Used Modules...
-func (m *poetry) ListUsedModules(path string) ([]models.Module, error) {
-	if err := m.LoadModuleList(path); err != nil {
-		return m.allModules, errFailedToConvertModules
+	p, err := readProject(filepath.Join(absPath, manifestFile))
+	if err != nil {
+		return nil, err
 	}
+	return rootModule(absPath, p, m.options.LicenseMatcher), nil
+}
 
-	decoder := worker.NewMetadataDecoder(m.GetPackageDetails)
-	metainfo, err := decoder.ConvertMetadataToModules(m.pkgs, &m.allModules)
+// ListUsedModules returns the packages of the lockfile, without the project itself
+func (m *poetry) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
 	if err != nil {
-		return m.allModules, err
+		return nil, err
 	}
-	m.metainfo = metainfo
-
-	return m.allModules, nil
+	return modules[1:], nil
 }
 
-// List Modules With Deps ...
+// ListModulesWithDeps returns the project followed by the packages of the lockfile, each linked to
+// the packages it depends on. The project depends on its main dependencies, the ones of its other
+// groups, e.g. dev or test, are DEV_DEPENDENCY_OF it
 func (m *poetry) ListModulesWithDeps(path string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := readProject(filepath.Join(absPath, manifestFile))
 	if err != nil {
 		return nil, err
 	}
-	m.GetRootModule(path)
-	if err := worker.BuildDependencyGraph(&m.allModules, &m.metainfo); err != nil {
+	lock, err := readLockfile(filepath.Join(absPath, manifestLockFile))
+	if err != nil {
 		return nil, err
 	}
-	return modules, err
+
+	modules := []models.Module{*rootModule(absPath, p, m.options.LicenseMatcher)}
+	for _, pkg := range lock.Packages {
+		modules = append(modules, packageModule(pkg))
+	}
+	// the first entry of a package locked for several environments is the linked one
+	index := map[*lockPackage]int{}
+	for i, pkg := range lock.Packages {
+		index[pkg] = i + 1
+	}
+
+	if len(p.Groups) == 0 {
+		p.Groups = lockRoots(lock)
+	}
+	groups := packageGroups(p, lock)
+	for _, group := range p.groupNames() {
+		relationship := models.RelationshipType("")
+		if group != mainGroup {
+			relationship = models.RelationshipDevDependencyOf
+		}
+		for _, name := range p.Groups[group] {
+			pkg, ok := lock.lookup(name)
+			if !ok {
+				continue
+			}
+			// a dependency of several groups is a main one when it is
+			if _, linked := modules[0].Modules[pkg.Name]; linked && group != mainGroup {
+				continue
+			}
+			helper.LinkModule(modules, 0, index[pkg], relationship)
+		}
+	}
+
+	for i, pkg := range lock.Packages {
+		if group := groups[pkg]; group != "" && group != mainGroup {
+			modules[i+1].PackageComment = fmt.Sprintf("poetry dependency group: %s", group)
+		}
+		for _, name := range pkg.Dependencies {
+			if dependency, ok := lock.lookup(name); ok {
+				helper.LinkModule(modules, i+1, index[dependency], "")
+			}
+		}
+	}
+
+	return modules, nil
 }
 
-func (m *poetry) buildCmd(cmd command, path string) error {
-	cmdArgs := cmd.Parse()
-	if cmdArgs[0] != cmdName {
-		return errNoPipCommand
+// packageGroups returns the group of each package: the one the lockfile tells or, for the lockfiles
+// without groups, the first of the project groups whose dependencies reach it, main first
+func packageGroups(p *project, lock *lockfile) map[*lockPackage]string {
+	groups := map[*lockPackage]string{}
+	for _, group := range p.groupNames() {
+		var queue []*lockPackage
+		for _, name := range p.Groups[group] {
+			if pkg, ok := lock.lookup(name); ok {
+				queue = append(queue, pkg)
+			}
+		}
+		for len(queue) > 0 {
+			pkg := queue[0]
+			queue = queue[1:]
+			if _, ok := groups[pkg]; ok {
+				continue
+			}
+			groups[pkg] = group
+			for _, name := range pkg.Dependencies {
+				if dependency, ok := lock.lookup(name); ok {
+					queue = append(queue, dependency)
+				}
+			}
+		}
 	}
 
-	command := helper.NewCmd(helper.CmdOptions{
-		Name:      cmdArgs[0],
-		Args:      cmdArgs[1:],
-		Directory: path,
-	})
+	for _, pkg := range lock.Packages {
+		if len(pkg.Groups) == 0 {
+			continue
+		}
+		groups[pkg] = pkg.Groups[0]
+		for _, group := range pkg.Groups {
+			if group == mainGroup {
+				groups[pkg] = mainGroup
+			}
+		}
+	}
+	return groups
+}
 
-	m.command = command
+// lockRoots returns the packages no other one depends on keyed by their group, the direct dependencies
+// of a project whose pyproject.toml does not tell them
+func lockRoots(lock *lockfile) map[string][]string {
+	dependencies := map[string]bool{}
+	for _, pkg := range lock.Packages {
+		for _, name := range pkg.Dependencies {
+			dependencies[name] = true
+		}
+	}
 
-	return command.Build()
+	roots := map[string][]string{}
+	for _, pkg := range lock.Packages {
+		name := worker.NormalizeName(pkg.Name)
+		if dependencies[name] {
+			continue
+		}
+		group := mainGroup
+		if len(pkg.Groups) > 0 {
+			group = pkg.Groups[0]
+		}
+		roots[group] = append(roots[group], name)
+	}
+	return roots
 }
 
-func (m *poetry) GetPackageDetails(packageName string) (string, error) {
-	metatdataCmd := command(strings.ReplaceAll(string(MetadataCmd), placeholderPkgName, packageName))
+func readProject(path string) (*project, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &project{Groups: map[string][]string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-	m.buildCmd(metatdataCmd, m.basepath)
-	result, err := m.command.Output()
+	return parseProject(path, file)
+}
+
+func readLockfile(path string) (*lockfile, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer file.Close()
 
-	return result, nil
+	return parseLockfile(path, file)
 }
 
-func (m *poetry) PushRootModuleToVenv() (bool, error) {
-	if err := m.buildCmd(InstallRootModuleCmd, m.basepath); err != nil {
-		return false, err
+// rootModule returns the package of the project, its license is the one of the pyproject.toml when
+// it is an SPDX expression, else the one of its license files
+func rootModule(path string, p *project, matcher helper.LicenseMatcher) *models.Module {
+	name := p.Name
+	if name == "" {
+		name = filepath.Base(path)
 	}
-	result, err := m.command.Output()
-	if err == nil && len(result) > 0 {
-		return true, err
+
+	mod := &models.Module{
+		Name:            name,
+		Version:         p.Version,
+		Root:            true,
+		LocalPath:       path,
+		PackageURL:      purl.New("pypi", "", worker.NormalizeName(name), p.Version).String(),
+		PackageHomePage: p.HomePage,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(fmt.Sprintf("%s-%s", name, p.Version)),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	mod.PackageDownloadLocation = p.Repository
+	if mod.PackageDownloadLocation == "" {
+		mod.PackageDownloadLocation = "NONE"
+	}
+	if p.Author != "" {
+		mod.Supplier = models.SupplierContact{Type: models.Person, Name: p.Author, Email: p.Email}
 	}
-	return false, nil
-}
 
-func (m *poetry) markRootModue() {
-	for i, pkg := range m.pkgs {
-		if worker.IsRootModule(pkg, m.metadata.Slug) {
-			m.pkgs[i].Root = true
-			break
+	if expression := helper.SPDXExpression(p.License); expression != "" {
+		mod.LicenseDeclared = expression
+		mod.LicenseConcluded = expression
+		return mod
+	}
+	if license, err := helper.GetLicenses(matcher, path); err == nil {
+		mod.LicenseDeclared = helper.BuildLicenseDeclared(license.ID)
+		mod.LicenseConcluded = helper.BuildLicenseConcluded(license.ID)
+		mod.CommentsLicense = license.Comments
+		if !helper.LicenseSPDXExists(license.ID) {
+			mod.OtherLicense = append(mod.OtherLicense, license)
 		}
 	}
+	return mod
 }
 
-func (m *poetry) LoadModuleList(path string) error {
-	state, err := m.PushRootModuleToVenv()
-	if err != nil && !state {
-		return err
+// packageModule returns the package of the lockfile with the hash of its distribution
+func packageModule(pkg *lockPackage) models.Module {
+	mod := models.Module{
+		Name:                    pkg.Name,
+		Version:                 pkg.Version,
+		PackageURL:              purl.New("pypi", "", worker.NormalizeName(pkg.Name), pkg.Version).String(),
+		PackageDownloadLocation: downloadLocation(pkg),
+		CheckSum:                pkg.checksum(),
+		Supplier:                models.SupplierContact{Name: pkg.Name},
+		Modules:                 map[string]*models.Module{},
 	}
-	m.buildCmd(ModulesCmd, m.basepath)
-	result, err := m.command.Output()
-	if err == nil && len(result) > 0 && worker.IsRequirementMeet(result) {
-		m.pkgs = worker.LoadModules(result, m.version)
-		m.markRootModue()
+	if pkg.Source.Type == "" {
+		mod.PackageHomePage = fmt.Sprintf("%s/project/%s/%s/", pypiURL, pkg.Name, pkg.Version)
 	}
-	return err
+	return mod
 }
 
-func (m *poetry) fetchRootModule() models.Module {
-	for _, mod := range m.allModules {
-		if mod.Root {
-			return mod
+// downloadLocation returns where the package is downloaded from: its git repository at the locked commit,
+// its url or the index it is served by, the source distribution PyPI serves or else its PyPI release
+func downloadLocation(pkg *lockPackage) string {
+	switch pkg.Source.Type {
+	case "git":
+		reference := pkg.Source.ResolvedReference
+		if reference == "" {
+			reference = pkg.Source.Reference
 		}
+		return fmt.Sprintf("git+%s@%s", pkg.Source.URL, reference)
+	case "url", "legacy":
+		return pkg.Source.URL
+	case "":
+	default:
+		// directory and file sources are local to the project
+		return "NONE"
+	}
+
+	if sdist, ok := pkg.sdist(); ok {
+		return fmt.Sprintf("https://files.pythonhosted.org/packages/source/%c/%s/%s", pkg.Name[0], pkg.Name, sdist.File)
 	}
-	return models.Module{}
+	return fmt.Sprintf("%s/project/%s/%s/", pypiURL, pkg.Name, pkg.Version)
 }
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package poetry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "lock-v2")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.NoError(t, m.HasModulesInstalled(filepath.Join("testdata", "lock-v1")))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled("testdata"))
+}
+
+func TestGetRootModule(t *testing.T) {
+	root, err := New().GetRootModule(filepath.Join("testdata", "lock-v2"))
+	assert.NoError(t, err)
+	assert.True(t, root.Root)
+	assert.Equal(t, "poetry-app", root.Name)
+	assert.Equal(t, "0.3.0", root.Version)
+	assert.Equal(t, "pkg:pypi/poetry-app@0.3.0", root.PackageURL)
+	assert.Equal(t, "https://example.com/poetry-app", root.PackageHomePage)
+	assert.Equal(t, "https://github.com/example/poetry-app", root.PackageDownloadLocation)
+	assert.Equal(t, "Apache-2.0 OR MIT", root.LicenseDeclared)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "lock-v2"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 8)
+
+	root := modules[0]
+	assert.Equal(t, []string{"flask-login", "pytest", "requests"}, modulestest.LinkedNames(root))
+	// requests is a main and a docs dependency
+	assert.Equal(t, models.RelationshipType(""), root.Modules["requests"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["pytest"].Relationship)
+
+	byName := modulestest.ByName(modules)
+	certifi := byName["certifi"]
+	assert.Equal(t, "pkg:pypi/certifi@2024.7.4", certifi.PackageURL)
+	assert.Equal(t, "https://files.pythonhosted.org/packages/source/c/certifi/certifi-2024.7.4.tar.gz", certifi.PackageDownloadLocation)
+	assert.Equal(t, "https://pypi.org/project/certifi/2024.7.4/", certifi.PackageHomePage)
+	assert.Equal(t, "5e1e6c71ccaa1603fac2b9a249db7b40636fb1c67155b54672c5df1fc6e87d8f", certifi.CheckSum.Value)
+
+	assert.Equal(t, []string{"certifi"}, modulestest.LinkedNames(byName["requests"]))
+	assert.Equal(t, []string{"werkzeug"}, modulestest.LinkedNames(byName["flask-login"]))
+	assert.Equal(t, "git+https://github.com/maxcountryman/flask-login.git@4ee8fa0e2ba6d4a2bd8d38ea73f03a7a6fea6e33", byName["flask-login"].PackageDownloadLocation)
+	assert.Equal(t, "https://pypi.example.com/simple", byName["werkzeug"].PackageDownloadLocation)
+	assert.Equal(t, "https://pypi.org/project/iniconfig/2.0.0/", byName["iniconfig"].PackageDownloadLocation)
+
+	// the packages only the dev group reaches are commented with it
+	assert.Equal(t, "poetry dependency group: dev", byName["iniconfig"].PackageComment)
+	assert.Empty(t, byName["certifi"].PackageComment)
+}
+
+func TestListModulesWithDepsLockV1(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "lock-v1"))
+	assert.NoError(t, err)
+
+	root := modules[0]
+	assert.Equal(t, "legacy_app", root.Name)
+	assert.Equal(t, "pkg:pypi/legacy-app@1.2.0", root.PackageURL)
+	assert.Equal(t, "BSD-3-Clause", root.LicenseDeclared)
+	assert.Equal(t, "https://github.com/example/legacy-app", root.PackageDownloadLocation)
+	assert.Equal(t, []string{"black", "click"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["black"].Relationship)
+
+	byName := modulestest.ByName(modules)
+	assert.Equal(t, []string{"click", "mypy-extensions"}, modulestest.LinkedNames(byName["black"]))
+	assert.Equal(t, "poetry dependency group: dev", byName["mypy-extensions"].PackageComment)
+	assert.Nil(t, byName["mypy-extensions"].CheckSum)
+}
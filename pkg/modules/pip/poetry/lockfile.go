@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package poetry
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// mainGroup is the group of the dependencies the project needs at runtime
+const mainGroup = "main"
+
+// lockFile is a distribution of a locked package, its hash is algorithm:hex, e.g. sha256:<hex>
+type lockFile struct {
+	File string
+	Hash string
+}
+
+// lockSource is where a package not resolved from PyPI comes from, e.g. a git repository or a private index
+type lockSource struct {
+	Type              string
+	URL               string
+	Reference         string
+	ResolvedReference string
+}
+
+// lockPackage is a [[package]] of the lockfile
+type lockPackage struct {
+	Name     string
+	Version  string
+	Optional bool
+	// Groups are the dependency groups of the package, the category of lockfiles 1.x or the groups of 2.1,
+	// empty when the lockfile does not tell them
+	Groups []string
+	Files  []lockFile
+	Source lockSource
+	// Dependencies are the normalized names of the packages it depends on, its optional ones excepted
+	Dependencies []string
+}
+
+// lockfile is a poetry.lock
+type lockfile struct {
+	Version  string
+	Packages []*lockPackage
+	// byName are the packages keyed by their normalized name
+	byName map[string]*lockPackage
+}
+
+// parseLockfile reads a poetry.lock of lock version 1.x (poetry 1.0 to 1.4, older ones have none) or 2.x.
+// The file hashes are the ones of the package entries or, in 1.x, of the [metadata.files] table
+func parseLockfile(fileName string, r io.Reader) (*lockfile, error) {
+	document, err := reader.ParseTOML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := document.Get("metadata")
+	l := &lockfile{Version: metadata.Str("lock-version"), byName: map[string]*lockPackage{}}
+	if l.Version == "" {
+		l.Version = "1.0"
+	}
+	switch strings.SplitN(l.Version, ".", 2)[0] {
+	case "1", "2":
+	default:
+		return nil, fmt.Errorf("%w: %q in %s", errUnsupportedLockfileVersion, l.Version, fileName)
+	}
+
+	var metadataFiles reader.Table
+	if metadata != nil {
+		metadataFiles = metadata.Get("files")
+	}
+	filesByName := map[string][]lockFile{}
+	for name := range metadataFiles {
+		filesByName[worker.NormalizeName(name)] = parseLockFiles(metadataFiles, name)
+	}
+
+	for _, entry := range document.Tables("package") {
+		pkg := &lockPackage{
+			Name:     entry.Str("name"),
+			Version:  entry.Str("version"),
+			Optional: entry["optional"] == true,
+			Groups:   entry.Strings("groups"),
+			Files:    parseLockFiles(entry, "files"),
+		}
+		if pkg.Name == "" {
+			continue
+		}
+		if category := entry.Str("category"); category != "" {
+			pkg.Groups = []string{category}
+		}
+		if len(pkg.Files) == 0 {
+			pkg.Files = filesByName[worker.NormalizeName(pkg.Name)]
+		}
+		if source := entry.Get("source"); source != nil {
+			pkg.Source = lockSource{
+				Type:              source.Str("type"),
+				URL:               source.Str("url"),
+				Reference:         source.Str("reference"),
+				ResolvedReference: source.Str("resolved_reference"),
+			}
+		}
+		pkg.Dependencies = lockDependencies(entry.Get("dependencies"))
+
+		l.Packages = append(l.Packages, pkg)
+		// the entries of a package locked for several environments share its name, the first one is linked
+		if _, ok := l.byName[worker.NormalizeName(pkg.Name)]; !ok {
+			l.byName[worker.NormalizeName(pkg.Name)] = pkg
+		}
+	}
+	return l, nil
+}
+
+// lookup returns the package of the name, whatever its case and separators
+func (l *lockfile) lookup(name string) (*lockPackage, bool) {
+	pkg, ok := l.byName[worker.NormalizeName(name)]
+	return pkg, ok
+}
+
+// parseLockFiles returns the files of the array of inline tables at key, e.g. files = [{file = "...", hash = "sha256:..."}]
+func parseLockFiles(t reader.Table, key string) []lockFile {
+	values, _ := t[key].([]interface{})
+	var files []lockFile
+	for _, value := range values {
+		if file, ok := value.(reader.Table); ok && file.Str("hash") != "" {
+			files = append(files, lockFile{File: file.Str("file"), Hash: file.Str("hash")})
+		}
+	}
+	return files
+}
+
+// lockDependencies returns the normalized names of the dependencies of a package. A dependency is a version
+// constraint, a table or, when it differs by environment, an array of tables; the optional ones are only
+// installed with an extra of the package and are left out
+func lockDependencies(dependencies reader.Table) []string {
+	var names []string
+	for name, value := range dependencies {
+		optional := false
+		switch constraint := value.(type) {
+		case reader.Table:
+			optional = constraint["optional"] == true
+		case []interface{}:
+			optional = true
+			for _, item := range constraint {
+				if t, ok := item.(reader.Table); !ok || t["optional"] != true {
+					optional = false
+				}
+			}
+		}
+		if !optional {
+			names = append(names, worker.NormalizeName(name))
+		}
+	}
+	return sortedStrings(names)
+}
+
+// checksum returns the hash of the source distribution of the package, else the one of its first file,
+// nil when the lockfile has none
+func (p *lockPackage) checksum() *models.CheckSum {
+	if len(p.Files) == 0 {
+		return nil
+	}
+
+	file := p.Files[0]
+	if sdist, ok := p.sdist(); ok {
+		file = sdist
+	}
+	parts := strings.SplitN(file.Hash, ":", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	algorithm, ok := hashAlgorithms[parts[0]]
+	if !ok {
+		return nil
+	}
+	return &models.CheckSum{Algorithm: algorithm, Value: parts[1]}
+}
+
+// sdist returns the source distribution of the package
+func (p *lockPackage) sdist() (lockFile, bool) {
+	for _, file := range p.Files {
+		if strings.HasSuffix(file.File, ".tar.gz") || strings.HasSuffix(file.File, ".zip") {
+			return file, true
+		}
+	}
+	return lockFile{}, false
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package poetry
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestParseLockfileV2(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "lock-v2", manifestLockFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", lock.Version)
+	assert.Len(t, lock.Packages, 7)
+
+	requests, ok := lock.lookup("Requests")
+	if assert.True(t, ok) {
+		assert.Equal(t, "2.32.3", requests.Version)
+		assert.Len(t, requests.Files, 2)
+		// chardet and PySocks are only installed with an extra
+		assert.Equal(t, []string{"certifi"}, requests.Dependencies)
+	}
+
+	flaskLogin, ok := lock.lookup("Flask_Login")
+	if assert.True(t, ok) {
+		assert.Equal(t, lockSource{
+			Type:              "git",
+			URL:               "https://github.com/maxcountryman/flask-login.git",
+			Reference:         "main",
+			ResolvedReference: "4ee8fa0e2ba6d4a2bd8d38ea73f03a7a6fea6e33",
+		}, flaskLogin.Source)
+		assert.Equal(t, []string{"werkzeug"}, flaskLogin.Dependencies)
+		assert.Nil(t, flaskLogin.checksum())
+	}
+
+	pytest, _ := lock.lookup("pytest")
+	assert.Equal(t, []string{"colorama", "iniconfig"}, pytest.Dependencies)
+	assert.Empty(t, pytest.Groups)
+}
+
+func TestParseLockfileV1(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "lock-v1", manifestLockFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1", lock.Version)
+
+	// the files of lock version 1.x are listed in [metadata.files]
+	click, _ := lock.lookup("click")
+	assert.Equal(t, []string{"main"}, click.Groups)
+	assert.Len(t, click.Files, 2)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "3b0c4006a64109e60ea2707b7337fab90f467b41b29a9e576e522028021c5d40"}, click.checksum())
+
+	black, _ := lock.lookup("black")
+	assert.Equal(t, []string{"dev"}, black.Groups)
+	assert.Equal(t, "0a689f80cd10e6807540fa3b594b0d3ce2ad3ed4532b9027528366b6e5c8d1bb", black.checksum().Value)
+}
+
+func TestParseLockfileUnsupportedVersion(t *testing.T) {
+	_, err := parseLockfile(manifestLockFile, strings.NewReader("[metadata]\nlock-version = \"3.0\"\n"))
+	assert.True(t, errors.Is(err, errUnsupportedLockfileVersion))
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package poetry
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// requirementNameRegex matches the name of a PEP 508 requirement, e.g. requests of requests[socks]>=2.25
+var requirementNameRegex = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// authorRegex splits a poetry author, e.g. Jane Doe <jane@example.com>
+var authorRegex = regexp.MustCompile(`^\s*([^<]*?)\s*(?:<([^>]*)>)?\s*$`)
+
+// project is the metadata and the direct dependencies of a pyproject.toml, the [project] table of PEP 621
+// taking precedence over the [tool.poetry] one
+type project struct {
+	Name       string
+	Version    string
+	License    string
+	HomePage   string
+	Repository string
+	Author     string
+	Email      string
+	// Groups are the normalized names of the direct dependencies keyed by their group, main for the runtime ones
+	Groups map[string][]string
+}
+
+// parseProject reads the project of a pyproject.toml
+func parseProject(fileName string, r io.Reader) (*project, error) {
+	document, err := reader.ParseTOML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &project{Groups: map[string][]string{}}
+	if poetry := document.Get("tool", "poetry"); poetry != nil {
+		p.Name = poetry.Str("name")
+		p.Version = poetry.Str("version")
+		p.License = poetry.Str("license")
+		p.HomePage = poetry.Str("homepage")
+		p.Repository = poetry.Str("repository")
+		if authors := poetry.Strings("authors"); len(authors) > 0 {
+			if match := authorRegex.FindStringSubmatch(authors[0]); match != nil {
+				p.Author, p.Email = match[1], match[2]
+			}
+		}
+
+		for name := range poetry.Get("dependencies") {
+			// the python constraint is not a package
+			if name != "python" {
+				p.addDependency(mainGroup, name)
+			}
+		}
+		for name := range poetry.Get("dev-dependencies") {
+			p.addDependency("dev", name)
+		}
+		for group, definition := range poetry.Get("group") {
+			if definition, ok := definition.(reader.Table); ok {
+				for name := range definition.Get("dependencies") {
+					p.addDependency(group, name)
+				}
+			}
+		}
+	}
+
+	if pep621 := document.Get("project"); pep621 != nil {
+		p.setProject(pep621)
+	}
+
+	for group, names := range p.Groups {
+		p.Groups[group] = sortedStrings(names)
+	}
+	return p, nil
+}
+
+// setProject sets the metadata and the runtime dependencies of the [project] table
+func (p *project) setProject(pep621 reader.Table) {
+	if name := pep621.Str("name"); name != "" {
+		p.Name = name
+	}
+	if version := pep621.Str("version"); version != "" {
+		p.Version = version
+	}
+
+	// the license is an expression or, before PEP 639, a table whose text names it
+	switch license := pep621["license"].(type) {
+	case string:
+		p.License = license
+	case reader.Table:
+		if text := strings.TrimSpace(license.Str("text")); text != "" && !strings.Contains(text, "\n") {
+			p.License = text
+		}
+	}
+
+	for key, value := range pep621.Get("urls") {
+		url, _ := value.(string)
+		switch strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(key)) {
+		case "homepage":
+			p.HomePage = url
+		case "repository", "source", "sourcecode":
+			p.Repository = url
+		}
+	}
+
+	if authors, _ := pep621["authors"].([]interface{}); len(authors) > 0 {
+		if author, ok := authors[0].(reader.Table); ok {
+			p.Author, p.Email = author.Str("name"), author.Str("email")
+		}
+	}
+
+	for _, requirement := range pep621.Strings("dependencies") {
+		if match := requirementNameRegex.FindStringSubmatch(requirement); match != nil {
+			p.addDependency(mainGroup, match[1])
+		}
+	}
+}
+
+func (p *project) addDependency(group, name string) {
+	name = worker.NormalizeName(name)
+	for _, existing := range p.Groups[group] {
+		if existing == name {
+			return
+		}
+	}
+	p.Groups[group] = append(p.Groups[group], name)
+}
+
+// groupNames returns the groups of the project, main first then sorted by name
+func (p *project) groupNames() []string {
+	var names []string
+	for group := range p.Groups {
+		if group != mainGroup {
+			names = append(names, group)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{mainGroup}, names...)
+}
+
+func sortedStrings(values []string) []string {
+	sort.Strings(values)
+	return values
+}
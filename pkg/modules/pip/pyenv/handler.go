@@ -39,6 +39,7 @@ type pyenv struct {
 	metainfo   map[string]worker.Metadata
 	allModules []models.Module
 	venv       string
+	options    models.PluginOptions
 }
 
 // New ...
@@ -70,6 +71,10 @@ func (m *pyenv) IsValid(path string) bool {
 
 // Has Modules Installed ...
 func (m *pyenv) HasModulesInstalled(path string) error {
+	// the requirements are read without python
+	if m.options.LockfileOnly {
+		return nil
+	}
 	dir := m.GetExecutableDir()
 	ModulesCmd := GetExecutableCommand(ModulesCmd)
 	if err := m.buildCmd(ModulesCmd, dir); err != nil {
@@ -82,6 +87,11 @@ func (m *pyenv) HasModulesInstalled(path string) error {
 	return errDependenciesNotFound
 }
 
+// Set Options ...
+func (m *pyenv) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // Get Version ...
 func (m *pyenv) GetVersion() (string, error) {
 	version := "Python"
@@ -125,18 +135,28 @@ func (m *pyenv) ListUsedModules(path string) ([]models.Module, error) {
 		return m.allModules, errFailedToConvertModules
 	}
 
-	decoder := worker.NewMetadataDecoder(m.GetPackageDetails)
+	decoder := worker.NewMetadataDecoder(m.GetPackageDetails, m.options.Offline, m.options.LicenseMatcher)
 	metainfo, err := decoder.ConvertMetadataToModules(m.pkgs, &m.allModules)
 	if err != nil {
 		return m.allModules, err
 	}
 	m.metainfo = metainfo
 
+	// the --hash pins of the requirements are the checksums of the distributions pip verified
+	requirements, err := readRequirements(filepath.Join(path, manifestFile))
+	if err != nil {
+		return m.allModules, err
+	}
+	setRequirementHashes(m.allModules, requirements)
+
 	return m.allModules, nil
 }
 
 // List Modules With Deps ...
 func (m *pyenv) ListModulesWithDeps(path string) ([]models.Module, error) {
+	if m.options.LockfileOnly {
+		return listRequiredModules(path, m.options.LicenseMatcher)
+	}
 	modules, err := m.ListUsedModules(path)
 	if err != nil {
 		return nil, err
@@ -158,6 +178,7 @@ func (m *pyenv) buildCmd(cmd command, path string) error {
 		Name:      cmdArgs[0],
 		Args:      cmdArgs[1:],
 		Directory: path,
+		Context:   m.options.Context,
 	})
 
 	m.command = command
@@ -187,6 +208,10 @@ func (m *pyenv) GetPackageDetails(packageName string) (string, error) {
 }
 
 func (m *pyenv) PushRootModuleToVenv() (bool, error) {
+	// installing the root module resolves its build dependencies from the package index
+	if m.options.Offline {
+		return false, nil
+	}
 	dir := m.GetExecutableDir()
 	InstallRootModuleCmd := GetExecutableCommand(InstallRootModuleCmd)
 	if err := m.buildCmd(InstallRootModuleCmd, dir); err != nil {
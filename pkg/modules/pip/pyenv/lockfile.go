@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pyenv
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// pypiURL is the default index of pip
+const pypiURL = "https://pypi.org"
+
+// unpinnedAnnotation notes the requirement has no exact version, the one pip installs is unknown without it
+const unpinnedAnnotation = "generated in the lockfile-only mode, the requirement is not pinned with ==: its version is unknown"
+
+// listRequiredModules returns the project followed by the packages of the requirements file and the files it
+// includes, without python. The requirements do not tell which package depends on which, so the project
+// depends on all of them
+func listRequiredModules(path string, matcher helper.LicenseMatcher) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	requirements, err := readRequirements(filepath.Join(absPath, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(absPath)
+	root := models.Module{
+		Name:                    name,
+		Root:                    true,
+		LocalPath:               absPath,
+		PackageDownloadLocation: "NONE",
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(name),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	if license, err := helper.GetLicenses(matcher, absPath); err == nil {
+		root.LicenseDeclared = helper.BuildLicenseDeclared(license.ID)
+		root.LicenseConcluded = helper.BuildLicenseConcluded(license.ID)
+		root.CommentsLicense = license.Comments
+		if !helper.LicenseSPDXExists(license.ID) {
+			root.OtherLicense = append(root.OtherLicense, license)
+		}
+	}
+
+	keys := make([]string, 0, len(requirements))
+	for key := range requirements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	modules := []models.Module{root}
+	for _, key := range keys {
+		mod := requirementModule(requirements[key])
+		link := mod
+		modules[0].Modules[key] = &link
+		modules = append(modules, mod)
+	}
+	return modules, nil
+}
+
+// requirementModule returns the package of a requirement with the hash of one of its distributions, an
+// unpinned requirement has neither a version nor a hash
+func requirementModule(r *requirement) models.Module {
+	location := fmt.Sprintf("%s/project/%s/", pypiURL, r.Name)
+	if r.Version != "" {
+		location = fmt.Sprintf("%s%s/", location, r.Version)
+	}
+	mod := models.Module{
+		Name:                    r.Name,
+		Version:                 r.Version,
+		PackageURL:              purl.New("pypi", "", worker.NormalizeName(r.Name), r.Version).String(),
+		PackageDownloadLocation: location,
+		PackageHomePage:         location,
+		CheckSum:                r.checksum(r.Version, nil),
+		Supplier:                models.SupplierContact{Name: r.Name},
+		Modules:                 map[string]*models.Module{},
+	}
+	if r.Version == "" {
+		mod.Unresolved = append(mod.Unresolved, unpinnedAnnotation)
+	}
+	return mod
+}
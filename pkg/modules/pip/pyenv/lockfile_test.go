@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pyenv
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListModulesWithDepsLockfileOnly(t *testing.T) {
+	m := New()
+	m.SetOptions(models.PluginOptions{Offline: true, LockfileOnly: true})
+	path := filepath.Join("testdata", "hashes")
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 6) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "hashes", root.Name)
+	assert.Len(t, root.Modules, 5)
+
+	names := []string{}
+	for _, mod := range modules[1:] {
+		names = append(names, mod.Name)
+	}
+	assert.Equal(t, []string{"certifi", "charset-normalizer", "idna", "requests", "urllib3"}, names)
+
+	requests := modules[4]
+	assert.Equal(t, "2.31.0", requests.Version)
+	assert.Equal(t, "pkg:pypi/requests@2.31.0", requests.PackageURL)
+	assert.Equal(t, "https://pypi.org/project/requests/2.31.0/", requests.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f"}, requests.CheckSum)
+	assert.Nil(t, modules[2].CheckSum)
+	assert.Empty(t, requests.Annotations)
+}
+
+func TestListRequiredModulesUnpinned(t *testing.T) {
+	modules, err := listRequiredModules(filepath.Join("testdata", "unpinned"), nil)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 3) {
+		return
+	}
+
+	flask := modules[1]
+	assert.Equal(t, "flask", flask.Name)
+	assert.Empty(t, flask.Version)
+	assert.Equal(t, "pkg:pypi/flask", flask.PackageURL)
+	assert.Equal(t, "https://pypi.org/project/flask/", flask.PackageDownloadLocation)
+	assert.Equal(t, []string{unpinnedAnnotation}, flask.Unresolved)
+}
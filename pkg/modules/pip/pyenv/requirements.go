@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pyenv
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
+)
+
+// requirementRegex splits a requirement line into the name and the version specifier, e.g. requests[socks]==2.31.0
+var requirementRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._\-]*)\s*(?:\[[^\]]*\])?\s*([^;]*)`)
+
+// hashAlgorithms are the algorithms pip checks the --hash options with
+var hashAlgorithms = map[string]models.HashAlgorithm{
+	"sha256": models.HashAlgoSHA256,
+	"sha384": models.HashAlgoSHA384,
+	"sha512": models.HashAlgoSHA512,
+}
+
+// requirement is a package of a requirements or constraints file, Version is set when pinned with ==
+type requirement struct {
+	Name    string
+	Version string
+	// Hashes are the --hash options of the requirement, algorithm:hex, one per distribution of the package
+	Hashes []string
+}
+
+// readRequirements returns the requirements of the file keyed by their normalized name, with the ones of
+// the requirements (-r) and constraints (-c) files it includes. The included paths are relative to the
+// including file, a file included twice is read once
+func readRequirements(path string) (map[string]*requirement, error) {
+	requirements := map[string]*requirement{}
+	return requirements, readRequirementsFile(path, requirements, map[string]bool{})
+}
+
+func readRequirementsFile(path string, requirements map[string]*requirement, visited map[string]bool) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if visited[absPath] {
+		return nil
+	}
+	visited[absPath] = true
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, line := range requirementLines(file) {
+		fields := strings.Fields(line)
+		if include, ok := includedFile(fields); ok {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(absPath), include)
+			}
+			if err := readRequirementsFile(include, requirements, visited); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			// the other options, e.g. --index-url or -e, do not pin a package
+			continue
+		}
+
+		r, ok := parseRequirement(fields)
+		if !ok {
+			continue
+		}
+		key := worker.NormalizeName(r.Name)
+		if existing, ok := requirements[key]; ok {
+			if existing.Version == "" {
+				existing.Version = r.Version
+			}
+			existing.Hashes = append(existing.Hashes, r.Hashes...)
+			continue
+		}
+		requirements[key] = &r
+	}
+	return nil
+}
+
+// requirementLines returns the logical lines of a requirements file: the lines ending with a backslash
+// are joined to the next one, the comments and the blank lines are left out
+func requirementLines(file *os.File) []string {
+	var lines []string
+	var current strings.Builder
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i == 0 || (i > 0 && (text[i-1] == ' ' || text[i-1] == '\t')) {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if strings.HasSuffix(text, "\\") {
+			current.WriteString(strings.TrimSuffix(text, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(text)
+		if line := strings.TrimSpace(current.String()); line != "" {
+			lines = append(lines, line)
+		}
+		current.Reset()
+	}
+	if line := strings.TrimSpace(current.String()); line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// includedFile returns the file of a -r, --requirement, -c or --constraint line
+func includedFile(fields []string) (string, bool) {
+	if len(fields) == 0 {
+		return "", false
+	}
+	for _, option := range []string{"-r", "--requirement", "-c", "--constraint"} {
+		switch {
+		case fields[0] == option && len(fields) > 1:
+			return fields[1], true
+		case strings.HasPrefix(fields[0], option+"=") && strings.HasPrefix(option, "--"):
+			return strings.TrimPrefix(fields[0], option+"="), true
+		case strings.HasPrefix(fields[0], option) && len(option) == 2 && len(fields[0]) > 2:
+			// the short options may be glued to their value, e.g. -rbase.txt
+			return fields[0][2:], true
+		}
+	}
+	return "", false
+}
+
+// parseRequirement parses a requirement line split on blanks: the requirement, its environment
+// markers and its options, of which only the --hash ones are kept
+func parseRequirement(fields []string) (requirement, bool) {
+	var specifier []string
+	var r requirement
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "--hash" && i+1 < len(fields):
+			r.Hashes = append(r.Hashes, fields[i+1])
+			i++
+		case strings.HasPrefix(field, "--hash="):
+			r.Hashes = append(r.Hashes, strings.TrimPrefix(field, "--hash="))
+		case strings.HasPrefix(field, "-"):
+			// the per requirement options, e.g. --global-option, are not part of it
+		default:
+			specifier = append(specifier, field)
+		}
+	}
+
+	match := requirementRegex.FindStringSubmatch(strings.Join(specifier, " "))
+	if match == nil || strings.Contains(match[0], "://") {
+		return requirement{}, false
+	}
+	r.Name = match[1]
+	if version := strings.TrimSpace(match[2]); strings.HasPrefix(version, "==") && !strings.ContainsAny(version[2:], "*,<>!~=") {
+		r.Version = strings.TrimSpace(version[2:])
+	}
+	return r, true
+}
+
+// checksum returns the checksum of the pinned hashes matching the version of the installed package, the
+// one already known, e.g. from PyPI, when it is pinned and else the first one. nil when none applies
+func (r *requirement) checksum(version string, known *models.CheckSum) *models.CheckSum {
+	if r.Version != "" && r.Version != version {
+		return nil
+	}
+
+	var first *models.CheckSum
+	for _, hash := range r.Hashes {
+		parts := strings.SplitN(hash, ":", 2)
+		algorithm, ok := hashAlgorithms[parts[0]]
+		if !ok || len(parts) != 2 {
+			continue
+		}
+		checksum := &models.CheckSum{Algorithm: algorithm, Value: strings.ToLower(parts[1])}
+		if known != nil && known.Algorithm == checksum.Algorithm && strings.EqualFold(known.Value, checksum.Value) {
+			return checksum
+		}
+		if first == nil {
+			first = checksum
+		}
+	}
+	return first
+}
+
+// setRequirementHashes sets the checksums of the modules pinned with --hash by the requirements file
+func setRequirementHashes(modules []models.Module, requirements map[string]*requirement) {
+	for i := range modules {
+		if modules[i].Root {
+			continue
+		}
+		r, ok := requirements[worker.NormalizeName(modules[i].Name)]
+		if !ok {
+			continue
+		}
+		if checksum := r.checksum(modules[i].Version, modules[i].CheckSum); checksum != nil {
+			modules[i].CheckSum = checksum
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pyenv
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRequirements(t *testing.T) {
+	requirements, err := readRequirements("testdata/hashes/requirements.txt")
+	assert.NoError(t, err)
+	assert.Len(t, requirements, 5)
+
+	assert.Equal(t, "2.31.0", requirements["requests"].Version)
+	assert.Equal(t, []string{
+		"sha256:58CD2187C01E70E6E26505BCA751777AA9F2EE0B7F4300988B709F44E013003F",
+		"sha256:942c5a758f98d790eaed1a29cb6eefc7ffb0d1cf7af05c3d2791656dbd6ad1e1",
+	}, requirements["requests"].Hashes)
+	assert.Equal(t, "3.4", requirements["idna"].Version)
+	assert.Equal(t, []string{"sha384:0123456789abcdef"}, requirements["idna"].Hashes)
+
+	// the included requirements file and the constraints one
+	assert.Equal(t, "2023.7.22", requirements["certifi"].Version)
+	assert.Len(t, requirements["certifi"].Hashes, 1)
+	assert.Equal(t, "2.0.4", requirements["urllib3"].Version)
+	assert.Len(t, requirements["urllib3"].Hashes, 2)
+	assert.Equal(t, "3.2.0", requirements["charset-normalizer"].Version)
+	assert.Empty(t, requirements["charset-normalizer"].Hashes)
+}
+
+func TestReadRequirementsMissingInclude(t *testing.T) {
+	file, err := ioutil.TempFile("", "requirements-*.txt")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("-r missing.txt\n")
+	assert.NoError(t, err)
+	file.Close()
+
+	_, err = readRequirements(file.Name())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		line    string
+		name    string
+		version string
+		ok      bool
+	}{
+		{"Django==4.2.4", "Django", "4.2.4", true},
+		{"requests[socks] == 2.31.0", "requests", "2.31.0", true},
+		{"numpy>=1.24", "numpy", "", true},
+		{"numpy==1.24.*", "numpy", "", true},
+		{"idna==3.4 ; python_version >= '3.7'", "idna", "3.4", true},
+		{"https://example.com/pkg-1.0.tar.gz", "", "", false},
+	}
+	for _, test := range tests {
+		r, ok := parseRequirement(strings.Fields(test.line))
+		assert.Equal(t, test.ok, ok, test.line)
+		assert.Equal(t, test.name, r.Name, test.line)
+		assert.Equal(t, test.version, r.Version, test.line)
+	}
+}
+
+func TestSetRequirementHashes(t *testing.T) {
+	requirements, err := readRequirements("testdata/hashes/requirements.txt")
+	assert.NoError(t, err)
+
+	known := &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "942c5a758f98d790eaed1a29cb6eefc7ffb0d1cf7af05c3d2791656dbd6ad1e1"}
+	modules := []models.Module{
+		{Name: "app", Root: true},
+		{Name: "Requests", Version: "2.31.0", CheckSum: known},
+		{Name: "idna", Version: "3.4"},
+		{Name: "urllib3", Version: "1.26.16"},
+		{Name: "urllib3-extra", Version: "1.0"},
+		{Name: "charset_normalizer", Version: "3.2.0"},
+	}
+	setRequirementHashes(modules, requirements)
+
+	assert.Nil(t, modules[0].CheckSum)
+	// the checksum PyPI gave is kept when pinned
+	assert.Equal(t, known.Value, modules[1].CheckSum.Value)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA384, Value: "0123456789abcdef"}, modules[2].CheckSum)
+	// the hashes pin another version than the installed one
+	assert.Nil(t, modules[3].CheckSum)
+	assert.Nil(t, modules[4].CheckSum)
+	assert.Nil(t, modules[5].CheckSum)
+
+	modules[1].CheckSum = nil
+	setRequirementHashes(modules, requirements)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f"}, modules[1].CheckSum)
+}
@@ -21,12 +21,17 @@ type GetPackageDetailsFunc = func(PackageName string) (string, error)
 
 type MetadataDecoder struct {
 	getPkgDetailsFunc GetPackageDetailsFunc
+	offline           bool
+	matcher           helper.LicenseMatcher
 }
 
-// New Metadata Decoder ...
-func NewMetadataDecoder(pkgDetailsFunc GetPackageDetailsFunc) *MetadataDecoder {
+// New Metadata Decoder, the packages are not looked up on pypi.org offline and their license files are
+// identified by the matcher
+func NewMetadataDecoder(pkgDetailsFunc GetPackageDetailsFunc, offline bool, matcher helper.LicenseMatcher) *MetadataDecoder {
 	return &MetadataDecoder{
 		getPkgDetailsFunc: pkgDetailsFunc,
+		offline:           offline,
+		matcher:           matcher,
 	}
 }
 
@@ -129,6 +134,15 @@ func (d *MetadataDecoder) BuildMetadata(pkgs []Packages) (map[string]Metadata, [
 	return metainfo, metaList, nil
 }
 
+// BuildHomePage returns the Home-page of the package metadata, pip reports a missing one as None or UNKNOWN
+func BuildHomePage(homePage string) string {
+	switch strings.TrimSpace(homePage) {
+	case "None", "UNKNOWN", NoAssertion:
+		return ""
+	}
+	return strings.TrimSpace(homePage)
+}
+
 func (d *MetadataDecoder) BuildModule(metadata Metadata) models.Module {
 	var module models.Module
 
@@ -139,16 +153,19 @@ func (d *MetadataDecoder) BuildModule(metadata Metadata) models.Module {
 	module.Path = metadata.ProjectURL
 	module.LocalPath = metadata.LocalPath
 	module.PackageURL = metadata.PackageReleaseURL
-	module.PackageHomePage = metadata.HomePage
+	module.PackageHomePage = BuildHomePage(metadata.HomePage)
 	module.PackageComment = metadata.Description
 
 	if (metadata.Root) && (len(metadata.HomePage) > 0) && metadata.HomePage != "None" {
 		module.PackageURL = metadata.HomePage
 	}
 
-	pypiData, err := GetPackageDataFromPyPi(metadata.PackageJsonURL)
+	pypiData, err := GetPackageDataFromPyPi(metadata.PackageJsonURL, d.offline)
 	if err != nil {
-		log.Warnf("Unable to get `%s` package details from pypi.org", metadata.Name)
+		// offline the package is described by its installed metadata only
+		if !d.offline {
+			log.Warnf("Unable to get `%s` package details from pypi.org", metadata.Name)
+		}
 		if (len(metadata.HomePage) > 0) && (metadata.HomePage != "None") {
 			module.PackageURL = metadata.HomePage
 		}
@@ -186,7 +203,7 @@ func (d *MetadataDecoder) BuildModule(metadata Metadata) models.Module {
 	}
 
 	// Prepare licenses
-	licensePkg, err := helper.GetLicenses(metadata.DistInfoPath)
+	licensePkg, err := helper.GetLicenses(d.matcher, metadata.DistInfoPath)
 	if err == nil {
 		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
 		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
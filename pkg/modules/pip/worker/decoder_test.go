@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetadataHomePage(t *testing.T) {
+	var metadata Metadata
+	ParseMetadata(&metadata, "Name: requests\nVersion: 2.26.0\nHome-page: https://requests.readthedocs.io\nLicense: Apache 2.0\n")
+
+	assert.Equal(t, "requests", metadata.Name)
+	assert.Equal(t, "https://requests.readthedocs.io", metadata.HomePage)
+	assert.Equal(t, "https://requests.readthedocs.io", BuildHomePage(metadata.HomePage))
+}
+
+func TestBuildHomePage(t *testing.T) {
+	assert.Equal(t, "", BuildHomePage("None"))
+	assert.Equal(t, "", BuildHomePage("UNKNOWN"))
+	assert.Equal(t, "", BuildHomePage(NoAssertion))
+	assert.Equal(t, "https://github.com/psf/black", BuildHomePage(" https://github.com/psf/black "))
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"os"
 	"path"
+	"regexp"
 	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
@@ -35,6 +36,8 @@ const (
 	KeyRequires    string = "requires"
 )
 
+var nameSeparatorRegex = regexp.MustCompile(`[-_.]+`)
+
 var AuthorAnOrganizationKeywords = []string{"Authority", "Team", "Developers", "Services", "Foundation", "Software"}
 
 type Packages struct {
@@ -246,3 +249,8 @@ func GetWheelDistributionInfo(metadata *Metadata) (string, string, error) {
 
 	return generator, tag, nil
 }
+
+// NormalizeName returns the PEP 503 normalized name of a Python package, e.g. zope-interface of Zope.Interface
+func NormalizeName(name string) string {
+	return nameSeparatorRegex.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+}
@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "zope-interface", NormalizeName("Zope.Interface"))
+	assert.Equal(t, "flask-login", NormalizeName("Flask__Login"))
+	assert.Equal(t, "pysocks", NormalizeName("PySocks"))
+}
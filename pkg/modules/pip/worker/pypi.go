@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
@@ -81,7 +82,11 @@ var HashAlgoPickOrder []models.HashAlgorithm = []models.HashAlgorithm{
 	models.HashAlgoMD2,
 }
 
-func makeGetRequest(packageJsonUrl string) (*http.Response, error) {
+func makeGetRequest(packageJsonUrl string, offline bool) (*http.Response, error) {
+	if offline {
+		return nil, helper.ErrOffline
+	}
+
 	url := "https://" + packageJsonUrl
 
 	request, _ := http.NewRequest("GET", url, nil)
@@ -100,10 +105,10 @@ func makeGetRequest(packageJsonUrl string) (*http.Response, error) {
 	return response, err
 }
 
-func GetPackageDataFromPyPi(packageJsonUrl string) (PypiPackageData, error) {
+func GetPackageDataFromPyPi(packageJsonUrl string, offline bool) (PypiPackageData, error) {
 	packageInfo := PypiPackageData{}
 
-	response, err := makeGetRequest(packageJsonUrl)
+	response, err := makeGetRequest(packageJsonUrl, offline)
 	if err != nil {
 		return packageInfo, err
 	}
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound       = errors.New("unable to generate SPDX file, no pnpm-lock.yaml found. Please install the dependencies before running spdx-sbom-generator, e.g.: `pnpm install`")
+	errUnsupportedLockfileVersion = errors.New("unsupported pnpm lockfile version")
+)
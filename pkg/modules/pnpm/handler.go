@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+type pnpm struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	LockFile     string = "pnpm-lock.yaml"
+	ManifestFile string = "package.json"
+	NPMRegistry  string = "https://registry.npmjs.org"
+)
+
+// integrityAlgorithms are the hash algorithms of the subresource integrity strings
+var integrityAlgorithms = map[string]models.HashAlgorithm{
+	"sha1":   models.HashAlgoSHA1,
+	"sha256": models.HashAlgoSHA256,
+	"sha384": models.HashAlgoSHA384,
+	"sha512": models.HashAlgoSHA512,
+}
+
+// New creates a new pnpm instance
+func New() *pnpm {
+	return &pnpm{
+		metadata: models.PluginMetadata{
+			Name:       "pnpm Package Manager",
+			Slug:       "pnpm",
+			Manifest:   []string{ManifestFile, LockFile},
+			ModulePath: []string{"node_modules"},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *pnpm) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the pnpm version
+func (m *pnpm) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "pnpm", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *pnpm) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *pnpm) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the package.json as the root package
+func (m *pnpm) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mod, err := m.manifestModule(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if mod.Name == "" {
+		mod.Name = filepath.Base(absPath)
+	}
+	mod.Root = true
+	mod.LocalPath = absPath
+	return &mod, nil
+}
+
+// ListUsedModules returns the packages of the lockfile, without the project itself
+func (m *pnpm) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the root package, the workspace packages it contains and the packages
+// of the lockfile, each linked to the packages it depends on
+func (m *pnpm) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := readLockfile(filepath.Join(path, LockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []models.Module{*root}
+	importers := map[string]int{rootImporter: 0}
+	for _, dir := range lock.ImporterPaths {
+		if dir == rootImporter {
+			continue
+		}
+		workspace, err := m.manifestModule(filepath.Join(root.LocalPath, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, err
+		}
+		if workspace.Name == "" {
+			workspace.Name = filepath.Base(dir)
+		}
+		importers[dir] = len(modules)
+		modules = append(modules, workspace)
+		helper.LinkModule(modules, 0, importers[dir], models.RelationshipContains)
+	}
+
+	packages := map[string]int{}
+	for _, id := range lock.PackageIDs {
+		packages[id] = len(modules)
+		modules = append(modules, m.packageModule(root.LocalPath, lock.Packages[id]))
+	}
+
+	for _, dir := range lock.ImporterPaths {
+		for _, dependency := range lock.Importers[dir] {
+			relationship := models.ScopeRelationship(dependency.Scope)
+			if linked, ok := workspaceLink(dir, dependency); ok {
+				// the root contains its workspace packages, the relationship is kept to tell them apart
+				if index, ok := importers[linked]; ok && importers[dir] != 0 {
+					helper.LinkModule(modules, importers[dir], index, relationship)
+				}
+				continue
+			}
+			if id, ok := lock.packageID(dependency); ok {
+				helper.LinkModule(modules, importers[dir], packages[id], relationship)
+			}
+		}
+	}
+	for _, id := range lock.PackageIDs {
+		for _, dependency := range lock.Packages[id].Dependencies {
+			if dependencyID, ok := lock.packageID(dependency); ok {
+				helper.LinkModule(modules, packages[id], packages[dependencyID], models.RelationshipDependsOn)
+			}
+		}
+	}
+
+	return modules, nil
+}
+
+// IsValid checks if the package.json and the pnpm-lock.yaml exist
+func (m *pnpm) IsValid(path string) bool {
+	for _, manifest := range m.metadata.Manifest {
+		if !helper.Exists(filepath.Join(path, manifest)) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasModulesInstalled checks the dependencies are locked, installing them only adds their licenses
+func (m *pnpm) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+func readLockfile(path string) (*lockfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseLockfile(path, file)
+}
+
+// manifestModule returns the package of the package.json of dir, with the license of its files
+func (m *pnpm) manifestModule(dir string) (models.Module, error) {
+	manifest, err := reader.New(filepath.Join(dir, ManifestFile)).ReadJson()
+	if err != nil {
+		return models.Module{}, err
+	}
+
+	name, _ := manifest["name"].(string)
+	version, _ := manifest["version"].(string)
+	mod := models.Module{
+		Name:       name,
+		Version:    version,
+		PackageURL: packageURL(name, version),
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(fmt.Sprintf("%s-%s", name, version)),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	mod.PackageDownloadLocation = repositoryURL(manifest["repository"])
+	m.setPackageFiles(&mod, dir, manifest)
+	return mod, nil
+}
+
+// packageModule returns the package of the lockfile, its licenses are read from node_modules when installed
+func (m *pnpm) packageModule(path string, pkg *lockPackage) models.Module {
+	mod := models.Module{
+		Name:                    pkg.Name,
+		Version:                 pkg.Version,
+		PackageURL:              packageURL(pkg.Name, pkg.Version),
+		PackageDownloadLocation: downloadLocation(pkg),
+		CheckSum:                integrityChecksum(pkg.Integrity),
+		Modules:                 map[string]*models.Module{},
+	}
+
+	if dir := installedPackageDirectory(path, pkg); dir != "" {
+		if manifest, err := reader.New(filepath.Join(dir, ManifestFile)).ReadJson(); err == nil {
+			m.setPackageFiles(&mod, dir, manifest)
+		}
+	}
+	return mod
+}
+
+// setPackageFiles sets the home page, copyright and license of the module from its package.json
+// and the license files of its directory
+func (m *pnpm) setPackageFiles(mod *models.Module, dir string, manifest map[string]interface{}) {
+	if homepage, ok := manifest["homepage"].(string); ok {
+		mod.PackageHomePage = homepage
+	}
+	for _, name := range []string{"LICENSE", "LICENSE.md", "license", "license.md"} {
+		if content := reader.New(filepath.Join(dir, name)).StringFromFile(); content != "" {
+			mod.Copyright = helper.GetCopyright(content)
+			break
+		}
+	}
+
+	helper.SetLicense(m.options.LicenseMatcher, dir, mod)
+}
+
+// installedPackageDirectory returns the directory pnpm installed the package to in its virtual store,
+// e.g. node_modules/.pnpm/@babel+core@7.14.0/node_modules/@babel/core, "" when it is not installed
+func installedPackageDirectory(path string, pkg *lockPackage) string {
+	store := strings.ReplaceAll(pkg.Name, "/", "+") + "@" + pkg.Version
+	matches, _ := filepath.Glob(filepath.Join(path, "node_modules", ".pnpm", store+"*"))
+	for _, match := range matches {
+		// the peer dependency variants are suffixed by _ or (, another version may share the prefix
+		suffix := strings.TrimPrefix(filepath.Base(match), store)
+		if suffix != "" && !strings.HasPrefix(suffix, "_") && !strings.HasPrefix(suffix, "(") {
+			continue
+		}
+		if dir := filepath.Join(match, "node_modules", filepath.FromSlash(pkg.Name)); helper.Exists(dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// integrityChecksum returns the checksum of a subresource integrity string, e.g. sha512-<base64>,
+// nil when there is none
+func integrityChecksum(integrity string) *models.CheckSum {
+	for _, value := range strings.Fields(integrity) {
+		parts := strings.SplitN(value, "-", 2)
+		algorithm, ok := integrityAlgorithms[parts[0]]
+		if !ok || len(parts) != 2 {
+			continue
+		}
+		digest, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			continue
+		}
+		return &models.CheckSum{Algorithm: algorithm, Value: hex.EncodeToString(digest)}
+	}
+	return nil
+}
+
+// downloadLocation returns the tarball of the package: the one of its resolution, its git repository
+// at the locked commit or the one the npm registry serves it at
+func downloadLocation(pkg *lockPackage) string {
+	switch {
+	case pkg.Tarball != "":
+		return pkg.Tarball
+	case pkg.Repo != "":
+		return fmt.Sprintf("git+%s@%s", pkg.Repo, pkg.Commit)
+	}
+	base := pkg.Name[strings.LastIndex(pkg.Name, "/")+1:]
+	return fmt.Sprintf("%s/%s/-/%s-%s.tgz", NPMRegistry, pkg.Name, base, pkg.Version)
+}
+
+// packageURL returns the pkg:npm purl of the package, the scope is its namespace
+func packageURL(name, version string) string {
+	if name == "" {
+		return ""
+	}
+	namespace := ""
+	if i := strings.Index(name, "/"); i > 0 && strings.HasPrefix(name, "@") {
+		namespace, name = name[:i], name[i+1:]
+	}
+	return purl.New("npm", namespace, name, version).String()
+}
+
+// repositoryURL returns the url of the repository field of a package.json, a string or an object
+func repositoryURL(repository interface{}) string {
+	switch value := repository.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		url, _ := value["url"].(string)
+		return url
+	}
+	return ""
+}
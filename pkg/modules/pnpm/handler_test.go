@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+// the integrities of the fixtures are the sha512 of the package ids
+func fixtureChecksum(id string) *models.CheckSum {
+	digest := sha512.Sum512([]byte(id))
+	return &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: hex.EncodeToString(digest[:])}
+}
+
+func modulesByID(modules []models.Module) map[string]models.Module {
+	result := map[string]models.Module{}
+	for _, mod := range modules {
+		result[mod.Name+"@"+mod.Version] = mod
+	}
+	return result
+}
+
+func TestIsValid(t *testing.T) {
+	n := New()
+	assert.True(t, n.IsValid(filepath.Join("testdata", "v6")))
+	assert.False(t, n.IsValid("testdata"))
+	assert.NoError(t, n.HasModulesInstalled(filepath.Join("testdata", "v5")))
+	assert.Equal(t, errDependenciesNotFound, n.HasModulesInstalled("testdata"))
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "v5"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 7)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "pnpm-app", root.Name)
+	assert.Equal(t, models.RelationshipDependsOn, root.Modules["react-dom"].Relationship)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["typescript"].Relationship)
+
+	byID := modulesByID(modules)
+	reactDOM := byID["react-dom@17.0.2"]
+	assert.Equal(t, "pkg:npm/react-dom@17.0.2", reactDOM.PackageURL)
+	assert.Equal(t, "https://registry.npmjs.org/react-dom/-/react-dom-17.0.2.tgz", reactDOM.PackageDownloadLocation)
+	assert.Equal(t, fixtureChecksum("react-dom@17.0.2"), reactDOM.CheckSum)
+	assert.Len(t, reactDOM.Modules, 3)
+	assert.Equal(t, "0.20.2", reactDOM.Modules["scheduler"].Version)
+	assert.Equal(t, []string{"js-tokens"}, modulestest.LinkedNames(byID["loose-envify@1.4.0"]))
+}
+
+func TestListWorkspaceModules(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "v6"))
+	assert.NoError(t, err)
+
+	root := modules[0]
+	assert.Equal(t, "pnpm-workspace", root.Name)
+	assert.Equal(t, "https://github.com/example/pnpm-workspace.git", root.PackageDownloadLocation)
+	assert.Equal(t, []string{"lib", "react-dom", "typescript"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipContains, root.Modules["lib"].Relationship)
+
+	byID := modulesByID(modules)
+	lib := byID["lib@0.1.0"]
+	assert.Equal(t, []string{"is-number", "ms"}, modulestest.LinkedNames(lib))
+	assert.Equal(t, "7.0.0", lib.Modules["is-number"].Version)
+
+	isNumber := byID["is-number@7.0.0"]
+	assert.Equal(t, "https://codeload.github.com/jonschlinkert/is-number/tar.gz/98e8ff1", isNumber.PackageDownloadLocation)
+	assert.Nil(t, isNumber.CheckSum)
+
+	// react-dom is installed in the virtual store
+	reactDOM := byID["react-dom@17.0.2"]
+	assert.Equal(t, "https://reactjs.org/", reactDOM.PackageHomePage)
+	assert.Equal(t, "MIT", reactDOM.LicenseDeclared)
+	assert.Equal(t, "Copyright (c) Facebook, Inc. and its affiliates.", reactDOM.Copyright)
+	assert.Empty(t, byID["react@17.0.2"].LicenseDeclared)
+}
+
+func TestListScopedAndAliasedModules(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "v9"))
+	assert.NoError(t, err)
+
+	root := modules[0]
+	assert.Equal(t, "pkg:npm/%40example/pnpm9-app@3.0.0", root.PackageURL)
+	assert.Equal(t, []string{"ms", "react-dom"}, modulestest.LinkedNames(root))
+	assert.Equal(t, fixtureChecksum("ms@2.1.3"), root.Modules["ms"].CheckSum)
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// rootImporter is the importer of the project directory
+const rootImporter = "."
+
+// dependencyScopes are the dependency fields of the importers, the scope of their dependencies
+var dependencyScopes = []struct {
+	field string
+	scope string
+}{
+	{"dependencies", ""},
+	{"devDependencies", "dev"},
+	{"optionalDependencies", "optional"},
+}
+
+// lockDependency is a dependency of an importer or of a package, Ref is the version pnpm resolved it
+// to, e.g. 17.0.2(react@17.0.2), a link:../lib for the workspace packages
+type lockDependency struct {
+	Name  string
+	Ref   string
+	Scope string
+}
+
+// lockPackage is a package of the lockfile, the entries of its peer dependency variants are merged
+type lockPackage struct {
+	ID        string
+	Name      string
+	Version   string
+	Integrity string
+	Tarball   string
+	// Repo and Commit are set for the packages resolved from git
+	Repo         string
+	Commit       string
+	Dependencies []lockDependency
+}
+
+// lockfile is a pnpm-lock.yaml, whatever its lockfile version
+type lockfile struct {
+	Version string
+	// Importers are the dependencies of the project and its workspace packages keyed by their directory
+	Importers     map[string][]lockDependency
+	ImporterPaths []string
+	// Packages are keyed by their id, name@version
+	Packages   map[string]*lockPackage
+	PackageIDs []string
+	// ids are the package ids keyed by the lockfile keys, without their leading slash
+	ids map[string]string
+}
+
+// parseLockfile reads a pnpm-lock.yaml of lockfile version 5 (pnpm 6 and 7), 6 (pnpm 8) or 9 (pnpm 9)
+func parseLockfile(fileName string, r io.Reader) (*lockfile, error) {
+	document, err := reader.ParseYAML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &lockfile{
+		Version:   document.Str("lockfileVersion"),
+		Importers: map[string][]lockDependency{},
+		Packages:  map[string]*lockPackage{},
+		ids:       map[string]string{},
+	}
+	switch l.major() {
+	case "5", "6", "9":
+	default:
+		return nil, fmt.Errorf("%w: %q in %s", errUnsupportedLockfileVersion, l.Version, fileName)
+	}
+
+	if importers := document.Get("importers"); importers != nil {
+		for _, dir := range importers.Keys {
+			l.addImporter(dir, importers.Get(dir))
+		}
+	} else {
+		// single project lockfiles list the dependencies of the project at the top level
+		l.addImporter(rootImporter, document)
+	}
+
+	packages := document.Get("packages")
+	// lockfile v9 keeps the dependencies of the packages in the snapshots, keyed with their peer dependencies
+	snapshots := document.Get("snapshots")
+	for _, entries := range []*reader.YAMLNode{packages, snapshots} {
+		for _, key := range fieldKeys(entries) {
+			if err := l.addPackage(key, entries.Get(key)); err != nil {
+				return nil, reader.MalformedError(fileName, entries.Get(key).Line, err.Error())
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// fieldKeys returns the keys of a mapping, none for a nil node
+func fieldKeys(n *reader.YAMLNode) []string {
+	if n == nil {
+		return nil
+	}
+	return n.Keys
+}
+
+// major returns the major lockfile version, e.g. 5 of 5.4
+func (l *lockfile) major() string {
+	return strings.SplitN(l.Version, ".", 2)[0]
+}
+
+func (l *lockfile) addImporter(dir string, importer *reader.YAMLNode) {
+	if _, ok := l.Importers[dir]; !ok {
+		l.ImporterPaths = append(l.ImporterPaths, dir)
+	}
+	l.Importers[dir] = append(l.Importers[dir], dependencies(importer)...)
+}
+
+func (l *lockfile) addPackage(key string, entry *reader.YAMLNode) error {
+	name, version := l.parseKey(key)
+	// the packages not resolved from a registry tell their name and version
+	if entry.Str("name") != "" {
+		name, version = entry.Str("name"), entry.Str("version")
+	}
+	if name == "" || version == "" {
+		return fmt.Errorf("package %s has no name or version", key)
+	}
+
+	id := name + "@" + version
+	l.ids[strings.TrimPrefix(key, "/")] = id
+	pkg, ok := l.Packages[id]
+	if !ok {
+		pkg = &lockPackage{ID: id, Name: name, Version: version}
+		l.Packages[id] = pkg
+		l.PackageIDs = append(l.PackageIDs, id)
+	}
+
+	resolution := entry.Get("resolution")
+	if integrity := resolution.Str("integrity"); integrity != "" {
+		pkg.Integrity = integrity
+	}
+	if tarball := resolution.Str("tarball"); tarball != "" {
+		pkg.Tarball = tarball
+	}
+	if repo := resolution.Str("repo"); repo != "" {
+		pkg.Repo, pkg.Commit = repo, resolution.Str("commit")
+	}
+
+	for _, dependency := range dependencies(entry) {
+		if !containsDependency(pkg.Dependencies, dependency) {
+			pkg.Dependencies = append(pkg.Dependencies, dependency)
+		}
+	}
+	return nil
+}
+
+// dependencies returns the dependencies of an importer or a package, their value is the version
+// pnpm resolved, or a mapping of the specifier and the version since lockfile v6
+func dependencies(entry *reader.YAMLNode) []lockDependency {
+	var result []lockDependency
+	for _, scope := range dependencyScopes {
+		field := entry.Get(scope.field)
+		for _, name := range fieldKeys(field) {
+			value := field.Get(name)
+			ref := value.Value
+			if version := value.Str("version"); version != "" {
+				ref = version
+			}
+			if ref == "" {
+				continue
+			}
+			result = append(result, lockDependency{Name: name, Ref: ref, Scope: scope.scope})
+		}
+	}
+	return result
+}
+
+func containsDependency(dependencies []lockDependency, dependency lockDependency) bool {
+	for _, d := range dependencies {
+		if d.Name == dependency.Name && d.Ref == dependency.Ref {
+			return true
+		}
+	}
+	return false
+}
+
+// parseKey returns the name and version of a package key without its peer dependencies, e.g.
+// /react-dom/17.0.2_react@17.0.2 (v5), /react-dom@17.0.2(react@17.0.2) (v6) or react-dom@17.0.2(react@17.0.2) (v9)
+func (l *lockfile) parseKey(key string) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if l.major() == "5" {
+		i := strings.LastIndex(key, "/")
+		if i < 0 {
+			return key, ""
+		}
+		return key[:i], strings.SplitN(key[i+1:], "_", 2)[0]
+	}
+
+	if i := strings.Index(key, "("); i > 0 {
+		key = key[:i]
+	}
+	if i := strings.LastIndex(key, "@"); i > 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+// packageID returns the id of the package a dependency resolves to, false for the workspace links
+// and the packages missing from the lockfile
+func (l *lockfile) packageID(dependency lockDependency) (string, bool) {
+	if strings.HasPrefix(dependency.Ref, "link:") {
+		return "", false
+	}
+
+	// the packages not resolved from a registry are referenced by their key, e.g. github.com/user/repo/abc123,
+	// as are the aliased dependencies, e.g. /string-width/4.2.3 or string-width@4.2.3
+	candidates := []string{dependency.Ref}
+	if l.major() == "5" {
+		candidates = append(candidates, dependency.Name+"/"+dependency.Ref)
+	} else {
+		candidates = append(candidates, dependency.Name+"@"+dependency.Ref)
+	}
+
+	for _, key := range candidates {
+		key = strings.TrimPrefix(key, "/")
+		if id, ok := l.ids[key]; ok {
+			return id, true
+		}
+		// the peer dependencies of lockfile v9 packages are only in the snapshot keys
+		name, version := l.parseKey(key)
+		if _, ok := l.Packages[name+"@"+version]; ok {
+			return name + "@" + version, true
+		}
+	}
+	return "", false
+}
+
+// workspaceLink returns the importer directory a link: dependency of the importer at dir points to
+func workspaceLink(dir string, dependency lockDependency) (string, bool) {
+	if !strings.HasPrefix(dependency.Ref, "link:") {
+		return "", false
+	}
+	return path.Clean(path.Join(dir, strings.TrimPrefix(dependency.Ref, "link:"))), true
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestParseLockfileVersions(t *testing.T) {
+	for _, version := range []string{"v5", "v6", "v9"} {
+		t.Run(version, func(t *testing.T) {
+			lock, err := readLockfile(filepath.Join("testdata", version, LockFile))
+			assert.NoError(t, err)
+
+			// the peer dependency variants are merged in the package they resolve
+			reactDOM := lock.Packages["react-dom@17.0.2"]
+			if assert.NotNil(t, reactDOM) {
+				assert.Equal(t, "react-dom", reactDOM.Name)
+				assert.True(t, strings.HasPrefix(reactDOM.Integrity, "sha512-"))
+				assert.Equal(t, []lockDependency{
+					{Name: "loose-envify", Ref: "1.4.0"},
+					{Name: "react", Ref: "17.0.2"},
+					{Name: "scheduler", Ref: "0.20.2"},
+				}, reactDOM.Dependencies)
+			}
+
+			for _, dependency := range lock.Importers[rootImporter] {
+				if dependency.Name == "react-dom" {
+					id, ok := lock.packageID(dependency)
+					assert.True(t, ok)
+					assert.Equal(t, "react-dom@17.0.2", id)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLockfileV5(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "v5", LockFile))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{rootImporter}, lock.ImporterPaths)
+	assert.Equal(t, []lockDependency{
+		{Name: "react", Ref: "17.0.2"},
+		{Name: "react-dom", Ref: "17.0.2_react@17.0.2"},
+		{Name: "typescript", Ref: "4.3.5", Scope: "dev"},
+	}, lock.Importers[rootImporter])
+	assert.Equal(t, []string{"js-tokens@4.0.0", "loose-envify@1.4.0", "react-dom@17.0.2", "react@17.0.2", "scheduler@0.20.2", "typescript@4.3.5"}, lock.PackageIDs)
+
+	id, ok := lock.packageID(lockDependency{Name: "react-dom", Ref: "17.0.2_react@17.0.2"})
+	assert.True(t, ok)
+	assert.Equal(t, "react-dom@17.0.2", id)
+}
+
+func TestParseLockfileWorkspace(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "v6", LockFile))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{rootImporter, "packages/lib"}, lock.ImporterPaths)
+
+	dir, ok := workspaceLink(rootImporter, lock.Importers[rootImporter][0])
+	assert.True(t, ok)
+	assert.Equal(t, "packages/lib", dir)
+	_, ok = lock.packageID(lock.Importers[rootImporter][0])
+	assert.False(t, ok)
+
+	// the git dependencies are keyed by their location, their name and version are fields
+	isNumber := lock.Importers["packages/lib"][0]
+	id, ok := lock.packageID(isNumber)
+	assert.True(t, ok)
+	assert.Equal(t, "is-number@7.0.0", id)
+	assert.Equal(t, "https://codeload.github.com/jonschlinkert/is-number/tar.gz/98e8ff1", lock.Packages[id].Tarball)
+}
+
+func TestParseLockfileAlias(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "v9", LockFile))
+	assert.NoError(t, err)
+
+	alias := lock.Importers[rootImporter][1]
+	assert.Equal(t, lockDependency{Name: "tiny-ms", Ref: "ms@2.1.3"}, alias)
+	id, ok := lock.packageID(alias)
+	assert.True(t, ok)
+	assert.Equal(t, "ms@2.1.3", id)
+}
+
+func TestParseUnsupportedLockfile(t *testing.T) {
+	_, err := parseLockfile("pnpm-lock.yaml", strings.NewReader("lockfileVersion: 4.0\n"))
+	assert.True(t, errors.Is(err, errUnsupportedLockfileVersion))
+
+	_, err = parseLockfile("pnpm-lock.yaml", strings.NewReader("lockfileVersion: '6.0'\npackages:\n  /ms:\n    dev: false\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file pnpm-lock.yaml: line 3: package /ms has no name or version")
+}
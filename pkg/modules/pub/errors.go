@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no pubspec.lock found. Please get the packages before running spdx-sbom-generator, e.g.: `dart pub get` or `flutter pub get`")
+)
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type pub struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ManifestFile string = "pubspec.yaml"
+	LockFile     string = "pubspec.lock"
+)
+
+// gitRevision is a sha1 commit hash
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// New creates a new pub instance
+func New() *pub {
+	return &pub{
+		metadata: models.PluginMetadata{
+			Name:       "Pub Package Manager",
+			Slug:       "pub",
+			Manifest:   []string{ManifestFile, LockFile},
+			ModulePath: []string{".dart_tool"},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *pub) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the dart version
+func (m *pub) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "dart", "--version").CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *pub) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *pub) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the pubspec.yaml, named after its directory when it has no name
+func (m *pub) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return rootModule(absPath, m.options.LicenseMatcher), nil
+}
+
+// ListUsedModules returns the packages of the project, without the project itself
+func (m *pub) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the packages of its pubspec.lock. The packages
+// are linked to the ones their pubspec.yaml requires when the pub cache holds them
+func (m *pub) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(absPath, LockFile)
+	if !helper.Exists(lockPath) {
+		return nil, errDependenciesNotFound
+	}
+	lock, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	return lockModules(absPath, rootModule(absPath, m.options.LicenseMatcher), lock, pubCache(), m.options.LicenseMatcher), nil
+}
+
+// IsValid checks if a pubspec.yaml or a pubspec.lock exists
+func (m *pub) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ManifestFile)) || helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks the packages are locked, the pub cache is only read for the licenses and
+// the dependencies between the packages
+func (m *pub) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// rootModule returns the package of the project, with the license of the project files
+func rootModule(path string, matcher helper.LicenseMatcher) *models.Module {
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+	if spec, err := readPubspec(filepath.Join(path, ManifestFile)); err == nil && spec.Name != "" {
+		mod.Name = spec.Name
+		mod.Version = spec.Version
+		mod.PackageURL = purl.New("pub", "", spec.Name, spec.Version).String()
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicense(matcher, path, mod)
+	return mod
+}
+
+// lockModules returns the root followed by the packages of the lockfile. The root depends on its direct
+// main and overridden packages and its direct dev ones are DEV_DEPENDENCY_OF it; a transitive package no
+// cached package requires is linked to the root as the lockfile does not tell which package requires it
+func lockModules(path string, root *models.Module, lock *pubspecLock, cache string, matcher helper.LicenseMatcher) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	dirs := map[int]string{}
+	for _, pkg := range lock.Packages {
+		i := len(modules)
+		index[pkg.Name] = i
+		mod := packageModule(path, pkg)
+		for _, dir := range cachedPackageDirs(cache, pkg) {
+			if helper.Exists(dir) {
+				mod.LocalPath = dir
+				break
+			}
+		}
+		if mod.LocalPath != "" && helper.Exists(mod.LocalPath) {
+			helper.SetLicense(matcher, mod.LocalPath, &mod)
+			dirs[i] = mod.LocalPath
+		}
+		modules = append(modules, mod)
+	}
+
+	required := map[int]bool{}
+	for i, dir := range dirs {
+		spec, err := readPubspec(filepath.Join(dir, ManifestFile))
+		if err != nil {
+			continue
+		}
+		for _, name := range spec.Dependencies {
+			if j, ok := index[name]; ok {
+				helper.LinkModule(modules, i, j, "")
+				required[j] = true
+			}
+		}
+	}
+
+	for i, pkg := range lock.Packages {
+		switch {
+		case pkg.Dependency == dependencyDev:
+			helper.LinkModule(modules, 0, i+1, models.RelationshipDevDependencyOf)
+		case pkg.isDirect():
+			helper.LinkModule(modules, 0, i+1, "")
+		case !required[i+1]:
+			modules[i+1].Unresolved = append(modules[i+1].Unresolved, "the package is a transitive dependency, the package requiring it is unknown")
+			helper.LinkModule(modules, 0, i+1, "")
+		}
+	}
+	return modules
+}
+
+// packageModule returns the package of a locked package: a hosted one is downloaded from its package
+// repository, with the sha256 hash of its archive, a git one from its repository at the locked commit
+// and a path one is built from a directory
+func packageModule(path string, pkg *lockedPackage) models.Module {
+	p := purl.New("pub", "", pkg.Name, pkg.Version)
+	mod := models.Module{
+		Name:                    pkg.Name,
+		Version:                 pkg.Version,
+		Supplier:                models.SupplierContact{Name: pkg.Name},
+		PackageDownloadLocation: "NOASSERTION",
+		Modules:                 map[string]*models.Module{},
+	}
+
+	switch pkg.Source {
+	case sourceHosted:
+		if isPubDev(pkg.URL) {
+			mod.PackageDownloadLocation = fmt.Sprintf("%s/api/archives/%s-%s.tar.gz", pubDevURL, pkg.Name, pkg.Version)
+			mod.PackageHomePage = fmt.Sprintf("%s/packages/%s", pubDevURL, pkg.Name)
+		} else {
+			p = p.WithQualifier("repository_url", strings.TrimSuffix(pkg.URL, "/"))
+		}
+		if pkg.SHA256 != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: pkg.SHA256}
+		}
+	case sourceGit:
+		vcs := fmt.Sprintf("git+%s", pkg.URL)
+		if pkg.ResolvedRef != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, pkg.ResolvedRef)
+		}
+		p = p.WithQualifier("vcs_url", vcs)
+		mod.PackageDownloadLocation = vcs
+		if pkg.Path != "" && pkg.Path != "." {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is in the directory %s of its repository", pkg.Path))
+		}
+		if gitRevision.MatchString(pkg.ResolvedRef) {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: pkg.ResolvedRef}
+		}
+	case sourcePath:
+		mod.LocalPath = filepath.FromSlash(pkg.Path)
+		if pkg.Relative || !filepath.IsAbs(mod.LocalPath) {
+			mod.LocalPath = filepath.Join(path, mod.LocalPath)
+		}
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is built from the directory %s", pkg.Path))
+	case sourceSDK:
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is provided by the %s sdk", pkg.SDK))
+	}
+	if pkg.Dependency == dependencyOverridden {
+		mod.Annotations = append(mod.Annotations, "the version is set by the dependency_overrides of the project")
+	}
+	mod.PackageURL = p.String()
+	return mod
+}
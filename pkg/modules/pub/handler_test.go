@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/modulestest"
+)
+
+func TestIsValid(t *testing.T) {
+	m := New()
+	assert.True(t, m.IsValid(filepath.Join("testdata", "app")))
+	assert.False(t, m.IsValid("testdata"))
+	assert.Equal(t, errDependenciesNotFound, m.HasModulesInstalled(filepath.Join("testdata", "local_widgets")))
+}
+
+func TestLockModules(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("testdata", "app"))
+	assert.NoError(t, err)
+	lock, err := readLockfile(filepath.Join(path, LockFile))
+	assert.NoError(t, err)
+
+	modules := lockModules(path, rootModule(path, nil), lock, filepath.Join("testdata", "pub-cache"), nil)
+	if !assert.Len(t, modules, 9) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "weather_app", root.Name)
+	assert.Equal(t, "1.4.0+12", root.Version)
+	assert.Equal(t, []string{"acme_auth", "charts", "flutter", "http", "lints", "local_widgets"}, modulestest.LinkedNames(root))
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["lints"].Relationship)
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	http := byName["http"]
+	assert.Equal(t, "pkg:pub/http@1.1.0", http.PackageURL)
+	assert.Equal(t, "https://pub.dev/api/archives/http-1.1.0.tar.gz", http.PackageDownloadLocation)
+	assert.Equal(t, models.HashAlgoSHA256, http.CheckSum.Algorithm)
+	assert.Equal(t, "759d1a329847dd0f39226c688d3e06a6b8679668e350e2891a6474f8b4bb8355", http.CheckSum.Value)
+	assert.Equal(t, "MIT", http.LicenseDeclared)
+	assert.Equal(t, []string{"async"}, modulestest.LinkedNames(http))
+	assert.Equal(t, []string{"the version is set by the dependency_overrides of the project"}, http.Annotations)
+	assert.Equal(t, []string{"collection"}, modulestest.LinkedNames(byName["async"]))
+	assert.Empty(t, byName["async"].Annotations)
+
+	collection := byName["collection"]
+	assert.Equal(t, "https://pub.dev/api/archives/collection-1.17.2.tar.gz", collection.PackageDownloadLocation)
+
+	auth := byName["acme_auth"]
+	assert.Equal(t, "pkg:pub/acme_auth@2.0.1?repository_url=https:%2F%2Fpub.acme.example.com", auth.PackageURL)
+	assert.Equal(t, "NOASSERTION", auth.PackageDownloadLocation)
+
+	charts := byName["charts"]
+	assert.Equal(t, "git+https://github.com/acme/charts.git@8d2f7c1e0b9a4d3c6e5f8a7b0c9d2e1f4a3b6c5d", charts.PackageDownloadLocation)
+	assert.Equal(t, models.HashAlgoSHA1, charts.CheckSum.Algorithm)
+	assert.Len(t, charts.Annotations, 1)
+
+	widgets := byName["local_widgets"]
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "local_widgets"), widgets.LocalPath)
+	assert.Equal(t, []string{"collection"}, modulestest.LinkedNames(widgets))
+
+	assert.Equal(t, []string{"the package is provided by the flutter sdk"}, byName["flutter"].Annotations)
+}
+
+func TestLockModulesWithoutCache(t *testing.T) {
+	lock := &pubspecLock{Packages: []*lockedPackage{
+		{Name: "async", Version: "2.11.0", Source: sourceHosted, Dependency: dependencyTransitive, URL: pubDevURL},
+	}}
+	modules := lockModules(t.TempDir(), rootModule(t.TempDir(), nil), lock, "", nil)
+	assert.Equal(t, []string{"async"}, modulestest.LinkedNames(modules[0]))
+	assert.Equal(t, []string{"the package is a transitive dependency, the package requiring it is unknown"}, modules[1].Unresolved)
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the sources of a locked package
+const (
+	sourceHosted = "hosted"
+	sourceGit    = "git"
+	sourcePath   = "path"
+	sourceSDK    = "sdk"
+)
+
+// the kinds of dependency of a locked package, a direct one is required by the pubspec.yaml of the project
+const (
+	dependencyMain       = "direct main"
+	dependencyDev        = "direct dev"
+	dependencyOverridden = "direct overridden"
+	dependencyTransitive = "transitive"
+)
+
+// pubDevURL is the default package repository
+const pubDevURL = "https://pub.dev"
+
+// lockedPackage is a package of a pubspec.lock
+type lockedPackage struct {
+	Name       string
+	Version    string
+	Source     string
+	Dependency string
+	// URL is the package repository of a hosted package and the repository of a git one
+	URL string
+	// SHA256 is the content hash of the archive of a hosted package
+	SHA256 string
+	// Path is the directory of a path package and the directory of a git one in its repository
+	Path     string
+	Relative bool
+	// ResolvedRef is the commit of a git package, Ref the one the pubspec.yaml requires
+	ResolvedRef string
+	Ref         string
+	// SDK is the sdk providing an sdk package, e.g. flutter
+	SDK string
+}
+
+// pubspecLock is a pubspec.lock, its packages are sorted by name
+type pubspecLock struct {
+	Packages []*lockedPackage
+	// SDKs are the constraints of the sdks the packages require, e.g. dart: ">=3.0.0 <4.0.0"
+	SDKs map[string]string
+}
+
+func readLockfile(path string) (*pubspecLock, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseLockfile(path, file)
+}
+
+// parseLockfile reads a pubspec.lock, the description of a package is a mapping but for the sdk ones
+// where it names the sdk:
+//
+//	packages:
+//	  async:
+//	    dependency: transitive
+//	    description:
+//	      name: async
+//	      sha256: "947bfcf1..."
+//	      url: "https://pub.dev"
+//	    source: hosted
+//	    version: "2.11.0"
+func parseLockfile(fileName string, r io.Reader) (*pubspecLock, error) {
+	document, err := reader.ParseYAML(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &pubspecLock{SDKs: map[string]string{}}
+	packages := document.Get("packages")
+	for _, name := range packages.Keys {
+		entry := packages.Fields[name]
+		pkg := &lockedPackage{
+			Name:       name,
+			Version:    entry.Str("version"),
+			Source:     entry.Str("source"),
+			Dependency: entry.Str("dependency"),
+		}
+		if pkg.Source == "" || pkg.Version == "" {
+			return nil, reader.MalformedError(fileName, entry.Line, fmt.Sprintf("package %s has no source or version", name))
+		}
+
+		description := entry.Get("description")
+		switch {
+		case description == nil:
+		case description.Fields == nil:
+			pkg.SDK = description.Value
+		default:
+			if descriptionName := description.Str("name"); descriptionName != "" {
+				pkg.Name = descriptionName
+			}
+			pkg.URL = description.Str("url")
+			pkg.SHA256 = description.Str("sha256")
+			pkg.Path = description.Str("path")
+			pkg.Relative = description.Str("relative") == "true"
+			pkg.ResolvedRef = description.Str("resolved-ref")
+			pkg.Ref = description.Str("ref")
+		}
+		if pkg.Source == sourceHosted && pkg.URL == "" {
+			pkg.URL = pubDevURL
+		}
+		lock.Packages = append(lock.Packages, pkg)
+	}
+
+	sdks := document.Get("sdks")
+	for _, sdk := range sdks.Keys {
+		lock.SDKs[sdk] = sdks.Fields[sdk].Value
+	}
+	return lock, nil
+}
+
+// isDirect tells whether the pubspec.yaml of the project requires the package
+func (p *lockedPackage) isDirect() bool {
+	return strings.HasPrefix(p.Dependency, "direct ")
+}
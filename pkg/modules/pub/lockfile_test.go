@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadLockfile(t *testing.T) {
+	lock, err := readLockfile(filepath.Join("testdata", "app", LockFile))
+	assert.NoError(t, err)
+	if !assert.Len(t, lock.Packages, 8) {
+		return
+	}
+
+	auth := lock.Packages[0]
+	assert.Equal(t, "acme_auth", auth.Name)
+	assert.Equal(t, "2.0.1", auth.Version)
+	assert.Equal(t, sourceHosted, auth.Source)
+	assert.Equal(t, dependencyMain, auth.Dependency)
+	assert.Equal(t, "https://pub.acme.example.com", auth.URL)
+	assert.True(t, auth.isDirect())
+
+	charts := lock.Packages[2]
+	assert.Equal(t, sourceGit, charts.Source)
+	assert.Equal(t, "packages/charts", charts.Path)
+	assert.Equal(t, "8d2f7c1e0b9a4d3c6e5f8a7b0c9d2e1f4a3b6c5d", charts.ResolvedRef)
+	assert.Equal(t, "main", charts.Ref)
+
+	assert.Equal(t, "f092b211a4319e98e5ff58223576de6c2803db36221657b46c82574721240687", lock.Packages[3].SHA256)
+	assert.False(t, lock.Packages[3].isDirect())
+	assert.Equal(t, "flutter", lock.Packages[4].SDK)
+	assert.True(t, lock.Packages[7].Relative)
+	assert.Equal(t, ">=3.10.0", lock.SDKs["flutter"])
+}
+
+func TestParseLockfileMalformed(t *testing.T) {
+	_, err := parseLockfile(LockFile, strings.NewReader("packages:\n  async:\n    dependency: transitive\n"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestReadPubspec(t *testing.T) {
+	spec, err := readPubspec(filepath.Join("testdata", "app", ManifestFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "weather_app", spec.Name)
+	assert.Equal(t, "1.4.0+12", spec.Version)
+	assert.Equal(t, []string{"flutter", "http", "charts", "local_widgets", "acme_auth"}, spec.Dependencies)
+	assert.Equal(t, []string{"lints"}, spec.DevDependencies)
+}
+
+func TestCachedPackageDirs(t *testing.T) {
+	cache := filepath.Join("testdata", "pub-cache")
+	hosted := &lockedPackage{Name: "http", Version: "1.1.0", Source: sourceHosted, URL: "https://pub.dartlang.org"}
+	assert.Equal(t, []string{
+		filepath.Join(cache, "hosted", "pub.dev", "http-1.1.0"),
+		filepath.Join(cache, "hosted", "pub.dartlang.org", "http-1.1.0"),
+	}, cachedPackageDirs(cache, hosted))
+
+	git := &lockedPackage{Name: "charts", Source: sourceGit, URL: "https://github.com/acme/charts.git", Path: "packages/charts", ResolvedRef: "8d2f7c1e"}
+	assert.Equal(t, []string{filepath.Join(cache, "git", "charts-8d2f7c1e", "packages", "charts")}, cachedPackageDirs(cache, git))
+	assert.Nil(t, cachedPackageDirs(cache, &lockedPackage{Source: sourceSDK}))
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// legacyPubDevHost is the host pub.dev was served from, the pub cache of older pub versions is named after it
+const legacyPubDevHost = "pub.dartlang.org"
+
+// pubspec is the part of a pubspec.yaml the packages are listed with
+type pubspec struct {
+	Name    string
+	Version string
+	// Dependencies are the names of the packages it requires, the dev ones are only required by a project
+	Dependencies    []string
+	DevDependencies []string
+}
+
+// readPubspec reads the name, the version and the dependencies of a pubspec.yaml. The file may hold
+// YAML pub does not write in its lockfiles, e.g. the block scalar of a description, so only the top
+// level fields and the keys of the dependencies mappings are read
+func readPubspec(fileName string) (*pubspec, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	spec := &pubspec{}
+	section, sectionIndent := "", -1
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := strings.TrimRight(reader.StripYAMLComment(scanner.Text()), " \r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" {
+			continue
+		}
+		indent := len(text) - len(trimmed)
+		key, value, ok := reader.SplitYAMLField(trimmed)
+
+		if indent == 0 {
+			section, sectionIndent = "", -1
+			if !ok {
+				continue
+			}
+			switch key {
+			case "name":
+				spec.Name = reader.UnquoteYAML(value)
+			case "version":
+				spec.Version = reader.UnquoteYAML(value)
+			case "dependencies", "dev_dependencies":
+				section = key
+			}
+			continue
+		}
+
+		if section == "" || !ok {
+			continue
+		}
+		// the packages are the keys of the first nested level, their constraints may be nested below
+		if sectionIndent < 0 {
+			sectionIndent = indent
+		}
+		if indent != sectionIndent {
+			continue
+		}
+		if section == "dependencies" {
+			spec.Dependencies = append(spec.Dependencies, reader.UnquoteYAML(key))
+		} else {
+			spec.DevDependencies = append(spec.DevDependencies, reader.UnquoteYAML(key))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// pubCache returns the directory pub downloads the packages to: PUB_CACHE, else the one of the platform
+func pubCache() string {
+	if cache := os.Getenv("PUB_CACHE"); cache != "" {
+		return cache
+	}
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("LOCALAPPDATA"); appData != "" {
+			return filepath.Join(appData, "Pub", "Cache")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pub-cache")
+}
+
+// cachedPackageDirs returns the directories of the pub cache a hosted or a git package may be
+// extracted to, nil for the other sources
+func cachedPackageDirs(cache string, pkg *lockedPackage) []string {
+	if cache == "" {
+		return nil
+	}
+
+	switch pkg.Source {
+	case sourceHosted:
+		u, err := url.Parse(pkg.URL)
+		if err != nil || u.Host == "" {
+			return nil
+		}
+		hosts := []string{u.Host}
+		if isPubDev(pkg.URL) {
+			hosts = []string{"pub.dev", legacyPubDevHost}
+		}
+		var dirs []string
+		for _, host := range hosts {
+			dirs = append(dirs, filepath.Join(cache, "hosted", host, pkg.Name+"-"+pkg.Version))
+		}
+		return dirs
+	case sourceGit:
+		if pkg.ResolvedRef == "" {
+			return nil
+		}
+		// pub checks a repository out to git/<repository name>-<commit>
+		repository := strings.TrimSuffix(path.Base(strings.TrimSuffix(pkg.URL, "/")), ".git")
+		dir := filepath.Join(cache, "git", repository+"-"+pkg.ResolvedRef)
+		if pkg.Path != "" && pkg.Path != "." {
+			dir = filepath.Join(dir, filepath.FromSlash(pkg.Path))
+		}
+		return []string{dir}
+	}
+	return nil
+}
+
+// isPubDev tells whether the package repository is pub.dev, by its current or its legacy host
+func isPubDev(repository string) bool {
+	u, err := url.Parse(repository)
+	if err != nil {
+		return false
+	}
+	return u.Host == "pub.dev" || u.Host == legacyPubDevHost
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package renv
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// rLicenses maps the licenses of the R license database to SPDX ids, the versioned GPL licenses are
+// the "only" ones but for the >= ones
+var rLicenses = map[string]string{
+	"AGPL-3":                  "AGPL-3.0-only",
+	"AGPL (>= 3)":             "AGPL-3.0-or-later",
+	"Apache License 2.0":      "Apache-2.0",
+	"Apache License (== 2.0)": "Apache-2.0",
+	"Apache License (>= 2)":   "Apache-2.0",
+	"Artistic-2.0":            "Artistic-2.0",
+	"Artistic License 2.0":    "Artistic-2.0",
+	"BSD_2_clause":            "BSD-2-Clause",
+	"BSD_3_clause":            "BSD-3-Clause",
+	"BSL-1.0":                 "BSL-1.0",
+	"CC0":                     "CC0-1.0",
+	"CC BY 4.0":               "CC-BY-4.0",
+	"CC BY-SA 4.0":            "CC-BY-SA-4.0",
+	"GPL-2":                   "GPL-2.0-only",
+	"GPL-3":                   "GPL-3.0-only",
+	"GPL (>= 2)":              "GPL-2.0-or-later",
+	"GPL (>= 2.0)":            "GPL-2.0-or-later",
+	"GPL (>= 3)":              "GPL-3.0-or-later",
+	"GPL (>= 3.0)":            "GPL-3.0-or-later",
+	"LGPL-2":                  "LGPL-2.0-only",
+	"LGPL-2.1":                "LGPL-2.1-only",
+	"LGPL-3":                  "LGPL-3.0-only",
+	"LGPL (>= 2)":             "LGPL-2.0-or-later",
+	"LGPL (>= 2.1)":           "LGPL-2.1-or-later",
+	"LGPL (>= 3)":             "LGPL-3.0-or-later",
+	"MIT":                     "MIT",
+	"MPL-2.0":                 "MPL-2.0",
+}
+
+// licenseFile is the file a license of the R license database is completed with, e.g. MIT + file LICENSE
+var licenseFile = regexp.MustCompile(`\s*\+\s*file\s+LICEN[CS]E\s*$`)
+
+// dependencyVersion is the version requirement of a dependency of a DESCRIPTION, e.g. (>= 1.0.0)
+var dependencyVersion = regexp.MustCompile(`\([^)]*\)`)
+
+// description is a DESCRIPTION file, the fields of its continuation lines are joined with a space
+type description map[string]string
+
+// readDescription reads a DESCRIPTION file, its fields are in the Debian control format:
+//
+//	Package: shop
+//	Imports:
+//	    dplyr (>= 1.1.0),
+//	    R6
+func readDescription(path string) (description, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	paragraphs, err := reader.ReadParagraphs(path, file)
+	if err != nil {
+		return nil, err
+	}
+	fields := description{}
+	if len(paragraphs) > 0 {
+		for field, value := range paragraphs[0] {
+			fields[field] = strings.Replace(value, "\n", " ", -1)
+		}
+	}
+	return fields, nil
+}
+
+// dependencies returns the packages of a dependency field, e.g. Imports, without their version requirements
+func (d description) dependencies(field string) []string {
+	var packages []string
+	for _, dependency := range strings.Split(dependencyVersion.ReplaceAllString(d[field], ""), ",") {
+		if dependency = strings.TrimSpace(dependency); dependency != "" {
+			packages = append(packages, dependency)
+		}
+	}
+	return packages
+}
+
+// maintainer returns the maintainer of the package, e.g. Jane Doe <jane@example.com>
+func (d description) maintainer() (models.SupplierContact, bool) {
+	maintainer := strings.TrimSpace(d["Maintainer"])
+	if maintainer == "" {
+		return models.SupplierContact{}, false
+	}
+	if i := strings.Index(maintainer, "<"); i >= 0 && strings.HasSuffix(maintainer, ">") {
+		return models.SupplierContact{
+			Name:  strings.TrimSpace(maintainer[:i]),
+			Email: maintainer[i+1 : len(maintainer)-1],
+			Type:  models.Person,
+		}, true
+	}
+	return models.SupplierContact{Name: maintainer, Type: models.Person}, true
+}
+
+// licenseExpression returns the SPDX expression of an R license, e.g. GPL-2 | GPL-3 or MIT + file LICENSE,
+// "" when one of its alternatives has no SPDX id
+func licenseExpression(license string) string {
+	var parts []string
+	for _, alternative := range strings.Split(license, "|") {
+		alternative = strings.TrimSpace(licenseFile.ReplaceAllString(alternative, ""))
+		if alternative == "" {
+			continue
+		}
+		id, ok := rLicenses[alternative]
+		if !ok {
+			id = helper.SPDXExpression(alternative)
+		}
+		if id == "" || strings.Contains(id, " ") {
+			return ""
+		}
+		parts = append(parts, id)
+	}
+	return strings.Join(parts, " OR ")
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package renv
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadDescription(t *testing.T) {
+	desc, err := readDescription(filepath.Join("testdata", "app", DescriptionFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "shop", desc["Package"])
+	assert.Equal(t, `person("Jane", "Doe", , "jane@example.com", role = c("aut", "cre"))`, desc["Authors@R"])
+	assert.Equal(t, []string{"shopr"}, desc.dependencies("Imports"))
+	assert.Equal(t, []string{"BiocGenerics"}, desc.dependencies("Suggests"))
+	assert.Empty(t, desc.dependencies("Depends"))
+
+	path := filepath.Join(t.TempDir(), DescriptionFile)
+	assert.NoError(t, ioutil.WriteFile(path, []byte("  shop\n"), 0644))
+	_, err = readDescription(path)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
+
+func TestLicenseExpression(t *testing.T) {
+	assert.Equal(t, "MIT", licenseExpression("MIT + file LICENSE"))
+	assert.Equal(t, "GPL-2.0-or-later", licenseExpression("GPL (>= 2)"))
+	assert.Equal(t, "GPL-2.0-only OR GPL-3.0-only", licenseExpression("GPL-2 | GPL-3"))
+	assert.Equal(t, "", licenseExpression("file LICENSE"))
+	assert.Equal(t, "", licenseExpression("MIT | Unlimited"))
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package renv
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no renv.lock found. Please snapshot the project library before running spdx-sbom-generator, e.g.: `Rscript -e 'renv::snapshot()'`")
+)
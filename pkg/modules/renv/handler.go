@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package renv
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type renv struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	LockFile        string = "renv.lock"
+	DescriptionFile string = "DESCRIPTION"
+)
+
+// LibraryDir is the project library renv installs the packages to, by platform and R version
+var LibraryDir = filepath.Join("renv", "library")
+
+const (
+	cranURL         = "https://cran.r-project.org"
+	bioconductorURL = "https://bioconductor.org"
+)
+
+// gitRevision is a sha1 commit hash
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// New creates a new renv instance
+func New() *renv {
+	return &renv{
+		metadata: models.PluginMetadata{
+			Name:       "R renv",
+			Slug:       "renv",
+			Manifest:   []string{LockFile, DescriptionFile},
+			ModulePath: []string{LibraryDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *renv) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the R version
+func (m *renv) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "R", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *renv) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *renv) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the DESCRIPTION of the project, named after its directory when it has none
+func (m *renv) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := rootModule(absPath, m.options.LicenseMatcher)
+	return root, nil
+}
+
+// ListUsedModules returns the packages of the project, without the project itself
+func (m *renv) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the packages of its renv.lock, linked to the
+// packages they require
+func (m *renv) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(absPath, LockFile)
+	if !helper.Exists(lockPath) {
+		return nil, errDependenciesNotFound
+	}
+	lock, packages, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, desc := rootModule(absPath, m.options.LicenseMatcher)
+	return lockModules(root, desc, lock, packages, installedPackages(filepath.Join(absPath, LibraryDir))), nil
+}
+
+// IsValid checks if a renv.lock exists, a DESCRIPTION alone is the one of a package without a project library
+func (m *renv) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks the packages are locked, the project library is only read for the licenses
+func (m *renv) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// rootModule returns the package of the DESCRIPTION of the project, with its license else the one of the
+// project files
+func rootModule(path string, matcher helper.LicenseMatcher) (*models.Module, description) {
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		Modules:                 map[string]*models.Module{},
+	}
+
+	desc, err := readDescription(filepath.Join(path, DescriptionFile))
+	if err != nil {
+		desc = nil
+	}
+	if name := desc["Package"]; name != "" {
+		mod.Name = name
+		mod.Version = desc["Version"]
+		mod.PackageURL = purl.New("cran", "", mod.Name, mod.Version).String()
+		if supplier, ok := desc.maintainer(); ok {
+			mod.Supplier = supplier
+		}
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicenseExpression(mod, licenseExpression(desc["License"]))
+	if mod.LicenseDeclared == "" {
+		helper.SetLicense(matcher, path, mod)
+	}
+	return mod, desc
+}
+
+// lockModules returns the root followed by the packages of the lockfile. The root depends on the packages
+// its DESCRIPTION depends on and imports, the ones it links to are BUILD_DEPENDENCY_OF it and the ones it
+// suggests OPTIONAL_DEPENDENCY_OF it. Without a DESCRIPTION the root depends on the packages no package requires
+func lockModules(root *models.Module, desc description, lock *renvLock, packages []*lockedPackage, installed map[string]string) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	for _, pkg := range packages {
+		index[pkg.Package] = len(modules)
+		modules = append(modules, packageModule(lock, pkg, installed[pkg.Package]))
+	}
+
+	required := map[int]bool{}
+	for i, pkg := range packages {
+		for _, name := range pkg.Requirements {
+			if j, ok := index[name]; ok {
+				helper.LinkModule(modules, i+1, j, "")
+				required[j] = true
+			}
+		}
+	}
+
+	linked := false
+	for _, field := range []struct {
+		name         string
+		relationship models.RelationshipType
+	}{
+		{"Depends", ""},
+		{"Imports", ""},
+		{"LinkingTo", models.RelationshipBuildDependencyOf},
+		{"Suggests", models.RelationshipOptionalDependencyOf},
+	} {
+		for _, name := range desc.dependencies(field.name) {
+			i, ok := index[name]
+			if !ok {
+				continue
+			}
+			linked = true
+			if _, ok := modules[0].Modules[name]; !ok {
+				helper.LinkModule(modules, 0, i, field.relationship)
+			}
+		}
+	}
+	if !linked {
+		for i := 1; i < len(modules); i++ {
+			if !required[i] {
+				helper.LinkModule(modules, 0, i, "")
+			}
+		}
+	}
+	return modules
+}
+
+// packageModule returns the package of a locked package: a repository one is downloaded from the source
+// tarball of its repository, a Bioconductor one from the one of its release and a remote one from its
+// repository at the locked commit. The checksum is the sha1 commit of a remote package, else the md5 hash
+// renv computes from its DESCRIPTION
+func packageModule(lock *renvLock, pkg *lockedPackage, installedDir string) models.Module {
+	mod := models.Module{
+		Name:                    pkg.Package,
+		Version:                 pkg.Version,
+		Supplier:                models.SupplierContact{Name: pkg.Package},
+		PackageDownloadLocation: "NOASSERTION",
+		Modules:                 map[string]*models.Module{},
+	}
+	if pkg.Hash != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: pkg.Hash}
+	}
+
+	p := purl.New("cran", "", pkg.Package, pkg.Version)
+	switch {
+	case pkg.Source == sourceBioconductor || strings.HasPrefix(pkg.Repository, "BioC"):
+		p = purl.New("bioconductor", "", pkg.Package, pkg.Version)
+		mod.PackageHomePage = fmt.Sprintf("%s/packages/%s", bioconductorURL, pkg.Package)
+		if release := lock.Bioconductor.Version; release != "" {
+			mod.PackageDownloadLocation = fmt.Sprintf("%s/packages/%s/bioc/src/contrib/%s_%s.tar.gz", bioconductorURL, release, pkg.Package, pkg.Version)
+		} else if pkg.GitURL != "" && pkg.GitLastCommit != "" {
+			mod.PackageDownloadLocation = fmt.Sprintf("git+%s@%s", pkg.GitURL, pkg.GitLastCommit)
+		}
+	case pkg.Source == sourceRepository:
+		repository := strings.TrimSuffix(lock.repositoryURL(pkg.Repository), "/")
+		if pkg.Repository == "CRAN" {
+			mod.PackageHomePage = fmt.Sprintf("https://CRAN.R-project.org/package=%s", pkg.Package)
+			if repository == "" {
+				repository = cranURL
+			}
+		} else if repository != "" {
+			p = p.WithQualifier("repository_url", repository)
+		}
+		if repository != "" {
+			mod.PackageDownloadLocation = fmt.Sprintf("%s/src/contrib/%s_%s.tar.gz", repository, pkg.Package, pkg.Version)
+		}
+	case pkg.Source == sourceGitHub || pkg.Source == sourceGitLab || pkg.Source == sourceBitbucket || pkg.Source == sourceGit:
+		repository := pkg.remoteRepository()
+		vcs := fmt.Sprintf("git+%s", repository)
+		if pkg.RemoteSha != "" {
+			vcs = fmt.Sprintf("%s@%s", vcs, pkg.RemoteSha)
+		}
+		if pkg.Source == sourceGitHub && pkg.RemoteUsername != "" && pkg.RemoteRepo != "" {
+			// the github purls are lowercase
+			p = purl.New("github", strings.ToLower(pkg.RemoteUsername), strings.ToLower(pkg.RemoteRepo), pkg.RemoteSha)
+		} else {
+			p = purl.New("generic", "", pkg.Package, pkg.Version).WithQualifier("vcs_url", vcs)
+		}
+		mod.PackageDownloadLocation = vcs
+		if gitRevision.MatchString(pkg.RemoteSha) {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: pkg.RemoteSha}
+		}
+		if pkg.RemoteSubdir != "" {
+			mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is in the directory %s of its repository", pkg.RemoteSubdir))
+		}
+	case pkg.Source == sourceLocal:
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is installed from %s", pkg.RemoteURL))
+	default:
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is installed from an unknown source %s", pkg.Source))
+	}
+	mod.PackageURL = p.String()
+
+	license, maintainer := pkg.License, pkg.Maintainer
+	if installedDir != "" {
+		mod.LocalPath = installedDir
+		if desc, err := readDescription(filepath.Join(installedDir, DescriptionFile)); err == nil {
+			if license == "" {
+				license = desc["License"]
+			}
+			if maintainer == "" {
+				maintainer = desc["Maintainer"]
+			}
+		}
+	}
+	helper.SetLicenseExpression(&mod, licenseExpression(license))
+	if supplier, ok := (description{"Maintainer": maintainer}).maintainer(); ok {
+		mod.Supplier = supplier
+	}
+	return mod
+}
+
+// remoteRepository returns the repository of a remote package, e.g. https://github.com/r-lib/cli
+func (p *lockedPackage) remoteRepository() string {
+	if p.Source == sourceGit || p.RemoteURL != "" {
+		return p.RemoteURL
+	}
+
+	host := p.RemoteHost
+	switch p.Source {
+	case sourceGitHub:
+		if host == "" || host == "api.github.com" {
+			host = "github.com"
+		}
+	case sourceGitLab:
+		if host == "" {
+			host = "gitlab.com"
+		}
+	case sourceBitbucket:
+		if host == "" || host == "api.bitbucket.org/2.0" {
+			host = "bitbucket.org"
+		}
+	}
+	return fmt.Sprintf("https://%s/%s/%s", host, p.RemoteUsername, p.RemoteRepo)
+}
+
+// installedPackages returns the directories of the packages of the project library, keyed by name. The
+// library is laid out as <R version>/<platform>/<package>, older renv versions prefix it with the platform
+func installedPackages(library string) map[string]string {
+	installed := map[string]string{}
+	for _, pattern := range []string{
+		filepath.Join(library, "*", "*", "*", DescriptionFile),
+		filepath.Join(library, "*", "*", "*", "*", DescriptionFile),
+	} {
+		files, _ := filepath.Glob(pattern)
+		for _, file := range files {
+			dir := filepath.Dir(file)
+			if _, ok := installed[filepath.Base(dir)]; !ok {
+				installed[filepath.Base(dir)] = dir
+			}
+		}
+	}
+	return installed
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package renv
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "app")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 4) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "GPL-2.0-only OR GPL-3.0-only", root.LicenseDeclared)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+	relationships := map[string]models.RelationshipType{}
+	for name, mod := range root.Modules {
+		relationships[name] = mod.Relationship
+	}
+	assert.Equal(t, map[string]models.RelationshipType{
+		"shopr":        "",
+		"R6":           models.RelationshipBuildDependencyOf,
+		"BiocGenerics": models.RelationshipOptionalDependencyOf,
+	}, relationships)
+
+	biocGenerics := modules[1]
+	assert.Equal(t, "pkg:bioconductor/BiocGenerics@0.46.0", biocGenerics.PackageURL)
+	assert.Equal(t, "https://bioconductor.org/packages/3.17/bioc/src/contrib/BiocGenerics_0.46.0.tar.gz", biocGenerics.PackageDownloadLocation)
+	assert.Equal(t, "https://bioconductor.org/packages/BiocGenerics", biocGenerics.PackageHomePage)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: "0cb7b2c3d2e4c9b5a2b4d2b8b2c1a6f0"}, biocGenerics.CheckSum)
+	assert.Empty(t, biocGenerics.Modules)
+
+	r6 := modules[2]
+	assert.Equal(t, "pkg:cran/R6@2.5.1", r6.PackageURL)
+	assert.Equal(t, "https://cloud.r-project.org/src/contrib/R6_2.5.1.tar.gz", r6.PackageDownloadLocation)
+	assert.Equal(t, "https://CRAN.R-project.org/package=R6", r6.PackageHomePage)
+	assert.Equal(t, "MIT", r6.LicenseDeclared)
+	assert.Equal(t, "Person: Winston Chang (winston@stdout.org)", r6.Supplier.Get())
+
+	shopr := modules[3]
+	assert.Equal(t, "pkg:github/example-org/shopr@4f3c9b1e2d6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c", shopr.PackageURL)
+	assert.Equal(t, "git+https://github.com/Example-Org/shopR@4f3c9b1e2d6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c", shopr.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "4f3c9b1e2d6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c"}, shopr.CheckSum)
+	assert.Equal(t, []string{"the package is in the directory pkg of its repository"}, shopr.Annotations)
+	assert.Len(t, shopr.Modules, 1)
+	assert.Contains(t, shopr.Modules, "R6")
+}
+
+func TestHasModulesInstalled(t *testing.T) {
+	assert.Equal(t, errDependenciesNotFound, New().HasModulesInstalled("testdata"))
+	_, err := New().ListModulesWithDeps("testdata")
+	assert.Equal(t, errDependenciesNotFound, err)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package renv
+
+import (
+	"io/ioutil"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the sources of a locked package
+const (
+	sourceRepository   = "Repository"
+	sourceBioconductor = "Bioconductor"
+	sourceGitHub       = "GitHub"
+	sourceGitLab       = "GitLab"
+	sourceBitbucket    = "Bitbucket"
+	sourceGit          = "git"
+	sourceLocal        = "Local"
+)
+
+// renvLock is a renv.lock
+type renvLock struct {
+	R struct {
+		Version      string `json:"Version"`
+		Repositories []struct {
+			Name string `json:"Name"`
+			URL  string `json:"URL"`
+		} `json:"Repositories"`
+	} `json:"R"`
+	Bioconductor struct {
+		Version string `json:"Version"`
+	} `json:"Bioconductor"`
+	Packages map[string]*lockedPackage `json:"Packages"`
+}
+
+// lockedPackage is a package of a renv.lock, the fields of a remote one are the ones of its DESCRIPTION
+type lockedPackage struct {
+	Package string `json:"Package"`
+	Version string `json:"Version"`
+	Source  string `json:"Source"`
+	// Repository is the name of the repository of a Repository package, e.g. CRAN
+	Repository string `json:"Repository"`
+	// Hash is the md5 hash renv computes from the DESCRIPTION of the package
+	Hash         string   `json:"Hash"`
+	Requirements []string `json:"Requirements"`
+	License      string   `json:"License"`
+	Maintainer   string   `json:"Maintainer"`
+
+	RemoteType     string `json:"RemoteType"`
+	RemoteHost     string `json:"RemoteHost"`
+	RemoteUsername string `json:"RemoteUsername"`
+	RemoteRepo     string `json:"RemoteRepo"`
+	RemoteRef      string `json:"RemoteRef"`
+	RemoteSha      string `json:"RemoteSha"`
+	RemoteSubdir   string `json:"RemoteSubdir"`
+	RemoteURL      string `json:"RemoteUrl"`
+
+	GitURL        string `json:"git_url"`
+	GitLastCommit string `json:"git_last_commit"`
+}
+
+// readLockfile reads a renv.lock, the packages are returned sorted by name
+func readLockfile(path string) (*renvLock, []*lockedPackage, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lock := &renvLock{}
+	if err := reader.DecodeJSON(path, data, lock); err != nil {
+		return nil, nil, err
+	}
+
+	packages := make([]*lockedPackage, 0, len(lock.Packages))
+	for name, pkg := range lock.Packages {
+		if pkg == nil {
+			continue
+		}
+		if pkg.Package == "" {
+			pkg.Package = name
+		}
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Package < packages[j].Package })
+	return lock, packages, nil
+}
+
+// repositoryURL returns the url of a repository of the lockfile, "" when it is not listed
+func (l *renvLock) repositoryURL(name string) string {
+	for _, repository := range l.R.Repositories {
+		if repository.Name == name {
+			return repository.URL
+		}
+	}
+	return ""
+}
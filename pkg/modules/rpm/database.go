@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rpm
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// none is what rpm prints for a tag the package does not have
+const none = "(none)"
+
+// queryFormat prints a line per package, its tags separated by tabs and the provided and required
+// capabilities separated by commas
+const queryFormat = `%{NAME}\t%{EPOCH}\t%{VERSION}\t%{RELEASE}\t%{ARCH}\t%{VENDOR}\t%{LICENSE}\t%{SOURCERPM}\t%{URL}\t%{SIGMD5}\t[%{PROVIDENAME},]\t[%{REQUIRENAME},]\n`
+
+// queryFields is the number of tags of a line of queryFormat
+const queryFields = 12
+
+var (
+	// databaseDirs are the directories of the rpm database, relative to the root filesystem. The newer
+	// distributions keep it in /usr, e.g. Fedora 36 and openSUSE Tumbleweed
+	databaseDirs = []string{
+		filepath.Join("var", "lib", "rpm"),
+		filepath.Join("usr", "lib", "sysimage", "rpm"),
+	}
+	// databaseFiles are the files of the Berkeley DB, ndb and sqlite backends of the rpm database
+	databaseFiles = []string{"Packages", "Packages.db", "rpmdb.sqlite"}
+)
+
+// queryDatabase runs rpm -qa against the database directory, replaced by the tests
+var queryDatabase = runQuery
+
+// rpmPackage is a package the rpm database lists as installed
+type rpmPackage struct {
+	Name    string
+	Epoch   string
+	Version string
+	Release string
+	Arch    string
+	Vendor  string
+	License string
+	// SourceRPM is the source package the package is built from, e.g. bash-5.2.15-3.fc38.src.rpm
+	SourceRPM string
+	URL       string
+	// SigMD5 is the md5 digest of the header and the payload of the package
+	SigMD5   string
+	Provides []string
+	Requires []string
+}
+
+// version returns the version of the package in the form rpm compares it, [epoch:]version-release
+func (p *rpmPackage) version() string {
+	version := p.Version
+	if p.Release != "" {
+		version = fmt.Sprintf("%s-%s", version, p.Release)
+	}
+	if p.Epoch != "" {
+		version = fmt.Sprintf("%s:%s", p.Epoch, version)
+	}
+	return version
+}
+
+// key identifies the package among the installed ones, a package may be installed for several architectures
+func (p *rpmPackage) key() string {
+	return fmt.Sprintf("%s.%s", p.Name, p.Arch)
+}
+
+// databaseDir returns the directory of the rpm database of the root filesystem
+func databaseDir(root string) (string, bool) {
+	for _, dir := range databaseDirs {
+		for _, file := range databaseFiles {
+			if helper.Exists(filepath.Join(root, dir, file)) {
+				return filepath.Join(root, dir), true
+			}
+		}
+	}
+	return "", false
+}
+
+// runQuery lists the packages of the database directory with rpm, killed once ctx is done, the database is read in place rather
+// than from the root filesystem rpm would chroot to
+func runQuery(ctx context.Context, dbPath string) (string, error) {
+	if _, err := exec.LookPath("rpm"); err != nil {
+		return "", errNoRpmCommand
+	}
+	output, err := helper.CommandContext(ctx, "rpm", "--dbpath", dbPath, "-qa", "--queryformat", queryFormat).Output()
+	if err != nil {
+		return "", fmt.Errorf("rpm -qa failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// readDatabase returns the packages installed to the root filesystem, sorted by name and architecture
+func readDatabase(ctx context.Context, root string) ([]*rpmPackage, error) {
+	dir, ok := databaseDir(root)
+	if !ok {
+		return nil, errDatabaseNotFound
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	output, err := queryDatabase(ctx, absDir)
+	if err != nil {
+		return nil, err
+	}
+	return parseQuery(output)
+}
+
+// parseQuery parses the output of rpm -qa in the queryFormat. The gpg-pubkey pseudo packages of the
+// imported signing keys are left out
+func parseQuery(output string) ([]*rpmPackage, error) {
+	var packages []*rpmPackage
+	for number, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != queryFields {
+			return nil, reader.MalformedError("rpm -qa", number+1, fmt.Sprintf("expected %d fields, got %d", queryFields, len(fields)))
+		}
+		for i := range fields {
+			if fields[i] == none {
+				fields[i] = ""
+			}
+		}
+		if fields[0] == "gpg-pubkey" {
+			continue
+		}
+
+		packages = append(packages, &rpmPackage{
+			Name:      fields[0],
+			Epoch:     fields[1],
+			Version:   fields[2],
+			Release:   fields[3],
+			Arch:      fields[4],
+			Vendor:    fields[5],
+			License:   fields[6],
+			SourceRPM: fields[7],
+			URL:       fields[8],
+			SigMD5:    fields[9],
+			Provides:  splitCapabilities(fields[10]),
+			Requires:  splitCapabilities(fields[11]),
+		})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].key() < packages[j].key() })
+	return packages, nil
+}
+
+// splitCapabilities splits the capabilities of a package, the rpmlib ones are features of rpm itself and
+// the rich dependencies, e.g. (foo if bar), are left out
+func splitCapabilities(field string) []string {
+	var capabilities []string
+	for _, capability := range strings.Split(field, ",") {
+		capability = strings.TrimSpace(capability)
+		if capability == "" || capability == none || strings.HasPrefix(capability, "rpmlib(") || strings.HasPrefix(capability, "(") {
+			continue
+		}
+		capabilities = append(capabilities, capability)
+	}
+	return capabilities
+}
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rpm
+
+import (
+	"errors"
+)
+
+var (
+	errDatabaseNotFound = errors.New("unable to generate SPDX file, no rpm database found. Please point --root to the root filesystem the RPM packages are installed to")
+	errNoRpmCommand     = errors.New("cannot find the rpm command, it reads the rpm database")
+)
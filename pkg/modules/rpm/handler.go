@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rpm
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+type rpm struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+// New creates a new rpm instance
+func New() *rpm {
+	var manifest []string
+	for _, dir := range databaseDirs {
+		for _, file := range databaseFiles {
+			manifest = append(manifest, filepath.Join(dir, file))
+		}
+	}
+	return &rpm{
+		metadata: models.PluginMetadata{
+			Name:       "RPM Packages",
+			Slug:       "rpm",
+			Manifest:   manifest,
+			ModulePath: databaseDirs,
+		},
+	}
+}
+
+// GetMetadata returns the plugin metadata
+func (m *rpm) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetOptions sets the root filesystem the packages are read from
+func (m *rpm) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the version of the rpm command the database is read with, e.g. RPM version 4.18.1
+func (m *rpm) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "rpm", "--version").Output()
+	if err != nil {
+		return "", errNoRpmCommand
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetRootModule sets root package information base on path given
+func (m *rpm) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the distribution of the root filesystem
+func (m *rpm) GetRootModule(path string) (*models.Module, error) {
+	root, _, err := helper.DistributionModule(m.root(path))
+	return root, err
+}
+
+// ListUsedModules returns the installed packages, without the distribution
+func (m *rpm) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the distribution followed by the installed packages, linked to the packages
+// providing what they require
+func (m *rpm) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, release, err := helper.DistributionModule(m.root(path))
+	if err != nil {
+		return nil, err
+	}
+	packages, err := readDatabase(m.options.Context, root.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	return packageModules(root, release, packages), nil
+}
+
+// IsValid checks if the root filesystem has an rpm database
+func (m *rpm) IsValid(path string) bool {
+	_, ok := databaseDir(m.root(path))
+	return ok
+}
+
+// HasModulesInstalled checks the root filesystem has an rpm database
+func (m *rpm) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errDatabaseNotFound
+}
+
+// root returns the root filesystem, the project path unless one is given
+func (m *rpm) root(path string) string {
+	if m.options.RootFS != "" {
+		return m.options.RootFS
+	}
+	return path
+}
+
+// packageModules returns the distribution followed by a package per installed package, the distribution
+// contains all of them. A requirement is linked to the first package providing it
+func packageModules(root *models.Module, release reader.OSRelease, packages []*rpmPackage) []models.Module {
+	modules := []models.Module{*root}
+	providers := map[string]int{}
+	for i, p := range packages {
+		modules = append(modules, packageModule(p, release))
+		for _, capability := range append([]string{p.Name}, p.Provides...) {
+			if _, ok := providers[capability]; !ok {
+				providers[capability] = i + 1
+			}
+		}
+	}
+
+	for i, p := range packages {
+		for _, capability := range p.Requires {
+			if j, ok := providers[capability]; ok {
+				helper.LinkModuleAs(modules, i+1, j, packages[j-1].key())
+			}
+		}
+	}
+	for i, p := range packages {
+		linked := modules[i+1]
+		linked.Relationship = models.RelationshipContains
+		modules[0].Modules[p.key()] = &linked
+	}
+	return modules
+}
+
+// packageModule returns the module of an installed package, its checksum is the md5 digest rpm verifies it with
+func packageModule(p *rpmPackage, release reader.OSRelease) models.Module {
+	distro := ""
+	if release.ID != "" && release.VersionID != "" {
+		distro = fmt.Sprintf("%s-%s", release.ID, release.VersionID)
+	}
+	version := p.Version
+	if p.Release != "" {
+		version = fmt.Sprintf("%s-%s", p.Version, p.Release)
+	}
+
+	mod := models.Module{
+		Name:    p.Name,
+		Version: p.version(),
+		PackageURL: purl.New("rpm", release.ID, p.Name, version).
+			WithQualifier("arch", p.Arch).
+			WithQualifier("epoch", p.Epoch).
+			WithQualifier("distro", distro).String(),
+		PackageDownloadLocation: "NOASSERTION",
+		PackageHomePage:         p.URL,
+		Supplier:                models.SupplierContact{Name: p.Vendor, Type: models.Organization},
+		Modules:                 map[string]*models.Module{},
+	}
+	if p.SigMD5 != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: p.SigMD5}
+	}
+	if p.SourceRPM != "" {
+		mod.SourceInfo = fmt.Sprintf("built from the source package %s", p.SourceRPM)
+	}
+	// the older distributions declare the licenses in the Fedora short names, e.g. GPLv2+
+	if expression := helper.SPDXExpression(p.License); expression != "" {
+		mod.LicenseDeclared = expression
+		mod.LicenseConcluded = expression
+	} else if p.License != "" {
+		mod.CommentsLicense = fmt.Sprintf("the package declares the license: %s", p.License)
+	}
+	return mod
+}
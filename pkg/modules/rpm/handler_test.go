@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rpm
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// stubQuery replaces rpm -qa with the output of the file
+func stubQuery(t *testing.T, fileName string) {
+	queryDatabase = func(ctx context.Context, dbPath string) (string, error) {
+		assert.True(t, filepath.IsAbs(dbPath))
+		output, err := ioutil.ReadFile(fileName)
+		return string(output), err
+	}
+	t.Cleanup(func() { queryDatabase = runQuery })
+}
+
+func moduleNames(modules map[string]*models.Module) []string {
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	return names
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	stubQuery(t, filepath.Join("testdata", "query.txt"))
+
+	m := New()
+	path := t.TempDir()
+	assert.False(t, m.IsValid(path))
+	assert.Equal(t, errDatabaseNotFound, m.HasModulesInstalled(path))
+
+	m.SetOptions(models.PluginOptions{RootFS: filepath.Join("testdata", "rootfs")})
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 6) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "fedora", root.Name)
+	assert.Equal(t, "38", root.Version)
+	assert.Equal(t, models.PurposeOperatingSystem, root.PrimaryPackagePurpose)
+	assert.Len(t, root.Modules, 5)
+
+	bash := modules[1]
+	assert.Equal(t, "bash", bash.Name)
+	assert.Equal(t, "5.2.15-3.fc38", bash.Version)
+	assert.Equal(t, "pkg:rpm/fedora/bash@5.2.15-3.fc38?arch=x86_64&distro=fedora-38", bash.PackageURL)
+	assert.Equal(t, models.SupplierContact{Name: "Fedora Project", Type: models.Organization}, bash.Supplier)
+	assert.Equal(t, "GPL-3.0-or-later", bash.LicenseDeclared)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: "5b3c4a0e1d3cdb6ad4d1f2f3c2f0e7a1"}, bash.CheckSum)
+	assert.Equal(t, "built from the source package bash-5.2.15-3.fc38.src.rpm", bash.SourceInfo)
+	// /bin/sh is a file, not a capability the packages provide
+	assert.ElementsMatch(t, []string{"filesystem", "glibc", "ncurses-libs"}, moduleNames(bash.Modules))
+
+	filesystem := modules[2]
+	assert.Nil(t, filesystem.CheckSum)
+	assert.Empty(t, filesystem.LicenseDeclared)
+	assert.Equal(t, "the package declares the license: Public Domain", filesystem.CommentsLicense)
+
+	glibc := modules[3]
+	assert.Equal(t, "LGPL-2.1-or-later AND GPL-2.0-or-later", glibc.LicenseDeclared)
+	assert.Equal(t, []string{"filesystem"}, moduleNames(glibc.Modules))
+
+	ncurses := modules[4]
+	assert.Equal(t, "0:6.4-3.20230114.fc38", ncurses.Version)
+	assert.Equal(t, "pkg:rpm/fedora/ncurses-libs@6.4-3.20230114.fc38?arch=x86_64&distro=fedora-38&epoch=0", ncurses.PackageURL)
+
+	openssl := modules[5]
+	assert.Equal(t, "1:3.0.9-2.fc38", openssl.Version)
+	assert.Equal(t, "the package declares the license: ASL 2.0", openssl.CommentsLicense)
+}
+
+func TestParseQueryMalformed(t *testing.T) {
+	_, err := parseQuery("bash\t5.2.15\n")
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"regexp"
+	"strings"
+)
+
+// buildSettingRegex matches the project settings of a build.sbt assigned a string literal,
+// e.g. ThisBuild / version := "0.1.0"
+var buildSettingRegex = regexp.MustCompile(`(?m)^\s*(?:ThisBuild\s*/\s*)?(name|organization|version|scalaVersion)\s*:=\s*"([^"]*)"`)
+
+// buildValRegex matches the string vals of a build.sbt, versions are often declared once in a val
+var buildValRegex = regexp.MustCompile(`(?m)^\s*(?:lazy\s+)?val\s+(\w+)\s*=\s*"([^"]*)"`)
+
+// libraryDependencyRegex matches a "group" % "artifact" % "version" module, %% cross builds it for the
+// Scala version, and its optional configuration, e.g. % Test. The version may be a val
+var libraryDependencyRegex = regexp.MustCompile(`"([^"\s]+)"\s*(%%?)\s*"([^"\s]+)"\s*%\s*("[^"\s]+"|\w+)(?:\s*%\s*("?\w+"?))?`)
+
+// buildDefinition is what the build.sbt declares, without evaluating it
+type buildDefinition struct {
+	Name         string
+	Organization string
+	Version      string
+	ScalaVersion string
+	Dependencies []declaredDependency
+}
+
+// declaredDependency is a libraryDependencies module of the build.sbt
+type declaredDependency struct {
+	artifact
+	// Configuration is the scope of the dependency, e.g. test, empty for compile
+	Configuration string
+}
+
+// parseBuildDefinition reads the settings and library dependencies of a build.sbt. The build is Scala
+// code, only the literal settings and the vals they refer to are understood
+func parseBuildDefinition(content string) buildDefinition {
+	var build buildDefinition
+
+	vals := map[string]string{}
+	for _, match := range buildValRegex.FindAllStringSubmatch(content, -1) {
+		vals[match[1]] = match[2]
+	}
+
+	for _, match := range buildSettingRegex.FindAllStringSubmatch(content, -1) {
+		// the first assignment is the one of the root project, the ThisBuild one usually
+		switch match[1] {
+		case "name":
+			setOnce(&build.Name, match[2])
+		case "organization":
+			setOnce(&build.Organization, match[2])
+		case "version":
+			setOnce(&build.Version, match[2])
+		case "scalaVersion":
+			setOnce(&build.ScalaVersion, match[2])
+		}
+	}
+
+	for _, match := range libraryDependencyRegex.FindAllStringSubmatch(content, -1) {
+		version := strings.Trim(match[4], `"`)
+		if !strings.HasPrefix(match[4], `"`) {
+			resolved, ok := vals[match[4]]
+			if !ok {
+				continue
+			}
+			version = resolved
+		}
+
+		artifactID := match[3]
+		if match[2] == "%%" {
+			artifactID += "_" + scalaBinaryVersion(build.ScalaVersion)
+		}
+
+		build.Dependencies = append(build.Dependencies, declaredDependency{
+			artifact:      artifact{GroupID: match[1], ArtifactID: artifactID, Version: version},
+			Configuration: strings.ToLower(strings.Trim(match[5], `"`)),
+		})
+	}
+
+	return build
+}
+
+func setOnce(setting *string, value string) {
+	if *setting == "" {
+		*setting = value
+	}
+}
+
+// scalaBinaryVersion returns the suffix %% appends to the artifacts, e.g. 2.13 for 2.13.10 and 3 for
+// Scala 3. sbt defaults to Scala 2.12
+func scalaBinaryVersion(scalaVersion string) string {
+	if scalaVersion == "" {
+		return "2.12"
+	}
+
+	parts := strings.Split(scalaVersion, ".")
+	if parts[0] == "3" || len(parts) < 2 {
+		return parts[0]
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBuildDefinition(t *testing.T) {
+	content, err := ioutil.ReadFile("testdata/project/build.sbt")
+	assert.NoError(t, err)
+
+	build := parseBuildDefinition(string(content))
+	assert.Equal(t, "hello", build.Name)
+	assert.Equal(t, "com.example", build.Organization)
+	assert.Equal(t, "0.1.0", build.Version)
+	assert.Equal(t, "2.13.10", build.ScalaVersion)
+
+	assert.Equal(t, []declaredDependency{
+		{artifact: artifact{GroupID: "org.typelevel", ArtifactID: "cats-core_2.13", Version: "2.9.0"}},
+		{artifact: artifact{GroupID: "com.typesafe", ArtifactID: "config", Version: "1.4.2"}},
+		{artifact: artifact{GroupID: "org.scalatest", ArtifactID: "scalatest_2.13", Version: "3.2.15"}, Configuration: "test"},
+	}, build.Dependencies)
+}
+
+func TestScalaBinaryVersion(t *testing.T) {
+	assert.Equal(t, "2.13", scalaBinaryVersion("2.13.10"))
+	assert.Equal(t, "3", scalaBinaryVersion("3.3.1"))
+	assert.Equal(t, "2.12", scalaBinaryVersion(""))
+}
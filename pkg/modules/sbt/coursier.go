@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// mavenCentralCache is the directory of Maven Central in the coursier cache, most artifacts come from it
+const mavenCentralCache = "https/repo1.maven.org/maven2"
+
+// coursierCache returns the coursier cache sbt downloads the dependencies to, COURSIER_CACHE
+// when set, the default of the operating system otherwise
+func coursierCache() string {
+	if cache := os.Getenv("COURSIER_CACHE"); cache != "" {
+		return cache
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			return filepath.Join(local, "Coursier", "Cache", "v1")
+		}
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, "Library", "Caches", "Coursier", "v1")
+		}
+	default:
+		if cache := os.Getenv("XDG_CACHE_HOME"); cache != "" {
+			return filepath.Join(cache, "coursier", "v1")
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".cache", "coursier", "v1")
+		}
+	}
+	return ""
+}
+
+// artifactPath returns the path of the jar of the artifact relative to its repository
+func artifactPath(a artifact) string {
+	return filepath.Join(filepath.FromSlash(strings.ReplaceAll(a.GroupID, ".", "/")), a.ArtifactID, a.Version,
+		a.ArtifactID+"-"+a.Version+".jar")
+}
+
+// findCachedJar looks up the jar of the artifact in the cache, the cache holds a directory per
+// protocol, host and repository path, Maven Central is tried first
+func findCachedJar(cache string, a artifact) (string, bool) {
+	if cache == "" {
+		return "", false
+	}
+
+	jar := artifactPath(a)
+	if path := filepath.Join(cache, filepath.FromSlash(mavenCentralCache), jar); isFile(path) {
+		return path, true
+	}
+
+	// the repository path has no fixed depth, e.g. https/repo.example.org/artifactory/libs-release
+	pattern := filepath.Join(cache, "*", "*")
+	for depth := 0; depth < 4; depth++ {
+		matches, _ := filepath.Glob(filepath.Join(pattern, jar))
+		for _, match := range matches {
+			if isFile(match) {
+				return match, true
+			}
+		}
+		pattern = filepath.Join(pattern, "*")
+	}
+	return "", false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"errors"
+)
+
+var errDependenciesNotFound error = errors.New("unable to generate SPDX file, neither sbt nor a coursier cache was found. Please install sbt and resolve the dependencies before running spdx-sbom-generator, e.g.: `sbt update`")
+var errNoProjectInTree error = errors.New("no project found in the sbt dependency tree")
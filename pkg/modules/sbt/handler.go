@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type sbt struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	BuildFile     string = "build.sbt"
+	RepositoryURL string = "https://repo1.maven.org/maven2/"
+)
+
+// dependencyTreeOutput runs sbt dependencyTree in the project directory, offline when asked, until ctx is done, tests replace it
+var dependencyTreeOutput = func(ctx context.Context, path string, offline bool) ([]byte, error) {
+	args := []string{"--batch", "-Dsbt.log.noformat=true"}
+	if offline {
+		args = append(args, "set every offline := true")
+	}
+	args = append(args, "dependencyTree")
+
+	cmd := helper.CommandContext(ctx, "sbt", args...)
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, helper.OfflineError(offline, err, "resolve the dependencies online first, e.g.: `sbt update`")
+	}
+	return out, nil
+}
+
+// New creates a new sbt instance
+func New() *sbt {
+	return &sbt{
+		metadata: models.PluginMetadata{
+			Name:       "Scala sbt",
+			Slug:       "sbt",
+			Manifest:   []string{BuildFile},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *sbt) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the version of the sbt launcher
+func (m *sbt) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "sbt", "--script-version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *sbt) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *sbt) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the project the build.sbt declares as the root package
+func (m *sbt) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(absPath, BuildFile))
+	if err != nil {
+		return nil, err
+	}
+
+	build := parseBuildDefinition(string(content))
+	name := build.Name
+	if name == "" {
+		name = filepath.Base(absPath)
+	}
+
+	root := newModule(artifact{GroupID: build.Organization, ArtifactID: name, Version: build.Version}, "")
+	root.Root = true
+	root.LocalPath = absPath
+	root.PackageDownloadLocation = ""
+	root.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA1,
+		Content:   content,
+	}
+	return &root, nil
+}
+
+// ListUsedModules returns the dependencies of the project, without the project itself
+func (m *sbt) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the root package followed by the dependencies sbt dependencyTree lists.
+// When sbt cannot list them, or in the lockfile-only mode, the dependencies the build.sbt declares are listed
+// without their own
+func (m *sbt) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := coursierCache()
+	if m.options.LockfileOnly {
+		return declaredModules(*root, cache)
+	}
+	out, err := dependencyTreeOutput(m.options.Context, path, m.options.Offline)
+	if err != nil {
+		log.Printf("sbt dependencyTree failed, only the dependencies of %s are listed: %v", BuildFile, err)
+		return declaredModules(*root, cache)
+	}
+
+	tree := parseDependencyTree(out)
+	if len(tree.Projects) == 0 {
+		return nil, errNoProjectInTree
+	}
+	return treeModules(*root, tree, cache), nil
+}
+
+// treeModules returns the root package, named after the first project of the tree, the other projects
+// it contains and the dependencies of the tree linked to the ones depending on them
+func treeModules(root models.Module, tree dependencyTree, cache string) []models.Module {
+	project := tree.Projects[0]
+	root.Name = project.ArtifactID
+	root.Version = project.Version
+	root.Path = project.GroupID + ":" + project.ArtifactID
+	root.PackageURL = packageURL(project)
+	root.Supplier = models.SupplierContact{Type: models.Organization, Name: project.GroupID}
+
+	modules := []models.Module{root}
+	indexes := map[string]int{project.key(): 0}
+	for _, a := range append(append([]artifact{}, tree.Projects[1:]...), tree.Artifacts...) {
+		if _, ok := indexes[a.key()]; ok {
+			continue
+		}
+		indexes[a.key()] = len(modules)
+		modules = append(modules, newModule(a, cache))
+	}
+
+	for _, a := range tree.Projects[1:] {
+		helper.LinkModule(modules, 0, indexes[a.key()], models.RelationshipContains)
+	}
+	for i := range modules {
+		for _, dependency := range tree.Dependencies[artifactKey(modules[i])] {
+			helper.LinkModule(modules, i, indexes[dependency], models.RelationshipDependsOn)
+		}
+	}
+
+	return modules
+}
+
+// declaredModules returns the root package and the dependencies the build.sbt declares
+func declaredModules(root models.Module, cache string) ([]models.Module, error) {
+	content, err := ioutil.ReadFile(filepath.Join(root.LocalPath, BuildFile))
+	if err != nil {
+		return nil, err
+	}
+
+	root.Unresolved = append(root.Unresolved, fmt.Sprintf("the transitive dependencies are not listed, only the ones %s declares", BuildFile))
+	modules := []models.Module{root}
+	for _, dependency := range parseBuildDefinition(string(content)).Dependencies {
+		modules = append(modules, newModule(dependency.artifact, cache))
+		helper.LinkModule(modules, 0, len(modules)-1, models.ScopeRelationship(dependency.Configuration))
+	}
+	return modules, nil
+}
+
+// newModule returns the package of the artifact, the checksum is the one of its jar in the cache
+func newModule(a artifact, cache string) models.Module {
+	mod := models.Module{
+		Name:                    a.ArtifactID,
+		Version:                 a.Version,
+		Path:                    a.GroupID + ":" + a.ArtifactID,
+		PackageURL:              packageURL(a),
+		PackageDownloadLocation: RepositoryURL + strings.ReplaceAll(a.GroupID, ".", "/") + "/" + a.ArtifactID + "/" + a.Version,
+		Supplier: models.SupplierContact{
+			Type: models.Organization,
+			Name: a.GroupID,
+		},
+		Modules: map[string]*models.Module{},
+	}
+
+	if jar, ok := findCachedJar(cache, a); ok {
+		if sum, err := helper.FileSHA1(jar); err == nil {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: sum}
+		}
+	} else {
+		mod.Unresolved = append(mod.Unresolved, "the jar is not in the coursier cache, its checksum is unknown")
+	}
+	return mod
+}
+
+// packageURL returns the pkg:maven purl of the artifact, sbt publishes to Maven repositories
+func packageURL(a artifact) string {
+	if a.GroupID == "" || a.ArtifactID == "" {
+		return ""
+	}
+	return purl.New("maven", a.GroupID, a.ArtifactID, a.Version).String()
+}
+
+// artifactKey returns the group:artifact:version key of a package
+func artifactKey(mod models.Module) string {
+	return mod.Path + ":" + mod.Version
+}
+
+// IsValid checks if the build.sbt exists
+func (m *sbt) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, BuildFile))
+}
+
+// HasModulesInstalled checks sbt can list the dependencies, or the coursier cache holds them. The build.sbt
+// is enough in the lockfile-only mode
+func (m *sbt) HasModulesInstalled(path string) error {
+	if _, err := exec.LookPath("sbt"); err == nil || m.options.LockfileOnly {
+		return nil
+	}
+	if cache := coursierCache(); cache != "" && helper.Exists(cache) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
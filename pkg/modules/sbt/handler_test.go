@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func stubDependencyTree(t *testing.T, out []byte, err error) func() {
+	cache, cacheErr := filepath.Abs(filepath.Join("testdata", "coursier"))
+	assert.NoError(t, cacheErr)
+	os.Setenv("COURSIER_CACHE", cache)
+
+	original := dependencyTreeOutput
+	dependencyTreeOutput = func(ctx context.Context, path string, offline bool) ([]byte, error) {
+		return out, err
+	}
+	return func() {
+		dependencyTreeOutput = original
+		os.Unsetenv("COURSIER_CACHE")
+	}
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	out, err := ioutil.ReadFile("testdata/dependency-tree.txt")
+	assert.NoError(t, err)
+	defer stubDependencyTree(t, out, nil)()
+
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "project"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 7)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "hello_2.13", root.Name)
+	assert.Equal(t, "pkg:maven/com.example/hello_2.13@0.1.0", root.PackageURL)
+	// the root depends on the core project, the docs one is only part of the build
+	assert.Equal(t, models.RelationshipDependsOn, root.Modules["hello-core_2.13"].Relationship)
+	assert.Equal(t, models.RelationshipContains, root.Modules["hello-docs_2.13"].Relationship)
+	assert.Contains(t, root.Modules, "config")
+	assert.Contains(t, root.Modules, "cats-core_2.13")
+	assert.NotContains(t, root.Modules, "cats-kernel_2.13")
+
+	byName := map[string]models.Module{}
+	for _, module := range modules {
+		byName[module.Name] = module
+	}
+
+	config := byName["config"]
+	assert.Equal(t, "1.4.2", config.Version)
+	assert.Equal(t, "com.typesafe:config", config.Path)
+	assert.Equal(t, "pkg:maven/com.typesafe/config@1.4.2", config.PackageURL)
+	assert.Equal(t, "https://repo1.maven.org/maven2/com/typesafe/config/1.4.2", config.PackageDownloadLocation)
+	assert.Equal(t, "Organization: com.typesafe", config.Supplier.Get())
+	assert.Equal(t, "b5c130fd30fa12d01d64c6b6f949f5276aac6b76", config.CheckSum.Value)
+
+	// the jar is found in the cache of another repository than Maven Central
+	cats := byName["cats-core_2.13"]
+	assert.Equal(t, "b68b1d37a0c4a001768035ee0b7304b5bd07a477", cats.CheckSum.Value)
+	assert.Contains(t, cats.Modules, "cats-kernel_2.13")
+	assert.Contains(t, cats.Modules, "scala-library")
+
+	assert.Nil(t, byName["cats-kernel_2.13"].CheckSum)
+	assert.NotEmpty(t, byName["cats-kernel_2.13"].Unresolved)
+}
+
+func TestListModulesWithDepsFallsBackToBuildDefinition(t *testing.T) {
+	defer stubDependencyTree(t, nil, errors.New("sbt: command not found"))()
+
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "project"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+
+	root := modules[0]
+	assert.Equal(t, "hello", root.Name)
+	assert.Equal(t, "0.1.0", root.Version)
+	assert.NotEmpty(t, root.Unresolved)
+	assert.Equal(t, models.RelationshipDevDependencyOf, root.Modules["scalatest_2.13"].Relationship)
+	assert.Equal(t, models.RelationshipDependsOn, root.Modules["config"].Relationship)
+	assert.Equal(t, "b5c130fd30fa12d01d64c6b6f949f5276aac6b76", modules[2].CheckSum.Value)
+}
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, New().IsValid(filepath.Join("testdata", "project")))
+	assert.False(t, New().IsValid("testdata"))
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+// treeNodeRegex matches a group:artifact:version node of the sbt dependencyTree output, followed by
+// its annotations, e.g. [S] for a Scala library or (evicted by: 2.13.10)
+var treeNodeRegex = regexp.MustCompile(`^([^\s:]+):([^\s:]+):([^\s:]+)(.*)$`)
+
+// artifact is a group:artifact:version coordinate of the dependency tree
+type artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+}
+
+func (a artifact) key() string {
+	return a.GroupID + ":" + a.ArtifactID + ":" + a.Version
+}
+
+// dependencyTree is the parsed sbt dependencyTree output. Projects are the roots of the trees, one per
+// project of the build, Artifacts the dependencies in the order they are first listed and
+// Dependencies the direct dependencies of every node by its key
+type dependencyTree struct {
+	Projects     []artifact
+	Artifacts    []artifact
+	Dependencies map[string][]string
+}
+
+// parseDependencyTree parses the output of sbt dependencyTree, the [info] log prefixes are removed.
+// Evicted dependencies are not on the classpath, they are skipped with the nodes below them
+func parseDependencyTree(out []byte) dependencyTree {
+	tree := dependencyTree{Dependencies: map[string][]string{}}
+	seen := map[string]bool{}
+
+	var parents []string
+	evictedDepth := -1
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimPrefix(sc.Text(), "[info] ")
+
+		depth, node := 0, line
+		if index := strings.Index(line, "+-"); index >= 0 && strings.Trim(line[:index], " |") == "" {
+			depth, node = index/2, line[index+2:]
+		} else if strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		match := treeNodeRegex.FindStringSubmatch(strings.TrimSpace(node))
+		if match == nil {
+			continue
+		}
+		if evictedDepth >= 0 && depth > evictedDepth {
+			continue
+		}
+		evictedDepth = -1
+		if strings.Contains(match[4], "(evicted by") {
+			evictedDepth = depth
+			continue
+		}
+
+		current := artifact{GroupID: match[1], ArtifactID: match[2], Version: match[3]}
+		if depth == 0 {
+			parents = []string{current.key()}
+			tree.Projects = append(tree.Projects, current)
+			seen[current.key()] = true
+			continue
+		}
+		if len(parents) < depth {
+			// a node deeper than the last one with no parent listed, the output is not a tree
+			continue
+		}
+
+		parents = parents[:depth]
+		parent := parents[depth-1]
+		tree.Dependencies[parent] = helper.AppendUnique(tree.Dependencies[parent], current.key())
+		parents = append(parents, current.key())
+
+		if !seen[current.key()] {
+			seen[current.key()] = true
+			tree.Artifacts = append(tree.Artifacts, current)
+		}
+	}
+
+	return tree
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sbt
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDependencyTree(t *testing.T) {
+	out, err := ioutil.ReadFile("testdata/dependency-tree.txt")
+	assert.NoError(t, err)
+
+	tree := parseDependencyTree(out)
+	assert.Equal(t, []artifact{
+		{GroupID: "com.example", ArtifactID: "hello_2.13", Version: "0.1.0"},
+		{GroupID: "com.example", ArtifactID: "hello-core_2.13", Version: "0.1.0"},
+		{GroupID: "com.example", ArtifactID: "hello-docs_2.13", Version: "0.1.0"},
+	}, tree.Projects)
+
+	keys := make([]string, len(tree.Artifacts))
+	for i, a := range tree.Artifacts {
+		keys[i] = a.key()
+	}
+	// the evicted scala-library is not on the classpath
+	assert.Equal(t, []string{
+		"com.example:hello-core_2.13:0.1.0",
+		"org.scala-lang:scala-library:2.13.10",
+		"com.typesafe:config:1.4.2",
+		"org.typelevel:cats-core_2.13:2.9.0",
+		"org.typelevel:cats-kernel_2.13:2.9.0",
+	}, keys)
+
+	assert.Equal(t, []string{
+		"com.example:hello-core_2.13:0.1.0",
+		"com.typesafe:config:1.4.2",
+		"org.typelevel:cats-core_2.13:2.9.0",
+	}, tree.Dependencies["com.example:hello_2.13:0.1.0"])
+	assert.Equal(t, []string{
+		"org.typelevel:cats-kernel_2.13:2.9.0",
+		"org.scala-lang:scala-library:2.13.10",
+	}, tree.Dependencies["org.typelevel:cats-core_2.13:2.9.0"])
+	assert.Equal(t, []string{"org.scala-lang:scala-library:2.13.10"}, tree.Dependencies["com.example:hello-core_2.13:0.1.0"])
+}
+
+func TestParseDependencyTreeWithoutProject(t *testing.T) {
+	tree := parseDependencyTree([]byte("[error] Not a valid command: dependencyTree\n"))
+	assert.Empty(t, tree.Projects)
+	assert.Empty(t, tree.Artifacts)
+}
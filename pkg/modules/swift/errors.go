@@ -7,3 +7,4 @@ import (
 )
 
 var errDependenciesNotFound error = errors.New("unable to generate SPDX file, no modules or vendors found. Please install them before running spdx-sbom-generator, e.g.: `swift build`")
+var errUnsupportedResolvedVersion error = errors.New("unsupported Package.resolved version")
@@ -14,10 +14,12 @@ import (
 
 type pkg struct {
 	metadata models.PluginMetadata
+	options  models.PluginOptions
 }
 
 const (
 	ManifestFile   string = "Package.swift"
+	ResolvedFile   string = "Package.resolved"
 	BuildDirectory string = ".build"
 )
 
@@ -27,15 +29,20 @@ func New() *pkg {
 		metadata: models.PluginMetadata{
 			Name:       "Swift Package Manager",
 			Slug:       "swift",
-			Manifest:   []string{ManifestFile},
+			Manifest:   []string{ManifestFile, ResolvedFile},
 			ModulePath: []string{BuildDirectory},
 		},
 	}
 }
 
+// SetOptions ...
+func (m *pkg) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // GetVersion returns Swift language version
 func (m *pkg) GetVersion() (string, error) {
-	cmd := exec.Command("swift", "--version")
+	cmd := helper.CommandContext(m.options.Context, "swift", "--version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -58,7 +65,15 @@ func (m *pkg) SetRootModule(path string) error {
 
 // GetRootModule returns root package information base on path given
 func (m *pkg) GetRootModule(path string) (*models.Module, error) {
-	cmd := exec.Command("swift", "package", "describe", "--type", "json")
+	if m.useResolved(path) {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		return resolvedRootModule(absPath, m.options.LicenseMatcher), nil
+	}
+
+	cmd := helper.CommandContext(m.options.Context, "swift", "package", "describe", "--type", "json")
 	cmd.Dir = path
 	output, err := cmd.Output()
 	if err != nil {
@@ -70,7 +85,7 @@ func (m *pkg) GetRootModule(path string) (*models.Module, error) {
 		return nil, err
 	}
 
-	mod := description.Module()
+	mod := description.Module(m.options.Context, m.options.LicenseMatcher)
 
 	return mod, nil
 }
@@ -81,7 +96,15 @@ func (m *pkg) GetRootModule(path string) (*models.Module, error) {
 // this is a plain list of all used modules
 // (no nested or tree view)
 func (m *pkg) ListUsedModules(path string) ([]models.Module, error) {
-	cmd := exec.Command("swift", "package", "show-dependencies", "--disable-automatic-resolution", "--format", "json")
+	if m.useResolved(path) {
+		modules, err := m.listResolvedModules(path)
+		if err != nil {
+			return nil, err
+		}
+		return modules[1:], nil
+	}
+
+	cmd := helper.CommandContext(m.options.Context, "swift", "package", "show-dependencies", "--disable-automatic-resolution", "--format", "json")
 	cmd.Dir = path
 	output, err := cmd.Output()
 	if err != nil {
@@ -106,7 +129,7 @@ func (m *pkg) ListUsedModules(path string) ([]models.Module, error) {
 
 	var collection []models.Module
 	for _, dep := range dependencies {
-		mod := dep.Module()
+		mod := dep.Module(m.options.Context, m.options.LicenseMatcher)
 		collection = append(collection, *mod)
 	}
 
@@ -120,6 +143,10 @@ func (m *pkg) ListUsedModules(path string) ([]models.Module, error) {
 // and each with its direct dependency only
 // (similar output to ListUsedModules but with direct dependency only)
 func (m *pkg) ListModulesWithDeps(path string) ([]models.Module, error) {
+	if m.useResolved(path) {
+		return m.listResolvedModules(path)
+	}
+
 	var collection []models.Module
 
 	mod, err := m.GetRootModule(path)
@@ -128,7 +155,7 @@ func (m *pkg) ListModulesWithDeps(path string) ([]models.Module, error) {
 	}
 	collection = append(collection, *mod)
 
-	cmd := exec.Command("swift", "package", "show-dependencies", "--disable-automatic-resolution", "--format", "json")
+	cmd := helper.CommandContext(m.options.Context, "swift", "package", "show-dependencies", "--disable-automatic-resolution", "--format", "json")
 	cmd.Dir = path
 	output, err := cmd.Output()
 	if err != nil {
@@ -141,7 +168,7 @@ func (m *pkg) ListModulesWithDeps(path string) ([]models.Module, error) {
 	}
 
 	for _, dep := range root.Dependencies {
-		mod := dep.Module()
+		mod := dep.Module(m.options.Context, m.options.LicenseMatcher)
 		collection = append(collection, *mod)
 	}
 
@@ -150,16 +177,39 @@ func (m *pkg) ListModulesWithDeps(path string) ([]models.Module, error) {
 
 // IsValid checks if the project dependency file provided in the contract exists
 func (m *pkg) IsValid(path string) bool {
-	return helper.Exists(filepath.Join(path, ManifestFile))
+	return helper.Exists(filepath.Join(path, ManifestFile)) || helper.Exists(filepath.Join(path, ResolvedFile))
 }
 
 // HasModulesInstalled checks whether
 // the current project (based on given path)
 // has the dependent packages installed
 func (m *pkg) HasModulesInstalled(path string) error {
-	if helper.Exists(filepath.Join(path, BuildDirectory)) {
+	if helper.Exists(filepath.Join(path, BuildDirectory)) || helper.Exists(filepath.Join(path, ResolvedFile)) {
 		return nil
 	}
 
 	return errDependenciesNotFound
 }
+
+// listResolvedModules returns the project followed by the packages its Package.resolved pins
+func (m *pkg) listResolvedModules(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := readResolved(filepath.Join(absPath, ResolvedFile))
+	if err != nil {
+		return nil, err
+	}
+	return resolvedModules(absPath, resolvedRootModule(absPath, m.options.LicenseMatcher), pins, m.options.LicenseMatcher), nil
+}
+
+// useResolved tells whether the packages are read from the Package.resolved rather than from swift,
+// when the toolchain is missing and always in the lockfile-only mode
+func (m *pkg) useResolved(path string) bool {
+	if !helper.Exists(filepath.Join(path, ResolvedFile)) {
+		return false
+	}
+	_, err := exec.LookPath("swift")
+	return err != nil || m.options.LockfileOnly
+}
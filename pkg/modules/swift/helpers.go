@@ -4,8 +4,8 @@ package swift
 
 import (
 	"bufio"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"golang.org/x/mod/semver"
@@ -14,20 +14,20 @@ import (
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
-func (description SwiftPackageDescription) Module() *models.Module {
+func (description SwiftPackageDescription) Module(ctx context.Context, matcher helper.LicenseMatcher) *models.Module {
 	mod := &models.Module{}
 
 	mod.Name = description.Name
 	mod.Root = true
 	mod.LocalPath = description.Path
-	setLicense(mod, description.Path)
-	setCheckSum(mod, description.Path)
-	setVersion(mod, description.Path)
+	setLicense(mod, description.Path, matcher)
+	setCheckSum(ctx, mod, description.Path)
+	setVersion(ctx, mod, description.Path)
 
 	return mod
 }
 
-func (dep SwiftPackageDependency) Module() *models.Module {
+func (dep SwiftPackageDependency) Module(ctx context.Context, matcher helper.LicenseMatcher) *models.Module {
 	mod := &models.Module{}
 	mod.Name = dep.Name
 	mod.PackageURL = strings.TrimSuffix(dep.Url, ".git")
@@ -42,14 +42,14 @@ func (dep SwiftPackageDependency) Module() *models.Module {
 
 	mod.Version = dep.Version
 	mod.LocalPath = dep.Path
-	setLicense(mod, dep.Path)
-	setCheckSum(mod, dep.Path)
+	setLicense(mod, dep.Path, matcher)
+	setCheckSum(ctx, mod, dep.Path)
 
 	return mod
 }
 
-func setLicense(mod *models.Module, path string) error {
-	licensePkg, err := helper.GetLicenses(path)
+func setLicense(mod *models.Module, path string, matcher helper.LicenseMatcher) error {
+	licensePkg, err := helper.GetLicenses(matcher, path)
 	if err != nil {
 		return err
 	}
@@ -66,8 +66,8 @@ func setLicense(mod *models.Module, path string) error {
 	return nil
 }
 
-func setVersion(mod *models.Module, path string) error {
-	cmd := exec.Command("git", "describe", "--tags", "--exact-match")
+func setVersion(ctx context.Context, mod *models.Module, path string) error {
+	cmd := helper.CommandContext(ctx, "git", "describe", "--tags", "--exact-match")
 	cmd.Dir = path
 	output, err := cmd.Output()
 	if err != nil {
@@ -92,18 +92,18 @@ func setVersion(mod *models.Module, path string) error {
 	return nil
 }
 
-func setCheckSum(mod *models.Module, path string) error {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+func setCheckSum(ctx context.Context, mod *models.Module, path string) error {
+	cmd := helper.CommandContext(ctx, "git", "rev-parse", "HEAD")
 	cmd.Dir = path
 	output, err := cmd.Output()
 	if err != nil {
 		return err
 	}
 
-	if len(output) > 0 {
+	if revision := strings.TrimSpace(string(output)); revision != "" {
 		mod.CheckSum = &models.CheckSum{
 			Algorithm: models.HashAlgoSHA1, // FIXME: derive from git
-			Value:     string(output),
+			Value:     revision,
 		}
 	}
 
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package swift
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// the kinds of the pins of a version 2 Package.resolved, a version 1 one only pins remote repositories
+const (
+	kindRemote     = "remoteSourceControl"
+	kindLocal      = "localSourceControl"
+	kindFileSystem = "fileSystem"
+	kindRegistry   = "registry"
+)
+
+// manifestName is the name of the package of a Package.swift
+var manifestName = regexp.MustCompile(`Package\s*\(\s*name\s*:\s*"([^"]+)"`)
+
+// manifestURL is the url of a dependency of a Package.swift, e.g. .package(url: "https://...", from: "1.0.0")
+var manifestURL = regexp.MustCompile(`\.package\s*\([^)]*?url\s*:\s*"([^"]+)"`)
+
+// gitRevision is a sha1 commit hash
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// resolvedPin is a package Package.resolved pins, at a version or else at the revision of a branch
+type resolvedPin struct {
+	Identity string
+	Kind     string
+	Location string
+	Revision string
+	Version  string
+	Branch   string
+}
+
+// resolvedState is the state of a pin, common to the versions of Package.resolved
+type resolvedState struct {
+	Branch   string `json:"branch"`
+	Revision string `json:"revision"`
+	Version  string `json:"version"`
+}
+
+// resolvedFile is a Package.resolved, its version 1 nests the pins in an object, the versions 2 and 3
+// name the pins by identity and tell their kind
+type resolvedFile struct {
+	Version int `json:"version"`
+	Object  struct {
+		Pins []struct {
+			Package       string        `json:"package"`
+			RepositoryURL string        `json:"repositoryURL"`
+			State         resolvedState `json:"state"`
+		} `json:"pins"`
+	} `json:"object"`
+	Pins []struct {
+		Identity string        `json:"identity"`
+		Kind     string        `json:"kind"`
+		Location string        `json:"location"`
+		State    resolvedState `json:"state"`
+	} `json:"pins"`
+}
+
+// readResolved reads the pins of a Package.resolved of the versions 1 to 3
+func readResolved(path string) ([]resolvedPin, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := resolvedFile{}
+	if err := reader.DecodeJSON(path, data, &file); err != nil {
+		return nil, err
+	}
+
+	var pins []resolvedPin
+	switch file.Version {
+	case 1:
+		for _, pin := range file.Object.Pins {
+			// the version 1 pins are named after the manifest of the package, the later ones by identity
+			identity := packageIdentity(pin.RepositoryURL)
+			if identity == "" {
+				identity = pin.Package
+			}
+			pins = append(pins, resolvedPin{
+				Identity: identity,
+				Kind:     kindRemote,
+				Location: pin.RepositoryURL,
+				Revision: pin.State.Revision,
+				Version:  pin.State.Version,
+				Branch:   pin.State.Branch,
+			})
+		}
+	case 2, 3:
+		for _, pin := range file.Pins {
+			pins = append(pins, resolvedPin{
+				Identity: pin.Identity,
+				Kind:     pin.Kind,
+				Location: pin.Location,
+				Revision: pin.State.Revision,
+				Version:  pin.State.Version,
+				Branch:   pin.State.Branch,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d in %s", errUnsupportedResolvedVersion, file.Version, path)
+	}
+	return pins, nil
+}
+
+// readManifest returns the name of the package of a Package.swift and the urls of its dependencies,
+// "" and nil when the directory has none
+func readManifest(path string) (string, []string) {
+	data, err := ioutil.ReadFile(filepath.Join(path, ManifestFile))
+	if err != nil {
+		return "", nil
+	}
+
+	name := ""
+	if match := manifestName.FindSubmatch(data); match != nil {
+		name = string(match[1])
+	}
+	var urls []string
+	for _, match := range manifestURL.FindAllSubmatch(data, -1) {
+		urls = append(urls, string(match[1]))
+	}
+	return name, urls
+}
+
+// resolvedRootModule returns the package of the project, named after its Package.swift or its directory
+func resolvedRootModule(path string, matcher helper.LicenseMatcher) *models.Module {
+	name, _ := readManifest(path)
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	mod := &models.Module{
+		Name:                    name,
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   []byte(name),
+		},
+		Modules: map[string]*models.Module{},
+	}
+	setLicense(mod, path, matcher)
+	return mod
+}
+
+// resolvedModules returns the root followed by the pinned packages. Package.resolved does not tell which
+// package requires which, so the root depends on all of them and the ones its Package.swift does not
+// declare are annotated as indirect
+func resolvedModules(path string, root *models.Module, pins []resolvedPin, matcher helper.LicenseMatcher) []models.Module {
+	_, urls := readManifest(path)
+	declared := map[string]bool{}
+	for _, u := range urls {
+		declared[strings.ToLower(repositoryKey(u))] = true
+	}
+
+	modules := []models.Module{*root}
+	for _, pin := range pins {
+		mod := pinModule(path, pin, matcher)
+		if len(declared) > 0 && !declared[strings.ToLower(repositoryKey(pin.Location))] {
+			mod.Unresolved = append(mod.Unresolved, "the package is resolved as an indirect dependency, the package requiring it is unknown")
+		}
+		modules = append(modules, mod)
+		linked := mod
+		modules[0].Modules[linked.Name] = &linked
+	}
+	return modules
+}
+
+// pinModule returns the package of a pin: a repository is downloaded at the pinned revision, the sha1
+// hash of the commit being its checksum, and a local package is built from its directory
+func pinModule(path string, pin resolvedPin, matcher helper.LicenseMatcher) models.Module {
+	version := pin.Version
+	if version == "" {
+		version = pin.Revision
+	}
+
+	mod := models.Module{
+		Name:                    pin.Identity,
+		Version:                 version,
+		PackageDownloadLocation: "NOASSERTION",
+		Modules:                 map[string]*models.Module{},
+	}
+	if pin.Version == "" && pin.Branch != "" {
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is pinned to the revision %s of the branch %s", pin.Revision, pin.Branch))
+	}
+
+	switch pin.Kind {
+	case kindRemote:
+		mod.PackageURL = packageURL(pin.Location, version)
+		mod.PackageHomePage = strings.TrimSuffix(pin.Location, ".git")
+		mod.PackageDownloadLocation = "git+" + pin.Location
+		if pin.Revision != "" {
+			mod.PackageDownloadLocation += "@" + pin.Revision
+		}
+		mod.LocalPath = filepath.Join(path, BuildDirectory, "checkouts", repositoryName(pin.Location))
+	case kindRegistry:
+		// the identity of a registry package is its scope and its name, e.g. mona.LinkedList
+		if parts := strings.SplitN(pin.Identity, ".", 2); len(parts) == 2 {
+			mod.PackageURL = purl.New("swift", parts[0], parts[1], version).String()
+		}
+	case kindLocal, kindFileSystem:
+		mod.LocalPath = pin.Location
+		if !filepath.IsAbs(mod.LocalPath) {
+			mod.LocalPath = filepath.Join(path, mod.LocalPath)
+		}
+		mod.Annotations = append(mod.Annotations, fmt.Sprintf("the package is built from the directory %s", pin.Location))
+	}
+
+	if gitRevision.MatchString(pin.Revision) {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: pin.Revision}
+	}
+	if mod.LocalPath != "" && helper.Exists(mod.LocalPath) {
+		setLicense(&mod, mod.LocalPath, matcher)
+	}
+	return mod
+}
+
+// packageURL returns the pkg:swift purl of a repository, namespaced by its host and its owner,
+// e.g. pkg:swift/github.com/apple/swift-nio@2.58.0
+func packageURL(location, version string) string {
+	key := repositoryKey(location)
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return ""
+	}
+	return purl.New("swift", key[:i], key[i+1:], version).String()
+}
+
+// repositoryKey returns the host and the path of a repository url without its .git suffix, ssh urls
+// included, e.g. github.com/apple/swift-nio for git@github.com:apple/swift-nio.git
+func repositoryKey(location string) string {
+	location = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(location), "/"), ".git")
+	if u, err := url.Parse(location); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname()) + u.Path
+	}
+	if i := strings.Index(location, "@"); i >= 0 && strings.Contains(location[i:], ":") {
+		host, path := location[i+1:], ""
+		if j := strings.Index(host, ":"); j >= 0 {
+			host, path = host[:j], host[j+1:]
+		}
+		return strings.ToLower(host) + "/" + strings.TrimPrefix(path, "/")
+	}
+	return location
+}
+
+// packageIdentity returns the identity SwiftPM gives the package of a repository url, its lowercased
+// last component, e.g. swift-nio for https://github.com/apple/swift-nio.git
+func packageIdentity(location string) string {
+	return strings.ToLower(repositoryName(location))
+}
+
+// repositoryName returns the last component of a repository url, the directory SwiftPM checks it out to
+func repositoryName(location string) string {
+	key := repositoryKey(location)
+	return key[strings.LastIndex(key, "/")+1:]
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package swift
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestReadResolvedVersion1(t *testing.T) {
+	pins, err := readResolved(filepath.Join("test", ResolvedFile))
+	assert.NoError(t, err)
+	if !assert.Len(t, pins, 3) {
+		return
+	}
+
+	assert.Equal(t, "example-package-deckofplayingcards", pins[0].Identity)
+	assert.Equal(t, kindRemote, pins[0].Kind)
+	assert.Equal(t, "https://github.com/apple/example-package-deckofplayingcards.git", pins[0].Location)
+	assert.Equal(t, "2c0e5ac3e10216151fc78ac1ec6bd9c2c0111a3a", pins[0].Revision)
+	assert.Equal(t, "3.0.4", pins[0].Version)
+}
+
+func TestReadResolvedUnsupportedVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ResolvedFile)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"pins": [], "version": 4}`), 0644))
+
+	_, err := readResolved(file)
+	assert.True(t, errors.Is(err, errUnsupportedResolvedVersion))
+}
+
+func TestResolvedModules(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("test", "resolved"))
+	assert.NoError(t, err)
+	pins, err := readResolved(filepath.Join(path, ResolvedFile))
+	assert.NoError(t, err)
+
+	modules := resolvedModules(path, resolvedRootModule(path, nil), pins, nil)
+	if !assert.Len(t, modules, 6) {
+		return
+	}
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "Resolved", root.Name)
+	assert.Len(t, root.Modules, 5)
+
+	byName := map[string]models.Module{}
+	for _, mod := range modules {
+		byName[mod.Name] = mod
+	}
+
+	parser := byName["swift-argument-parser"]
+	assert.Equal(t, "1.3.0", parser.Version)
+	assert.Equal(t, "pkg:swift/github.com/apple/swift-argument-parser@1.3.0", parser.PackageURL)
+	assert.Equal(t, "git+https://github.com/apple/swift-argument-parser.git@c8ed701b513cf5177118a175d85fbbbcd707ab41", parser.PackageDownloadLocation)
+	assert.Equal(t, "https://github.com/apple/swift-argument-parser", parser.PackageHomePage)
+	assert.Equal(t, models.HashAlgoSHA1, parser.CheckSum.Algorithm)
+	assert.Equal(t, "c8ed701b513cf5177118a175d85fbbbcd707ab41", parser.CheckSum.Value)
+	assert.Equal(t, "MIT", parser.LicenseDeclared)
+	assert.Empty(t, parser.Annotations)
+
+	collections := byName["swift-collections"]
+	assert.Equal(t, []string{"the package is resolved as an indirect dependency, the package requiring it is unknown"}, collections.Unresolved)
+
+	toolkit := byName["swift-toolkit"]
+	assert.Equal(t, "5a8e3f2c1b0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f", toolkit.Version)
+	assert.Equal(t, "pkg:swift/github.com/acme/swift-toolkit@5a8e3f2c1b0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f", toolkit.PackageURL)
+	assert.Equal(t, []string{"the package is pinned to the revision 5a8e3f2c1b0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f of the branch main"}, toolkit.Annotations)
+
+	linkedList := byName["mona.linkedlist"]
+	assert.Equal(t, "pkg:swift/mona/linkedlist@1.1.0", linkedList.PackageURL)
+	assert.Equal(t, "NOASSERTION", linkedList.PackageDownloadLocation)
+	assert.Nil(t, linkedList.CheckSum)
+
+	localKit := byName["localkit"]
+	assert.Equal(t, "NOASSERTION", localKit.PackageDownloadLocation)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "LocalKit"), localKit.LocalPath)
+	assert.Contains(t, localKit.Annotations, "the package is built from the directory ../LocalKit")
+}
+
+func TestRepositoryKey(t *testing.T) {
+	assert.Equal(t, "github.com/apple/swift-nio", repositoryKey("https://github.com/apple/swift-nio.git"))
+	assert.Equal(t, "github.com/apple/swift-nio", repositoryKey("git@github.com:apple/swift-nio.git"))
+	assert.Equal(t, "github.com/apple/swift-nio", repositoryKey("ssh://git@GitHub.com/apple/swift-nio"))
+	assert.Equal(t, "swift-nio", repositoryName("https://github.com/apple/swift-nio.git"))
+}
@@ -0,0 +1,19 @@
+// swift-tools-version:5.9
+
+import PackageDescription
+
+let package = Package(
+    name: "Resolved",
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-argument-parser.git", from: "1.2.0"),
+        .package(url: "git@github.com:acme/swift-toolkit.git", branch: "main"),
+        .package(path: "../LocalKit"),
+    ],
+    targets: [
+        .executableTarget(
+            name: "Resolved",
+            dependencies: [
+                .product(name: "ArgumentParser", package: "swift-argument-parser"),
+            ]),
+    ]
+)
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"errors"
+)
+
+var errInvalidProviderAddress error = errors.New("invalid provider address")
+var errDependenciesNotFound error = errors.New("unable to generate SPDX file, no dependency lock file found. Please lock the providers before running spdx-sbom-generator, e.g.: `terraform init`")
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type terraform struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	LockFile string = ".terraform.lock.hcl"
+)
+
+// New creates a new Terraform provider lock instance
+func New() *terraform {
+	return &terraform{
+		metadata: models.PluginMetadata{
+			Name:       "Terraform",
+			Slug:       "terraform",
+			Manifest:   []string{LockFile},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *terraform) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the terraform or tofu version
+func (m *terraform) GetVersion() (string, error) {
+	var err error
+	for _, command := range []string{"terraform", "tofu"} {
+		var output []byte
+		output, err = helper.CommandContext(m.options.Context, command, "version").Output()
+		if err != nil {
+			continue
+		}
+		return strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]), nil
+	}
+	return "", err
+}
+
+// GetMetadata returns the plugin metadata
+func (m *terraform) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *terraform) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the configuration directory as the root package
+func (m *terraform) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(absPath, LockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Module{
+		Name:      filepath.Base(absPath),
+		Root:      true,
+		LocalPath: absPath,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Content:   content,
+		},
+		Modules: map[string]*models.Module{},
+	}, nil
+}
+
+// ListUsedModules returns the providers locked in the dependency lock file
+func (m *terraform) ListUsedModules(path string) ([]models.Module, error) {
+	lockFile := filepath.Join(path, LockFile)
+	content, err := ioutil.ReadFile(lockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	providers, err := parseLockFile(lockFile, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var collection []models.Module
+	for _, provider := range providers {
+		module, err := provider.Module()
+		if err != nil {
+			return nil, err
+		}
+		collection = append(collection, module)
+	}
+
+	return collection, nil
+}
+
+// ListModulesWithDeps returns the root package followed by the providers, which it depends on.
+// Providers have no dependencies of their own
+func (m *terraform) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	providers, err := m.ListUsedModules(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range providers {
+		root.Modules[providers[i].Path] = &providers[i]
+	}
+
+	return append([]models.Module{*root}, providers...), nil
+}
+
+// IsValid checks if the dependency lock file exists
+func (m *terraform) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, LockFile))
+}
+
+// HasModulesInstalled checks whether the providers are locked, installing them is not required
+func (m *terraform) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
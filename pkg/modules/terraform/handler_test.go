@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestParseLockFile(t *testing.T) {
+	file, err := os.Open("testdata/.terraform.lock.hcl")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	providers, err := parseLockFile(file.Name(), file)
+	assert.NoError(t, err)
+	assert.Len(t, providers, 3)
+
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws", providers[0].Address)
+	assert.Equal(t, "5.31.0", providers[0].Version)
+	assert.Equal(t, "~> 5.0", providers[0].Constraints)
+	assert.Equal(t, []string{
+		"h1:fRUHKEpXV8rGticIpO8Av8XWlSVkictwTxK0ueYlXfI=",
+		"zh:0cdb9c2083bf0902442384f7309367791e4640581652dda456f2d6d7abf0de8d",
+	}, providers[0].Hashes)
+	assert.Equal(t, "registry.opentofu.org/hashicorp/null", providers[2].Address)
+}
+
+func TestParseTruncatedLockFile(t *testing.T) {
+	file, err := os.Open("testdata/truncated.terraform.lock.hcl")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	_, err = parseLockFile(file.Name(), file)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file testdata/truncated.terraform.lock.hcl: line 1: provider registry.terraform.io/hashicorp/aws is not terminated, the file is truncated")
+
+	_, err = parseLockFile("corrupt.hcl", strings.NewReader("provider \"hashicorp/aws\" {\n  version = 5.31.0\n}\n"))
+	assert.EqualError(t, err, `malformed file corrupt.hcl: line 2: unexpected "version = 5.31.0"`)
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps("testdata")
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "testdata", root.Name)
+	assert.Len(t, root.Modules, 3)
+
+	aws := modules[1]
+	assert.Equal(t, "hashicorp/aws", aws.Name)
+	assert.Equal(t, "5.31.0", aws.Version)
+	assert.Equal(t, "pkg:terraform/hashicorp/aws@5.31.0", aws.PackageURL)
+	assert.Equal(t, "https://registry.terraform.io/providers/hashicorp/aws/5.31.0", aws.PackageDownloadLocation)
+	assert.Equal(t, "https://registry.terraform.io/providers/hashicorp/aws", aws.PackageHomePage)
+	assert.Equal(t, models.SupplierContact{Type: models.Organization, Name: "hashicorp"}, aws.Supplier)
+	assert.Equal(t, &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Value:     "7d1507284a5757cac6b62708a4ef00bfc5d695256489cb704f12b4b9e6255df2",
+	}, aws.CheckSum)
+
+	null := modules[3]
+	assert.Equal(t, "pkg:terraform/hashicorp/null@3.2.2?repository_url=https:%2F%2Fregistry.opentofu.org", null.PackageURL)
+	assert.Equal(t, "https://search.opentofu.org/provider/hashicorp/null/v3.2.2", null.PackageDownloadLocation)
+}
+
+func TestParseProviderAddress(t *testing.T) {
+	address, err := parseProviderAddress("hashicorp/aws")
+	assert.NoError(t, err)
+	assert.Equal(t, providerAddress{Hostname: "registry.terraform.io", Namespace: "hashicorp", Type: "aws"}, address)
+
+	_, err = parseProviderAddress("aws")
+	assert.True(t, errors.Is(err, errInvalidProviderAddress))
+}
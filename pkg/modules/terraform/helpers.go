@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+const (
+	terraformRegistry = "registry.terraform.io"
+	opentofuRegistry  = "registry.opentofu.org"
+)
+
+// providerAddress is a provider source address, e.g. registry.terraform.io/hashicorp/aws
+type providerAddress struct {
+	Hostname  string
+	Namespace string
+	Type      string
+}
+
+// parseProviderAddress parses a hostname/namespace/type address, the hostname defaults to the Terraform registry
+func parseProviderAddress(address string) (providerAddress, error) {
+	parts := strings.Split(address, "/")
+	switch len(parts) {
+	case 2:
+		parts = append([]string{terraformRegistry}, parts...)
+	case 3:
+	default:
+		return providerAddress{}, fmt.Errorf("%w: %s", errInvalidProviderAddress, address)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return providerAddress{}, fmt.Errorf("%w: %s", errInvalidProviderAddress, address)
+		}
+	}
+
+	return providerAddress{
+		Hostname:  strings.ToLower(parts[0]),
+		Namespace: strings.ToLower(parts[1]),
+		Type:      strings.ToLower(parts[2]),
+	}, nil
+}
+
+// packageURL returns the pkg:terraform purl of the provider, the registry is a qualifier unless it is the Terraform one
+func (a providerAddress) packageURL(version string) string {
+	packageURL := purl.New("terraform", a.Namespace, a.Type, version)
+	if a.Hostname != terraformRegistry {
+		packageURL = packageURL.WithQualifier("repository_url", "https://"+a.Hostname)
+	}
+	return packageURL.String()
+}
+
+// downloadLocation returns the registry page of the provider version, empty for unknown registries
+func (a providerAddress) downloadLocation(version string) string {
+	switch a.Hostname {
+	case terraformRegistry:
+		return fmt.Sprintf("https://%s/providers/%s/%s/%s", terraformRegistry, a.Namespace, a.Type, version)
+	case opentofuRegistry:
+		return fmt.Sprintf("https://search.opentofu.org/provider/%s/%s/v%s", a.Namespace, a.Type, version)
+	}
+	return ""
+}
+
+// homePage returns the registry page of the provider, empty for unknown registries
+func (a providerAddress) homePage() string {
+	switch a.Hostname {
+	case terraformRegistry:
+		return fmt.Sprintf("https://%s/providers/%s/%s", terraformRegistry, a.Namespace, a.Type)
+	case opentofuRegistry:
+		return fmt.Sprintf("https://search.opentofu.org/provider/%s/%s", a.Namespace, a.Type)
+	}
+	return ""
+}
+
+// h1Checksum returns the hex SHA256 of the first h1: hash, the platform independent hash of the provider package content
+func h1Checksum(hashes []string) *models.CheckSum {
+	for _, hash := range hashes {
+		if !strings.HasPrefix(hash, "h1:") {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(hash, "h1:"))
+		if err != nil || len(sum) != 32 {
+			continue
+		}
+		return &models.CheckSum{
+			Algorithm: models.HashAlgoSHA256,
+			Value:     hex.EncodeToString(sum),
+		}
+	}
+	return nil
+}
+
+// Module converts the locked provider to a module
+func (p providerLock) Module() (models.Module, error) {
+	address, err := parseProviderAddress(p.Address)
+	if err != nil {
+		return models.Module{}, err
+	}
+
+	return models.Module{
+		Name:                    address.Namespace + "/" + address.Type,
+		Version:                 p.Version,
+		Path:                    p.Address,
+		PackageURL:              address.packageURL(p.Version),
+		PackageDownloadLocation: address.downloadLocation(p.Version),
+		PackageHomePage:         address.homePage(),
+		CheckSum:                h1Checksum(p.Hashes),
+		Supplier: models.SupplierContact{
+			Type: models.Organization,
+			Name: address.Namespace,
+		},
+		Modules: map[string]*models.Module{},
+	}, nil
+}
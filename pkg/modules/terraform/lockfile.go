@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+var (
+	providerBlockRegex = regexp.MustCompile(`^provider\s+"([^"]+)"\s*\{$`)
+	attributeRegex     = regexp.MustCompile(`^([a-z_]+)\s*=\s*"([^"]*)"$`)
+	hashesStartRegex   = regexp.MustCompile(`^hashes\s*=\s*\[$`)
+	hashRegex          = regexp.MustCompile(`^"([^"]+)",?$`)
+)
+
+// providerLock is a provider block of the dependency lock file
+type providerLock struct {
+	Address     string
+	Version     string
+	Constraints string
+	Hashes      []string
+}
+
+// parseLockFile reads the provider blocks of a .terraform.lock.hcl file, as written by
+// terraform init and tofu init. Only the subset of HCL those tools write is supported,
+// errors name the file and the line
+func parseLockFile(fileName string, r io.Reader) ([]providerLock, error) {
+	var providers []providerLock
+	var current *providerLock
+	start := 0
+	inHashes := false
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		switch {
+		case inHashes:
+			if line == "]" {
+				inHashes = false
+				continue
+			}
+			match := hashRegex.FindStringSubmatch(line)
+			if match == nil {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", line))
+			}
+			current.Hashes = append(current.Hashes, match[1])
+		case current == nil:
+			match := providerBlockRegex.FindStringSubmatch(line)
+			if match == nil {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", line))
+			}
+			current = &providerLock{Address: match[1]}
+			start = number
+		case line == "}":
+			if current.Version == "" {
+				return nil, reader.MalformedError(fileName, start, fmt.Sprintf("provider %s has no version", current.Address))
+			}
+			providers = append(providers, *current)
+			current = nil
+		case hashesStartRegex.MatchString(line):
+			inHashes = true
+		default:
+			match := attributeRegex.FindStringSubmatch(line)
+			if match == nil {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", line))
+			}
+			switch match[1] {
+			case "version":
+				current.Version = match[2]
+			case "constraints":
+				current.Constraints = match[2]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		return nil, reader.MalformedError(fileName, start, fmt.Sprintf("provider %s is not terminated, the file is truncated", current.Address))
+	}
+	return providers, nil
+}
@@ -0,0 +1,25 @@
+# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:fRUHKEpXV8rGticIpO8Av8XWlSVkictwTxK0ueYlXfI=",
+    "zh:0cdb9c2083bf0902442384f7309367791e4640581652dda456f2d6d7abf0de8d",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+  hashes = [
+    "h1:pEGxX+mjz1ZmEZCguTud7H0EEnKIzIclCWfPO1KJTRE=",
+  ]
+}
+
+provider "registry.opentofu.org/hashicorp/null" {
+  version = "3.2.2"
+  hashes = [
+    "h1:dCNOmK/nSY+12vHzasLXiswzlGT5UHA7jAGYkvmCuQs=",
+  ]
+}
@@ -0,0 +1,4 @@
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+  hashes = [
+    "h1:fRUHKEpXV8rGticIpO8Av8XWlSVkictwTxK0ueYlXfI=",
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"errors"
+)
+
+var (
+	errDependenciesNotFound = errors.New("unable to generate SPDX file, no installed tree found. Please install the ports of the manifest before running spdx-sbom-generator, e.g.: `vcpkg install`")
+)
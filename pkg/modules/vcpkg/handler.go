@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+type vcpkg struct {
+	metadata models.PluginMetadata
+	options  models.PluginOptions
+}
+
+const (
+	ManifestFile string = "vcpkg.json"
+	// InstalledDir is the installed tree vcpkg installs the ports of a manifest to
+	InstalledDir string = "vcpkg_installed"
+	// PortDocument is the SPDX document vcpkg writes to the share directory of an installed port
+	PortDocument string = "vcpkg.spdx.json"
+)
+
+// StatusFile is the status database of the installed tree
+var StatusFile = filepath.Join(InstalledDir, "vcpkg", "status")
+
+// New creates a new vcpkg instance
+func New() *vcpkg {
+	return &vcpkg{
+		metadata: models.PluginMetadata{
+			Name:       "vcpkg C/C++ Package Manager",
+			Slug:       "vcpkg",
+			Manifest:   []string{ManifestFile},
+			ModulePath: []string{InstalledDir},
+		},
+	}
+}
+
+// SetOptions ...
+func (m *vcpkg) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
+// GetVersion returns the vcpkg version
+func (m *vcpkg) GetVersion() (string, error) {
+	output, err := helper.CommandContext(m.options.Context, "vcpkg", "version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]), nil
+}
+
+// GetMetadata returns the plugin metadata
+func (m *vcpkg) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule sets root package information base on path given
+func (m *vcpkg) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule returns the package of the vcpkg.json of the project, named after its directory when it has no name
+func (m *vcpkg) GetRootModule(path string) (*models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root, _, err := rootModule(absPath, m.options.LicenseMatcher)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// ListUsedModules returns the ports of the project, without the project itself
+func (m *vcpkg) ListUsedModules(path string) ([]models.Module, error) {
+	modules, err := m.ListModulesWithDeps(path)
+	if err != nil {
+		return nil, err
+	}
+	return modules[1:], nil
+}
+
+// ListModulesWithDeps returns the project followed by the ports of its installed tree, linked to the ports
+// they depend on
+func (m *vcpkg) ListModulesWithDeps(path string) ([]models.Module, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	statusPath := filepath.Join(absPath, StatusFile)
+	if !helper.Exists(statusPath) {
+		return nil, errDependenciesNotFound
+	}
+	ports, err := readStatus(statusPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, manifest, err := rootModule(absPath, m.options.LicenseMatcher)
+	if err != nil {
+		return nil, err
+	}
+	return portModules(root, manifest, ports, filepath.Join(absPath, InstalledDir), m.options.LicenseMatcher), nil
+}
+
+// IsValid checks if a vcpkg.json exists
+func (m *vcpkg) IsValid(path string) bool {
+	return helper.Exists(filepath.Join(path, ManifestFile))
+}
+
+// HasModulesInstalled checks the ports of the manifest are installed to the installed tree of the project
+func (m *vcpkg) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, StatusFile)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// rootModule returns the package of the vcpkg.json of the project
+func rootModule(path string, matcher helper.LicenseMatcher) (*models.Module, *manifest, error) {
+	m, err := readManifest(filepath.Join(path, ManifestFile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mod := &models.Module{
+		Name:                    filepath.Base(path),
+		Root:                    true,
+		LocalPath:               path,
+		PackageDownloadLocation: "NONE",
+		PackageHomePage:         m.Homepage,
+		Modules:                 map[string]*models.Module{},
+	}
+	if m.Name != "" {
+		mod.Name = m.Name
+		mod.Version = m.version()
+	}
+	if maintainers := m.maintainers(); len(maintainers) > 0 {
+		mod.Supplier = helper.MaintainerSupplier(maintainers[0])
+	}
+	mod.CheckSum = &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Content:   []byte(fmt.Sprintf("%s%s", mod.Name, mod.Version)),
+	}
+	helper.SetLicenseExpression(mod, helper.SPDXExpression(m.License))
+	if mod.LicenseDeclared == "" {
+		helper.SetLicense(matcher, path, mod)
+	}
+	return mod, m, nil
+}
+
+// portModules returns the root followed by a package per installed port, the root depends on the ports of
+// its manifest and on the host ones as build tools. A port installed for several triplets is listed once
+func portModules(root *models.Module, m *manifest, ports []*port, installedDir string, matcher helper.LicenseMatcher) []models.Module {
+	modules := []models.Module{*root}
+	index := map[string]int{}
+	for _, p := range ports {
+		if i, ok := index[p.Name]; ok {
+			modules[i].Annotations = append(modules[i].Annotations, fmt.Sprintf("the port is also installed for the triplet %s", p.Triplet))
+			continue
+		}
+		index[p.Name] = len(modules)
+		modules = append(modules, portModule(p, filepath.Join(installedDir, p.Triplet, "share", p.Name), matcher))
+	}
+
+	for _, p := range ports {
+		for _, dependency := range p.Dependencies {
+			if j, ok := index[dependency.Name]; ok {
+				helper.LinkModule(modules, index[p.Name], j, "")
+			}
+		}
+	}
+	for _, dependency := range m.Dependencies {
+		i, ok := index[dependency.Name]
+		if !ok {
+			continue
+		}
+		relationship := models.RelationshipType("")
+		if dependency.Host {
+			relationship = models.RelationshipBuildToolOf
+		}
+		helper.LinkModule(modules, 0, i, relationship)
+	}
+	return modules
+}
+
+// portModule returns the package of an installed port. It is downloaded from its source when it has a
+// single one, with the sha512 checksum vcpkg verifies, else it is identified by its abi hash
+func portModule(p *port, shareDir string, matcher helper.LicenseMatcher) models.Module {
+	mod := models.Module{
+		Name:                    p.Name,
+		Version:                 p.version(),
+		PackageURL:              purl.New("generic", "vcpkg", p.Name, p.version()).String(),
+		PackageDownloadLocation: "NOASSERTION",
+		Supplier:                models.SupplierContact{Name: p.Name},
+		LocalPath:               shareDir,
+		Modules:                 map[string]*models.Module{},
+	}
+	if p.Abi != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: p.Abi}
+	}
+
+	if metadata, err := readPortDocument(filepath.Join(shareDir, PortDocument)); err == nil {
+		mod.PackageHomePage = metadata.Homepage
+		helper.SetLicenseExpression(&mod, helper.SPDXExpression(metadata.License))
+		if len(metadata.Sources) == 1 {
+			mod.PackageDownloadLocation = metadata.Sources[0].DownloadLocation
+			if metadata.Sources[0].SHA512 != "" {
+				mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: metadata.Sources[0].SHA512}
+			}
+		}
+	}
+	if mod.LicenseDeclared == "" {
+		helper.SetLicense(matcher, shareDir, &mod)
+	}
+	return mod
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDeps(t *testing.T) {
+	m := New()
+	path := filepath.Join("testdata", "app")
+	assert.True(t, m.IsValid(path))
+	assert.NoError(t, m.HasModulesInstalled(path))
+
+	modules, err := m.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	if !assert.Len(t, modules, 6) {
+		return
+	}
+
+	root := modules[0]
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "1.2.0#1", root.Version)
+	assert.Equal(t, "MIT", root.LicenseDeclared)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", root.Supplier.Get())
+	relationships := map[string]models.RelationshipType{}
+	for name, mod := range root.Modules {
+		relationships[name] = mod.Relationship
+	}
+	assert.Equal(t, map[string]models.RelationshipType{
+		"fmt":         "",
+		"curl":        "",
+		"vcpkg-cmake": models.RelationshipBuildToolOf,
+	}, relationships)
+
+	curl := modules[1]
+	assert.Equal(t, "pkg:generic/vcpkg/curl@8.2.1", curl.PackageURL)
+	assert.Equal(t, "NOASSERTION", curl.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c"}, curl.CheckSum)
+	assert.Len(t, curl.Modules, 3)
+	assert.Contains(t, curl.Modules, "openssl")
+
+	fmt := modules[2]
+	assert.Equal(t, "git+https://github.com/fmtlib/fmt@10.1.1", fmt.PackageDownloadLocation)
+	assert.Equal(t, "https://github.com/fmtlib/fmt", fmt.PackageHomePage)
+	assert.Equal(t, "MIT", fmt.LicenseDeclared)
+	assert.Equal(t, models.HashAlgoSHA512, fmt.CheckSum.Algorithm)
+	assert.Equal(t, "288c349baac5f96f527d5b1bed0fa5f5aec0e5bdb1b3c3f6e9a4d2c1b0a9f8e7d6c5b4a3928170f6e5d4c3b2a1908f7e6d5c4b3a291807f6e5d4c3b2a19080", fmt.CheckSum.Value)
+	assert.Len(t, fmt.Modules, 1)
+	assert.Contains(t, fmt.Modules, "vcpkg-cmake")
+
+	assert.Equal(t, "pkg:generic/vcpkg/zlib@1.3%231", modules[5].PackageURL)
+}
+
+func TestHasModulesInstalled(t *testing.T) {
+	assert.Equal(t, errDependenciesNotFound, New().HasModulesInstalled("testdata"))
+	_, err := New().ListModulesWithDeps("testdata")
+	assert.Equal(t, errDependenciesNotFound, err)
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// manifest is a vcpkg.json, its version is in one of the version fields of its versioning scheme
+type manifest struct {
+	Name          string          `json:"name"`
+	Version       string          `json:"version"`
+	VersionSemver string          `json:"version-semver"`
+	VersionDate   string          `json:"version-date"`
+	VersionString string          `json:"version-string"`
+	PortVersion   int             `json:"port-version"`
+	License       string          `json:"license"`
+	Homepage      string          `json:"homepage"`
+	Maintainers   json.RawMessage `json:"maintainers"`
+	Dependencies  []dependency    `json:"dependencies"`
+}
+
+// dependency is a dependency of a vcpkg.json, either a port name or an object. A host dependency is
+// built for the host triplet, e.g. a tool running during the build
+type dependency struct {
+	Name string `json:"name"`
+	Host bool   `json:"host"`
+}
+
+// UnmarshalJSON decodes a dependency from its name or its object
+func (d *dependency) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.Name); err == nil {
+		return nil
+	}
+	type object dependency
+	return json.Unmarshal(data, (*object)(d))
+}
+
+// readManifest reads a vcpkg.json
+func readManifest(path string) (*manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err := reader.DecodeJSON(path, data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// version returns the version of the manifest, followed by its port version when it is not 0, e.g. 1.2.0#1
+func (m *manifest) version() string {
+	version := m.Version
+	for _, v := range []string{m.VersionSemver, m.VersionDate, m.VersionString} {
+		if version == "" {
+			version = v
+		}
+	}
+	if version != "" && m.PortVersion > 0 {
+		version = fmt.Sprintf("%s#%d", version, m.PortVersion)
+	}
+	return version
+}
+
+// maintainers returns the maintainers of the manifest, a string or a list of them
+func (m *manifest) maintainers() []string {
+	var maintainer string
+	if err := json.Unmarshal(m.Maintainers, &maintainer); err == nil {
+		if maintainer == "" {
+			return nil
+		}
+		return []string{maintainer}
+	}
+	var maintainers []string
+	if err := json.Unmarshal(m.Maintainers, &maintainers); err == nil {
+		return maintainers
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// portSPDXID is the SPDX id of the port in the document vcpkg writes for it, the sources it downloads
+// are SPDXRef-resource-N packages
+const (
+	portSPDXID     = "SPDXRef-port"
+	resourcePrefix = "SPDXRef-resource-"
+)
+
+// portDocument is the vcpkg.spdx.json vcpkg writes to the share directory of an installed port
+type portDocument struct {
+	Packages []struct {
+		SPDXID           string `json:"SPDXID"`
+		Name             string `json:"name"`
+		DownloadLocation string `json:"downloadLocation"`
+		Homepage         string `json:"homepage"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+		Checksums        []struct {
+			Algorithm     string `json:"algorithm"`
+			ChecksumValue string `json:"checksumValue"`
+		} `json:"checksums"`
+	} `json:"packages"`
+}
+
+// portMetadata is the metadata of an installed port read from its vcpkg.spdx.json
+type portMetadata struct {
+	Homepage string
+	License  string
+	// Sources are the archives and repositories the port downloads, with their checksums
+	Sources []source
+}
+
+// source is a download of a port, e.g. git+https://github.com/madler/zlib@v1.3
+type source struct {
+	DownloadLocation string
+	SHA512           string
+}
+
+// readPortDocument reads the metadata of an installed port from its vcpkg.spdx.json, the license of the
+// port is the one of its vcpkg.json vcpkg concludes
+func readPortDocument(path string) (*portMetadata, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := portDocument{}
+	if err := reader.DecodeJSON(path, data, &doc); err != nil {
+		return nil, err
+	}
+
+	metadata := &portMetadata{}
+	for _, pkg := range doc.Packages {
+		switch {
+		case pkg.SPDXID == portSPDXID:
+			metadata.Homepage = pkg.Homepage
+			for _, license := range []string{pkg.LicenseDeclared, pkg.LicenseConcluded} {
+				if metadata.License == "" && license != "NOASSERTION" && license != "NONE" {
+					metadata.License = license
+				}
+			}
+		case strings.HasPrefix(pkg.SPDXID, resourcePrefix):
+			s := source{DownloadLocation: pkg.DownloadLocation}
+			for _, checksum := range pkg.Checksums {
+				if checksum.Algorithm == "SHA512" {
+					s.SHA512 = strings.ToLower(checksum.ChecksumValue)
+				}
+			}
+			metadata.Sources = append(metadata.Sources, s)
+		}
+	}
+	return metadata, nil
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// installedStatus is the status of a paragraph of an installed port or feature
+const installedStatus = "install ok installed"
+
+// port is a port installed for a triplet, with the dependencies of its installed features
+type port struct {
+	Name        string
+	Version     string
+	PortVersion string
+	Triplet     string
+	// Abi is the sha256 hash of the inputs the port is built from, the key of its binary cache
+	Abi          string
+	Features     []string
+	Dependencies []portID
+}
+
+// portID identifies a port installed for a triplet
+type portID struct {
+	Name    string
+	Triplet string
+}
+
+// String returns the id of the port in the form vcpkg prints it, e.g. zlib:x64-linux
+func (id portID) String() string {
+	return fmt.Sprintf("%s:%s", id.Name, id.Triplet)
+}
+
+func (p *port) id() portID {
+	return portID{Name: p.Name, Triplet: p.Triplet}
+}
+
+// version returns the version of the port, followed by its port version when it is not 0, e.g. 1.3#2
+func (p *port) version() string {
+	if p.PortVersion == "" || p.PortVersion == "0" {
+		return p.Version
+	}
+	return fmt.Sprintf("%s#%s", p.Version, p.PortVersion)
+}
+
+// readStatus reads the ports installed according to the status database of an installed tree, e.g.
+// vcpkg_installed/vcpkg/status. The database is appended to so the last paragraph of a port wins, the
+// paragraphs of its features add their dependencies to it. The ports are returned sorted by name and triplet
+func readStatus(path string) ([]*port, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	paragraphs, err := reader.ReadParagraphs(path, file)
+	if err != nil {
+		return nil, err
+	}
+
+	type featureID struct {
+		portID
+		feature string
+	}
+	ports := map[portID]*port{}
+	features := map[featureID][]portID{}
+	for _, paragraph := range paragraphs {
+		id := portID{Name: paragraph["Package"], Triplet: paragraph["Architecture"]}
+		if id.Name == "" {
+			return nil, fmt.Errorf("%w %s: paragraph without a Package", reader.ErrMalformedFile, path)
+		}
+		installed := paragraph["Status"] == installedStatus
+		dependencies := parseDepends(paragraph["Depends"], id.Triplet)
+
+		if feature := paragraph["Feature"]; feature != "" {
+			if installed {
+				features[featureID{id, feature}] = dependencies
+			} else {
+				delete(features, featureID{id, feature})
+			}
+			continue
+		}
+		if !installed {
+			delete(ports, id)
+			continue
+		}
+		ports[id] = &port{
+			Name:         id.Name,
+			Version:      paragraph["Version"],
+			PortVersion:  paragraph["Port-Version"],
+			Triplet:      id.Triplet,
+			Abi:          paragraph["Abi"],
+			Dependencies: dependencies,
+		}
+	}
+
+	for id, dependencies := range features {
+		p, ok := ports[id.portID]
+		if !ok {
+			continue
+		}
+		p.Features = append(p.Features, id.feature)
+		for _, dependency := range dependencies {
+			if dependency != p.id() && !containsPort(p.Dependencies, dependency) {
+				p.Dependencies = append(p.Dependencies, dependency)
+			}
+		}
+	}
+
+	installed := make([]*port, 0, len(ports))
+	for _, p := range ports {
+		sort.Strings(p.Features)
+		sort.Slice(p.Dependencies, func(i, j int) bool { return p.Dependencies[i].String() < p.Dependencies[j].String() })
+		installed = append(installed, p)
+	}
+	sort.Slice(installed, func(i, j int) bool { return installed[i].id().String() < installed[j].id().String() })
+	return installed, nil
+}
+
+// parseDepends parses the Depends field of a paragraph, e.g. vcpkg-cmake:x64-linux, zlib. A dependency
+// without a triplet is the one of the port, a dependency on a feature of a port is one on the port
+func parseDepends(depends string, triplet string) []portID {
+	var dependencies []portID
+	seen := map[portID]bool{}
+	for _, dependency := range strings.Split(depends, ",") {
+		dependency = strings.TrimSpace(dependency)
+		if dependency == "" {
+			continue
+		}
+		// a dependency on a feature, e.g. curl[ssl]:x64-linux
+		if i := strings.Index(dependency, "["); i >= 0 {
+			if j := strings.Index(dependency[i:], "]"); j >= 0 {
+				dependency = dependency[:i] + dependency[i+j+1:]
+			}
+		}
+		id := portID{Name: dependency, Triplet: triplet}
+		if i := strings.Index(dependency, ":"); i >= 0 {
+			id = portID{Name: dependency[:i], Triplet: dependency[i+1:]}
+		}
+		if !seen[id] {
+			seen[id] = true
+			dependencies = append(dependencies, id)
+		}
+	}
+	return dependencies
+}
+
+func containsPort(ids []portID, id portID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vcpkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadStatus(t *testing.T) {
+	ports, err := readStatus(filepath.Join("testdata", "app", StatusFile))
+	assert.NoError(t, err)
+	if !assert.Len(t, ports, 5) {
+		return
+	}
+
+	curl := ports[0]
+	assert.Equal(t, "curl", curl.Name)
+	assert.Equal(t, "8.2.1", curl.version())
+	assert.Equal(t, []string{"ssl"}, curl.Features)
+	assert.Equal(t, []portID{
+		{Name: "openssl", Triplet: "x64-linux"},
+		{Name: "vcpkg-cmake", Triplet: "x64-linux"},
+		{Name: "zlib", Triplet: "x64-linux"},
+	}, curl.Dependencies)
+
+	zlib := ports[4]
+	assert.Equal(t, "1.3#1", zlib.version())
+	assert.Equal(t, "7a1b2c3d4e5f60718293a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5", zlib.Abi)
+	assert.Empty(t, zlib.Dependencies)
+}
+
+func TestParseDepends(t *testing.T) {
+	assert.Equal(t, []portID{
+		{Name: "vcpkg-cmake", Triplet: "x64-linux"},
+		{Name: "curl", Triplet: "arm64-osx"},
+	}, parseDepends("vcpkg-cmake:x64-linux, curl[ssl], curl", "arm64-osx"))
+	assert.Empty(t, parseDepends("", "x64-linux"))
+}
@@ -0,0 +1,353 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// berryMetadataKey is the first entry of the lockfiles written by yarn 2 and later
+const berryMetadataKey = "__metadata"
+
+var protocolRegex = regexp.MustCompile(`^[a-z][a-z0-9+.-]*:`)
+
+// berryPackage is an entry of a yarn 2+ lockfile, resolved for one or more descriptors
+type berryPackage struct {
+	// Descriptors are the name@range the entry resolves, e.g. lodash@npm:^4.17.20
+	Descriptors []string
+	Name        string
+	Version     string
+	// Resolution is the locator of the package, e.g. lodash@npm:4.17.21
+	Resolution string
+	Checksum   string
+	// LinkType is hard for the packages fetched by yarn, soft for the workspaces and linked folders
+	LinkType string
+	// Dependencies maps the dependency names to their range
+	Dependencies map[string]string
+}
+
+// isWorkspace tells whether the package is a workspace of the project
+func (p berryPackage) isWorkspace() bool {
+	return strings.Contains(p.Resolution, "@workspace:")
+}
+
+// workspaceDir returns the directory of a workspace relative to the project, e.g. packages/lib of
+// lib@workspace:packages/lib
+func (p berryPackage) workspaceDir() string {
+	return filepath.FromSlash(strings.TrimPrefix(p.reference(), "workspace:"))
+}
+
+// reference returns the part of the resolution after the package name, e.g. npm:4.17.21
+func (p berryPackage) reference() string {
+	return strings.TrimPrefix(p.Resolution, p.Name+"@")
+}
+
+// isBerryLockFile tells whether the lockfile at path was written by yarn 2 or later, whose
+// first entry is __metadata where yarn 1 lockfiles start with their packages
+func isBerryLockFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		return text == berryMetadataKey+":"
+	}
+	return false
+}
+
+// readBerryLockFile reads the packages of a yarn 2+ lockfile
+func readBerryLockFile(path string) ([]berryPackage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseBerryLockFile(path, file)
+}
+
+// parseBerryLockFile parses the subset of YAML yarn writes its lockfiles with: top level entries
+// keyed by their descriptors, two space indented fields and dependency maps. Errors name the
+// file and the line
+func parseBerryLockFile(fileName string, r io.Reader) ([]berryPackage, error) {
+	var packages []berryPackage
+	// lines are the line numbers of the package entries
+	var lines []int
+	var current *berryPackage
+	inMetadata := false
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimRight(scanner.Text(), " \r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, reader.MalformedError(fileName, number, "tab indentation")
+		}
+
+		indent := len(text) - len(trimmed)
+		switch {
+		case indent == 0:
+			key, value, ok := splitBerryField(trimmed)
+			if !ok || value != "" {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", trimmed))
+			}
+			current, section = nil, ""
+			inMetadata = key == berryMetadataKey
+			if inMetadata {
+				continue
+			}
+			packages = append(packages, berryPackage{
+				Descriptors:  strings.Split(key, ", "),
+				Dependencies: map[string]string{},
+			})
+			current = &packages[len(packages)-1]
+			lines = append(lines, number)
+		case inMetadata:
+			continue
+		case current == nil:
+			return nil, reader.MalformedError(fileName, number, "indented line outside of an entry")
+		case indent == 2:
+			key, value, ok := splitBerryField(trimmed)
+			if !ok {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected %q", trimmed))
+			}
+			section = ""
+			switch key {
+			case "version":
+				current.Version = value
+			case "resolution":
+				current.Resolution = value
+				current.Name = locatorName(value)
+			case "checksum":
+				current.Checksum = value
+			case "linkType":
+				current.LinkType = value
+			default:
+				if value == "" {
+					section = key
+				}
+			}
+		case indent == 4 && section == "dependencies":
+			name, version, ok := splitBerryField(trimmed)
+			if !ok || version == "" {
+				return nil, reader.MalformedError(fileName, number, fmt.Sprintf("unexpected dependency %q", trimmed))
+			}
+			current.Dependencies[name] = version
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range packages {
+		if packages[i].Resolution == "" || packages[i].Version == "" {
+			return nil, reader.MalformedError(fileName, lines[i], fmt.Sprintf("entry %s has no resolution or version", packages[i].Descriptors[0]))
+		}
+	}
+
+	return packages, nil
+}
+
+// splitBerryField splits a `key: value` line, the key and the value may be double quoted
+func splitBerryField(text string) (string, string, bool) {
+	var key, rest string
+	if strings.HasPrefix(text, "\"") {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		unquoted, err := strconv.Unquote(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		key, rest = unquoted, text[end+1:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		rest = rest[1:]
+	} else {
+		i := strings.Index(text, ":")
+		if i <= 0 {
+			return "", "", false
+		}
+		key, rest = text[:i], text[i+1:]
+	}
+
+	value := strings.TrimSpace(rest)
+	if strings.HasPrefix(value, "\"") {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", "", false
+		}
+		value = unquoted
+	}
+
+	return key, value, true
+}
+
+// closingQuote returns the index of the quote closing the string text starts with, -1 if none
+func closingQuote(text string) int {
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// locatorName returns the package name of a locator or a descriptor, e.g. @babel/core of @babel/core@npm:7.14.0,
+// the @ of the scoped names is not the version separator
+func locatorName(locator string) string {
+	if len(locator) > 1 {
+		if i := strings.Index(locator[1:], "@"); i >= 0 {
+			return locator[:i+1]
+		}
+	}
+	return locator
+}
+
+// dependencyDescriptor returns the lockfile descriptor of a dependency range, the ranges without
+// protocol are npm ones, e.g. lodash@npm:^4.17.20 for lodash ^4.17.20
+func dependencyDescriptor(name, version string) string {
+	if !protocolRegex.MatchString(version) {
+		version = "npm:" + version
+	}
+	return name + "@" + version
+}
+
+// berryDownloadLocation returns the registry tarball of the npm packages, e.g.
+// https://registry.yarnpkg.com/@babel/core/-/core-7.14.0.tgz, the package page otherwise
+func berryDownloadLocation(p berryPackage) string {
+	if !strings.HasPrefix(p.reference(), "npm:") {
+		return fmt.Sprintf("https://www.yarnpkg.com/package/%s", p.Name)
+	}
+
+	version := strings.TrimPrefix(p.reference(), "npm:")
+	base := p.Name[strings.LastIndex(p.Name, "/")+1:]
+	return fmt.Sprintf("%s/%s/-/%s-%s.tgz", yarnRegistry, p.Name, base, version)
+}
+
+// buildBerryDependencies returns the root module and the packages of a yarn 2+ lockfile linked to their
+// dependencies. The files of the packages are read from their Plug'n'Play location, from node_modules
+// when yarn installed with the node-modules linker. The workspace packages are read from their
+// package.json and contained by the root
+func (m *yarn) buildBerryDependencies(path string, packages []berryPackage) ([]models.Module, error) {
+	root, err := m.buildRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := readPnpLocations(path)
+	if err != nil {
+		return nil, err
+	}
+
+	byDescriptor := map[string]*berryPackage{}
+	for i := range packages {
+		for _, descriptor := range packages[i].Descriptors {
+			byDescriptor[descriptor] = &packages[i]
+		}
+		// the lockfile versions the workspaces 0.0.0-use.local
+		if packages[i].isWorkspace() {
+			if version := getPackageVersion(filepath.Join(path, packages[i].workspaceDir(), "package.json")); version != "" {
+				packages[i].Version = version
+			}
+		}
+	}
+
+	modules := []models.Module{*root}
+	workspaces := map[string]int{}
+	for _, p := range packages {
+		if p.isWorkspace() && p.workspaceDir() == "." {
+			linkBerryDependencies(modules[0].Modules, p, byDescriptor)
+			markDevDependencies(modules[0].Modules, path)
+			continue
+		}
+
+		if p.isWorkspace() {
+			dir := filepath.Join(path, p.workspaceDir())
+			workspace, err := m.buildRootModule(dir)
+			if err != nil {
+				return nil, err
+			}
+			workspace.Name, workspace.Version = p.Name, p.Version
+			linkBerryDependencies(workspace.Modules, p, byDescriptor)
+			markDevDependencies(workspace.Modules, dir)
+			workspaces[p.Name] = len(modules)
+			modules = append(modules, *workspace)
+			continue
+		}
+
+		mod := models.Module{
+			Name:                    p.Name,
+			Version:                 p.Version,
+			PackageDownloadLocation: berryDownloadLocation(p),
+			CheckSum:                packageChecksum(p.Name),
+			Modules:                 map[string]*models.Module{},
+		}
+		mod.Supplier.Name = mod.Name
+
+		location, ok := locations[p.Resolution]
+		if !ok {
+			location = filepath.Join("node_modules", p.Name)
+		}
+		if dir, cleanup, err := packageDirectory(path, location); err == nil {
+			m.setPackageFiles(&mod, dir)
+			cleanup()
+		}
+
+		linkBerryDependencies(mod.Modules, p, byDescriptor)
+		modules = append(modules, mod)
+	}
+
+	containWorkspaces(modules, workspaces)
+	return modules, nil
+}
+
+// linkBerryDependencies adds the packages the dependencies of p resolve to, to links
+func linkBerryDependencies(links map[string]*models.Module, p berryPackage, byDescriptor map[string]*berryPackage) {
+	for name, version := range p.Dependencies {
+		dependency, ok := byDescriptor[dependencyDescriptor(name, version)]
+		if !ok {
+			dependency, ok = byDescriptor[name+"@"+version]
+		}
+		if !ok {
+			continue
+		}
+		links[dependency.Name] = bareModule(dependency.Name, dependency.Version)
+	}
+}
+
+// markDevDependencies sets the relationship of the links the package.json of dir only declares as
+// devDependencies, the lockfile lists them with the other dependencies
+func markDevDependencies(links map[string]*models.Module, dir string) {
+	for name := range devDependencyNames(dir) {
+		if link, ok := links[name]; ok {
+			link.Relationship = models.ScopeRelationship("dev")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadBerryLockFile(t *testing.T) {
+	path := filepath.Join("test", "berry", lockFile)
+	assert.True(t, isBerryLockFile(path))
+	assert.False(t, isBerryLockFile(filepath.Join("test", "truncated.yarn.lock")))
+
+	packages, err := readBerryLockFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, packages, 5)
+
+	assert.Equal(t, berryPackage{
+		Descriptors:  []string{"@babel/helper-validator-identifier@npm:^7.14.0"},
+		Name:         "@babel/helper-validator-identifier",
+		Version:      "7.14.0",
+		Resolution:   "@babel/helper-validator-identifier@npm:7.14.0",
+		Checksum:     packages[0].Checksum,
+		LinkType:     "hard",
+		Dependencies: map[string]string{},
+	}, packages[0])
+	assert.NotEmpty(t, packages[0].Checksum)
+
+	assert.True(t, packages[1].isWorkspace())
+	assert.Equal(t, map[string]string{
+		"@babel/helper-validator-identifier": "^7.14.0",
+		"debug":                              "^4.3.1",
+		"ms":                                 "^2.1.3",
+	}, packages[1].Dependencies)
+
+	// the nested peerDependenciesMeta are not dependencies
+	assert.Equal(t, map[string]string{"ms": "2.1.2"}, packages[2].Dependencies)
+}
+
+func TestParseMalformedBerryLockFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"indented line outside of an entry", "\n  version: 1.0.0\n", "line 2: indented line outside of an entry"},
+		{"missing version", "\"ms@npm:^2.1.3\":\n  resolution: \"ms@npm:2.1.3\"\n", "line 1: entry ms@npm:^2.1.3 has no resolution or version"},
+		{"unterminated key", "\"ms@npm:^2.1.3:\n  version: 2.1.3\n", "line 1: unexpected \"\\\"ms@npm:^2.1.3:\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseBerryLockFile("yarn.lock", strings.NewReader(test.content))
+			assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+			assert.EqualError(t, err, "malformed file yarn.lock: "+test.expected)
+		})
+	}
+}
+
+func TestBerryDescriptors(t *testing.T) {
+	assert.Equal(t, "@babel/core", locatorName("@babel/core@npm:7.14.0"))
+	assert.Equal(t, "ms", locatorName("ms@npm:2.1.3"))
+	assert.Equal(t, "ms@npm:^2.1.3", dependencyDescriptor("ms", "^2.1.3"))
+	assert.Equal(t, "app@workspace:packages/app", dependencyDescriptor("app", "workspace:packages/app"))
+
+	assert.Equal(t, "https://registry.yarnpkg.com/@babel/core/-/core-7.14.0.tgz",
+		berryDownloadLocation(berryPackage{Name: "@babel/core", Resolution: "@babel/core@npm:7.14.0"}))
+	assert.Equal(t, "https://www.yarnpkg.com/package/lib",
+		berryDownloadLocation(berryPackage{Name: "lib", Resolution: "lib@https://github.com/example/lib.git#commit=abc"}))
+}
+
+func TestListBerryModules(t *testing.T) {
+	path := filepath.Join("test", "berry")
+	n := New()
+	assert.NoError(t, n.HasModulesInstalled(path))
+
+	modules, err := n.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 5)
+
+	root := modules[0]
+	assert.Equal(t, "berry-app", root.Name)
+	assert.Equal(t, []string{"@babel/helper-validator-identifier", "debug", "ms"}, moduleNames(root.Modules))
+	assert.Equal(t, "2.1.3", root.Modules["ms"].Version)
+
+	byLocator := map[string]models.Module{}
+	for _, mod := range modules[1:] {
+		byLocator[mod.Name+"@"+mod.Version] = mod
+	}
+
+	babel := byLocator["@babel/helper-validator-identifier@7.14.0"]
+	assert.Equal(t, "https://registry.yarnpkg.com/@babel/helper-validator-identifier/-/helper-validator-identifier-7.14.0.tgz", babel.PackageDownloadLocation)
+	assert.Equal(t, "https://babel.dev/docs/en/next/babel-helper-validator-identifier", babel.PackageHomePage)
+	assert.Equal(t, "MIT", babel.LicenseDeclared)
+	assert.Equal(t, "Copyright (c) 2014-present Sebastian McKenzie and other contributors", babel.Copyright)
+
+	// read from the zip archive of the cache, from the unplugged directory
+	assert.Equal(t, "MIT", byLocator["ms@2.1.3"].LicenseDeclared)
+	assert.Equal(t, "https://github.com/zeit/ms", byLocator["ms@2.1.2"].PackageHomePage)
+	assert.Equal(t, "MIT", byLocator["ms@2.1.2"].LicenseDeclared)
+
+	// the archive of debug is not in the cache
+	debug := byLocator["debug@4.3.1"]
+	assert.Empty(t, debug.LicenseDeclared)
+	assert.Equal(t, []string{"ms"}, moduleNames(debug.Modules))
+	assert.Equal(t, "2.1.2", debug.Modules["ms"].Version)
+}
+
+func moduleNames(modules map[string]*models.Module) []string {
+	var names []string
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
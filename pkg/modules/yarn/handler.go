@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -20,6 +19,7 @@ import (
 
 type yarn struct {
 	metadata models.PluginMetadata
+	options  models.PluginOptions
 }
 
 var (
@@ -57,10 +57,11 @@ func (m *yarn) IsValid(path string) bool {
 	return true
 }
 
-// HasModulesInstalled checks if modules of manifest file already installed
+// HasModulesInstalled checks if modules of manifest file already installed,
+// in node_modules or with Plug'n'Play for yarn 2+. The lockfile is enough in the lockfile-only mode
 func (m *yarn) HasModulesInstalled(path string) error {
 	for _, p := range m.metadata.ModulePath {
-		if !helper.Exists(filepath.Join(path, p)) {
+		if !helper.Exists(filepath.Join(path, p)) && !hasPnpInstall(path) && !m.options.LockfileOnly {
 			return errDependenciesNotFound
 		}
 	}
@@ -73,9 +74,14 @@ func (m *yarn) HasModulesInstalled(path string) error {
 	return nil
 }
 
+// SetOptions ...
+func (m *yarn) SetOptions(opts models.PluginOptions) {
+	m.options = opts
+}
+
 // GetVersion returns yarn version
 func (m *yarn) GetVersion() (string, error) {
-	cmd := exec.Command("yarn", "-v")
+	cmd := helper.CommandContext(m.options.Context, "yarn", "-v")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -121,8 +127,9 @@ func (m *yarn) GetRootModule(path string) (*models.Module, error) {
 			mod.PackageDownloadLocation = repository.(map[string]interface{})["url"].(string)
 		}
 	}
-	if pkResult["homepage"] != nil {
-		mod.PackageURL = helper.RemoveURLProtocol(pkResult["homepage"].(string))
+	if homepage, ok := pkResult["homepage"].(string); ok {
+		mod.PackageURL = helper.RemoveURLProtocol(homepage)
+		mod.PackageHomePage = homepage
 		mod.PackageDownloadLocation = mod.PackageURL
 	}
 	if !rg.MatchString(mod.PackageDownloadLocation) {
@@ -130,7 +137,7 @@ func (m *yarn) GetRootModule(path string) (*models.Module, error) {
 	}
 	mod.Modules = map[string]*models.Module{}
 	mod.Copyright = getCopyright(path)
-	modLic, err := helper.GetLicenses(path)
+	modLic, err := helper.GetLicenses(m.options.LicenseMatcher, path)
 	if err != nil {
 		return mod, nil
 	}
@@ -165,30 +172,33 @@ func (m *yarn) ListUsedModules(path string) ([]models.Module, error) {
 
 // ListModulesWithDeps return all info of installed modules
 func (m *yarn) ListModulesWithDeps(path string) ([]models.Module, error) {
+	if isBerryLockFile(filepath.Join(path, lockFile)) {
+		packages, err := readBerryLockFile(filepath.Join(path, lockFile))
+		if err != nil {
+			return nil, err
+		}
+		return m.buildBerryDependencies(path, packages)
+	}
+
 	deps, err := readLockFile(filepath.Join(path, lockFile))
-	allDeps := appendNestedDependencies(deps)
 	if err != nil {
 		return nil, err
 	}
+	allDeps := appendNestedDependencies(deps)
 
-	return m.buildDependencies(path, allDeps)
+	modules, err := m.buildDependencies(path, allDeps)
+	if err != nil {
+		return modules, err
+	}
+	return m.addWorkspaces(path, modules, deps)
 }
 
 func (m *yarn) buildDependencies(path string, deps []dependency) ([]models.Module, error) {
 	modules := make([]models.Module, 0)
-	de, err := m.GetRootModule(path)
+	de, err := m.buildRootModule(path)
 	if err != nil {
 		return modules, err
 	}
-	h := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s-%s", de.Name, de.Version))))
-	de.CheckSum = &models.CheckSum{
-		Algorithm: "SHA256",
-		Value:     h,
-	}
-	de.Supplier.Name = de.Name
-	if de.PackageDownloadLocation == "" {
-		de.PackageDownloadLocation = de.Name
-	}
 	modules = append(modules, *de)
 	for _, d := range deps {
 		var mod models.Module
@@ -230,35 +240,64 @@ func (m *yarn) buildDependencies(path string, deps []dependency) ([]models.Modul
 		}
 		mod.Supplier.Name = mod.Name
 
-		mod.PackageURL = getPackageHomepage(filepath.Join(path, m.metadata.ModulePath[0], d.PkPath, m.metadata.Manifest[0]))
-		h := fmt.Sprintf("%x", sha256.Sum256([]byte(mod.Name)))
-		mod.CheckSum = &models.CheckSum{
-			Algorithm: "SHA256",
-			Value:     h,
-		}
-		licensePath := filepath.Join(path, m.metadata.ModulePath[0], d.PkPath, "LICENSE")
-		if helper.Exists(licensePath) {
-			r := reader.New(licensePath)
-			s := r.StringFromFile()
-			mod.Copyright = helper.GetCopyright(s)
-		}
-
-		modLic, err := helper.GetLicenses(filepath.Join(path, m.metadata.ModulePath[0], d.PkPath))
-		if err != nil {
-			modules = append(modules, mod)
-			continue
-		}
-		mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
-		mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
-		mod.CommentsLicense = modLic.Comments
-		if !helper.LicenseSPDXExists(modLic.ID) {
-			mod.OtherLicense = append(mod.OtherLicense, modLic)
-		}
+		mod.CheckSum = packageChecksum(mod.Name)
+		m.setPackageFiles(&mod, filepath.Join(path, m.metadata.ModulePath[0], d.PkPath))
 		modules = append(modules, mod)
 	}
 	return modules, nil
 }
 
+// buildRootModule returns the root module with its checksum, supplier and download location
+func (m *yarn) buildRootModule(path string) (*models.Module, error) {
+	de, err := m.GetRootModule(path)
+	if err != nil {
+		return de, err
+	}
+	h := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s-%s", de.Name, de.Version))))
+	de.CheckSum = &models.CheckSum{
+		Algorithm: "SHA256",
+		Value:     h,
+	}
+	de.Supplier.Name = de.Name
+	if de.PackageDownloadLocation == "" {
+		de.PackageDownloadLocation = de.Name
+	}
+	return de, nil
+}
+
+// packageChecksum returns the checksum of a dependency, the hash of its name
+func packageChecksum(name string) *models.CheckSum {
+	h := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+	return &models.CheckSum{
+		Algorithm: "SHA256",
+		Value:     h,
+	}
+}
+
+// setPackageFiles sets the home page, copyright and license of the module
+// from the package.json and the license files of its directory
+func (m *yarn) setPackageFiles(mod *models.Module, dir string) {
+	mod.PackageHomePage = getPackageHomepage(filepath.Join(dir, "package.json"))
+	mod.PackageURL = helper.RemoveURLProtocol(mod.PackageHomePage)
+	licensePath := filepath.Join(dir, "LICENSE")
+	if helper.Exists(licensePath) {
+		r := reader.New(licensePath)
+		s := r.StringFromFile()
+		mod.Copyright = helper.GetCopyright(s)
+	}
+
+	modLic, err := helper.GetLicenses(m.options.LicenseMatcher, dir)
+	if err != nil {
+		return
+	}
+	mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
+	mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
+	mod.CommentsLicense = modLic.Comments
+	if !helper.LicenseSPDXExists(modLic.ID) {
+		mod.OtherLicense = append(mod.OtherLicense, modLic)
+	}
+}
+
 func readLockFile(path string) ([]dependency, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -271,9 +310,15 @@ func readLockFile(path string) ([]dependency, error) {
 
 	isPk := false
 	isDep := false
-	for scanner.Scan() {
+	// v1 lockfiles carry a version for every entry, a missing one means the file is truncated
+	isV1 := false
+	entryLine := 0
+	for line := 1; scanner.Scan(); line++ {
 		text := scanner.Text()
 		if strings.HasPrefix(scanner.Text(), "#") {
+			if strings.Contains(text, "yarn lockfile v1") {
+				isV1 = true
+			}
 			continue
 		}
 		if strings.TrimSpace(text) == "" {
@@ -287,6 +332,9 @@ func readLockFile(path string) ([]dependency, error) {
 		}
 		if isPk {
 			if strings.HasPrefix(text, "  version ") {
+				if !strings.Contains(p[i].Name, "@") || !strings.Contains(p[i].PkPath, "@") {
+					return []dependency{}, reader.MalformedError(path, line, fmt.Sprintf("entry %s has no version range", p[i].PkPath))
+				}
 				p[i].Version = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(text, "  version "), "\""), "\"")
 				n := p[i].Name[:strings.Index(p[i].Name, "@")]
 				p[i].Name = n
@@ -307,12 +355,23 @@ func readLockFile(path string) ([]dependency, error) {
 			}
 		}
 
+		if strings.HasPrefix(text, "  ") && !isPk {
+			return []dependency{}, reader.MalformedError(path, line, "indented line outside of an entry")
+		}
+
 		if !strings.HasPrefix(scanner.Text(), "  ") {
+			if isV1 && i >= 0 && p[i].Version == "" {
+				return []dependency{}, reader.MalformedError(path, entryLine, fmt.Sprintf("entry %s has no version", p[i].PkPath))
+			}
+			entryLine = line
 			isPk = true
 			i++
 			var dep dependency
 			name := text
 			name = strings.TrimSpace(name)
+			for _, descriptor := range strings.Split(strings.TrimSuffix(name, ":"), ",") {
+				dep.Descriptors = append(dep.Descriptors, strings.Trim(strings.TrimSpace(descriptor), "\""))
+			}
 			if strings.Contains(name, ",") {
 				s := strings.Split(name, ",")
 				name = s[0]
@@ -333,6 +392,10 @@ func readLockFile(path string) ([]dependency, error) {
 		return []dependency{}, err
 	}
 
+	if isV1 && i >= 0 && p[i].Version == "" {
+		return []dependency{}, reader.MalformedError(path, entryLine, fmt.Sprintf("entry %s has no version, the file is truncated", p[i].PkPath))
+	}
+
 	return p, nil
 }
 
@@ -357,14 +420,28 @@ func getCopyright(path string) string {
 	return ""
 }
 
+// getPackageHomepage returns the homepage declared in the package.json at path
 func getPackageHomepage(path string) string {
 	r := reader.New(path)
 	pkResult, err := r.ReadJson()
 	if err != nil {
 		return ""
 	}
-	if pkResult["homepage"] != nil {
-		return helper.RemoveURLProtocol(pkResult["homepage"].(string))
+	if homepage, ok := pkResult["homepage"].(string); ok {
+		return homepage
+	}
+	return ""
+}
+
+// getPackageVersion returns the version declared in the package.json at path
+func getPackageVersion(path string) string {
+	r := reader.New(path)
+	pkResult, err := r.ReadJson()
+	if err != nil {
+		return ""
+	}
+	if version, ok := pkResult["version"].(string); ok {
+		return version
 	}
 	return ""
 }
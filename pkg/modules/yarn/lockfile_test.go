@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+func TestReadTruncatedLockFile(t *testing.T) {
+	_, err := readLockFile(filepath.Join("test", "truncated.yarn.lock"))
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file test/truncated.yarn.lock: line 10: entry debug@^4.3.1 has no version, the file is truncated")
+}
+
+func TestReadCorruptLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "yarn.lock")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("  version \"2.1.2\"\n"), 0644))
+	_, err := readLockFile(path)
+	assert.True(t, errors.Is(err, reader.ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file "+path+": line 1: indented line outside of an entry")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("ms:\n  version \"2.1.2\"\n"), 0644))
+	_, err = readLockFile(path)
+	assert.EqualError(t, err, "malformed file "+path+": line 2: entry ms has no version range")
+}
@@ -9,4 +9,6 @@ type dependency struct {
 	Resolved     string
 	Integrity    string
 	Dependencies []string
+	// Descriptors are the name@range the entry resolves, e.g. lodash@^4.17.20
+	Descriptors []string
 }
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// pnpFiles are the Plug'n'Play files yarn 2+ writes instead of node_modules, the data file
+// is written when the state is not inlined in the loader
+var pnpFiles = []string{".pnp.data.json", ".pnp.cjs", ".pnp.js"}
+
+var errPnpStateNotFound = errors.New("no Plug'n'Play runtime state found")
+
+// pnpState is the part of the Plug'n'Play runtime state telling where the packages are installed
+type pnpState struct {
+	// PackageRegistryData lists [name, [[reference, {packageLocation, ...}], ...]] per package
+	PackageRegistryData [][]json.RawMessage `json:"packageRegistryData"`
+}
+
+// hasPnpInstall tells whether yarn installed the project with Plug'n'Play
+func hasPnpInstall(path string) bool {
+	for _, file := range pnpFiles {
+		if helper.Exists(filepath.Join(path, file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPnpLocations returns the install location of the packages keyed by their locator, e.g.
+// lodash@npm:4.17.21, relative to the project. It is empty when yarn did not install with Plug'n'Play
+func readPnpLocations(path string) (map[string]string, error) {
+	for _, file := range pnpFiles {
+		data, err := ioutil.ReadFile(filepath.Join(path, file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		state, err := parsePnpState(filepath.Join(path, file), data)
+		if err != nil {
+			return nil, err
+		}
+		return state.locations(), nil
+	}
+
+	return map[string]string{}, nil
+}
+
+// parsePnpState reads the runtime state of .pnp.data.json, or the one the loader inlines either as
+// the RAW_RUNTIME_STATE string (yarn 3+) or as the object literal given to hydrateRuntimeState (yarn 2)
+func parsePnpState(fileName string, data []byte) (pnpState, error) {
+	text := string(data)
+	if strings.HasSuffix(fileName, ".json") {
+		var state pnpState
+		err := reader.DecodeJSON(fileName, data, &state)
+		return state, err
+	}
+
+	for _, marker := range []string{"RAW_RUNTIME_STATE =", "JSON.parse("} {
+		i := strings.Index(text, marker)
+		if i < 0 {
+			continue
+		}
+		literal := strings.TrimLeft(text[i+len(marker):], " \t\r\n")
+		if !strings.HasPrefix(literal, "'") {
+			continue
+		}
+		raw, ok := unquoteJSString(literal)
+		if !ok {
+			return pnpState{}, errPnpStateNotFound
+		}
+		var state pnpState
+		err := reader.DecodeJSON(fileName, []byte(raw), &state)
+		return state, err
+	}
+
+	if i := strings.Index(text, "hydrateRuntimeState({"); i >= 0 {
+		// the decoder stops at the end of the object, ignoring the code after it
+		var state pnpState
+		err := json.NewDecoder(strings.NewReader(text[i+len("hydrateRuntimeState("):])).Decode(&state)
+		return state, err
+	}
+
+	return pnpState{}, errPnpStateNotFound
+}
+
+// unquoteJSString returns the content of the single quoted JavaScript string text starts with,
+// the line continuations are dropped
+func unquoteJSString(text string) (string, bool) {
+	var b strings.Builder
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		if c == '\'' {
+			return b.String(), true
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i == len(text) {
+			break
+		}
+		switch text[i] {
+		case '\n':
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return "", false
+}
+
+// locations returns the location of the packages keyed by locator, the virtual packages yarn creates
+// for the peer dependencies are left out, their package is listed with its own reference
+func (s pnpState) locations() map[string]string {
+	locations := map[string]string{}
+	for _, entry := range s.PackageRegistryData {
+		if len(entry) != 2 {
+			continue
+		}
+		var name *string
+		var references [][]json.RawMessage
+		if json.Unmarshal(entry[0], &name) != nil || name == nil || json.Unmarshal(entry[1], &references) != nil {
+			continue
+		}
+
+		for _, reference := range references {
+			if len(reference) != 2 {
+				continue
+			}
+			var ref *string
+			var info struct {
+				PackageLocation string `json:"packageLocation"`
+			}
+			if json.Unmarshal(reference[0], &ref) != nil || ref == nil || json.Unmarshal(reference[1], &info) != nil {
+				continue
+			}
+			if strings.HasPrefix(*ref, "virtual:") {
+				continue
+			}
+			locations[*name+"@"+*ref] = info.PackageLocation
+		}
+	}
+	return locations
+}
+
+// packageDirectory returns the directory holding the package.json and license files of the package
+// installed at location, relative to the project path. The packages of the yarn cache are in zip
+// archives, their top level files are extracted to a temporary directory removed by cleanup
+func packageDirectory(path, location string) (dir string, cleanup func(), err error) {
+	location = filepath.FromSlash(location)
+	i := strings.Index(location, ".zip"+string(filepath.Separator))
+	if i < 0 {
+		return filepath.Join(path, location), func() {}, nil
+	}
+
+	archive := filepath.Join(path, location[:i+len(".zip")])
+	prefix := filepath.ToSlash(location[i+len(".zip")+1:])
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	dir, err = ioutil.TempDir("", "yarn-package")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	if err := extractTopLevelFiles(archive, prefix, dir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return dir, cleanup, nil
+}
+
+// extractTopLevelFiles writes the files of the archive directory prefix to dir, its subdirectories are skipped
+func extractTopLevelFiles(archive, prefix, dir string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		name := strings.TrimPrefix(file.Name, prefix)
+		if name == file.Name || name == "" || strings.Contains(name, "/") {
+			continue
+		}
+		if err := extractFile(file, filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(file *zip.File, target string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPnpLocations(t *testing.T) {
+	locations, err := readPnpLocations(filepath.Join("test", "berry"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"@babel/helper-validator-identifier@npm:7.14.0": "./.yarn/cache/@babel-helper-validator-identifier-npm-7.14.0-d6e7fc1f8a-6276d4a7fb.zip/node_modules/@babel/helper-validator-identifier/",
+		"berry-app@workspace:.":                         "./",
+		"debug@npm:4.3.1":                               "./.yarn/cache/debug-npm-4.3.1-22e4c2fe37-2c3352e37d.zip/node_modules/debug/",
+		"ms@npm:2.1.2":                                  "./.yarn/unplugged/ms-npm-2.1.2-ab1c2d3e4f/node_modules/ms/",
+		"ms@npm:2.1.3":                                  "./.yarn/cache/ms-npm-2.1.3-81ff3cfac1-aa92de6080.zip/node_modules/ms/",
+	}, locations)
+
+	locations, err = readPnpLocations("test")
+	assert.NoError(t, err)
+	assert.Empty(t, locations)
+}
+
+func TestParsePnpState(t *testing.T) {
+	// yarn 2 inlines the state as an object literal
+	loader := `function $$SETUP_STATE(hydrateRuntimeState, basePath) {
+  return hydrateRuntimeState({
+    "packageRegistryData": [
+      [null, [[null, {"packageLocation": "./", "linkType": "SOFT"}]]],
+      ["ms", [["npm:2.1.3", {"packageLocation": "./.yarn/cache/ms-npm-2.1.3-81ff3cfac1-aa92de6080.zip/node_modules/ms/", "linkType": "HARD"}]]]
+    ]
+  }, {
+    basePath: basePath || __dirname,
+  });
+}`
+	state, err := parsePnpState(".pnp.js", []byte(loader))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ms@npm:2.1.3": "./.yarn/cache/ms-npm-2.1.3-81ff3cfac1-aa92de6080.zip/node_modules/ms/"}, state.locations())
+
+	_, err = parsePnpState(".pnp.cjs", []byte("module.exports = {};"))
+	assert.Equal(t, errPnpStateNotFound, err)
+}
+
+func TestPackageDirectory(t *testing.T) {
+	path := filepath.Join("test", "berry")
+	dir, cleanup, err := packageDirectory(path, "./.yarn/cache/ms-npm-2.1.3-81ff3cfac1-aa92de6080.zip/node_modules/ms/")
+	assert.NoError(t, err)
+	defer cleanup()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "index.js"), filepath.Join(dir, "license.md"), filepath.Join(dir, "package.json")}, files)
+
+	dir, _, err = packageDirectory(path, "./.yarn/unplugged/ms-npm-2.1.2-ab1c2d3e4f/node_modules/ms/")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(path, ".yarn", "unplugged", "ms-npm-2.1.2-ab1c2d3e4f", "node_modules", "ms"), dir)
+
+	_, _, err = packageDirectory(path, "./.yarn/cache/missing-npm-1.0.0.zip/node_modules/missing/")
+	assert.Error(t, err)
+}
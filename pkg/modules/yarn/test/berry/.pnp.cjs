@@ -0,0 +1,173 @@
+#!/usr/bin/env node
+/* eslint-disable */
+"use strict";
+
+const RAW_RUNTIME_STATE =
+'{\
+  "__info": [\
+    "This file is automatically generated. Do not touch it, or risk",\
+    "your modifications being lost."\
+  ],\
+  "dependencyTreeRoots": [\
+    {\
+      "name": "berry-app",\
+      "reference": "workspace:."\
+    }\
+  ],\
+  "enableTopLevelFallback": true,\
+  "ignorePatternData": "(^(?:\\\\\\\\.yarn\\\\\\\\/sdks(?:\\\\\\\\/(?!\\\\\\\\.{1,2}(?:\\\\\\\\/|$))(?:(?:(?!(?:^|\\\\\\\\/)\\\\\\\\.{1,2}(?:\\\\\\\\/|$)).)*?)|$))$)",\
+  "fallbackExclusionList": [\
+    [\
+      "berry-app",\
+      [\
+        "workspace:."\
+      ]\
+    ]\
+  ],\
+  "fallbackPool": [],\
+  "packageRegistryData": [\
+    [\
+      null,\
+      [\
+        [\
+          null,\
+          {\
+            "packageLocation": "./",\
+            "packageDependencies": [\
+              [\
+                "@babel/helper-validator-identifier",\
+                "npm:7.14.0"\
+              ],\
+              [\
+                "debug",\
+                "virtual:4b1d9bd5#npm:4.3.1"\
+              ],\
+              [\
+                "ms",\
+                "npm:2.1.3"\
+              ]\
+            ],\
+            "linkType": "SOFT"\
+          }\
+        ]\
+      ]\
+    ],\
+    [\
+      "@babel/helper-validator-identifier",\
+      [\
+        [\
+          "npm:7.14.0",\
+          {\
+            "packageLocation": "./.yarn/cache/@babel-helper-validator-identifier-npm-7.14.0-d6e7fc1f8a-6276d4a7fb.zip/node_modules/@babel/helper-validator-identifier/",\
+            "packageDependencies": [\
+              [\
+                "@babel/helper-validator-identifier",\
+                "npm:7.14.0"\
+              ]\
+            ],\
+            "linkType": "HARD"\
+          }\
+        ]\
+      ]\
+    ],\
+    [\
+      "berry-app",\
+      [\
+        [\
+          "workspace:.",\
+          {\
+            "packageLocation": "./",\
+            "packageDependencies": [\
+              [\
+                "berry-app",\
+                "workspace:."\
+              ]\
+            ],\
+            "linkType": "SOFT"\
+          }\
+        ]\
+      ]\
+    ],\
+    [\
+      "debug",\
+      [\
+        [\
+          "npm:4.3.1",\
+          {\
+            "packageLocation": "./.yarn/cache/debug-npm-4.3.1-22e4c2fe37-2c3352e37d.zip/node_modules/debug/",\
+            "packageDependencies": [\
+              [\
+                "debug",\
+                "npm:4.3.1"\
+              ],\
+              [\
+                "ms",\
+                "npm:2.1.2"\
+              ]\
+            ],\
+            "linkType": "HARD"\
+          }\
+        ],\
+        [\
+          "virtual:4b1d9bd5#npm:4.3.1",\
+          {\
+            "packageLocation": "./.yarn/__virtual__/debug-virtual-4b1d9bd5/0/cache/debug-npm-4.3.1-22e4c2fe37-2c3352e37d.zip/node_modules/debug/",\
+            "packageDependencies": [\
+              [\
+                "debug",\
+                "virtual:4b1d9bd5#npm:4.3.1"\
+              ],\
+              [\
+                "ms",\
+                "npm:2.1.2"\
+              ],\
+              [\
+                "supports-color",\
+                null\
+              ]\
+            ],\
+            "linkType": "HARD"\
+          }\
+        ]\
+      ]\
+    ],\
+    [\
+      "ms",\
+      [\
+        [\
+          "npm:2.1.2",\
+          {\
+            "packageLocation": "./.yarn/unplugged/ms-npm-2.1.2-ab1c2d3e4f/node_modules/ms/",\
+            "packageDependencies": [\
+              [\
+                "ms",\
+                "npm:2.1.2"\
+              ]\
+            ],\
+            "linkType": "HARD"\
+          }\
+        ],\
+        [\
+          "npm:2.1.3",\
+          {\
+            "packageLocation": "./.yarn/cache/ms-npm-2.1.3-81ff3cfac1-aa92de6080.zip/node_modules/ms/",\
+            "packageDependencies": [\
+              [\
+                "ms",\
+                "npm:2.1.3"\
+              ]\
+            ],\
+            "linkType": "HARD"\
+          }\
+        ]\
+      ]\
+    ]\
+  ]\
+}';
+
+function $$SETUP_STATE(hydrateRuntimeState, basePath) {
+  return hydrateRuntimeState(JSON.parse(RAW_RUNTIME_STATE), {basePath: basePath || __dirname});
+}
+
+const fs = require('fs');
+const path = require('path');
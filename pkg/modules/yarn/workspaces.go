@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/reader"
+)
+
+// manifestDependencySections are the package.json dependency fields and the scope of their dependencies
+var manifestDependencySections = []struct {
+	field string
+	scope string
+}{
+	{"dependencies", ""},
+	{"optionalDependencies", ""},
+	{"devDependencies", "dev"},
+}
+
+// addWorkspaces appends the workspace packages of a yarn 1 project, found with the workspaces patterns of
+// its package.json, to the modules. Each is linked to the lockfile entries its dependency ranges resolve to
+// and to the workspace packages it depends on, the root contains them
+func (m *yarn) addWorkspaces(path string, modules []models.Module, deps []dependency) ([]models.Module, error) {
+	manifest, err := reader.New(filepath.Join(path, m.metadata.Manifest[0])).ReadJson()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := helper.FindWorkspaces(path, helper.WorkspacePatterns(manifest))
+	if err != nil || len(dirs) == 0 {
+		return modules, err
+	}
+
+	byDescriptor := map[string]dependency{}
+	for _, d := range deps {
+		for _, descriptor := range d.Descriptors {
+			byDescriptor[descriptor] = d
+		}
+	}
+
+	workspaces := map[string]int{}
+	for _, dir := range dirs {
+		workspace, err := m.buildRootModule(filepath.Join(path, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, err
+		}
+		workspaces[workspace.Name] = len(modules)
+		modules = append(modules, *workspace)
+	}
+
+	for i, dir := range dirs {
+		workspace := &modules[len(modules)-len(dirs)+i]
+		for _, d := range manifestDependencies(filepath.Join(path, filepath.FromSlash(dir))) {
+			var link *models.Module
+			if index, ok := workspaces[d.Name]; ok {
+				link = bareModule(modules[index].Name, modules[index].Version)
+			} else if entry, ok := byDescriptor[d.Name+"@"+d.Range]; ok {
+				// the yarn 1 modules are named without the @ of their scope
+				link = bareModule(strings.TrimPrefix(d.Name, "@"), extractVersion(entry.Version))
+			} else {
+				continue
+			}
+			if d.Scope != "" {
+				link.Relationship = models.ScopeRelationship(d.Scope)
+			}
+			workspace.Modules[link.Name] = link
+		}
+	}
+
+	containWorkspaces(modules, workspaces)
+	return modules, nil
+}
+
+// manifestDependency is a dependency range declared in a package.json
+type manifestDependency struct {
+	Name  string
+	Range string
+	Scope string
+}
+
+// manifestDependencies returns the dependencies declared in the package.json of dir, a dependency
+// also declared for development keeps the runtime scope
+func manifestDependencies(dir string) []manifestDependency {
+	manifest, err := reader.New(filepath.Join(dir, "package.json")).ReadJson()
+	if err != nil {
+		return nil
+	}
+
+	var dependencies []manifestDependency
+	seen := map[string]bool{}
+	for _, section := range manifestDependencySections {
+		ranges, _ := manifest[section.field].(map[string]interface{})
+		for name, value := range ranges {
+			version, ok := value.(string)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			dependencies = append(dependencies, manifestDependency{Name: name, Range: version, Scope: section.scope})
+		}
+	}
+	return dependencies
+}
+
+// devDependencyNames returns the names of the dependencies the package.json of dir only declares for development
+func devDependencyNames(dir string) map[string]bool {
+	names := map[string]bool{}
+	for _, d := range manifestDependencies(dir) {
+		if d.Scope == "dev" {
+			names[d.Name] = true
+		}
+	}
+	return names
+}
+
+// containWorkspaces links the workspace modules, keyed by name, to the root with a CONTAINS relationship
+func containWorkspaces(modules []models.Module, workspaces map[string]int) {
+	for _, index := range workspaces {
+		link := bareModule(modules[index].Name, modules[index].Version)
+		link.Relationship = models.RelationshipContains
+		modules[0].Modules[link.Name] = link
+	}
+}
+
+// bareModule returns the module linked to its dependents, the name and version identify the listed one
+func bareModule(name, version string) *models.Module {
+	return &models.Module{
+		Name:     name,
+		Version:  version,
+		CheckSum: &models.CheckSum{Content: []byte(fmt.Sprintf("%s-%s", name, version))},
+	}
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDepsWorkspaces(t *testing.T) {
+	for _, fixture := range []string{"workspaces", "berry-workspaces"} {
+		mods, err := New().ListModulesWithDeps(filepath.Join("test", fixture))
+		assert.NoError(t, err, fixture)
+
+		byName := map[string]models.Module{}
+		for _, mod := range mods {
+			byName[mod.Name] = mod
+		}
+
+		root := mods[0]
+		assert.Equal(t, models.RelationshipContains, root.Modules["app"].Relationship, fixture)
+		assert.Equal(t, models.RelationshipContains, root.Modules["@example/lib"].Relationship, fixture)
+		assert.Equal(t, "1.0.0", root.Modules["@example/lib"].Version, fixture)
+
+		// the workspace packages are versioned by their package.json and depend on each other
+		app := byName["app"]
+		assert.Equal(t, "0.1.0", app.Version, fixture)
+		assert.Equal(t, "NONE", app.PackageDownloadLocation, fixture)
+		assert.Equal(t, "1.0.0", app.Modules["@example/lib"].Version, fixture)
+		assert.Equal(t, models.RelationshipType(""), app.Modules["@example/lib"].Relationship, fixture)
+		assert.Equal(t, models.RelationshipType(""), app.Modules["debug"].Relationship, fixture)
+
+		lib := byName["@example/lib"]
+		assert.Equal(t, "1.0.0", lib.Version, fixture)
+		assert.Equal(t, "2.1.3", lib.Modules["ms"].Version, fixture)
+		assert.Equal(t, models.RelationshipDevDependencyOf, lib.Modules["ms"].Relationship, fixture)
+	}
+}
+
+func TestManifestDependencies(t *testing.T) {
+	dependencies := manifestDependencies(filepath.Join("test", "berry-workspaces", "packages", "app"))
+	assert.ElementsMatch(t, []manifestDependency{
+		{Name: "@example/lib", Range: "workspace:^"},
+		{Name: "debug", Range: "^4.3.1"},
+	}, dependencies)
+
+	assert.Equal(t, map[string]bool{"ms": true}, devDependencyNames(filepath.Join("test", "berry-workspaces")))
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// category groups licenses sharing the same compatibility rules
+type category string
+
+const (
+	permissive      category = "permissive"
+	weakCopyleft    category = "weak-copyleft"
+	gpl2Only        category = "GPL-2.0-only"
+	strongCopyleft  category = "strong-copyleft"
+	networkCopyleft category = "network-copyleft"
+)
+
+// categories classifies the common licenses, unknown licenses are not checked
+var categories = map[string]category{
+	"MIT":               permissive,
+	"ISC":               permissive,
+	"0BSD":              permissive,
+	"BSD-2-Clause":      permissive,
+	"BSD-3-Clause":      permissive,
+	"Apache-2.0":        permissive,
+	"Unlicense":         permissive,
+	"CC0-1.0":           permissive,
+	"Zlib":              permissive,
+	"MPL-2.0":           weakCopyleft,
+	"EPL-1.0":           weakCopyleft,
+	"EPL-2.0":           weakCopyleft,
+	"LGPL-2.1-only":     weakCopyleft,
+	"LGPL-2.1-or-later": weakCopyleft,
+	"LGPL-3.0-only":     weakCopyleft,
+	"LGPL-3.0-or-later": weakCopyleft,
+	"GPL-2.0-only":      gpl2Only,
+	"GPL-2.0":           gpl2Only,
+	"GPL-2.0-or-later":  strongCopyleft,
+	"GPL-3.0-only":      strongCopyleft,
+	"GPL-3.0-or-later":  strongCopyleft,
+	"GPL-3.0":           strongCopyleft,
+	"AGPL-3.0-only":     networkCopyleft,
+	"AGPL-3.0-or-later": networkCopyleft,
+	"AGPL-3.0":          networkCopyleft,
+}
+
+// incompatible lists, for a project license category, the dependency license categories it cannot include
+var incompatible = map[category]map[category]bool{
+	permissive:      {gpl2Only: true, strongCopyleft: true, networkCopyleft: true},
+	weakCopyleft:    {gpl2Only: true, strongCopyleft: true, networkCopyleft: true},
+	gpl2Only:        {networkCopyleft: true, strongCopyleft: true},
+	strongCopyleft:  {},
+	networkCopyleft: {},
+}
+
+// exceptions are specific pairings conflicting within compatible categories
+var exceptions = map[string]map[string]bool{
+	"GPL-2.0-only": {"Apache-2.0": true, "LGPL-3.0-only": true, "LGPL-3.0-or-later": true},
+	"GPL-2.0":      {"Apache-2.0": true, "LGPL-3.0-only": true, "LGPL-3.0-or-later": true},
+}
+
+// Violation is a dependency whose license cannot be combined with the project license
+type Violation struct {
+	Package        string
+	Version        string
+	License        string
+	ProjectLicense string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("license conflict: package %s %s licensed under %s is incompatible with the project license %s",
+		v.Package, v.Version, v.License, v.ProjectLicense)
+}
+
+// Check returns the license conflicts between the root module and its dependencies.
+// A dependency is compatible when any alternative of its license expression is
+func Check(modules []models.Module) []Violation {
+	var root *models.Module
+	for i := range modules {
+		if modules[i].Root {
+			root = &modules[i]
+			break
+		}
+	}
+	if root == nil {
+		return nil
+	}
+
+	projectLicense := license(*root)
+	if projectLicense == "" {
+		return nil
+	}
+
+	var violations []Violation
+	for _, module := range modules {
+		if module.Root {
+			continue
+		}
+
+		dependencyLicense := license(module)
+		if dependencyLicense == "" || Compatible(projectLicense, dependencyLicense) {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Package:        module.Name,
+			Version:        module.Version,
+			License:        dependencyLicense,
+			ProjectLicense: projectLicense,
+		})
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Package < violations[j].Package
+	})
+
+	return violations
+}
+
+// Compatible reports whether a dependency licensed under dependencyLicense can be included in a
+// project licensed under projectLicense, both being SPDX license expressions
+func Compatible(projectLicense, dependencyLicense string) bool {
+	// the project must satisfy every license it is distributed under
+	for _, project := range alternatives(projectLicense) {
+		if !compatibleWithAll(project, dependencyLicense) {
+			return false
+		}
+	}
+	return true
+}
+
+// compatibleWithAll checks a project license set against every dependency alternative
+func compatibleWithAll(project []string, dependencyLicense string) bool {
+	for _, dependency := range alternatives(dependencyLicense) {
+		if compatibleSets(project, dependency) {
+			return true
+		}
+	}
+	return false
+}
+
+func compatibleSets(project, dependency []string) bool {
+	for _, p := range project {
+		for _, d := range dependency {
+			if !compatiblePair(p, d) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func compatiblePair(project, dependency string) bool {
+	if project == dependency {
+		return true
+	}
+
+	if exceptions[project][dependency] {
+		return false
+	}
+
+	projectCategory, ok := categories[project]
+	if !ok {
+		return true
+	}
+	dependencyCategory, ok := categories[dependency]
+	if !ok {
+		return true
+	}
+
+	return !incompatible[projectCategory][dependencyCategory]
+}
+
+// alternatives splits an expression in its OR alternatives, each being the licenses joined by AND
+func alternatives(expression string) [][]string {
+	expression = strings.NewReplacer("(", " ", ")", " ").Replace(expression)
+
+	var result [][]string
+	for _, alternative := range strings.Split(expression, " OR ") {
+		var licenses []string
+		for _, license := range strings.Split(alternative, " AND ") {
+			license = strings.TrimSpace(license)
+			// exceptions only grant additional permissions
+			if idx := strings.Index(license, " WITH "); idx >= 0 {
+				license = strings.TrimSpace(license[:idx])
+			}
+			if license != "" {
+				licenses = append(licenses, license)
+			}
+		}
+		if len(licenses) > 0 {
+			result = append(result, licenses)
+		}
+	}
+
+	return result
+}
+
+func license(module models.Module) string {
+	for _, l := range []string{module.LicenseConcluded, module.LicenseDeclared} {
+		if l != "" && l != "NOASSERTION" && l != "NONE" {
+			return l
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestCheck(t *testing.T) {
+	modules := []models.Module{
+		{Name: "root", Root: true, LicenseDeclared: "Apache-2.0"},
+		{Name: "gpl-lib", Version: "1.2.0", LicenseDeclared: "GPL-3.0-only"},
+		{Name: "mit-lib", Version: "2.0.0", LicenseDeclared: "MIT"},
+		{Name: "dual-lib", Version: "3.0.0", LicenseDeclared: "MIT OR GPL-3.0-only"},
+		{Name: "unknown-lib", Version: "4.0.0", LicenseDeclared: "NOASSERTION"},
+	}
+
+	violations := Check(modules)
+
+	assert.Equal(t, []Violation{{
+		Package:        "gpl-lib",
+		Version:        "1.2.0",
+		License:        "GPL-3.0-only",
+		ProjectLicense: "Apache-2.0",
+	}}, violations)
+	assert.Equal(t, "license conflict: package gpl-lib 1.2.0 licensed under GPL-3.0-only is incompatible with the project license Apache-2.0", violations[0].String())
+}
+
+func TestCheckWithoutProjectLicense(t *testing.T) {
+	modules := []models.Module{
+		{Name: "root", Root: true},
+		{Name: "gpl-lib", LicenseDeclared: "GPL-3.0-only"},
+	}
+
+	assert.Empty(t, Check(modules))
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		project    string
+		dependency string
+		expected   bool
+	}{
+		{"Apache-2.0", "MIT", true},
+		{"Apache-2.0", "GPL-3.0-only", false},
+		{"MIT", "AGPL-3.0-only", false},
+		{"GPL-3.0-only", "Apache-2.0", true},
+		{"GPL-2.0-only", "Apache-2.0", false},
+		{"GPL-2.0-only", "MIT", true},
+		{"Apache-2.0", "MIT AND GPL-2.0-only", false},
+		{"Apache-2.0", "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", true},
+		{"Apache-2.0", "LicenseRef-Custom", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Compatible(test.project, test.dependency), test.project+" <- "+test.dependency)
+	}
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package purl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const scheme = "pkg:"
+
+var (
+	errMissingScheme   = errors.New("purl must start with pkg:")
+	errInvalidType     = errors.New("purl type is missing or invalid")
+	errMissingName     = errors.New("purl name is missing")
+	errInvalidQualifer = errors.New("purl qualifier is invalid")
+
+	typeRegex      = regexp.MustCompile(`^[a-z.+\-][a-z0-9.+\-]*$`)
+	qualifierRegex = regexp.MustCompile(`^[a-z.\-_][a-z0-9.\-_]*$`)
+)
+
+// PackageURL is a package url as defined by https://github.com/package-url/purl-spec,
+// components are stored decoded and encoded by String
+type PackageURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// New creates a package url from decoded components
+func New(purlType, namespace, name, version string) PackageURL {
+	return PackageURL{
+		Type:      purlType,
+		Namespace: namespace,
+		Name:      name,
+		Version:   version,
+	}
+}
+
+// WithQualifier returns a copy of the package url with the qualifier set, empty values are ignored
+func (p PackageURL) WithQualifier(key, value string) PackageURL {
+	if value == "" {
+		return p
+	}
+
+	qualifiers := map[string]string{}
+	for k, v := range p.Qualifiers {
+		qualifiers[k] = v
+	}
+	qualifiers[strings.ToLower(key)] = value
+	p.Qualifiers = qualifiers
+
+	return p
+}
+
+// Validate checks the package url components against the spec
+func (p PackageURL) Validate() error {
+	if !typeRegex.MatchString(strings.ToLower(p.Type)) {
+		return errInvalidType
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return errMissingName
+	}
+	for key, value := range p.Qualifiers {
+		if !qualifierRegex.MatchString(strings.ToLower(key)) || value == "" {
+			return fmt.Errorf("%w: %s", errInvalidQualifer, key)
+		}
+	}
+	return nil
+}
+
+// String returns the canonical, percent-encoded form of the package url
+func (p PackageURL) String() string {
+	var sb strings.Builder
+	sb.WriteString(scheme)
+	sb.WriteString(strings.ToLower(p.Type))
+	sb.WriteString("/")
+
+	if namespace := encodeSegments(p.Namespace); namespace != "" {
+		sb.WriteString(namespace)
+		sb.WriteString("/")
+	}
+	sb.WriteString(escape(strings.Trim(p.Name, "/")))
+
+	if p.Version != "" {
+		sb.WriteString("@")
+		sb.WriteString(escape(p.Version))
+	}
+
+	if len(p.Qualifiers) > 0 {
+		keys := make([]string, 0, len(p.Qualifiers))
+		for key, value := range p.Qualifiers {
+			if value != "" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for i, key := range keys {
+			if i == 0 {
+				sb.WriteString("?")
+			} else {
+				sb.WriteString("&")
+			}
+			sb.WriteString(strings.ToLower(key))
+			sb.WriteString("=")
+			sb.WriteString(escape(p.Qualifiers[key]))
+		}
+	}
+
+	if subpath := encodeSegments(p.Subpath); subpath != "" {
+		sb.WriteString("#")
+		sb.WriteString(subpath)
+	}
+
+	return sb.String()
+}
+
+// Parse reads a package url, percent-encoded components are decoded
+func Parse(s string) (PackageURL, error) {
+	var p PackageURL
+	if !strings.HasPrefix(s, scheme) {
+		return p, errMissingScheme
+	}
+	remainder := strings.TrimLeft(strings.TrimPrefix(s, scheme), "/")
+
+	if idx := strings.LastIndex(remainder, "#"); idx >= 0 {
+		p.Subpath = decodeSegments(remainder[idx+1:])
+		remainder = remainder[:idx]
+	}
+
+	if idx := strings.LastIndex(remainder, "?"); idx >= 0 {
+		p.Qualifiers = map[string]string{}
+		for _, pair := range strings.Split(remainder[idx+1:], "&") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return p, fmt.Errorf("%w: %s", errInvalidQualifer, pair)
+			}
+			p.Qualifiers[strings.ToLower(kv[0])] = unescape(kv[1])
+		}
+		remainder = remainder[:idx]
+	}
+
+	idx := strings.Index(remainder, "/")
+	if idx <= 0 {
+		return p, errInvalidType
+	}
+	p.Type = strings.ToLower(remainder[:idx])
+	remainder = strings.Trim(remainder[idx+1:], "/")
+
+	if idx := strings.LastIndex(remainder, "@"); idx >= 0 && !strings.Contains(remainder[idx:], "/") {
+		p.Version = unescape(remainder[idx+1:])
+		remainder = remainder[:idx]
+	}
+
+	if idx := strings.LastIndex(remainder, "/"); idx >= 0 {
+		p.Namespace = decodeSegments(remainder[:idx])
+		remainder = remainder[idx+1:]
+	}
+	p.Name = unescape(remainder)
+
+	return p, p.Validate()
+}
+
+// Normalize parses and re-encodes a package url, fixing unescaped characters
+func Normalize(s string) (string, error) {
+	p, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+// escape percent-encodes everything but the unreserved characters, ':' is allowed as is
+func escape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) || c == ':' {
+			sb.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", c)
+	}
+	return sb.String()
+}
+
+func isUnreserved(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func unescape(s string) string {
+	unescaped, err := url.PathUnescape(s)
+	if err != nil {
+		return s
+	}
+	return unescaped
+}
+
+func encodeSegments(s string) string {
+	var segments []string
+	for _, segment := range strings.Split(s, "/") {
+		if segment != "" && segment != "." && segment != ".." {
+			segments = append(segments, escape(segment))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func decodeSegments(s string) string {
+	var segments []string
+	for _, segment := range strings.Split(s, "/") {
+		if segment != "" {
+			segments = append(segments, unescape(segment))
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package purl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		purl     PackageURL
+		expected string
+	}{
+		{New("npm", "@angular", "animation", "12.3.1"), "pkg:npm/%40angular/animation@12.3.1"},
+		{New("maven", "org.apache.xmlgraphics", "batik-anim", "1.9.1").WithQualifier("type", "aar"), "pkg:maven/org.apache.xmlgraphics/batik-anim@1.9.1?type=aar"},
+		{New("generic", "my company", "tool & lib", "1.0 beta+1"), "pkg:generic/my%20company/tool%20%26%20lib@1.0%20beta%2B1"},
+		{New("golang", "github.com/spdx", "tools-golang", "v0.1.0"), "pkg:golang/github.com/spdx/tools-golang@v0.1.0"},
+		{New("PyPI", "", "Django", ""), "pkg:pypi/Django"},
+	}
+
+	for _, test := range tests {
+		assert.NoError(t, test.purl.Validate())
+		assert.Equal(t, test.expected, test.purl.String())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"pkg:generic/my company/tool & lib@1.0 beta":          "pkg:generic/my%20company/tool%20%26%20lib@1.0%20beta",
+		"pkg:npm/%40angular/animation@12.3.1":                 "pkg:npm/%40angular/animation@12.3.1",
+		"pkg:maven/org.example/lib@1.0?Type=aar&classifier=x": "pkg:maven/org.example/lib@1.0?classifier=x&type=aar",
+		"pkg:gem/ruby-advisory-db-check@0.12.4#lib/sub dir":   "pkg:gem/ruby-advisory-db-check@0.12.4#lib/sub%20dir",
+	}
+
+	for input, expected := range tests {
+		normalized, err := Normalize(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, normalized, input)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{
+		"maven/org.example/lib@1.0",
+		"pkg:/lib@1.0",
+		"pkg:1maven/org.example/lib@1.0",
+		"pkg:maven/org.example/@1.0",
+		"pkg:maven/org.example/lib@1.0?type",
+	} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxControlLine is the longest line of a control file, the Conffiles and Description fields of a
+// status database can be long
+const maxControlLine = 1024 * 1024
+
+// Paragraph is a paragraph of a control file, the lines of a field continued on the next lines are
+// joined with a newline
+type Paragraph map[string]string
+
+// ReadParagraphs reads the paragraphs of a control file in the Debian format, e.g. the dpkg status
+// database or an R DESCRIPTION. The paragraphs are separated by empty lines:
+//
+//	Package: zlib1g
+//	Version: 1:1.2.13.dfsg-1
+//	Description: compression library - runtime
+//	 zlib is a library implementing the deflate compression method
+func ReadParagraphs(fileName string, r io.Reader) ([]Paragraph, error) {
+	var paragraphs []Paragraph
+	var paragraph Paragraph
+	field := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxControlLine)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case line == "":
+			if paragraph != nil {
+				paragraphs = append(paragraphs, paragraph)
+			}
+			paragraph, field = nil, ""
+		case line[0] == ' ' || line[0] == '\t':
+			if field == "" {
+				return nil, MalformedError(fileName, number, "continuation line without a field")
+			}
+			if value := paragraph[field]; value != "" {
+				paragraph[field] = value + "\n" + strings.TrimSpace(line)
+			} else {
+				paragraph[field] = strings.TrimSpace(line)
+			}
+		default:
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 {
+				return nil, MalformedError(fileName, number, fmt.Sprintf("unexpected %q", line))
+			}
+			if paragraph == nil {
+				paragraph = Paragraph{}
+			}
+			field = parts[0]
+			paragraph[field] = strings.TrimSpace(parts[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if paragraph != nil {
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return paragraphs, nil
+}
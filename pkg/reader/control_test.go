@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadParagraphs(t *testing.T) {
+	paragraphs, err := ReadParagraphs("status", strings.NewReader(`Package: zlib1g
+Status: install ok installed
+Version: 1:1.2.13.dfsg-1
+Description: compression library - runtime
+ zlib is a library implementing the deflate compression method
+ .
+ This package includes the shared library.
+
+
+Package: libc6
+Version: 2.36-9
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Paragraph{
+		{
+			"Package":     "zlib1g",
+			"Status":      "install ok installed",
+			"Version":     "1:1.2.13.dfsg-1",
+			"Description": "compression library - runtime\nzlib is a library implementing the deflate compression method\n.\nThis package includes the shared library.",
+		},
+		{
+			"Package": "libc6",
+			"Version": "2.36-9",
+		},
+	}, paragraphs)
+
+	_, err = ReadParagraphs("status", strings.NewReader("Package: zlib1g\n\n continued\n"))
+	assert.True(t, errors.Is(err, ErrMalformedFile))
+	assert.Contains(t, err.Error(), "status: line 3")
+
+	_, err = ReadParagraphs("status", strings.NewReader("Package zlib1g\n"))
+	assert.True(t, errors.Is(err, ErrMalformedFile))
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// ErrMalformedFile is returned when a file cannot be decoded, e.g. a truncated or corrupt lockfile
+var ErrMalformedFile = errors.New("malformed file")
+
+// MalformedError reports a decoding error of the file at the line, counted from 1
+func MalformedError(fileName string, line int, reason string) error {
+	return fmt.Errorf("%w %s: line %d: %s", ErrMalformedFile, fileName, line, reason)
+}
+
+// DecodeJSON decodes data read from fileName into v, a syntax or type error names the file
+// and the line and column it occurred at
+func DecodeJSON(fileName string, data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return fmt.Errorf("%w %s: %v", ErrMalformedFile, fileName, err)
+	}
+
+	line, column := position(data, offset)
+	return fmt.Errorf("%w %s: line %d, column %d: %v", ErrMalformedFile, fileName, line, column, err)
+}
+
+// DecodeXML decodes data read from fileName into v, a syntax error names the file and the line it occurred at
+func DecodeXML(fileName string, data []byte, v interface{}) error {
+	err := xml.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return MalformedError(fileName, syntaxErr.Line, syntaxErr.Msg)
+	}
+	return fmt.Errorf("%w %s: %v", ErrMalformedFile, fileName, err)
+}
+
+// position converts a byte offset of data to a line and column, both counted from 1
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	var v map[string]interface{}
+	assert.NoError(t, DecodeJSON("valid.json", []byte(`{"name": "valid"}`), &v))
+	assert.Equal(t, "valid", v["name"])
+
+	err := DecodeJSON("truncated.json", []byte("{\n  \"name\": \"truncated\",\n  \"version\": \"1."), &v)
+	assert.True(t, errors.Is(err, ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file truncated.json: line 3, column 17: unexpected end of JSON input")
+
+	var list []string
+	err = DecodeJSON("type.json", []byte("{\n  \"name\": 1\n}"), &list)
+	assert.True(t, errors.Is(err, ErrMalformedFile))
+	assert.Contains(t, err.Error(), "malformed file type.json: line 1, column 2: ")
+}
+
+func TestDecodeXML(t *testing.T) {
+	var v struct {
+		Name string `xml:"name"`
+	}
+	assert.NoError(t, DecodeXML("valid.xml", []byte("<project><name>valid</name></project>"), &v))
+	assert.Equal(t, "valid", v.Name)
+
+	err := DecodeXML("truncated.xml", []byte("<project>\n  <name>truncated</name>\n  <version>1."), &v)
+	assert.True(t, errors.Is(err, ErrMalformedFile))
+	assert.EqualError(t, err, "malformed file truncated.xml: line 3: unexpected EOF")
+}
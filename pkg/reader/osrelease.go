@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// osReleaseFiles are the operating system identification files, relative to the root filesystem, in the
+// order they are looked up
+var osReleaseFiles = []string{
+	filepath.Join("etc", "os-release"),
+	filepath.Join("usr", "lib", "os-release"),
+}
+
+// OSRelease identifies the operating system of a root filesystem, see os-release(5)
+type OSRelease struct {
+	// ID is the lower case name of the distribution, e.g. debian
+	ID         string
+	Name       string
+	PrettyName string
+	VersionID  string
+	// VersionCodename is the release code name, e.g. bookworm
+	VersionCodename string
+	HomeURL         string
+}
+
+// ReadOSRelease reads the os-release file of the root filesystem, etc/os-release or else usr/lib/os-release
+func ReadOSRelease(root string) (OSRelease, error) {
+	var err error
+	for _, name := range osReleaseFiles {
+		var release OSRelease
+		if release, err = readOSReleaseFile(filepath.Join(root, name)); err == nil {
+			return release, nil
+		}
+		if !os.IsNotExist(err) {
+			return OSRelease{}, err
+		}
+	}
+	return OSRelease{}, err
+}
+
+// readOSReleaseFile reads the KEY=value assignments of an os-release file, the values may be quoted
+func readOSReleaseFile(fileName string) (OSRelease, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return OSRelease{}, err
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return OSRelease{}, MalformedError(fileName, number, "expected KEY=value")
+		}
+		value := parts[1]
+		if strings.HasPrefix(value, `"`) {
+			if value, err = strconv.Unquote(value); err != nil {
+				return OSRelease{}, MalformedError(fileName, number, err.Error())
+			}
+		} else {
+			value = strings.Trim(value, "'")
+		}
+		values[parts[0]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return OSRelease{}, err
+	}
+
+	return OSRelease{
+		ID:              values["ID"],
+		Name:            values["NAME"],
+		PrettyName:      values["PRETTY_NAME"],
+		VersionID:       values["VERSION_ID"],
+		VersionCodename: values["VERSION_CODENAME"],
+		HomeURL:         values["HOME_URL"],
+	}, nil
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOSRelease(t *testing.T) {
+	root := t.TempDir()
+	_, err := ReadOSRelease(root)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr", "lib"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "usr", "lib", "os-release"), []byte(`PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+NAME="Debian GNU/Linux"
+VERSION_ID="12"
+VERSION_CODENAME=bookworm
+ID=debian
+# the project homepage
+HOME_URL='https://www.debian.org/'
+`), 0644))
+
+	release, err := ReadOSRelease(root)
+	assert.NoError(t, err)
+	assert.Equal(t, OSRelease{
+		ID:              "debian",
+		Name:            "Debian GNU/Linux",
+		PrettyName:      "Debian GNU/Linux 12 (bookworm)",
+		VersionID:       "12",
+		VersionCodename: "bookworm",
+		HomeURL:         "https://www.debian.org/",
+	}, release)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "os-release"), []byte("ID=alpine\nVERSION_ID=\"3.18\n"), 0644))
+	_, err = ReadOSRelease(root)
+	assert.True(t, errors.Is(err, ErrMalformedFile))
+}
@@ -3,7 +3,6 @@
 package reader
 
 import (
-	"encoding/json"
 	"io/ioutil"
 )
 
@@ -36,7 +35,7 @@ func (s *Reader) ReadJson() (map[string]interface{},error) {
 		return nil, err
 	}
 	var jResult map[string]interface{}
-	err = json.Unmarshal(fByte, &jResult)
+	err = DecodeJSON(s.fileName, fByte, &jResult)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,439 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Table is a TOML table, its values are strings, booleans, arrays ([]interface{}) and tables.
+// The numbers and dates are kept as strings, an array of tables is an array of tables
+type Table map[string]interface{}
+
+// Get returns the table at the dotted path, nil when it is missing. The path may go through
+// an array of tables, e.g. package of [[package]], whose last table is used
+func (t Table) Get(path ...string) Table {
+	current := t
+	for _, key := range path {
+		switch value := current[key].(type) {
+		case Table:
+			current = value
+		case []interface{}:
+			if len(value) == 0 {
+				return nil
+			}
+			last, ok := value[len(value)-1].(Table)
+			if !ok {
+				return nil
+			}
+			current = last
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// Str returns the string value of the key, "" when it is missing or not a string
+func (t Table) Str(key string) string {
+	value, _ := t[key].(string)
+	return value
+}
+
+// Tables returns the tables of an array of tables, e.g. the [[package]] entries
+func (t Table) Tables(key string) []Table {
+	values, _ := t[key].([]interface{})
+	var tables []Table
+	for _, value := range values {
+		if child, ok := value.(Table); ok {
+			tables = append(tables, child)
+		}
+	}
+	return tables
+}
+
+// Strings returns the string items of the array at key
+func (t Table) Strings(key string) []string {
+	values, _ := t[key].([]interface{})
+	var items []string
+	for _, value := range values {
+		if item, ok := value.(string); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// tomlEscapes are the characters the escape sequences of the basic strings stand for
+var tomlEscapes = map[byte]string{'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", '"': `"`, '\\': `\`}
+
+type tomlParser struct {
+	fileName string
+	data     string
+	pos      int
+	line     int
+}
+
+// ParseTOML parses the subset of TOML the lockfiles and manifests of poetry and cargo are written with: tables,
+// arrays of tables, dotted and quoted keys, basic, literal and multi-line strings, arrays spanning
+// lines and inline tables. Errors name the file and the line
+func ParseTOML(fileName string, r io.Reader) (Table, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &tomlParser{fileName: fileName, data: string(data), line: 1}
+	root := Table{}
+	current := root
+	for {
+		p.skipBlank(true)
+		if p.pos >= len(p.data) {
+			return root, nil
+		}
+
+		if p.data[p.pos] == '[' {
+			current, err = p.parseHeader(root)
+		} else {
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipBlank(false)
+		if p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+			return nil, p.errorf("unexpected %q after the value", p.rest())
+		}
+	}
+}
+
+// parseHeader parses a [table] or [[array.of.tables]] header and returns the table the next keys belong to
+func (p *tomlParser) parseHeader(root Table) (Table, error) {
+	array := strings.HasPrefix(p.data[p.pos:], "[[")
+	if array {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.data[p.pos:], closing) {
+		return nil, p.errorf("unterminated Table header")
+	}
+	p.pos += len(closing)
+
+	parent, err := p.descend(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := keys[len(keys)-1]
+	if !array {
+		return p.descend(parent, []string{last})
+	}
+
+	child := Table{}
+	switch existing := parent[last].(type) {
+	case nil:
+		parent[last] = []interface{}{child}
+	case []interface{}:
+		parent[last] = append(existing, child)
+	default:
+		return nil, p.errorf("%s is not an array of tables", strings.Join(keys, "."))
+	}
+	return child, nil
+}
+
+// descend returns the table at the keys below t, creating the missing ones
+func (p *tomlParser) descend(t Table, keys []string) (Table, error) {
+	for _, key := range keys {
+		if _, ok := t[key]; !ok {
+			t[key] = Table{}
+		}
+		child := Table{key: t[key]}.Get(key)
+		if child == nil {
+			return nil, p.errorf("%s is not a Table", key)
+		}
+		t = child
+	}
+	return t, nil
+}
+
+func (p *tomlParser) parseKeyValue(t Table) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.pos >= len(p.data) || p.data[p.pos] != '=' {
+		return p.errorf("expected = after %s", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipBlank(false)
+
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(t, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	parent[keys[len(keys)-1]] = value
+	return nil
+}
+
+// parseKey parses a dotted key whose parts are bare or quoted, e.g. tool.poetry."my-group"
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipBlank(false)
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unexpected end of file in a key")
+		}
+
+		var key string
+		switch p.data[p.pos] {
+		case '"', '\'':
+			value, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = value
+		default:
+			start := p.pos
+			for p.pos < len(p.data) && isBareKeyChar(p.data[p.pos]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("unexpected %q in a key", p.rest())
+			}
+			key = p.data[start:p.pos]
+		}
+		keys = append(keys, key)
+
+		p.skipBlank(false)
+		if p.pos >= len(p.data) || p.data[p.pos] != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseValue() (interface{}, error) {
+	if p.pos >= len(p.data) {
+		return nil, p.errorf("missing value")
+	}
+
+	switch p.data[p.pos] {
+	case '"', '\'':
+		return p.parseString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.pos
+	for p.pos < len(p.data) && !strings.ContainsRune(",]} \t\r\n#", rune(p.data[p.pos])) {
+		p.pos++
+	}
+	switch token := p.data[start:p.pos]; token {
+	case "":
+		return nil, p.errorf("missing value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return token, nil
+	}
+}
+
+func (p *tomlParser) parseArray() (interface{}, error) {
+	p.pos++
+	values := []interface{}{}
+	for {
+		p.skipBlank(true)
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return values, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skipBlank(true)
+		if p.pos < len(p.data) && p.data[p.pos] == ',' {
+			p.pos++
+		} else if p.pos < len(p.data) && p.data[p.pos] != ']' {
+			return nil, p.errorf("expected , or ] in an array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (interface{}, error) {
+	p.pos++
+	t := Table{}
+	for {
+		p.skipBlank(false)
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unterminated inline Table")
+		}
+		if p.data[p.pos] == '}' {
+			p.pos++
+			return t, nil
+		}
+
+		if err := p.parseKeyValue(t); err != nil {
+			return nil, err
+		}
+
+		p.skipBlank(false)
+		if p.pos < len(p.data) && p.data[p.pos] == ',' {
+			p.pos++
+		} else if p.pos < len(p.data) && p.data[p.pos] != '}' {
+			return nil, p.errorf("expected , or } in an inline Table")
+		}
+	}
+}
+
+// parseString parses a basic or literal string, on one line or, tripled quotes, on several
+func (p *tomlParser) parseString() (string, error) {
+	quote := p.data[p.pos]
+	if delimiter := strings.Repeat(string(quote), 3); strings.HasPrefix(p.data[p.pos:], delimiter) {
+		p.pos += 3
+		// the newline right after the opening delimiter is trimmed
+		if strings.HasPrefix(p.data[p.pos:], "\r\n") {
+			p.pos += 2
+			p.line++
+		} else if strings.HasPrefix(p.data[p.pos:], "\n") {
+			p.pos++
+			p.line++
+		}
+		end := strings.Index(p.data[p.pos:], delimiter)
+		if end < 0 {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		raw := p.data[p.pos : p.pos+end]
+		p.line += strings.Count(raw, "\n")
+		p.pos += end + 3
+		if quote == '\'' {
+			return raw, nil
+		}
+		return unescape(raw), nil
+	}
+
+	for i := p.pos + 1; i < len(p.data); i++ {
+		switch c := p.data[i]; {
+		case c == '\n':
+			return "", p.errorf("unterminated string")
+		case c == '\\' && quote == '"':
+			i++
+		case c == quote:
+			raw := p.data[p.pos+1 : i]
+			p.pos = i + 1
+			if quote == '\'' {
+				return raw, nil
+			}
+			return unescape(raw), nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// skipBlank skips the spaces and the comments, and the newlines when newlines is set
+func (p *tomlParser) skipBlank(newlines bool) {
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		case (c == '\n' || c == '\r') && newlines:
+			if c == '\n' {
+				p.line++
+			}
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) rest() string {
+	end := strings.IndexAny(p.data[p.pos:], "\r\n")
+	if end < 0 {
+		return p.data[p.pos:]
+	}
+	return p.data[p.pos : p.pos+end]
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return MalformedError(p.fileName, p.line, fmt.Sprintf(format, args...))
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// unescape returns the content of a basic string, the unknown escape sequences are kept as is
+func unescape(raw string) string {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 == len(raw) {
+			b.WriteByte(raw[i])
+			continue
+		}
+
+		switch c := raw[i+1]; c {
+		case 'b', 't', 'n', 'f', 'r', '"', '\\':
+			b.WriteString(tomlEscapes[c])
+			i++
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			if i+2+size > len(raw) {
+				b.WriteByte(raw[i])
+				continue
+			}
+			code, err := strconv.ParseUint(raw[i+2:i+2+size], 16, 32)
+			if err != nil {
+				b.WriteByte(raw[i])
+				continue
+			}
+			b.WriteRune(rune(code))
+			i += 1 + size
+		case ' ', '\t', '\r', '\n':
+			// a backslash ending a line of a multi-line string trims the whitespace up to the next non blank
+			i++
+			for i+1 < len(raw) && strings.IndexByte(" \t\r\n", raw[i+1]) >= 0 {
+				i++
+			}
+		default:
+			b.WriteByte(raw[i])
+		}
+	}
+	return b.String()
+}
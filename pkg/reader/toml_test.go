@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTOML(t *testing.T) {
+	document, err := ParseTOML("test.toml", strings.NewReader(`# a comment
+title = "poetry"   # trailing comment
+literal = 'C:\path'
+escaped = "tab\there \"quoted\" \u00e9"
+multiline = """
+first
+second"""
+enabled = true
+count = 3
+site."google.com" = 'quoted key'
+
+[tool.poetry]
+authors = [
+    "Jane Doe <jane@example.com>", # the maintainer
+    'John Doe',
+]
+
+[tool.poetry.dependencies]
+python = "^3.9"
+requests = { version = "^2.31", extras = ["socks"], optional = false }
+
+[[package]]
+name = "a"
+
+[package.dependencies]
+b = "*"
+
+[[package]]
+name = "b"
+`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "poetry", document.Str("title"))
+	assert.Equal(t, `C:\path`, document.Str("literal"))
+	assert.Equal(t, "tab\there \"quoted\" é", document.Str("escaped"))
+	assert.Equal(t, "first\nsecond", document.Str("multiline"))
+	assert.Equal(t, true, document["enabled"])
+	assert.Equal(t, "3", document.Str("count"))
+	assert.Equal(t, "quoted key", document.Get("site").Str("google.com"))
+
+	poetry := document.Get("tool", "poetry")
+	assert.Equal(t, []string{"Jane Doe <jane@example.com>", "John Doe"}, poetry.Strings("authors"))
+	requests := poetry.Get("dependencies", "requests")
+	assert.Equal(t, "^2.31", requests.Str("version"))
+	assert.Equal(t, []string{"socks"}, requests.Strings("extras"))
+	assert.Equal(t, false, requests["optional"])
+
+	// the [package.dependencies] table belongs to the [[package]] before it
+	packages := document.Tables("package")
+	assert.Len(t, packages, 2)
+	assert.Equal(t, "*", packages[0].Get("dependencies").Str("b"))
+	assert.Nil(t, packages[1].Get("dependencies"))
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	for _, content := range []string{
+		"name = \"unterminated\n",
+		"[tool\n",
+		"list = [1, 2\n",
+		"name \"missing equals\"\n",
+		"\n\nname = \"a\" junk\n",
+	} {
+		_, err := ParseTOML("broken.toml", strings.NewReader(content))
+		assert.True(t, errors.Is(err, ErrMalformedFile), content)
+		assert.Contains(t, err.Error(), "broken.toml")
+	}
+
+	_, err := ParseTOML("broken.toml", strings.NewReader("\n\nname = \"a\" junk\n"))
+	assert.Contains(t, err.Error(), "line 3")
+}
@@ -0,0 +1,356 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// YAMLNode is a value of the YAML subset the lockfiles of pnpm, conda, CocoaPods, pub and stack are
+// written with: a scalar, a mapping keeping the order of its keys or a sequence of nodes
+type YAMLNode struct {
+	Value  string
+	Keys   []string
+	Fields map[string]*YAMLNode
+	Items  []*YAMLNode
+	// Line is the line of the key of a field, else the one the node starts at
+	Line int
+}
+
+// Get returns the field of a mapping, nil for the missing ones and for a nil node
+func (n *YAMLNode) Get(key string) *YAMLNode {
+	if n == nil {
+		return nil
+	}
+	return n.Fields[key]
+}
+
+// Str returns the scalar value of a field, "" when it is missing
+func (n *YAMLNode) Str(key string) string {
+	if field := n.Get(key); field != nil {
+		return field.Value
+	}
+	return ""
+}
+
+// List returns the items of a sequence field, nil when it is missing
+func (n *YAMLNode) List(key string) []*YAMLNode {
+	if field := n.Get(key); field != nil {
+		return field.Items
+	}
+	return nil
+}
+
+// Strings returns the scalar values of the items of a sequence field
+func (n *YAMLNode) Strings(key string) []string {
+	var values []string
+	for _, item := range n.List(key) {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	fileName string
+	lines    []yamlLine
+	pos      int
+}
+
+// ParseYAML parses block mappings, block sequences of scalars and mappings, flow mappings and sequences
+// on a single line and plain or quoted scalars. Errors name the file and the line
+func ParseYAML(fileName string, r io.Reader) (*YAMLNode, error) {
+	p := &yamlParser{fileName: fileName}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimRight(StripYAMLComment(scanner.Text()), " \r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, MalformedError(fileName, number, "tab indentation")
+		}
+		p.lines = append(p.lines, yamlLine{number: number, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(p.lines) == 0 {
+		return &YAMLNode{Fields: map[string]*YAMLNode{}}, nil
+	}
+	if p.lines[0].indent != 0 {
+		return nil, MalformedError(fileName, p.lines[0].number, "unexpected indentation")
+	}
+
+	root, err := p.parseBlock(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, MalformedError(fileName, p.lines[p.pos].number, "unexpected indentation")
+	}
+	return root, nil
+}
+
+// parseBlock parses the mapping or the sequence of the lines at indent
+func (p *yamlParser) parseBlock(indent int) (*YAMLNode, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+
+	n := &YAMLNode{Fields: map[string]*YAMLNode{}, Line: p.lines[p.pos].number}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && isSequenceItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, MalformedError(p.fileName, line.number, "unexpected indentation")
+		}
+		p.pos++
+
+		key, value, ok := SplitYAMLField(line.text)
+		if !ok {
+			return nil, MalformedError(p.fileName, line.number, fmt.Sprintf("unexpected %q", line.text))
+		}
+
+		child, err := p.parseFieldValue(line, indent, value)
+		if err != nil {
+			return nil, err
+		}
+		child.Line = line.number
+		if _, exists := n.Fields[key]; !exists {
+			n.Keys = append(n.Keys, key)
+		}
+		n.Fields[key] = child
+	}
+	return n, nil
+}
+
+// parseFieldValue parses the value of a field: the one on its line or the block of the lines nested
+// below it, a sequence may be indented as its key
+func (p *yamlParser) parseFieldValue(line yamlLine, indent int, value string) (*YAMLNode, error) {
+	if value != "" {
+		child, ok := parseYAMLValue(value)
+		if !ok {
+			return nil, MalformedError(p.fileName, line.number, fmt.Sprintf("unexpected value %q", value))
+		}
+		return child, nil
+	}
+
+	if p.pos < len(p.lines) && (p.lines[p.pos].indent > indent ||
+		(p.lines[p.pos].indent == indent && isSequenceItem(p.lines[p.pos].text))) {
+		return p.parseBlock(p.lines[p.pos].indent)
+	}
+	return &YAMLNode{Line: line.number}, nil
+}
+
+// parseSequence parses the `- item` lines at indent. An item holding a `key: value` is a mapping whose
+// other fields are the lines indented as that key
+func (p *yamlParser) parseSequence(indent int) (*YAMLNode, error) {
+	n := &YAMLNode{Line: p.lines[p.pos].number}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSequenceItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		content := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+
+		switch {
+		case content == "":
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				item, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				n.Items = append(n.Items, item)
+			} else {
+				n.Items = append(n.Items, &YAMLNode{Line: line.number})
+			}
+		case isMappingEntry(content):
+			// the item is parsed as a mapping starting at the column of its first key
+			p.lines[p.pos] = yamlLine{number: line.number, indent: line.indent + len(line.text) - len(content), text: content}
+			item, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			n.Items = append(n.Items, item)
+		default:
+			p.pos++
+			item, ok := parseYAMLValue(content)
+			if !ok {
+				return nil, MalformedError(p.fileName, line.number, fmt.Sprintf("unexpected value %q", content))
+			}
+			item.Line = line.number
+			n.Items = append(n.Items, item)
+		}
+	}
+	return n, nil
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isMappingEntry tells whether the content of a sequence item is a `key: value` entry rather than a scalar
+func isMappingEntry(content string) bool {
+	if strings.HasPrefix(content, "{") || strings.HasPrefix(content, "[") {
+		return false
+	}
+	if (strings.HasPrefix(content, "'") || strings.HasPrefix(content, "\"")) && closingQuote(content) == len(content)-1 {
+		return false
+	}
+	_, _, ok := SplitYAMLField(content)
+	return ok
+}
+
+// parseYAMLValue parses a value on the line of its key: a flow mapping, a flow sequence or a scalar
+func parseYAMLValue(value string) (*YAMLNode, bool) {
+	switch {
+	case strings.HasPrefix(value, "{"):
+		if !strings.HasSuffix(value, "}") {
+			return nil, false
+		}
+		n := &YAMLNode{Fields: map[string]*YAMLNode{}}
+		for _, entry := range splitFlow(value[1 : len(value)-1]) {
+			key, field, ok := SplitYAMLField(entry)
+			if !ok {
+				return nil, false
+			}
+			child, ok := parseYAMLValue(field)
+			if !ok {
+				return nil, false
+			}
+			n.Keys = append(n.Keys, key)
+			n.Fields[key] = child
+		}
+		return n, true
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return nil, false
+		}
+		n := &YAMLNode{}
+		for _, item := range splitFlow(value[1 : len(value)-1]) {
+			child, ok := parseYAMLValue(item)
+			if !ok {
+				return nil, false
+			}
+			n.Items = append(n.Items, child)
+		}
+		return n, true
+	}
+	return &YAMLNode{Value: UnquoteYAML(value)}, true
+}
+
+// splitFlow splits the entries of a flow collection on the commas outside of quotes and nested collections
+func splitFlow(text string) []string {
+	var entries []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			entries = append(entries, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" {
+		entries = append(entries, last)
+	}
+	return entries
+}
+
+// SplitYAMLField splits a `key: value` line, the key may be quoted. A key ends at the first `: `
+// or at the final colon, e.g. /@babel/core/7.14.0:
+func SplitYAMLField(text string) (string, string, bool) {
+	if strings.HasPrefix(text, "'") || strings.HasPrefix(text, "\"") {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		rest := text[end+1:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return UnquoteYAML(text[:end+1]), strings.TrimSpace(rest[1:]), true
+	}
+
+	if i := strings.Index(text, ": "); i > 0 {
+		return text[:i], strings.TrimSpace(text[i+2:]), true
+	}
+	if strings.HasSuffix(text, ":") && len(text) > 1 {
+		return text[:len(text)-1], "", true
+	}
+	return "", "", false
+}
+
+// StripYAMLComment drops the comment of a line, a # starting the line or following a blank outside of quotes
+func StripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// closingQuote returns the index of the quote closing the string text starts with, -1 if none.
+// Single quotes are escaped by doubling them, double quotes by a backslash
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// UnquoteYAML returns the value of a plain, single quoted or double quoted scalar
+func UnquoteYAML(value string) string {
+	switch {
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	return value
+}
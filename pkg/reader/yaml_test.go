@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYAML(t *testing.T) {
+	document, err := ParseYAML("test.yml", strings.NewReader(`# comment
+name: test  # trailing comment
+channels:
+  - conda-forge
+  - 'defaults'
+dependencies:
+  - python=3.11
+  - pip:
+    - requests==2.31.0
+package:
+- name: numpy
+  dependencies:
+    python: '>=3.11'
+  hash: {md5: abc, sha256: def}
+- name: "pip #1"
+  platforms: [linux-64, osx-arm64]
+-
+  name: nested
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "test", document.Str("name"))
+	assert.Equal(t, []string{"conda-forge", "defaults"}, document.Strings("channels"))
+
+	dependencies := document.List("dependencies")
+	assert.Len(t, dependencies, 2)
+	assert.Equal(t, "python=3.11", dependencies[0].Value)
+	assert.Equal(t, []string{"requests==2.31.0"}, dependencies[1].Strings("pip"))
+
+	packages := document.List("package")
+	assert.Len(t, packages, 3)
+	assert.Equal(t, "numpy", packages[0].Str("name"))
+	assert.Equal(t, 11, packages[0].Line)
+	assert.Equal(t, []string{"python"}, packages[0].Get("dependencies").Keys)
+	assert.Equal(t, 12, packages[0].Get("dependencies").Line)
+	assert.Equal(t, "def", packages[0].Get("hash").Str("sha256"))
+	assert.Equal(t, "pip #1", packages[1].Str("name"))
+	assert.Equal(t, []string{"linux-64", "osx-arm64"}, packages[1].Strings("platforms"))
+	assert.Equal(t, "nested", packages[2].Str("name"))
+}
+
+func TestParseYAMLKeys(t *testing.T) {
+	document, err := ParseYAML("pnpm-lock.yaml", strings.NewReader(`lockfileVersion: '6.0'
+packages:
+
+  /@babel/core@7.14.0:
+    resolution: {integrity: sha512-abc==, tarball: 'https://example.com/core.tgz'}
+    engines: {}
+  "it's":
+    name: 'it''s'
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "6.0", document.Str("lockfileVersion"))
+
+	packages := document.Get("packages")
+	assert.Equal(t, []string{"/@babel/core@7.14.0", "it's"}, packages.Keys)
+
+	core := packages.Get("/@babel/core@7.14.0")
+	assert.Equal(t, "sha512-abc==", core.Get("resolution").Str("integrity"))
+	assert.Equal(t, "https://example.com/core.tgz", core.Get("resolution").Str("tarball"))
+	assert.Empty(t, core.Get("engines").Keys)
+	assert.Equal(t, 4, core.Line)
+
+	assert.Equal(t, "it's", packages.Get("it's").Str("name"))
+	assert.Nil(t, document.Get("missing").Get("field"))
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"indented first line", "  lockfileVersion: 5.4\n", "line 1: unexpected indentation"},
+		{"deeper indentation", "packages:\n  /ms/2.1.3:\n      dev: false\n    dev: true\n", "line 4: unexpected indentation"},
+		{"not a field", "packages:\n  /ms/2.1.3\n", "line 2: unexpected \"/ms/2.1.3\""},
+		{"indented sequence item field", "package:\n- name: a\n   version: 1\n", "line 3: unexpected indentation"},
+		{"unterminated flow mapping", "resolution: {integrity: sha512-abc==\n", "line 1: unexpected value \"{integrity: sha512-abc==\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseYAML("test.yml", strings.NewReader(test.content))
+			assert.True(t, errors.Is(err, ErrMalformedFile))
+			assert.EqualError(t, err, "malformed file test.yml: "+test.expected)
+		})
+	}
+}
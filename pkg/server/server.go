@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	contentTypeJSON     = "application/spdx+json"
+	contentTypeTagValue = "text/spdx; charset=utf-8"
+)
+
+var errNoDocuments = errors.New("no document to serve")
+
+// Server serves generated SPDX documents, the format is negotiated with the Accept header
+// or forced with the `format` query parameter (json or spdx)
+type Server struct {
+	documents        map[string]models.Document
+	slugs            []string
+	jsonRenderer     format.SPDXRenderer
+	tagValueRenderer format.SPDXRenderer
+}
+
+// New creates a server for the documents keyed by plugin slug, rendered in JSON or in tag-value
+// with the renderers of the generation settings
+func New(documents map[string]models.Document, jsonRenderer, tagValueRenderer format.SPDXRenderer) (*Server, error) {
+	if len(documents) == 0 {
+		return nil, errNoDocuments
+	}
+
+	slugs := make([]string, 0, len(documents))
+	for slug := range documents {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	return &Server{
+		documents:        documents,
+		slugs:            slugs,
+		jsonRenderer:     jsonRenderer,
+		tagValueRenderer: tagValueRenderer,
+	}, nil
+}
+
+// ServeHTTP serves the first document at / and every document at /<slug>
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	slug := strings.Trim(r.URL.Path, "/")
+	if slug == "" {
+		slug = s.slugs[0]
+	}
+
+	document, ok := s.documents[slug]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	if wantsJSON(r) {
+		contentType = contentTypeJSON
+		body, err = s.jsonRenderer.RenderDocument(document)
+	} else {
+		contentType = contentTypeTagValue
+		body, err = s.tagValueRenderer.RenderDocument(document)
+	}
+	if err != nil {
+		log.Errorf("failed to render document %s: %v", slug, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(body)
+	}
+}
+
+// ParseDocument decodes a document rendered in JSON format
+func ParseDocument(data []byte) (models.Document, error) {
+	var document models.Document
+	err := json.Unmarshal(data, &document)
+	return document, err
+}
+
+func wantsJSON(r *http.Request) bool {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "json":
+		return true
+	case "spdx":
+		return false
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "json")
+}
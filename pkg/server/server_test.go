@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func newServer(t *testing.T, compactJSON bool, lineEnding format.LineEnding) *httptest.Server {
+	s, err := New(map[string]models.Document{"stub": testDocument()},
+		format.NewRenderer(models.OutputFormatJson, compactJSON, lineEnding),
+		format.NewRenderer(models.OutputFormatSpdx, compactJSON, lineEnding))
+	assert.NoError(t, err)
+
+	return httptest.NewServer(s)
+}
+
+func testDocument() models.Document {
+	return models.Document{
+		SPDXVersion:  "SPDX-2.2",
+		DataLicense:  "CC0-1.0",
+		SPDXID:       "SPDXRef-DOCUMENT",
+		DocumentName: "root-1.0.0",
+		Packages: []models.Package{{
+			PackageName:    "root",
+			SPDXID:         "SPDXRef-Package-root",
+			PackageVersion: "1.0.0",
+		}},
+	}
+}
+
+func get(t *testing.T, url, accept string) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	return res, string(body)
+}
+
+func TestServe(t *testing.T) {
+	ts := newServer(t, false, format.LineEndingLF)
+	defer ts.Close()
+
+	res, body := get(t, ts.URL, "application/spdx+json")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/spdx+json", res.Header.Get("Content-Type"))
+	document, err := ParseDocument([]byte(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "root-1.0.0", document.DocumentName)
+
+	res, body = get(t, ts.URL+"/stub", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/spdx; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.Contains(t, body, "DocumentName: root-1.0.0")
+	assert.Contains(t, body, "PackageName: root")
+
+	res, _ = get(t, ts.URL+"/stub?format=json", "text/spdx")
+	assert.Equal(t, "application/spdx+json", res.Header.Get("Content-Type"))
+
+	res, _ = get(t, ts.URL+"/unknown", "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestServeWithSettings(t *testing.T) {
+	ts := newServer(t, true, format.LineEndingCRLF)
+	defer ts.Close()
+
+	_, body := get(t, ts.URL+"/stub?format=json", "")
+	assert.NotContains(t, body, "\n")
+	document, err := ParseDocument([]byte(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "root-1.0.0", document.DocumentName)
+
+	_, body = get(t, ts.URL+"/stub", "")
+	assert.Contains(t, body, "DocumentName: root-1.0.0\r\n")
+	assert.NotContains(t, strings.ReplaceAll(body, "\r\n", ""), "\n")
+}
+
+func TestNewWithoutDocuments(t *testing.T) {
+	_, err := New(map[string]models.Document{}, format.JsonSPDXRenderer{}, format.TagValueSPDXRenderer{})
+	assert.Equal(t, errNoDocuments, err)
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package versionlock
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var errInvalidLockLine = errors.New("invalid version lock line")
+
+// Versions holds the pinned versions keyed by groupId:artifactId
+type Versions map[string]string
+
+// Lookup returns the version pinned for the artifact
+func (v Versions) Lookup(groupID, artifactID string) (string, bool) {
+	version, ok := v[groupID+":"+artifactID]
+	return version, ok
+}
+
+// Load reads a version lock file, the format is detected line by line:
+//   - Gradle dependency lock file: group:artifact:version=configurations
+//   - refreshVersions versions.properties: version.group..artifact=version
+//   - plain properties: group:artifact=version
+//
+// Comments, blank lines and the other properties are skipped
+func Load(path string) (Versions, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	versions := Versions{}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		key, version, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+		if key == "" || version == "" {
+			return nil, fmt.Errorf("%w %d: %s", errInvalidLockLine, number, line)
+		}
+		versions[key] = version
+	}
+
+	return versions, scanner.Err()
+}
+
+// parseLine returns the groupId:artifactId and version of a line, ok is false for lines
+// which do not pin an artifact version
+func parseLine(line string) (string, string, bool) {
+	idx := strings.Index(line, "=")
+	if idx < 0 {
+		return "", "", false
+	}
+	key, value := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+
+	if strings.HasPrefix(key, "version.") {
+		parts := strings.SplitN(strings.TrimPrefix(key, "version."), "..", 2)
+		if len(parts) != 2 {
+			// a version alias, e.g. version.kotlin
+			return "", "", false
+		}
+		return artifactKey(parts[0], parts[1]), value, true
+	}
+
+	parts := strings.Split(key, ":")
+	switch len(parts) {
+	case 2:
+		return artifactKey(parts[0], parts[1]), value, true
+	case 3:
+		// the gradle lock file lists the configurations after the coordinate
+		return artifactKey(parts[0], parts[1]), parts[2], true
+	}
+
+	return "", "", false
+}
+
+// artifactKey returns groupId:artifactId, empty when either is missing
+func artifactKey(groupID, artifactID string) string {
+	if groupID == "" || artifactID == "" {
+		return ""
+	}
+	return groupID + ":" + artifactID
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package versionlock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadGradleLockfile(t *testing.T) {
+	versions, err := Load(filepath.Join("testdata", "gradle.lockfile"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Versions{
+		"com.google.guava:guava": "30.1-jre",
+		"org.slf4j:slf4j-api":    "1.7.30",
+	}, versions)
+}
+
+func TestLoadVersionsProperties(t *testing.T) {
+	versions, err := Load(filepath.Join("testdata", "versions.properties"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Versions{
+		"com.google.guava:guava": "31.0.1-jre",
+		"junit:junit":            "4.13.2",
+		"org.yaml:snakeyaml":     "1.29",
+	}, versions)
+
+	version, ok := versions.Lookup("org.yaml", "snakeyaml")
+	assert.True(t, ok)
+	assert.Equal(t, "1.29", version)
+}
+
+func TestLoadInvalidLine(t *testing.T) {
+	_, err := Load(filepath.Join("testdata", "invalid.lockfile"))
+	assert.True(t, errors.Is(err, errInvalidLockLine))
+}